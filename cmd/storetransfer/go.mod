@@ -0,0 +1,38 @@
+module github.com/polarismesh/polaris-plugin-api/cmd/storetransfer
+
+go 1.21
+
+require (
+	github.com/polarismesh/polaris-plugin-api v0.0.0-20261017200005-299e819a1df7
+	github.com/polarismesh/polaris-plugin-api/store/sqlite v0.0.0-20261017200055-2d02aefa96c2
+)
+
+require (
+	github.com/dustin/go-humanize v1.0.1 // indirect
+	github.com/golang/protobuf v1.5.2 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/hashicorp/golang-lru/v2 v2.0.7 // indirect
+	github.com/mattn/go-isatty v0.0.20 // indirect
+	github.com/ncruces/go-strftime v0.1.9 // indirect
+	github.com/polarismesh/specification v1.4.2 // indirect
+	github.com/remyoudompheng/bigfft v0.0.0-20230129092748-24d4a6f8daec // indirect
+	golang.org/x/net v0.2.0 // indirect
+	golang.org/x/sys v0.22.0 // indirect
+	golang.org/x/text v0.4.0 // indirect
+	google.golang.org/genproto v0.0.0-20200526211855-cb27e3aa2013 // indirect
+	google.golang.org/grpc v1.51.0 // indirect
+	google.golang.org/protobuf v1.28.1 // indirect
+	modernc.org/gc/v3 v3.0.0-20240107210532-573471604cb6 // indirect
+	modernc.org/libc v1.55.3 // indirect
+	modernc.org/mathutil v1.6.0 // indirect
+	modernc.org/memory v1.8.0 // indirect
+	modernc.org/sqlite v1.33.1 // indirect
+	modernc.org/strutil v1.2.0 // indirect
+	modernc.org/token v1.1.0 // indirect
+)
+
+// 在仓库内开发时使用本地的模块，go install 时 replace 不生效，使用上面 require 的版本
+replace (
+	github.com/polarismesh/polaris-plugin-api => ../..
+	github.com/polarismesh/polaris-plugin-api/store/sqlite => ../../store/sqlite
+)
@@ -0,0 +1,199 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// storetransfer 在已注册的存储插件之间迁移全部资源，或者将存储备份为与实现无关的快照
+//
+//	storetransfer -from sqlite -from-option path=/data/polaris.db -to mysql -to-option ... -checkpoint /data/transfer.json
+//	storetransfer -from sqlite -from-option path=/data/polaris.db -backup /data/polaris.snapshot
+//	storetransfer -to sqlite -to-option path=/data/restored.db -restore /data/polaris.snapshot
+//
+// 迁移中断后使用相同的参数重新执行即可继续，-verify-only 只校验不写入。
+// 需要迁移到其他存储插件时，在 import 中加入对应的插件后重新编译。
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	_ "github.com/polarismesh/polaris-plugin-api/store/memory"
+	_ "github.com/polarismesh/polaris-plugin-api/store/sqlite"
+	"github.com/polarismesh/polaris-plugin-api/store/transfer"
+)
+
+// options 可重复的 key=value 形式的存储配置
+type options map[string]interface{}
+
+func (o options) String() string {
+	return fmt.Sprint(map[string]interface{}(o))
+}
+
+func (o options) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("invalid option %q, expect key=value", value)
+	}
+	o[key] = val
+	return nil
+}
+
+type config struct {
+	from, to       string
+	fromOpts       options
+	toOpts         options
+	checkpointPath string
+	backupPath     string
+	restorePath    string
+	batchSize      uint
+	kinds          string
+	verifyOnly     bool
+}
+
+func main() {
+	c := &config{fromOpts: options{}, toOpts: options{}}
+	flag.StringVar(&c.from, "from", "", "source store plugin name")
+	flag.Var(c.fromOpts, "from-option", "source store option key=value, can be repeated")
+	flag.StringVar(&c.to, "to", "", "target store plugin name")
+	flag.Var(c.toOpts, "to-option", "target store option key=value, can be repeated")
+	flag.StringVar(&c.checkpointPath, "checkpoint", "", "checkpoint file used to resume an interrupted transfer")
+	flag.StringVar(&c.backupPath, "backup", "", "write a snapshot of the source store to this file")
+	flag.StringVar(&c.restorePath, "restore", "", "restore the snapshot in this file into the target store")
+	flag.UintVar(&c.batchSize, "batch-size", 100, "number of resources written per batch")
+	flag.StringVar(&c.kinds, "kinds", "", "comma separated resource kinds, default all")
+	flag.BoolVar(&c.verifyOnly, "verify-only", false, "only verify checksums without writing")
+	flag.Parse()
+
+	if err := run(c); err != nil {
+		fmt.Fprintln(os.Stderr, "storetransfer:", err)
+		os.Exit(1)
+	}
+}
+
+func run(c *config) error {
+	opts := []transfer.Option{
+		transfer.WithBatchSize(uint32(c.batchSize)),
+		transfer.WithProgress(printReport),
+	}
+	if c.kinds != "" {
+		selected := make([]transfer.Kind, 0)
+		for _, kind := range strings.Split(c.kinds, ",") {
+			selected = append(selected, transfer.Kind(strings.TrimSpace(kind)))
+		}
+		opts = append(opts, transfer.WithKinds(selected...))
+	}
+	if c.checkpointPath != "" && !c.verifyOnly {
+		checkpoint, err := transfer.NewFileCheckpoint(c.checkpointPath)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, transfer.WithCheckpoint(checkpoint))
+	}
+
+	switch {
+	case c.backupPath != "":
+		return backup(c, opts)
+	case c.restorePath != "":
+		return restore(c, opts)
+	}
+	if c.from == "" || c.to == "" {
+		return fmt.Errorf("both -from and -to are required")
+	}
+	if c.from == c.to {
+		// 插件以单例的形式注册，同名插件无法同时打开两个实例
+		return fmt.Errorf("source and target must be different store plugins")
+	}
+	src, err := open(c.from, c.fromOpts)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = src.Destroy()
+	}()
+	dst, err := open(c.to, c.toOpts)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = dst.Destroy()
+	}()
+	if c.verifyOnly {
+		_, err = transfer.Verify(src, dst, opts...)
+		return err
+	}
+	_, err = transfer.Copy(src, dst, opts...)
+	return err
+}
+
+func backup(c *config, opts []transfer.Option) error {
+	src, err := open(c.from, c.fromOpts)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = src.Destroy()
+	}()
+	file, err := os.Create(c.backupPath)
+	if err != nil {
+		return err
+	}
+	if _, err := transfer.Backup(src, file, opts...); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func restore(c *config, opts []transfer.Option) error {
+	dst, err := open(c.to, c.toOpts)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = dst.Destroy()
+	}()
+	file, err := os.Open(c.restorePath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	_, err = transfer.Restore(dst, file, opts...)
+	return err
+}
+
+func open(name string, opts options) (store.Store, error) {
+	s, ok := store.Get(name)
+	if !ok {
+		return nil, fmt.Errorf("store plugin %q is not registered", name)
+	}
+	if err := s.Initialize(&store.Config{Name: name, Option: opts}); err != nil {
+		return nil, fmt.Errorf("initialize store %s: %w", name, err)
+	}
+	return s, nil
+}
+
+func printReport(report transfer.KindReport) {
+	if report.Resumed {
+		fmt.Printf("%-28s source=%-6d resumed checksum=%s\n", report.Kind, report.Source, report.Checksum)
+		return
+	}
+	fmt.Printf("%-28s source=%-6d copied=%-6d existing=%-6d extra=%-6d mismatched=%-6d checksum=%s\n", report.Kind,
+		report.Source, report.Copied, report.Existing, report.Extra, len(report.Mismatched), report.Checksum)
+}
@@ -0,0 +1,70 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package election
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+var (
+	slots = make(map[string]Elector)
+)
+
+// Register 注册插件
+func Register(name string, plugin Elector) {
+	if _, exist := slots[name]; exist {
+		panic(fmt.Sprintf("existed plugin: name=%v", name))
+	}
+	slots[name] = plugin
+}
+
+func Get(name string) (Elector, bool) {
+	server, exist := slots[name]
+	return server, exist
+}
+
+// ConfigEntry 单个插件配置
+type ConfigEntry struct {
+	Name   string                 `yaml:"name"`
+	Option map[string]interface{} `yaml:"option"`
+}
+
+// Elector leader 选举插件接口，与 store.AdminStore 中的选举方法含义一致，
+// ListLeaderElections 返回的记录同样按照 ElectKey 排序，Valid 表示当前是否存在 leader
+type Elector interface {
+	// Name .
+	Name() string
+	// Initialize .
+	Initialize(c *ConfigEntry) error
+	// Destroy 放弃所有的 leader 身份
+	Destroy() error
+	// StartLeaderElection 参与 key 的选举，选举可能是异步进行的
+	StartLeaderElection(key string) error
+	// IsLeader 当前节点是否为 key 的 leader
+	IsLeader(key string) bool
+	// ListLeaderElections 列出所有的选举记录
+	ListLeaderElections() ([]*model.LeaderElection, error)
+	// ReleaseLeaderElection 放弃 key 的 leader 身份并退出选举
+	ReleaseLeaderElection(key string) error
+	// WatchLeaderElection 监听 key 的 leader 变化，语义与 store.LeaderWatchStore 的同名方法一致
+	WatchLeaderElection(ctx context.Context, key string) (store.LeaderWatcher, error)
+}
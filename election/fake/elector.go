@@ -0,0 +1,214 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package fake
+
+import (
+	"context"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/election"
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+const (
+	// PluginName 进程内选举插件的名字
+	PluginName = "fake"
+
+	// pollInterval 状态都在内存中，使用较短的轮询间隔让 leader 变化尽快送达
+	pollInterval = 10 * time.Millisecond
+)
+
+func init() {
+	election.Register(PluginName, New())
+}
+
+var _ election.Elector = (*Elector)(nil)
+
+// Elector 进程内的选举实现，用于测试：参与选举的节点在没有 leader 时立即成为 leader，
+// 可以通过 SetLeader 模拟其他节点成为 leader 或者 leader 下线
+type Elector struct {
+	host string
+	now  func() time.Time
+
+	lock    sync.Mutex
+	records map[string]*model.LeaderElection
+	// campaigns 当前节点参与的选举
+	campaigns map[string]struct{}
+}
+
+// Option 进程内选举的可选配置
+type Option func(e *Elector)
+
+// WithHost 设置当前节点的地址，默认为本机的 hostname
+func WithHost(host string) Option {
+	return func(e *Elector) {
+		e.host = host
+	}
+}
+
+// WithClock 设置获取当前时间的方法，便于测试中控制选举记录的时间
+func WithClock(now func() time.Time) Option {
+	return func(e *Elector) {
+		e.now = now
+	}
+}
+
+// New 创建进程内的选举
+func New(options ...Option) *Elector {
+	e := &Elector{
+		host:      localHost(),
+		now:       time.Now,
+		records:   make(map[string]*model.LeaderElection),
+		campaigns: make(map[string]struct{}),
+	}
+	for i := range options {
+		options[i](e)
+	}
+	return e
+}
+
+// Name .
+func (e *Elector) Name() string {
+	return PluginName
+}
+
+// Initialize .
+func (e *Elector) Initialize(c *election.ConfigEntry) error {
+	return nil
+}
+
+// Destroy 放弃所有的 leader 身份
+func (e *Elector) Destroy() error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	for key := range e.campaigns {
+		e.resign(key)
+	}
+	e.campaigns = make(map[string]struct{})
+	return nil
+}
+
+// StartLeaderElection 参与选举，没有 leader 时当前节点立即成为 leader
+func (e *Elector) StartLeaderElection(key string) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.campaigns[key] = struct{}{}
+	e.elect(key)
+	return nil
+}
+
+// IsLeader .
+func (e *Elector) IsLeader(key string) bool {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	saved, ok := e.records[key]
+	return ok && saved.Valid && saved.Host == e.host
+}
+
+// ListLeaderElections .
+func (e *Elector) ListLeaderElections() ([]*model.LeaderElection, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	ret := make([]*model.LeaderElection, 0, len(e.records))
+	for _, item := range e.records {
+		copied := *item
+		ret = append(ret, &copied)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].ElectKey < ret[j].ElectKey
+	})
+	return ret, nil
+}
+
+// ReleaseLeaderElection 放弃 leader 身份并退出选举
+func (e *Elector) ReleaseLeaderElection(key string) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	delete(e.campaigns, key)
+	e.resign(key)
+	return nil
+}
+
+// WatchLeaderElection .
+func (e *Elector) WatchLeaderElection(ctx context.Context, key string) (store.LeaderWatcher, error) {
+	return store.PollLeaderElection(ctx, e, key, store.WithLeaderPollInterval(pollInterval))
+}
+
+// SetLeader 模拟 host 成为 key 的 leader，host 为空时模拟 leader 下线，此时参与选举的当前节点会接任 leader
+func (e *Elector) SetLeader(key string, host string) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if host == "" {
+		e.update(key, "", false)
+		e.elect(key)
+		return
+	}
+	e.update(key, host, true)
+}
+
+// elect 没有 leader 并且当前节点参与选举时成为 leader，调用方需要持有锁
+func (e *Elector) elect(key string) {
+	if _, ok := e.campaigns[key]; !ok {
+		return
+	}
+	if saved, ok := e.records[key]; ok && saved.Valid {
+		return
+	}
+	e.update(key, e.host, true)
+}
+
+// resign 当前节点是 leader 时放弃 leader 身份，调用方需要持有锁
+func (e *Elector) resign(key string) {
+	if saved, ok := e.records[key]; ok && saved.Valid && saved.Host == e.host {
+		e.update(key, saved.Host, false)
+	}
+}
+
+// update 更新选举记录，调用方需要持有锁
+func (e *Elector) update(key string, host string, valid bool) {
+	now := e.now()
+	saved, ok := e.records[key]
+	if !ok {
+		saved = &model.LeaderElection{ElectKey: key, Ctime: now.Unix(), CreateTime: now}
+		e.records[key] = saved
+	}
+	if host != "" {
+		saved.Host = host
+	}
+	saved.Valid = valid
+	saved.Mtime = now.Unix()
+	saved.ModifyTime = now
+}
+
+func localHost() string {
+	host, err := os.Hostname()
+	if err != nil {
+		return "127.0.0.1"
+	}
+	return host
+}
@@ -0,0 +1,59 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package fake_test
+
+import (
+	"testing"
+
+	"github.com/polarismesh/polaris-plugin-api/election"
+	"github.com/polarismesh/polaris-plugin-api/election/fake"
+)
+
+func TestRegistered(t *testing.T) {
+	if _, ok := election.Get(fake.PluginName); !ok {
+		t.Fatalf("plugin %s not registered", fake.PluginName)
+	}
+}
+
+func TestSetLeader(t *testing.T) {
+	e := fake.New(fake.WithHost("me"))
+	e.SetLeader("x", "other")
+	if err := e.StartLeaderElection("x"); err != nil {
+		t.Fatal(err)
+	}
+	if e.IsLeader("x") {
+		t.Fatal("another host is the leader")
+	}
+	e.SetLeader("x", "")
+	if !e.IsLeader("x") {
+		t.Fatal("campaigning elector should take over a free key")
+	}
+	list, err := e.ListLeaderElections()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].Host != "me" || !list[0].Valid {
+		t.Fatalf("elections: %+v", list)
+	}
+	if err := e.Destroy(); err != nil {
+		t.Fatal(err)
+	}
+	if e.IsLeader("x") {
+		t.Fatal("leadership kept after destroy")
+	}
+}
@@ -0,0 +1,369 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package filelock
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/election"
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+const (
+	// PluginName 基于文件锁的选举插件的名字
+	PluginName = "filelock"
+	// OptionDir 配置项：锁文件所在的目录，同一台机器上的节点需要使用相同的目录
+	OptionDir = "dir"
+	// OptionHost 配置项：当前节点的地址，写入锁文件供其他节点查询，默认为本机的 hostname
+	OptionHost = "host"
+	// OptionRetryInterval 配置项：没有成为 leader 时重新尝试加锁的间隔，例如 "1s"
+	OptionRetryInterval = "retryInterval"
+
+	defaultRetryInterval = time.Second
+	lockSuffix           = ".lock"
+)
+
+func init() {
+	election.Register(PluginName, New())
+}
+
+// Option 文件锁选举的可选配置
+type Option func(e *elector)
+
+// WithDir 设置锁文件所在的目录，Initialize 时配置中的 dir 优先
+func WithDir(dir string) Option {
+	return func(e *elector) {
+		e.dir = dir
+	}
+}
+
+// WithHost 设置当前节点的地址，Initialize 时配置中的 host 优先
+func WithHost(host string) Option {
+	return func(e *elector) {
+		e.host = host
+	}
+}
+
+// WithRetryInterval 设置没有成为 leader 时重新尝试加锁的间隔
+func WithRetryInterval(interval time.Duration) Option {
+	return func(e *elector) {
+		if interval > 0 {
+			e.interval = interval
+		}
+	}
+}
+
+// New 基于文件锁的选举，适用于单机部署：每个选举 key 对应目录下的一个锁文件，持有文件排他锁的进程即为 leader，
+// 进程退出时操作系统会自动释放文件锁；锁文件中记录 leader 的地址，文件的修改时间作为选举记录的修改时间
+func New(options ...Option) election.Elector {
+	e := &elector{
+		dir:       filepath.Join(os.TempDir(), "polaris-election"),
+		host:      localHost(),
+		interval:  defaultRetryInterval,
+		campaigns: make(map[string]*campaign),
+	}
+	for i := range options {
+		options[i](e)
+	}
+	return e
+}
+
+type elector struct {
+	dir      string
+	host     string
+	interval time.Duration
+
+	lock      sync.Mutex
+	campaigns map[string]*campaign
+}
+
+// campaign 参与中的选举，file 不为空时表示已经成为 leader
+type campaign struct {
+	file *os.File
+	stop chan struct{}
+	done chan struct{}
+}
+
+// Name .
+func (e *elector) Name() string {
+	return PluginName
+}
+
+// Initialize 创建锁文件所在的目录
+func (e *elector) Initialize(c *election.ConfigEntry) error {
+	if c != nil {
+		if dir, ok := c.Option[OptionDir].(string); ok && dir != "" {
+			e.dir = dir
+		}
+		if host, ok := c.Option[OptionHost].(string); ok && host != "" {
+			e.host = host
+		}
+		if value, ok := c.Option[OptionRetryInterval].(string); ok && value != "" {
+			interval, err := time.ParseDuration(value)
+			if err != nil || interval <= 0 {
+				return fmt.Errorf("invalid filelock retryInterval: %s", value)
+			}
+			e.interval = interval
+		}
+	}
+	return os.MkdirAll(e.dir, 0o755)
+}
+
+// Destroy 退出所有的选举并释放文件锁
+func (e *elector) Destroy() error {
+	e.lock.Lock()
+	keys := make([]string, 0, len(e.campaigns))
+	for key := range e.campaigns {
+		keys = append(keys, key)
+	}
+	e.lock.Unlock()
+
+	var firstErr error
+	for _, key := range keys {
+		if err := e.ReleaseLeaderElection(key); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// StartLeaderElection 立即尝试获取文件锁，失败时在后台按照 retryInterval 重试直至成功或者退出选举
+func (e *elector) StartLeaderElection(key string) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if _, ok := e.campaigns[key]; ok {
+		return nil
+	}
+	file, err := e.tryLock(key)
+	if err != nil {
+		return err
+	}
+	c := &campaign{file: file, stop: make(chan struct{}), done: make(chan struct{})}
+	e.campaigns[key] = c
+	if file != nil {
+		close(c.done)
+		return nil
+	}
+	go e.campaign(key, c)
+	return nil
+}
+
+// campaign 在后台重试获取文件锁
+func (e *elector) campaign(key string, c *campaign) {
+	defer close(c.done)
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+		}
+		file, err := e.tryLock(key)
+		if err != nil || file == nil {
+			continue
+		}
+
+		e.lock.Lock()
+		select {
+		case <-c.stop:
+			_ = unlockFile(file)
+			_ = file.Close()
+		default:
+			c.file = file
+		}
+		e.lock.Unlock()
+		return
+	}
+}
+
+// tryLock 尝试获取 key 对应的文件锁并写入当前节点的地址，锁被其他节点持有时返回 nil
+func (e *elector) tryLock(key string) (*os.File, error) {
+	file, err := os.OpenFile(e.path(key), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	locked, err := lockFile(file, true)
+	if err != nil || !locked {
+		_ = file.Close()
+		return nil, err
+	}
+	if err := writeHost(file, e.host); err != nil {
+		_ = unlockFile(file)
+		_ = file.Close()
+		return nil, err
+	}
+	return file, nil
+}
+
+// IsLeader 当前节点是否持有 key 对应的文件锁
+func (e *elector) IsLeader(key string) bool {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	c, ok := e.campaigns[key]
+	return ok && c.file != nil
+}
+
+// ListLeaderElections 列出目录下的所有锁文件，锁文件被任意节点持有时记录有效
+func (e *elector) ListLeaderElections() ([]*model.LeaderElection, error) {
+	entries, err := os.ReadDir(e.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*model.LeaderElection{}, nil
+		}
+		return nil, err
+	}
+	ret := make([]*model.LeaderElection, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, lockSuffix) {
+			continue
+		}
+		key, err := url.PathUnescape(strings.TrimSuffix(name, lockSuffix))
+		if err != nil {
+			continue
+		}
+		record, err := e.record(key)
+		if err != nil {
+			return nil, err
+		}
+		if record != nil {
+			ret = append(ret, record)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].ElectKey < ret[j].ElectKey
+	})
+	return ret, nil
+}
+
+// record 读取 key 对应的锁文件，文件已经被删除时返回 nil
+func (e *elector) record(key string) (*model.LeaderElection, error) {
+	file, err := os.Open(e.path(key))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	host, err := readHost(file)
+	if err != nil {
+		return nil, err
+	}
+	// 能够获取共享锁说明没有节点持有排他锁，即当前没有 leader
+	free, err := lockFile(file, false)
+	if err != nil {
+		return nil, err
+	}
+	if free {
+		_ = unlockFile(file)
+	}
+	mtime := info.ModTime()
+	return &model.LeaderElection{
+		ElectKey:   key,
+		Host:       host,
+		Ctime:      mtime.Unix(),
+		CreateTime: mtime,
+		Mtime:      mtime.Unix(),
+		ModifyTime: mtime,
+		Valid:      !free,
+	}, nil
+}
+
+// ReleaseLeaderElection 退出选举，当前节点是 leader 时释放文件锁
+func (e *elector) ReleaseLeaderElection(key string) error {
+	e.lock.Lock()
+	c, ok := e.campaigns[key]
+	if ok {
+		delete(e.campaigns, key)
+		close(c.stop)
+	}
+	e.lock.Unlock()
+	if !ok {
+		return nil
+	}
+
+	<-c.done
+	if c.file == nil {
+		return nil
+	}
+	now := time.Now()
+	// 更新修改时间，使得选举记录反映 leader 释放的时间
+	_ = os.Chtimes(e.path(key), now, now)
+	err := unlockFile(c.file)
+	if closeErr := c.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// WatchLeaderElection 通过轮询监听 leader 变化，轮询间隔与 retryInterval 相同
+func (e *elector) WatchLeaderElection(ctx context.Context, key string) (store.LeaderWatcher, error) {
+	return store.PollLeaderElection(ctx, e, key, store.WithLeaderPollInterval(e.interval))
+}
+
+// path 选举 key 对应的锁文件，key 经过转义后作为文件名
+func (e *elector) path(key string) string {
+	return filepath.Join(e.dir, url.PathEscape(key)+lockSuffix)
+}
+
+func writeHost(file *os.File, host string) error {
+	if err := file.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := file.WriteAt([]byte(host), 0); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+func readHost(file *os.File) (string, error) {
+	data, err := os.ReadFile(file.Name())
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func localHost() string {
+	host, err := os.Hostname()
+	if err != nil {
+		return "127.0.0.1"
+	}
+	return host
+}
@@ -0,0 +1,100 @@
+//go:build unix
+
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package filelock_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/election"
+	"github.com/polarismesh/polaris-plugin-api/election/filelock"
+)
+
+func newElector(t *testing.T, dir, host string) election.Elector {
+	t.Helper()
+	e := filelock.New(filelock.WithDir(dir), filelock.WithHost(host), filelock.WithRetryInterval(20*time.Millisecond))
+	if err := e.Initialize(nil); err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func TestRegistered(t *testing.T) {
+	if _, ok := election.Get(filelock.PluginName); !ok {
+		t.Fatalf("plugin %s not registered", filelock.PluginName)
+	}
+}
+
+func TestElection(t *testing.T) {
+	dir := t.TempDir()
+	a := newElector(t, dir, "a")
+	b := newElector(t, dir, "b")
+	if err := a.StartLeaderElection("k/1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.StartLeaderElection("k/1"); err != nil {
+		t.Fatal(err)
+	}
+	if !a.IsLeader("k/1") || b.IsLeader("k/1") {
+		t.Fatal("a should be the leader")
+	}
+	list, err := b.ListLeaderElections()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].ElectKey != "k/1" || list[0].Host != "a" || !list[0].Valid {
+		t.Fatalf("elections: %+v", list)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	w, err := b.WatchLeaderElection(ctx, "k/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+	if ev := <-w.Events(); ev.Leader || ev.LeaderHost != "a" {
+		t.Fatalf("first event: %+v", ev)
+	}
+	if err := a.ReleaseLeaderElection("k/1"); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.After(2 * time.Second)
+	for taken := false; !taken; {
+		select {
+		case ev := <-w.Events():
+			taken = ev.Leader && ev.LeaderHost == "b"
+		case <-deadline:
+			t.Fatal("b never took over")
+		}
+	}
+
+	if err := b.Destroy(); err != nil {
+		t.Fatal(err)
+	}
+	list, err = a.ListLeaderElections()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].Valid {
+		t.Fatalf("lock not released: %+v", list)
+	}
+}
@@ -0,0 +1,37 @@
+//go:build !unix
+
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package filelock
+
+import (
+	"errors"
+	"os"
+)
+
+var errUnsupported = errors.New("filelock election is only supported on unix")
+
+// lockFile 当前平台不支持文件锁
+func lockFile(file *os.File, exclusive bool) (bool, error) {
+	return false, errUnsupported
+}
+
+// unlockFile 当前平台不支持文件锁
+func unlockFile(file *os.File) error {
+	return errUnsupported
+}
@@ -0,0 +1,128 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package storeelect
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/polarismesh/polaris-plugin-api/election"
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+const (
+	// PluginName 基于存储的选举插件的名字
+	PluginName = "store"
+	// OptionStore 配置项：使用的存储插件的名字，存储插件需要已经完成初始化
+	OptionStore = "store"
+)
+
+func init() {
+	election.Register(PluginName, New(nil))
+}
+
+// New 基于 s 的 AdminStore 选举接口实现 election.Elector，s 为空时在 Initialize 时根据配置项 store 获取已注册的存储插件；
+// 存储的生命周期由调用方管理，Destroy 不会销毁存储
+func New(s store.AdminStore) election.Elector {
+	return &elector{store: s, keys: make(map[string]struct{})}
+}
+
+type elector struct {
+	store store.AdminStore
+
+	lock sync.Mutex
+	// keys 参与过的选举，Destroy 时释放
+	keys map[string]struct{}
+}
+
+// Name .
+func (e *elector) Name() string {
+	return PluginName
+}
+
+// Initialize .
+func (e *elector) Initialize(c *election.ConfigEntry) error {
+	if c != nil {
+		if name, ok := c.Option[OptionStore].(string); ok && name != "" {
+			s, exist := store.Get(name)
+			if !exist {
+				return fmt.Errorf("store plugin %s not found", name)
+			}
+			e.store = s
+		}
+	}
+	if e.store == nil {
+		return fmt.Errorf("option %s is required by election plugin %s", OptionStore, PluginName)
+	}
+	return nil
+}
+
+// Destroy 释放所有参与过的选举
+func (e *elector) Destroy() error {
+	e.lock.Lock()
+	keys := e.keys
+	e.keys = make(map[string]struct{})
+	e.lock.Unlock()
+
+	var firstErr error
+	for key := range keys {
+		if !e.store.IsLeader(key) {
+			continue
+		}
+		if err := e.store.ReleaseLeaderElection(key); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// StartLeaderElection .
+func (e *elector) StartLeaderElection(key string) error {
+	if err := e.store.StartLeaderElection(key); err != nil {
+		return err
+	}
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.keys[key] = struct{}{}
+	return nil
+}
+
+// IsLeader .
+func (e *elector) IsLeader(key string) bool {
+	return e.store.IsLeader(key)
+}
+
+// ListLeaderElections .
+func (e *elector) ListLeaderElections() ([]*model.LeaderElection, error) {
+	return e.store.ListLeaderElections()
+}
+
+// ReleaseLeaderElection .
+func (e *elector) ReleaseLeaderElection(key string) error {
+	e.lock.Lock()
+	delete(e.keys, key)
+	e.lock.Unlock()
+	return e.store.ReleaseLeaderElection(key)
+}
+
+// WatchLeaderElection .
+func (e *elector) WatchLeaderElection(ctx context.Context, key string) (store.LeaderWatcher, error) {
+	return store.WatchLeaderElection(ctx, e.store, key)
+}
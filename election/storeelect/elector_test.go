@@ -0,0 +1,62 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package storeelect_test
+
+import (
+	"testing"
+
+	"github.com/polarismesh/polaris-plugin-api/election"
+	"github.com/polarismesh/polaris-plugin-api/election/storeelect"
+	"github.com/polarismesh/polaris-plugin-api/store/memory"
+)
+
+func TestRegistered(t *testing.T) {
+	if _, ok := election.Get(storeelect.PluginName); !ok {
+		t.Fatalf("plugin %s not registered", storeelect.PluginName)
+	}
+}
+
+func TestElection(t *testing.T) {
+	s := memory.New()
+	e := storeelect.New(s)
+	if err := e.Initialize(nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.StartLeaderElection("y"); err != nil {
+		t.Fatal(err)
+	}
+	if !s.IsLeader("y") || !e.IsLeader("y") {
+		t.Fatal("elector should delegate to the store")
+	}
+	if err := e.Destroy(); err != nil {
+		t.Fatal(err)
+	}
+	if s.IsLeader("y") {
+		t.Fatal("leadership kept after destroy")
+	}
+}
+
+func TestInitializeUnknownStore(t *testing.T) {
+	c := &election.ConfigEntry{Option: map[string]interface{}{storeelect.OptionStore: "nope"}}
+	if err := storeelect.New(nil).Initialize(c); err == nil {
+		t.Fatal("unknown store accepted")
+	}
+	if err := storeelect.New(nil).Initialize(nil); err == nil {
+		t.Fatal("missing store accepted")
+	}
+}
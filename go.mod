@@ -2,14 +2,16 @@ module github.com/polarismesh/polaris-plugin-api
 
 go 1.21
 
-require github.com/polarismesh/specification v1.4.2
+require (
+	github.com/golang/protobuf v1.5.2
+	github.com/polarismesh/specification v1.4.2
+	google.golang.org/protobuf v1.28.1
+)
 
 require (
-	github.com/golang/protobuf v1.5.2 // indirect
 	golang.org/x/net v0.2.0 // indirect
 	golang.org/x/sys v0.2.0 // indirect
 	golang.org/x/text v0.4.0 // indirect
 	google.golang.org/genproto v0.0.0-20200526211855-cb27e3aa2013 // indirect
 	google.golang.org/grpc v1.51.0 // indirect
-	google.golang.org/protobuf v1.28.1 // indirect
 )
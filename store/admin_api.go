@@ -18,6 +18,7 @@
 package store
 
 import (
+	"context"
 	"time"
 
 	"github.com/polarismesh/polaris-plugin-api/store/model"
@@ -46,9 +47,37 @@ type AdminStore interface {
 	BatchCleanDeletedClients(timeout time.Duration, batchSize uint32) (uint32, error)
 }
 
-// LeaderChangeEvent
+// LeaderWatchStore 可选接口，支持原生推送 leader 状态变化的存储插件实现该接口
+type LeaderWatchStore interface {
+	// WatchLeaderElection 监听 key 的 leader 变化，开始监听时先推送一次当前的状态，之后只在状态变化时推送，ctx 结束时停止
+	WatchLeaderElection(ctx context.Context, key string) (LeaderWatcher, error)
+}
+
+// WatchLeaderElection s 实现了 LeaderWatchStore 时调用其 WatchLeaderElection，否则通过 PollLeaderElection 轮询，
+// options 只在轮询时生效
+func WatchLeaderElection(ctx context.Context, s LeaderElectionReader, key string,
+	options ...LeaderPollingOption) (LeaderWatcher, error) {
+	if ws, ok := s.(LeaderWatchStore); ok {
+		return ws.WatchLeaderElection(ctx, key)
+	}
+	return PollLeaderElection(ctx, s, key, options...)
+}
+
+// LeaderChangeEvent leader 状态变化事件
 type LeaderChangeEvent struct {
-	Key        string
-	Leader     bool
+	Key string
+	// Leader 当前节点是否为 leader
+	Leader bool
+	// LeaderHost 当前 leader 的地址，没有 leader 时为空
 	LeaderHost string
 }
+
+// LeaderWatcher leader 状态的监听者
+type LeaderWatcher interface {
+	// Events leader 状态变化事件，LeaderWatcher 停止后会被关闭
+	Events() <-chan LeaderChangeEvent
+	// Err 返回 LeaderWatcher 停止的原因，调用 Stop 主动停止时返回 nil，存储持续查询失败时返回最后一次的错误
+	Err() error
+	// Stop 停止监听
+	Stop()
+}
@@ -0,0 +1,1944 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package store
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+const (
+	defaultCacheTTL      = 10 * time.Second
+	defaultCacheCapacity = 10000
+)
+
+// CacheStats 单个方法的缓存统计
+type CacheStats struct {
+	// Hits 命中缓存的次数
+	Hits uint64
+	// Misses 未命中缓存、需要查询存储的次数
+	Misses uint64
+	// Evictions 超出容量被淘汰的条目数
+	Evictions uint64
+	// Size 当前缓存的条目数
+	Size int
+}
+
+// CachingStore 带有读缓存的 Store
+type CachingStore interface {
+	Store
+	// CacheStats 返回每个被缓存方法的统计，key 为方法名
+	CacheStats() map[string]CacheStats
+}
+
+// CachingOption NewCachingStore 的可选配置
+type CachingOption func(c *cachingStore)
+
+// WithCacheTTL 设置缓存条目的有效期
+func WithCacheTTL(ttl time.Duration) CachingOption {
+	return func(c *cachingStore) {
+		if ttl > 0 {
+			c.ttl = ttl
+		}
+	}
+}
+
+// WithCacheCapacity 设置每个被缓存方法最多缓存的条目数，超出后淘汰最久未访问的条目
+func WithCacheCapacity(capacity int) CachingOption {
+	return func(c *cachingStore) {
+		if capacity > 0 {
+			c.capacity = capacity
+		}
+	}
+}
+
+// WithFeedInvalidation 通过该 Store 调用 GetMoreServices、GetMoreInstances、GetUsersForCache、
+// GetStrategyDetailsForCache 以及对应的 Range* 方法时，根据返回的变更数据失效对应的缓存，用于感知其他节点写入的数据
+func WithFeedInvalidation() CachingOption {
+	return func(c *cachingStore) {
+		c.feedInvalidation = true
+	}
+}
+
+// NewCachingStore 为 GetService、GetServiceByID、GetInstance、GetConfigFile、GetUser 以及 GetStrategyDetail
+// 增加读缓存，通过该 Store 执行的写操作会失效对应的缓存，其他节点写入的数据在 TTL 之后可见。
+// 使用该 Store 创建的事务中的写操作在事务结束之后失效缓存，使用其他 Tx 时在写操作返回之后立即失效。
+// 缓存只保存查询到的数据，返回的对象在多次调用之间共享，调用方不能修改
+func NewCachingStore(s Store, options ...CachingOption) CachingStore {
+	c := &cachingStore{
+		store:    s,
+		ttl:      defaultCacheTTL,
+		capacity: defaultCacheCapacity,
+	}
+	for i := range options {
+		options[i](c)
+	}
+	c.services = newLRUCache[*model.Service](c.capacity)
+	c.servicesByID = newLRUCache[*model.Service](c.capacity)
+	c.instances = newLRUCache[*model.Instance](c.capacity)
+	c.configFiles = newLRUCache[*model.ConfigFile](c.capacity)
+	c.users = newLRUCache[*model.User](c.capacity)
+	c.strategies = newLRUCache[*model.StrategyDetail](c.capacity)
+	return c
+}
+
+var (
+	_ CachingStore         = (*cachingStore)(nil)
+	_ TxStore              = (*cachingStore)(nil)
+	_ NamingTxStore        = (*cachingStore)(nil)
+	_ DistributedLockStore = (*cachingStore)(nil)
+	_ LeaderWatchStore     = (*cachingStore)(nil)
+	_ RevisionStore        = (*cachingStore)(nil)
+	_ RetentionStore       = (*cachingStore)(nil)
+	_ QueryStore           = (*cachingStore)(nil)
+	_ CursorStore          = (*cachingStore)(nil)
+	_ StreamStore          = (*cachingStore)(nil)
+	_ WatchableStore       = (*cachingStore)(nil)
+)
+
+// cachingStore 带有读缓存的 Store 装饰器，未缓存的方法直接交给被包装的 Store 处理
+type cachingStore struct {
+	store            Store
+	ttl              time.Duration
+	capacity         int
+	feedInvalidation bool
+
+	services     *lruCache[*model.Service]
+	servicesByID *lruCache[*model.Service]
+	instances    *lruCache[*model.Instance]
+	configFiles  *lruCache[*model.ConfigFile]
+	users        *lruCache[*model.User]
+	strategies   *lruCache[*model.StrategyDetail]
+}
+
+// CacheStats 实现 CachingStore
+func (c *cachingStore) CacheStats() map[string]CacheStats {
+	return map[string]CacheStats{
+		"GetService":        c.services.stats(),
+		"GetServiceByID":    c.servicesByID.stats(),
+		"GetInstance":       c.instances.stats(),
+		"GetConfigFile":     c.configFiles.stats(),
+		"GetUser":           c.users.stats(),
+		"GetStrategyDetail": c.strategies.stats(),
+	}
+}
+
+// cached 优先从缓存中读取，未命中时查询存储并缓存非空的结果
+func cached[V comparable](c *cachingStore, cache *lruCache[V], key string, load func() (V, error)) (V, error) {
+	if v, ok := cache.get(key); ok {
+		return v, nil
+	}
+	generation := cache.generation()
+	v, err := load()
+	var zero V
+	if err != nil || v == zero {
+		return v, err
+	}
+	cache.add(key, v, c.ttl, generation)
+	return v, nil
+}
+
+func serviceKey(name, namespace string) string {
+	return namespace + "/" + name
+}
+
+func configFileKey(namespace, group, name string) string {
+	return namespace + "/" + group + "/" + name
+}
+
+// invalidateService 失效服务的缓存，同时失效按照名字以及按照 ID 缓存的条目，
+// 两个缓存中的条目不一定同时存在，因此另一个缓存需要按照条目的内容查找
+func (c *cachingStore) invalidateService(id, name, namespace string) {
+	if name != "" || namespace != "" {
+		c.services.remove(serviceKey(name, namespace))
+		c.servicesByID.removeIf(func(svc *model.Service) bool {
+			return svc.Name == name && svc.Namespace == namespace
+		})
+	}
+	if id != "" {
+		c.servicesByID.remove(id)
+		c.services.removeIf(func(svc *model.Service) bool {
+			return svc.ID == id
+		})
+	}
+}
+
+// invalidateInstances 失效实例的缓存，ids 中的元素为实例 ID，与存储插件一样按照 fmt.Sprint 的结果匹配
+func (c *cachingStore) invalidateInstances(ids []interface{}) {
+	for i := range ids {
+		c.instances.remove(fmt.Sprint(ids[i]))
+	}
+}
+
+// BeginTx 实现 TxStore，事务中的写操作在事务结束之后失效相关的缓存
+func (c *cachingStore) BeginTx(opts TxOptions) (UnifiedTx, error) {
+	tx, err := NewTxStore(c.store).BeginTx(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &cachingUnifiedTx{UnifiedTx: tx}, nil
+}
+
+// cachingTx cachingStore 创建的 Tx，记录事务中的写操作需要失效的缓存
+type cachingTx struct {
+	Tx
+	pendingInvalidations
+}
+
+// Commit 提交事务之后失效缓存，避免其他请求在提交之前把旧的数据重新放回缓存
+func (t *cachingTx) Commit() error {
+	defer t.flush()
+	return t.Tx.Commit()
+}
+
+// Rollback 回滚事务之后同样失效缓存，事务进行中其他请求可能读取并缓存了未提交的数据
+func (t *cachingTx) Rollback() error {
+	defer t.flush()
+	return t.Tx.Rollback()
+}
+
+// cachingUnifiedTx cachingStore 创建的 UnifiedTx
+type cachingUnifiedTx struct {
+	UnifiedTx
+	pendingInvalidations
+}
+
+// Commit 提交事务之后失效缓存
+func (t *cachingUnifiedTx) Commit() error {
+	defer t.flush()
+	return t.UnifiedTx.Commit()
+}
+
+// Rollback 回滚事务之后失效缓存
+func (t *cachingUnifiedTx) Rollback() error {
+	defer t.flush()
+	return t.UnifiedTx.Rollback()
+}
+
+// pendingInvalidations 等待事务结束之后执行的缓存失效
+type pendingInvalidations struct {
+	lock    sync.Mutex
+	pending []func()
+}
+
+func (p *pendingInvalidations) add(invalidate func()) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.pending = append(p.pending, invalidate)
+}
+
+func (p *pendingInvalidations) flush() {
+	p.lock.Lock()
+	pending := p.pending
+	p.pending = nil
+	p.lock.Unlock()
+	for i := range pending {
+		pending[i]()
+	}
+}
+
+// innerTx 返回需要传递给被包装的 Store 的 Tx
+func innerTx(tx Tx) Tx {
+	switch t := tx.(type) {
+	case *cachingTx:
+		return t.Tx
+	case *cachingUnifiedTx:
+		return t.UnifiedTx
+	}
+	return tx
+}
+
+// afterWrite tx 由 cachingStore 创建时把 invalidate 推迟到事务结束之后执行，
+// 其他 Tx 无法得知事务何时结束，在写操作返回之后立即执行
+func afterWrite(tx Tx, invalidate func()) {
+	switch t := tx.(type) {
+	case *cachingTx:
+		t.add(invalidate)
+	case *cachingUnifiedTx:
+		t.add(invalidate)
+	default:
+		invalidate()
+	}
+}
+
+// GetService 实现 Store
+func (c *cachingStore) GetService(name string, namespace string) (*model.Service, error) {
+	return cached(c, c.services, serviceKey(name, namespace), func() (*model.Service, error) {
+		return c.store.GetService(name, namespace)
+	})
+}
+
+// GetServiceByID 实现 Store
+func (c *cachingStore) GetServiceByID(id string) (*model.Service, error) {
+	return cached(c, c.servicesByID, id, func() (*model.Service, error) {
+		return c.store.GetServiceByID(id)
+	})
+}
+
+// GetInstance 实现 Store
+func (c *cachingStore) GetInstance(instanceID string) (*model.Instance, error) {
+	return cached(c, c.instances, instanceID, func() (*model.Instance, error) {
+		return c.store.GetInstance(instanceID)
+	})
+}
+
+// GetConfigFile 实现 Store
+func (c *cachingStore) GetConfigFile(namespace, group, name string) (*model.ConfigFile, error) {
+	return cached(c, c.configFiles, configFileKey(namespace, group, name), func() (*model.ConfigFile, error) {
+		return c.store.GetConfigFile(namespace, group, name)
+	})
+}
+
+// GetUser 实现 Store
+func (c *cachingStore) GetUser(id string) (*model.User, error) {
+	return cached(c, c.users, id, func() (*model.User, error) {
+		return c.store.GetUser(id)
+	})
+}
+
+// GetStrategyDetail 实现 Store
+func (c *cachingStore) GetStrategyDetail(id string) (*model.StrategyDetail, error) {
+	return cached(c, c.strategies, id, func() (*model.StrategyDetail, error) {
+		return c.store.GetStrategyDetail(id)
+	})
+}
+
+// AddService 实现 Store
+func (c *cachingStore) AddService(service *model.Service) error {
+	defer c.invalidateService(service.ID, service.Name, service.Namespace)
+	return c.store.AddService(service)
+}
+
+// AddServiceTx 实现 Store
+func (c *cachingStore) AddServiceTx(tx Tx, service *model.Service) error {
+	defer afterWrite(tx, func() { c.invalidateService(service.ID, service.Name, service.Namespace) })
+	return NewNamingTxStore(c.store).AddServiceTx(innerTx(tx), service)
+}
+
+// DeleteService 实现 Store
+func (c *cachingStore) DeleteService(id, serviceName, namespaceName string) error {
+	defer c.invalidateService(id, serviceName, namespaceName)
+	return c.store.DeleteService(id, serviceName, namespaceName)
+}
+
+// DeleteServiceTx 实现 Store
+func (c *cachingStore) DeleteServiceTx(tx Tx, id, serviceName, namespaceName string) error {
+	defer afterWrite(tx, func() { c.invalidateService(id, serviceName, namespaceName) })
+	return NewNamingTxStore(c.store).DeleteServiceTx(innerTx(tx), id, serviceName, namespaceName)
+}
+
+// DeleteServiceAlias 实现 Store
+func (c *cachingStore) DeleteServiceAlias(name string, namespace string) error {
+	defer c.invalidateService("", name, namespace)
+	return c.store.DeleteServiceAlias(name, namespace)
+}
+
+// DeleteServiceAliasTx 实现 Store
+func (c *cachingStore) DeleteServiceAliasTx(tx Tx, name string, namespace string) error {
+	defer afterWrite(tx, func() { c.invalidateService("", name, namespace) })
+	return NewNamingTxStore(c.store).DeleteServiceAliasTx(innerTx(tx), name, namespace)
+}
+
+// UpdateServiceAlias 实现 Store
+func (c *cachingStore) UpdateServiceAlias(alias *model.Service, needUpdateOwner bool) error {
+	defer c.invalidateService(alias.ID, alias.Name, alias.Namespace)
+	return c.store.UpdateServiceAlias(alias, needUpdateOwner)
+}
+
+// UpdateServiceAliasTx 实现 Store
+func (c *cachingStore) UpdateServiceAliasTx(tx Tx, alias *model.Service, needUpdateOwner bool) error {
+	defer afterWrite(tx, func() { c.invalidateService(alias.ID, alias.Name, alias.Namespace) })
+	return NewNamingTxStore(c.store).UpdateServiceAliasTx(innerTx(tx), alias, needUpdateOwner)
+}
+
+// UpdateService 实现 Store，需要同步更新别名的负责人时失效所有服务的缓存
+func (c *cachingStore) UpdateService(service *model.Service, needUpdateOwner bool) error {
+	defer func() {
+		if needUpdateOwner {
+			c.services.purge()
+			c.servicesByID.purge()
+			return
+		}
+		c.invalidateService(service.ID, service.Name, service.Namespace)
+	}()
+	return c.store.UpdateService(service, needUpdateOwner)
+}
+
+// UpdateServiceTx 实现 Store，需要同步更新别名的负责人时失效所有服务的缓存
+func (c *cachingStore) UpdateServiceTx(tx Tx, service *model.Service, needUpdateOwner bool) error {
+	defer afterWrite(tx, func() {
+		if needUpdateOwner {
+			c.services.purge()
+			c.servicesByID.purge()
+			return
+		}
+		c.invalidateService(service.ID, service.Name, service.Namespace)
+	})
+	return NewNamingTxStore(c.store).UpdateServiceTx(innerTx(tx), service, needUpdateOwner)
+}
+
+// UpdateServiceToken 实现 Store
+func (c *cachingStore) UpdateServiceToken(serviceID string, token string, revision string) error {
+	defer c.invalidateService(serviceID, "", "")
+	return c.store.UpdateServiceToken(serviceID, token, revision)
+}
+
+// UpdateServiceTokenTx 实现 Store
+func (c *cachingStore) UpdateServiceTokenTx(tx Tx, serviceID string, token string, revision string) error {
+	defer afterWrite(tx, func() { c.invalidateService(serviceID, "", "") })
+	return NewNamingTxStore(c.store).UpdateServiceTokenTx(innerTx(tx), serviceID, token, revision)
+}
+
+// GetMoreServices 实现 Store
+func (c *cachingStore) GetMoreServices(mtime time.Time, firstUpdate, disableBusiness, needMeta bool) (
+	map[string]*model.Service, error) {
+	services, err := c.store.GetMoreServices(mtime, firstUpdate, disableBusiness, needMeta)
+	if err == nil && c.feedInvalidation {
+		for _, svc := range services {
+			c.invalidateService(svc.ID, svc.Name, svc.Namespace)
+		}
+	}
+	return services, err
+}
+
+// AddInstance 实现 Store
+func (c *cachingStore) AddInstance(instance *model.Instance) error {
+	defer c.instances.remove(instance.Proto.GetId().GetValue())
+	return c.store.AddInstance(instance)
+}
+
+// AddInstanceTx 实现 Store
+func (c *cachingStore) AddInstanceTx(tx Tx, instance *model.Instance) error {
+	defer afterWrite(tx, func() { c.instances.remove(instance.Proto.GetId().GetValue()) })
+	return NewNamingTxStore(c.store).AddInstanceTx(innerTx(tx), instance)
+}
+
+// BatchAddInstances 实现 Store
+func (c *cachingStore) BatchAddInstances(instances []*model.Instance) error {
+	defer func() {
+		for i := range instances {
+			c.instances.remove(instances[i].Proto.GetId().GetValue())
+		}
+	}()
+	return c.store.BatchAddInstances(instances)
+}
+
+// BatchAddInstancesTx 实现 Store
+func (c *cachingStore) BatchAddInstancesTx(tx Tx, instances []*model.Instance) error {
+	defer afterWrite(tx, func() {
+		for i := range instances {
+			c.instances.remove(instances[i].Proto.GetId().GetValue())
+		}
+	})
+	return NewNamingTxStore(c.store).BatchAddInstancesTx(innerTx(tx), instances)
+}
+
+// UpdateInstance 实现 Store
+func (c *cachingStore) UpdateInstance(instance *model.Instance) error {
+	defer c.instances.remove(instance.Proto.GetId().GetValue())
+	return c.store.UpdateInstance(instance)
+}
+
+// UpdateInstanceTx 实现 Store
+func (c *cachingStore) UpdateInstanceTx(tx Tx, instance *model.Instance) error {
+	defer afterWrite(tx, func() { c.instances.remove(instance.Proto.GetId().GetValue()) })
+	return NewNamingTxStore(c.store).UpdateInstanceTx(innerTx(tx), instance)
+}
+
+// DeleteInstance 实现 Store
+func (c *cachingStore) DeleteInstance(instanceID string) error {
+	defer c.instances.remove(instanceID)
+	return c.store.DeleteInstance(instanceID)
+}
+
+// DeleteInstanceTx 实现 Store
+func (c *cachingStore) DeleteInstanceTx(tx Tx, instanceID string) error {
+	defer afterWrite(tx, func() { c.instances.remove(instanceID) })
+	return NewNamingTxStore(c.store).DeleteInstanceTx(innerTx(tx), instanceID)
+}
+
+// BatchDeleteInstances 实现 Store
+func (c *cachingStore) BatchDeleteInstances(ids []interface{}) error {
+	defer c.invalidateInstances(ids)
+	return c.store.BatchDeleteInstances(ids)
+}
+
+// BatchDeleteInstancesTx 实现 Store
+func (c *cachingStore) BatchDeleteInstancesTx(tx Tx, ids []interface{}) error {
+	defer afterWrite(tx, func() { c.invalidateInstances(ids) })
+	return NewNamingTxStore(c.store).BatchDeleteInstancesTx(innerTx(tx), ids)
+}
+
+// CleanInstance 实现 Store
+func (c *cachingStore) CleanInstance(instanceID string) error {
+	defer c.instances.remove(instanceID)
+	return c.store.CleanInstance(instanceID)
+}
+
+// CleanInstanceTx 实现 Store
+func (c *cachingStore) CleanInstanceTx(tx Tx, instanceID string) error {
+	defer afterWrite(tx, func() { c.instances.remove(instanceID) })
+	return NewNamingTxStore(c.store).CleanInstanceTx(innerTx(tx), instanceID)
+}
+
+// SetInstanceHealthStatus 实现 Store
+func (c *cachingStore) SetInstanceHealthStatus(instanceID string, flag int, revision string) error {
+	defer c.instances.remove(instanceID)
+	return c.store.SetInstanceHealthStatus(instanceID, flag, revision)
+}
+
+// SetInstanceHealthStatusTx 实现 Store
+func (c *cachingStore) SetInstanceHealthStatusTx(tx Tx, instanceID string, flag int, revision string) error {
+	defer afterWrite(tx, func() { c.instances.remove(instanceID) })
+	return NewNamingTxStore(c.store).SetInstanceHealthStatusTx(innerTx(tx), instanceID, flag, revision)
+}
+
+// BatchSetInstanceHealthStatus 实现 Store
+func (c *cachingStore) BatchSetInstanceHealthStatus(ids []interface{}, healthy int, revision string) error {
+	defer c.invalidateInstances(ids)
+	return c.store.BatchSetInstanceHealthStatus(ids, healthy, revision)
+}
+
+// BatchSetInstanceHealthStatusTx 实现 Store
+func (c *cachingStore) BatchSetInstanceHealthStatusTx(tx Tx, ids []interface{}, healthy int, revision string) error {
+	defer afterWrite(tx, func() { c.invalidateInstances(ids) })
+	return NewNamingTxStore(c.store).BatchSetInstanceHealthStatusTx(innerTx(tx), ids, healthy, revision)
+}
+
+// BatchSetInstanceIsolate 实现 Store
+func (c *cachingStore) BatchSetInstanceIsolate(ids []interface{}, isolate int, revision string) error {
+	defer c.invalidateInstances(ids)
+	return c.store.BatchSetInstanceIsolate(ids, isolate, revision)
+}
+
+// BatchSetInstanceIsolateTx 实现 Store
+func (c *cachingStore) BatchSetInstanceIsolateTx(tx Tx, ids []interface{}, isolate int, revision string) error {
+	defer afterWrite(tx, func() { c.invalidateInstances(ids) })
+	return NewNamingTxStore(c.store).BatchSetInstanceIsolateTx(innerTx(tx), ids, isolate, revision)
+}
+
+// BatchAppendInstanceMetadata 实现 Store
+func (c *cachingStore) BatchAppendInstanceMetadata(requests []*model.InstanceMetadataRequest) error {
+	defer func() {
+		for i := range requests {
+			c.instances.remove(requests[i].InstanceID)
+		}
+	}()
+	return c.store.BatchAppendInstanceMetadata(requests)
+}
+
+// BatchAppendInstanceMetadataTx 实现 Store
+func (c *cachingStore) BatchAppendInstanceMetadataTx(tx Tx, requests []*model.InstanceMetadataRequest) error {
+	defer afterWrite(tx, func() {
+		for i := range requests {
+			c.instances.remove(requests[i].InstanceID)
+		}
+	})
+	return NewNamingTxStore(c.store).BatchAppendInstanceMetadataTx(innerTx(tx), requests)
+}
+
+// BatchRemoveInstanceMetadata 实现 Store
+func (c *cachingStore) BatchRemoveInstanceMetadata(requests []*model.InstanceMetadataRequest) error {
+	defer func() {
+		for i := range requests {
+			c.instances.remove(requests[i].InstanceID)
+		}
+	}()
+	return c.store.BatchRemoveInstanceMetadata(requests)
+}
+
+// BatchRemoveInstanceMetadataTx 实现 Store
+func (c *cachingStore) BatchRemoveInstanceMetadataTx(tx Tx, requests []*model.InstanceMetadataRequest) error {
+	defer afterWrite(tx, func() {
+		for i := range requests {
+			c.instances.remove(requests[i].InstanceID)
+		}
+	})
+	return NewNamingTxStore(c.store).BatchRemoveInstanceMetadataTx(innerTx(tx), requests)
+}
+
+// BatchCleanDeletedInstances 实现 Store，被清理的实例无法得知，失效所有实例的缓存
+func (c *cachingStore) BatchCleanDeletedInstances(timeout time.Duration, batchSize uint32) (uint32, error) {
+	defer c.instances.purge()
+	return c.store.BatchCleanDeletedInstances(timeout, batchSize)
+}
+
+// GetMoreInstances 实现 Store
+func (c *cachingStore) GetMoreInstances(tx Tx, mtime time.Time, firstUpdate, needMeta bool, serviceID []string) (
+	map[string]*model.Instance, error) {
+	instances, err := c.store.GetMoreInstances(innerTx(tx), mtime, firstUpdate, needMeta, serviceID)
+	if err == nil && c.feedInvalidation {
+		for id := range instances {
+			c.instances.remove(id)
+		}
+	}
+	return instances, err
+}
+
+// CreateRoutingConfigTx 实现 NamingTxStore
+func (c *cachingStore) CreateRoutingConfigTx(tx Tx, conf *model.RoutingConfig) error {
+	return NewNamingTxStore(c.store).CreateRoutingConfigTx(innerTx(tx), conf)
+}
+
+// UpdateRoutingConfigTx 实现 NamingTxStore
+func (c *cachingStore) UpdateRoutingConfigTx(tx Tx, conf *model.RoutingConfig) error {
+	return NewNamingTxStore(c.store).UpdateRoutingConfigTx(innerTx(tx), conf)
+}
+
+// CreateRateLimitTx 实现 NamingTxStore
+func (c *cachingStore) CreateRateLimitTx(tx Tx, limiting *model.RateLimit) error {
+	return NewNamingTxStore(c.store).CreateRateLimitTx(innerTx(tx), limiting)
+}
+
+// UpdateRateLimitTx 实现 NamingTxStore
+func (c *cachingStore) UpdateRateLimitTx(tx Tx, limiting *model.RateLimit) error {
+	return NewNamingTxStore(c.store).UpdateRateLimitTx(innerTx(tx), limiting)
+}
+
+// EnableRateLimitTx 实现 NamingTxStore
+func (c *cachingStore) EnableRateLimitTx(tx Tx, limit *model.RateLimit) error {
+	return NewNamingTxStore(c.store).EnableRateLimitTx(innerTx(tx), limit)
+}
+
+// DeleteRateLimitTx 实现 NamingTxStore
+func (c *cachingStore) DeleteRateLimitTx(tx Tx, limiting *model.RateLimit) error {
+	return NewNamingTxStore(c.store).DeleteRateLimitTx(innerTx(tx), limiting)
+}
+
+// CreateCircuitBreakerRuleTx 实现 NamingTxStore
+func (c *cachingStore) CreateCircuitBreakerRuleTx(tx Tx, cbRule *model.CircuitBreakerRule) error {
+	return NewNamingTxStore(c.store).CreateCircuitBreakerRuleTx(innerTx(tx), cbRule)
+}
+
+// UpdateCircuitBreakerRuleTx 实现 NamingTxStore
+func (c *cachingStore) UpdateCircuitBreakerRuleTx(tx Tx, cbRule *model.CircuitBreakerRule) error {
+	return NewNamingTxStore(c.store).UpdateCircuitBreakerRuleTx(innerTx(tx), cbRule)
+}
+
+// DeleteCircuitBreakerRuleTx 实现 NamingTxStore
+func (c *cachingStore) DeleteCircuitBreakerRuleTx(tx Tx, id string) error {
+	return NewNamingTxStore(c.store).DeleteCircuitBreakerRuleTx(innerTx(tx), id)
+}
+
+// EnableCircuitBreakerRuleTx 实现 NamingTxStore
+func (c *cachingStore) EnableCircuitBreakerRuleTx(tx Tx, cbRule *model.CircuitBreakerRule) error {
+	return NewNamingTxStore(c.store).EnableCircuitBreakerRuleTx(innerTx(tx), cbRule)
+}
+
+// EnableRoutingTx 实现 NamingTxStore
+func (c *cachingStore) EnableRoutingTx(tx Tx, conf *model.RouterConfig) error {
+	return NewNamingTxStore(c.store).EnableRoutingTx(innerTx(tx), conf)
+}
+
+// DeleteRoutingConfigV2Tx 实现 NamingTxStore
+func (c *cachingStore) DeleteRoutingConfigV2Tx(tx Tx, serviceID string) error {
+	return NewNamingTxStore(c.store).DeleteRoutingConfigV2Tx(innerTx(tx), serviceID)
+}
+
+// CreateFaultDetectRuleTx 实现 NamingTxStore
+func (c *cachingStore) CreateFaultDetectRuleTx(tx Tx, conf *model.FaultDetectRule) error {
+	return NewNamingTxStore(c.store).CreateFaultDetectRuleTx(innerTx(tx), conf)
+}
+
+// UpdateFaultDetectRuleTx 实现 NamingTxStore
+func (c *cachingStore) UpdateFaultDetectRuleTx(tx Tx, conf *model.FaultDetectRule) error {
+	return NewNamingTxStore(c.store).UpdateFaultDetectRuleTx(innerTx(tx), conf)
+}
+
+// DeleteFaultDetectRuleTx 实现 NamingTxStore
+func (c *cachingStore) DeleteFaultDetectRuleTx(tx Tx, id string) error {
+	return NewNamingTxStore(c.store).DeleteFaultDetectRuleTx(innerTx(tx), id)
+}
+
+// CreateServiceContractTx 实现 NamingTxStore
+func (c *cachingStore) CreateServiceContractTx(tx Tx, contract *model.ServiceContract) error {
+	return NewNamingTxStore(c.store).CreateServiceContractTx(innerTx(tx), contract)
+}
+
+// UpdateServiceContractTx 实现 NamingTxStore
+func (c *cachingStore) UpdateServiceContractTx(tx Tx, contract *model.ServiceContract) error {
+	return NewNamingTxStore(c.store).UpdateServiceContractTx(innerTx(tx), contract)
+}
+
+// DeleteServiceContractTx 实现 NamingTxStore
+func (c *cachingStore) DeleteServiceContractTx(tx Tx, contract *model.ServiceContract) error {
+	return NewNamingTxStore(c.store).DeleteServiceContractTx(innerTx(tx), contract)
+}
+
+// AddServiceContractInterfacesTx 实现 NamingTxStore
+func (c *cachingStore) AddServiceContractInterfacesTx(tx Tx, contract *model.ServiceContract) error {
+	return NewNamingTxStore(c.store).AddServiceContractInterfacesTx(innerTx(tx), contract)
+}
+
+// AppendServiceContractInterfacesTx 实现 NamingTxStore
+func (c *cachingStore) AppendServiceContractInterfacesTx(tx Tx, contract *model.ServiceContract) error {
+	return NewNamingTxStore(c.store).AppendServiceContractInterfacesTx(innerTx(tx), contract)
+}
+
+// DeleteServiceContractInterfacesTx 实现 NamingTxStore
+func (c *cachingStore) DeleteServiceContractInterfacesTx(tx Tx, contract *model.ServiceContract) error {
+	return NewNamingTxStore(c.store).DeleteServiceContractInterfacesTx(innerTx(tx), contract)
+}
+
+// CreateConfigFileTx 实现 Store
+func (c *cachingStore) CreateConfigFileTx(tx Tx, file *model.ConfigFile) error {
+	defer afterWrite(tx, func() { c.configFiles.remove(configFileKey(file.Namespace, file.Group, file.Name)) })
+	return c.store.CreateConfigFileTx(innerTx(tx), file)
+}
+
+// UpdateConfigFileTx 实现 Store
+func (c *cachingStore) UpdateConfigFileTx(tx Tx, file *model.ConfigFile) error {
+	defer afterWrite(tx, func() { c.configFiles.remove(configFileKey(file.Namespace, file.Group, file.Name)) })
+	return c.store.UpdateConfigFileTx(innerTx(tx), file)
+}
+
+// DeleteConfigFileTx 实现 Store
+func (c *cachingStore) DeleteConfigFileTx(tx Tx, namespace, group, name string) error {
+	defer afterWrite(tx, func() { c.configFiles.remove(configFileKey(namespace, group, name)) })
+	return c.store.DeleteConfigFileTx(innerTx(tx), namespace, group, name)
+}
+
+// AddUser 实现 Store，用户的默认策略会随之创建，失效所有策略的缓存
+func (c *cachingStore) AddUser(user *model.User) error {
+	defer func() {
+		c.users.remove(user.ID)
+		c.strategies.purge()
+	}()
+	return c.store.AddUser(user)
+}
+
+// UpdateUser 实现 Store
+func (c *cachingStore) UpdateUser(user *model.User) error {
+	defer c.users.remove(user.ID)
+	return c.store.UpdateUser(user)
+}
+
+// DeleteUser 实现 Store，用户的默认策略以及作为成员的策略会随之变化，失效所有策略的缓存
+func (c *cachingStore) DeleteUser(user *model.User) error {
+	defer func() {
+		c.users.remove(user.ID)
+		c.strategies.purge()
+	}()
+	return c.store.DeleteUser(user)
+}
+
+// GetUsersForCache 实现 Store
+func (c *cachingStore) GetUsersForCache(mtime time.Time, firstUpdate bool) ([]*model.User, error) {
+	users, err := c.store.GetUsersForCache(mtime, firstUpdate)
+	if err == nil && c.feedInvalidation {
+		for i := range users {
+			c.users.remove(users[i].ID)
+		}
+	}
+	return users, err
+}
+
+// AddGroup 实现 Store，用户组的默认策略会随之创建，失效所有策略的缓存
+func (c *cachingStore) AddGroup(group *model.UserGroup) error {
+	defer c.strategies.purge()
+	return c.store.AddGroup(group)
+}
+
+// DeleteGroup 实现 Store，用户组的默认策略以及作为成员的策略会随之变化，失效所有策略的缓存
+func (c *cachingStore) DeleteGroup(group *model.UserGroup) error {
+	defer c.strategies.purge()
+	return c.store.DeleteGroup(group)
+}
+
+// AddStrategy 实现 Store
+func (c *cachingStore) AddStrategy(strategy *model.StrategyDetail) error {
+	defer c.strategies.remove(strategy.ID)
+	return c.store.AddStrategy(strategy)
+}
+
+// UpdateStrategy 实现 Store
+func (c *cachingStore) UpdateStrategy(strategy *model.ModifyStrategyDetail) error {
+	defer c.strategies.remove(strategy.ID)
+	return c.store.UpdateStrategy(strategy)
+}
+
+// DeleteStrategy 实现 Store
+func (c *cachingStore) DeleteStrategy(id string) error {
+	defer c.strategies.remove(id)
+	return c.store.DeleteStrategy(id)
+}
+
+// LooseAddStrategyResources 实现 Store
+func (c *cachingStore) LooseAddStrategyResources(resources []model.StrategyResource) error {
+	defer c.invalidateStrategyResources(resources)
+	return c.store.LooseAddStrategyResources(resources)
+}
+
+// RemoveStrategyResources 实现 Store
+func (c *cachingStore) RemoveStrategyResources(resources []model.StrategyResource) error {
+	defer c.invalidateStrategyResources(resources)
+	return c.store.RemoveStrategyResources(resources)
+}
+
+// GetStrategyDetailsForCache 实现 Store
+func (c *cachingStore) GetStrategyDetailsForCache(mtime time.Time, firstUpdate bool) (
+	[]*model.StrategyDetail, error) {
+	strategies, err := c.store.GetStrategyDetailsForCache(mtime, firstUpdate)
+	if err == nil && c.feedInvalidation {
+		for i := range strategies {
+			c.strategies.remove(strategies[i].ID)
+		}
+	}
+	return strategies, err
+}
+
+// WatchLeaderElection 实现 LeaderWatchStore
+func (c *cachingStore) WatchLeaderElection(ctx context.Context, key string) (LeaderWatcher, error) {
+	return WatchLeaderElection(ctx, c.store, key)
+}
+
+// AcquireLock 实现 DistributedLockStore
+func (c *cachingStore) AcquireLock(key string, owner string, ttl time.Duration) (*model.DistributedLock, error) {
+	return NewDistributedLockStore(c.store).AcquireLock(key, owner, ttl)
+}
+
+// RenewLock 实现 DistributedLockStore
+func (c *cachingStore) RenewLock(key string, owner string, ttl time.Duration) (*model.DistributedLock, error) {
+	return NewDistributedLockStore(c.store).RenewLock(key, owner, ttl)
+}
+
+// ReleaseLock 实现 DistributedLockStore
+func (c *cachingStore) ReleaseLock(key string, owner string) error {
+	return NewDistributedLockStore(c.store).ReleaseLock(key, owner)
+}
+
+// ListLocks 实现 DistributedLockStore
+func (c *cachingStore) ListLocks() ([]*model.DistributedLock, error) {
+	return NewDistributedLockStore(c.store).ListLocks()
+}
+
+// Name 实现 Store
+func (c *cachingStore) Name() string {
+	return c.store.Name()
+}
+
+// Initialize 实现 Store
+func (c *cachingStore) Initialize(conf *Config) error {
+	return c.store.Initialize(conf)
+}
+
+// Destroy 实现 Store
+func (c *cachingStore) Destroy() error {
+	return c.store.Destroy()
+}
+
+// CreateTransaction 实现 Store
+func (c *cachingStore) CreateTransaction() (Transaction, error) {
+	return c.store.CreateTransaction()
+}
+
+// StartTx 实现 Store，事务中的写操作在事务结束之后失效相关的缓存
+func (c *cachingStore) StartTx() (Tx, error) {
+	tx, err := c.store.StartTx()
+	if err != nil {
+		return nil, err
+	}
+	return &cachingTx{Tx: tx}, nil
+}
+
+// StartReadTx 实现 Store
+func (c *cachingStore) StartReadTx() (Tx, error) {
+	return c.store.StartReadTx()
+}
+
+// AddNamespace 实现 Store
+func (c *cachingStore) AddNamespace(namespace *model.Namespace) error {
+	return c.store.AddNamespace(namespace)
+}
+
+// UpdateNamespace 实现 Store
+func (c *cachingStore) UpdateNamespace(namespace *model.Namespace) error {
+	return c.store.UpdateNamespace(namespace)
+}
+
+// UpdateNamespaceToken 实现 Store
+func (c *cachingStore) UpdateNamespaceToken(name string, token string) error {
+	return c.store.UpdateNamespaceToken(name, token)
+}
+
+// GetNamespace 实现 Store
+func (c *cachingStore) GetNamespace(name string) (*model.Namespace, error) {
+	return c.store.GetNamespace(name)
+}
+
+// GetNamespaces 实现 Store
+func (c *cachingStore) GetNamespaces(filter map[string][]string, offset int, limit int) ([]*model.Namespace, uint32, error) {
+	return c.store.GetNamespaces(filter, offset, limit)
+}
+
+// GetMoreNamespaces 实现 Store
+func (c *cachingStore) GetMoreNamespaces(mtime time.Time) ([]*model.Namespace, error) {
+	return c.store.GetMoreNamespaces(mtime)
+}
+
+// GetSourceServiceToken 实现 Store
+func (c *cachingStore) GetSourceServiceToken(name string, namespace string) (*model.Service, error) {
+	return c.store.GetSourceServiceToken(name, namespace)
+}
+
+// GetServices 实现 Store
+func (c *cachingStore) GetServices(serviceFilters map[string]string, serviceMetas map[string]string,
+	instanceFilters *model.InstanceArgs, offset uint32, limit uint32) (uint32, []*model.Service, error) {
+	return c.store.GetServices(serviceFilters, serviceMetas, instanceFilters, offset, limit)
+}
+
+// GetServicesCount 实现 Store
+func (c *cachingStore) GetServicesCount() (uint32, error) {
+	return c.store.GetServicesCount()
+}
+
+// GetServiceAliases 实现 Store
+func (c *cachingStore) GetServiceAliases(filter map[string]string, offset uint32, limit uint32) (uint32, []*model.ServiceAlias, error) {
+	return c.store.GetServiceAliases(filter, offset, limit)
+}
+
+// GetSystemServices 实现 Store
+func (c *cachingStore) GetSystemServices() ([]*model.Service, error) {
+	return c.store.GetSystemServices()
+}
+
+// GetServicesBatch 实现 Store
+func (c *cachingStore) GetServicesBatch(services []*model.Service) ([]*model.Service, error) {
+	return c.store.GetServicesBatch(services)
+}
+
+// BatchGetInstanceIsolate 实现 Store
+func (c *cachingStore) BatchGetInstanceIsolate(ids map[string]bool) (map[string]bool, error) {
+	return c.store.BatchGetInstanceIsolate(ids)
+}
+
+// GetInstancesBrief 实现 Store
+func (c *cachingStore) GetInstancesBrief(ids map[string]bool) (map[string]*model.Instance, error) {
+	return c.store.GetInstancesBrief(ids)
+}
+
+// GetInstancesCount 实现 Store
+func (c *cachingStore) GetInstancesCount() (uint32, error) {
+	return c.store.GetInstancesCount()
+}
+
+// GetInstancesCountTx 实现 Store
+func (c *cachingStore) GetInstancesCountTx(tx Tx) (uint32, error) {
+	return c.store.GetInstancesCountTx(innerTx(tx))
+}
+
+// GetInstancesMainByService 实现 Store
+func (c *cachingStore) GetInstancesMainByService(serviceID string, host string) ([]*model.Instance, error) {
+	return c.store.GetInstancesMainByService(serviceID, host)
+}
+
+// GetExpandInstances 实现 Store
+func (c *cachingStore) GetExpandInstances(filter map[string]string, metaFilter map[string]string, offset uint32,
+	limit uint32) (uint32, []*model.Instance, error) {
+	return c.store.GetExpandInstances(filter, metaFilter, offset, limit)
+}
+
+// CreateRoutingConfig 实现 Store
+func (c *cachingStore) CreateRoutingConfig(conf *model.RoutingConfig) error {
+	return c.store.CreateRoutingConfig(conf)
+}
+
+// UpdateRoutingConfig 实现 Store
+func (c *cachingStore) UpdateRoutingConfig(conf *model.RoutingConfig) error {
+	return c.store.UpdateRoutingConfig(conf)
+}
+
+// DeleteRoutingConfig 实现 Store
+func (c *cachingStore) DeleteRoutingConfig(serviceID string) error {
+	return c.store.DeleteRoutingConfig(serviceID)
+}
+
+// DeleteRoutingConfigTx 实现 Store
+func (c *cachingStore) DeleteRoutingConfigTx(tx Tx, serviceID string) error {
+	return c.store.DeleteRoutingConfigTx(innerTx(tx), serviceID)
+}
+
+// GetRoutingConfigsForCache 实现 Store
+func (c *cachingStore) GetRoutingConfigsForCache(mtime time.Time, firstUpdate bool) ([]*model.RoutingConfig, error) {
+	return c.store.GetRoutingConfigsForCache(mtime, firstUpdate)
+}
+
+// GetRoutingConfigWithService 实现 Store
+func (c *cachingStore) GetRoutingConfigWithService(name string, namespace string) (*model.RoutingConfig, error) {
+	return c.store.GetRoutingConfigWithService(name, namespace)
+}
+
+// GetRoutingConfigWithID 实现 Store
+func (c *cachingStore) GetRoutingConfigWithID(id string) (*model.RoutingConfig, error) {
+	return c.store.GetRoutingConfigWithID(id)
+}
+
+// GetRoutingConfigs 实现 Store
+func (c *cachingStore) GetRoutingConfigs(filter map[string]string, offset uint32, limit uint32) (uint32, []*model.RoutingConfig, error) {
+	return c.store.GetRoutingConfigs(filter, offset, limit)
+}
+
+// GetL5Extend 实现 Store
+func (c *cachingStore) GetL5Extend(serviceID string) (map[string]interface{}, error) {
+	return c.store.GetL5Extend(serviceID)
+}
+
+// SetL5Extend 实现 Store
+func (c *cachingStore) SetL5Extend(serviceID string, meta map[string]interface{}) (map[string]interface{}, error) {
+	return c.store.SetL5Extend(serviceID, meta)
+}
+
+// GenNextL5Sid 实现 Store
+func (c *cachingStore) GenNextL5Sid(layoutID uint32) (string, error) {
+	return c.store.GenNextL5Sid(layoutID)
+}
+
+// GetMoreL5Extend 实现 Store
+func (c *cachingStore) GetMoreL5Extend(mtime time.Time) (map[string]map[string]interface{}, error) {
+	return c.store.GetMoreL5Extend(mtime)
+}
+
+// GetMoreL5Routes 实现 Store
+func (c *cachingStore) GetMoreL5Routes(flow uint32) ([]*model.Route, error) {
+	return c.store.GetMoreL5Routes(flow)
+}
+
+// GetMoreL5Policies 实现 Store
+func (c *cachingStore) GetMoreL5Policies(flow uint32) ([]*model.Policy, error) {
+	return c.store.GetMoreL5Policies(flow)
+}
+
+// GetMoreL5Sections 实现 Store
+func (c *cachingStore) GetMoreL5Sections(flow uint32) ([]*model.Section, error) {
+	return c.store.GetMoreL5Sections(flow)
+}
+
+// GetMoreL5IPConfigs 实现 Store
+func (c *cachingStore) GetMoreL5IPConfigs(flow uint32) ([]*model.IPConfig, error) {
+	return c.store.GetMoreL5IPConfigs(flow)
+}
+
+// CreateRateLimit 实现 Store
+func (c *cachingStore) CreateRateLimit(limiting *model.RateLimit) error {
+	return c.store.CreateRateLimit(limiting)
+}
+
+// UpdateRateLimit 实现 Store
+func (c *cachingStore) UpdateRateLimit(limiting *model.RateLimit) error {
+	return c.store.UpdateRateLimit(limiting)
+}
+
+// EnableRateLimit 实现 Store
+func (c *cachingStore) EnableRateLimit(limit *model.RateLimit) error {
+	return c.store.EnableRateLimit(limit)
+}
+
+// DeleteRateLimit 实现 Store
+func (c *cachingStore) DeleteRateLimit(limiting *model.RateLimit) error {
+	return c.store.DeleteRateLimit(limiting)
+}
+
+// GetExtendRateLimits 实现 Store
+func (c *cachingStore) GetExtendRateLimits(query map[string]string, offset uint32, limit uint32) (uint32, []*model.RateLimit, error) {
+	return c.store.GetExtendRateLimits(query, offset, limit)
+}
+
+// GetRateLimitWithID 实现 Store
+func (c *cachingStore) GetRateLimitWithID(id string) (*model.RateLimit, error) {
+	return c.store.GetRateLimitWithID(id)
+}
+
+// GetRateLimitsForCache 实现 Store
+func (c *cachingStore) GetRateLimitsForCache(mtime time.Time, firstUpdate bool) ([]*model.RateLimit, error) {
+	return c.store.GetRateLimitsForCache(mtime, firstUpdate)
+}
+
+// CreateCircuitBreakerRule 实现 Store
+func (c *cachingStore) CreateCircuitBreakerRule(cbRule *model.CircuitBreakerRule) error {
+	return c.store.CreateCircuitBreakerRule(cbRule)
+}
+
+// UpdateCircuitBreakerRule 实现 Store
+func (c *cachingStore) UpdateCircuitBreakerRule(cbRule *model.CircuitBreakerRule) error {
+	return c.store.UpdateCircuitBreakerRule(cbRule)
+}
+
+// DeleteCircuitBreakerRule 实现 Store
+func (c *cachingStore) DeleteCircuitBreakerRule(id string) error {
+	return c.store.DeleteCircuitBreakerRule(id)
+}
+
+// HasCircuitBreakerRule 实现 Store
+func (c *cachingStore) HasCircuitBreakerRule(id string) (bool, error) {
+	return c.store.HasCircuitBreakerRule(id)
+}
+
+// HasCircuitBreakerRuleByName 实现 Store
+func (c *cachingStore) HasCircuitBreakerRuleByName(name string, namespace string) (bool, error) {
+	return c.store.HasCircuitBreakerRuleByName(name, namespace)
+}
+
+// HasCircuitBreakerRuleByNameExcludeId 实现 Store
+func (c *cachingStore) HasCircuitBreakerRuleByNameExcludeId(name string, namespace string, id string) (bool, error) {
+	return c.store.HasCircuitBreakerRuleByNameExcludeId(name, namespace, id)
+}
+
+// GetCircuitBreakerRules 实现 Store
+func (c *cachingStore) GetCircuitBreakerRules(filter map[string]string, offset uint32, limit uint32) (uint32,
+	[]*model.CircuitBreakerRule, error) {
+	return c.store.GetCircuitBreakerRules(filter, offset, limit)
+}
+
+// GetCircuitBreakerRulesForCache 实现 Store
+func (c *cachingStore) GetCircuitBreakerRulesForCache(mtime time.Time, firstUpdate bool) ([]*model.CircuitBreakerRule, error) {
+	return c.store.GetCircuitBreakerRulesForCache(mtime, firstUpdate)
+}
+
+// EnableCircuitBreakerRule 实现 Store
+func (c *cachingStore) EnableCircuitBreakerRule(cbRule *model.CircuitBreakerRule) error {
+	return c.store.EnableCircuitBreakerRule(cbRule)
+}
+
+// EnableRouting 实现 Store
+func (c *cachingStore) EnableRouting(conf *model.RouterConfig) error {
+	return c.store.EnableRouting(conf)
+}
+
+// CreateRoutingConfigV2 实现 Store
+func (c *cachingStore) CreateRoutingConfigV2(conf *model.RouterConfig) error {
+	return c.store.CreateRoutingConfigV2(conf)
+}
+
+// CreateRoutingConfigV2Tx 实现 Store
+func (c *cachingStore) CreateRoutingConfigV2Tx(tx Tx, conf *model.RouterConfig) error {
+	return c.store.CreateRoutingConfigV2Tx(innerTx(tx), conf)
+}
+
+// UpdateRoutingConfigV2 实现 Store
+func (c *cachingStore) UpdateRoutingConfigV2(conf *model.RouterConfig) error {
+	return c.store.UpdateRoutingConfigV2(conf)
+}
+
+// UpdateRoutingConfigV2Tx 实现 Store
+func (c *cachingStore) UpdateRoutingConfigV2Tx(tx Tx, conf *model.RouterConfig) error {
+	return c.store.UpdateRoutingConfigV2Tx(innerTx(tx), conf)
+}
+
+// DeleteRoutingConfigV2 实现 Store
+func (c *cachingStore) DeleteRoutingConfigV2(serviceID string) error {
+	return c.store.DeleteRoutingConfigV2(serviceID)
+}
+
+// GetRoutingConfigsV2ForCache 实现 Store
+func (c *cachingStore) GetRoutingConfigsV2ForCache(mtime time.Time, firstUpdate bool) ([]*model.RouterConfig, error) {
+	return c.store.GetRoutingConfigsV2ForCache(mtime, firstUpdate)
+}
+
+// GetRoutingConfigV2WithID 实现 Store
+func (c *cachingStore) GetRoutingConfigV2WithID(id string) (*model.RouterConfig, error) {
+	return c.store.GetRoutingConfigV2WithID(id)
+}
+
+// GetRoutingConfigV2WithIDTx 实现 Store
+func (c *cachingStore) GetRoutingConfigV2WithIDTx(tx Tx, id string) (*model.RouterConfig, error) {
+	return c.store.GetRoutingConfigV2WithIDTx(innerTx(tx), id)
+}
+
+// CreateFaultDetectRule 实现 Store
+func (c *cachingStore) CreateFaultDetectRule(conf *model.FaultDetectRule) error {
+	return c.store.CreateFaultDetectRule(conf)
+}
+
+// UpdateFaultDetectRule 实现 Store
+func (c *cachingStore) UpdateFaultDetectRule(conf *model.FaultDetectRule) error {
+	return c.store.UpdateFaultDetectRule(conf)
+}
+
+// DeleteFaultDetectRule 实现 Store
+func (c *cachingStore) DeleteFaultDetectRule(id string) error {
+	return c.store.DeleteFaultDetectRule(id)
+}
+
+// HasFaultDetectRule 实现 Store
+func (c *cachingStore) HasFaultDetectRule(id string) (bool, error) {
+	return c.store.HasFaultDetectRule(id)
+}
+
+// HasFaultDetectRuleByName 实现 Store
+func (c *cachingStore) HasFaultDetectRuleByName(name string, namespace string) (bool, error) {
+	return c.store.HasFaultDetectRuleByName(name, namespace)
+}
+
+// HasFaultDetectRuleByNameExcludeId 实现 Store
+func (c *cachingStore) HasFaultDetectRuleByNameExcludeId(name string, namespace string, id string) (bool, error) {
+	return c.store.HasFaultDetectRuleByNameExcludeId(name, namespace, id)
+}
+
+// GetFaultDetectRules 实现 Store
+func (c *cachingStore) GetFaultDetectRules(filter map[string]string, offset uint32, limit uint32) (uint32,
+	[]*model.FaultDetectRule, error) {
+	return c.store.GetFaultDetectRules(filter, offset, limit)
+}
+
+// GetFaultDetectRulesForCache 实现 Store
+func (c *cachingStore) GetFaultDetectRulesForCache(mtime time.Time, firstUpdate bool) ([]*model.FaultDetectRule, error) {
+	return c.store.GetFaultDetectRulesForCache(mtime, firstUpdate)
+}
+
+// CreateServiceContract 实现 Store
+func (c *cachingStore) CreateServiceContract(contract *model.ServiceContract) error {
+	return c.store.CreateServiceContract(contract)
+}
+
+// UpdateServiceContract 实现 Store
+func (c *cachingStore) UpdateServiceContract(contract *model.ServiceContract) error {
+	return c.store.UpdateServiceContract(contract)
+}
+
+// DeleteServiceContract 实现 Store
+func (c *cachingStore) DeleteServiceContract(contract *model.ServiceContract) error {
+	return c.store.DeleteServiceContract(contract)
+}
+
+// GetMoreServiceContracts 实现 Store
+func (c *cachingStore) GetMoreServiceContracts(firstUpdate bool, mtime time.Time) ([]*model.ServiceContract, error) {
+	return c.store.GetMoreServiceContracts(firstUpdate, mtime)
+}
+
+// GetServiceContract 实现 Store
+func (c *cachingStore) GetServiceContract(id string) (*model.ServiceContract, error) {
+	return c.store.GetServiceContract(id)
+}
+
+// AddServiceContractInterfaces 实现 Store
+func (c *cachingStore) AddServiceContractInterfaces(contract *model.ServiceContract) error {
+	return c.store.AddServiceContractInterfaces(contract)
+}
+
+// AppendServiceContractInterfaces 实现 Store
+func (c *cachingStore) AppendServiceContractInterfaces(contract *model.ServiceContract) error {
+	return c.store.AppendServiceContractInterfaces(contract)
+}
+
+// DeleteServiceContractInterfaces 实现 Store
+func (c *cachingStore) DeleteServiceContractInterfaces(contract *model.ServiceContract) error {
+	return c.store.DeleteServiceContractInterfaces(contract)
+}
+
+// CreateConfigFileGroup 实现 Store
+func (c *cachingStore) CreateConfigFileGroup(fileGroup *model.ConfigFileGroup) (*model.ConfigFileGroup, error) {
+	return c.store.CreateConfigFileGroup(fileGroup)
+}
+
+// UpdateConfigFileGroup 实现 Store
+func (c *cachingStore) UpdateConfigFileGroup(fileGroup *model.ConfigFileGroup) error {
+	return c.store.UpdateConfigFileGroup(fileGroup)
+}
+
+// GetConfigFileGroup 实现 Store
+func (c *cachingStore) GetConfigFileGroup(namespace string, name string) (*model.ConfigFileGroup, error) {
+	return c.store.GetConfigFileGroup(namespace, name)
+}
+
+// DeleteConfigFileGroup 实现 Store
+func (c *cachingStore) DeleteConfigFileGroup(namespace string, name string) error {
+	return c.store.DeleteConfigFileGroup(namespace, name)
+}
+
+// GetMoreConfigGroup 实现 Store
+func (c *cachingStore) GetMoreConfigGroup(firstUpdate bool, mtime time.Time) ([]*model.ConfigFileGroup, error) {
+	return c.store.GetMoreConfigGroup(firstUpdate, mtime)
+}
+
+// CountConfigGroups 实现 Store
+func (c *cachingStore) CountConfigGroups(namespace string) (uint64, error) {
+	return c.store.CountConfigGroups(namespace)
+}
+
+// LockConfigFile 实现 Store
+func (c *cachingStore) LockConfigFile(tx Tx, file *model.ConfigFileKey) (*model.ConfigFile, error) {
+	return c.store.LockConfigFile(innerTx(tx), file)
+}
+
+// GetConfigFileTx 实现 Store
+func (c *cachingStore) GetConfigFileTx(tx Tx, namespace string, group string, name string) (*model.ConfigFile, error) {
+	return c.store.GetConfigFileTx(innerTx(tx), namespace, group, name)
+}
+
+// QueryConfigFiles 实现 Store
+func (c *cachingStore) QueryConfigFiles(filter map[string]string, offset uint32, limit uint32) (uint32, []*model.ConfigFile, error) {
+	return c.store.QueryConfigFiles(filter, offset, limit)
+}
+
+// CountConfigFiles 实现 Store
+func (c *cachingStore) CountConfigFiles(namespace string, group string) (uint64, error) {
+	return c.store.CountConfigFiles(namespace, group)
+}
+
+// CountConfigFileEachGroup 实现 Store
+func (c *cachingStore) CountConfigFileEachGroup() (map[string]map[string]int64, error) {
+	return c.store.CountConfigFileEachGroup()
+}
+
+// GetConfigFileActiveRelease 实现 Store
+func (c *cachingStore) GetConfigFileActiveRelease(file *model.ConfigFileKey) (*model.ConfigFileRelease, error) {
+	return c.store.GetConfigFileActiveRelease(file)
+}
+
+// GetConfigFileActiveReleaseTx 实现 Store
+func (c *cachingStore) GetConfigFileActiveReleaseTx(tx Tx, file *model.ConfigFileKey) (*model.ConfigFileRelease, error) {
+	return c.store.GetConfigFileActiveReleaseTx(innerTx(tx), file)
+}
+
+// CreateConfigFileReleaseTx 实现 Store
+func (c *cachingStore) CreateConfigFileReleaseTx(tx Tx, fileRelease *model.ConfigFileRelease) error {
+	return c.store.CreateConfigFileReleaseTx(innerTx(tx), fileRelease)
+}
+
+// GetConfigFileRelease 实现 Store
+func (c *cachingStore) GetConfigFileRelease(req *model.ConfigFileReleaseKey) (*model.ConfigFileRelease, error) {
+	return c.store.GetConfigFileRelease(req)
+}
+
+// GetConfigFileReleaseTx 实现 Store
+func (c *cachingStore) GetConfigFileReleaseTx(tx Tx, req *model.ConfigFileReleaseKey) (*model.ConfigFileRelease, error) {
+	return c.store.GetConfigFileReleaseTx(innerTx(tx), req)
+}
+
+// DeleteConfigFileReleaseTx 实现 Store
+func (c *cachingStore) DeleteConfigFileReleaseTx(tx Tx, data *model.ConfigFileReleaseKey) error {
+	return c.store.DeleteConfigFileReleaseTx(innerTx(tx), data)
+}
+
+// ActiveConfigFileReleaseTx 实现 Store
+func (c *cachingStore) ActiveConfigFileReleaseTx(tx Tx, release *model.ConfigFileRelease) error {
+	return c.store.ActiveConfigFileReleaseTx(innerTx(tx), release)
+}
+
+// InactiveConfigFileReleaseTx 实现 Store
+func (c *cachingStore) InactiveConfigFileReleaseTx(tx Tx, release *model.ConfigFileRelease) error {
+	return c.store.InactiveConfigFileReleaseTx(innerTx(tx), release)
+}
+
+// CleanConfigFileReleasesTx 实现 Store
+func (c *cachingStore) CleanConfigFileReleasesTx(tx Tx, namespace string, group string, fileName string) error {
+	return c.store.CleanConfigFileReleasesTx(innerTx(tx), namespace, group, fileName)
+}
+
+// GetMoreReleaseFile 实现 Store
+func (c *cachingStore) GetMoreReleaseFile(firstUpdate bool, modifyTime time.Time) ([]*model.ConfigFileRelease, error) {
+	return c.store.GetMoreReleaseFile(firstUpdate, modifyTime)
+}
+
+// CountConfigReleases 实现 Store
+func (c *cachingStore) CountConfigReleases(namespace string, group string, onlyActive bool) (uint64, error) {
+	return c.store.CountConfigReleases(namespace, group, onlyActive)
+}
+
+// GetConfigFileBetaReleaseTx 实现 Store
+func (c *cachingStore) GetConfigFileBetaReleaseTx(tx Tx, file *model.ConfigFileKey) (*model.ConfigFileRelease, error) {
+	return c.store.GetConfigFileBetaReleaseTx(innerTx(tx), file)
+}
+
+// CreateConfigFileReleaseHistory 实现 Store
+func (c *cachingStore) CreateConfigFileReleaseHistory(history *model.ConfigFileReleaseHistory) error {
+	return c.store.CreateConfigFileReleaseHistory(history)
+}
+
+// QueryConfigFileReleaseHistories 实现 Store
+func (c *cachingStore) QueryConfigFileReleaseHistories(filter map[string]string, offset uint32,
+	limit uint32) (uint32, []*model.ConfigFileReleaseHistory, error) {
+	return c.store.QueryConfigFileReleaseHistories(filter, offset, limit)
+}
+
+// CleanConfigFileReleaseHistory 实现 Store
+func (c *cachingStore) CleanConfigFileReleaseHistory(endTime time.Time, limit uint64) error {
+	return c.store.CleanConfigFileReleaseHistory(endTime, limit)
+}
+
+// QueryAllConfigFileTemplates 实现 Store
+func (c *cachingStore) QueryAllConfigFileTemplates() ([]*model.ConfigFileTemplate, error) {
+	return c.store.QueryAllConfigFileTemplates()
+}
+
+// CreateConfigFileTemplate 实现 Store
+func (c *cachingStore) CreateConfigFileTemplate(template *model.ConfigFileTemplate) (*model.ConfigFileTemplate, error) {
+	return c.store.CreateConfigFileTemplate(template)
+}
+
+// GetConfigFileTemplate 实现 Store
+func (c *cachingStore) GetConfigFileTemplate(name string) (*model.ConfigFileTemplate, error) {
+	return c.store.GetConfigFileTemplate(name)
+}
+
+// BatchAddClients 实现 Store
+func (c *cachingStore) BatchAddClients(clients []*model.Client) error {
+	return c.store.BatchAddClients(clients)
+}
+
+// BatchDeleteClients 实现 Store
+func (c *cachingStore) BatchDeleteClients(ids []string) error {
+	return c.store.BatchDeleteClients(ids)
+}
+
+// GetMoreClients 实现 Store
+func (c *cachingStore) GetMoreClients(mtime time.Time, firstUpdate bool) (map[string]*model.Client, error) {
+	return c.store.GetMoreClients(mtime, firstUpdate)
+}
+
+// StartLeaderElection 实现 Store
+func (c *cachingStore) StartLeaderElection(key string) error {
+	return c.store.StartLeaderElection(key)
+}
+
+// IsLeader 实现 Store
+func (c *cachingStore) IsLeader(key string) bool {
+	return c.store.IsLeader(key)
+}
+
+// ListLeaderElections 实现 Store
+func (c *cachingStore) ListLeaderElections() ([]*model.LeaderElection, error) {
+	return c.store.ListLeaderElections()
+}
+
+// ReleaseLeaderElection 实现 Store
+func (c *cachingStore) ReleaseLeaderElection(key string) error {
+	return c.store.ReleaseLeaderElection(key)
+}
+
+// GetUnHealthyInstances 实现 Store
+func (c *cachingStore) GetUnHealthyInstances(timeout time.Duration, limit uint32) ([]string, error) {
+	return c.store.GetUnHealthyInstances(timeout, limit)
+}
+
+// BatchCleanDeletedClients 实现 Store
+func (c *cachingStore) BatchCleanDeletedClients(timeout time.Duration, batchSize uint32) (uint32, error) {
+	return c.store.BatchCleanDeletedClients(timeout, batchSize)
+}
+
+// CleanGrayResource 实现 Store
+func (c *cachingStore) CleanGrayResource(tx Tx, data *model.GrayResource) error {
+	return c.store.CleanGrayResource(innerTx(tx), data)
+}
+
+// CreateGrayResourceTx 实现 Store
+func (c *cachingStore) CreateGrayResourceTx(tx Tx, data *model.GrayResource) error {
+	return c.store.CreateGrayResourceTx(innerTx(tx), data)
+}
+
+// GetMoreGrayResouces 实现 Store
+func (c *cachingStore) GetMoreGrayResouces(firstUpdate bool, mtime time.Time) ([]*model.GrayResource, error) {
+	return c.store.GetMoreGrayResouces(firstUpdate, mtime)
+}
+
+// GetSubCount 实现 Store
+func (c *cachingStore) GetSubCount(user *model.User) (uint32, error) {
+	return c.store.GetSubCount(user)
+}
+
+// GetUserByName 实现 Store
+func (c *cachingStore) GetUserByName(name string, ownerId string) (*model.User, error) {
+	return c.store.GetUserByName(name, ownerId)
+}
+
+// GetUserByIds 实现 Store
+func (c *cachingStore) GetUserByIds(ids []string) ([]*model.User, error) {
+	return c.store.GetUserByIds(ids)
+}
+
+// GetUsers 实现 Store
+func (c *cachingStore) GetUsers(filters map[string]string, offset uint32, limit uint32) (uint32, []*model.User, error) {
+	return c.store.GetUsers(filters, offset, limit)
+}
+
+// UpdateGroup 实现 Store
+func (c *cachingStore) UpdateGroup(group *model.ModifyUserGroup) error {
+	return c.store.UpdateGroup(group)
+}
+
+// GetGroup 实现 Store
+func (c *cachingStore) GetGroup(id string) (*model.UserGroup, error) {
+	return c.store.GetGroup(id)
+}
+
+// GetGroupByName 实现 Store
+func (c *cachingStore) GetGroupByName(name string, owner string) (*model.UserGroup, error) {
+	return c.store.GetGroupByName(name, owner)
+}
+
+// GetGroups 实现 Store
+func (c *cachingStore) GetGroups(filters map[string]string, offset uint32, limit uint32) (uint32, []*model.UserGroup, error) {
+	return c.store.GetGroups(filters, offset, limit)
+}
+
+// GetGroupsForCache 实现 Store
+func (c *cachingStore) GetGroupsForCache(mtime time.Time, firstUpdate bool) ([]*model.UserGroup, error) {
+	return c.store.GetGroupsForCache(mtime, firstUpdate)
+}
+
+// GetStrategyResources 实现 Store
+func (c *cachingStore) GetStrategyResources(principalId string, principalRole string) ([]model.StrategyResource, error) {
+	return c.store.GetStrategyResources(principalId, principalRole)
+}
+
+// GetDefaultStrategyDetailByPrincipal 实现 Store
+func (c *cachingStore) GetDefaultStrategyDetailByPrincipal(principalId string, principalType string) (*model.StrategyDetail, error) {
+	return c.store.GetDefaultStrategyDetailByPrincipal(principalId, principalType)
+}
+
+// GetStrategies 实现 Store
+func (c *cachingStore) GetStrategies(filters map[string]string, offset uint32, limit uint32) (uint32, []*model.StrategyDetail, error) {
+	return c.store.GetStrategies(filters, offset, limit)
+}
+
+// UpdateServiceCAS 实现 RevisionStore，需要同步更新别名的负责人时失效所有服务的缓存
+func (c *cachingStore) UpdateServiceCAS(service *model.Service, needUpdateOwner bool, expectRevision string) error {
+	defer func() {
+		if needUpdateOwner {
+			c.services.purge()
+			c.servicesByID.purge()
+			return
+		}
+		c.invalidateService(service.ID, service.Name, service.Namespace)
+	}()
+	return NewRevisionStore(c.store).UpdateServiceCAS(service, needUpdateOwner, expectRevision)
+}
+
+// UpdateRoutingConfigCAS 实现 RevisionStore
+func (c *cachingStore) UpdateRoutingConfigCAS(conf *model.RoutingConfig, expectRevision string) error {
+	return NewRevisionStore(c.store).UpdateRoutingConfigCAS(conf, expectRevision)
+}
+
+// UpdateRoutingConfigV2CAS 实现 RevisionStore
+func (c *cachingStore) UpdateRoutingConfigV2CAS(conf *model.RouterConfig, expectRevision string) error {
+	return NewRevisionStore(c.store).UpdateRoutingConfigV2CAS(conf, expectRevision)
+}
+
+// UpdateRateLimitCAS 实现 RevisionStore
+func (c *cachingStore) UpdateRateLimitCAS(limit *model.RateLimit, expectRevision string) error {
+	return NewRevisionStore(c.store).UpdateRateLimitCAS(limit, expectRevision)
+}
+
+// UpdateCircuitBreakerRuleCAS 实现 RevisionStore
+func (c *cachingStore) UpdateCircuitBreakerRuleCAS(cbRule *model.CircuitBreakerRule, expectRevision string) error {
+	return NewRevisionStore(c.store).UpdateCircuitBreakerRuleCAS(cbRule, expectRevision)
+}
+
+// UpdateFaultDetectRuleCAS 实现 RevisionStore
+func (c *cachingStore) UpdateFaultDetectRuleCAS(conf *model.FaultDetectRule, expectRevision string) error {
+	return NewRevisionStore(c.store).UpdateFaultDetectRuleCAS(conf, expectRevision)
+}
+
+// UpdateStrategyCAS 实现 RevisionStore
+func (c *cachingStore) UpdateStrategyCAS(strategy *model.ModifyStrategyDetail, expectRevision string) error {
+	defer c.strategies.remove(strategy.ID)
+	return NewRevisionStore(c.store).UpdateStrategyCAS(strategy, expectRevision)
+}
+
+// PurgeDeleted 实现 RetentionStore，被删除的数据无法得知，失效对应类型的全部缓存
+func (c *cachingStore) PurgeDeleted(kind RetentionKind, timeout time.Duration, batchSize uint32) (uint32, error) {
+	defer c.purgeKind(kind)
+	return NewRetentionStore(c.store).PurgeDeleted(kind, timeout, batchSize)
+}
+
+// SearchServices 实现 QueryStore
+func (c *cachingStore) SearchServices(q *Query) (uint32, []*model.Service, error) {
+	return NewQueryStore(c.store).SearchServices(q)
+}
+
+// SearchInstances 实现 QueryStore
+func (c *cachingStore) SearchInstances(q *Query) (uint32, []*model.Instance, error) {
+	return NewQueryStore(c.store).SearchInstances(q)
+}
+
+// SearchRoutingConfigs 实现 QueryStore
+func (c *cachingStore) SearchRoutingConfigs(q *Query) (uint32, []*model.RoutingConfig, error) {
+	return NewQueryStore(c.store).SearchRoutingConfigs(q)
+}
+
+// SearchFaultDetectRules 实现 QueryStore
+func (c *cachingStore) SearchFaultDetectRules(q *Query) (uint32, []*model.FaultDetectRule, error) {
+	return NewQueryStore(c.store).SearchFaultDetectRules(q)
+}
+
+// SearchConfigFiles 实现 QueryStore
+func (c *cachingStore) SearchConfigFiles(q *Query) (uint32, []*model.ConfigFile, error) {
+	return NewQueryStore(c.store).SearchConfigFiles(q)
+}
+
+// SearchUsers 实现 QueryStore
+func (c *cachingStore) SearchUsers(q *Query) (uint32, []*model.User, error) {
+	return NewQueryStore(c.store).SearchUsers(q)
+}
+
+// SearchStrategies 实现 QueryStore
+func (c *cachingStore) SearchStrategies(q *Query) (uint32, []*model.StrategyDetail, error) {
+	return NewQueryStore(c.store).SearchStrategies(q)
+}
+
+// GetNamespacesPage 实现 CursorStore
+func (c *cachingStore) GetNamespacesPage(filter map[string][]string, token PageToken, limit uint32) (*Page[*model.Namespace], error) {
+	return NewCursorStore(c.store).GetNamespacesPage(filter, token, limit)
+}
+
+// GetServicesPage 实现 CursorStore
+func (c *cachingStore) GetServicesPage(serviceFilters map[string]string, serviceMetas map[string]string,
+	instanceFilters *model.InstanceArgs, token PageToken, limit uint32) (*Page[*model.Service], error) {
+	return NewCursorStore(c.store).GetServicesPage(serviceFilters, serviceMetas, instanceFilters, token, limit)
+}
+
+// GetServiceAliasesPage 实现 CursorStore
+func (c *cachingStore) GetServiceAliasesPage(filter map[string]string, token PageToken, limit uint32) (*Page[*model.ServiceAlias], error) {
+	return NewCursorStore(c.store).GetServiceAliasesPage(filter, token, limit)
+}
+
+// GetExpandInstancesPage 实现 CursorStore
+func (c *cachingStore) GetExpandInstancesPage(filter map[string]string, metaFilter map[string]string, token PageToken,
+	limit uint32) (*Page[*model.Instance], error) {
+	return NewCursorStore(c.store).GetExpandInstancesPage(filter, metaFilter, token, limit)
+}
+
+// GetRoutingConfigsPage 实现 CursorStore
+func (c *cachingStore) GetRoutingConfigsPage(filter map[string]string, token PageToken, limit uint32) (*Page[*model.RoutingConfig], error) {
+	return NewCursorStore(c.store).GetRoutingConfigsPage(filter, token, limit)
+}
+
+// GetExtendRateLimitsPage 实现 CursorStore
+func (c *cachingStore) GetExtendRateLimitsPage(query map[string]string, token PageToken, limit uint32) (*Page[*model.RateLimit], error) {
+	return NewCursorStore(c.store).GetExtendRateLimitsPage(query, token, limit)
+}
+
+// GetCircuitBreakerRulesPage 实现 CursorStore
+func (c *cachingStore) GetCircuitBreakerRulesPage(filter map[string]string, token PageToken,
+	limit uint32) (*Page[*model.CircuitBreakerRule], error) {
+	return NewCursorStore(c.store).GetCircuitBreakerRulesPage(filter, token, limit)
+}
+
+// GetFaultDetectRulesPage 实现 CursorStore
+func (c *cachingStore) GetFaultDetectRulesPage(filter map[string]string, token PageToken,
+	limit uint32) (*Page[*model.FaultDetectRule], error) {
+	return NewCursorStore(c.store).GetFaultDetectRulesPage(filter, token, limit)
+}
+
+// QueryConfigFilesPage 实现 CursorStore
+func (c *cachingStore) QueryConfigFilesPage(filter map[string]string, token PageToken, limit uint32) (*Page[*model.ConfigFile], error) {
+	return NewCursorStore(c.store).QueryConfigFilesPage(filter, token, limit)
+}
+
+// QueryConfigFileReleaseHistoriesPage 实现 CursorStore
+func (c *cachingStore) QueryConfigFileReleaseHistoriesPage(filter map[string]string, token PageToken,
+	limit uint32) (*Page[*model.ConfigFileReleaseHistory], error) {
+	return NewCursorStore(c.store).QueryConfigFileReleaseHistoriesPage(filter, token, limit)
+}
+
+// GetUsersPage 实现 CursorStore
+func (c *cachingStore) GetUsersPage(filters map[string]string, token PageToken, limit uint32) (*Page[*model.User], error) {
+	return NewCursorStore(c.store).GetUsersPage(filters, token, limit)
+}
+
+// GetGroupsPage 实现 CursorStore
+func (c *cachingStore) GetGroupsPage(filters map[string]string, token PageToken, limit uint32) (*Page[*model.UserGroup], error) {
+	return NewCursorStore(c.store).GetGroupsPage(filters, token, limit)
+}
+
+// GetStrategiesPage 实现 CursorStore
+func (c *cachingStore) GetStrategiesPage(filters map[string]string, token PageToken, limit uint32) (*Page[*model.StrategyDetail], error) {
+	return NewCursorStore(c.store).GetStrategiesPage(filters, token, limit)
+}
+
+// RangeMoreNamespaces 实现 StreamStore
+func (c *cachingStore) RangeMoreNamespaces(mtime time.Time, batchSize int, handler BatchHandler[*model.Namespace]) error {
+	return NewStreamStore(c.store).RangeMoreNamespaces(mtime, batchSize, handler)
+}
+
+// RangeMoreServices 实现 StreamStore，与对应的 GetMore* 方法一样根据返回的变更数据失效缓存
+func (c *cachingStore) RangeMoreServices(mtime time.Time, firstUpdate bool, disableBusiness bool, needMeta bool,
+	batchSize int, handler BatchHandler[*model.Service]) error {
+	if c.feedInvalidation {
+		next := handler
+		handler = func(items []*model.Service) (bool, error) {
+			for i := range items {
+				c.invalidateService(items[i].ID, items[i].Name, items[i].Namespace)
+			}
+			return next(items)
+		}
+	}
+	return NewStreamStore(c.store).RangeMoreServices(mtime, firstUpdate, disableBusiness, needMeta, batchSize, handler)
+}
+
+// RangeMoreInstances 实现 StreamStore，与对应的 GetMore* 方法一样根据返回的变更数据失效缓存
+func (c *cachingStore) RangeMoreInstances(tx Tx, mtime time.Time, firstUpdate bool, needMeta bool, serviceID []string,
+	batchSize int, handler BatchHandler[*model.Instance]) error {
+	if c.feedInvalidation {
+		next := handler
+		handler = func(items []*model.Instance) (bool, error) {
+			for i := range items {
+				c.instances.remove(items[i].Proto.GetId().GetValue())
+			}
+			return next(items)
+		}
+	}
+	return NewStreamStore(c.store).RangeMoreInstances(innerTx(tx), mtime, firstUpdate, needMeta, serviceID, batchSize, handler)
+}
+
+// RangeMoreClients 实现 StreamStore
+func (c *cachingStore) RangeMoreClients(mtime time.Time, firstUpdate bool, batchSize int, handler BatchHandler[*model.Client]) error {
+	return NewStreamStore(c.store).RangeMoreClients(mtime, firstUpdate, batchSize, handler)
+}
+
+// RangeMoreServiceContracts 实现 StreamStore
+func (c *cachingStore) RangeMoreServiceContracts(firstUpdate bool, mtime time.Time, batchSize int,
+	handler BatchHandler[*model.ServiceContract]) error {
+	return NewStreamStore(c.store).RangeMoreServiceContracts(firstUpdate, mtime, batchSize, handler)
+}
+
+// RangeMoreGrayResources 实现 StreamStore
+func (c *cachingStore) RangeMoreGrayResources(firstUpdate bool, mtime time.Time, batchSize int,
+	handler BatchHandler[*model.GrayResource]) error {
+	return NewStreamStore(c.store).RangeMoreGrayResources(firstUpdate, mtime, batchSize, handler)
+}
+
+// RangeRoutingConfigsForCache 实现 StreamStore
+func (c *cachingStore) RangeRoutingConfigsForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler BatchHandler[*model.RoutingConfig]) error {
+	return NewStreamStore(c.store).RangeRoutingConfigsForCache(mtime, firstUpdate, batchSize, handler)
+}
+
+// RangeRoutingConfigsV2ForCache 实现 StreamStore
+func (c *cachingStore) RangeRoutingConfigsV2ForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler BatchHandler[*model.RouterConfig]) error {
+	return NewStreamStore(c.store).RangeRoutingConfigsV2ForCache(mtime, firstUpdate, batchSize, handler)
+}
+
+// RangeRateLimitsForCache 实现 StreamStore
+func (c *cachingStore) RangeRateLimitsForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler BatchHandler[*model.RateLimit]) error {
+	return NewStreamStore(c.store).RangeRateLimitsForCache(mtime, firstUpdate, batchSize, handler)
+}
+
+// RangeCircuitBreakerRulesForCache 实现 StreamStore
+func (c *cachingStore) RangeCircuitBreakerRulesForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler BatchHandler[*model.CircuitBreakerRule]) error {
+	return NewStreamStore(c.store).RangeCircuitBreakerRulesForCache(mtime, firstUpdate, batchSize, handler)
+}
+
+// RangeFaultDetectRulesForCache 实现 StreamStore
+func (c *cachingStore) RangeFaultDetectRulesForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler BatchHandler[*model.FaultDetectRule]) error {
+	return NewStreamStore(c.store).RangeFaultDetectRulesForCache(mtime, firstUpdate, batchSize, handler)
+}
+
+// RangeMoreConfigGroup 实现 StreamStore
+func (c *cachingStore) RangeMoreConfigGroup(firstUpdate bool, mtime time.Time, batchSize int,
+	handler BatchHandler[*model.ConfigFileGroup]) error {
+	return NewStreamStore(c.store).RangeMoreConfigGroup(firstUpdate, mtime, batchSize, handler)
+}
+
+// RangeMoreReleaseFile 实现 StreamStore
+func (c *cachingStore) RangeMoreReleaseFile(firstUpdate bool, modifyTime time.Time, batchSize int,
+	handler BatchHandler[*model.ConfigFileRelease]) error {
+	return NewStreamStore(c.store).RangeMoreReleaseFile(firstUpdate, modifyTime, batchSize, handler)
+}
+
+// RangeUsersForCache 实现 StreamStore，与对应的 GetMore* 方法一样根据返回的变更数据失效缓存
+func (c *cachingStore) RangeUsersForCache(mtime time.Time, firstUpdate bool, batchSize int, handler BatchHandler[*model.User]) error {
+	if c.feedInvalidation {
+		next := handler
+		handler = func(items []*model.User) (bool, error) {
+			for i := range items {
+				c.users.remove(items[i].ID)
+			}
+			return next(items)
+		}
+	}
+	return NewStreamStore(c.store).RangeUsersForCache(mtime, firstUpdate, batchSize, handler)
+}
+
+// RangeGroupsForCache 实现 StreamStore
+func (c *cachingStore) RangeGroupsForCache(mtime time.Time, firstUpdate bool, batchSize int, handler BatchHandler[*model.UserGroup]) error {
+	return NewStreamStore(c.store).RangeGroupsForCache(mtime, firstUpdate, batchSize, handler)
+}
+
+// RangeStrategyDetailsForCache 实现 StreamStore，与对应的 GetMore* 方法一样根据返回的变更数据失效缓存
+func (c *cachingStore) RangeStrategyDetailsForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler BatchHandler[*model.StrategyDetail]) error {
+	if c.feedInvalidation {
+		next := handler
+		handler = func(items []*model.StrategyDetail) (bool, error) {
+			for i := range items {
+				c.strategies.remove(items[i].ID)
+			}
+			return next(items)
+		}
+	}
+	return NewStreamStore(c.store).RangeStrategyDetailsForCache(mtime, firstUpdate, batchSize, handler)
+}
+
+// Watch 实现 WatchableStore
+func (c *cachingStore) Watch(ctx context.Context, kind ResourceKind, cursor Cursor) (Watcher, error) {
+	return Watch(ctx, c.store, kind, cursor)
+}
+
+// purgeKind 失效 kind 类型资源的全部缓存
+func (c *cachingStore) purgeKind(kind RetentionKind) {
+	switch kind {
+	case RetentionService, RetentionServiceAlias:
+		c.services.purge()
+		c.servicesByID.purge()
+	case RetentionConfigFile:
+		c.configFiles.purge()
+	case RetentionUser:
+		c.users.purge()
+	case RetentionStrategy:
+		c.strategies.purge()
+	}
+}
+
+func (c *cachingStore) invalidateStrategyResources(resources []model.StrategyResource) {
+	for i := range resources {
+		c.strategies.remove(resources[i].StrategyID)
+	}
+}
+
+// lruCache 带有容量上限以及过期时间的 LRU 缓存
+type lruCache[V any] struct {
+	lock     sync.Mutex
+	capacity int
+	items    map[string]*list.Element
+	order    *list.List
+	// gen 每次失效缓存时递增，用于丢弃失效之前开始查询的结果
+	gen uint64
+
+	hits      uint64
+	misses    uint64
+	evictions uint64
+}
+
+type lruEntry[V any] struct {
+	key      string
+	value    V
+	expireAt time.Time
+}
+
+func newLRUCache[V any](capacity int) *lruCache[V] {
+	return &lruCache[V]{
+		capacity: capacity,
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+func (l *lruCache[V]) get(key string) (V, bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	var zero V
+	elem, ok := l.items[key]
+	if !ok {
+		l.misses++
+		return zero, false
+	}
+	entry := elem.Value.(*lruEntry[V])
+	if time.Now().After(entry.expireAt) {
+		l.order.Remove(elem)
+		delete(l.items, key)
+		l.misses++
+		return zero, false
+	}
+	l.order.MoveToFront(elem)
+	l.hits++
+	return entry.value, true
+}
+
+func (l *lruCache[V]) generation() uint64 {
+	return atomic.LoadUint64(&l.gen)
+}
+
+// add 缓存查询结果，generation 为开始查询时的版本，期间缓存被失效过时不缓存该结果
+func (l *lruCache[V]) add(key string, value V, ttl time.Duration, generation uint64) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if atomic.LoadUint64(&l.gen) != generation {
+		return
+	}
+	entry := &lruEntry[V]{key: key, value: value, expireAt: time.Now().Add(ttl)}
+	if elem, ok := l.items[key]; ok {
+		elem.Value = entry
+		l.order.MoveToFront(elem)
+		return
+	}
+	l.items[key] = l.order.PushFront(entry)
+	for l.order.Len() > l.capacity {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.items, oldest.Value.(*lruEntry[V]).key)
+		l.evictions++
+	}
+}
+
+func (l *lruCache[V]) remove(key string) (V, bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	atomic.AddUint64(&l.gen, 1)
+	var zero V
+	elem, ok := l.items[key]
+	if !ok {
+		return zero, false
+	}
+	l.order.Remove(elem)
+	delete(l.items, key)
+	return elem.Value.(*lruEntry[V]).value, true
+}
+
+// removeIf 删除满足条件的条目，需要遍历全部条目，只用于无法通过 key 定位的失效
+func (l *lruCache[V]) removeIf(pred func(value V) bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	atomic.AddUint64(&l.gen, 1)
+	for key, elem := range l.items {
+		if pred(elem.Value.(*lruEntry[V]).value) {
+			l.order.Remove(elem)
+			delete(l.items, key)
+		}
+	}
+}
+
+func (l *lruCache[V]) purge() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	atomic.AddUint64(&l.gen, 1)
+	l.items = make(map[string]*list.Element)
+	l.order.Init()
+}
+
+func (l *lruCache[V]) stats() CacheStats {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return CacheStats{Hits: l.hits, Misses: l.misses, Evictions: l.evictions, Size: l.order.Len()}
+}
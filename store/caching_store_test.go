@@ -0,0 +1,98 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package store_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/memory"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+func TestCachingStoreInvalidation(t *testing.T) {
+	inner := memory.New()
+	c := store.NewCachingStore(inner, store.WithCacheTTL(time.Hour))
+	addNamespace(t, c, "ns")
+	addService(t, c, "svc", "svc", "ns")
+
+	for i := 0; i < 2; i++ {
+		if svc, err := c.GetService("svc", "ns"); err != nil || svc == nil {
+			t.Fatalf("get service: %v %v", svc, err)
+		}
+	}
+	if stats := c.CacheStats()["GetService"]; stats.Hits != 1 || stats.Misses != 1 || stats.Size != 1 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+
+	// 绕过缓存的写入在 TTL 内不可见
+	if err := inner.UpdateService(&model.Service{ID: "svc", Name: "svc", Namespace: "ns", Comment: "v2"}, false); err != nil {
+		t.Fatal(err)
+	}
+	if svc, _ := c.GetService("svc", "ns"); svc.Comment != "v1" {
+		t.Fatalf("expect cached comment v1, got %s", svc.Comment)
+	}
+	// 通过缓存按照 ID 写入时，同时失效按照名字缓存的数据
+	if err := c.UpdateServiceToken("svc", "token", "revision"); err != nil {
+		t.Fatal(err)
+	}
+	if svc, _ := c.GetService("svc", "ns"); svc.Comment != "v2" {
+		t.Fatalf("expect comment v2 after invalidation, got %s", svc.Comment)
+	}
+}
+
+func TestCachingStoreCapacity(t *testing.T) {
+	c := store.NewCachingStore(memory.New(), store.WithCacheCapacity(1))
+	addNamespace(t, c, "ns")
+	addService(t, c, "a", "a", "ns")
+	addService(t, c, "b", "b", "ns")
+	for _, name := range []string{"a", "b", "a"} {
+		if _, err := c.GetService(name, "ns"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if stats := c.CacheStats()["GetService"]; stats.Evictions != 2 || stats.Size != 1 || stats.Hits != 0 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestCachingStoreTxInvalidation(t *testing.T) {
+	c := store.NewCachingStore(memory.New())
+	addNamespace(t, c, "ns")
+	addService(t, c, "svc", "svc", "ns")
+
+	tx, err := c.StartTx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	updated := &model.Service{ID: "svc", Name: "svc", Namespace: "ns", Comment: "v2"}
+	if err := store.NewNamingTxStore(c).UpdateServiceTx(tx, updated, false); err != nil {
+		t.Fatal(err)
+	}
+	// 事务提交之前读取并缓存，提交之后需要失效
+	if _, err := c.GetService("svc", "ns"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if svc, _ := c.GetService("svc", "ns"); svc.Comment != "v2" {
+		t.Fatalf("expect comment v2 after commit, got %s", svc.Comment)
+	}
+}
@@ -18,7 +18,10 @@
 package store
 
 import (
+	"errors"
+	"fmt"
 	"strings"
+	"sync"
 )
 
 // StatusCode 存储层的状态码
@@ -67,33 +70,98 @@ const (
 	// 非法的用户ID列表
 	InvalidUserIDSlice
 	NotFoundResource
+	// 临时错误，比如连接中断、超时等，重试后可能成功
+	TransientErr
+	// 持久化的 schema 版本与当前代码要求的版本不一致，需要先执行迁移
+	SchemaVersionMismatch
+	// 资源不属于当前租户，或者操作会跨越租户
+	TenantMismatchErr
+	// 存储插件没有实现该操作所需的可选接口
+	NotSupportedErr
 )
 
-// Error 普通error转StatusError
+// Error 实现error接口，使得状态码可以作为 errors.Is 的比较目标，例如 errors.Is(err, store.DeadlockErr)
+func (c StatusCode) Error() string {
+	return fmt.Sprintf("store status code %d", uint32(c))
+}
+
+// Retryable 该状态码对应的错误是否可以通过重试解决
+func (c StatusCode) Retryable() bool {
+	return c == DeadlockErr || c == TransientErr
+}
+
+// Conflict 该状态码是否表示数据冲突，比如主键重复、并发更新
+func (c StatusCode) Conflict() bool {
+	return c == DataConflictErr || c == DuplicateEntryErr
+}
+
+// ErrorClassifier 将存储层原生的错误转换为状态码，无法识别时返回 false
+type ErrorClassifier func(err error) (StatusCode, bool)
+
+var (
+	classifierLock  sync.RWMutex
+	classifierNames = make(map[string]struct{})
+	classifiers     []ErrorClassifier
+)
+
+// RegisterErrorClassifier 注册错误分类器，Error 按照注册顺序使用分类器识别错误，
+// 都无法识别时再按照 MySQL 的错误信息进行匹配；可以在运行时与 Error 并发调用，
+// 例如存储插件在 Initialize 中注册，注册之前产生的错误不会被该分类器识别
+func RegisterErrorClassifier(name string, classifier ErrorClassifier) {
+	classifierLock.Lock()
+	defer classifierLock.Unlock()
+
+	if _, exist := classifierNames[name]; exist {
+		panic(fmt.Sprintf("existed error classifier: name=%v", name))
+	}
+	classifierNames[name] = struct{}{}
+	classifiers = append(classifiers, classifier)
+}
+
+// Error 普通error转StatusError，转换后的StatusError包装了原始的error
 func Error(err error) error {
 	if err == nil {
 		return nil
 	}
 
 	// 已经是StatusError了，不再转换
-	if _, ok := err.(*StatusError); ok {
+	var se *StatusError
+	if errors.As(err, &se) {
 		return err
 	}
 
-	s := &StatusError{message: err.Error()}
-	if strings.Contains(s.message, "Data too long") {
-		s.code = OutOfRangeErr
-	} else if strings.Contains(s.message, "Duplicate entry") {
-		s.code = DuplicateEntryErr
-	} else if strings.Contains(s.message, "a foreign key constraint fails") {
-		s.code = ForeignKeyErr
-	} else if strings.Contains(s.message, "Deadlock") {
-		s.code = DeadlockErr
-	} else {
-		s.code = Unknown
+	return &StatusError{code: classify(err), message: err.Error(), cause: err}
+}
+
+func classify(err error) StatusCode {
+	// 分类器只会追加，复制切片之后在锁外调用，分类器中可以再调用 Error
+	classifierLock.RLock()
+	registered := classifiers
+	classifierLock.RUnlock()
+
+	for _, classifier := range registered {
+		if code, ok := classifier(err); ok {
+			return code
+		}
 	}
+	return classifyMySQL(err)
+}
 
-	return s
+// classifyMySQL 根据 MySQL 的错误信息识别错误
+func classifyMySQL(err error) StatusCode {
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "Data too long"):
+		return OutOfRangeErr
+	case strings.Contains(message, "Duplicate entry"):
+		return DuplicateEntryErr
+	case strings.Contains(message, "a foreign key constraint fails"):
+		return ForeignKeyErr
+	case strings.Contains(message, "Deadlock"):
+		return DeadlockErr
+	default:
+		return Unknown
+	}
 }
 
 // NewStatusError 根据code和message创建StatusError
@@ -104,24 +172,44 @@ func NewStatusError(code StatusCode, message string) error {
 	}
 }
 
-// Code 根据error接口，获取状态码
+// WrapStatusError 根据code和message创建StatusError，并包装原始的error
+func WrapStatusError(code StatusCode, cause error, message string) error {
+	return &StatusError{
+		code:    code,
+		message: message,
+		cause:   cause,
+	}
+}
+
+// Code 根据error接口，获取状态码，会沿着 Unwrap 链查找StatusError
 func Code(err error) StatusCode {
 	if err == nil {
 		return Ok
 	}
 
-	se, ok := err.(*StatusError)
-	if ok {
+	var se *StatusError
+	if errors.As(err, &se) {
 		return se.code
 	}
 
 	return Unknown
 }
 
+// IsRetryable 判断错误是否可以通过重试解决
+func IsRetryable(err error) bool {
+	return err != nil && Code(err).Retryable()
+}
+
+// IsConflict 判断错误是否为数据冲突
+func IsConflict(err error) bool {
+	return err != nil && Code(err).Conflict()
+}
+
 // StatusError 包括了状态码的error接口
 type StatusError struct {
 	code    StatusCode
 	message string
+	cause   error
 }
 
 // Error 实现error接口
@@ -129,6 +217,31 @@ func (s *StatusError) Error() string {
 	if s == nil {
 		return ""
 	}
+	if s.message == "" && s.cause != nil {
+		return s.cause.Error()
+	}
 
 	return s.message
 }
+
+// Code 获取状态码
+func (s *StatusError) Code() StatusCode {
+	return s.code
+}
+
+// Unwrap 返回被包装的原始error
+func (s *StatusError) Unwrap() error {
+	return s.cause
+}
+
+// Is 支持 errors.Is 与状态码或者相同状态码的StatusError比较
+func (s *StatusError) Is(target error) bool {
+	switch t := target.(type) {
+	case StatusCode:
+		return s.code == t
+	case *StatusError:
+		return t != nil && s.code == t.code
+	default:
+		return false
+	}
+}
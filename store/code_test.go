@@ -0,0 +1,100 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package store_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+)
+
+func TestStatusErrorWrapping(t *testing.T) {
+	cause := errors.New("connection reset")
+	err := fmt.Errorf("query: %w", store.WrapStatusError(store.TransientErr, cause, ""))
+	if !errors.Is(err, cause) {
+		t.Fatal("cause is not reachable through errors.Is")
+	}
+	if !errors.Is(err, store.TransientErr) || errors.Is(err, store.DeadlockErr) {
+		t.Fatal("errors.Is should compare status codes")
+	}
+	if !errors.Is(err, store.NewStatusError(store.TransientErr, "other message")) {
+		t.Fatal("errors.Is should match a StatusError with the same code")
+	}
+	if store.Code(err) != store.TransientErr || !store.IsRetryable(err) || store.IsConflict(err) {
+		t.Fatalf("unexpected classification of %v", err)
+	}
+	if err.Error() != "query: connection reset" {
+		t.Fatalf("empty message should fall back to the cause: %q", err.Error())
+	}
+	if store.Code(nil) != store.Ok || store.Code(cause) != store.Unknown {
+		t.Fatal("unexpected code for nil or plain errors")
+	}
+}
+
+var errBusy = errors.New("database is busy")
+
+func TestErrorClassifier(t *testing.T) {
+	store.RegisterErrorClassifier("store_test", func(err error) (store.StatusCode, bool) {
+		if errors.Is(err, errBusy) {
+			return store.DeadlockErr, true
+		}
+		return 0, false
+	})
+	err := store.Error(fmt.Errorf("exec: %w", errBusy))
+	if store.Code(err) != store.DeadlockErr || !errors.Is(err, errBusy) {
+		t.Fatalf("registered classifier is not used: %v", err)
+	}
+	if code := store.Code(store.Error(errors.New("Error 1062: Duplicate entry 'a' for key 'PRIMARY'"))); code != store.DuplicateEntryErr {
+		t.Fatalf("MySQL fallback: %d", code)
+	}
+	if code := store.Code(store.Error(errors.New("boom"))); code != store.Unknown {
+		t.Fatalf("unknown error: %d", code)
+	}
+	se := store.NewStatusError(store.NotFoundResource, "missing")
+	if store.Error(se) != se || store.Error(nil) != nil {
+		t.Fatal("StatusError and nil should be returned as is")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("duplicate classifier name should panic")
+		}
+	}()
+	store.RegisterErrorClassifier("store_test", func(error) (store.StatusCode, bool) { return 0, false })
+}
+
+func TestRegisterErrorClassifierConcurrently(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			store.RegisterErrorClassifier(fmt.Sprintf("store_test_%d", i), func(error) (store.StatusCode, bool) {
+				return 0, false
+			})
+		}(i)
+		go func() {
+			defer wg.Done()
+			_ = store.Error(errors.New("boom"))
+		}()
+	}
+	wg.Wait()
+}
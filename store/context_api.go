@@ -0,0 +1,162 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// ContextStore 支持 context 的存储接口，请求的超时、取消以及链路信息可以透传到存储层，
+// 原生支持 context 的存储插件可以直接实现该接口并取消执行中的查询，其余的插件可以通过 NewContextStore 进行适配，
+// 适配后只能提前返回，无法取消底层的查询
+type ContextStore interface {
+	// ServiceStoreContext 服务接口
+	ServiceStoreContext
+	// InstanceStoreContext 实例接口
+	InstanceStoreContext
+	// ConfigFileStoreContext 配置文件接口
+	ConfigFileStoreContext
+	// UserStoreContext 用户接口
+	UserStoreContext
+}
+
+// ServiceStoreContext 支持 context 的服务存储接口
+type ServiceStoreContext interface {
+	// AddService 保存一个服务
+	AddService(ctx context.Context, service *model.Service) error
+	// DeleteService 删除服务
+	DeleteService(ctx context.Context, id string, serviceName string, namespaceName string) error
+	// DeleteServiceAlias 删除服务别名
+	DeleteServiceAlias(ctx context.Context, name string, namespace string) error
+	// UpdateServiceAlias 修改服务别名
+	UpdateServiceAlias(ctx context.Context, alias *model.Service, needUpdateOwner bool) error
+	// UpdateService 更新服务
+	UpdateService(ctx context.Context, service *model.Service, needUpdateOwner bool) error
+	// UpdateServiceToken 更新服务token
+	UpdateServiceToken(ctx context.Context, serviceID string, token string, revision string) error
+	// GetSourceServiceToken 获取源服务的token信息
+	GetSourceServiceToken(ctx context.Context, name string, namespace string) (*model.Service, error)
+	// GetService 根据服务名和命名空间获取服务的详情
+	GetService(ctx context.Context, name string, namespace string) (*model.Service, error)
+	// GetServiceByID 根据服务ID查询服务详情
+	GetServiceByID(ctx context.Context, id string) (*model.Service, error)
+	// GetServices 根据相关条件查询对应服务及数目
+	GetServices(ctx context.Context, serviceFilters map[string]string, serviceMetas map[string]string, instanceFilters *model.InstanceArgs, offset uint32, limit uint32) (uint32, []*model.Service, error)
+	// GetServicesCount 获取所有服务总数
+	GetServicesCount(ctx context.Context) (uint32, error)
+	// GetMoreServices 获取增量services
+	// 此方法用于 cache 增量更新，需要注意 mtime 应为数据库时间戳
+	GetMoreServices(ctx context.Context, mtime time.Time, firstUpdate bool, disableBusiness bool, needMeta bool) (map[string]*model.Service, error)
+	// GetServiceAliases 获取服务别名列表
+	GetServiceAliases(ctx context.Context, filter map[string]string, offset uint32, limit uint32) (uint32, []*model.ServiceAlias, error)
+	// GetSystemServices 获取系统服务
+	GetSystemServices(ctx context.Context) ([]*model.Service, error)
+	// GetServicesBatch 批量获取服务id、负责人等信息
+	GetServicesBatch(ctx context.Context, services []*model.Service) ([]*model.Service, error)
+}
+
+// InstanceStoreContext 支持 context 的实例存储接口
+type InstanceStoreContext interface {
+	// AddInstance 增加一个实例
+	AddInstance(ctx context.Context, instance *model.Instance) error
+	// BatchAddInstances 增加多个实例
+	BatchAddInstances(ctx context.Context, instances []*model.Instance) error
+	// UpdateInstance 更新实例
+	UpdateInstance(ctx context.Context, instance *model.Instance) error
+	// DeleteInstance 删除一个实例，实际是把valid置为false
+	DeleteInstance(ctx context.Context, instanceID string) error
+	// BatchDeleteInstances 批量删除实例，flag=1
+	BatchDeleteInstances(ctx context.Context, ids []interface{}) error
+	// CleanInstance 清空一个实例，真正删除
+	CleanInstance(ctx context.Context, instanceID string) error
+	// BatchGetInstanceIsolate 检查ID是否存在，并且返回存在的ID，以及ID的隔离状态
+	BatchGetInstanceIsolate(ctx context.Context, ids map[string]bool) (map[string]bool, error)
+	// GetInstancesBrief 获取实例关联的token
+	GetInstancesBrief(ctx context.Context, ids map[string]bool) (map[string]*model.Instance, error)
+	// GetInstance 查询一个实例的详情，只返回有效的数据
+	GetInstance(ctx context.Context, instanceID string) (*model.Instance, error)
+	// GetInstancesCount 获取有效的实例总数
+	GetInstancesCount(ctx context.Context) (uint32, error)
+	// GetInstancesCountTx 获取有效的实例总数
+	GetInstancesCountTx(ctx context.Context, tx Tx) (uint32, error)
+	// GetInstancesMainByService 根据服务和Host获取实例（不包括metadata）
+	GetInstancesMainByService(ctx context.Context, serviceID string, host string) ([]*model.Instance, error)
+	// GetExpandInstances 根据过滤条件查看实例详情及对应数目
+	GetExpandInstances(ctx context.Context, filter map[string]string, metaFilter map[string]string, offset uint32, limit uint32) (uint32, []*model.Instance, error)
+	// GetMoreInstances 根据mtime获取增量instances，返回所有store的变更信息
+	// 此方法用于 cache 增量更新，需要注意 mtime 应为数据库时间戳
+	GetMoreInstances(ctx context.Context, tx Tx, mtime time.Time, firstUpdate bool, needMeta bool, serviceID []string) (map[string]*model.Instance, error)
+	// SetInstanceHealthStatus 设置实例的健康状态
+	SetInstanceHealthStatus(ctx context.Context, instanceID string, flag int, revision string) error
+	// BatchSetInstanceHealthStatus 批量设置实例的健康状态
+	BatchSetInstanceHealthStatus(ctx context.Context, ids []interface{}, healthy int, revision string) error
+	// BatchSetInstanceIsolate 批量修改实例的隔离状态
+	BatchSetInstanceIsolate(ctx context.Context, ids []interface{}, isolate int, revision string) error
+	// AppendInstanceMetadata 追加实例 metadata
+	BatchAppendInstanceMetadata(ctx context.Context, requests []*model.InstanceMetadataRequest) error
+	// RemoveInstanceMetadata 删除实例指定的 metadata
+	BatchRemoveInstanceMetadata(ctx context.Context, requests []*model.InstanceMetadataRequest) error
+}
+
+// ConfigFileStoreContext 支持 context 的配置文件存储接口
+type ConfigFileStoreContext interface {
+	// LockConfigFile 加锁配置文件
+	LockConfigFile(ctx context.Context, tx Tx, file *model.ConfigFileKey) (*model.ConfigFile, error)
+	// CreateConfigFileTx 创建配置文件
+	CreateConfigFileTx(ctx context.Context, tx Tx, file *model.ConfigFile) error
+	// GetConfigFile 获取配置文件
+	GetConfigFile(ctx context.Context, namespace string, group string, name string) (*model.ConfigFile, error)
+	// GetConfigFileTx 获取配置文件
+	GetConfigFileTx(ctx context.Context, tx Tx, namespace string, group string, name string) (*model.ConfigFile, error)
+	// QueryConfigFiles 翻页查询配置文件，group、name可为模糊匹配
+	QueryConfigFiles(ctx context.Context, filter map[string]string, offset uint32, limit uint32) (uint32, []*model.ConfigFile, error)
+	// UpdateConfigFileTx 更新配置文件
+	UpdateConfigFileTx(ctx context.Context, tx Tx, file *model.ConfigFile) error
+	// DeleteConfigFileTx 删除配置文件
+	DeleteConfigFileTx(ctx context.Context, tx Tx, namespace string, group string, name string) error
+	// CountConfigFiles 获取一个配置文件组下的文件数量
+	CountConfigFiles(ctx context.Context, namespace string, group string) (uint64, error)
+	// CountConfigFileEachGroup 统计 namespace.group 下的配置文件数量
+	CountConfigFileEachGroup(ctx context.Context) (map[string]map[string]int64, error)
+}
+
+// UserStoreContext 支持 context 的用户存储接口
+type UserStoreContext interface {
+	// AddUser Create a user
+	AddUser(ctx context.Context, user *model.User) error
+	// UpdateUser Update user
+	UpdateUser(ctx context.Context, user *model.User) error
+	// DeleteUser delete users
+	DeleteUser(ctx context.Context, user *model.User) error
+	// GetSubCount Number of getting a child account
+	GetSubCount(ctx context.Context, user *model.User) (uint32, error)
+	// GetUser Obtain user
+	GetUser(ctx context.Context, id string) (*model.User, error)
+	// GetUserByName Get a unique user according to Name + Owner
+	GetUserByName(ctx context.Context, name string, ownerId string) (*model.User, error)
+	// GetUserByIDS Get users according to USER IDS batch
+	GetUserByIds(ctx context.Context, ids []string) ([]*model.User, error)
+	// GetUsers Query user list
+	GetUsers(ctx context.Context, filters map[string]string, offset uint32, limit uint32) (uint32, []*model.User, error)
+	// GetUsersForCache Used to refresh user cache
+	// 此方法用于 cache 增量更新，需要注意 mtime 应为数据库时间戳
+	GetUsersForCache(ctx context.Context, mtime time.Time, firstUpdate bool) ([]*model.User, error)
+}
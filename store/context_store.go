@@ -0,0 +1,527 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// Interceptor 拦截 ContextStore 的每一次调用，可用于创建链路追踪的 span、记录耗时等，
+// method 为调用的方法名，拦截器需要调用 invoke 执行真正的存储操作
+type Interceptor func(ctx context.Context, method string, invoke func(ctx context.Context) error) error
+
+// ContextOption NewContextStore 的可选配置
+type ContextOption func(c *contextStore)
+
+// WithInterceptors 设置调用拦截器，按照设置的顺序由外向内执行
+func WithInterceptors(interceptors ...Interceptor) ContextOption {
+	return func(c *contextStore) {
+		c.interceptors = append(c.interceptors, interceptors...)
+	}
+}
+
+// NewContextStore 将不支持 context 的 Store 适配为 ContextStore，底层的 Store 不感知 ctx，适配器无法取消已经开始的操作。
+// 读操作在 ctx 超时或者取消时立即返回 ctx.Err()，但底层的查询并不会停止，执行查询的 goroutine 会一直运行到查询结束，
+// 查询耗时较长时超时的请求会累积这些 goroutine；ctx 不会结束（ctx.Done() 为 nil）时直接在当前 goroutine 中查询。
+// 在事务中的读操作与写操作一样只在执行前检查 ctx，并在当前 goroutine 中执行完成，避免与调用方继续使用的事务并发。
+// 写操作只在执行前检查 ctx，一旦开始执行就会等待其完成，避免调用方无法确定写操作是否生效
+func NewContextStore(s Store, options ...ContextOption) ContextStore {
+	c := &contextStore{store: s}
+	for i := range options {
+		options[i](c)
+	}
+	return c
+}
+
+var _ ContextStore = (*contextStore)(nil)
+
+// contextStore Store 到 ContextStore 的适配器
+type contextStore struct {
+	store        Store
+	interceptors []Interceptor
+}
+
+// read 执行读操作，ctx 结束时不再等待结果，但不会取消底层的查询
+func (c *contextStore) read(ctx context.Context, method string, call func() error) error {
+	_, err := readValue(ctx, c, method, func() (struct{}, error) {
+		return struct{}{}, call()
+	})
+	return err
+}
+
+// write 执行写操作，只在执行前检查 ctx 是否已经结束
+func (c *contextStore) write(ctx context.Context, method string, call func() error) error {
+	return c.intercept(ctx, method, func(ctx context.Context) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return call()
+	})
+}
+
+func (c *contextStore) intercept(ctx context.Context, method string, invoke func(ctx context.Context) error) error {
+	for i := len(c.interceptors) - 1; i >= 0; i-- {
+		interceptor, next := c.interceptors[i], invoke
+		invoke = func(ctx context.Context) error {
+			return interceptor(ctx, method, next)
+		}
+	}
+	return invoke(ctx)
+}
+
+// readValue 执行读操作，ctx 可能结束时在新的 goroutine 中查询并通过 channel 传递结果，
+// ctx 结束后不再等待，查询仍然会执行到结束，其结果被丢弃
+func readValue[T any](ctx context.Context, c *contextStore, method string, call func() (T, error)) (T, error) {
+	type result struct {
+		value T
+		err   error
+	}
+	var ret T
+	err := c.intercept(ctx, method, func(ctx context.Context) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if ctx.Done() == nil {
+			var err error
+			ret, err = call()
+			return err
+		}
+		done := make(chan result, 1)
+		go func() {
+			value, err := call()
+			done <- result{value: value, err: err}
+		}()
+		select {
+		case r := <-done:
+			ret = r.value
+			return r.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+	return ret, err
+}
+
+// readTxValue 执行在事务中的读操作，tx 为空时与 readValue 相同；
+// 事务不能并发使用，因此只在执行前检查 ctx，并在当前 goroutine 中等待查询结束
+func readTxValue[T any](ctx context.Context, c *contextStore, method string, tx Tx, call func() (T, error)) (T, error) {
+	if tx == nil {
+		return readValue(ctx, c, method, call)
+	}
+	var ret T
+	err := c.intercept(ctx, method, func(ctx context.Context) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var err error
+		ret, err = call()
+		return err
+	})
+	return ret, err
+}
+
+// readPage 执行分页查询的读操作，返回总数以及当前页的数据
+func readPage[T any](ctx context.Context, c *contextStore, method string,
+	call func() (uint32, T, error)) (uint32, T, error) {
+	type page struct {
+		total uint32
+		items T
+	}
+	ret, err := readValue(ctx, c, method, func() (page, error) {
+		total, items, err := call()
+		return page{total: total, items: items}, err
+	})
+	return ret.total, ret.items, err
+}
+
+// writeValue 执行带返回值的写操作，只在执行前检查 ctx 是否已经结束
+func writeValue[T any](ctx context.Context, c *contextStore, method string, call func() (T, error)) (T, error) {
+	var ret T
+	err := c.write(ctx, method, func() (err error) {
+		ret, err = call()
+		return err
+	})
+	return ret, err
+}
+
+// AddService 调用 Store.AddService
+func (c *contextStore) AddService(ctx context.Context, service *model.Service) error {
+	return c.write(ctx, "AddService", func() error {
+		return c.store.AddService(service)
+	})
+}
+
+// DeleteService 调用 Store.DeleteService
+func (c *contextStore) DeleteService(ctx context.Context, id string, serviceName string, namespaceName string) error {
+	return c.write(ctx, "DeleteService", func() error {
+		return c.store.DeleteService(id, serviceName, namespaceName)
+	})
+}
+
+// DeleteServiceAlias 调用 Store.DeleteServiceAlias
+func (c *contextStore) DeleteServiceAlias(ctx context.Context, name string, namespace string) error {
+	return c.write(ctx, "DeleteServiceAlias", func() error {
+		return c.store.DeleteServiceAlias(name, namespace)
+	})
+}
+
+// UpdateServiceAlias 调用 Store.UpdateServiceAlias
+func (c *contextStore) UpdateServiceAlias(ctx context.Context, alias *model.Service, needUpdateOwner bool) error {
+	return c.write(ctx, "UpdateServiceAlias", func() error {
+		return c.store.UpdateServiceAlias(alias, needUpdateOwner)
+	})
+}
+
+// UpdateService 调用 Store.UpdateService
+func (c *contextStore) UpdateService(ctx context.Context, service *model.Service, needUpdateOwner bool) error {
+	return c.write(ctx, "UpdateService", func() error {
+		return c.store.UpdateService(service, needUpdateOwner)
+	})
+}
+
+// UpdateServiceToken 调用 Store.UpdateServiceToken
+func (c *contextStore) UpdateServiceToken(ctx context.Context, serviceID string, token string, revision string) error {
+	return c.write(ctx, "UpdateServiceToken", func() error {
+		return c.store.UpdateServiceToken(serviceID, token, revision)
+	})
+}
+
+// GetSourceServiceToken 调用 Store.GetSourceServiceToken
+func (c *contextStore) GetSourceServiceToken(ctx context.Context, name string, namespace string) (*model.Service, error) {
+	return readValue(ctx, c, "GetSourceServiceToken", func() (*model.Service, error) {
+		return c.store.GetSourceServiceToken(name, namespace)
+	})
+}
+
+// GetService 调用 Store.GetService
+func (c *contextStore) GetService(ctx context.Context, name string, namespace string) (*model.Service, error) {
+	return readValue(ctx, c, "GetService", func() (*model.Service, error) {
+		return c.store.GetService(name, namespace)
+	})
+}
+
+// GetServiceByID 调用 Store.GetServiceByID
+func (c *contextStore) GetServiceByID(ctx context.Context, id string) (*model.Service, error) {
+	return readValue(ctx, c, "GetServiceByID", func() (*model.Service, error) {
+		return c.store.GetServiceByID(id)
+	})
+}
+
+// GetServices 调用 Store.GetServices
+func (c *contextStore) GetServices(ctx context.Context, serviceFilters map[string]string, serviceMetas map[string]string, instanceFilters *model.InstanceArgs, offset uint32, limit uint32) (uint32, []*model.Service, error) {
+	return readPage(ctx, c, "GetServices", func() (uint32, []*model.Service, error) {
+		return c.store.GetServices(serviceFilters, serviceMetas, instanceFilters, offset, limit)
+	})
+}
+
+// GetServicesCount 调用 Store.GetServicesCount
+func (c *contextStore) GetServicesCount(ctx context.Context) (uint32, error) {
+	return readValue(ctx, c, "GetServicesCount", func() (uint32, error) {
+		return c.store.GetServicesCount()
+	})
+}
+
+// GetMoreServices 调用 Store.GetMoreServices
+func (c *contextStore) GetMoreServices(ctx context.Context, mtime time.Time, firstUpdate bool, disableBusiness bool, needMeta bool) (map[string]*model.Service, error) {
+	return readValue(ctx, c, "GetMoreServices", func() (map[string]*model.Service, error) {
+		return c.store.GetMoreServices(mtime, firstUpdate, disableBusiness, needMeta)
+	})
+}
+
+// GetServiceAliases 调用 Store.GetServiceAliases
+func (c *contextStore) GetServiceAliases(ctx context.Context, filter map[string]string, offset uint32, limit uint32) (uint32, []*model.ServiceAlias, error) {
+	return readPage(ctx, c, "GetServiceAliases", func() (uint32, []*model.ServiceAlias, error) {
+		return c.store.GetServiceAliases(filter, offset, limit)
+	})
+}
+
+// GetSystemServices 调用 Store.GetSystemServices
+func (c *contextStore) GetSystemServices(ctx context.Context) ([]*model.Service, error) {
+	return readValue(ctx, c, "GetSystemServices", func() ([]*model.Service, error) {
+		return c.store.GetSystemServices()
+	})
+}
+
+// GetServicesBatch 调用 Store.GetServicesBatch
+func (c *contextStore) GetServicesBatch(ctx context.Context, services []*model.Service) ([]*model.Service, error) {
+	return readValue(ctx, c, "GetServicesBatch", func() ([]*model.Service, error) {
+		return c.store.GetServicesBatch(services)
+	})
+}
+
+// AddInstance 调用 Store.AddInstance
+func (c *contextStore) AddInstance(ctx context.Context, instance *model.Instance) error {
+	return c.write(ctx, "AddInstance", func() error {
+		return c.store.AddInstance(instance)
+	})
+}
+
+// BatchAddInstances 调用 Store.BatchAddInstances
+func (c *contextStore) BatchAddInstances(ctx context.Context, instances []*model.Instance) error {
+	return c.write(ctx, "BatchAddInstances", func() error {
+		return c.store.BatchAddInstances(instances)
+	})
+}
+
+// UpdateInstance 调用 Store.UpdateInstance
+func (c *contextStore) UpdateInstance(ctx context.Context, instance *model.Instance) error {
+	return c.write(ctx, "UpdateInstance", func() error {
+		return c.store.UpdateInstance(instance)
+	})
+}
+
+// DeleteInstance 调用 Store.DeleteInstance
+func (c *contextStore) DeleteInstance(ctx context.Context, instanceID string) error {
+	return c.write(ctx, "DeleteInstance", func() error {
+		return c.store.DeleteInstance(instanceID)
+	})
+}
+
+// BatchDeleteInstances 调用 Store.BatchDeleteInstances
+func (c *contextStore) BatchDeleteInstances(ctx context.Context, ids []interface{}) error {
+	return c.write(ctx, "BatchDeleteInstances", func() error {
+		return c.store.BatchDeleteInstances(ids)
+	})
+}
+
+// CleanInstance 调用 Store.CleanInstance
+func (c *contextStore) CleanInstance(ctx context.Context, instanceID string) error {
+	return c.write(ctx, "CleanInstance", func() error {
+		return c.store.CleanInstance(instanceID)
+	})
+}
+
+// BatchGetInstanceIsolate 调用 Store.BatchGetInstanceIsolate
+func (c *contextStore) BatchGetInstanceIsolate(ctx context.Context, ids map[string]bool) (map[string]bool, error) {
+	return readValue(ctx, c, "BatchGetInstanceIsolate", func() (map[string]bool, error) {
+		return c.store.BatchGetInstanceIsolate(ids)
+	})
+}
+
+// GetInstancesBrief 调用 Store.GetInstancesBrief
+func (c *contextStore) GetInstancesBrief(ctx context.Context, ids map[string]bool) (map[string]*model.Instance, error) {
+	return readValue(ctx, c, "GetInstancesBrief", func() (map[string]*model.Instance, error) {
+		return c.store.GetInstancesBrief(ids)
+	})
+}
+
+// GetInstance 调用 Store.GetInstance
+func (c *contextStore) GetInstance(ctx context.Context, instanceID string) (*model.Instance, error) {
+	return readValue(ctx, c, "GetInstance", func() (*model.Instance, error) {
+		return c.store.GetInstance(instanceID)
+	})
+}
+
+// GetInstancesCount 调用 Store.GetInstancesCount
+func (c *contextStore) GetInstancesCount(ctx context.Context) (uint32, error) {
+	return readValue(ctx, c, "GetInstancesCount", func() (uint32, error) {
+		return c.store.GetInstancesCount()
+	})
+}
+
+// GetInstancesCountTx 调用 Store.GetInstancesCountTx
+func (c *contextStore) GetInstancesCountTx(ctx context.Context, tx Tx) (uint32, error) {
+	return readTxValue(ctx, c, "GetInstancesCountTx", tx, func() (uint32, error) {
+		return c.store.GetInstancesCountTx(tx)
+	})
+}
+
+// GetInstancesMainByService 调用 Store.GetInstancesMainByService
+func (c *contextStore) GetInstancesMainByService(ctx context.Context, serviceID string, host string) ([]*model.Instance, error) {
+	return readValue(ctx, c, "GetInstancesMainByService", func() ([]*model.Instance, error) {
+		return c.store.GetInstancesMainByService(serviceID, host)
+	})
+}
+
+// GetExpandInstances 调用 Store.GetExpandInstances
+func (c *contextStore) GetExpandInstances(ctx context.Context, filter map[string]string, metaFilter map[string]string, offset uint32, limit uint32) (uint32, []*model.Instance, error) {
+	return readPage(ctx, c, "GetExpandInstances", func() (uint32, []*model.Instance, error) {
+		return c.store.GetExpandInstances(filter, metaFilter, offset, limit)
+	})
+}
+
+// GetMoreInstances 调用 Store.GetMoreInstances
+func (c *contextStore) GetMoreInstances(ctx context.Context, tx Tx, mtime time.Time, firstUpdate bool, needMeta bool, serviceID []string) (map[string]*model.Instance, error) {
+	return readTxValue(ctx, c, "GetMoreInstances", tx, func() (map[string]*model.Instance, error) {
+		return c.store.GetMoreInstances(tx, mtime, firstUpdate, needMeta, serviceID)
+	})
+}
+
+// SetInstanceHealthStatus 调用 Store.SetInstanceHealthStatus
+func (c *contextStore) SetInstanceHealthStatus(ctx context.Context, instanceID string, flag int, revision string) error {
+	return c.write(ctx, "SetInstanceHealthStatus", func() error {
+		return c.store.SetInstanceHealthStatus(instanceID, flag, revision)
+	})
+}
+
+// BatchSetInstanceHealthStatus 调用 Store.BatchSetInstanceHealthStatus
+func (c *contextStore) BatchSetInstanceHealthStatus(ctx context.Context, ids []interface{}, healthy int, revision string) error {
+	return c.write(ctx, "BatchSetInstanceHealthStatus", func() error {
+		return c.store.BatchSetInstanceHealthStatus(ids, healthy, revision)
+	})
+}
+
+// BatchSetInstanceIsolate 调用 Store.BatchSetInstanceIsolate
+func (c *contextStore) BatchSetInstanceIsolate(ctx context.Context, ids []interface{}, isolate int, revision string) error {
+	return c.write(ctx, "BatchSetInstanceIsolate", func() error {
+		return c.store.BatchSetInstanceIsolate(ids, isolate, revision)
+	})
+}
+
+// BatchAppendInstanceMetadata 调用 Store.BatchAppendInstanceMetadata
+func (c *contextStore) BatchAppendInstanceMetadata(ctx context.Context, requests []*model.InstanceMetadataRequest) error {
+	return c.write(ctx, "BatchAppendInstanceMetadata", func() error {
+		return c.store.BatchAppendInstanceMetadata(requests)
+	})
+}
+
+// BatchRemoveInstanceMetadata 调用 Store.BatchRemoveInstanceMetadata
+func (c *contextStore) BatchRemoveInstanceMetadata(ctx context.Context, requests []*model.InstanceMetadataRequest) error {
+	return c.write(ctx, "BatchRemoveInstanceMetadata", func() error {
+		return c.store.BatchRemoveInstanceMetadata(requests)
+	})
+}
+
+// LockConfigFile 调用 Store.LockConfigFile
+func (c *contextStore) LockConfigFile(ctx context.Context, tx Tx, file *model.ConfigFileKey) (*model.ConfigFile, error) {
+	return writeValue(ctx, c, "LockConfigFile", func() (*model.ConfigFile, error) {
+		return c.store.LockConfigFile(tx, file)
+	})
+}
+
+// CreateConfigFileTx 调用 Store.CreateConfigFileTx
+func (c *contextStore) CreateConfigFileTx(ctx context.Context, tx Tx, file *model.ConfigFile) error {
+	return c.write(ctx, "CreateConfigFileTx", func() error {
+		return c.store.CreateConfigFileTx(tx, file)
+	})
+}
+
+// GetConfigFile 调用 Store.GetConfigFile
+func (c *contextStore) GetConfigFile(ctx context.Context, namespace string, group string, name string) (*model.ConfigFile, error) {
+	return readValue(ctx, c, "GetConfigFile", func() (*model.ConfigFile, error) {
+		return c.store.GetConfigFile(namespace, group, name)
+	})
+}
+
+// GetConfigFileTx 调用 Store.GetConfigFileTx
+func (c *contextStore) GetConfigFileTx(ctx context.Context, tx Tx, namespace string, group string, name string) (*model.ConfigFile, error) {
+	return readTxValue(ctx, c, "GetConfigFileTx", tx, func() (*model.ConfigFile, error) {
+		return c.store.GetConfigFileTx(tx, namespace, group, name)
+	})
+}
+
+// QueryConfigFiles 调用 Store.QueryConfigFiles
+func (c *contextStore) QueryConfigFiles(ctx context.Context, filter map[string]string, offset uint32, limit uint32) (uint32, []*model.ConfigFile, error) {
+	return readPage(ctx, c, "QueryConfigFiles", func() (uint32, []*model.ConfigFile, error) {
+		return c.store.QueryConfigFiles(filter, offset, limit)
+	})
+}
+
+// UpdateConfigFileTx 调用 Store.UpdateConfigFileTx
+func (c *contextStore) UpdateConfigFileTx(ctx context.Context, tx Tx, file *model.ConfigFile) error {
+	return c.write(ctx, "UpdateConfigFileTx", func() error {
+		return c.store.UpdateConfigFileTx(tx, file)
+	})
+}
+
+// DeleteConfigFileTx 调用 Store.DeleteConfigFileTx
+func (c *contextStore) DeleteConfigFileTx(ctx context.Context, tx Tx, namespace string, group string, name string) error {
+	return c.write(ctx, "DeleteConfigFileTx", func() error {
+		return c.store.DeleteConfigFileTx(tx, namespace, group, name)
+	})
+}
+
+// CountConfigFiles 调用 Store.CountConfigFiles
+func (c *contextStore) CountConfigFiles(ctx context.Context, namespace string, group string) (uint64, error) {
+	return readValue(ctx, c, "CountConfigFiles", func() (uint64, error) {
+		return c.store.CountConfigFiles(namespace, group)
+	})
+}
+
+// CountConfigFileEachGroup 调用 Store.CountConfigFileEachGroup
+func (c *contextStore) CountConfigFileEachGroup(ctx context.Context) (map[string]map[string]int64, error) {
+	return readValue(ctx, c, "CountConfigFileEachGroup", func() (map[string]map[string]int64, error) {
+		return c.store.CountConfigFileEachGroup()
+	})
+}
+
+// AddUser 调用 Store.AddUser
+func (c *contextStore) AddUser(ctx context.Context, user *model.User) error {
+	return c.write(ctx, "AddUser", func() error {
+		return c.store.AddUser(user)
+	})
+}
+
+// UpdateUser 调用 Store.UpdateUser
+func (c *contextStore) UpdateUser(ctx context.Context, user *model.User) error {
+	return c.write(ctx, "UpdateUser", func() error {
+		return c.store.UpdateUser(user)
+	})
+}
+
+// DeleteUser 调用 Store.DeleteUser
+func (c *contextStore) DeleteUser(ctx context.Context, user *model.User) error {
+	return c.write(ctx, "DeleteUser", func() error {
+		return c.store.DeleteUser(user)
+	})
+}
+
+// GetSubCount 调用 Store.GetSubCount
+func (c *contextStore) GetSubCount(ctx context.Context, user *model.User) (uint32, error) {
+	return readValue(ctx, c, "GetSubCount", func() (uint32, error) {
+		return c.store.GetSubCount(user)
+	})
+}
+
+// GetUser 调用 Store.GetUser
+func (c *contextStore) GetUser(ctx context.Context, id string) (*model.User, error) {
+	return readValue(ctx, c, "GetUser", func() (*model.User, error) {
+		return c.store.GetUser(id)
+	})
+}
+
+// GetUserByName 调用 Store.GetUserByName
+func (c *contextStore) GetUserByName(ctx context.Context, name string, ownerId string) (*model.User, error) {
+	return readValue(ctx, c, "GetUserByName", func() (*model.User, error) {
+		return c.store.GetUserByName(name, ownerId)
+	})
+}
+
+// GetUserByIds 调用 Store.GetUserByIds
+func (c *contextStore) GetUserByIds(ctx context.Context, ids []string) ([]*model.User, error) {
+	return readValue(ctx, c, "GetUserByIds", func() ([]*model.User, error) {
+		return c.store.GetUserByIds(ids)
+	})
+}
+
+// GetUsers 调用 Store.GetUsers
+func (c *contextStore) GetUsers(ctx context.Context, filters map[string]string, offset uint32, limit uint32) (uint32, []*model.User, error) {
+	return readPage(ctx, c, "GetUsers", func() (uint32, []*model.User, error) {
+		return c.store.GetUsers(filters, offset, limit)
+	})
+}
+
+// GetUsersForCache 调用 Store.GetUsersForCache
+func (c *contextStore) GetUsersForCache(ctx context.Context, mtime time.Time, firstUpdate bool) ([]*model.User, error) {
+	return readValue(ctx, c, "GetUsersForCache", func() ([]*model.User, error) {
+		return c.store.GetUsersForCache(mtime, firstUpdate)
+	})
+}
@@ -0,0 +1,138 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package store_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/memory"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// blockingStore GetService 以及 GetInstancesCountTx 一直阻塞到 release 被关闭
+type blockingStore struct {
+	store.Store
+	release chan struct{}
+}
+
+func (b *blockingStore) GetService(name string, namespace string) (*model.Service, error) {
+	<-b.release
+	return b.Store.GetService(name, namespace)
+}
+
+func (b *blockingStore) GetInstancesCountTx(tx store.Tx) (uint32, error) {
+	<-b.release
+	return b.Store.GetInstancesCountTx(tx)
+}
+
+func TestContextStoreInterceptors(t *testing.T) {
+	var calls []string
+	record := func(tag string) store.Interceptor {
+		return func(ctx context.Context, method string, invoke func(ctx context.Context) error) error {
+			calls = append(calls, tag+":"+method)
+			return invoke(ctx)
+		}
+	}
+	inner := memory.New()
+	addNamespace(t, inner, "ns")
+	s := store.NewContextStore(inner, store.WithInterceptors(record("outer"), record("inner")))
+
+	svc := &model.Service{ID: "svc", Name: "svc", Namespace: "ns"}
+	if err := s.AddService(context.Background(), svc); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := s.GetService(context.Background(), "svc", "ns"); err != nil || got == nil {
+		t.Fatalf("get service: %v %v", got, err)
+	}
+	expect := []string{"outer:AddService", "inner:AddService", "outer:GetService", "inner:GetService"}
+	if len(calls) != len(expect) {
+		t.Fatalf("expect %v, got %v", expect, calls)
+	}
+	for i := range expect {
+		if calls[i] != expect[i] {
+			t.Fatalf("expect %v, got %v", expect, calls)
+		}
+	}
+}
+
+func TestContextStoreCanceled(t *testing.T) {
+	inner := memory.New()
+	addNamespace(t, inner, "ns")
+	s := store.NewContextStore(inner)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := s.AddService(ctx, &model.Service{ID: "svc", Name: "svc", Namespace: "ns"}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expect context.Canceled, got %v", err)
+	}
+	if svc, _ := inner.GetServiceByID("svc"); svc != nil {
+		t.Fatal("write is executed after ctx is canceled")
+	}
+	if _, err := s.GetService(ctx, "svc", "ns"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expect context.Canceled, got %v", err)
+	}
+}
+
+func TestContextStoreReadTimeout(t *testing.T) {
+	inner := &blockingStore{Store: memory.New(), release: make(chan struct{})}
+	defer close(inner.release)
+	s := store.NewContextStore(inner)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := s.GetService(ctx, "svc", "ns"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expect context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("read does not return when ctx is done, elapsed %v", elapsed)
+	}
+}
+
+func TestContextStoreTxReadIsSynchronous(t *testing.T) {
+	m := memory.New()
+	inner := &blockingStore{Store: m, release: make(chan struct{})}
+	s := store.NewContextStore(inner)
+	tx, err := m.StartReadTx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	time.AfterFunc(50*time.Millisecond, func() {
+		close(inner.release)
+	})
+	// 事务中的读操作在当前 goroutine 中执行完成，返回之后事务不会再被使用
+	if _, err := s.GetInstancesCountTx(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+	if ctx.Err() == nil {
+		t.Fatal("tx read returns before the query finishes")
+	}
+	if _, err := s.GetInstancesCountTx(ctx, tx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expect context.DeadlineExceeded before a tx read starts, got %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package store_test
+
+import (
+	"testing"
+
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+func addNamespace(t *testing.T, s store.Store, name string) {
+	t.Helper()
+	if err := s.AddNamespace(&model.Namespace{Name: name}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func addService(t *testing.T, s store.Store, id, name, namespace string) {
+	t.Helper()
+	if err := s.AddService(&model.Service{ID: id, Name: name, Namespace: namespace, Comment: "v1"}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func addInstance(t *testing.T, s store.Store, id, namespace, serviceID string) {
+	t.Helper()
+	err := s.AddInstance(&model.Instance{
+		ServiceID: serviceID,
+		Proto: &apiservice.Instance{
+			Id:        wrapperspb.String(id),
+			Namespace: wrapperspb.String(namespace),
+			Service:   wrapperspb.String(serviceID),
+			Host:      wrapperspb.String("127.0.0.1"),
+			Port:      wrapperspb.UInt32(8080),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,2254 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/observability/statis"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+const (
+	// CallMetricTypeStore 存储层调用的指标类型
+	CallMetricTypeStore statis.CallMetricType = "store"
+	// LabelRows 存储层调用返回的数据行数所在的区间
+	LabelRows = "rows"
+)
+
+// NewInstrumentedStore 包装 Store，通过 statis 上报每个方法的耗时、状态码以及返回的数据行数，
+// 上报的 CallMetric 中 API 为方法名，Protocol 为存储插件的名字，TrafficDirection 为 OUTBOUND；
+// 返回的 Transaction 以及 Tx 上的调用不会被统计
+func NewInstrumentedStore(s Store, reporter statis.Statis) Store {
+	return &instrumentedStore{store: s, reporter: reporter, name: s.Name()}
+}
+
+var (
+	_ Store                = (*instrumentedStore)(nil)
+	_ TxStore              = (*instrumentedStore)(nil)
+	_ NamingTxStore        = (*instrumentedStore)(nil)
+	_ DistributedLockStore = (*instrumentedStore)(nil)
+	_ LeaderWatchStore     = (*instrumentedStore)(nil)
+	_ RevisionStore        = (*instrumentedStore)(nil)
+	_ RetentionStore       = (*instrumentedStore)(nil)
+	_ QueryStore           = (*instrumentedStore)(nil)
+	_ CursorStore          = (*instrumentedStore)(nil)
+	_ StreamStore          = (*instrumentedStore)(nil)
+	_ WatchableStore       = (*instrumentedStore)(nil)
+)
+
+// instrumentedStore 上报调用指标的 Store 装饰器
+type instrumentedStore struct {
+	store    Store
+	reporter statis.Statis
+	name     string
+}
+
+// report 上报一次调用的指标，rows 小于 0 时表示该方法不返回数据行
+func (i *instrumentedStore) report(api string, start time.Time, err error, rows int) {
+	labels := map[string]string{}
+	if rows >= 0 {
+		labels[LabelRows] = rowsBucket(rows)
+	}
+	i.reporter.ReportCallMetrics(statis.CallMetric{
+		Type:             CallMetricTypeStore,
+		API:              api,
+		Protocol:         i.name,
+		Code:             int(Code(err)),
+		Times:            1,
+		Success:          err == nil,
+		Duration:         time.Since(start),
+		Labels:           labels,
+		TrafficDirection: statis.TrafficDirectionOutBound,
+	})
+}
+
+// rowsBucket 将数据行数转换为区间，避免指标的标签基数过大
+func rowsBucket(rows int) string {
+	switch {
+	case rows == 0:
+		return "0"
+	case rows == 1:
+		return "1"
+	case rows <= 10:
+		return "2-10"
+	case rows <= 100:
+		return "11-100"
+	case rows <= 1000:
+		return "101-1000"
+	default:
+		return "1000+"
+	}
+}
+
+func countOf(exist bool) int {
+	if exist {
+		return 1
+	}
+	return 0
+}
+
+// Name 实现 Store
+func (i *instrumentedStore) Name() string {
+	return i.store.Name()
+}
+
+// Initialize 实现 Store
+func (i *instrumentedStore) Initialize(c *Config) error {
+	start := time.Now()
+	err := i.store.Initialize(c)
+	i.report("Initialize", start, err, -1)
+	return err
+}
+
+// Destroy 实现 Store
+func (i *instrumentedStore) Destroy() error {
+	start := time.Now()
+	err := i.store.Destroy()
+	i.report("Destroy", start, err, -1)
+	return err
+}
+
+// CreateTransaction 实现 Store
+func (i *instrumentedStore) CreateTransaction() (Transaction, error) {
+	start := time.Now()
+	ret0, err := i.store.CreateTransaction()
+	i.report("CreateTransaction", start, err, -1)
+	return ret0, err
+}
+
+// StartTx 实现 Store
+func (i *instrumentedStore) StartTx() (Tx, error) {
+	start := time.Now()
+	ret0, err := i.store.StartTx()
+	i.report("StartTx", start, err, -1)
+	return ret0, err
+}
+
+// StartReadTx 实现 Store
+func (i *instrumentedStore) StartReadTx() (Tx, error) {
+	start := time.Now()
+	ret0, err := i.store.StartReadTx()
+	i.report("StartReadTx", start, err, -1)
+	return ret0, err
+}
+
+// BeginTx 实现 TxStore
+func (i *instrumentedStore) BeginTx(opts TxOptions) (UnifiedTx, error) {
+	start := time.Now()
+	ret0, err := NewTxStore(i.store).BeginTx(opts)
+	i.report("BeginTx", start, err, -1)
+	return ret0, err
+}
+
+// AddNamespace 实现 Store
+func (i *instrumentedStore) AddNamespace(namespace *model.Namespace) error {
+	start := time.Now()
+	err := i.store.AddNamespace(namespace)
+	i.report("AddNamespace", start, err, -1)
+	return err
+}
+
+// UpdateNamespace 实现 Store
+func (i *instrumentedStore) UpdateNamespace(namespace *model.Namespace) error {
+	start := time.Now()
+	err := i.store.UpdateNamespace(namespace)
+	i.report("UpdateNamespace", start, err, -1)
+	return err
+}
+
+// UpdateNamespaceToken 实现 Store
+func (i *instrumentedStore) UpdateNamespaceToken(name string, token string) error {
+	start := time.Now()
+	err := i.store.UpdateNamespaceToken(name, token)
+	i.report("UpdateNamespaceToken", start, err, -1)
+	return err
+}
+
+// GetNamespace 实现 Store
+func (i *instrumentedStore) GetNamespace(name string) (*model.Namespace, error) {
+	start := time.Now()
+	ret0, err := i.store.GetNamespace(name)
+	i.report("GetNamespace", start, err, countOf(ret0 != nil))
+	return ret0, err
+}
+
+// GetNamespaces 实现 Store
+func (i *instrumentedStore) GetNamespaces(filter map[string][]string, offset int, limit int) ([]*model.Namespace, uint32, error) {
+	start := time.Now()
+	ret0, ret1, err := i.store.GetNamespaces(filter, offset, limit)
+	i.report("GetNamespaces", start, err, len(ret0))
+	return ret0, ret1, err
+}
+
+// GetMoreNamespaces 实现 Store
+func (i *instrumentedStore) GetMoreNamespaces(mtime time.Time) ([]*model.Namespace, error) {
+	start := time.Now()
+	ret0, err := i.store.GetMoreNamespaces(mtime)
+	i.report("GetMoreNamespaces", start, err, len(ret0))
+	return ret0, err
+}
+
+// AddService 实现 Store
+func (i *instrumentedStore) AddService(service *model.Service) error {
+	start := time.Now()
+	err := i.store.AddService(service)
+	i.report("AddService", start, err, -1)
+	return err
+}
+
+// AddServiceTx 实现 Store
+func (i *instrumentedStore) AddServiceTx(tx Tx, service *model.Service) error {
+	start := time.Now()
+	err := NewNamingTxStore(i.store).AddServiceTx(tx, service)
+	i.report("AddServiceTx", start, err, -1)
+	return err
+}
+
+// DeleteService 实现 Store
+func (i *instrumentedStore) DeleteService(id string, serviceName string, namespaceName string) error {
+	start := time.Now()
+	err := i.store.DeleteService(id, serviceName, namespaceName)
+	i.report("DeleteService", start, err, -1)
+	return err
+}
+
+// DeleteServiceTx 实现 Store
+func (i *instrumentedStore) DeleteServiceTx(tx Tx, id, serviceName, namespaceName string) error {
+	start := time.Now()
+	err := NewNamingTxStore(i.store).DeleteServiceTx(tx, id, serviceName, namespaceName)
+	i.report("DeleteServiceTx", start, err, -1)
+	return err
+}
+
+// DeleteServiceAlias 实现 Store
+func (i *instrumentedStore) DeleteServiceAlias(name string, namespace string) error {
+	start := time.Now()
+	err := i.store.DeleteServiceAlias(name, namespace)
+	i.report("DeleteServiceAlias", start, err, -1)
+	return err
+}
+
+// DeleteServiceAliasTx 实现 Store
+func (i *instrumentedStore) DeleteServiceAliasTx(tx Tx, name string, namespace string) error {
+	start := time.Now()
+	err := NewNamingTxStore(i.store).DeleteServiceAliasTx(tx, name, namespace)
+	i.report("DeleteServiceAliasTx", start, err, -1)
+	return err
+}
+
+// UpdateServiceAlias 实现 Store
+func (i *instrumentedStore) UpdateServiceAlias(alias *model.Service, needUpdateOwner bool) error {
+	start := time.Now()
+	err := i.store.UpdateServiceAlias(alias, needUpdateOwner)
+	i.report("UpdateServiceAlias", start, err, -1)
+	return err
+}
+
+// UpdateServiceAliasTx 实现 Store
+func (i *instrumentedStore) UpdateServiceAliasTx(tx Tx, alias *model.Service, needUpdateOwner bool) error {
+	start := time.Now()
+	err := NewNamingTxStore(i.store).UpdateServiceAliasTx(tx, alias, needUpdateOwner)
+	i.report("UpdateServiceAliasTx", start, err, -1)
+	return err
+}
+
+// UpdateService 实现 Store
+func (i *instrumentedStore) UpdateService(service *model.Service, needUpdateOwner bool) error {
+	start := time.Now()
+	err := i.store.UpdateService(service, needUpdateOwner)
+	i.report("UpdateService", start, err, -1)
+	return err
+}
+
+// UpdateServiceTx 实现 Store
+func (i *instrumentedStore) UpdateServiceTx(tx Tx, service *model.Service, needUpdateOwner bool) error {
+	start := time.Now()
+	err := NewNamingTxStore(i.store).UpdateServiceTx(tx, service, needUpdateOwner)
+	i.report("UpdateServiceTx", start, err, -1)
+	return err
+}
+
+// UpdateServiceToken 实现 Store
+func (i *instrumentedStore) UpdateServiceToken(serviceID string, token string, revision string) error {
+	start := time.Now()
+	err := i.store.UpdateServiceToken(serviceID, token, revision)
+	i.report("UpdateServiceToken", start, err, -1)
+	return err
+}
+
+// UpdateServiceTokenTx 实现 Store
+func (i *instrumentedStore) UpdateServiceTokenTx(tx Tx, serviceID string, token string, revision string) error {
+	start := time.Now()
+	err := NewNamingTxStore(i.store).UpdateServiceTokenTx(tx, serviceID, token, revision)
+	i.report("UpdateServiceTokenTx", start, err, -1)
+	return err
+}
+
+// GetSourceServiceToken 实现 Store
+func (i *instrumentedStore) GetSourceServiceToken(name string, namespace string) (*model.Service, error) {
+	start := time.Now()
+	ret0, err := i.store.GetSourceServiceToken(name, namespace)
+	i.report("GetSourceServiceToken", start, err, countOf(ret0 != nil))
+	return ret0, err
+}
+
+// GetService 实现 Store
+func (i *instrumentedStore) GetService(name string, namespace string) (*model.Service, error) {
+	start := time.Now()
+	ret0, err := i.store.GetService(name, namespace)
+	i.report("GetService", start, err, countOf(ret0 != nil))
+	return ret0, err
+}
+
+// GetServiceByID 实现 Store
+func (i *instrumentedStore) GetServiceByID(id string) (*model.Service, error) {
+	start := time.Now()
+	ret0, err := i.store.GetServiceByID(id)
+	i.report("GetServiceByID", start, err, countOf(ret0 != nil))
+	return ret0, err
+}
+
+// GetServices 实现 Store
+func (i *instrumentedStore) GetServices(serviceFilters map[string]string, serviceMetas map[string]string,
+	instanceFilters *model.InstanceArgs, offset uint32, limit uint32) (uint32, []*model.Service, error) {
+	start := time.Now()
+	ret0, ret1, err := i.store.GetServices(serviceFilters, serviceMetas, instanceFilters, offset, limit)
+	i.report("GetServices", start, err, len(ret1))
+	return ret0, ret1, err
+}
+
+// GetServicesCount 实现 Store
+func (i *instrumentedStore) GetServicesCount() (uint32, error) {
+	start := time.Now()
+	ret0, err := i.store.GetServicesCount()
+	i.report("GetServicesCount", start, err, -1)
+	return ret0, err
+}
+
+// GetMoreServices 实现 Store
+func (i *instrumentedStore) GetMoreServices(mtime time.Time, firstUpdate bool, disableBusiness bool,
+	needMeta bool) (map[string]*model.Service, error) {
+	start := time.Now()
+	ret0, err := i.store.GetMoreServices(mtime, firstUpdate, disableBusiness, needMeta)
+	i.report("GetMoreServices", start, err, len(ret0))
+	return ret0, err
+}
+
+// GetServiceAliases 实现 Store
+func (i *instrumentedStore) GetServiceAliases(filter map[string]string, offset uint32, limit uint32) (uint32,
+	[]*model.ServiceAlias, error) {
+	start := time.Now()
+	ret0, ret1, err := i.store.GetServiceAliases(filter, offset, limit)
+	i.report("GetServiceAliases", start, err, len(ret1))
+	return ret0, ret1, err
+}
+
+// GetSystemServices 实现 Store
+func (i *instrumentedStore) GetSystemServices() ([]*model.Service, error) {
+	start := time.Now()
+	ret0, err := i.store.GetSystemServices()
+	i.report("GetSystemServices", start, err, len(ret0))
+	return ret0, err
+}
+
+// GetServicesBatch 实现 Store
+func (i *instrumentedStore) GetServicesBatch(services []*model.Service) ([]*model.Service, error) {
+	start := time.Now()
+	ret0, err := i.store.GetServicesBatch(services)
+	i.report("GetServicesBatch", start, err, len(ret0))
+	return ret0, err
+}
+
+// AddInstance 实现 Store
+func (i *instrumentedStore) AddInstance(instance *model.Instance) error {
+	start := time.Now()
+	err := i.store.AddInstance(instance)
+	i.report("AddInstance", start, err, -1)
+	return err
+}
+
+// AddInstanceTx 实现 Store
+func (i *instrumentedStore) AddInstanceTx(tx Tx, instance *model.Instance) error {
+	start := time.Now()
+	err := NewNamingTxStore(i.store).AddInstanceTx(tx, instance)
+	i.report("AddInstanceTx", start, err, -1)
+	return err
+}
+
+// BatchAddInstances 实现 Store
+func (i *instrumentedStore) BatchAddInstances(instances []*model.Instance) error {
+	start := time.Now()
+	err := i.store.BatchAddInstances(instances)
+	i.report("BatchAddInstances", start, err, -1)
+	return err
+}
+
+// BatchAddInstancesTx 实现 Store
+func (i *instrumentedStore) BatchAddInstancesTx(tx Tx, instances []*model.Instance) error {
+	start := time.Now()
+	err := NewNamingTxStore(i.store).BatchAddInstancesTx(tx, instances)
+	i.report("BatchAddInstancesTx", start, err, -1)
+	return err
+}
+
+// UpdateInstance 实现 Store
+func (i *instrumentedStore) UpdateInstance(instance *model.Instance) error {
+	start := time.Now()
+	err := i.store.UpdateInstance(instance)
+	i.report("UpdateInstance", start, err, -1)
+	return err
+}
+
+// UpdateInstanceTx 实现 Store
+func (i *instrumentedStore) UpdateInstanceTx(tx Tx, instance *model.Instance) error {
+	start := time.Now()
+	err := NewNamingTxStore(i.store).UpdateInstanceTx(tx, instance)
+	i.report("UpdateInstanceTx", start, err, -1)
+	return err
+}
+
+// DeleteInstance 实现 Store
+func (i *instrumentedStore) DeleteInstance(instanceID string) error {
+	start := time.Now()
+	err := i.store.DeleteInstance(instanceID)
+	i.report("DeleteInstance", start, err, -1)
+	return err
+}
+
+// DeleteInstanceTx 实现 Store
+func (i *instrumentedStore) DeleteInstanceTx(tx Tx, instanceID string) error {
+	start := time.Now()
+	err := NewNamingTxStore(i.store).DeleteInstanceTx(tx, instanceID)
+	i.report("DeleteInstanceTx", start, err, -1)
+	return err
+}
+
+// BatchDeleteInstances 实现 Store
+func (i *instrumentedStore) BatchDeleteInstances(ids []interface{}) error {
+	start := time.Now()
+	err := i.store.BatchDeleteInstances(ids)
+	i.report("BatchDeleteInstances", start, err, -1)
+	return err
+}
+
+// BatchDeleteInstancesTx 实现 Store
+func (i *instrumentedStore) BatchDeleteInstancesTx(tx Tx, ids []interface{}) error {
+	start := time.Now()
+	err := NewNamingTxStore(i.store).BatchDeleteInstancesTx(tx, ids)
+	i.report("BatchDeleteInstancesTx", start, err, -1)
+	return err
+}
+
+// CleanInstance 实现 Store
+func (i *instrumentedStore) CleanInstance(instanceID string) error {
+	start := time.Now()
+	err := i.store.CleanInstance(instanceID)
+	i.report("CleanInstance", start, err, -1)
+	return err
+}
+
+// CleanInstanceTx 实现 Store
+func (i *instrumentedStore) CleanInstanceTx(tx Tx, instanceID string) error {
+	start := time.Now()
+	err := NewNamingTxStore(i.store).CleanInstanceTx(tx, instanceID)
+	i.report("CleanInstanceTx", start, err, -1)
+	return err
+}
+
+// BatchGetInstanceIsolate 实现 Store
+func (i *instrumentedStore) BatchGetInstanceIsolate(ids map[string]bool) (map[string]bool, error) {
+	start := time.Now()
+	ret0, err := i.store.BatchGetInstanceIsolate(ids)
+	i.report("BatchGetInstanceIsolate", start, err, len(ret0))
+	return ret0, err
+}
+
+// GetInstancesBrief 实现 Store
+func (i *instrumentedStore) GetInstancesBrief(ids map[string]bool) (map[string]*model.Instance, error) {
+	start := time.Now()
+	ret0, err := i.store.GetInstancesBrief(ids)
+	i.report("GetInstancesBrief", start, err, len(ret0))
+	return ret0, err
+}
+
+// GetInstance 实现 Store
+func (i *instrumentedStore) GetInstance(instanceID string) (*model.Instance, error) {
+	start := time.Now()
+	ret0, err := i.store.GetInstance(instanceID)
+	i.report("GetInstance", start, err, countOf(ret0 != nil))
+	return ret0, err
+}
+
+// GetInstancesCount 实现 Store
+func (i *instrumentedStore) GetInstancesCount() (uint32, error) {
+	start := time.Now()
+	ret0, err := i.store.GetInstancesCount()
+	i.report("GetInstancesCount", start, err, -1)
+	return ret0, err
+}
+
+// GetInstancesCountTx 实现 Store
+func (i *instrumentedStore) GetInstancesCountTx(tx Tx) (uint32, error) {
+	start := time.Now()
+	ret0, err := i.store.GetInstancesCountTx(tx)
+	i.report("GetInstancesCountTx", start, err, -1)
+	return ret0, err
+}
+
+// GetInstancesMainByService 实现 Store
+func (i *instrumentedStore) GetInstancesMainByService(serviceID string, host string) ([]*model.Instance, error) {
+	start := time.Now()
+	ret0, err := i.store.GetInstancesMainByService(serviceID, host)
+	i.report("GetInstancesMainByService", start, err, len(ret0))
+	return ret0, err
+}
+
+// GetExpandInstances 实现 Store
+func (i *instrumentedStore) GetExpandInstances(filter map[string]string, metaFilter map[string]string, offset uint32,
+	limit uint32) (uint32, []*model.Instance, error) {
+	start := time.Now()
+	ret0, ret1, err := i.store.GetExpandInstances(filter, metaFilter, offset, limit)
+	i.report("GetExpandInstances", start, err, len(ret1))
+	return ret0, ret1, err
+}
+
+// GetMoreInstances 实现 Store
+func (i *instrumentedStore) GetMoreInstances(tx Tx, mtime time.Time, firstUpdate bool, needMeta bool,
+	serviceID []string) (map[string]*model.Instance, error) {
+	start := time.Now()
+	ret0, err := i.store.GetMoreInstances(tx, mtime, firstUpdate, needMeta, serviceID)
+	i.report("GetMoreInstances", start, err, len(ret0))
+	return ret0, err
+}
+
+// SetInstanceHealthStatus 实现 Store
+func (i *instrumentedStore) SetInstanceHealthStatus(instanceID string, flag int, revision string) error {
+	start := time.Now()
+	err := i.store.SetInstanceHealthStatus(instanceID, flag, revision)
+	i.report("SetInstanceHealthStatus", start, err, -1)
+	return err
+}
+
+// SetInstanceHealthStatusTx 实现 Store
+func (i *instrumentedStore) SetInstanceHealthStatusTx(tx Tx, instanceID string, flag int, revision string) error {
+	start := time.Now()
+	err := NewNamingTxStore(i.store).SetInstanceHealthStatusTx(tx, instanceID, flag, revision)
+	i.report("SetInstanceHealthStatusTx", start, err, -1)
+	return err
+}
+
+// BatchSetInstanceHealthStatus 实现 Store
+func (i *instrumentedStore) BatchSetInstanceHealthStatus(ids []interface{}, healthy int, revision string) error {
+	start := time.Now()
+	err := i.store.BatchSetInstanceHealthStatus(ids, healthy, revision)
+	i.report("BatchSetInstanceHealthStatus", start, err, -1)
+	return err
+}
+
+// BatchSetInstanceHealthStatusTx 实现 Store
+func (i *instrumentedStore) BatchSetInstanceHealthStatusTx(tx Tx, ids []interface{}, healthy int, revision string) error {
+	start := time.Now()
+	err := NewNamingTxStore(i.store).BatchSetInstanceHealthStatusTx(tx, ids, healthy, revision)
+	i.report("BatchSetInstanceHealthStatusTx", start, err, -1)
+	return err
+}
+
+// BatchSetInstanceIsolate 实现 Store
+func (i *instrumentedStore) BatchSetInstanceIsolate(ids []interface{}, isolate int, revision string) error {
+	start := time.Now()
+	err := i.store.BatchSetInstanceIsolate(ids, isolate, revision)
+	i.report("BatchSetInstanceIsolate", start, err, -1)
+	return err
+}
+
+// BatchSetInstanceIsolateTx 实现 Store
+func (i *instrumentedStore) BatchSetInstanceIsolateTx(tx Tx, ids []interface{}, isolate int, revision string) error {
+	start := time.Now()
+	err := NewNamingTxStore(i.store).BatchSetInstanceIsolateTx(tx, ids, isolate, revision)
+	i.report("BatchSetInstanceIsolateTx", start, err, -1)
+	return err
+}
+
+// BatchAppendInstanceMetadata 实现 Store
+func (i *instrumentedStore) BatchAppendInstanceMetadata(requests []*model.InstanceMetadataRequest) error {
+	start := time.Now()
+	err := i.store.BatchAppendInstanceMetadata(requests)
+	i.report("BatchAppendInstanceMetadata", start, err, -1)
+	return err
+}
+
+// BatchAppendInstanceMetadataTx 实现 Store
+func (i *instrumentedStore) BatchAppendInstanceMetadataTx(tx Tx, requests []*model.InstanceMetadataRequest) error {
+	start := time.Now()
+	err := NewNamingTxStore(i.store).BatchAppendInstanceMetadataTx(tx, requests)
+	i.report("BatchAppendInstanceMetadataTx", start, err, -1)
+	return err
+}
+
+// BatchRemoveInstanceMetadata 实现 Store
+func (i *instrumentedStore) BatchRemoveInstanceMetadata(requests []*model.InstanceMetadataRequest) error {
+	start := time.Now()
+	err := i.store.BatchRemoveInstanceMetadata(requests)
+	i.report("BatchRemoveInstanceMetadata", start, err, -1)
+	return err
+}
+
+// BatchRemoveInstanceMetadataTx 实现 Store
+func (i *instrumentedStore) BatchRemoveInstanceMetadataTx(tx Tx, requests []*model.InstanceMetadataRequest) error {
+	start := time.Now()
+	err := NewNamingTxStore(i.store).BatchRemoveInstanceMetadataTx(tx, requests)
+	i.report("BatchRemoveInstanceMetadataTx", start, err, -1)
+	return err
+}
+
+// CreateRoutingConfig 实现 Store
+func (i *instrumentedStore) CreateRoutingConfig(conf *model.RoutingConfig) error {
+	start := time.Now()
+	err := i.store.CreateRoutingConfig(conf)
+	i.report("CreateRoutingConfig", start, err, -1)
+	return err
+}
+
+// CreateRoutingConfigTx 实现 Store
+func (i *instrumentedStore) CreateRoutingConfigTx(tx Tx, conf *model.RoutingConfig) error {
+	start := time.Now()
+	err := NewNamingTxStore(i.store).CreateRoutingConfigTx(tx, conf)
+	i.report("CreateRoutingConfigTx", start, err, -1)
+	return err
+}
+
+// UpdateRoutingConfig 实现 Store
+func (i *instrumentedStore) UpdateRoutingConfig(conf *model.RoutingConfig) error {
+	start := time.Now()
+	err := i.store.UpdateRoutingConfig(conf)
+	i.report("UpdateRoutingConfig", start, err, -1)
+	return err
+}
+
+// UpdateRoutingConfigTx 实现 Store
+func (i *instrumentedStore) UpdateRoutingConfigTx(tx Tx, conf *model.RoutingConfig) error {
+	start := time.Now()
+	err := NewNamingTxStore(i.store).UpdateRoutingConfigTx(tx, conf)
+	i.report("UpdateRoutingConfigTx", start, err, -1)
+	return err
+}
+
+// DeleteRoutingConfig 实现 Store
+func (i *instrumentedStore) DeleteRoutingConfig(serviceID string) error {
+	start := time.Now()
+	err := i.store.DeleteRoutingConfig(serviceID)
+	i.report("DeleteRoutingConfig", start, err, -1)
+	return err
+}
+
+// DeleteRoutingConfigTx 实现 Store
+func (i *instrumentedStore) DeleteRoutingConfigTx(tx Tx, serviceID string) error {
+	start := time.Now()
+	err := i.store.DeleteRoutingConfigTx(tx, serviceID)
+	i.report("DeleteRoutingConfigTx", start, err, -1)
+	return err
+}
+
+// GetRoutingConfigsForCache 实现 Store
+func (i *instrumentedStore) GetRoutingConfigsForCache(mtime time.Time, firstUpdate bool) ([]*model.RoutingConfig, error) {
+	start := time.Now()
+	ret0, err := i.store.GetRoutingConfigsForCache(mtime, firstUpdate)
+	i.report("GetRoutingConfigsForCache", start, err, len(ret0))
+	return ret0, err
+}
+
+// GetRoutingConfigWithService 实现 Store
+func (i *instrumentedStore) GetRoutingConfigWithService(name string, namespace string) (*model.RoutingConfig, error) {
+	start := time.Now()
+	ret0, err := i.store.GetRoutingConfigWithService(name, namespace)
+	i.report("GetRoutingConfigWithService", start, err, countOf(ret0 != nil))
+	return ret0, err
+}
+
+// GetRoutingConfigWithID 实现 Store
+func (i *instrumentedStore) GetRoutingConfigWithID(id string) (*model.RoutingConfig, error) {
+	start := time.Now()
+	ret0, err := i.store.GetRoutingConfigWithID(id)
+	i.report("GetRoutingConfigWithID", start, err, countOf(ret0 != nil))
+	return ret0, err
+}
+
+// GetRoutingConfigs 实现 Store
+func (i *instrumentedStore) GetRoutingConfigs(filter map[string]string, offset uint32, limit uint32) (uint32,
+	[]*model.RoutingConfig, error) {
+	start := time.Now()
+	ret0, ret1, err := i.store.GetRoutingConfigs(filter, offset, limit)
+	i.report("GetRoutingConfigs", start, err, len(ret1))
+	return ret0, ret1, err
+}
+
+// GetL5Extend 实现 Store
+func (i *instrumentedStore) GetL5Extend(serviceID string) (map[string]interface{}, error) {
+	start := time.Now()
+	ret0, err := i.store.GetL5Extend(serviceID)
+	i.report("GetL5Extend", start, err, len(ret0))
+	return ret0, err
+}
+
+// SetL5Extend 实现 Store
+func (i *instrumentedStore) SetL5Extend(serviceID string, meta map[string]interface{}) (map[string]interface{}, error) {
+	start := time.Now()
+	ret0, err := i.store.SetL5Extend(serviceID, meta)
+	i.report("SetL5Extend", start, err, len(ret0))
+	return ret0, err
+}
+
+// GenNextL5Sid 实现 Store
+func (i *instrumentedStore) GenNextL5Sid(layoutID uint32) (string, error) {
+	start := time.Now()
+	ret0, err := i.store.GenNextL5Sid(layoutID)
+	i.report("GenNextL5Sid", start, err, -1)
+	return ret0, err
+}
+
+// GetMoreL5Extend 实现 Store
+func (i *instrumentedStore) GetMoreL5Extend(mtime time.Time) (map[string]map[string]interface{}, error) {
+	start := time.Now()
+	ret0, err := i.store.GetMoreL5Extend(mtime)
+	i.report("GetMoreL5Extend", start, err, len(ret0))
+	return ret0, err
+}
+
+// GetMoreL5Routes 实现 Store
+func (i *instrumentedStore) GetMoreL5Routes(flow uint32) ([]*model.Route, error) {
+	start := time.Now()
+	ret0, err := i.store.GetMoreL5Routes(flow)
+	i.report("GetMoreL5Routes", start, err, len(ret0))
+	return ret0, err
+}
+
+// GetMoreL5Policies 实现 Store
+func (i *instrumentedStore) GetMoreL5Policies(flow uint32) ([]*model.Policy, error) {
+	start := time.Now()
+	ret0, err := i.store.GetMoreL5Policies(flow)
+	i.report("GetMoreL5Policies", start, err, len(ret0))
+	return ret0, err
+}
+
+// GetMoreL5Sections 实现 Store
+func (i *instrumentedStore) GetMoreL5Sections(flow uint32) ([]*model.Section, error) {
+	start := time.Now()
+	ret0, err := i.store.GetMoreL5Sections(flow)
+	i.report("GetMoreL5Sections", start, err, len(ret0))
+	return ret0, err
+}
+
+// GetMoreL5IPConfigs 实现 Store
+func (i *instrumentedStore) GetMoreL5IPConfigs(flow uint32) ([]*model.IPConfig, error) {
+	start := time.Now()
+	ret0, err := i.store.GetMoreL5IPConfigs(flow)
+	i.report("GetMoreL5IPConfigs", start, err, len(ret0))
+	return ret0, err
+}
+
+// CreateRateLimit 实现 Store
+func (i *instrumentedStore) CreateRateLimit(limiting *model.RateLimit) error {
+	start := time.Now()
+	err := i.store.CreateRateLimit(limiting)
+	i.report("CreateRateLimit", start, err, -1)
+	return err
+}
+
+// CreateRateLimitTx 实现 Store
+func (i *instrumentedStore) CreateRateLimitTx(tx Tx, limiting *model.RateLimit) error {
+	start := time.Now()
+	err := NewNamingTxStore(i.store).CreateRateLimitTx(tx, limiting)
+	i.report("CreateRateLimitTx", start, err, -1)
+	return err
+}
+
+// UpdateRateLimit 实现 Store
+func (i *instrumentedStore) UpdateRateLimit(limiting *model.RateLimit) error {
+	start := time.Now()
+	err := i.store.UpdateRateLimit(limiting)
+	i.report("UpdateRateLimit", start, err, -1)
+	return err
+}
+
+// UpdateRateLimitTx 实现 Store
+func (i *instrumentedStore) UpdateRateLimitTx(tx Tx, limiting *model.RateLimit) error {
+	start := time.Now()
+	err := NewNamingTxStore(i.store).UpdateRateLimitTx(tx, limiting)
+	i.report("UpdateRateLimitTx", start, err, -1)
+	return err
+}
+
+// EnableRateLimit 实现 Store
+func (i *instrumentedStore) EnableRateLimit(limit *model.RateLimit) error {
+	start := time.Now()
+	err := i.store.EnableRateLimit(limit)
+	i.report("EnableRateLimit", start, err, -1)
+	return err
+}
+
+// EnableRateLimitTx 实现 Store
+func (i *instrumentedStore) EnableRateLimitTx(tx Tx, limit *model.RateLimit) error {
+	start := time.Now()
+	err := NewNamingTxStore(i.store).EnableRateLimitTx(tx, limit)
+	i.report("EnableRateLimitTx", start, err, -1)
+	return err
+}
+
+// DeleteRateLimit 实现 Store
+func (i *instrumentedStore) DeleteRateLimit(limiting *model.RateLimit) error {
+	start := time.Now()
+	err := i.store.DeleteRateLimit(limiting)
+	i.report("DeleteRateLimit", start, err, -1)
+	return err
+}
+
+// DeleteRateLimitTx 实现 Store
+func (i *instrumentedStore) DeleteRateLimitTx(tx Tx, limiting *model.RateLimit) error {
+	start := time.Now()
+	err := NewNamingTxStore(i.store).DeleteRateLimitTx(tx, limiting)
+	i.report("DeleteRateLimitTx", start, err, -1)
+	return err
+}
+
+// GetExtendRateLimits 实现 Store
+func (i *instrumentedStore) GetExtendRateLimits(query map[string]string, offset uint32, limit uint32) (uint32, []*model.RateLimit, error) {
+	start := time.Now()
+	ret0, ret1, err := i.store.GetExtendRateLimits(query, offset, limit)
+	i.report("GetExtendRateLimits", start, err, len(ret1))
+	return ret0, ret1, err
+}
+
+// GetRateLimitWithID 实现 Store
+func (i *instrumentedStore) GetRateLimitWithID(id string) (*model.RateLimit, error) {
+	start := time.Now()
+	ret0, err := i.store.GetRateLimitWithID(id)
+	i.report("GetRateLimitWithID", start, err, countOf(ret0 != nil))
+	return ret0, err
+}
+
+// GetRateLimitsForCache 实现 Store
+func (i *instrumentedStore) GetRateLimitsForCache(mtime time.Time, firstUpdate bool) ([]*model.RateLimit, error) {
+	start := time.Now()
+	ret0, err := i.store.GetRateLimitsForCache(mtime, firstUpdate)
+	i.report("GetRateLimitsForCache", start, err, len(ret0))
+	return ret0, err
+}
+
+// CreateCircuitBreakerRule 实现 Store
+func (i *instrumentedStore) CreateCircuitBreakerRule(cbRule *model.CircuitBreakerRule) error {
+	start := time.Now()
+	err := i.store.CreateCircuitBreakerRule(cbRule)
+	i.report("CreateCircuitBreakerRule", start, err, -1)
+	return err
+}
+
+// CreateCircuitBreakerRuleTx 实现 Store
+func (i *instrumentedStore) CreateCircuitBreakerRuleTx(tx Tx, cbRule *model.CircuitBreakerRule) error {
+	start := time.Now()
+	err := NewNamingTxStore(i.store).CreateCircuitBreakerRuleTx(tx, cbRule)
+	i.report("CreateCircuitBreakerRuleTx", start, err, -1)
+	return err
+}
+
+// UpdateCircuitBreakerRule 实现 Store
+func (i *instrumentedStore) UpdateCircuitBreakerRule(cbRule *model.CircuitBreakerRule) error {
+	start := time.Now()
+	err := i.store.UpdateCircuitBreakerRule(cbRule)
+	i.report("UpdateCircuitBreakerRule", start, err, -1)
+	return err
+}
+
+// UpdateCircuitBreakerRuleTx 实现 Store
+func (i *instrumentedStore) UpdateCircuitBreakerRuleTx(tx Tx, cbRule *model.CircuitBreakerRule) error {
+	start := time.Now()
+	err := NewNamingTxStore(i.store).UpdateCircuitBreakerRuleTx(tx, cbRule)
+	i.report("UpdateCircuitBreakerRuleTx", start, err, -1)
+	return err
+}
+
+// DeleteCircuitBreakerRule 实现 Store
+func (i *instrumentedStore) DeleteCircuitBreakerRule(id string) error {
+	start := time.Now()
+	err := i.store.DeleteCircuitBreakerRule(id)
+	i.report("DeleteCircuitBreakerRule", start, err, -1)
+	return err
+}
+
+// DeleteCircuitBreakerRuleTx 实现 Store
+func (i *instrumentedStore) DeleteCircuitBreakerRuleTx(tx Tx, id string) error {
+	start := time.Now()
+	err := NewNamingTxStore(i.store).DeleteCircuitBreakerRuleTx(tx, id)
+	i.report("DeleteCircuitBreakerRuleTx", start, err, -1)
+	return err
+}
+
+// HasCircuitBreakerRule 实现 Store
+func (i *instrumentedStore) HasCircuitBreakerRule(id string) (bool, error) {
+	start := time.Now()
+	ret0, err := i.store.HasCircuitBreakerRule(id)
+	i.report("HasCircuitBreakerRule", start, err, -1)
+	return ret0, err
+}
+
+// HasCircuitBreakerRuleByName 实现 Store
+func (i *instrumentedStore) HasCircuitBreakerRuleByName(name string, namespace string) (bool, error) {
+	start := time.Now()
+	ret0, err := i.store.HasCircuitBreakerRuleByName(name, namespace)
+	i.report("HasCircuitBreakerRuleByName", start, err, -1)
+	return ret0, err
+}
+
+// HasCircuitBreakerRuleByNameExcludeId 实现 Store
+func (i *instrumentedStore) HasCircuitBreakerRuleByNameExcludeId(name string, namespace string, id string) (bool, error) {
+	start := time.Now()
+	ret0, err := i.store.HasCircuitBreakerRuleByNameExcludeId(name, namespace, id)
+	i.report("HasCircuitBreakerRuleByNameExcludeId", start, err, -1)
+	return ret0, err
+}
+
+// GetCircuitBreakerRules 实现 Store
+func (i *instrumentedStore) GetCircuitBreakerRules(filter map[string]string, offset uint32, limit uint32) (uint32,
+	[]*model.CircuitBreakerRule, error) {
+	start := time.Now()
+	ret0, ret1, err := i.store.GetCircuitBreakerRules(filter, offset, limit)
+	i.report("GetCircuitBreakerRules", start, err, len(ret1))
+	return ret0, ret1, err
+}
+
+// GetCircuitBreakerRulesForCache 实现 Store
+func (i *instrumentedStore) GetCircuitBreakerRulesForCache(mtime time.Time, firstUpdate bool) ([]*model.CircuitBreakerRule, error) {
+	start := time.Now()
+	ret0, err := i.store.GetCircuitBreakerRulesForCache(mtime, firstUpdate)
+	i.report("GetCircuitBreakerRulesForCache", start, err, len(ret0))
+	return ret0, err
+}
+
+// EnableCircuitBreakerRule 实现 Store
+func (i *instrumentedStore) EnableCircuitBreakerRule(cbRule *model.CircuitBreakerRule) error {
+	start := time.Now()
+	err := i.store.EnableCircuitBreakerRule(cbRule)
+	i.report("EnableCircuitBreakerRule", start, err, -1)
+	return err
+}
+
+// EnableCircuitBreakerRuleTx 实现 Store
+func (i *instrumentedStore) EnableCircuitBreakerRuleTx(tx Tx, cbRule *model.CircuitBreakerRule) error {
+	start := time.Now()
+	err := NewNamingTxStore(i.store).EnableCircuitBreakerRuleTx(tx, cbRule)
+	i.report("EnableCircuitBreakerRuleTx", start, err, -1)
+	return err
+}
+
+// EnableRouting 实现 Store
+func (i *instrumentedStore) EnableRouting(conf *model.RouterConfig) error {
+	start := time.Now()
+	err := i.store.EnableRouting(conf)
+	i.report("EnableRouting", start, err, -1)
+	return err
+}
+
+// EnableRoutingTx 实现 Store
+func (i *instrumentedStore) EnableRoutingTx(tx Tx, conf *model.RouterConfig) error {
+	start := time.Now()
+	err := NewNamingTxStore(i.store).EnableRoutingTx(tx, conf)
+	i.report("EnableRoutingTx", start, err, -1)
+	return err
+}
+
+// CreateRoutingConfigV2 实现 Store
+func (i *instrumentedStore) CreateRoutingConfigV2(conf *model.RouterConfig) error {
+	start := time.Now()
+	err := i.store.CreateRoutingConfigV2(conf)
+	i.report("CreateRoutingConfigV2", start, err, -1)
+	return err
+}
+
+// CreateRoutingConfigV2Tx 实现 Store
+func (i *instrumentedStore) CreateRoutingConfigV2Tx(tx Tx, conf *model.RouterConfig) error {
+	start := time.Now()
+	err := i.store.CreateRoutingConfigV2Tx(tx, conf)
+	i.report("CreateRoutingConfigV2Tx", start, err, -1)
+	return err
+}
+
+// UpdateRoutingConfigV2 实现 Store
+func (i *instrumentedStore) UpdateRoutingConfigV2(conf *model.RouterConfig) error {
+	start := time.Now()
+	err := i.store.UpdateRoutingConfigV2(conf)
+	i.report("UpdateRoutingConfigV2", start, err, -1)
+	return err
+}
+
+// UpdateRoutingConfigV2Tx 实现 Store
+func (i *instrumentedStore) UpdateRoutingConfigV2Tx(tx Tx, conf *model.RouterConfig) error {
+	start := time.Now()
+	err := i.store.UpdateRoutingConfigV2Tx(tx, conf)
+	i.report("UpdateRoutingConfigV2Tx", start, err, -1)
+	return err
+}
+
+// DeleteRoutingConfigV2 实现 Store
+func (i *instrumentedStore) DeleteRoutingConfigV2(serviceID string) error {
+	start := time.Now()
+	err := i.store.DeleteRoutingConfigV2(serviceID)
+	i.report("DeleteRoutingConfigV2", start, err, -1)
+	return err
+}
+
+// DeleteRoutingConfigV2Tx 实现 Store
+func (i *instrumentedStore) DeleteRoutingConfigV2Tx(tx Tx, serviceID string) error {
+	start := time.Now()
+	err := NewNamingTxStore(i.store).DeleteRoutingConfigV2Tx(tx, serviceID)
+	i.report("DeleteRoutingConfigV2Tx", start, err, -1)
+	return err
+}
+
+// GetRoutingConfigsV2ForCache 实现 Store
+func (i *instrumentedStore) GetRoutingConfigsV2ForCache(mtime time.Time, firstUpdate bool) ([]*model.RouterConfig, error) {
+	start := time.Now()
+	ret0, err := i.store.GetRoutingConfigsV2ForCache(mtime, firstUpdate)
+	i.report("GetRoutingConfigsV2ForCache", start, err, len(ret0))
+	return ret0, err
+}
+
+// GetRoutingConfigV2WithID 实现 Store
+func (i *instrumentedStore) GetRoutingConfigV2WithID(id string) (*model.RouterConfig, error) {
+	start := time.Now()
+	ret0, err := i.store.GetRoutingConfigV2WithID(id)
+	i.report("GetRoutingConfigV2WithID", start, err, countOf(ret0 != nil))
+	return ret0, err
+}
+
+// GetRoutingConfigV2WithIDTx 实现 Store
+func (i *instrumentedStore) GetRoutingConfigV2WithIDTx(tx Tx, id string) (*model.RouterConfig, error) {
+	start := time.Now()
+	ret0, err := i.store.GetRoutingConfigV2WithIDTx(tx, id)
+	i.report("GetRoutingConfigV2WithIDTx", start, err, countOf(ret0 != nil))
+	return ret0, err
+}
+
+// CreateFaultDetectRule 实现 Store
+func (i *instrumentedStore) CreateFaultDetectRule(conf *model.FaultDetectRule) error {
+	start := time.Now()
+	err := i.store.CreateFaultDetectRule(conf)
+	i.report("CreateFaultDetectRule", start, err, -1)
+	return err
+}
+
+// CreateFaultDetectRuleTx 实现 Store
+func (i *instrumentedStore) CreateFaultDetectRuleTx(tx Tx, conf *model.FaultDetectRule) error {
+	start := time.Now()
+	err := NewNamingTxStore(i.store).CreateFaultDetectRuleTx(tx, conf)
+	i.report("CreateFaultDetectRuleTx", start, err, -1)
+	return err
+}
+
+// UpdateFaultDetectRule 实现 Store
+func (i *instrumentedStore) UpdateFaultDetectRule(conf *model.FaultDetectRule) error {
+	start := time.Now()
+	err := i.store.UpdateFaultDetectRule(conf)
+	i.report("UpdateFaultDetectRule", start, err, -1)
+	return err
+}
+
+// UpdateFaultDetectRuleTx 实现 Store
+func (i *instrumentedStore) UpdateFaultDetectRuleTx(tx Tx, conf *model.FaultDetectRule) error {
+	start := time.Now()
+	err := NewNamingTxStore(i.store).UpdateFaultDetectRuleTx(tx, conf)
+	i.report("UpdateFaultDetectRuleTx", start, err, -1)
+	return err
+}
+
+// DeleteFaultDetectRule 实现 Store
+func (i *instrumentedStore) DeleteFaultDetectRule(id string) error {
+	start := time.Now()
+	err := i.store.DeleteFaultDetectRule(id)
+	i.report("DeleteFaultDetectRule", start, err, -1)
+	return err
+}
+
+// DeleteFaultDetectRuleTx 实现 Store
+func (i *instrumentedStore) DeleteFaultDetectRuleTx(tx Tx, id string) error {
+	start := time.Now()
+	err := NewNamingTxStore(i.store).DeleteFaultDetectRuleTx(tx, id)
+	i.report("DeleteFaultDetectRuleTx", start, err, -1)
+	return err
+}
+
+// HasFaultDetectRule 实现 Store
+func (i *instrumentedStore) HasFaultDetectRule(id string) (bool, error) {
+	start := time.Now()
+	ret0, err := i.store.HasFaultDetectRule(id)
+	i.report("HasFaultDetectRule", start, err, -1)
+	return ret0, err
+}
+
+// HasFaultDetectRuleByName 实现 Store
+func (i *instrumentedStore) HasFaultDetectRuleByName(name string, namespace string) (bool, error) {
+	start := time.Now()
+	ret0, err := i.store.HasFaultDetectRuleByName(name, namespace)
+	i.report("HasFaultDetectRuleByName", start, err, -1)
+	return ret0, err
+}
+
+// HasFaultDetectRuleByNameExcludeId 实现 Store
+func (i *instrumentedStore) HasFaultDetectRuleByNameExcludeId(name string, namespace string, id string) (bool, error) {
+	start := time.Now()
+	ret0, err := i.store.HasFaultDetectRuleByNameExcludeId(name, namespace, id)
+	i.report("HasFaultDetectRuleByNameExcludeId", start, err, -1)
+	return ret0, err
+}
+
+// GetFaultDetectRules 实现 Store
+func (i *instrumentedStore) GetFaultDetectRules(filter map[string]string, offset uint32, limit uint32) (uint32,
+	[]*model.FaultDetectRule, error) {
+	start := time.Now()
+	ret0, ret1, err := i.store.GetFaultDetectRules(filter, offset, limit)
+	i.report("GetFaultDetectRules", start, err, len(ret1))
+	return ret0, ret1, err
+}
+
+// GetFaultDetectRulesForCache 实现 Store
+func (i *instrumentedStore) GetFaultDetectRulesForCache(mtime time.Time, firstUpdate bool) ([]*model.FaultDetectRule, error) {
+	start := time.Now()
+	ret0, err := i.store.GetFaultDetectRulesForCache(mtime, firstUpdate)
+	i.report("GetFaultDetectRulesForCache", start, err, len(ret0))
+	return ret0, err
+}
+
+// CreateServiceContract 实现 Store
+func (i *instrumentedStore) CreateServiceContract(contract *model.ServiceContract) error {
+	start := time.Now()
+	err := i.store.CreateServiceContract(contract)
+	i.report("CreateServiceContract", start, err, -1)
+	return err
+}
+
+// CreateServiceContractTx 实现 Store
+func (i *instrumentedStore) CreateServiceContractTx(tx Tx, contract *model.ServiceContract) error {
+	start := time.Now()
+	err := NewNamingTxStore(i.store).CreateServiceContractTx(tx, contract)
+	i.report("CreateServiceContractTx", start, err, -1)
+	return err
+}
+
+// UpdateServiceContract 实现 Store
+func (i *instrumentedStore) UpdateServiceContract(contract *model.ServiceContract) error {
+	start := time.Now()
+	err := i.store.UpdateServiceContract(contract)
+	i.report("UpdateServiceContract", start, err, -1)
+	return err
+}
+
+// UpdateServiceContractTx 实现 Store
+func (i *instrumentedStore) UpdateServiceContractTx(tx Tx, contract *model.ServiceContract) error {
+	start := time.Now()
+	err := NewNamingTxStore(i.store).UpdateServiceContractTx(tx, contract)
+	i.report("UpdateServiceContractTx", start, err, -1)
+	return err
+}
+
+// DeleteServiceContract 实现 Store
+func (i *instrumentedStore) DeleteServiceContract(contract *model.ServiceContract) error {
+	start := time.Now()
+	err := i.store.DeleteServiceContract(contract)
+	i.report("DeleteServiceContract", start, err, -1)
+	return err
+}
+
+// DeleteServiceContractTx 实现 Store
+func (i *instrumentedStore) DeleteServiceContractTx(tx Tx, contract *model.ServiceContract) error {
+	start := time.Now()
+	err := NewNamingTxStore(i.store).DeleteServiceContractTx(tx, contract)
+	i.report("DeleteServiceContractTx", start, err, -1)
+	return err
+}
+
+// GetMoreServiceContracts 实现 Store
+func (i *instrumentedStore) GetMoreServiceContracts(firstUpdate bool, mtime time.Time) ([]*model.ServiceContract, error) {
+	start := time.Now()
+	ret0, err := i.store.GetMoreServiceContracts(firstUpdate, mtime)
+	i.report("GetMoreServiceContracts", start, err, len(ret0))
+	return ret0, err
+}
+
+// GetServiceContract 实现 Store
+func (i *instrumentedStore) GetServiceContract(id string) (*model.ServiceContract, error) {
+	start := time.Now()
+	ret0, err := i.store.GetServiceContract(id)
+	i.report("GetServiceContract", start, err, countOf(ret0 != nil))
+	return ret0, err
+}
+
+// AddServiceContractInterfaces 实现 Store
+func (i *instrumentedStore) AddServiceContractInterfaces(contract *model.ServiceContract) error {
+	start := time.Now()
+	err := i.store.AddServiceContractInterfaces(contract)
+	i.report("AddServiceContractInterfaces", start, err, -1)
+	return err
+}
+
+// AddServiceContractInterfacesTx 实现 Store
+func (i *instrumentedStore) AddServiceContractInterfacesTx(tx Tx, contract *model.ServiceContract) error {
+	start := time.Now()
+	err := NewNamingTxStore(i.store).AddServiceContractInterfacesTx(tx, contract)
+	i.report("AddServiceContractInterfacesTx", start, err, -1)
+	return err
+}
+
+// AppendServiceContractInterfaces 实现 Store
+func (i *instrumentedStore) AppendServiceContractInterfaces(contract *model.ServiceContract) error {
+	start := time.Now()
+	err := i.store.AppendServiceContractInterfaces(contract)
+	i.report("AppendServiceContractInterfaces", start, err, -1)
+	return err
+}
+
+// AppendServiceContractInterfacesTx 实现 Store
+func (i *instrumentedStore) AppendServiceContractInterfacesTx(tx Tx, contract *model.ServiceContract) error {
+	start := time.Now()
+	err := NewNamingTxStore(i.store).AppendServiceContractInterfacesTx(tx, contract)
+	i.report("AppendServiceContractInterfacesTx", start, err, -1)
+	return err
+}
+
+// DeleteServiceContractInterfaces 实现 Store
+func (i *instrumentedStore) DeleteServiceContractInterfaces(contract *model.ServiceContract) error {
+	start := time.Now()
+	err := i.store.DeleteServiceContractInterfaces(contract)
+	i.report("DeleteServiceContractInterfaces", start, err, -1)
+	return err
+}
+
+// DeleteServiceContractInterfacesTx 实现 Store
+func (i *instrumentedStore) DeleteServiceContractInterfacesTx(tx Tx, contract *model.ServiceContract) error {
+	start := time.Now()
+	err := NewNamingTxStore(i.store).DeleteServiceContractInterfacesTx(tx, contract)
+	i.report("DeleteServiceContractInterfacesTx", start, err, -1)
+	return err
+}
+
+// CreateConfigFileGroup 实现 Store
+func (i *instrumentedStore) CreateConfigFileGroup(fileGroup *model.ConfigFileGroup) (*model.ConfigFileGroup, error) {
+	start := time.Now()
+	ret0, err := i.store.CreateConfigFileGroup(fileGroup)
+	i.report("CreateConfigFileGroup", start, err, countOf(ret0 != nil))
+	return ret0, err
+}
+
+// UpdateConfigFileGroup 实现 Store
+func (i *instrumentedStore) UpdateConfigFileGroup(fileGroup *model.ConfigFileGroup) error {
+	start := time.Now()
+	err := i.store.UpdateConfigFileGroup(fileGroup)
+	i.report("UpdateConfigFileGroup", start, err, -1)
+	return err
+}
+
+// GetConfigFileGroup 实现 Store
+func (i *instrumentedStore) GetConfigFileGroup(namespace string, name string) (*model.ConfigFileGroup, error) {
+	start := time.Now()
+	ret0, err := i.store.GetConfigFileGroup(namespace, name)
+	i.report("GetConfigFileGroup", start, err, countOf(ret0 != nil))
+	return ret0, err
+}
+
+// DeleteConfigFileGroup 实现 Store
+func (i *instrumentedStore) DeleteConfigFileGroup(namespace string, name string) error {
+	start := time.Now()
+	err := i.store.DeleteConfigFileGroup(namespace, name)
+	i.report("DeleteConfigFileGroup", start, err, -1)
+	return err
+}
+
+// GetMoreConfigGroup 实现 Store
+func (i *instrumentedStore) GetMoreConfigGroup(firstUpdate bool, mtime time.Time) ([]*model.ConfigFileGroup, error) {
+	start := time.Now()
+	ret0, err := i.store.GetMoreConfigGroup(firstUpdate, mtime)
+	i.report("GetMoreConfigGroup", start, err, len(ret0))
+	return ret0, err
+}
+
+// CountConfigGroups 实现 Store
+func (i *instrumentedStore) CountConfigGroups(namespace string) (uint64, error) {
+	start := time.Now()
+	ret0, err := i.store.CountConfigGroups(namespace)
+	i.report("CountConfigGroups", start, err, -1)
+	return ret0, err
+}
+
+// LockConfigFile 实现 Store
+func (i *instrumentedStore) LockConfigFile(tx Tx, file *model.ConfigFileKey) (*model.ConfigFile, error) {
+	start := time.Now()
+	ret0, err := i.store.LockConfigFile(tx, file)
+	i.report("LockConfigFile", start, err, countOf(ret0 != nil))
+	return ret0, err
+}
+
+// CreateConfigFileTx 实现 Store
+func (i *instrumentedStore) CreateConfigFileTx(tx Tx, file *model.ConfigFile) error {
+	start := time.Now()
+	err := i.store.CreateConfigFileTx(tx, file)
+	i.report("CreateConfigFileTx", start, err, -1)
+	return err
+}
+
+// GetConfigFile 实现 Store
+func (i *instrumentedStore) GetConfigFile(namespace string, group string, name string) (*model.ConfigFile, error) {
+	start := time.Now()
+	ret0, err := i.store.GetConfigFile(namespace, group, name)
+	i.report("GetConfigFile", start, err, countOf(ret0 != nil))
+	return ret0, err
+}
+
+// GetConfigFileTx 实现 Store
+func (i *instrumentedStore) GetConfigFileTx(tx Tx, namespace string, group string, name string) (*model.ConfigFile, error) {
+	start := time.Now()
+	ret0, err := i.store.GetConfigFileTx(tx, namespace, group, name)
+	i.report("GetConfigFileTx", start, err, countOf(ret0 != nil))
+	return ret0, err
+}
+
+// QueryConfigFiles 实现 Store
+func (i *instrumentedStore) QueryConfigFiles(filter map[string]string, offset uint32, limit uint32) (uint32, []*model.ConfigFile, error) {
+	start := time.Now()
+	ret0, ret1, err := i.store.QueryConfigFiles(filter, offset, limit)
+	i.report("QueryConfigFiles", start, err, len(ret1))
+	return ret0, ret1, err
+}
+
+// UpdateConfigFileTx 实现 Store
+func (i *instrumentedStore) UpdateConfigFileTx(tx Tx, file *model.ConfigFile) error {
+	start := time.Now()
+	err := i.store.UpdateConfigFileTx(tx, file)
+	i.report("UpdateConfigFileTx", start, err, -1)
+	return err
+}
+
+// DeleteConfigFileTx 实现 Store
+func (i *instrumentedStore) DeleteConfigFileTx(tx Tx, namespace string, group string, name string) error {
+	start := time.Now()
+	err := i.store.DeleteConfigFileTx(tx, namespace, group, name)
+	i.report("DeleteConfigFileTx", start, err, -1)
+	return err
+}
+
+// CountConfigFiles 实现 Store
+func (i *instrumentedStore) CountConfigFiles(namespace string, group string) (uint64, error) {
+	start := time.Now()
+	ret0, err := i.store.CountConfigFiles(namespace, group)
+	i.report("CountConfigFiles", start, err, -1)
+	return ret0, err
+}
+
+// CountConfigFileEachGroup 实现 Store
+func (i *instrumentedStore) CountConfigFileEachGroup() (map[string]map[string]int64, error) {
+	start := time.Now()
+	ret0, err := i.store.CountConfigFileEachGroup()
+	i.report("CountConfigFileEachGroup", start, err, len(ret0))
+	return ret0, err
+}
+
+// GetConfigFileActiveRelease 实现 Store
+func (i *instrumentedStore) GetConfigFileActiveRelease(file *model.ConfigFileKey) (*model.ConfigFileRelease, error) {
+	start := time.Now()
+	ret0, err := i.store.GetConfigFileActiveRelease(file)
+	i.report("GetConfigFileActiveRelease", start, err, countOf(ret0 != nil))
+	return ret0, err
+}
+
+// GetConfigFileActiveReleaseTx 实现 Store
+func (i *instrumentedStore) GetConfigFileActiveReleaseTx(tx Tx, file *model.ConfigFileKey) (*model.ConfigFileRelease, error) {
+	start := time.Now()
+	ret0, err := i.store.GetConfigFileActiveReleaseTx(tx, file)
+	i.report("GetConfigFileActiveReleaseTx", start, err, countOf(ret0 != nil))
+	return ret0, err
+}
+
+// CreateConfigFileReleaseTx 实现 Store
+func (i *instrumentedStore) CreateConfigFileReleaseTx(tx Tx, fileRelease *model.ConfigFileRelease) error {
+	start := time.Now()
+	err := i.store.CreateConfigFileReleaseTx(tx, fileRelease)
+	i.report("CreateConfigFileReleaseTx", start, err, -1)
+	return err
+}
+
+// GetConfigFileRelease 实现 Store
+func (i *instrumentedStore) GetConfigFileRelease(req *model.ConfigFileReleaseKey) (*model.ConfigFileRelease, error) {
+	start := time.Now()
+	ret0, err := i.store.GetConfigFileRelease(req)
+	i.report("GetConfigFileRelease", start, err, countOf(ret0 != nil))
+	return ret0, err
+}
+
+// GetConfigFileReleaseTx 实现 Store
+func (i *instrumentedStore) GetConfigFileReleaseTx(tx Tx, req *model.ConfigFileReleaseKey) (*model.ConfigFileRelease, error) {
+	start := time.Now()
+	ret0, err := i.store.GetConfigFileReleaseTx(tx, req)
+	i.report("GetConfigFileReleaseTx", start, err, countOf(ret0 != nil))
+	return ret0, err
+}
+
+// DeleteConfigFileReleaseTx 实现 Store
+func (i *instrumentedStore) DeleteConfigFileReleaseTx(tx Tx, data *model.ConfigFileReleaseKey) error {
+	start := time.Now()
+	err := i.store.DeleteConfigFileReleaseTx(tx, data)
+	i.report("DeleteConfigFileReleaseTx", start, err, -1)
+	return err
+}
+
+// ActiveConfigFileReleaseTx 实现 Store
+func (i *instrumentedStore) ActiveConfigFileReleaseTx(tx Tx, release *model.ConfigFileRelease) error {
+	start := time.Now()
+	err := i.store.ActiveConfigFileReleaseTx(tx, release)
+	i.report("ActiveConfigFileReleaseTx", start, err, -1)
+	return err
+}
+
+// InactiveConfigFileReleaseTx 实现 Store
+func (i *instrumentedStore) InactiveConfigFileReleaseTx(tx Tx, release *model.ConfigFileRelease) error {
+	start := time.Now()
+	err := i.store.InactiveConfigFileReleaseTx(tx, release)
+	i.report("InactiveConfigFileReleaseTx", start, err, -1)
+	return err
+}
+
+// CleanConfigFileReleasesTx 实现 Store
+func (i *instrumentedStore) CleanConfigFileReleasesTx(tx Tx, namespace string, group string, fileName string) error {
+	start := time.Now()
+	err := i.store.CleanConfigFileReleasesTx(tx, namespace, group, fileName)
+	i.report("CleanConfigFileReleasesTx", start, err, -1)
+	return err
+}
+
+// GetMoreReleaseFile 实现 Store
+func (i *instrumentedStore) GetMoreReleaseFile(firstUpdate bool, modifyTime time.Time) ([]*model.ConfigFileRelease, error) {
+	start := time.Now()
+	ret0, err := i.store.GetMoreReleaseFile(firstUpdate, modifyTime)
+	i.report("GetMoreReleaseFile", start, err, len(ret0))
+	return ret0, err
+}
+
+// CountConfigReleases 实现 Store
+func (i *instrumentedStore) CountConfigReleases(namespace string, group string, onlyActive bool) (uint64, error) {
+	start := time.Now()
+	ret0, err := i.store.CountConfigReleases(namespace, group, onlyActive)
+	i.report("CountConfigReleases", start, err, -1)
+	return ret0, err
+}
+
+// GetConfigFileBetaReleaseTx 实现 Store
+func (i *instrumentedStore) GetConfigFileBetaReleaseTx(tx Tx, file *model.ConfigFileKey) (*model.ConfigFileRelease, error) {
+	start := time.Now()
+	ret0, err := i.store.GetConfigFileBetaReleaseTx(tx, file)
+	i.report("GetConfigFileBetaReleaseTx", start, err, countOf(ret0 != nil))
+	return ret0, err
+}
+
+// CreateConfigFileReleaseHistory 实现 Store
+func (i *instrumentedStore) CreateConfigFileReleaseHistory(history *model.ConfigFileReleaseHistory) error {
+	start := time.Now()
+	err := i.store.CreateConfigFileReleaseHistory(history)
+	i.report("CreateConfigFileReleaseHistory", start, err, -1)
+	return err
+}
+
+// QueryConfigFileReleaseHistories 实现 Store
+func (i *instrumentedStore) QueryConfigFileReleaseHistories(filter map[string]string, offset uint32,
+	limit uint32) (uint32, []*model.ConfigFileReleaseHistory, error) {
+	start := time.Now()
+	ret0, ret1, err := i.store.QueryConfigFileReleaseHistories(filter, offset, limit)
+	i.report("QueryConfigFileReleaseHistories", start, err, len(ret1))
+	return ret0, ret1, err
+}
+
+// CleanConfigFileReleaseHistory 实现 Store
+func (i *instrumentedStore) CleanConfigFileReleaseHistory(endTime time.Time, limit uint64) error {
+	start := time.Now()
+	err := i.store.CleanConfigFileReleaseHistory(endTime, limit)
+	i.report("CleanConfigFileReleaseHistory", start, err, -1)
+	return err
+}
+
+// QueryAllConfigFileTemplates 实现 Store
+func (i *instrumentedStore) QueryAllConfigFileTemplates() ([]*model.ConfigFileTemplate, error) {
+	start := time.Now()
+	ret0, err := i.store.QueryAllConfigFileTemplates()
+	i.report("QueryAllConfigFileTemplates", start, err, len(ret0))
+	return ret0, err
+}
+
+// CreateConfigFileTemplate 实现 Store
+func (i *instrumentedStore) CreateConfigFileTemplate(template *model.ConfigFileTemplate) (*model.ConfigFileTemplate, error) {
+	start := time.Now()
+	ret0, err := i.store.CreateConfigFileTemplate(template)
+	i.report("CreateConfigFileTemplate", start, err, countOf(ret0 != nil))
+	return ret0, err
+}
+
+// GetConfigFileTemplate 实现 Store
+func (i *instrumentedStore) GetConfigFileTemplate(name string) (*model.ConfigFileTemplate, error) {
+	start := time.Now()
+	ret0, err := i.store.GetConfigFileTemplate(name)
+	i.report("GetConfigFileTemplate", start, err, countOf(ret0 != nil))
+	return ret0, err
+}
+
+// BatchAddClients 实现 Store
+func (i *instrumentedStore) BatchAddClients(clients []*model.Client) error {
+	start := time.Now()
+	err := i.store.BatchAddClients(clients)
+	i.report("BatchAddClients", start, err, -1)
+	return err
+}
+
+// BatchDeleteClients 实现 Store
+func (i *instrumentedStore) BatchDeleteClients(ids []string) error {
+	start := time.Now()
+	err := i.store.BatchDeleteClients(ids)
+	i.report("BatchDeleteClients", start, err, -1)
+	return err
+}
+
+// GetMoreClients 实现 Store
+func (i *instrumentedStore) GetMoreClients(mtime time.Time, firstUpdate bool) (map[string]*model.Client, error) {
+	start := time.Now()
+	ret0, err := i.store.GetMoreClients(mtime, firstUpdate)
+	i.report("GetMoreClients", start, err, len(ret0))
+	return ret0, err
+}
+
+// StartLeaderElection 实现 Store
+func (i *instrumentedStore) StartLeaderElection(key string) error {
+	start := time.Now()
+	err := i.store.StartLeaderElection(key)
+	i.report("StartLeaderElection", start, err, -1)
+	return err
+}
+
+// IsLeader 实现 Store
+func (i *instrumentedStore) IsLeader(key string) bool {
+	start := time.Now()
+	ret0 := i.store.IsLeader(key)
+	i.report("IsLeader", start, nil, -1)
+	return ret0
+}
+
+// ListLeaderElections 实现 Store
+func (i *instrumentedStore) ListLeaderElections() ([]*model.LeaderElection, error) {
+	start := time.Now()
+	ret0, err := i.store.ListLeaderElections()
+	i.report("ListLeaderElections", start, err, len(ret0))
+	return ret0, err
+}
+
+// ReleaseLeaderElection 实现 Store
+func (i *instrumentedStore) ReleaseLeaderElection(key string) error {
+	start := time.Now()
+	err := i.store.ReleaseLeaderElection(key)
+	i.report("ReleaseLeaderElection", start, err, -1)
+	return err
+}
+
+// BatchCleanDeletedInstances 实现 Store
+func (i *instrumentedStore) BatchCleanDeletedInstances(timeout time.Duration, batchSize uint32) (uint32, error) {
+	start := time.Now()
+	ret0, err := i.store.BatchCleanDeletedInstances(timeout, batchSize)
+	i.report("BatchCleanDeletedInstances", start, err, -1)
+	return ret0, err
+}
+
+// GetUnHealthyInstances 实现 Store
+func (i *instrumentedStore) GetUnHealthyInstances(timeout time.Duration, limit uint32) ([]string, error) {
+	start := time.Now()
+	ret0, err := i.store.GetUnHealthyInstances(timeout, limit)
+	i.report("GetUnHealthyInstances", start, err, len(ret0))
+	return ret0, err
+}
+
+// BatchCleanDeletedClients 实现 Store
+func (i *instrumentedStore) BatchCleanDeletedClients(timeout time.Duration, batchSize uint32) (uint32, error) {
+	start := time.Now()
+	ret0, err := i.store.BatchCleanDeletedClients(timeout, batchSize)
+	i.report("BatchCleanDeletedClients", start, err, -1)
+	return ret0, err
+}
+
+// AcquireLock 实现 DistributedLockStore
+func (i *instrumentedStore) AcquireLock(key string, owner string, ttl time.Duration) (*model.DistributedLock, error) {
+	start := time.Now()
+	ret0, err := NewDistributedLockStore(i.store).AcquireLock(key, owner, ttl)
+	i.report("AcquireLock", start, err, -1)
+	return ret0, err
+}
+
+// RenewLock 实现 DistributedLockStore
+func (i *instrumentedStore) RenewLock(key string, owner string, ttl time.Duration) (*model.DistributedLock, error) {
+	start := time.Now()
+	ret0, err := NewDistributedLockStore(i.store).RenewLock(key, owner, ttl)
+	i.report("RenewLock", start, err, -1)
+	return ret0, err
+}
+
+// ReleaseLock 实现 DistributedLockStore
+func (i *instrumentedStore) ReleaseLock(key string, owner string) error {
+	start := time.Now()
+	err := NewDistributedLockStore(i.store).ReleaseLock(key, owner)
+	i.report("ReleaseLock", start, err, -1)
+	return err
+}
+
+// ListLocks 实现 DistributedLockStore
+func (i *instrumentedStore) ListLocks() ([]*model.DistributedLock, error) {
+	start := time.Now()
+	ret0, err := NewDistributedLockStore(i.store).ListLocks()
+	i.report("ListLocks", start, err, len(ret0))
+	return ret0, err
+}
+
+// WatchLeaderElection 实现 LeaderWatchStore
+func (i *instrumentedStore) WatchLeaderElection(ctx context.Context, key string) (LeaderWatcher, error) {
+	start := time.Now()
+	ret0, err := WatchLeaderElection(ctx, i.store, key)
+	i.report("WatchLeaderElection", start, err, -1)
+	return ret0, err
+}
+
+// CleanGrayResource 实现 Store
+func (i *instrumentedStore) CleanGrayResource(tx Tx, data *model.GrayResource) error {
+	start := time.Now()
+	err := i.store.CleanGrayResource(tx, data)
+	i.report("CleanGrayResource", start, err, -1)
+	return err
+}
+
+// CreateGrayResourceTx 实现 Store
+func (i *instrumentedStore) CreateGrayResourceTx(tx Tx, data *model.GrayResource) error {
+	start := time.Now()
+	err := i.store.CreateGrayResourceTx(tx, data)
+	i.report("CreateGrayResourceTx", start, err, -1)
+	return err
+}
+
+// GetMoreGrayResouces 实现 Store
+func (i *instrumentedStore) GetMoreGrayResouces(firstUpdate bool, mtime time.Time) ([]*model.GrayResource, error) {
+	start := time.Now()
+	ret0, err := i.store.GetMoreGrayResouces(firstUpdate, mtime)
+	i.report("GetMoreGrayResouces", start, err, len(ret0))
+	return ret0, err
+}
+
+// AddUser 实现 Store
+func (i *instrumentedStore) AddUser(user *model.User) error {
+	start := time.Now()
+	err := i.store.AddUser(user)
+	i.report("AddUser", start, err, -1)
+	return err
+}
+
+// UpdateUser 实现 Store
+func (i *instrumentedStore) UpdateUser(user *model.User) error {
+	start := time.Now()
+	err := i.store.UpdateUser(user)
+	i.report("UpdateUser", start, err, -1)
+	return err
+}
+
+// DeleteUser 实现 Store
+func (i *instrumentedStore) DeleteUser(user *model.User) error {
+	start := time.Now()
+	err := i.store.DeleteUser(user)
+	i.report("DeleteUser", start, err, -1)
+	return err
+}
+
+// GetSubCount 实现 Store
+func (i *instrumentedStore) GetSubCount(user *model.User) (uint32, error) {
+	start := time.Now()
+	ret0, err := i.store.GetSubCount(user)
+	i.report("GetSubCount", start, err, -1)
+	return ret0, err
+}
+
+// GetUser 实现 Store
+func (i *instrumentedStore) GetUser(id string) (*model.User, error) {
+	start := time.Now()
+	ret0, err := i.store.GetUser(id)
+	i.report("GetUser", start, err, countOf(ret0 != nil))
+	return ret0, err
+}
+
+// GetUserByName 实现 Store
+func (i *instrumentedStore) GetUserByName(name string, ownerId string) (*model.User, error) {
+	start := time.Now()
+	ret0, err := i.store.GetUserByName(name, ownerId)
+	i.report("GetUserByName", start, err, countOf(ret0 != nil))
+	return ret0, err
+}
+
+// GetUserByIds 实现 Store
+func (i *instrumentedStore) GetUserByIds(ids []string) ([]*model.User, error) {
+	start := time.Now()
+	ret0, err := i.store.GetUserByIds(ids)
+	i.report("GetUserByIds", start, err, len(ret0))
+	return ret0, err
+}
+
+// GetUsers 实现 Store
+func (i *instrumentedStore) GetUsers(filters map[string]string, offset uint32, limit uint32) (uint32, []*model.User, error) {
+	start := time.Now()
+	ret0, ret1, err := i.store.GetUsers(filters, offset, limit)
+	i.report("GetUsers", start, err, len(ret1))
+	return ret0, ret1, err
+}
+
+// GetUsersForCache 实现 Store
+func (i *instrumentedStore) GetUsersForCache(mtime time.Time, firstUpdate bool) ([]*model.User, error) {
+	start := time.Now()
+	ret0, err := i.store.GetUsersForCache(mtime, firstUpdate)
+	i.report("GetUsersForCache", start, err, len(ret0))
+	return ret0, err
+}
+
+// AddGroup 实现 Store
+func (i *instrumentedStore) AddGroup(group *model.UserGroup) error {
+	start := time.Now()
+	err := i.store.AddGroup(group)
+	i.report("AddGroup", start, err, -1)
+	return err
+}
+
+// UpdateGroup 实现 Store
+func (i *instrumentedStore) UpdateGroup(group *model.ModifyUserGroup) error {
+	start := time.Now()
+	err := i.store.UpdateGroup(group)
+	i.report("UpdateGroup", start, err, -1)
+	return err
+}
+
+// DeleteGroup 实现 Store
+func (i *instrumentedStore) DeleteGroup(group *model.UserGroup) error {
+	start := time.Now()
+	err := i.store.DeleteGroup(group)
+	i.report("DeleteGroup", start, err, -1)
+	return err
+}
+
+// GetGroup 实现 Store
+func (i *instrumentedStore) GetGroup(id string) (*model.UserGroup, error) {
+	start := time.Now()
+	ret0, err := i.store.GetGroup(id)
+	i.report("GetGroup", start, err, countOf(ret0 != nil))
+	return ret0, err
+}
+
+// GetGroupByName 实现 Store
+func (i *instrumentedStore) GetGroupByName(name string, owner string) (*model.UserGroup, error) {
+	start := time.Now()
+	ret0, err := i.store.GetGroupByName(name, owner)
+	i.report("GetGroupByName", start, err, countOf(ret0 != nil))
+	return ret0, err
+}
+
+// GetGroups 实现 Store
+func (i *instrumentedStore) GetGroups(filters map[string]string, offset uint32, limit uint32) (uint32, []*model.UserGroup, error) {
+	start := time.Now()
+	ret0, ret1, err := i.store.GetGroups(filters, offset, limit)
+	i.report("GetGroups", start, err, len(ret1))
+	return ret0, ret1, err
+}
+
+// GetGroupsForCache 实现 Store
+func (i *instrumentedStore) GetGroupsForCache(mtime time.Time, firstUpdate bool) ([]*model.UserGroup, error) {
+	start := time.Now()
+	ret0, err := i.store.GetGroupsForCache(mtime, firstUpdate)
+	i.report("GetGroupsForCache", start, err, len(ret0))
+	return ret0, err
+}
+
+// AddStrategy 实现 Store
+func (i *instrumentedStore) AddStrategy(strategy *model.StrategyDetail) error {
+	start := time.Now()
+	err := i.store.AddStrategy(strategy)
+	i.report("AddStrategy", start, err, -1)
+	return err
+}
+
+// UpdateStrategy 实现 Store
+func (i *instrumentedStore) UpdateStrategy(strategy *model.ModifyStrategyDetail) error {
+	start := time.Now()
+	err := i.store.UpdateStrategy(strategy)
+	i.report("UpdateStrategy", start, err, -1)
+	return err
+}
+
+// DeleteStrategy 实现 Store
+func (i *instrumentedStore) DeleteStrategy(id string) error {
+	start := time.Now()
+	err := i.store.DeleteStrategy(id)
+	i.report("DeleteStrategy", start, err, -1)
+	return err
+}
+
+// LooseAddStrategyResources 实现 Store
+func (i *instrumentedStore) LooseAddStrategyResources(resources []model.StrategyResource) error {
+	start := time.Now()
+	err := i.store.LooseAddStrategyResources(resources)
+	i.report("LooseAddStrategyResources", start, err, -1)
+	return err
+}
+
+// RemoveStrategyResources 实现 Store
+func (i *instrumentedStore) RemoveStrategyResources(resources []model.StrategyResource) error {
+	start := time.Now()
+	err := i.store.RemoveStrategyResources(resources)
+	i.report("RemoveStrategyResources", start, err, -1)
+	return err
+}
+
+// GetStrategyResources 实现 Store
+func (i *instrumentedStore) GetStrategyResources(principalId string, principalRole string) ([]model.StrategyResource, error) {
+	start := time.Now()
+	ret0, err := i.store.GetStrategyResources(principalId, principalRole)
+	i.report("GetStrategyResources", start, err, len(ret0))
+	return ret0, err
+}
+
+// GetDefaultStrategyDetailByPrincipal 实现 Store
+func (i *instrumentedStore) GetDefaultStrategyDetailByPrincipal(principalId string, principalType string) (*model.StrategyDetail, error) {
+	start := time.Now()
+	ret0, err := i.store.GetDefaultStrategyDetailByPrincipal(principalId, principalType)
+	i.report("GetDefaultStrategyDetailByPrincipal", start, err, countOf(ret0 != nil))
+	return ret0, err
+}
+
+// GetStrategyDetail 实现 Store
+func (i *instrumentedStore) GetStrategyDetail(id string) (*model.StrategyDetail, error) {
+	start := time.Now()
+	ret0, err := i.store.GetStrategyDetail(id)
+	i.report("GetStrategyDetail", start, err, countOf(ret0 != nil))
+	return ret0, err
+}
+
+// GetStrategies 实现 Store
+func (i *instrumentedStore) GetStrategies(filters map[string]string, offset uint32, limit uint32) (uint32, []*model.StrategyDetail, error) {
+	start := time.Now()
+	ret0, ret1, err := i.store.GetStrategies(filters, offset, limit)
+	i.report("GetStrategies", start, err, len(ret1))
+	return ret0, ret1, err
+}
+
+// GetStrategyDetailsForCache 实现 Store
+func (i *instrumentedStore) GetStrategyDetailsForCache(mtime time.Time, firstUpdate bool) ([]*model.StrategyDetail, error) {
+	start := time.Now()
+	ret0, err := i.store.GetStrategyDetailsForCache(mtime, firstUpdate)
+	i.report("GetStrategyDetailsForCache", start, err, len(ret0))
+	return ret0, err
+}
+
+// UpdateServiceCAS 实现 RevisionStore
+func (i *instrumentedStore) UpdateServiceCAS(service *model.Service, needUpdateOwner bool, expectRevision string) error {
+	start := time.Now()
+	err := NewRevisionStore(i.store).UpdateServiceCAS(service, needUpdateOwner, expectRevision)
+	i.report("UpdateServiceCAS", start, err, -1)
+	return err
+}
+
+// UpdateRoutingConfigCAS 实现 RevisionStore
+func (i *instrumentedStore) UpdateRoutingConfigCAS(conf *model.RoutingConfig, expectRevision string) error {
+	start := time.Now()
+	err := NewRevisionStore(i.store).UpdateRoutingConfigCAS(conf, expectRevision)
+	i.report("UpdateRoutingConfigCAS", start, err, -1)
+	return err
+}
+
+// UpdateRoutingConfigV2CAS 实现 RevisionStore
+func (i *instrumentedStore) UpdateRoutingConfigV2CAS(conf *model.RouterConfig, expectRevision string) error {
+	start := time.Now()
+	err := NewRevisionStore(i.store).UpdateRoutingConfigV2CAS(conf, expectRevision)
+	i.report("UpdateRoutingConfigV2CAS", start, err, -1)
+	return err
+}
+
+// UpdateRateLimitCAS 实现 RevisionStore
+func (i *instrumentedStore) UpdateRateLimitCAS(limit *model.RateLimit, expectRevision string) error {
+	start := time.Now()
+	err := NewRevisionStore(i.store).UpdateRateLimitCAS(limit, expectRevision)
+	i.report("UpdateRateLimitCAS", start, err, -1)
+	return err
+}
+
+// UpdateCircuitBreakerRuleCAS 实现 RevisionStore
+func (i *instrumentedStore) UpdateCircuitBreakerRuleCAS(cbRule *model.CircuitBreakerRule, expectRevision string) error {
+	start := time.Now()
+	err := NewRevisionStore(i.store).UpdateCircuitBreakerRuleCAS(cbRule, expectRevision)
+	i.report("UpdateCircuitBreakerRuleCAS", start, err, -1)
+	return err
+}
+
+// UpdateFaultDetectRuleCAS 实现 RevisionStore
+func (i *instrumentedStore) UpdateFaultDetectRuleCAS(conf *model.FaultDetectRule, expectRevision string) error {
+	start := time.Now()
+	err := NewRevisionStore(i.store).UpdateFaultDetectRuleCAS(conf, expectRevision)
+	i.report("UpdateFaultDetectRuleCAS", start, err, -1)
+	return err
+}
+
+// UpdateStrategyCAS 实现 RevisionStore
+func (i *instrumentedStore) UpdateStrategyCAS(strategy *model.ModifyStrategyDetail, expectRevision string) error {
+	start := time.Now()
+	err := NewRevisionStore(i.store).UpdateStrategyCAS(strategy, expectRevision)
+	i.report("UpdateStrategyCAS", start, err, -1)
+	return err
+}
+
+// PurgeDeleted 实现 RetentionStore
+func (i *instrumentedStore) PurgeDeleted(kind RetentionKind, timeout time.Duration, batchSize uint32) (uint32, error) {
+	start := time.Now()
+	ret0, err := NewRetentionStore(i.store).PurgeDeleted(kind, timeout, batchSize)
+	i.report("PurgeDeleted", start, err, -1)
+	return ret0, err
+}
+
+// SearchServices 实现 QueryStore
+func (i *instrumentedStore) SearchServices(q *Query) (uint32, []*model.Service, error) {
+	start := time.Now()
+	ret0, ret1, err := NewQueryStore(i.store).SearchServices(q)
+	i.report("SearchServices", start, err, len(ret1))
+	return ret0, ret1, err
+}
+
+// SearchInstances 实现 QueryStore
+func (i *instrumentedStore) SearchInstances(q *Query) (uint32, []*model.Instance, error) {
+	start := time.Now()
+	ret0, ret1, err := NewQueryStore(i.store).SearchInstances(q)
+	i.report("SearchInstances", start, err, len(ret1))
+	return ret0, ret1, err
+}
+
+// SearchRoutingConfigs 实现 QueryStore
+func (i *instrumentedStore) SearchRoutingConfigs(q *Query) (uint32, []*model.RoutingConfig, error) {
+	start := time.Now()
+	ret0, ret1, err := NewQueryStore(i.store).SearchRoutingConfigs(q)
+	i.report("SearchRoutingConfigs", start, err, len(ret1))
+	return ret0, ret1, err
+}
+
+// SearchFaultDetectRules 实现 QueryStore
+func (i *instrumentedStore) SearchFaultDetectRules(q *Query) (uint32, []*model.FaultDetectRule, error) {
+	start := time.Now()
+	ret0, ret1, err := NewQueryStore(i.store).SearchFaultDetectRules(q)
+	i.report("SearchFaultDetectRules", start, err, len(ret1))
+	return ret0, ret1, err
+}
+
+// SearchConfigFiles 实现 QueryStore
+func (i *instrumentedStore) SearchConfigFiles(q *Query) (uint32, []*model.ConfigFile, error) {
+	start := time.Now()
+	ret0, ret1, err := NewQueryStore(i.store).SearchConfigFiles(q)
+	i.report("SearchConfigFiles", start, err, len(ret1))
+	return ret0, ret1, err
+}
+
+// SearchUsers 实现 QueryStore
+func (i *instrumentedStore) SearchUsers(q *Query) (uint32, []*model.User, error) {
+	start := time.Now()
+	ret0, ret1, err := NewQueryStore(i.store).SearchUsers(q)
+	i.report("SearchUsers", start, err, len(ret1))
+	return ret0, ret1, err
+}
+
+// SearchStrategies 实现 QueryStore
+func (i *instrumentedStore) SearchStrategies(q *Query) (uint32, []*model.StrategyDetail, error) {
+	start := time.Now()
+	ret0, ret1, err := NewQueryStore(i.store).SearchStrategies(q)
+	i.report("SearchStrategies", start, err, len(ret1))
+	return ret0, ret1, err
+}
+
+// GetNamespacesPage 实现 CursorStore
+func (i *instrumentedStore) GetNamespacesPage(filter map[string][]string, token PageToken, limit uint32) (*Page[*model.Namespace], error) {
+	start := time.Now()
+	ret0, err := NewCursorStore(i.store).GetNamespacesPage(filter, token, limit)
+	i.report("GetNamespacesPage", start, err, pageRows(ret0))
+	return ret0, err
+}
+
+// GetServicesPage 实现 CursorStore
+func (i *instrumentedStore) GetServicesPage(serviceFilters map[string]string, serviceMetas map[string]string,
+	instanceFilters *model.InstanceArgs, token PageToken, limit uint32) (*Page[*model.Service], error) {
+	start := time.Now()
+	ret0, err := NewCursorStore(i.store).GetServicesPage(serviceFilters, serviceMetas, instanceFilters, token, limit)
+	i.report("GetServicesPage", start, err, pageRows(ret0))
+	return ret0, err
+}
+
+// GetServiceAliasesPage 实现 CursorStore
+func (i *instrumentedStore) GetServiceAliasesPage(filter map[string]string, token PageToken,
+	limit uint32) (*Page[*model.ServiceAlias], error) {
+	start := time.Now()
+	ret0, err := NewCursorStore(i.store).GetServiceAliasesPage(filter, token, limit)
+	i.report("GetServiceAliasesPage", start, err, pageRows(ret0))
+	return ret0, err
+}
+
+// GetExpandInstancesPage 实现 CursorStore
+func (i *instrumentedStore) GetExpandInstancesPage(filter map[string]string, metaFilter map[string]string,
+	token PageToken, limit uint32) (*Page[*model.Instance], error) {
+	start := time.Now()
+	ret0, err := NewCursorStore(i.store).GetExpandInstancesPage(filter, metaFilter, token, limit)
+	i.report("GetExpandInstancesPage", start, err, pageRows(ret0))
+	return ret0, err
+}
+
+// GetRoutingConfigsPage 实现 CursorStore
+func (i *instrumentedStore) GetRoutingConfigsPage(filter map[string]string, token PageToken,
+	limit uint32) (*Page[*model.RoutingConfig], error) {
+	start := time.Now()
+	ret0, err := NewCursorStore(i.store).GetRoutingConfigsPage(filter, token, limit)
+	i.report("GetRoutingConfigsPage", start, err, pageRows(ret0))
+	return ret0, err
+}
+
+// GetExtendRateLimitsPage 实现 CursorStore
+func (i *instrumentedStore) GetExtendRateLimitsPage(query map[string]string, token PageToken,
+	limit uint32) (*Page[*model.RateLimit], error) {
+	start := time.Now()
+	ret0, err := NewCursorStore(i.store).GetExtendRateLimitsPage(query, token, limit)
+	i.report("GetExtendRateLimitsPage", start, err, pageRows(ret0))
+	return ret0, err
+}
+
+// GetCircuitBreakerRulesPage 实现 CursorStore
+func (i *instrumentedStore) GetCircuitBreakerRulesPage(filter map[string]string, token PageToken,
+	limit uint32) (*Page[*model.CircuitBreakerRule], error) {
+	start := time.Now()
+	ret0, err := NewCursorStore(i.store).GetCircuitBreakerRulesPage(filter, token, limit)
+	i.report("GetCircuitBreakerRulesPage", start, err, pageRows(ret0))
+	return ret0, err
+}
+
+// GetFaultDetectRulesPage 实现 CursorStore
+func (i *instrumentedStore) GetFaultDetectRulesPage(filter map[string]string, token PageToken,
+	limit uint32) (*Page[*model.FaultDetectRule], error) {
+	start := time.Now()
+	ret0, err := NewCursorStore(i.store).GetFaultDetectRulesPage(filter, token, limit)
+	i.report("GetFaultDetectRulesPage", start, err, pageRows(ret0))
+	return ret0, err
+}
+
+// QueryConfigFilesPage 实现 CursorStore
+func (i *instrumentedStore) QueryConfigFilesPage(filter map[string]string, token PageToken,
+	limit uint32) (*Page[*model.ConfigFile], error) {
+	start := time.Now()
+	ret0, err := NewCursorStore(i.store).QueryConfigFilesPage(filter, token, limit)
+	i.report("QueryConfigFilesPage", start, err, pageRows(ret0))
+	return ret0, err
+}
+
+// QueryConfigFileReleaseHistoriesPage 实现 CursorStore
+func (i *instrumentedStore) QueryConfigFileReleaseHistoriesPage(filter map[string]string, token PageToken,
+	limit uint32) (*Page[*model.ConfigFileReleaseHistory], error) {
+	start := time.Now()
+	ret0, err := NewCursorStore(i.store).QueryConfigFileReleaseHistoriesPage(filter, token, limit)
+	i.report("QueryConfigFileReleaseHistoriesPage", start, err, pageRows(ret0))
+	return ret0, err
+}
+
+// GetUsersPage 实现 CursorStore
+func (i *instrumentedStore) GetUsersPage(filters map[string]string, token PageToken, limit uint32) (*Page[*model.User], error) {
+	start := time.Now()
+	ret0, err := NewCursorStore(i.store).GetUsersPage(filters, token, limit)
+	i.report("GetUsersPage", start, err, pageRows(ret0))
+	return ret0, err
+}
+
+// GetGroupsPage 实现 CursorStore
+func (i *instrumentedStore) GetGroupsPage(filters map[string]string, token PageToken, limit uint32) (*Page[*model.UserGroup], error) {
+	start := time.Now()
+	ret0, err := NewCursorStore(i.store).GetGroupsPage(filters, token, limit)
+	i.report("GetGroupsPage", start, err, pageRows(ret0))
+	return ret0, err
+}
+
+// GetStrategiesPage 实现 CursorStore
+func (i *instrumentedStore) GetStrategiesPage(filters map[string]string, token PageToken,
+	limit uint32) (*Page[*model.StrategyDetail], error) {
+	start := time.Now()
+	ret0, err := NewCursorStore(i.store).GetStrategiesPage(filters, token, limit)
+	i.report("GetStrategiesPage", start, err, pageRows(ret0))
+	return ret0, err
+}
+
+// RangeMoreNamespaces 实现 StreamStore
+func (i *instrumentedStore) RangeMoreNamespaces(mtime time.Time, batchSize int, handler BatchHandler[*model.Namespace]) error {
+	start := time.Now()
+	err := NewStreamStore(i.store).RangeMoreNamespaces(mtime, batchSize, handler)
+	i.report("RangeMoreNamespaces", start, err, -1)
+	return err
+}
+
+// RangeMoreServices 实现 StreamStore
+func (i *instrumentedStore) RangeMoreServices(mtime time.Time, firstUpdate bool, disableBusiness bool, needMeta bool,
+	batchSize int, handler BatchHandler[*model.Service]) error {
+	start := time.Now()
+	err := NewStreamStore(i.store).RangeMoreServices(mtime, firstUpdate, disableBusiness, needMeta, batchSize, handler)
+	i.report("RangeMoreServices", start, err, -1)
+	return err
+}
+
+// RangeMoreInstances 实现 StreamStore
+func (i *instrumentedStore) RangeMoreInstances(tx Tx, mtime time.Time, firstUpdate bool, needMeta bool,
+	serviceID []string, batchSize int, handler BatchHandler[*model.Instance]) error {
+	start := time.Now()
+	err := NewStreamStore(i.store).RangeMoreInstances(tx, mtime, firstUpdate, needMeta, serviceID, batchSize, handler)
+	i.report("RangeMoreInstances", start, err, -1)
+	return err
+}
+
+// RangeMoreClients 实现 StreamStore
+func (i *instrumentedStore) RangeMoreClients(mtime time.Time, firstUpdate bool, batchSize int, handler BatchHandler[*model.Client]) error {
+	start := time.Now()
+	err := NewStreamStore(i.store).RangeMoreClients(mtime, firstUpdate, batchSize, handler)
+	i.report("RangeMoreClients", start, err, -1)
+	return err
+}
+
+// RangeMoreServiceContracts 实现 StreamStore
+func (i *instrumentedStore) RangeMoreServiceContracts(firstUpdate bool, mtime time.Time, batchSize int,
+	handler BatchHandler[*model.ServiceContract]) error {
+	start := time.Now()
+	err := NewStreamStore(i.store).RangeMoreServiceContracts(firstUpdate, mtime, batchSize, handler)
+	i.report("RangeMoreServiceContracts", start, err, -1)
+	return err
+}
+
+// RangeMoreGrayResources 实现 StreamStore
+func (i *instrumentedStore) RangeMoreGrayResources(firstUpdate bool, mtime time.Time, batchSize int,
+	handler BatchHandler[*model.GrayResource]) error {
+	start := time.Now()
+	err := NewStreamStore(i.store).RangeMoreGrayResources(firstUpdate, mtime, batchSize, handler)
+	i.report("RangeMoreGrayResources", start, err, -1)
+	return err
+}
+
+// RangeRoutingConfigsForCache 实现 StreamStore
+func (i *instrumentedStore) RangeRoutingConfigsForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler BatchHandler[*model.RoutingConfig]) error {
+	start := time.Now()
+	err := NewStreamStore(i.store).RangeRoutingConfigsForCache(mtime, firstUpdate, batchSize, handler)
+	i.report("RangeRoutingConfigsForCache", start, err, -1)
+	return err
+}
+
+// RangeRoutingConfigsV2ForCache 实现 StreamStore
+func (i *instrumentedStore) RangeRoutingConfigsV2ForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler BatchHandler[*model.RouterConfig]) error {
+	start := time.Now()
+	err := NewStreamStore(i.store).RangeRoutingConfigsV2ForCache(mtime, firstUpdate, batchSize, handler)
+	i.report("RangeRoutingConfigsV2ForCache", start, err, -1)
+	return err
+}
+
+// RangeRateLimitsForCache 实现 StreamStore
+func (i *instrumentedStore) RangeRateLimitsForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler BatchHandler[*model.RateLimit]) error {
+	start := time.Now()
+	err := NewStreamStore(i.store).RangeRateLimitsForCache(mtime, firstUpdate, batchSize, handler)
+	i.report("RangeRateLimitsForCache", start, err, -1)
+	return err
+}
+
+// RangeCircuitBreakerRulesForCache 实现 StreamStore
+func (i *instrumentedStore) RangeCircuitBreakerRulesForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler BatchHandler[*model.CircuitBreakerRule]) error {
+	start := time.Now()
+	err := NewStreamStore(i.store).RangeCircuitBreakerRulesForCache(mtime, firstUpdate, batchSize, handler)
+	i.report("RangeCircuitBreakerRulesForCache", start, err, -1)
+	return err
+}
+
+// RangeFaultDetectRulesForCache 实现 StreamStore
+func (i *instrumentedStore) RangeFaultDetectRulesForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler BatchHandler[*model.FaultDetectRule]) error {
+	start := time.Now()
+	err := NewStreamStore(i.store).RangeFaultDetectRulesForCache(mtime, firstUpdate, batchSize, handler)
+	i.report("RangeFaultDetectRulesForCache", start, err, -1)
+	return err
+}
+
+// RangeMoreConfigGroup 实现 StreamStore
+func (i *instrumentedStore) RangeMoreConfigGroup(firstUpdate bool, mtime time.Time, batchSize int,
+	handler BatchHandler[*model.ConfigFileGroup]) error {
+	start := time.Now()
+	err := NewStreamStore(i.store).RangeMoreConfigGroup(firstUpdate, mtime, batchSize, handler)
+	i.report("RangeMoreConfigGroup", start, err, -1)
+	return err
+}
+
+// RangeMoreReleaseFile 实现 StreamStore
+func (i *instrumentedStore) RangeMoreReleaseFile(firstUpdate bool, modifyTime time.Time, batchSize int,
+	handler BatchHandler[*model.ConfigFileRelease]) error {
+	start := time.Now()
+	err := NewStreamStore(i.store).RangeMoreReleaseFile(firstUpdate, modifyTime, batchSize, handler)
+	i.report("RangeMoreReleaseFile", start, err, -1)
+	return err
+}
+
+// RangeUsersForCache 实现 StreamStore
+func (i *instrumentedStore) RangeUsersForCache(mtime time.Time, firstUpdate bool, batchSize int, handler BatchHandler[*model.User]) error {
+	start := time.Now()
+	err := NewStreamStore(i.store).RangeUsersForCache(mtime, firstUpdate, batchSize, handler)
+	i.report("RangeUsersForCache", start, err, -1)
+	return err
+}
+
+// RangeGroupsForCache 实现 StreamStore
+func (i *instrumentedStore) RangeGroupsForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler BatchHandler[*model.UserGroup]) error {
+	start := time.Now()
+	err := NewStreamStore(i.store).RangeGroupsForCache(mtime, firstUpdate, batchSize, handler)
+	i.report("RangeGroupsForCache", start, err, -1)
+	return err
+}
+
+// RangeStrategyDetailsForCache 实现 StreamStore
+func (i *instrumentedStore) RangeStrategyDetailsForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler BatchHandler[*model.StrategyDetail]) error {
+	start := time.Now()
+	err := NewStreamStore(i.store).RangeStrategyDetailsForCache(mtime, firstUpdate, batchSize, handler)
+	i.report("RangeStrategyDetailsForCache", start, err, -1)
+	return err
+}
+
+// Watch 实现 WatchableStore
+func (i *instrumentedStore) Watch(ctx context.Context, kind ResourceKind, cursor Cursor) (Watcher, error) {
+	start := time.Now()
+	ret0, err := Watch(ctx, i.store, kind, cursor)
+	i.report("Watch", start, err, -1)
+	return ret0, err
+}
+
+// pageRows 返回分页查询的数据行数，查询失败时返回 -1
+func pageRows[T any](page *Page[T]) int {
+	if page == nil {
+		return -1
+	}
+	return len(page.Items)
+}
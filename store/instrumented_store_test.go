@@ -0,0 +1,73 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package store_test
+
+import (
+	"testing"
+
+	"github.com/polarismesh/polaris-plugin-api/observability/statis"
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/memory"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// recorder 记录上报的调用指标
+type recorder struct {
+	statis.Statis
+	metrics []statis.CallMetric
+}
+
+func (r *recorder) ReportCallMetrics(metric statis.CallMetric) {
+	r.metrics = append(r.metrics, metric)
+}
+
+func TestInstrumentedStore(t *testing.T) {
+	r := &recorder{}
+	s := store.NewInstrumentedStore(memory.New(), r)
+	addNamespace(t, s, "ns")
+	err := s.AddNamespace(&model.Namespace{Name: "ns"})
+	if store.Code(err) != store.DuplicateEntryErr {
+		t.Fatalf("expect DuplicateEntryErr, got %v", err)
+	}
+	if _, _, err := s.GetNamespaces(nil, 0, 10); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(r.metrics) != 3 {
+		t.Fatalf("expect 3 metrics, got %d", len(r.metrics))
+	}
+	for _, m := range r.metrics {
+		if m.Type != store.CallMetricTypeStore || m.Protocol != s.Name() || m.Times != 1 ||
+			m.TrafficDirection != statis.TrafficDirectionOutBound {
+			t.Fatalf("unexpected metric: %+v", m)
+		}
+	}
+	added, duplicated, listed := r.metrics[0], r.metrics[1], r.metrics[2]
+	if added.API != "AddNamespace" || !added.Success || added.Code != int(store.Ok) {
+		t.Fatalf("unexpected metric: %+v", added)
+	}
+	if _, ok := added.Labels[store.LabelRows]; ok {
+		t.Fatalf("write should not report rows: %+v", added)
+	}
+	if duplicated.Success || duplicated.Code != int(store.DuplicateEntryErr) {
+		t.Fatalf("unexpected metric: %+v", duplicated)
+	}
+	if listed.API != "GetNamespaces" || listed.Labels[store.LabelRows] != "1" {
+		t.Fatalf("unexpected metric: %+v", listed)
+	}
+}
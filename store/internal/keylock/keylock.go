@@ -0,0 +1,147 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package keylock 按照资源加锁的读写锁，供存储插件实现 store.Transaction 中的 LockNamespace、LockService 等行锁
+package keylock
+
+import (
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+)
+
+// Locks 按照资源加锁的读写锁
+type Locks struct {
+	lock  sync.Mutex
+	items map[string]*keyLock
+}
+
+// keyLock 单个资源的读写锁，released 在锁被释放时关闭，用于唤醒等待者
+type keyLock struct {
+	readers  int
+	writer   bool
+	waiters  int
+	released chan struct{}
+}
+
+// New 创建资源锁
+func New() *Locks {
+	return &Locks{items: make(map[string]*keyLock)}
+}
+
+// Acquire 获取 key 的排他锁或者共享锁，返回释放锁的方法，等待超过 timeout 时返回 store.DeadlockErr
+func (k *Locks) Acquire(key string, exclusive bool, timeout time.Duration) (func(), error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	k.lock.Lock()
+	for {
+		item, ok := k.items[key]
+		if !ok {
+			item = &keyLock{released: make(chan struct{})}
+			k.items[key] = item
+		}
+		if !item.writer && (!exclusive || item.readers == 0) {
+			if exclusive {
+				item.writer = true
+			} else {
+				item.readers++
+			}
+			k.lock.Unlock()
+			return func() { k.release(key, exclusive) }, nil
+		}
+		item.waiters++
+		released := item.released
+		k.lock.Unlock()
+
+		select {
+		case <-released:
+		case <-timer.C:
+			k.lock.Lock()
+			item.waiters--
+			k.cleanup(key, item)
+			k.lock.Unlock()
+			return nil, store.NewStatusError(store.DeadlockErr, "wait for lock timeout: "+key)
+		}
+		k.lock.Lock()
+		item.waiters--
+	}
+}
+
+func (k *Locks) release(key string, exclusive bool) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+
+	item, ok := k.items[key]
+	if !ok {
+		return
+	}
+	if exclusive {
+		item.writer = false
+	} else {
+		item.readers--
+	}
+	close(item.released)
+	item.released = make(chan struct{})
+	k.cleanup(key, item)
+}
+
+// cleanup 资源锁没有持有者以及等待者时删除，调用方需要持有 k.lock
+func (k *Locks) cleanup(key string, item *keyLock) {
+	if !item.writer && item.readers == 0 && item.waiters == 0 {
+		delete(k.items, key)
+	}
+}
+
+// Held 事务已经持有的资源锁，同一个事务重复加锁时不会阻塞，调用方需要保证并发安全
+type Held map[string]heldLock
+
+// heldLock 事务持有的资源锁
+type heldLock struct {
+	exclusive bool
+	unlock    func()
+}
+
+// Acquire 获取资源锁，等待超过 timeout 时返回 store.DeadlockErr；
+// 已经持有共享锁时再获取排他锁，会先释放共享锁再重新排队获取排他锁
+func (h *Held) Acquire(locks *Locks, key string, exclusive bool, timeout time.Duration) error {
+	if held, ok := (*h)[key]; ok {
+		if held.exclusive || !exclusive {
+			return nil
+		}
+		held.unlock()
+		delete(*h, key)
+	}
+	unlock, err := locks.Acquire(key, exclusive, timeout)
+	if err != nil {
+		return err
+	}
+	if *h == nil {
+		*h = make(Held)
+	}
+	(*h)[key] = heldLock{exclusive: exclusive, unlock: unlock}
+	return nil
+}
+
+// Release 释放所有的资源锁
+func (h *Held) Release() {
+	for _, item := range *h {
+		item.unlock()
+	}
+	*h = nil
+}
@@ -0,0 +1,120 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// LeaderElectionReader PollLeaderElection 轮询 leader 状态时使用的查询接口，AdminStore 满足该接口
+type LeaderElectionReader interface {
+	// IsLeader whether it is leader node
+	IsLeader(key string) bool
+	// ListLeaderElections list all leaderelection
+	ListLeaderElections() ([]*model.LeaderElection, error)
+}
+
+// LeaderPollingOption PollLeaderElection 的可选配置
+type LeaderPollingOption func(w *leaderPoller)
+
+// WithLeaderPollInterval 设置轮询 leader 状态的间隔
+func WithLeaderPollInterval(interval time.Duration) LeaderPollingOption {
+	return func(w *leaderPoller) {
+		if interval > 0 {
+			w.interval = interval
+		}
+	}
+}
+
+// WithLeaderPollMaxFailures 设置 ListLeaderElections 连续失败多少次后停止 LeaderWatcher
+func WithLeaderPollMaxFailures(n int) LeaderPollingOption {
+	return func(w *leaderPoller) {
+		if n > 0 {
+			w.maxFailures = n
+		}
+	}
+}
+
+// PollLeaderElection 通过轮询 IsLeader 以及 ListLeaderElections 监听 key 的 leader 变化，用于不支持原生推送的存储插件，
+// 轮询间隔通过 WithLeaderPollInterval 设置，默认为 1s；查询失败时等待下一次轮询重试，连续失败 3 次后停止
+func PollLeaderElection(ctx context.Context, s LeaderElectionReader, key string,
+	options ...LeaderPollingOption) (LeaderWatcher, error) {
+	if key == "" {
+		return nil, NewStatusError(EmptyParamsErr, "election key is required")
+	}
+
+	w := &leaderPoller{
+		poller: newPoller(ctx, defaultPollInterval, defaultPollMaxFailures),
+		store:  s,
+		key:    key,
+		events: make(chan LeaderChangeEvent),
+	}
+	for i := range options {
+		options[i](w)
+	}
+	go w.run(w.pollOnce, func() { close(w.events) })
+	return w, nil
+}
+
+// leaderPoller 轮询实现的 LeaderWatcher
+type leaderPoller struct {
+	*poller
+	store LeaderElectionReader
+	key   string
+	// last 最近一次推送的状态，sent 为 false 时表示还没有推送过
+	last   LeaderChangeEvent
+	sent   bool
+	events chan LeaderChangeEvent
+}
+
+// Events 实现 LeaderWatcher
+func (w *leaderPoller) Events() <-chan LeaderChangeEvent {
+	return w.events
+}
+
+// pollOnce 查询一次 leader 状态，与上一次推送的状态不同时推送，返回查询失败的错误
+func (w *leaderPoller) pollOnce() error {
+	event, err := w.current()
+	if err != nil || (w.sent && event == w.last) {
+		return err
+	}
+	select {
+	case w.events <- event:
+		w.last, w.sent = event, true
+	case <-w.ctx.Done():
+	}
+	return nil
+}
+
+// current 查询 key 当前的 leader 状态
+func (w *leaderPoller) current() (LeaderChangeEvent, error) {
+	elections, err := w.store.ListLeaderElections()
+	if err != nil {
+		return LeaderChangeEvent{}, err
+	}
+	event := LeaderChangeEvent{Key: w.key, Leader: w.store.IsLeader(w.key)}
+	for _, item := range elections {
+		if item.ElectKey == w.key && item.Valid {
+			event.LeaderHost = item.Host
+		}
+	}
+	return event, nil
+}
@@ -0,0 +1,128 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package store_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// fakeLeaderReader 可以修改 leader 状态的 LeaderElectionReader
+type fakeLeaderReader struct {
+	lock   sync.Mutex
+	leader bool
+	host   string
+	err    error
+}
+
+func (f *fakeLeaderReader) set(leader bool, host string, err error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.leader, f.host, f.err = leader, host, err
+}
+
+func (f *fakeLeaderReader) IsLeader(key string) bool {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.leader
+}
+
+func (f *fakeLeaderReader) ListLeaderElections() ([]*model.LeaderElection, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	return []*model.LeaderElection{
+		{ElectKey: "other", Host: "10.0.0.9", Valid: true},
+		{ElectKey: "cache", Host: f.host, Valid: f.host != ""},
+	}, nil
+}
+
+func nextLeaderEvent(t *testing.T, w store.LeaderWatcher) store.LeaderChangeEvent {
+	t.Helper()
+	select {
+	case event, ok := <-w.Events():
+		if !ok {
+			t.Fatalf("leader watcher stopped: %v", w.Err())
+		}
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for leader event")
+	}
+	return store.LeaderChangeEvent{}
+}
+
+func TestPollLeaderElection(t *testing.T) {
+	if _, err := store.PollLeaderElection(context.Background(), &fakeLeaderReader{}, ""); store.Code(err) != store.EmptyParamsErr {
+		t.Fatalf("expect EmptyParamsErr, got %v", err)
+	}
+
+	reader := &fakeLeaderReader{host: "10.0.0.1"}
+	w, err := store.PollLeaderElection(context.Background(), reader, "cache",
+		store.WithLeaderPollInterval(time.Millisecond), store.WithLeaderPollMaxFailures(1000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := store.LeaderChangeEvent{Key: "cache", LeaderHost: "10.0.0.1"}
+	if event := nextLeaderEvent(t, w); event != expect {
+		t.Fatalf("expect %+v, got %+v", expect, event)
+	}
+
+	// 状态没有变化时不推送，查询失败的次数未达到上限时不停止
+	reader.set(false, "10.0.0.1", errors.New("db down"))
+	time.Sleep(20 * time.Millisecond)
+	reader.set(true, "10.0.0.2", nil)
+	expect = store.LeaderChangeEvent{Key: "cache", Leader: true, LeaderHost: "10.0.0.2"}
+	if event := nextLeaderEvent(t, w); event != expect {
+		t.Fatalf("expect %+v, got %+v", expect, event)
+	}
+
+	w.Stop()
+	if _, ok := <-w.Events(); ok || w.Err() != nil {
+		t.Fatalf("expect closed events and nil error after Stop, got %v", w.Err())
+	}
+}
+
+func TestPollLeaderElectionFailures(t *testing.T) {
+	cause := errors.New("db down")
+	reader := &fakeLeaderReader{err: cause}
+	w, err := store.PollLeaderElection(context.Background(), reader, "cache",
+		store.WithLeaderPollInterval(time.Millisecond), store.WithLeaderPollMaxFailures(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case _, ok := <-w.Events():
+		if ok {
+			t.Fatal("unexpected leader event")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("leader watcher should stop after consecutive failures")
+	}
+	if !errors.Is(w.Err(), cause) {
+		t.Fatalf("expect %v, got %v", cause, w.Err())
+	}
+	w.Stop()
+}
@@ -0,0 +1,101 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package store
+
+import (
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// 以下函数实现了分布式锁租约的状态变化，存储插件在同一个事务中读取 saved、计算新的状态并写回即可实现 DistributedLockStore，
+// saved 为锁当前的状态，从未被获取过时为 nil；函数不会修改 saved
+
+// AcquireLease 计算 owner 获取锁之后的状态，锁空闲或者已经过期时递增 fencing token，owner 已经持有时只延长租约
+func AcquireLease(saved *model.DistributedLock, key string, owner string, ttl time.Duration,
+	now time.Time) (*model.DistributedLock, error) {
+	if err := checkLease(key, owner, ttl); err != nil {
+		return nil, err
+	}
+	if saved == nil {
+		return &model.DistributedLock{Key: key, Owner: owner, Token: 1, ExpireTime: now.Add(ttl),
+			CreateTime: now, ModifyTime: now}, nil
+	}
+	lock := *saved
+	if lock.Held(now) {
+		if lock.Owner != owner {
+			return nil, NewStatusError(DataConflictErr, "lock "+key+" is held by "+lock.Owner)
+		}
+	} else {
+		lock.Owner = owner
+		lock.Token++
+	}
+	lock.ExpireTime = now.Add(ttl)
+	lock.ModifyTime = now
+	return &lock, nil
+}
+
+// RenewLease 计算 owner 续约之后的状态，owner 不再持有锁时返回 DataConflictErr
+func RenewLease(saved *model.DistributedLock, key string, owner string, ttl time.Duration,
+	now time.Time) (*model.DistributedLock, error) {
+	if err := checkLease(key, owner, ttl); err != nil {
+		return nil, err
+	}
+	if saved == nil || !saved.Held(now) || saved.Owner != owner {
+		return nil, NewStatusError(DataConflictErr, "lock "+key+" is not held by "+owner)
+	}
+	lock := *saved
+	lock.ExpireTime = now.Add(ttl)
+	lock.ModifyTime = now
+	return &lock, nil
+}
+
+// ReleaseLease 计算 owner 释放锁之后的状态，锁已经过期或者释放时返回 nil，表示无需修改
+func ReleaseLease(saved *model.DistributedLock, key string, owner string, now time.Time) (*model.DistributedLock, error) {
+	if err := checkOwner(key, owner); err != nil {
+		return nil, err
+	}
+	if saved == nil || !saved.Held(now) {
+		return nil, nil
+	}
+	if saved.Owner != owner {
+		return nil, NewStatusError(DataConflictErr, "lock "+key+" is held by "+saved.Owner)
+	}
+	lock := *saved
+	lock.Owner = ""
+	lock.ExpireTime = now
+	lock.ModifyTime = now
+	return &lock, nil
+}
+
+func checkLease(key string, owner string, ttl time.Duration) error {
+	if err := checkOwner(key, owner); err != nil {
+		return err
+	}
+	if ttl <= 0 {
+		return NewStatusError(EmptyParamsErr, "lock ttl must be positive")
+	}
+	return nil
+}
+
+func checkOwner(key string, owner string) error {
+	if key == "" || owner == "" {
+		return NewStatusError(EmptyParamsErr, "lock key and owner are required")
+	}
+	return nil
+}
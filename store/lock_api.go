@@ -0,0 +1,80 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package store
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// DistributedLockStore 可选接口，支持基于租约的分布式锁的存储插件实现该接口
+type DistributedLockStore interface {
+	// AcquireLock 以 owner 的身份获取租约为 ttl 的分布式锁，owner 已经持有该锁时延长租约，
+	// 锁被其他持有者占用时返回 DataConflictErr
+	AcquireLock(key string, owner string, ttl time.Duration) (*model.DistributedLock, error)
+
+	// RenewLock 将 owner 持有的锁的租约延长为 ttl，锁已经过期或者被其他持有者占用时返回 DataConflictErr
+	RenewLock(key string, owner string, ttl time.Duration) (*model.DistributedLock, error)
+
+	// ReleaseLock 释放 owner 持有的锁，锁已经过期或者释放时直接返回，被其他持有者占用时返回 DataConflictErr
+	ReleaseLock(key string, owner string) error
+
+	// ListLocks 列出所有被持有并且没有过期的锁
+	ListLocks() ([]*model.DistributedLock, error)
+}
+
+// NewDistributedLockStore s 实现了 DistributedLockStore 时直接返回，否则返回的适配实现的所有方法都返回 NotSupportedErr，
+// 分布式锁无法基于 Store 的其他方法安全地模拟
+func NewDistributedLockStore(s Store) DistributedLockStore {
+	if ls, ok := s.(DistributedLockStore); ok {
+		return ls
+	}
+	return &unsupportedLockStore{store: s}
+}
+
+// unsupportedLockStore 没有实现 DistributedLockStore 的存储插件的适配实现
+type unsupportedLockStore struct {
+	store Store
+}
+
+// notSupported 返回 NotSupportedErr
+func (u *unsupportedLockStore) notSupported(method string) error {
+	return NewStatusError(NotSupportedErr, fmt.Sprintf("store %s does not support %s", u.store.Name(), method))
+}
+
+// AcquireLock 实现 DistributedLockStore
+func (u *unsupportedLockStore) AcquireLock(key string, owner string, ttl time.Duration) (*model.DistributedLock, error) {
+	return nil, u.notSupported("AcquireLock")
+}
+
+// RenewLock 实现 DistributedLockStore
+func (u *unsupportedLockStore) RenewLock(key string, owner string, ttl time.Duration) (*model.DistributedLock, error) {
+	return nil, u.notSupported("RenewLock")
+}
+
+// ReleaseLock 实现 DistributedLockStore
+func (u *unsupportedLockStore) ReleaseLock(key string, owner string) error {
+	return u.notSupported("ReleaseLock")
+}
+
+// ListLocks 实现 DistributedLockStore
+func (u *unsupportedLockStore) ListLocks() ([]*model.DistributedLock, error) {
+	return nil, u.notSupported("ListLocks")
+}
@@ -0,0 +1,90 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package store_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+func TestLeaseFencing(t *testing.T) {
+	now := time.Unix(1000, 0)
+	lock, err := store.AcquireLease(nil, "key", "a", time.Second, now)
+	if err != nil || lock.Token != 1 || lock.Owner != "a" {
+		t.Fatalf("unexpected lock %+v: %v", lock, err)
+	}
+	// 持有者重复获取或者续约时只延长租约，fencing token 不变
+	saved := *lock
+	lock, err = store.AcquireLease(&saved, "key", "a", time.Second, now.Add(500*time.Millisecond))
+	if err != nil || lock.Token != 1 || !lock.ExpireTime.Equal(now.Add(1500*time.Millisecond)) {
+		t.Fatalf("unexpected reacquired lock %+v: %v", lock, err)
+	}
+	if !saved.ExpireTime.Equal(now.Add(time.Second)) {
+		t.Fatal("AcquireLease should not modify saved")
+	}
+	lock, err = store.RenewLease(lock, "key", "a", time.Second, now.Add(time.Second))
+	if err != nil || lock.Token != 1 {
+		t.Fatalf("unexpected renewed lock %+v: %v", lock, err)
+	}
+	if _, err := store.AcquireLease(lock, "key", "b", time.Second, now.Add(time.Second)); store.Code(err) != store.DataConflictErr {
+		t.Fatalf("expect DataConflictErr while held by a, got %v", err)
+	}
+
+	// 租约过期后被其他持有者获取，fencing token 递增，原持有者不能再续约或者释放
+	expired := lock.ExpireTime
+	lock, err = store.AcquireLease(lock, "key", "b", time.Second, expired)
+	if err != nil || lock.Token != 2 || lock.Owner != "b" {
+		t.Fatalf("unexpected lock after expiry %+v: %v", lock, err)
+	}
+	if _, err := store.RenewLease(lock, "key", "a", time.Second, expired); store.Code(err) != store.DataConflictErr {
+		t.Fatalf("expect DataConflictErr when renewing a lost lock, got %v", err)
+	}
+	if _, err := store.ReleaseLease(lock, "key", "a", expired); store.Code(err) != store.DataConflictErr {
+		t.Fatalf("expect DataConflictErr when releasing a lost lock, got %v", err)
+	}
+
+	released, err := store.ReleaseLease(lock, "key", "b", expired)
+	if err != nil || released.Owner != "" || released.Held(expired) || released.Token != 2 {
+		t.Fatalf("unexpected released lock %+v: %v", released, err)
+	}
+	if again, err := store.ReleaseLease(released, "key", "b", expired); err != nil || again != nil {
+		t.Fatalf("releasing a free lock should be a no-op, got %+v: %v", again, err)
+	}
+	if lock, err = store.AcquireLease(released, "key", "a", time.Second, expired); err != nil || lock.Token != 3 {
+		t.Fatalf("expect token 3 after release, got %+v: %v", lock, err)
+	}
+}
+
+func TestLeaseInvalidParams(t *testing.T) {
+	now := time.Now()
+	saved := &model.DistributedLock{Key: "key", Owner: "a", Token: 1, ExpireTime: now.Add(time.Second)}
+	for _, err := range []error{
+		func() error { _, err := store.AcquireLease(nil, "", "a", time.Second, now); return err }(),
+		func() error { _, err := store.AcquireLease(nil, "key", "", time.Second, now); return err }(),
+		func() error { _, err := store.AcquireLease(nil, "key", "a", 0, now); return err }(),
+		func() error { _, err := store.RenewLease(saved, "key", "a", -time.Second, now); return err }(),
+		func() error { _, err := store.ReleaseLease(saved, "key", "", now); return err }(),
+	} {
+		if store.Code(err) != store.EmptyParamsErr {
+			t.Fatalf("expect EmptyParamsErr, got %v", err)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// StartLeaderElection 内存存储只有单个节点，发起选举的节点直接成为 leader
+func (s *memoryStore) StartLeaderElection(key string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := s.now()
+	if saved, ok := s.leaders[key]; ok {
+		saved.Host = s.host
+		saved.Valid = true
+		saved.ModifyTime = now
+		saved.Mtime = now.Unix()
+		return nil
+	}
+	s.leaders[key] = &model.LeaderElection{
+		ElectKey:   key,
+		Host:       s.host,
+		Ctime:      now.Unix(),
+		CreateTime: now,
+		Mtime:      now.Unix(),
+		ModifyTime: now,
+		Valid:      true,
+	}
+	return nil
+}
+
+// IsLeader whether it is leader node
+func (s *memoryStore) IsLeader(key string) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	saved, ok := s.leaders[key]
+	return ok && saved.Valid && saved.Host == s.host
+}
+
+// leaderPollInterval 内存存储的查询没有 IO 开销，使用较短的轮询间隔让 leader 变化尽快送达
+const leaderPollInterval = 100 * time.Millisecond
+
+// WatchLeaderElection 实现 store.LeaderWatchStore，使用较短的间隔轮询 leader 变化
+func (s *memoryStore) WatchLeaderElection(ctx context.Context, key string) (store.LeaderWatcher, error) {
+	return store.PollLeaderElection(ctx, s, key, store.WithLeaderPollInterval(leaderPollInterval))
+}
+
+// ListLeaderElections list all leaderelection
+func (s *memoryStore) ListLeaderElections() ([]*model.LeaderElection, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ret := make([]*model.LeaderElection, 0, len(s.leaders))
+	for _, item := range s.leaders {
+		copied := *item
+		ret = append(ret, &copied)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].ElectKey < ret[j].ElectKey
+	})
+	return ret, nil
+}
+
+// ReleaseLeaderElection force release leader status
+func (s *memoryStore) ReleaseLeaderElection(key string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	saved, ok := s.leaders[key]
+	if !ok || !saved.Valid {
+		return nil
+	}
+	now := s.now()
+	saved.Valid = false
+	saved.ModifyTime = now
+	saved.Mtime = now.Unix()
+	return nil
+}
+
+// BatchCleanDeletedInstances batch clean soft deleted instances which mtime time out
+func (s *memoryStore) BatchCleanDeletedInstances(timeout time.Duration, batchSize uint32) (uint32, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	deadline := s.now().Add(-timeout)
+	var count uint32
+	for id, ins := range s.instances {
+		if count >= batchSize {
+			break
+		}
+		if ins.Valid || ins.ModifyTime.After(deadline) {
+			continue
+		}
+		delete(s.instances, id)
+		count++
+	}
+	return count, nil
+}
+
+// GetUnHealthyInstances get unhealthy instances which mtime time out
+func (s *memoryStore) GetUnHealthyInstances(timeout time.Duration, limit uint32) ([]string, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	deadline := s.now().Add(-timeout)
+	ret := make([]string, 0)
+	for id, ins := range s.instances {
+		if uint32(len(ret)) >= limit {
+			break
+		}
+		if !ins.Valid || !ins.Proto.GetEnableHealthCheck().GetValue() || ins.Proto.GetHealthy().GetValue() {
+			continue
+		}
+		if ins.ModifyTime.After(deadline) {
+			continue
+		}
+		ret = append(ret, id)
+	}
+	return ret, nil
+}
+
+// BatchCleanDeletedClients batch clean soft deleted clients which mtime time out
+func (s *memoryStore) BatchCleanDeletedClients(timeout time.Duration, batchSize uint32) (uint32, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	deadline := s.now().Add(-timeout)
+	var count uint32
+	for id, client := range s.clients {
+		if count >= batchSize {
+			break
+		}
+		if client.Valid || client.ModifyTime.After(deadline) {
+			continue
+		}
+		delete(s.clients, id)
+		count++
+	}
+	return count, nil
+}
+
+// AcquireLock 获取分布式锁
+func (s *memoryStore) AcquireLock(key string, owner string, ttl time.Duration) (*model.DistributedLock, error) {
+	return s.updateLock(key, func(saved *model.DistributedLock, now time.Time) (*model.DistributedLock, error) {
+		return store.AcquireLease(saved, key, owner, ttl, now)
+	})
+}
+
+// RenewLock 续约分布式锁
+func (s *memoryStore) RenewLock(key string, owner string, ttl time.Duration) (*model.DistributedLock, error) {
+	return s.updateLock(key, func(saved *model.DistributedLock, now time.Time) (*model.DistributedLock, error) {
+		return store.RenewLease(saved, key, owner, ttl, now)
+	})
+}
+
+// ReleaseLock 释放分布式锁
+func (s *memoryStore) ReleaseLock(key string, owner string) error {
+	_, err := s.updateLock(key, func(saved *model.DistributedLock, now time.Time) (*model.DistributedLock, error) {
+		return store.ReleaseLease(saved, key, owner, now)
+	})
+	return err
+}
+
+// ListLocks 列出所有被持有并且没有过期的锁
+func (s *memoryStore) ListLocks() ([]*model.DistributedLock, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	now := s.now()
+	ret := make([]*model.DistributedLock, 0, len(s.distLocks))
+	for _, item := range s.distLocks {
+		if !item.Held(now) {
+			continue
+		}
+		copied := *item
+		ret = append(ret, &copied)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Key < ret[j].Key
+	})
+	return ret, nil
+}
+
+// updateLock 根据锁当前的状态计算新的状态并保存，next 返回 nil 时不做修改
+func (s *memoryStore) updateLock(key string,
+	next func(saved *model.DistributedLock, now time.Time) (*model.DistributedLock, error)) (*model.DistributedLock, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	lock, err := next(s.distLocks[key], s.now())
+	if err != nil || lock == nil {
+		return nil, err
+	}
+	s.distLocks[key] = lock
+	copied := *lock
+	return &copied, nil
+}
@@ -0,0 +1,667 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package memory
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// AddUser Create a user
+func (s *memoryStore) AddUser(user *model.User) error {
+	if user == nil || user.ID == "" || user.Name == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "add user missing some params")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if old, ok := s.users[user.ID]; ok && old.Valid {
+		return store.NewStatusError(store.DuplicateEntryErr, "user already exists: "+user.ID)
+	}
+	if s.findUserByName(user.Name, user.Owner) != nil {
+		return store.NewStatusError(store.DuplicateEntryErr, "user name already exists: "+user.Name)
+	}
+	now := s.now()
+	saved := *user
+	saved.Valid = true
+	saved.CreateTime = now
+	saved.ModifyTime = now
+	s.users[user.ID] = &saved
+	return nil
+}
+
+// UpdateUser Update user
+func (s *memoryStore) UpdateUser(user *model.User) error {
+	if user == nil || user.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "update user missing id")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	saved, ok := s.users[user.ID]
+	if !ok || !saved.Valid {
+		return store.NewStatusError(store.NotFoundUser, "user not found: "+user.ID)
+	}
+	saved.Password = user.Password
+	saved.Mobile = user.Mobile
+	saved.Email = user.Email
+	saved.Token = user.Token
+	saved.TokenEnable = user.TokenEnable
+	saved.Comment = user.Comment
+	saved.ModifyTime = s.now()
+	return nil
+}
+
+// DeleteUser delete users, the user will also be removed from all user groups
+func (s *memoryStore) DeleteUser(user *model.User) error {
+	if user == nil || user.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "delete user missing id")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	saved, ok := s.users[user.ID]
+	if !ok || !saved.Valid {
+		return nil
+	}
+	now := s.now()
+	saved.Valid = false
+	saved.ModifyTime = now
+	for _, group := range s.groups {
+		if !group.Valid || !containsString(group.UserIds, user.ID) {
+			continue
+		}
+		group.UserIds = removeStrings(group.UserIds, []string{user.ID})
+		group.ModifyTime = now
+	}
+	return nil
+}
+
+// GetSubCount Number of getting a child account
+func (s *memoryStore) GetSubCount(user *model.User) (uint32, error) {
+	if user == nil {
+		return 0, store.NewStatusError(store.EmptyParamsErr, "get sub count missing user")
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var count uint32
+	for _, item := range s.users {
+		if item.Valid && item.Owner == user.ID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// GetUser Obtain user
+func (s *memoryStore) GetUser(id string) (*model.User, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	saved, ok := s.users[id]
+	if !ok || !saved.Valid {
+		return nil, nil
+	}
+	ret := *saved
+	return &ret, nil
+}
+
+// GetUserByName Get a unique user according to Name + Owner
+func (s *memoryStore) GetUserByName(name, ownerId string) (*model.User, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	saved := s.findUserByName(name, ownerId)
+	if saved == nil {
+		return nil, nil
+	}
+	ret := *saved
+	return &ret, nil
+}
+
+// GetUserByIds Get users according to USER IDS batch
+func (s *memoryStore) GetUserByIds(ids []string) ([]*model.User, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ret := make([]*model.User, 0, len(ids))
+	for _, id := range ids {
+		saved, ok := s.users[id]
+		if !ok || !saved.Valid {
+			continue
+		}
+		copied := *saved
+		ret = append(ret, &copied)
+	}
+	return ret, nil
+}
+
+// GetUsers Query user list, group_id 用于查询用户组下的用户
+func (s *memoryStore) GetUsers(filters map[string]string, offset uint32, limit uint32) (uint32, []*model.User, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var members []string
+	if groupID, ok := filters["group_id"]; ok {
+		if group, exist := s.groups[groupID]; exist && group.Valid {
+			members = group.UserIds
+		}
+	}
+	ret := make([]*model.User, 0)
+	for _, item := range s.users {
+		if !item.Valid {
+			continue
+		}
+		if _, ok := filters["group_id"]; ok && !containsString(members, item.ID) {
+			continue
+		}
+		if !matchFilters(filters, userGetters(item)) {
+			continue
+		}
+		copied := *item
+		ret = append(ret, &copied)
+	}
+	sortByModifyTime(ret, func(u *model.User) time.Time { return u.ModifyTime },
+		func(u *model.User) string { return u.ID })
+	return uint32(len(ret)), paginate(ret, offset, limit), nil
+}
+
+// GetUsersForCache Used to refresh user cache
+func (s *memoryStore) GetUsersForCache(mtime time.Time, firstUpdate bool) ([]*model.User, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ret := make([]*model.User, 0)
+	for _, item := range s.users {
+		if !isIncrement(item.ModifyTime, mtime, item.Valid, firstUpdate) {
+			continue
+		}
+		copied := *item
+		ret = append(ret, &copied)
+	}
+	return ret, nil
+}
+
+// findUserByName 查找有效的用户，调用方需要持有锁
+func (s *memoryStore) findUserByName(name, owner string) *model.User {
+	for _, item := range s.users {
+		if item.Valid && item.Name == name && item.Owner == owner {
+			return item
+		}
+	}
+	return nil
+}
+
+// AddGroup Add a user group
+func (s *memoryStore) AddGroup(group *model.UserGroup) error {
+	if group == nil || group.ID == "" || group.Name == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "add user group missing some params")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if old, ok := s.groups[group.ID]; ok && old.Valid {
+		return store.NewStatusError(store.DuplicateEntryErr, "user group already exists: "+group.ID)
+	}
+	now := s.now()
+	saved := cloneUserGroup(group)
+	saved.Valid = true
+	saved.CreateTime = now
+	saved.ModifyTime = now
+	s.groups[group.ID] = saved
+	return nil
+}
+
+// UpdateGroup Update user group
+func (s *memoryStore) UpdateGroup(group *model.ModifyUserGroup) error {
+	if group == nil || group.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "update user group missing id")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	saved, ok := s.groups[group.ID]
+	if !ok || !saved.Valid {
+		return store.NewStatusError(store.NotFoundUserGroup, "user group not found: "+group.ID)
+	}
+	saved.Token = group.Token
+	saved.TokenEnable = group.TokenEnable
+	saved.Comment = group.Comment
+	saved.UserIds = removeStrings(saved.UserIds, group.RemoveUserIds)
+	for _, id := range group.AddUserIds {
+		if !containsString(saved.UserIds, id) {
+			saved.UserIds = append(saved.UserIds, id)
+		}
+	}
+	saved.ModifyTime = s.now()
+	return nil
+}
+
+// DeleteGroup Delete user group
+func (s *memoryStore) DeleteGroup(group *model.UserGroup) error {
+	if group == nil || group.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "delete user group missing id")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	saved, ok := s.groups[group.ID]
+	if !ok || !saved.Valid {
+		return nil
+	}
+	saved.Valid = false
+	saved.ModifyTime = s.now()
+	return nil
+}
+
+// GetGroup Get user group details
+func (s *memoryStore) GetGroup(id string) (*model.UserGroup, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	saved, ok := s.groups[id]
+	if !ok || !saved.Valid {
+		return nil, nil
+	}
+	return cloneUserGroup(saved), nil
+}
+
+// GetGroupByName Get user groups according to Name and Owner
+func (s *memoryStore) GetGroupByName(name, owner string) (*model.UserGroup, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	for _, item := range s.groups {
+		if item.Valid && item.Name == name && item.Owner == owner {
+			return cloneUserGroup(item), nil
+		}
+	}
+	return nil, nil
+}
+
+// GetGroups Get a list of user groups, user_id 用于查询用户所在的用户组
+func (s *memoryStore) GetGroups(filters map[string]string, offset uint32, limit uint32) (
+	uint32, []*model.UserGroup, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ret := make([]*model.UserGroup, 0)
+	for _, item := range s.groups {
+		if !item.Valid {
+			continue
+		}
+		if userID, ok := filters["user_id"]; ok && !containsString(item.UserIds, userID) {
+			continue
+		}
+		getters := map[string]func() string{
+			"id":    func() string { return item.ID },
+			"name":  func() string { return item.Name },
+			"owner": func() string { return item.Owner },
+		}
+		if !matchFilters(filters, getters) {
+			continue
+		}
+		ret = append(ret, cloneUserGroup(item))
+	}
+	sortByModifyTime(ret, func(g *model.UserGroup) time.Time { return g.ModifyTime },
+		func(g *model.UserGroup) string { return g.ID })
+	return uint32(len(ret)), paginate(ret, offset, limit), nil
+}
+
+// GetGroupsForCache Refresh of getting user groups for cache
+func (s *memoryStore) GetGroupsForCache(mtime time.Time, firstUpdate bool) ([]*model.UserGroup, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ret := make([]*model.UserGroup, 0)
+	for _, item := range s.groups {
+		if !isIncrement(item.ModifyTime, mtime, item.Valid, firstUpdate) {
+			continue
+		}
+		ret = append(ret, cloneUserGroup(item))
+	}
+	return ret, nil
+}
+
+func cloneUserGroup(group *model.UserGroup) *model.UserGroup {
+	ret := *group
+	ret.UserIds = append([]string(nil), group.UserIds...)
+	return &ret
+}
+
+// AddStrategy Create authentication strategy
+func (s *memoryStore) AddStrategy(strategy *model.StrategyDetail) error {
+	if strategy == nil || strategy.ID == "" || strategy.Name == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "add strategy missing some params")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if old, ok := s.strategies[strategy.ID]; ok && old.Valid {
+		return store.NewStatusError(store.DuplicateEntryErr, "strategy already exists: "+strategy.ID)
+	}
+	now := s.now()
+	saved := cloneStrategy(strategy)
+	saved.Valid = true
+	saved.CreateTime = now
+	saved.ModifyTime = now
+	for i := range saved.Principals {
+		saved.Principals[i].StrategyID = saved.ID
+	}
+	for i := range saved.Resources {
+		saved.Resources[i].StrategyID = saved.ID
+	}
+	s.strategies[strategy.ID] = saved
+	return nil
+}
+
+// UpdateStrategy Update authentication strategy
+func (s *memoryStore) UpdateStrategy(strategy *model.ModifyStrategyDetail) error {
+	return s.updateStrategy(strategy, nil)
+}
+
+// updateStrategy 更新鉴权策略，expectRevision 不为空时只有当前的 Revision 与其一致才会更新
+func (s *memoryStore) updateStrategy(strategy *model.ModifyStrategyDetail, expectRevision *string) error {
+	if strategy == nil || strategy.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "update strategy missing id")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	saved, ok := s.strategies[strategy.ID]
+	if !ok || !saved.Valid {
+		return store.NewStatusError(store.NotFoundResource, "strategy not found: "+strategy.ID)
+	}
+	if err := checkRevision("strategy", strategy.ID, saved.Revision, expectRevision); err != nil {
+		return err
+	}
+	if strategy.Name != "" {
+		saved.Name = strategy.Name
+	}
+	saved.Action = strategy.Action
+	saved.Comment = strategy.Comment
+	saved.Principals = removePrincipals(saved.Principals, strategy.RemovePrincipals)
+	for _, item := range strategy.AddPrincipals {
+		item.StrategyID = saved.ID
+		if !containsPrincipal(saved.Principals, item) {
+			saved.Principals = append(saved.Principals, item)
+		}
+	}
+	saved.Resources = removeResources(saved.Resources, strategy.RemoveResources)
+	for _, item := range strategy.AddResources {
+		item.StrategyID = saved.ID
+		if !containsResource(saved.Resources, item) {
+			saved.Resources = append(saved.Resources, item)
+		}
+	}
+	if strategy.Revision != "" {
+		saved.Revision = strategy.Revision
+	}
+	saved.ModifyTime = s.now()
+	return nil
+}
+
+// DeleteStrategy Delete authentication strategy
+func (s *memoryStore) DeleteStrategy(id string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	saved, ok := s.strategies[id]
+	if !ok || !saved.Valid {
+		return nil
+	}
+	saved.Valid = false
+	saved.ModifyTime = s.now()
+	return nil
+}
+
+// LooseAddStrategyResources 添加策略的资源，忽略已经存在的资源
+func (s *memoryStore) LooseAddStrategyResources(resources []model.StrategyResource) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := s.now()
+	for _, item := range resources {
+		saved, ok := s.strategies[item.StrategyID]
+		if !ok || !saved.Valid {
+			continue
+		}
+		if containsResource(saved.Resources, item) {
+			continue
+		}
+		saved.Resources = append(saved.Resources, item)
+		saved.ModifyTime = now
+	}
+	return nil
+}
+
+// RemoveStrategyResources 清理所有策略中关联的对应资源
+func (s *memoryStore) RemoveStrategyResources(resources []model.StrategyResource) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := s.now()
+	for _, saved := range s.strategies {
+		remain := make([]model.StrategyResource, 0, len(saved.Resources))
+		for _, res := range saved.Resources {
+			matched := false
+			for _, item := range resources {
+				if res.ResType == item.ResType && res.ResID == item.ResID {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				remain = append(remain, res)
+			}
+		}
+		if len(remain) != len(saved.Resources) {
+			saved.Resources = remain
+			saved.ModifyTime = now
+		}
+	}
+	return nil
+}
+
+// GetStrategyResources Gets a Principal's corresponding resource ID data information
+func (s *memoryStore) GetStrategyResources(principalId string, principalRole string) (
+	[]model.StrategyResource, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ret := make([]model.StrategyResource, 0)
+	for _, saved := range s.strategies {
+		if !saved.Valid || !hasPrincipal(saved.Principals, principalId, principalRole) {
+			continue
+		}
+		ret = append(ret, saved.Resources...)
+	}
+	return ret, nil
+}
+
+// GetDefaultStrategyDetailByPrincipal Get a default policy for a Principal
+func (s *memoryStore) GetDefaultStrategyDetailByPrincipal(principalId string,
+	principalType string) (*model.StrategyDetail, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	for _, saved := range s.strategies {
+		if saved.Valid && saved.Default && hasPrincipal(saved.Principals, principalId, principalType) {
+			return cloneStrategy(saved), nil
+		}
+	}
+	return nil, nil
+}
+
+// GetStrategyDetail Get strategy details
+func (s *memoryStore) GetStrategyDetail(id string) (*model.StrategyDetail, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	saved, ok := s.strategies[id]
+	if !ok || !saved.Valid {
+		return nil, nil
+	}
+	return cloneStrategy(saved), nil
+}
+
+// GetStrategies Get a list of strategies
+func (s *memoryStore) GetStrategies(filters map[string]string, offset uint32, limit uint32) (uint32,
+	[]*model.StrategyDetail, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ret := make([]*model.StrategyDetail, 0)
+	for _, item := range s.strategies {
+		if !item.Valid {
+			continue
+		}
+		if principalID, ok := filters["principal_id"]; ok &&
+			!hasPrincipal(item.Principals, principalID, filters["principal_type"]) {
+			continue
+		}
+		if resID, ok := filters["res_id"]; ok && !hasResource(item.Resources, resID, filters["res_type"]) {
+			continue
+		}
+		if !matchFilters(filters, strategyGetters(item)) {
+			continue
+		}
+		ret = append(ret, cloneStrategy(item))
+	}
+	sortByModifyTime(ret, func(d *model.StrategyDetail) time.Time { return d.ModifyTime },
+		func(d *model.StrategyDetail) string { return d.ID })
+	return uint32(len(ret)), paginate(ret, offset, limit), nil
+}
+
+// GetStrategyDetailsForCache Used to refresh policy cache
+func (s *memoryStore) GetStrategyDetailsForCache(mtime time.Time, firstUpdate bool) (
+	[]*model.StrategyDetail, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ret := make([]*model.StrategyDetail, 0)
+	for _, item := range s.strategies {
+		if !isIncrement(item.ModifyTime, mtime, item.Valid, firstUpdate) {
+			continue
+		}
+		ret = append(ret, cloneStrategy(item))
+	}
+	return ret, nil
+}
+
+func cloneStrategy(strategy *model.StrategyDetail) *model.StrategyDetail {
+	ret := *strategy
+	ret.Principals = append([]model.Principal(nil), strategy.Principals...)
+	ret.Resources = append([]model.StrategyResource(nil), strategy.Resources...)
+	return &ret
+}
+
+// hasPrincipal principalRole 为空时只比较 principalId
+func hasPrincipal(principals []model.Principal, principalID, principalRole string) bool {
+	for _, item := range principals {
+		if item.PrincipalID == principalID && (principalRole == "" || item.PrincipalRole == principalRole) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasResource resType 为空时只比较 resID
+func hasResource(resources []model.StrategyResource, resID, resType string) bool {
+	for _, item := range resources {
+		if item.ResID == resID && (resType == "" || strconv.Itoa(int(item.ResType)) == resType) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsPrincipal(principals []model.Principal, target model.Principal) bool {
+	return hasPrincipal(principals, target.PrincipalID, target.PrincipalRole)
+}
+
+func containsResource(resources []model.StrategyResource, target model.StrategyResource) bool {
+	for _, item := range resources {
+		if item.ResType == target.ResType && item.ResID == target.ResID {
+			return true
+		}
+	}
+	return false
+}
+
+func removePrincipals(principals []model.Principal, removed []model.Principal) []model.Principal {
+	ret := make([]model.Principal, 0, len(principals))
+	for _, item := range principals {
+		if !containsPrincipal(removed, item) {
+			ret = append(ret, item)
+		}
+	}
+	return ret
+}
+
+func removeResources(resources []model.StrategyResource, removed []model.StrategyResource) []model.StrategyResource {
+	ret := make([]model.StrategyResource, 0, len(resources))
+	for _, item := range resources {
+		if !containsResource(removed, item) {
+			ret = append(ret, item)
+		}
+	}
+	return ret
+}
+
+func removeStrings(items []string, removed []string) []string {
+	ret := make([]string, 0, len(items))
+	for _, item := range items {
+		if !containsString(removed, item) {
+			ret = append(ret, item)
+		}
+	}
+	return ret
+}
+
+func userGetters(item *model.User) map[string]func() string {
+	return map[string]func() string{
+		"id":     func() string { return item.ID },
+		"name":   func() string { return item.Name },
+		"owner":  func() string { return item.Owner },
+		"source": func() string { return item.Source },
+		"type":   func() string { return item.Type },
+	}
+}
+
+func strategyGetters(item *model.StrategyDetail) map[string]func() string {
+	return map[string]func() string{
+		"id":      func() string { return item.ID },
+		"name":    func() string { return item.Name },
+		"owner":   func() string { return item.Owner },
+		"default": func() string { return strconv.FormatBool(item.Default) },
+	}
+}
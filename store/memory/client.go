@@ -0,0 +1,157 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package memory
+
+import (
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// BatchAddClients insert the client info, existing clients will be overwritten
+func (s *memoryStore) BatchAddClients(clients []*model.Client) error {
+	for _, client := range clients {
+		if client == nil || client.Proto.GetId().GetValue() == "" {
+			return store.NewStatusError(store.EmptyParamsErr, "add client missing id")
+		}
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := s.now()
+	for _, client := range clients {
+		saved := cloneClient(client)
+		saved.Valid = true
+		saved.ModifyTime = now
+		if old, ok := s.clients[client.Proto.GetId().GetValue()]; ok && old.Valid {
+			saved.CreateTime = old.CreateTime
+		} else {
+			saved.CreateTime = now
+		}
+		s.clients[client.Proto.GetId().GetValue()] = saved
+	}
+	return nil
+}
+
+// BatchDeleteClients delete the client info, only mark valid as false
+func (s *memoryStore) BatchDeleteClients(ids []string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := s.now()
+	for _, id := range ids {
+		saved, ok := s.clients[id]
+		if !ok || !saved.Valid {
+			continue
+		}
+		saved.Valid = false
+		saved.ModifyTime = now
+	}
+	return nil
+}
+
+// GetMoreClients 根据mtime获取增量clients
+func (s *memoryStore) GetMoreClients(mtime time.Time, firstUpdate bool) (map[string]*model.Client, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ret := make(map[string]*model.Client)
+	for id, client := range s.clients {
+		if !isIncrement(client.ModifyTime, mtime, client.Valid, firstUpdate) {
+			continue
+		}
+		ret[id] = cloneClient(client)
+	}
+	return ret, nil
+}
+
+func cloneClient(client *model.Client) *model.Client {
+	ret := *client
+	if client.Proto != nil {
+		ret.Proto = proto.Clone(client.Proto).(*apiservice.Client)
+	}
+	return &ret
+}
+
+// CleanGrayResource 删除灰度资源，实际是把valid置为false
+func (s *memoryStore) CleanGrayResource(tx store.Tx, data *model.GrayResource) error {
+	if data == nil || data.Name == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "clean gray resource missing name")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	saved, ok := s.grayResources[data.Name]
+	if !ok || !saved.Valid {
+		return nil
+	}
+	snapshot(mtx, s.grayResources, data.Name)
+	saved.Valid = false
+	saved.ModifyBy = data.ModifyBy
+	saved.ModifyTime = s.now()
+	return nil
+}
+
+// CreateGrayResourceTx 创建灰度资源，已存在的同名资源会被覆盖
+func (s *memoryStore) CreateGrayResourceTx(tx store.Tx, data *model.GrayResource) error {
+	if data == nil || data.Name == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "create gray resource missing name")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	snapshot(mtx, s.grayResources, data.Name)
+	now := s.now()
+	saved := *data
+	saved.Valid = true
+	saved.CreateTime = now
+	saved.ModifyTime = now
+	s.grayResources[data.Name] = &saved
+	return nil
+}
+
+// GetMoreGrayResouces 获取增量的灰度资源
+func (s *memoryStore) GetMoreGrayResouces(firstUpdate bool, mtime time.Time) ([]*model.GrayResource, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ret := make([]*model.GrayResource, 0)
+	for _, item := range s.grayResources {
+		if !isIncrement(item.ModifyTime, mtime, item.Valid, firstUpdate) {
+			continue
+		}
+		copied := *item
+		ret = append(ret, &copied)
+	}
+	return ret, nil
+}
@@ -0,0 +1,201 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package memory
+
+import (
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// LockConfigFile 加锁配置文件，内存存储的写操作本身是串行的，这里只返回有效的配置文件
+func (s *memoryStore) LockConfigFile(tx store.Tx, file *model.ConfigFileKey) (*model.ConfigFile, error) {
+	if file == nil {
+		return nil, store.NewStatusError(store.EmptyParamsErr, "lock config file missing file key")
+	}
+	return s.GetConfigFileTx(tx, file.Namespace, file.Group, file.Name)
+}
+
+// CreateConfigFileTx 创建配置文件
+func (s *memoryStore) CreateConfigFileTx(tx store.Tx, file *model.ConfigFile) error {
+	if file == nil || file.Namespace == "" || file.Group == "" || file.Name == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "create config file missing some params")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	key := configFileKey(file.Namespace, file.Group, file.Name)
+	if old, ok := s.configFiles[key]; ok && old.Valid {
+		return store.NewStatusError(store.DuplicateEntryErr, "config file already exists: "+key)
+	}
+	snapshot(mtx, s.configFiles, key)
+	now := s.now()
+	saved := cloneConfigFile(file)
+	saved.Id = s.nextID()
+	saved.Valid = true
+	saved.CreateTime = now
+	saved.ModifyTime = now
+	s.configFiles[key] = saved
+	return nil
+}
+
+// GetConfigFile 获取配置文件
+func (s *memoryStore) GetConfigFile(namespace, group, name string) (*model.ConfigFile, error) {
+	return s.GetConfigFileTx(nil, namespace, group, name)
+}
+
+// GetConfigFileTx 获取配置文件
+func (s *memoryStore) GetConfigFileTx(tx store.Tx, namespace, group, name string) (*model.ConfigFile, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	saved, ok := s.configFiles[configFileKey(namespace, group, name)]
+	if !ok || !saved.Valid {
+		return nil, nil
+	}
+	return cloneConfigFile(saved), nil
+}
+
+// QueryConfigFiles 翻页查询配置文件，group、name可为模糊匹配
+func (s *memoryStore) QueryConfigFiles(filter map[string]string, offset uint32, limit uint32) (
+	uint32, []*model.ConfigFile, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ret := make([]*model.ConfigFile, 0)
+	for _, item := range s.configFiles {
+		if !item.Valid {
+			continue
+		}
+		if !matchFilters(filter, configFileGetters(item)) {
+			continue
+		}
+		ret = append(ret, cloneConfigFile(item))
+	}
+	sortByModifyTime(ret, func(f *model.ConfigFile) time.Time { return f.ModifyTime },
+		func(f *model.ConfigFile) string { return configFileKey(f.Namespace, f.Group, f.Name) })
+	return uint32(len(ret)), paginate(ret, offset, limit), nil
+}
+
+// UpdateConfigFileTx 更新配置文件
+func (s *memoryStore) UpdateConfigFileTx(tx store.Tx, file *model.ConfigFile) error {
+	if file == nil || file.Namespace == "" || file.Group == "" || file.Name == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "update config file missing some params")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	key := configFileKey(file.Namespace, file.Group, file.Name)
+	saved, ok := s.configFiles[key]
+	if !ok || !saved.Valid {
+		return store.NewStatusError(store.AffectedRowsNotMatch, "config file not found: "+key)
+	}
+	snapshot(mtx, s.configFiles, key)
+	saved.OriginContent = file.OriginContent
+	saved.Content = file.Content
+	saved.Comment = file.Comment
+	saved.Format = file.Format
+	saved.Metadata = cloneStrings(file.Metadata)
+	saved.Encrypt = file.Encrypt
+	saved.EncryptAlgo = file.EncryptAlgo
+	saved.Status = file.Status
+	saved.ModifyBy = file.ModifyBy
+	saved.ModifyTime = s.now()
+	return nil
+}
+
+// DeleteConfigFileTx 删除配置文件，实际是把valid置为false
+func (s *memoryStore) DeleteConfigFileTx(tx store.Tx, namespace, group, name string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	key := configFileKey(namespace, group, name)
+	saved, ok := s.configFiles[key]
+	if !ok || !saved.Valid {
+		return nil
+	}
+	snapshot(mtx, s.configFiles, key)
+	saved.Valid = false
+	saved.ModifyTime = s.now()
+	return nil
+}
+
+// CountConfigFiles 获取一个配置文件组下的文件数量
+func (s *memoryStore) CountConfigFiles(namespace, group string) (uint64, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var count uint64
+	for _, item := range s.configFiles {
+		if item.Valid && item.Namespace == namespace && item.Group == group {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// CountConfigFileEachGroup 统计 namespace.group 下的配置文件数量
+func (s *memoryStore) CountConfigFileEachGroup() (map[string]map[string]int64, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ret := make(map[string]map[string]int64)
+	for _, item := range s.configFiles {
+		if !item.Valid {
+			continue
+		}
+		if _, ok := ret[item.Namespace]; !ok {
+			ret[item.Namespace] = map[string]int64{}
+		}
+		ret[item.Namespace][item.Group]++
+	}
+	return ret, nil
+}
+
+func configFileKey(namespace, group, name string) string {
+	return model.ConfigFileKey{Namespace: namespace, Group: group, Name: name}.String()
+}
+
+func cloneConfigFile(file *model.ConfigFile) *model.ConfigFile {
+	ret := *file
+	ret.Metadata = cloneStrings(file.Metadata)
+	return &ret
+}
+
+func configFileGetters(item *model.ConfigFile) map[string]func() string {
+	return map[string]func() string{
+		"namespace": func() string { return item.Namespace },
+		"group":     func() string { return item.Group },
+		"name":      func() string { return item.Name },
+	}
+}
@@ -0,0 +1,138 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package memory
+
+import (
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// CreateConfigFileGroup 创建配置文件组
+func (s *memoryStore) CreateConfigFileGroup(fileGroup *model.ConfigFileGroup) (*model.ConfigFileGroup, error) {
+	if fileGroup == nil || fileGroup.Namespace == "" || fileGroup.Name == "" {
+		return nil, store.NewStatusError(store.EmptyParamsErr, "create config file group missing some params")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	key := configGroupKey(fileGroup.Namespace, fileGroup.Name)
+	if old, ok := s.configGroups[key]; ok && old.Valid {
+		return nil, store.NewStatusError(store.DuplicateEntryErr, "config file group already exists: "+key)
+	}
+	now := s.now()
+	saved := cloneConfigFileGroup(fileGroup)
+	saved.Id = s.nextID()
+	saved.Valid = true
+	saved.CreateTime = now
+	saved.ModifyTime = now
+	s.configGroups[key] = saved
+	return cloneConfigFileGroup(saved), nil
+}
+
+// UpdateConfigFileGroup 更新配置文件组
+func (s *memoryStore) UpdateConfigFileGroup(fileGroup *model.ConfigFileGroup) error {
+	if fileGroup == nil || fileGroup.Namespace == "" || fileGroup.Name == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "update config file group missing some params")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	key := configGroupKey(fileGroup.Namespace, fileGroup.Name)
+	saved, ok := s.configGroups[key]
+	if !ok || !saved.Valid {
+		return store.NewStatusError(store.AffectedRowsNotMatch, "config file group not found: "+key)
+	}
+	saved.Comment = fileGroup.Comment
+	saved.Owner = fileGroup.Owner
+	saved.Business = fileGroup.Business
+	saved.Department = fileGroup.Department
+	saved.Metadata = cloneStrings(fileGroup.Metadata)
+	saved.ModifyBy = fileGroup.ModifyBy
+	saved.Revision = fileGroup.Revision
+	saved.ModifyTime = s.now()
+	return nil
+}
+
+// GetConfigFileGroup 获取单个配置文件组
+func (s *memoryStore) GetConfigFileGroup(namespace, name string) (*model.ConfigFileGroup, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	saved, ok := s.configGroups[configGroupKey(namespace, name)]
+	if !ok || !saved.Valid {
+		return nil, nil
+	}
+	return cloneConfigFileGroup(saved), nil
+}
+
+// DeleteConfigFileGroup 删除配置文件组，实际是把valid置为false
+func (s *memoryStore) DeleteConfigFileGroup(namespace, name string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	saved, ok := s.configGroups[configGroupKey(namespace, name)]
+	if !ok || !saved.Valid {
+		return nil
+	}
+	saved.Valid = false
+	saved.ModifyTime = s.now()
+	return nil
+}
+
+// GetMoreConfigGroup 获取增量的配置分组
+func (s *memoryStore) GetMoreConfigGroup(firstUpdate bool, mtime time.Time) ([]*model.ConfigFileGroup, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ret := make([]*model.ConfigFileGroup, 0)
+	for _, item := range s.configGroups {
+		if !isIncrement(item.ModifyTime, mtime, item.Valid, firstUpdate) {
+			continue
+		}
+		ret = append(ret, cloneConfigFileGroup(item))
+	}
+	return ret, nil
+}
+
+// CountConfigGroups 获取一个命名空间下的配置分组数量
+func (s *memoryStore) CountConfigGroups(namespace string) (uint64, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var count uint64
+	for _, item := range s.configGroups {
+		if item.Valid && item.Namespace == namespace {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func configGroupKey(namespace, name string) string {
+	return namespace + "@" + name
+}
+
+func cloneConfigFileGroup(group *model.ConfigFileGroup) *model.ConfigFileGroup {
+	ret := *group
+	ret.Metadata = cloneStrings(group.Metadata)
+	return &ret
+}
@@ -0,0 +1,427 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package memory
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// GetConfigFileActiveRelease 获取配置文件处于 Active 的全量发布记录
+func (s *memoryStore) GetConfigFileActiveRelease(file *model.ConfigFileKey) (*model.ConfigFileRelease, error) {
+	return s.GetConfigFileActiveReleaseTx(nil, file)
+}
+
+// GetConfigFileActiveReleaseTx 获取配置文件处于 Active 的全量发布记录
+func (s *memoryStore) GetConfigFileActiveReleaseTx(tx store.Tx, file *model.ConfigFileKey) (
+	*model.ConfigFileRelease, error) {
+	return s.getActiveRelease(file, model.ReleaseTypeFull)
+}
+
+// GetConfigFileBetaReleaseTx 获取灰度发布的配置文件信息
+func (s *memoryStore) GetConfigFileBetaReleaseTx(tx store.Tx, file *model.ConfigFileKey) (
+	*model.ConfigFileRelease, error) {
+	return s.getActiveRelease(file, model.ReleaseTypeGray)
+}
+
+func (s *memoryStore) getActiveRelease(file *model.ConfigFileKey, releaseType model.ReleaseType) (
+	*model.ConfigFileRelease, error) {
+	if file == nil {
+		return nil, store.NewStatusError(store.EmptyParamsErr, "get active release missing file key")
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	for _, item := range s.configReleases {
+		if !item.Valid || !item.Active || item.ReleaseType != releaseType {
+			continue
+		}
+		if item.Namespace == file.Namespace && item.Group == file.Group && item.FileName == file.Name {
+			return cloneConfigFileRelease(item), nil
+		}
+	}
+	return nil, nil
+}
+
+// CreateConfigFileReleaseTx 创建配置文件发布，新的发布会处于 Active 状态，并且版本号递增，
+// 同一个配置文件同一类型的其他发布会被置为失效
+func (s *memoryStore) CreateConfigFileReleaseTx(tx store.Tx, fileRelease *model.ConfigFileRelease) error {
+	if fileRelease == nil || fileRelease.SimpleConfigFileRelease == nil ||
+		fileRelease.ConfigFileReleaseKey == nil || fileRelease.Name == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "create config file release missing some params")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	key := configReleaseKey(fileRelease.ConfigFileReleaseKey)
+	if old, ok := s.configReleases[key]; ok && old.Valid {
+		return store.NewStatusError(store.DuplicateEntryErr, "config file release already exists: "+key)
+	}
+	now := s.now()
+	maxVersion := s.inactiveReleases(mtx, fileRelease.ConfigFileReleaseKey, now)
+
+	snapshot(mtx, s.configReleases, key)
+	saved := cloneConfigFileRelease(fileRelease)
+	saved.Id = s.nextID()
+	saved.Version = maxVersion + 1
+	saved.Active = true
+	saved.Valid = true
+	saved.CreateTime = now
+	saved.ModifyTime = now
+	s.configReleases[key] = saved
+	return nil
+}
+
+// GetConfigFileRelease 获取配置文件发布内容，只获取 flag=0 的记录
+func (s *memoryStore) GetConfigFileRelease(req *model.ConfigFileReleaseKey) (*model.ConfigFileRelease, error) {
+	return s.GetConfigFileReleaseTx(nil, req)
+}
+
+// GetConfigFileReleaseTx 在已开启的事务中获取配置文件发布内容，只获取 flag=0 的记录
+func (s *memoryStore) GetConfigFileReleaseTx(tx store.Tx, req *model.ConfigFileReleaseKey) (
+	*model.ConfigFileRelease, error) {
+	if req == nil {
+		return nil, store.NewStatusError(store.EmptyParamsErr, "get config file release missing key")
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	saved, ok := s.configReleases[configReleaseKey(req)]
+	if !ok || !saved.Valid {
+		return nil, nil
+	}
+	return cloneConfigFileRelease(saved), nil
+}
+
+// DeleteConfigFileReleaseTx 删除配置文件发布内容，实际是把valid置为false
+func (s *memoryStore) DeleteConfigFileReleaseTx(tx store.Tx, data *model.ConfigFileReleaseKey) error {
+	if data == nil {
+		return store.NewStatusError(store.EmptyParamsErr, "delete config file release missing key")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	key := configReleaseKey(data)
+	saved, ok := s.configReleases[key]
+	if !ok || !saved.Valid {
+		return nil
+	}
+	s.updateRelease(mtx, key, func(release *model.ConfigFileRelease) {
+		release.Valid = false
+		release.Active = false
+	})
+	return nil
+}
+
+// ActiveConfigFileReleaseTx 指定激活发布的配置文件，同一个配置文件同一类型的其他发布会被置为失效
+func (s *memoryStore) ActiveConfigFileReleaseTx(tx store.Tx, release *model.ConfigFileRelease) error {
+	if release == nil || release.SimpleConfigFileRelease == nil || release.ConfigFileReleaseKey == nil {
+		return store.NewStatusError(store.EmptyParamsErr, "active config file release missing key")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	key := configReleaseKey(release.ConfigFileReleaseKey)
+	saved, ok := s.configReleases[key]
+	if !ok || !saved.Valid {
+		return store.NewStatusError(store.AffectedRowsNotMatch, "config file release not found: "+key)
+	}
+	maxVersion := s.inactiveReleases(mtx, saved.ConfigFileReleaseKey, s.now())
+	s.updateRelease(mtx, key, func(item *model.ConfigFileRelease) {
+		item.Active = true
+		item.Version = maxVersion + 1
+		item.ModifyBy = release.ModifyBy
+		item.ReleaseDescription = release.ReleaseDescription
+	})
+	return nil
+}
+
+// InactiveConfigFileReleaseTx 指定失效发布的配置文件
+func (s *memoryStore) InactiveConfigFileReleaseTx(tx store.Tx, release *model.ConfigFileRelease) error {
+	if release == nil || release.SimpleConfigFileRelease == nil || release.ConfigFileReleaseKey == nil {
+		return store.NewStatusError(store.EmptyParamsErr, "inactive config file release missing key")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	key := configReleaseKey(release.ConfigFileReleaseKey)
+	saved, ok := s.configReleases[key]
+	if !ok || !saved.Valid {
+		return nil
+	}
+	s.updateRelease(mtx, key, func(item *model.ConfigFileRelease) {
+		item.Active = false
+		item.ModifyBy = release.ModifyBy
+	})
+	return nil
+}
+
+// CleanConfigFileReleasesTx 清空配置文件发布，实际是把valid置为false
+func (s *memoryStore) CleanConfigFileReleasesTx(tx store.Tx, namespace, group, fileName string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	for key, item := range s.configReleases {
+		if !item.Valid || item.Namespace != namespace || item.Group != group || item.FileName != fileName {
+			continue
+		}
+		s.updateRelease(mtx, key, func(release *model.ConfigFileRelease) {
+			release.Valid = false
+			release.Active = false
+		})
+	}
+	return nil
+}
+
+// GetMoreReleaseFile 获取最近更新的配置文件发布
+func (s *memoryStore) GetMoreReleaseFile(firstUpdate bool, modifyTime time.Time) ([]*model.ConfigFileRelease, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ret := make([]*model.ConfigFileRelease, 0)
+	for _, item := range s.configReleases {
+		if !isIncrement(item.ModifyTime, modifyTime, item.Valid, firstUpdate) {
+			continue
+		}
+		ret = append(ret, cloneConfigFileRelease(item))
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Id < ret[j].Id
+	})
+	return ret, nil
+}
+
+// CountConfigReleases 获取一个配置文件组下的发布数量
+func (s *memoryStore) CountConfigReleases(namespace, group string, onlyActive bool) (uint64, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var count uint64
+	for _, item := range s.configReleases {
+		if !item.Valid || item.Namespace != namespace || item.Group != group {
+			continue
+		}
+		if onlyActive && !item.Active {
+			continue
+		}
+		count++
+	}
+	return count, nil
+}
+
+// inactiveReleases 将同一个配置文件同一类型的发布置为失效，并返回当前最大的版本号，调用方需要持有写锁
+func (s *memoryStore) inactiveReleases(tx *memoryTx, file *model.ConfigFileReleaseKey, now time.Time) uint64 {
+	var maxVersion uint64
+	for key, item := range s.configReleases {
+		if item.Namespace != file.Namespace || item.Group != file.Group || item.FileName != file.FileName {
+			continue
+		}
+		if item.Version > maxVersion {
+			maxVersion = item.Version
+		}
+		if !item.Valid || !item.Active || item.ReleaseType != file.ReleaseType {
+			continue
+		}
+		s.updateRelease(tx, key, func(release *model.ConfigFileRelease) {
+			release.Active = false
+		})
+	}
+	return maxVersion
+}
+
+// updateRelease 以写时复制的方式修改发布记录，保证事务回滚时能够恢复原始数据，调用方需要持有写锁
+func (s *memoryStore) updateRelease(tx *memoryTx, key string, update func(*model.ConfigFileRelease)) {
+	snapshot(tx, s.configReleases, key)
+	updated := cloneConfigFileRelease(s.configReleases[key])
+	update(updated)
+	updated.ModifyTime = s.now()
+	s.configReleases[key] = updated
+}
+
+func configReleaseKey(key *model.ConfigFileReleaseKey) string {
+	return key.Namespace + "@" + key.Group + "@" + key.FileName + "@" + key.Name
+}
+
+func cloneConfigFileRelease(release *model.ConfigFileRelease) *model.ConfigFileRelease {
+	ret := model.NewConfigFileRelease()
+	ret.Content = release.Content
+	if release.SimpleConfigFileRelease != nil {
+		*ret.SimpleConfigFileRelease = *release.SimpleConfigFileRelease
+		ret.ConfigFileReleaseKey = &model.ConfigFileReleaseKey{}
+		if release.ConfigFileReleaseKey != nil {
+			*ret.ConfigFileReleaseKey = *release.ConfigFileReleaseKey
+		}
+		ret.Metadata = cloneStrings(release.Metadata)
+		ret.BetaLabels = append(ret.BetaLabels[:0:0], release.BetaLabels...)
+	}
+	return ret
+}
+
+// CreateConfigFileReleaseHistory 创建配置文件发布历史记录
+func (s *memoryStore) CreateConfigFileReleaseHistory(history *model.ConfigFileReleaseHistory) error {
+	if history == nil {
+		return store.NewStatusError(store.EmptyParamsErr, "create config file release history missing params")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := s.now()
+	saved := cloneConfigFileReleaseHistory(history)
+	saved.Id = s.nextID()
+	saved.Valid = true
+	saved.CreateTime = now
+	saved.ModifyTime = now
+	s.configHistories = append(s.configHistories, saved)
+	return nil
+}
+
+// QueryConfigFileReleaseHistories 获取配置文件的发布历史记录，结果按照 ID 倒序排列，
+// endId 用于向前翻页，只返回 ID 小于 endId 的记录
+func (s *memoryStore) QueryConfigFileReleaseHistories(filter map[string]string, offset, limit uint32) (
+	uint32, []*model.ConfigFileReleaseHistory, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var endID uint64
+	if v, ok := filter["endId"]; ok {
+		endID, _ = strconv.ParseUint(v, 10, 64)
+	}
+	ret := make([]*model.ConfigFileReleaseHistory, 0)
+	for i := len(s.configHistories) - 1; i >= 0; i-- {
+		item := s.configHistories[i]
+		if !item.Valid || (endID > 0 && item.Id >= endID) {
+			continue
+		}
+		getters := map[string]func() string{
+			"namespace": func() string { return item.Namespace },
+			"group":     func() string { return item.Group },
+			"name":      func() string { return item.FileName },
+		}
+		if !matchFilters(filter, getters) {
+			continue
+		}
+		ret = append(ret, cloneConfigFileReleaseHistory(item))
+	}
+	return uint32(len(ret)), paginate(ret, offset, limit), nil
+}
+
+// CleanConfigFileReleaseHistory 清理 endTime 之前创建的配置发布历史，最多清理 limit 条
+func (s *memoryStore) CleanConfigFileReleaseHistory(endTime time.Time, limit uint64) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var cleaned uint64
+	remain := make([]*model.ConfigFileReleaseHistory, 0, len(s.configHistories))
+	for _, item := range s.configHistories {
+		if cleaned < limit && item.CreateTime.Before(endTime) {
+			cleaned++
+			continue
+		}
+		remain = append(remain, item)
+	}
+	s.configHistories = remain
+	return nil
+}
+
+func cloneConfigFileReleaseHistory(history *model.ConfigFileReleaseHistory) *model.ConfigFileReleaseHistory {
+	ret := *history
+	ret.Metadata = cloneStrings(history.Metadata)
+	return &ret
+}
+
+// QueryAllConfigFileTemplates query all config file templates
+func (s *memoryStore) QueryAllConfigFileTemplates() ([]*model.ConfigFileTemplate, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ret := make([]*model.ConfigFileTemplate, 0, len(s.configTemplates))
+	for _, item := range s.configTemplates {
+		copied := *item
+		ret = append(ret, &copied)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Id < ret[j].Id
+	})
+	return ret, nil
+}
+
+// CreateConfigFileTemplate create config file template
+func (s *memoryStore) CreateConfigFileTemplate(template *model.ConfigFileTemplate) (*model.ConfigFileTemplate, error) {
+	if template == nil || template.Name == "" {
+		return nil, store.NewStatusError(store.EmptyParamsErr, "create config file template missing name")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.configTemplates[template.Name]; ok {
+		return nil, store.NewStatusError(store.DuplicateEntryErr, "config file template already exists: "+template.Name)
+	}
+	now := s.now()
+	saved := *template
+	saved.Id = s.nextID()
+	saved.CreateTime = now
+	saved.ModifyTime = now
+	s.configTemplates[template.Name] = &saved
+	ret := saved
+	return &ret, nil
+}
+
+// GetConfigFileTemplate get config file template by name
+func (s *memoryStore) GetConfigFileTemplate(name string) (*model.ConfigFileTemplate, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	saved, ok := s.configTemplates[name]
+	if !ok {
+		return nil, nil
+	}
+	ret := *saved
+	return &ret, nil
+}
@@ -0,0 +1,269 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package memory
+
+import (
+	"time"
+
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// CreateServiceContract 创建服务契约
+func (s *memoryStore) CreateServiceContract(contract *model.ServiceContract) error {
+	return s.CreateServiceContractTx(nil, contract)
+}
+
+// CreateServiceContractTx 在事务中创建服务契约
+func (s *memoryStore) CreateServiceContractTx(tx store.Tx, contract *model.ServiceContract) error {
+	if contract == nil || contract.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "create service contract missing id")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	if old, ok := s.contracts[contract.ID]; ok && old.Valid {
+		return store.NewStatusError(store.DuplicateEntryErr, "service contract already exists: "+contract.ID)
+	}
+	snapshot(mtx, s.contracts, contract.ID)
+	now := s.now()
+	saved := cloneServiceContract(contract)
+	saved.Valid = true
+	saved.CreateTime = now
+	saved.ModifyTime = now
+	s.contracts[contract.ID] = saved
+	return nil
+}
+
+// UpdateServiceContract 更新服务契约
+func (s *memoryStore) UpdateServiceContract(contract *model.ServiceContract) error {
+	return s.UpdateServiceContractTx(nil, contract)
+}
+
+// UpdateServiceContractTx 在事务中更新服务契约
+func (s *memoryStore) UpdateServiceContractTx(tx store.Tx, contract *model.ServiceContract) error {
+	if contract == nil || contract.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "update service contract missing id")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	saved, ok := s.contracts[contract.ID]
+	if !ok || !saved.Valid {
+		return store.NewStatusError(store.AffectedRowsNotMatch, "service contract not found: "+contract.ID)
+	}
+	snapshot(mtx, s.contracts, contract.ID)
+	saved.Content = contract.Content
+	saved.Revision = contract.Revision
+	saved.ModifyTime = s.now()
+	return nil
+}
+
+// DeleteServiceContract 删除服务契约，实际是把valid置为false
+func (s *memoryStore) DeleteServiceContract(contract *model.ServiceContract) error {
+	return s.DeleteServiceContractTx(nil, contract)
+}
+
+// DeleteServiceContractTx 在事务中删除服务契约，实际是把valid置为false
+func (s *memoryStore) DeleteServiceContractTx(tx store.Tx, contract *model.ServiceContract) error {
+	if contract == nil || contract.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "delete service contract missing id")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	saved, ok := s.contracts[contract.ID]
+	if !ok || !saved.Valid {
+		return nil
+	}
+	snapshot(mtx, s.contracts, contract.ID)
+	saved.Valid = false
+	saved.ModifyTime = s.now()
+	return nil
+}
+
+// GetMoreServiceContracts 用于缓存加载数据
+func (s *memoryStore) GetMoreServiceContracts(firstUpdate bool, mtime time.Time) ([]*model.ServiceContract, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ret := make([]*model.ServiceContract, 0)
+	for _, item := range s.contracts {
+		if !isIncrement(item.ModifyTime, mtime, item.Valid, firstUpdate) {
+			continue
+		}
+		ret = append(ret, cloneServiceContract(item))
+	}
+	return ret, nil
+}
+
+// GetServiceContract 查询服务契约数据
+func (s *memoryStore) GetServiceContract(id string) (*model.ServiceContract, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	saved, ok := s.contracts[id]
+	if !ok || !saved.Valid {
+		return nil, nil
+	}
+	return cloneServiceContract(saved), nil
+}
+
+// AddServiceContractInterfaces 创建服务契约API接口，会覆盖契约中同一来源的全部接口
+func (s *memoryStore) AddServiceContractInterfaces(contract *model.ServiceContract) error {
+	return s.AddServiceContractInterfacesTx(nil, contract)
+}
+
+// AddServiceContractInterfacesTx 在事务中创建服务契约API接口，会覆盖契约中同一来源的全部接口
+func (s *memoryStore) AddServiceContractInterfacesTx(tx store.Tx, contract *model.ServiceContract) error {
+	return s.updateContractInterfaces(tx, contract, func(saved *model.ServiceContract, now time.Time) {
+		if contract.ClientInterfaces != nil {
+			saved.ClientInterfaces = map[string]*model.InterfaceDescriptor{}
+		}
+		if contract.ManualInterfaces != nil {
+			saved.ManualInterfaces = map[string]*model.InterfaceDescriptor{}
+		}
+		putContractInterfaces(saved, contract, now)
+	})
+}
+
+// AppendServiceContractInterfaces 追加服务契约API接口
+func (s *memoryStore) AppendServiceContractInterfaces(contract *model.ServiceContract) error {
+	return s.AppendServiceContractInterfacesTx(nil, contract)
+}
+
+// AppendServiceContractInterfacesTx 在事务中追加服务契约API接口
+func (s *memoryStore) AppendServiceContractInterfacesTx(tx store.Tx, contract *model.ServiceContract) error {
+	return s.updateContractInterfaces(tx, contract, func(saved *model.ServiceContract, now time.Time) {
+		putContractInterfaces(saved, contract, now)
+	})
+}
+
+// DeleteServiceContractInterfaces 批量删除服务契约API接口
+func (s *memoryStore) DeleteServiceContractInterfaces(contract *model.ServiceContract) error {
+	return s.DeleteServiceContractInterfacesTx(nil, contract)
+}
+
+// DeleteServiceContractInterfacesTx 在事务中批量删除服务契约API接口
+func (s *memoryStore) DeleteServiceContractInterfacesTx(tx store.Tx, contract *model.ServiceContract) error {
+	return s.updateContractInterfaces(tx, contract, func(saved *model.ServiceContract, now time.Time) {
+		for _, interfaces := range []map[string]*model.InterfaceDescriptor{
+			contract.ClientInterfaces, contract.ManualInterfaces} {
+			for id := range interfaces {
+				delete(saved.ClientInterfaces, id)
+				delete(saved.ManualInterfaces, id)
+			}
+		}
+	})
+}
+
+// updateContractInterfaces 修改契约中的接口，事务中会先复制契约再修改，保证回滚时能够恢复原有的接口
+func (s *memoryStore) updateContractInterfaces(tx store.Tx, contract *model.ServiceContract,
+	update func(saved *model.ServiceContract, now time.Time)) error {
+	if contract == nil || contract.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "service contract interfaces missing contract id")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	saved, ok := s.contracts[contract.ID]
+	if !ok || !saved.Valid {
+		return store.NewStatusError(store.NotFoundResource, "service contract not found: "+contract.ID)
+	}
+	if mtx != nil {
+		snapshot(mtx, s.contracts, contract.ID)
+		saved = cloneServiceContract(saved)
+		s.contracts[contract.ID] = saved
+	}
+	now := s.now()
+	update(saved, now)
+	saved.Revision = contract.Revision
+	saved.ModifyTime = now
+	return nil
+}
+
+// putContractInterfaces 将 contract 中的接口写入 saved，接口按照 ID 区分
+func putContractInterfaces(saved, contract *model.ServiceContract, now time.Time) {
+	if saved.ClientInterfaces == nil {
+		saved.ClientInterfaces = map[string]*model.InterfaceDescriptor{}
+	}
+	if saved.ManualInterfaces == nil {
+		saved.ManualInterfaces = map[string]*model.InterfaceDescriptor{}
+	}
+	for _, interfaces := range []map[string]*model.InterfaceDescriptor{
+		contract.ClientInterfaces, contract.ManualInterfaces} {
+		for id, item := range interfaces {
+			copied := *item
+			copied.ContractID = saved.ID
+			copied.Valid = true
+			copied.ModifyTime = now
+			if copied.CreateTime.IsZero() {
+				copied.CreateTime = now
+			}
+			if copied.ID == "" {
+				copied.ID = id
+			}
+			if copied.Source == apiservice.InterfaceDescriptor_Client {
+				saved.ClientInterfaces[copied.ID] = &copied
+			} else {
+				saved.ManualInterfaces[copied.ID] = &copied
+			}
+		}
+	}
+}
+
+func cloneServiceContract(contract *model.ServiceContract) *model.ServiceContract {
+	ret := *contract
+	ret.ClientInterfaces = cloneInterfaceDescriptors(contract.ClientInterfaces)
+	ret.ManualInterfaces = cloneInterfaceDescriptors(contract.ManualInterfaces)
+	return &ret
+}
+
+func cloneInterfaceDescriptors(m map[string]*model.InterfaceDescriptor) map[string]*model.InterfaceDescriptor {
+	if m == nil {
+		return nil
+	}
+	ret := make(map[string]*model.InterfaceDescriptor, len(m))
+	for k, v := range m {
+		copied := *v
+		ret[k] = &copied
+	}
+	return ret
+}
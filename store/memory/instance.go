@@ -0,0 +1,510 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// AddInstance 增加一个实例，已存在的同 ID 实例会被覆盖
+func (s *memoryStore) AddInstance(instance *model.Instance) error {
+	return s.AddInstanceTx(nil, instance)
+}
+
+// AddInstanceTx 在事务中增加一个实例，已存在的同 ID 实例会被覆盖
+func (s *memoryStore) AddInstanceTx(tx store.Tx, instance *model.Instance) error {
+	return s.BatchAddInstancesTx(tx, []*model.Instance{instance})
+}
+
+// BatchAddInstances 增加多个实例
+func (s *memoryStore) BatchAddInstances(instances []*model.Instance) error {
+	return s.BatchAddInstancesTx(nil, instances)
+}
+
+// BatchAddInstancesTx 在事务中增加多个实例
+func (s *memoryStore) BatchAddInstancesTx(tx store.Tx, instances []*model.Instance) error {
+	for _, ins := range instances {
+		if ins == nil || ins.Proto == nil || ins.Proto.GetId().GetValue() == "" || ins.ServiceID == "" {
+			return store.NewStatusError(store.EmptyParamsErr, "add instance missing some params")
+		}
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	for _, ins := range instances {
+		if svc, ok := s.services[ins.ServiceID]; !ok || !svc.Valid {
+			return store.NewStatusError(store.NotFoundService, "service not found: "+ins.ServiceID)
+		}
+	}
+	now := s.now()
+	for _, ins := range instances {
+		saved := cloneInstance(ins)
+		saved.Valid = true
+		saved.ModifyTime = now
+		saved.Proto.Ctime = wrapperspb.String(formatTime(now))
+		saved.Proto.Mtime = wrapperspb.String(formatTime(now))
+		snapshot(mtx, s.instances, instanceID(saved))
+		s.instances[instanceID(saved)] = saved
+	}
+	return nil
+}
+
+// UpdateInstance 更新实例
+func (s *memoryStore) UpdateInstance(instance *model.Instance) error {
+	return s.UpdateInstanceTx(nil, instance)
+}
+
+// UpdateInstanceTx 在事务中更新实例
+func (s *memoryStore) UpdateInstanceTx(tx store.Tx, instance *model.Instance) error {
+	if instance == nil || instance.Proto == nil || instance.Proto.GetId().GetValue() == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "update instance missing id")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	saved, ok := s.instances[instance.Proto.GetId().GetValue()]
+	if !ok || !saved.Valid {
+		return store.NewStatusError(store.AffectedRowsNotMatch, "instance not found: "+instance.Proto.GetId().GetValue())
+	}
+	snapshot(mtx, s.instances, instanceID(saved))
+	now := s.now()
+	ctime := saved.Proto.GetCtime()
+	saved.Proto = proto.Clone(instance.Proto).(*apiservice.Instance)
+	saved.Proto.Ctime = ctime
+	saved.Proto.Mtime = wrapperspb.String(formatTime(now))
+	saved.ModifyTime = now
+	saved.ModifyBy = modifyBy(instance.ModifyBy, saved.ModifyBy)
+	return nil
+}
+
+// DeleteInstance 删除一个实例，实际是把valid置为false
+func (s *memoryStore) DeleteInstance(instanceID string) error {
+	return s.DeleteInstanceTx(nil, instanceID)
+}
+
+// DeleteInstanceTx 在事务中删除一个实例，实际是把valid置为false
+func (s *memoryStore) DeleteInstanceTx(tx store.Tx, instanceID string) error {
+	return s.BatchDeleteInstancesTx(tx, []interface{}{instanceID})
+}
+
+// BatchDeleteInstances 批量删除实例，实际是把valid置为false
+func (s *memoryStore) BatchDeleteInstances(ids []interface{}) error {
+	return s.BatchDeleteInstancesTx(nil, ids)
+}
+
+// BatchDeleteInstancesTx 在事务中批量删除实例，实际是把valid置为false
+func (s *memoryStore) BatchDeleteInstancesTx(tx store.Tx, ids []interface{}) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	now := s.now()
+	for _, id := range ids {
+		if saved, ok := s.instances[fmt.Sprint(id)]; !ok || !saved.Valid {
+			continue
+		}
+		saved := s.editInstance(mtx, fmt.Sprint(id))
+		saved.Valid = false
+		s.touchInstance(saved, now)
+	}
+	return nil
+}
+
+// CleanInstance 清空一个实例，真正删除，只有已经软删除的实例才会被清理
+func (s *memoryStore) CleanInstance(instanceID string) error {
+	return s.CleanInstanceTx(nil, instanceID)
+}
+
+// CleanInstanceTx 在事务中清空一个实例，真正删除，只有已经软删除的实例才会被清理
+func (s *memoryStore) CleanInstanceTx(tx store.Tx, instanceID string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	if saved, ok := s.instances[instanceID]; ok && !saved.Valid {
+		snapshot(mtx, s.instances, instanceID)
+		delete(s.instances, instanceID)
+	}
+	return nil
+}
+
+// BatchGetInstanceIsolate 检查ID是否存在，并且返回存在的ID，以及ID的隔离状态
+func (s *memoryStore) BatchGetInstanceIsolate(ids map[string]bool) (map[string]bool, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ret := make(map[string]bool, len(ids))
+	for id := range ids {
+		saved, ok := s.instances[id]
+		if !ok || !saved.Valid {
+			continue
+		}
+		ret[id] = saved.Proto.GetIsolate().GetValue()
+	}
+	return ret, nil
+}
+
+// GetInstancesBrief 获取实例的基础信息以及关联服务的token
+func (s *memoryStore) GetInstancesBrief(ids map[string]bool) (map[string]*model.Instance, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ret := make(map[string]*model.Instance, len(ids))
+	for id := range ids {
+		saved, ok := s.instances[id]
+		if !ok || !saved.Valid {
+			continue
+		}
+		brief := &apiservice.Instance{
+			Id:        saved.Proto.GetId(),
+			Host:      saved.Proto.GetHost(),
+			Port:      saved.Proto.GetPort(),
+			Service:   saved.Proto.GetService(),
+			Namespace: saved.Proto.GetNamespace(),
+			VpcId:     saved.Proto.GetVpcId(),
+		}
+		if svc, ok := s.services[saved.ServiceID]; ok {
+			brief.ServiceToken = wrapperspb.String(svc.Token)
+		}
+		ret[id] = &model.Instance{
+			Proto:             proto.Clone(brief).(*apiservice.Instance),
+			ServiceID:         saved.ServiceID,
+			ServicePlatformID: saved.ServicePlatformID,
+			Valid:             saved.Valid,
+			ModifyTime:        saved.ModifyTime,
+		}
+	}
+	return ret, nil
+}
+
+// GetInstance 查询一个实例的详情，只返回有效的数据
+func (s *memoryStore) GetInstance(instanceID string) (*model.Instance, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	saved, ok := s.instances[instanceID]
+	if !ok || !saved.Valid {
+		return nil, nil
+	}
+	return cloneInstance(saved), nil
+}
+
+// GetInstancesCount 获取有效的实例总数
+func (s *memoryStore) GetInstancesCount() (uint32, error) {
+	return s.GetInstancesCountTx(nil)
+}
+
+// GetInstancesCountTx 获取有效的实例总数
+func (s *memoryStore) GetInstancesCountTx(tx store.Tx) (uint32, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var count uint32
+	for _, ins := range s.instances {
+		if ins.Valid {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// GetInstancesMainByService 根据服务和Host获取实例（不包括metadata）
+func (s *memoryStore) GetInstancesMainByService(serviceID, host string) ([]*model.Instance, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ret := make([]*model.Instance, 0)
+	for _, ins := range s.instances {
+		if !ins.Valid || ins.ServiceID != serviceID || ins.Proto.GetHost().GetValue() != host {
+			continue
+		}
+		item := cloneInstance(ins)
+		item.Proto.Metadata = nil
+		ret = append(ret, item)
+	}
+	sortInstances(ret)
+	return ret, nil
+}
+
+// GetExpandInstances 根据过滤条件查看实例详情及对应数目
+func (s *memoryStore) GetExpandInstances(filter, metaFilter map[string]string, offset uint32, limit uint32) (
+	uint32, []*model.Instance, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ret := make([]*model.Instance, 0)
+	for _, ins := range s.instances {
+		if !ins.Valid || !matchFilters(normalizeBoolFilters(filter), instanceGetters(ins)) {
+			continue
+		}
+		if !matchMetadata(metaFilter, ins.Proto.GetMetadata()) {
+			continue
+		}
+		ret = append(ret, cloneInstance(ins))
+	}
+	sortInstances(ret)
+	return uint32(len(ret)), paginate(ret, offset, limit), nil
+}
+
+// GetMoreInstances 根据mtime获取增量instances，serviceID 不为空时只返回这些服务下的实例
+func (s *memoryStore) GetMoreInstances(tx store.Tx, mtime time.Time, firstUpdate, needMeta bool,
+	serviceID []string) (map[string]*model.Instance, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ret := make(map[string]*model.Instance)
+	for id, ins := range s.instances {
+		if !isIncrement(ins.ModifyTime, mtime, ins.Valid, firstUpdate) {
+			continue
+		}
+		if len(serviceID) > 0 && !containsString(serviceID, ins.ServiceID) {
+			continue
+		}
+		item := cloneInstance(ins)
+		if !needMeta {
+			item.Proto.Metadata = nil
+		}
+		ret[id] = item
+	}
+	return ret, nil
+}
+
+// SetInstanceHealthStatus 设置实例的健康状态
+func (s *memoryStore) SetInstanceHealthStatus(instanceID string, flag int, revision string) error {
+	return s.SetInstanceHealthStatusTx(nil, instanceID, flag, revision)
+}
+
+// SetInstanceHealthStatusTx 在事务中设置实例的健康状态
+func (s *memoryStore) SetInstanceHealthStatusTx(tx store.Tx, instanceID string, flag int, revision string) error {
+	return s.BatchSetInstanceHealthStatusTx(tx, []interface{}{instanceID}, flag, revision)
+}
+
+// BatchSetInstanceHealthStatus 批量设置实例的健康状态
+func (s *memoryStore) BatchSetInstanceHealthStatus(ids []interface{}, healthy int, revision string) error {
+	return s.BatchSetInstanceHealthStatusTx(nil, ids, healthy, revision)
+}
+
+// BatchSetInstanceHealthStatusTx 在事务中批量设置实例的健康状态
+func (s *memoryStore) BatchSetInstanceHealthStatusTx(tx store.Tx, ids []interface{}, healthy int, revision string) error {
+	return s.batchUpdateInstances(tx, ids, revision, func(ins *apiservice.Instance) {
+		ins.Healthy = wrapperspb.Bool(healthy > 0)
+	})
+}
+
+// BatchSetInstanceIsolate 批量修改实例的隔离状态
+func (s *memoryStore) BatchSetInstanceIsolate(ids []interface{}, isolate int, revision string) error {
+	return s.BatchSetInstanceIsolateTx(nil, ids, isolate, revision)
+}
+
+// BatchSetInstanceIsolateTx 在事务中批量修改实例的隔离状态
+func (s *memoryStore) BatchSetInstanceIsolateTx(tx store.Tx, ids []interface{}, isolate int, revision string) error {
+	return s.batchUpdateInstances(tx, ids, revision, func(ins *apiservice.Instance) {
+		ins.Isolate = wrapperspb.Bool(isolate > 0)
+	})
+}
+
+// BatchAppendInstanceMetadata 追加实例 metadata
+func (s *memoryStore) BatchAppendInstanceMetadata(requests []*model.InstanceMetadataRequest) error {
+	return s.BatchAppendInstanceMetadataTx(nil, requests)
+}
+
+// BatchAppendInstanceMetadataTx 在事务中追加实例 metadata
+func (s *memoryStore) BatchAppendInstanceMetadataTx(tx store.Tx, requests []*model.InstanceMetadataRequest) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	now := s.now()
+	for _, req := range requests {
+		if saved, ok := s.instances[req.InstanceID]; !ok || !saved.Valid {
+			continue
+		}
+		saved := s.editInstance(mtx, req.InstanceID)
+		if saved.Proto.Metadata == nil {
+			saved.Proto.Metadata = map[string]string{}
+		}
+		for k, v := range req.Metadata {
+			saved.Proto.Metadata[k] = v
+		}
+		saved.Proto.Revision = wrapperspb.String(req.Revision)
+		s.touchInstance(saved, now)
+	}
+	return nil
+}
+
+// BatchRemoveInstanceMetadata 删除实例指定的 metadata
+func (s *memoryStore) BatchRemoveInstanceMetadata(requests []*model.InstanceMetadataRequest) error {
+	return s.BatchRemoveInstanceMetadataTx(nil, requests)
+}
+
+// BatchRemoveInstanceMetadataTx 在事务中删除实例指定的 metadata
+func (s *memoryStore) BatchRemoveInstanceMetadataTx(tx store.Tx, requests []*model.InstanceMetadataRequest) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	now := s.now()
+	for _, req := range requests {
+		if saved, ok := s.instances[req.InstanceID]; !ok || !saved.Valid {
+			continue
+		}
+		saved := s.editInstance(mtx, req.InstanceID)
+		for _, key := range req.Keys {
+			delete(saved.Proto.Metadata, key)
+		}
+		saved.Proto.Revision = wrapperspb.String(req.Revision)
+		s.touchInstance(saved, now)
+	}
+	return nil
+}
+
+func (s *memoryStore) batchUpdateInstances(tx store.Tx, ids []interface{}, revision string,
+	update func(*apiservice.Instance)) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	now := s.now()
+	for _, id := range ids {
+		if saved, ok := s.instances[fmt.Sprint(id)]; !ok || !saved.Valid {
+			continue
+		}
+		saved := s.editInstance(mtx, fmt.Sprint(id))
+		update(saved.Proto)
+		saved.Proto.Revision = wrapperspb.String(revision)
+		s.touchInstance(saved, now)
+	}
+	return nil
+}
+
+// editInstance 返回可以直接修改的实例，事务中会先记录回滚快照并复制一份实例，
+// 避免回滚时实例的 Proto 已经被修改，调用方需要持有写锁
+func (s *memoryStore) editInstance(tx *memoryTx, id string) *model.Instance {
+	if tx == nil {
+		return s.instances[id]
+	}
+	snapshot(tx, s.instances, id)
+	saved := cloneInstance(s.instances[id])
+	s.instances[id] = saved
+	return saved
+}
+
+func (s *memoryStore) touchInstance(ins *model.Instance, now time.Time) {
+	ins.ModifyTime = now
+	ins.Proto.Mtime = wrapperspb.String(formatTime(now))
+}
+
+func instanceGetters(ins *model.Instance) map[string]func() string {
+	return map[string]func() string{
+		"id":            func() string { return ins.Proto.GetId().GetValue() },
+		"service_id":    func() string { return ins.ServiceID },
+		"name":          func() string { return ins.Proto.GetService().GetValue() },
+		"service":       func() string { return ins.Proto.GetService().GetValue() },
+		"namespace":     func() string { return ins.Proto.GetNamespace().GetValue() },
+		"host":          func() string { return ins.Proto.GetHost().GetValue() },
+		"port":          func() string { return strconv.FormatUint(uint64(ins.Proto.GetPort().GetValue()), 10) },
+		"protocol":      func() string { return ins.Proto.GetProtocol().GetValue() },
+		"version":       func() string { return ins.Proto.GetVersion().GetValue() },
+		"logic_set":     func() string { return ins.Proto.GetLogicSet().GetValue() },
+		"health_status": func() string { return formatBool(ins.Proto.GetHealthy().GetValue()) },
+		"healthy":       func() string { return formatBool(ins.Proto.GetHealthy().GetValue()) },
+		"isolate":       func() string { return formatBool(ins.Proto.GetIsolate().GetValue()) },
+	}
+}
+
+// normalizeBoolFilters 将布尔类型的过滤条件统一转为 1/0 的形式
+func normalizeBoolFilters(filter map[string]string) map[string]string {
+	ret := make(map[string]string, len(filter))
+	for k, v := range filter {
+		switch k {
+		case "health_status", "healthy", "isolate":
+			if b, err := strconv.ParseBool(v); err == nil {
+				v = formatBool(b)
+			}
+		}
+		ret[k] = v
+	}
+	return ret
+}
+
+func formatBool(v bool) string {
+	if v {
+		return "1"
+	}
+	return "0"
+}
+
+func formatTime(t time.Time) string {
+	return t.Format("2006-01-02 15:04:05")
+}
+
+func sortInstances(instances []*model.Instance) {
+	sort.Slice(instances, func(i, j int) bool {
+		if !instances[i].ModifyTime.Equal(instances[j].ModifyTime) {
+			return instances[i].ModifyTime.After(instances[j].ModifyTime)
+		}
+		return instanceID(instances[i]) < instanceID(instances[j])
+	})
+}
+
+func cloneInstance(ins *model.Instance) *model.Instance {
+	ret := *ins
+	if ins.Proto != nil {
+		ret.Proto = proto.Clone(ins.Proto).(*apiservice.Instance)
+	}
+	return &ret
+}
+
+func instanceID(ins *model.Instance) string {
+	return ins.Proto.GetId().GetValue()
+}
@@ -0,0 +1,110 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package memory
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// l5Extend 服务的 L5 扩展数据
+type l5Extend struct {
+	meta       map[string]interface{}
+	modifyTime time.Time
+}
+
+// GetL5Extend 获取扩展数据
+func (s *memoryStore) GetL5Extend(serviceID string) (map[string]interface{}, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	saved, ok := s.l5Extends[serviceID]
+	if !ok {
+		return nil, nil
+	}
+	return cloneInterfaces(saved.meta), nil
+}
+
+// SetL5Extend 设置meta里保存的扩展数据，内存存储会保存全部的数据，因此剩余的meta为空
+func (s *memoryStore) SetL5Extend(serviceID string, meta map[string]interface{}) (map[string]interface{}, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.l5Extends[serviceID] = &l5Extend{
+		meta:       cloneInterfaces(meta),
+		modifyTime: s.now(),
+	}
+	return map[string]interface{}{}, nil
+}
+
+// GenNextL5Sid 获取module
+func (s *memoryStore) GenNextL5Sid(layoutID uint32) (string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.l5Sid[layoutID]++
+	return fmt.Sprintf("%d:%d", layoutID, s.l5Sid[layoutID]), nil
+}
+
+// GetMoreL5Extend 获取增量数据
+func (s *memoryStore) GetMoreL5Extend(mtime time.Time) (map[string]map[string]interface{}, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ret := make(map[string]map[string]interface{})
+	for id, saved := range s.l5Extends {
+		if saved.modifyTime.Before(mtime) {
+			continue
+		}
+		ret[id] = cloneInterfaces(saved.meta)
+	}
+	return ret, nil
+}
+
+// GetMoreL5Routes 获取Route增量数据，内存存储不保存 L5 的路由数据
+func (s *memoryStore) GetMoreL5Routes(flow uint32) ([]*model.Route, error) {
+	return []*model.Route{}, nil
+}
+
+// GetMoreL5Policies 获取Policy增量数据，内存存储不保存 L5 的路由数据
+func (s *memoryStore) GetMoreL5Policies(flow uint32) ([]*model.Policy, error) {
+	return []*model.Policy{}, nil
+}
+
+// GetMoreL5Sections 获取Section增量数据，内存存储不保存 L5 的路由数据
+func (s *memoryStore) GetMoreL5Sections(flow uint32) ([]*model.Section, error) {
+	return []*model.Section{}, nil
+}
+
+// GetMoreL5IPConfigs 获取IP Config增量数据，内存存储不保存 L5 的路由数据
+func (s *memoryStore) GetMoreL5IPConfigs(flow uint32) ([]*model.IPConfig, error) {
+	return []*model.IPConfig{}, nil
+}
+
+func cloneInterfaces(m map[string]interface{}) map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+	ret := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		ret[k] = v
+	}
+	return ret
+}
@@ -0,0 +1,181 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// AddNamespace Save a namespace
+func (s *memoryStore) AddNamespace(namespace *model.Namespace) error {
+	if namespace == nil || namespace.Name == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "add namespace missing name")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if old, ok := s.namespaces[namespace.Name]; ok && old.Valid {
+		return store.NewStatusError(store.DuplicateEntryErr, "namespace already exists: "+namespace.Name)
+	}
+	now := s.now()
+	saved := cloneNamespace(namespace)
+	saved.Valid = true
+	saved.CreateTime = now
+	saved.ModifyTime = now
+	s.namespaces[namespace.Name] = saved
+	return nil
+}
+
+// UpdateNamespace Update namespace
+func (s *memoryStore) UpdateNamespace(namespace *model.Namespace) error {
+	if namespace == nil || namespace.Name == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "update namespace missing name")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	saved, ok := s.namespaces[namespace.Name]
+	if !ok || !saved.Valid {
+		return store.NewStatusError(store.AffectedRowsNotMatch, "namespace not found: "+namespace.Name)
+	}
+	saved.Owner = namespace.Owner
+	saved.Comment = namespace.Comment
+	saved.ServiceExportTo = cloneSet(namespace.ServiceExportTo)
+	saved.ModifyTime = s.now()
+	return nil
+}
+
+// UpdateNamespaceToken Update namespace token
+func (s *memoryStore) UpdateNamespaceToken(name string, token string) error {
+	if name == "" || token == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "update namespace token missing some params")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	saved, ok := s.namespaces[name]
+	if !ok || !saved.Valid {
+		return store.NewStatusError(store.AffectedRowsNotMatch, "namespace not found: "+name)
+	}
+	saved.Token = token
+	saved.ModifyTime = s.now()
+	return nil
+}
+
+// GetNamespace Get the details of the namespace according to Name
+func (s *memoryStore) GetNamespace(name string) (*model.Namespace, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	saved, ok := s.namespaces[name]
+	if !ok || !saved.Valid {
+		return nil, nil
+	}
+	return cloneNamespace(saved), nil
+}
+
+// GetNamespaces Query Namespace from the database, filter 中同一个 key 的多个值为或的关系
+func (s *memoryStore) GetNamespaces(filter map[string][]string, offset, limit int) ([]*model.Namespace, uint32, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ret := make([]*model.Namespace, 0, len(s.namespaces))
+	for _, ns := range s.namespaces {
+		if !ns.Valid {
+			continue
+		}
+		getters := map[string]func() string{
+			"name":  func() string { return ns.Name },
+			"owner": func() string { return ns.Owner },
+		}
+		if !matchAnyFilters(filter, getters) {
+			continue
+		}
+		ret = append(ret, cloneNamespace(ns))
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Name < ret[j].Name
+	})
+	if offset < 0 {
+		offset = 0
+	}
+	if limit < 0 {
+		limit = 0
+	}
+	return paginate(ret, uint32(offset), uint32(limit)), uint32(len(ret)), nil
+}
+
+// GetMoreNamespaces Get incremental data
+func (s *memoryStore) GetMoreNamespaces(mtime time.Time) ([]*model.Namespace, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ret := make([]*model.Namespace, 0, len(s.namespaces))
+	for _, ns := range s.namespaces {
+		if !isIncrement(ns.ModifyTime, mtime, ns.Valid, false) {
+			continue
+		}
+		ret = append(ret, cloneNamespace(ns))
+	}
+	return ret, nil
+}
+
+// matchAnyFilters filter 中同一个 key 的多个值只要满足其中一个即可
+func matchAnyFilters(filter map[string][]string, getters map[string]func() string) bool {
+	for key, patterns := range filter {
+		getter, ok := getters[key]
+		if !ok || len(patterns) == 0 {
+			continue
+		}
+		matched := false
+		for _, pattern := range patterns {
+			if matchString(pattern, getter()) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+func cloneNamespace(ns *model.Namespace) *model.Namespace {
+	ret := *ns
+	ret.ServiceExportTo = cloneSet(ns.ServiceExportTo)
+	return &ret
+}
+
+func cloneSet(m map[string]struct{}) map[string]struct{} {
+	if m == nil {
+		return nil
+	}
+	ret := make(map[string]struct{}, len(m))
+	for k := range m {
+		ret[k] = struct{}{}
+	}
+	return ret
+}
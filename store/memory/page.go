@@ -0,0 +1,146 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package memory
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+var _ store.CursorStore = (*memoryStore)(nil)
+
+// GetNamespacesPage 实现 store.CursorStore
+func (s *memoryStore) GetNamespacesPage(filter map[string][]string, token store.PageToken, limit uint32) (
+	*store.Page[*model.Namespace], error) {
+	items, _, err := s.GetNamespaces(filter, 0, 0)
+	return keysetPage(items, func(ns *model.Namespace) string { return ns.Name }, token, limit, err)
+}
+
+// GetServicesPage 实现 store.CursorStore
+func (s *memoryStore) GetServicesPage(serviceFilters, serviceMetas map[string]string, instanceFilters *model.InstanceArgs, token store.PageToken, limit uint32) (
+	*store.Page[*model.Service], error) {
+	_, items, err := s.GetServices(serviceFilters, serviceMetas, instanceFilters, 0, 0)
+	return keysetPage(items, func(svc *model.Service) string { return svc.ID }, token, limit, err)
+}
+
+// GetServiceAliasesPage 实现 store.CursorStore
+func (s *memoryStore) GetServiceAliasesPage(filter map[string]string, token store.PageToken, limit uint32) (
+	*store.Page[*model.ServiceAlias], error) {
+	_, items, err := s.GetServiceAliases(filter, 0, 0)
+	return keysetPage(items, func(alias *model.ServiceAlias) string { return alias.ID }, token, limit, err)
+}
+
+// GetExpandInstancesPage 实现 store.CursorStore
+func (s *memoryStore) GetExpandInstancesPage(filter, metaFilter map[string]string, token store.PageToken, limit uint32) (
+	*store.Page[*model.Instance], error) {
+	_, items, err := s.GetExpandInstances(filter, metaFilter, 0, 0)
+	return keysetPage(items, func(ins *model.Instance) string { return instanceID(ins) }, token, limit, err)
+}
+
+// GetRoutingConfigsPage 实现 store.CursorStore
+func (s *memoryStore) GetRoutingConfigsPage(filter map[string]string, token store.PageToken, limit uint32) (
+	*store.Page[*model.RoutingConfig], error) {
+	_, items, err := s.GetRoutingConfigs(filter, 0, 0)
+	return keysetPage(items, func(conf *model.RoutingConfig) string { return conf.ID }, token, limit, err)
+}
+
+// GetExtendRateLimitsPage 实现 store.CursorStore
+func (s *memoryStore) GetExtendRateLimitsPage(query map[string]string, token store.PageToken, limit uint32) (
+	*store.Page[*model.RateLimit], error) {
+	_, items, err := s.GetExtendRateLimits(query, 0, 0)
+	return keysetPage(items, func(rule *model.RateLimit) string { return rule.ID }, token, limit, err)
+}
+
+// GetCircuitBreakerRulesPage 实现 store.CursorStore
+func (s *memoryStore) GetCircuitBreakerRulesPage(filter map[string]string, token store.PageToken, limit uint32) (
+	*store.Page[*model.CircuitBreakerRule], error) {
+	_, items, err := s.GetCircuitBreakerRules(filter, 0, 0)
+	return keysetPage(items, func(rule *model.CircuitBreakerRule) string { return rule.ID }, token, limit, err)
+}
+
+// GetFaultDetectRulesPage 实现 store.CursorStore
+func (s *memoryStore) GetFaultDetectRulesPage(filter map[string]string, token store.PageToken, limit uint32) (
+	*store.Page[*model.FaultDetectRule], error) {
+	_, items, err := s.GetFaultDetectRules(filter, 0, 0)
+	return keysetPage(items, func(rule *model.FaultDetectRule) string { return rule.ID }, token, limit, err)
+}
+
+// QueryConfigFilesPage 实现 store.CursorStore
+func (s *memoryStore) QueryConfigFilesPage(filter map[string]string, token store.PageToken, limit uint32) (
+	*store.Page[*model.ConfigFile], error) {
+	_, items, err := s.QueryConfigFiles(filter, 0, 0)
+	return keysetPage(items, func(file *model.ConfigFile) string { return configFileKey(file.Namespace, file.Group, file.Name) }, token, limit, err)
+}
+
+// QueryConfigFileReleaseHistoriesPage 实现 store.CursorStore
+func (s *memoryStore) QueryConfigFileReleaseHistoriesPage(filter map[string]string, token store.PageToken, limit uint32) (
+	*store.Page[*model.ConfigFileReleaseHistory], error) {
+	_, items, err := s.QueryConfigFileReleaseHistories(filter, 0, 0)
+	return keysetPage(items, func(history *model.ConfigFileReleaseHistory) string { return fmt.Sprintf("%020d", history.Id) }, token, limit, err)
+}
+
+// GetUsersPage 实现 store.CursorStore
+func (s *memoryStore) GetUsersPage(filters map[string]string, token store.PageToken, limit uint32) (
+	*store.Page[*model.User], error) {
+	_, items, err := s.GetUsers(filters, 0, 0)
+	return keysetPage(items, func(user *model.User) string { return user.ID }, token, limit, err)
+}
+
+// GetGroupsPage 实现 store.CursorStore
+func (s *memoryStore) GetGroupsPage(filters map[string]string, token store.PageToken, limit uint32) (
+	*store.Page[*model.UserGroup], error) {
+	_, items, err := s.GetGroups(filters, 0, 0)
+	return keysetPage(items, func(group *model.UserGroup) string { return group.ID }, token, limit, err)
+}
+
+// GetStrategiesPage 实现 store.CursorStore
+func (s *memoryStore) GetStrategiesPage(filters map[string]string, token store.PageToken, limit uint32) (
+	*store.Page[*model.StrategyDetail], error) {
+	_, items, err := s.GetStrategies(filters, 0, 0)
+	return keysetPage(items, func(strategy *model.StrategyDetail) string { return strategy.ID }, token, limit, err)
+}
+
+// keysetPage 按照排序键升序排列，返回排序键大于翻页标记的 limit 条数据
+func keysetPage[T any](items []T, key func(T) string, token store.PageToken, limit uint32, err error) (
+	*store.Page[T], error) {
+	if err != nil {
+		return nil, err
+	}
+	after, err := store.DecodePageToken(token)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return key(items[i]) < key(items[j])
+	})
+	start := 0
+	if token != "" {
+		start = sort.Search(len(items), func(i int) bool {
+			return key(items[i]) > after
+		})
+	}
+	page := &store.Page[T]{Items: items[start:]}
+	if limit > 0 && len(page.Items) > int(limit) {
+		page.Items = page.Items[:limit]
+		page.NextToken = store.EncodePageToken(key(page.Items[limit-1]))
+	}
+	return page, nil
+}
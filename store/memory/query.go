@@ -0,0 +1,209 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+var _ store.QueryStore = (*memoryStore)(nil)
+
+// SearchServices 实现 store.QueryStore
+func (s *memoryStore) SearchServices(q *store.Query) (uint32, []*model.Service, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	items := make([]*model.Service, 0, len(s.services))
+	for _, svc := range s.services {
+		if svc.Valid {
+			items = append(items, svc)
+		}
+	}
+	total, ret, err := search(q, store.ServiceQuerySchema, items, func(svc *model.Service) queryFields {
+		return newQueryFields(serviceGetters(svc), svc.ModifyTime, svc.Meta)
+	}, func(svc *model.Service) string { return svc.ID })
+	return total, cloneAll(ret, cloneService), err
+}
+
+// SearchInstances 实现 store.QueryStore
+func (s *memoryStore) SearchInstances(q *store.Query) (uint32, []*model.Instance, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	items := make([]*model.Instance, 0, len(s.instances))
+	for _, ins := range s.instances {
+		if ins.Valid {
+			items = append(items, ins)
+		}
+	}
+	total, ret, err := search(q, store.InstanceQuerySchema, items, func(ins *model.Instance) queryFields {
+		return newQueryFields(instanceGetters(ins), ins.ModifyTime, ins.Proto.GetMetadata())
+	}, instanceID)
+	return total, cloneAll(ret, cloneInstance), err
+}
+
+// SearchRoutingConfigs 实现 store.QueryStore
+func (s *memoryStore) SearchRoutingConfigs(q *store.Query) (uint32, []*model.RoutingConfig, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	items := make([]*model.RoutingConfig, 0, len(s.routingConfigs))
+	for _, conf := range s.routingConfigs {
+		if conf.Valid {
+			items = append(items, s.fillRoutingConfig(conf))
+		}
+	}
+	return search(q, store.RoutingConfigQuerySchema, items, func(conf *model.RoutingConfig) queryFields {
+		return newQueryFields(routingConfigGetters(conf), conf.ModifyTime, nil)
+	}, func(conf *model.RoutingConfig) string { return conf.ID })
+}
+
+// SearchFaultDetectRules 实现 store.QueryStore
+func (s *memoryStore) SearchFaultDetectRules(q *store.Query) (uint32, []*model.FaultDetectRule, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	items := make([]*model.FaultDetectRule, 0, len(s.faultDetectRules))
+	for _, rule := range s.faultDetectRules {
+		if rule.Valid {
+			items = append(items, rule)
+		}
+	}
+	total, ret, err := search(q, store.FaultDetectRuleQuerySchema, items, func(rule *model.FaultDetectRule) queryFields {
+		return newQueryFields(faultDetectRuleGetters(rule), rule.ModifyTime, nil)
+	}, func(rule *model.FaultDetectRule) string { return rule.ID })
+	return total, cloneAll(ret, cloneFaultDetectRule), err
+}
+
+// SearchConfigFiles 实现 store.QueryStore
+func (s *memoryStore) SearchConfigFiles(q *store.Query) (uint32, []*model.ConfigFile, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	items := make([]*model.ConfigFile, 0, len(s.configFiles))
+	for _, file := range s.configFiles {
+		if file.Valid {
+			items = append(items, file)
+		}
+	}
+	total, ret, err := search(q, store.ConfigFileQuerySchema, items, func(file *model.ConfigFile) queryFields {
+		return newQueryFields(configFileGetters(file), file.ModifyTime, nil)
+	}, func(file *model.ConfigFile) string { return configFileKey(file.Namespace, file.Group, file.Name) })
+	return total, cloneAll(ret, cloneConfigFile), err
+}
+
+// SearchUsers 实现 store.QueryStore
+func (s *memoryStore) SearchUsers(q *store.Query) (uint32, []*model.User, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	items := make([]*model.User, 0, len(s.users))
+	for _, user := range s.users {
+		if user.Valid {
+			items = append(items, user)
+		}
+	}
+	total, ret, err := search(q, store.UserQuerySchema, items, func(user *model.User) queryFields {
+		return newQueryFields(userGetters(user), user.ModifyTime, nil)
+	}, func(user *model.User) string { return user.ID })
+	return total, cloneAll(ret, func(user *model.User) *model.User {
+		copied := *user
+		return &copied
+	}), err
+}
+
+// SearchStrategies 实现 store.QueryStore
+func (s *memoryStore) SearchStrategies(q *store.Query) (uint32, []*model.StrategyDetail, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	items := make([]*model.StrategyDetail, 0, len(s.strategies))
+	for _, strategy := range s.strategies {
+		if strategy.Valid {
+			items = append(items, strategy)
+		}
+	}
+	total, ret, err := search(q, store.StrategyQuerySchema, items, func(strategy *model.StrategyDetail) queryFields {
+		return newQueryFields(strategyGetters(strategy), strategy.ModifyTime, nil)
+	}, func(strategy *model.StrategyDetail) string { return strategy.ID })
+	return total, cloneAll(ret, cloneStrategy), err
+}
+
+// queryFields 根据字段名获取字段值，供 store.Query 匹配以及排序使用
+type queryFields func(field string) (string, bool)
+
+func newQueryFields(getters map[string]func() string, mtime time.Time, metadata map[string]string) queryFields {
+	return func(field string) (string, bool) {
+		if field == store.FieldModifyTime {
+			// 补齐位数，保证按照字符串排序的结果与时间先后一致
+			return fmt.Sprintf("%020d", mtime.UnixNano()), true
+		}
+		if strings.HasPrefix(field, store.MetadataFieldPrefix) {
+			v, ok := metadata[strings.TrimPrefix(field, store.MetadataFieldPrefix)]
+			return v, ok
+		}
+		getter, ok := getters[field]
+		if !ok {
+			return "", false
+		}
+		return getter(), true
+	}
+}
+
+// search 校验查询条件后过滤、排序并分页，未指定排序时按照修改时间倒序，相同时按照 id 排序
+func search[T any](q *store.Query, schema *store.QuerySchema, items []T, fields func(T) queryFields,
+	id func(T) string) (uint32, []T, error) {
+	if err := q.Validate(schema); err != nil {
+		return 0, nil, err
+	}
+	matched := make([]T, 0, len(items))
+	for _, item := range items {
+		if q.Match(fields(item)) {
+			matched = append(matched, item)
+		}
+	}
+	defaultOrder := store.NewQuery().OrderBy(store.FieldModifyTime, true)
+	sort.SliceStable(matched, func(i, j int) bool {
+		a, b := fields(matched[i]), fields(matched[j])
+		if ret := q.Compare(a, b); ret != 0 {
+			return ret < 0
+		}
+		if ret := defaultOrder.Compare(a, b); ret != 0 {
+			return ret < 0
+		}
+		return id(matched[i]) < id(matched[j])
+	})
+	var offset, limit uint32
+	if q != nil {
+		offset, limit = q.Offset, q.Limit
+	}
+	return uint32(len(matched)), paginate(matched, offset, limit), nil
+}
+
+func cloneAll[T any](items []T, clone func(T) T) []T {
+	for i := range items {
+		items[i] = clone(items[i])
+	}
+	return items
+}
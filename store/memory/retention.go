@@ -0,0 +1,113 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package memory
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+var _ store.RetentionStore = (*memoryStore)(nil)
+
+// PurgeDeleted batch purge soft deleted resources of kind which mtime time out
+func (s *memoryStore) PurgeDeleted(kind store.RetentionKind, timeout time.Duration, batchSize uint32) (uint32, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	deadline := s.now().Add(-timeout)
+	switch kind {
+	case store.RetentionService:
+		return purgeDeleted(s.services, deadline, batchSize, func(svc *model.Service) (bool, time.Time) {
+			return svc.Valid || svc.Reference != "", svc.ModifyTime
+		}), nil
+	case store.RetentionServiceAlias:
+		return purgeDeleted(s.services, deadline, batchSize, func(svc *model.Service) (bool, time.Time) {
+			return svc.Valid || svc.Reference == "", svc.ModifyTime
+		}), nil
+	case store.RetentionRoutingConfig:
+		return purgeDeleted(s.routingConfigs, deadline, batchSize, func(conf *model.RoutingConfig) (bool, time.Time) {
+			return conf.Valid, conf.ModifyTime
+		}), nil
+	case store.RetentionRouterConfig:
+		return purgeDeleted(s.routerConfigs, deadline, batchSize, func(conf *model.RouterConfig) (bool, time.Time) {
+			return conf.Valid, conf.ModifyTime
+		}), nil
+	case store.RetentionRateLimit:
+		return purgeDeleted(s.rateLimits, deadline, batchSize, func(limit *model.RateLimit) (bool, time.Time) {
+			return limit.Valid, limit.ModifyTime
+		}), nil
+	case store.RetentionCircuitBreakerRule:
+		return purgeDeleted(s.circuitBreakers, deadline, batchSize, func(rule *model.CircuitBreakerRule) (bool, time.Time) {
+			return rule.Valid, rule.ModifyTime
+		}), nil
+	case store.RetentionFaultDetectRule:
+		return purgeDeleted(s.faultDetectRules, deadline, batchSize, func(rule *model.FaultDetectRule) (bool, time.Time) {
+			return rule.Valid, rule.ModifyTime
+		}), nil
+	case store.RetentionServiceContract:
+		return purgeDeleted(s.contracts, deadline, batchSize, func(contract *model.ServiceContract) (bool, time.Time) {
+			return contract.Valid, contract.ModifyTime
+		}), nil
+	case store.RetentionConfigFileGroup:
+		return purgeDeleted(s.configGroups, deadline, batchSize, func(group *model.ConfigFileGroup) (bool, time.Time) {
+			return group.Valid, group.ModifyTime
+		}), nil
+	case store.RetentionConfigFile:
+		return purgeDeleted(s.configFiles, deadline, batchSize, func(file *model.ConfigFile) (bool, time.Time) {
+			return file.Valid, file.ModifyTime
+		}), nil
+	case store.RetentionConfigFileRelease:
+		return purgeDeleted(s.configReleases, deadline, batchSize, func(release *model.ConfigFileRelease) (bool, time.Time) {
+			return release.Valid, release.ModifyTime
+		}), nil
+	case store.RetentionUser:
+		return purgeDeleted(s.users, deadline, batchSize, func(user *model.User) (bool, time.Time) {
+			return user.Valid, user.ModifyTime
+		}), nil
+	case store.RetentionUserGroup:
+		return purgeDeleted(s.groups, deadline, batchSize, func(group *model.UserGroup) (bool, time.Time) {
+			return group.Valid, group.ModifyTime
+		}), nil
+	case store.RetentionStrategy:
+		return purgeDeleted(s.strategies, deadline, batchSize, func(strategy *model.StrategyDetail) (bool, time.Time) {
+			return strategy.Valid, strategy.ModifyTime
+		}), nil
+	default:
+		return 0, store.NewStatusError(store.EmptyParamsErr, fmt.Sprintf("unsupported retention kind %s", kind))
+	}
+}
+
+// purgeDeleted 删除 items 中 keep 返回 false 且 mtime 不晚于 deadline 的数据，最多删除 batchSize 条
+func purgeDeleted[T any](items map[string]*T, deadline time.Time, batchSize uint32,
+	keep func(item *T) (bool, time.Time)) uint32 {
+	var count uint32
+	for id, item := range items {
+		if count >= batchSize {
+			break
+		}
+		if kept, mtime := keep(item); kept || mtime.After(deadline) {
+			continue
+		}
+		delete(items, id)
+		count++
+	}
+	return count
+}
@@ -0,0 +1,379 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// CreateRoutingConfig 新增一个路由配置
+func (s *memoryStore) CreateRoutingConfig(conf *model.RoutingConfig) error {
+	return s.CreateRoutingConfigTx(nil, conf)
+}
+
+// CreateRoutingConfigTx 在事务中新增一个路由配置
+func (s *memoryStore) CreateRoutingConfigTx(tx store.Tx, conf *model.RoutingConfig) error {
+	if conf == nil || conf.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "create routing config missing service id")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	if svc, ok := s.services[conf.ID]; !ok || !svc.Valid {
+		return store.NewStatusError(store.NotFoundService, "service not found: "+conf.ID)
+	}
+	if old, ok := s.routingConfigs[conf.ID]; ok && old.Valid {
+		return store.NewStatusError(store.DuplicateEntryErr, "routing config already exists: "+conf.ID)
+	}
+	snapshot(mtx, s.routingConfigs, conf.ID)
+	now := s.now()
+	saved := *conf
+	saved.Valid = true
+	saved.CreateTime = now
+	saved.ModifyTime = now
+	s.routingConfigs[conf.ID] = &saved
+	return nil
+}
+
+// UpdateRoutingConfig 更新一个路由配置
+func (s *memoryStore) UpdateRoutingConfig(conf *model.RoutingConfig) error {
+	return s.updateRoutingConfig(nil, conf, nil)
+}
+
+// UpdateRoutingConfigTx 在事务中更新一个路由配置
+func (s *memoryStore) UpdateRoutingConfigTx(tx store.Tx, conf *model.RoutingConfig) error {
+	return s.updateRoutingConfig(tx, conf, nil)
+}
+
+// updateRoutingConfig 更新一个路由配置，expectRevision 不为空时只有当前的 Revision 与其一致才会更新
+func (s *memoryStore) updateRoutingConfig(tx store.Tx, conf *model.RoutingConfig, expectRevision *string) error {
+	if conf == nil || conf.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "update routing config missing service id")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	saved, ok := s.routingConfigs[conf.ID]
+	if !ok || !saved.Valid {
+		return store.NewStatusError(store.AffectedRowsNotMatch, "routing config not found: "+conf.ID)
+	}
+	if err := checkRevision("routing config", conf.ID, saved.Revision, expectRevision); err != nil {
+		return err
+	}
+	snapshot(mtx, s.routingConfigs, conf.ID)
+	saved.InBounds = conf.InBounds
+	saved.OutBounds = conf.OutBounds
+	saved.Revision = conf.Revision
+	saved.ModifyTime = s.now()
+	return nil
+}
+
+// DeleteRoutingConfig 删除一个路由配置，实际是把valid置为false
+func (s *memoryStore) DeleteRoutingConfig(serviceID string) error {
+	return s.DeleteRoutingConfigTx(nil, serviceID)
+}
+
+// DeleteRoutingConfigTx 删除一个路由配置，实际是把valid置为false
+func (s *memoryStore) DeleteRoutingConfigTx(tx store.Tx, serviceID string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	saved, ok := s.routingConfigs[serviceID]
+	if !ok || !saved.Valid {
+		return nil
+	}
+	snapshot(mtx, s.routingConfigs, serviceID)
+	saved.Valid = false
+	saved.ModifyTime = s.now()
+	return nil
+}
+
+// GetRoutingConfigsForCache 通过mtime拉取增量的路由配置信息
+func (s *memoryStore) GetRoutingConfigsForCache(mtime time.Time, firstUpdate bool) ([]*model.RoutingConfig, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ret := make([]*model.RoutingConfig, 0)
+	for _, conf := range s.routingConfigs {
+		if !isIncrement(conf.ModifyTime, mtime, conf.Valid, firstUpdate) {
+			continue
+		}
+		ret = append(ret, s.fillRoutingConfig(conf))
+	}
+	return ret, nil
+}
+
+// GetRoutingConfigWithService 根据服务名和命名空间拉取路由配置
+func (s *memoryStore) GetRoutingConfigWithService(name string, namespace string) (*model.RoutingConfig, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	svc := s.findService(name, namespace)
+	if svc == nil || !svc.Valid {
+		return nil, nil
+	}
+	saved, ok := s.routingConfigs[svc.ID]
+	if !ok || !saved.Valid {
+		return nil, nil
+	}
+	return s.fillRoutingConfig(saved), nil
+}
+
+// GetRoutingConfigWithID 根据服务ID拉取路由配置
+func (s *memoryStore) GetRoutingConfigWithID(id string) (*model.RoutingConfig, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	saved, ok := s.routingConfigs[id]
+	if !ok || !saved.Valid {
+		return nil, nil
+	}
+	return s.fillRoutingConfig(saved), nil
+}
+
+// GetRoutingConfigs 查询路由配置列表
+func (s *memoryStore) GetRoutingConfigs(filter map[string]string, offset uint32, limit uint32) (
+	uint32, []*model.RoutingConfig, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ret := make([]*model.RoutingConfig, 0)
+	for _, conf := range s.routingConfigs {
+		if !conf.Valid {
+			continue
+		}
+		item := s.fillRoutingConfig(conf)
+		if !matchFilters(filter, routingConfigGetters(item)) {
+			continue
+		}
+		ret = append(ret, item)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if !ret[i].ModifyTime.Equal(ret[j].ModifyTime) {
+			return ret[i].ModifyTime.After(ret[j].ModifyTime)
+		}
+		return ret[i].ID < ret[j].ID
+	})
+	return uint32(len(ret)), paginate(ret, offset, limit), nil
+}
+
+// fillRoutingConfig 复制路由配置并补充服务名以及命名空间，调用方需要持有锁
+func (s *memoryStore) fillRoutingConfig(conf *model.RoutingConfig) *model.RoutingConfig {
+	ret := *conf
+	if svc, ok := s.services[conf.ID]; ok {
+		ret.ServiceName = svc.Name
+		ret.NamespaceName = svc.Namespace
+	}
+	return &ret
+}
+
+// EnableRouting 设置路由规则是否启用
+func (s *memoryStore) EnableRouting(conf *model.RouterConfig) error {
+	return s.EnableRoutingTx(nil, conf)
+}
+
+// EnableRoutingTx 在事务中设置路由规则是否启用
+func (s *memoryStore) EnableRoutingTx(tx store.Tx, conf *model.RouterConfig) error {
+	if conf == nil || conf.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "enable routing config missing id")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	saved, ok := s.routerConfigs[conf.ID]
+	if !ok || !saved.Valid {
+		return store.NewStatusError(store.AffectedRowsNotMatch, "routing config not found: "+conf.ID)
+	}
+	snapshot(mtx, s.routerConfigs, conf.ID)
+	now := s.now()
+	saved.Enable = conf.Enable
+	saved.Revision = conf.Revision
+	saved.ModifyBy = modifyBy(conf.ModifyBy, saved.ModifyBy)
+	saved.ModifyTime = now
+	if conf.Enable {
+		saved.EnableTime = now
+	}
+	return nil
+}
+
+// CreateRoutingConfigV2 新增一个路由配置
+func (s *memoryStore) CreateRoutingConfigV2(conf *model.RouterConfig) error {
+	return s.CreateRoutingConfigV2Tx(nil, conf)
+}
+
+// CreateRoutingConfigV2Tx 新增一个路由配置
+func (s *memoryStore) CreateRoutingConfigV2Tx(tx store.Tx, conf *model.RouterConfig) error {
+	if conf == nil || conf.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "create routing config missing id")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	if old, ok := s.routerConfigs[conf.ID]; ok && old.Valid {
+		return store.NewStatusError(store.DuplicateEntryErr, "routing config already exists: "+conf.ID)
+	}
+	snapshot(mtx, s.routerConfigs, conf.ID)
+	now := s.now()
+	saved := *conf
+	saved.Valid = true
+	saved.CreateTime = now
+	saved.ModifyTime = now
+	if saved.Enable {
+		saved.EnableTime = now
+	}
+	s.routerConfigs[conf.ID] = &saved
+	return nil
+}
+
+// UpdateRoutingConfigV2 更新一个路由配置
+func (s *memoryStore) UpdateRoutingConfigV2(conf *model.RouterConfig) error {
+	return s.UpdateRoutingConfigV2Tx(nil, conf)
+}
+
+// UpdateRoutingConfigV2Tx 更新一个路由配置
+func (s *memoryStore) UpdateRoutingConfigV2Tx(tx store.Tx, conf *model.RouterConfig) error {
+	return s.updateRoutingConfigV2(tx, conf, nil)
+}
+
+// updateRoutingConfigV2 更新一个路由配置，expectRevision 不为空时只有当前的 Revision 与其一致才会更新
+func (s *memoryStore) updateRoutingConfigV2(tx store.Tx, conf *model.RouterConfig, expectRevision *string) error {
+	if conf == nil || conf.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "update routing config missing id")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	saved, ok := s.routerConfigs[conf.ID]
+	if !ok || !saved.Valid {
+		return store.NewStatusError(store.AffectedRowsNotMatch, "routing config not found: "+conf.ID)
+	}
+	if err := checkRevision("routing config", conf.ID, saved.Revision, expectRevision); err != nil {
+		return err
+	}
+	snapshot(mtx, s.routerConfigs, conf.ID)
+	saved.Name = conf.Name
+	saved.Namespace = conf.Namespace
+	saved.Policy = conf.Policy
+	saved.Config = conf.Config
+	saved.Priority = conf.Priority
+	saved.Revision = conf.Revision
+	saved.Description = conf.Description
+	saved.ModifyBy = modifyBy(conf.ModifyBy, saved.ModifyBy)
+	saved.ModifyTime = s.now()
+	return nil
+}
+
+// DeleteRoutingConfigV2 删除一个路由配置，实际是把valid置为false
+func (s *memoryStore) DeleteRoutingConfigV2(ruleID string) error {
+	return s.DeleteRoutingConfigV2Tx(nil, ruleID)
+}
+
+// DeleteRoutingConfigV2Tx 在事务中删除一个路由配置，实际是把valid置为false
+func (s *memoryStore) DeleteRoutingConfigV2Tx(tx store.Tx, ruleID string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	saved, ok := s.routerConfigs[ruleID]
+	if !ok || !saved.Valid {
+		return nil
+	}
+	snapshot(mtx, s.routerConfigs, ruleID)
+	saved.Valid = false
+	saved.ModifyTime = s.now()
+	return nil
+}
+
+// GetRoutingConfigsV2ForCache 通过mtime拉取增量的路由配置信息
+func (s *memoryStore) GetRoutingConfigsV2ForCache(mtime time.Time, firstUpdate bool) ([]*model.RouterConfig, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ret := make([]*model.RouterConfig, 0)
+	for _, conf := range s.routerConfigs {
+		if !isIncrement(conf.ModifyTime, mtime, conf.Valid, firstUpdate) {
+			continue
+		}
+		item := *conf
+		ret = append(ret, &item)
+	}
+	return ret, nil
+}
+
+// GetRoutingConfigV2WithID 根据规则ID拉取路由配置
+func (s *memoryStore) GetRoutingConfigV2WithID(id string) (*model.RouterConfig, error) {
+	return s.GetRoutingConfigV2WithIDTx(nil, id)
+}
+
+// GetRoutingConfigV2WithIDTx 根据规则ID拉取路由配置
+func (s *memoryStore) GetRoutingConfigV2WithIDTx(tx store.Tx, id string) (*model.RouterConfig, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	saved, ok := s.routerConfigs[id]
+	if !ok || !saved.Valid {
+		return nil, nil
+	}
+	item := *saved
+	return &item, nil
+}
+
+func routingConfigGetters(item *model.RoutingConfig) map[string]func() string {
+	return map[string]func() string{
+		"id":        func() string { return item.ID },
+		"name":      func() string { return item.ServiceName },
+		"service":   func() string { return item.ServiceName },
+		"namespace": func() string { return item.NamespaceName },
+	}
+}
@@ -0,0 +1,641 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package memory
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	apifault "github.com/polarismesh/specification/source/go/api/v1/fault_tolerance"
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// CreateRateLimit 新增限流规则
+func (s *memoryStore) CreateRateLimit(limit *model.RateLimit) error {
+	return s.CreateRateLimitTx(nil, limit)
+}
+
+// CreateRateLimitTx 在事务中新增限流规则
+func (s *memoryStore) CreateRateLimitTx(tx store.Tx, limit *model.RateLimit) error {
+	if limit == nil || limit.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "create rate limit missing id")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	if old, ok := s.rateLimits[limit.ID]; ok && old.Valid {
+		return store.NewStatusError(store.DuplicateEntryErr, "rate limit already exists: "+limit.ID)
+	}
+	snapshot(mtx, s.rateLimits, limit.ID)
+	now := s.now()
+	saved := cloneRateLimit(limit)
+	saved.Valid = true
+	saved.CreateTime = now
+	saved.ModifyTime = now
+	if !saved.Disable {
+		saved.EnableTime = now
+	}
+	s.rateLimits[limit.ID] = saved
+	return nil
+}
+
+// UpdateRateLimit 更新限流规则
+func (s *memoryStore) UpdateRateLimit(limit *model.RateLimit) error {
+	return s.updateRateLimit(nil, limit, nil)
+}
+
+// UpdateRateLimitTx 在事务中更新限流规则
+func (s *memoryStore) UpdateRateLimitTx(tx store.Tx, limit *model.RateLimit) error {
+	return s.updateRateLimit(tx, limit, nil)
+}
+
+// updateRateLimit 更新限流规则，expectRevision 不为空时只有当前的 Revision 与其一致才会更新
+func (s *memoryStore) updateRateLimit(tx store.Tx, limit *model.RateLimit, expectRevision *string) error {
+	if limit == nil || limit.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "update rate limit missing id")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	saved, ok := s.rateLimits[limit.ID]
+	if !ok || !saved.Valid {
+		return store.NewStatusError(store.AffectedRowsNotMatch, "rate limit not found: "+limit.ID)
+	}
+	if err := checkRevision("rate limit", limit.ID, saved.Revision, expectRevision); err != nil {
+		return err
+	}
+	snapshot(mtx, s.rateLimits, limit.ID)
+	now := s.now()
+	updated := cloneRateLimit(limit)
+	updated.Valid = true
+	updated.CreateTime = saved.CreateTime
+	updated.CreateBy = saved.CreateBy
+	updated.ModifyBy = modifyBy(updated.ModifyBy, saved.ModifyBy)
+	updated.ModifyTime = now
+	updated.EnableTime = saved.EnableTime
+	if saved.Disable && !updated.Disable {
+		updated.EnableTime = now
+	}
+	s.rateLimits[limit.ID] = updated
+	return nil
+}
+
+// EnableRateLimit 启用限流规则
+func (s *memoryStore) EnableRateLimit(limit *model.RateLimit) error {
+	return s.EnableRateLimitTx(nil, limit)
+}
+
+// EnableRateLimitTx 在事务中启用限流规则
+func (s *memoryStore) EnableRateLimitTx(tx store.Tx, limit *model.RateLimit) error {
+	if limit == nil || limit.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "enable rate limit missing id")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	saved, ok := s.rateLimits[limit.ID]
+	if !ok || !saved.Valid {
+		return store.NewStatusError(store.AffectedRowsNotMatch, "rate limit not found: "+limit.ID)
+	}
+	snapshot(mtx, s.rateLimits, limit.ID)
+	now := s.now()
+	saved.Disable = limit.Disable
+	saved.Revision = limit.Revision
+	saved.ModifyBy = modifyBy(limit.ModifyBy, saved.ModifyBy)
+	saved.ModifyTime = now
+	if !limit.Disable {
+		saved.EnableTime = now
+	}
+	return nil
+}
+
+// DeleteRateLimit 删除限流规则，实际是把valid置为false
+func (s *memoryStore) DeleteRateLimit(limit *model.RateLimit) error {
+	return s.DeleteRateLimitTx(nil, limit)
+}
+
+// DeleteRateLimitTx 在事务中删除限流规则，实际是把valid置为false
+func (s *memoryStore) DeleteRateLimitTx(tx store.Tx, limit *model.RateLimit) error {
+	if limit == nil || limit.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "delete rate limit missing id")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	saved, ok := s.rateLimits[limit.ID]
+	if !ok || !saved.Valid {
+		return nil
+	}
+	snapshot(mtx, s.rateLimits, limit.ID)
+	saved.Valid = false
+	saved.Revision = limit.Revision
+	saved.ModifyBy = modifyBy(limit.ModifyBy, saved.ModifyBy)
+	saved.ModifyTime = s.now()
+	return nil
+}
+
+// GetExtendRateLimits 根据过滤条件拉取限流规则
+func (s *memoryStore) GetExtendRateLimits(query map[string]string, offset uint32, limit uint32) (
+	uint32, []*model.RateLimit, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ret := make([]*model.RateLimit, 0)
+	for _, item := range s.rateLimits {
+		if !item.Valid {
+			continue
+		}
+		getters := map[string]func() string{
+			"id":        func() string { return item.ID },
+			"name":      func() string { return item.Name },
+			"service":   func() string { return item.ServiceName },
+			"namespace": func() string { return item.NamespaceName },
+			"method":    func() string { return item.Method },
+			"disable":   func() string { return strconv.FormatBool(item.Disable) },
+		}
+		if !matchFilters(query, getters) {
+			continue
+		}
+		ret = append(ret, cloneRateLimit(item))
+	}
+	sortByModifyTime(ret, func(r *model.RateLimit) time.Time { return r.ModifyTime },
+		func(r *model.RateLimit) string { return r.ID })
+	return uint32(len(ret)), paginate(ret, offset, limit), nil
+}
+
+// GetRateLimitWithID 根据限流ID拉取限流规则
+func (s *memoryStore) GetRateLimitWithID(id string) (*model.RateLimit, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	saved, ok := s.rateLimits[id]
+	if !ok || !saved.Valid {
+		return nil, nil
+	}
+	return cloneRateLimit(saved), nil
+}
+
+// GetRateLimitsForCache 根据修改时间拉取增量限流规则
+func (s *memoryStore) GetRateLimitsForCache(mtime time.Time, firstUpdate bool) ([]*model.RateLimit, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ret := make([]*model.RateLimit, 0)
+	for _, item := range s.rateLimits {
+		if !isIncrement(item.ModifyTime, mtime, item.Valid, firstUpdate) {
+			continue
+		}
+		ret = append(ret, cloneRateLimit(item))
+	}
+	return ret, nil
+}
+
+// CreateCircuitBreakerRule create general circuitbreaker rule
+func (s *memoryStore) CreateCircuitBreakerRule(cbRule *model.CircuitBreakerRule) error {
+	return s.CreateCircuitBreakerRuleTx(nil, cbRule)
+}
+
+// CreateCircuitBreakerRuleTx create general circuitbreaker rule in transaction
+func (s *memoryStore) CreateCircuitBreakerRuleTx(tx store.Tx, cbRule *model.CircuitBreakerRule) error {
+	if cbRule == nil || cbRule.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "create circuitbreaker rule missing id")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	if old, ok := s.circuitBreakers[cbRule.ID]; ok && old.Valid {
+		return store.NewStatusError(store.DuplicateEntryErr, "circuitbreaker rule already exists: "+cbRule.ID)
+	}
+	snapshot(mtx, s.circuitBreakers, cbRule.ID)
+	now := s.now()
+	saved := cloneCircuitBreakerRule(cbRule)
+	saved.Valid = true
+	saved.CreateTime = now
+	saved.ModifyTime = now
+	if saved.Enable {
+		saved.EnableTime = now
+	}
+	s.circuitBreakers[cbRule.ID] = saved
+	return nil
+}
+
+// UpdateCircuitBreakerRule update general circuitbreaker rule
+func (s *memoryStore) UpdateCircuitBreakerRule(cbRule *model.CircuitBreakerRule) error {
+	return s.updateCircuitBreakerRule(nil, cbRule, nil)
+}
+
+// UpdateCircuitBreakerRuleTx update general circuitbreaker rule in transaction
+func (s *memoryStore) UpdateCircuitBreakerRuleTx(tx store.Tx, cbRule *model.CircuitBreakerRule) error {
+	return s.updateCircuitBreakerRule(tx, cbRule, nil)
+}
+
+// updateCircuitBreakerRule 更新熔断规则，expectRevision 不为空时只有当前的 Revision 与其一致才会更新
+func (s *memoryStore) updateCircuitBreakerRule(tx store.Tx, cbRule *model.CircuitBreakerRule, expectRevision *string) error {
+	if cbRule == nil || cbRule.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "update circuitbreaker rule missing id")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	saved, ok := s.circuitBreakers[cbRule.ID]
+	if !ok || !saved.Valid {
+		return store.NewStatusError(store.AffectedRowsNotMatch, "circuitbreaker rule not found: "+cbRule.ID)
+	}
+	if err := checkRevision("circuitbreaker rule", cbRule.ID, saved.Revision, expectRevision); err != nil {
+		return err
+	}
+	snapshot(mtx, s.circuitBreakers, cbRule.ID)
+	now := s.now()
+	updated := cloneCircuitBreakerRule(cbRule)
+	updated.Valid = true
+	updated.CreateTime = saved.CreateTime
+	updated.CreateBy = saved.CreateBy
+	updated.ModifyBy = modifyBy(updated.ModifyBy, saved.ModifyBy)
+	updated.ModifyTime = now
+	updated.EnableTime = saved.EnableTime
+	if !saved.Enable && updated.Enable {
+		updated.EnableTime = now
+	}
+	s.circuitBreakers[cbRule.ID] = updated
+	return nil
+}
+
+// DeleteCircuitBreakerRule delete general circuitbreaker rule, only mark valid as false
+func (s *memoryStore) DeleteCircuitBreakerRule(id string) error {
+	return s.DeleteCircuitBreakerRuleTx(nil, id)
+}
+
+// DeleteCircuitBreakerRuleTx delete general circuitbreaker rule in transaction, only mark valid as false
+func (s *memoryStore) DeleteCircuitBreakerRuleTx(tx store.Tx, id string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	saved, ok := s.circuitBreakers[id]
+	if !ok || !saved.Valid {
+		return nil
+	}
+	snapshot(mtx, s.circuitBreakers, id)
+	saved.Valid = false
+	saved.ModifyTime = s.now()
+	return nil
+}
+
+// HasCircuitBreakerRule check circuitbreaker rule exists
+func (s *memoryStore) HasCircuitBreakerRule(id string) (bool, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	saved, ok := s.circuitBreakers[id]
+	return ok && saved.Valid, nil
+}
+
+// HasCircuitBreakerRuleByName check circuitbreaker rule exists for name
+func (s *memoryStore) HasCircuitBreakerRuleByName(name string, namespace string) (bool, error) {
+	return s.HasCircuitBreakerRuleByNameExcludeId(name, namespace, "")
+}
+
+// HasCircuitBreakerRuleByNameExcludeId check circuitbreaker rule exists for name not this id
+func (s *memoryStore) HasCircuitBreakerRuleByNameExcludeId(name string, namespace string, id string) (bool, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	for _, item := range s.circuitBreakers {
+		if item.Valid && item.Name == name && item.Namespace == namespace && item.ID != id {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// GetCircuitBreakerRules get all circuitbreaker rules by query and limit
+func (s *memoryStore) GetCircuitBreakerRules(filter map[string]string, offset uint32, limit uint32) (
+	uint32, []*model.CircuitBreakerRule, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ret := make([]*model.CircuitBreakerRule, 0)
+	for _, item := range s.circuitBreakers {
+		if !item.Valid {
+			continue
+		}
+		getters := map[string]func() string{
+			"id":            func() string { return item.ID },
+			"name":          func() string { return item.Name },
+			"namespace":     func() string { return item.Namespace },
+			"enable":        func() string { return strconv.FormatBool(item.Enable) },
+			"level":         func() string { return strconv.Itoa(item.Level) },
+			"src_service":   func() string { return item.SrcService },
+			"src_namespace": func() string { return item.SrcNamespace },
+			"dst_service":   func() string { return item.DstService },
+			"dst_namespace": func() string { return item.DstNamespace },
+			"dst_method":    func() string { return item.DstMethod },
+			"description":   func() string { return item.Description },
+		}
+		if !matchFilters(filter, getters) {
+			continue
+		}
+		ret = append(ret, cloneCircuitBreakerRule(item))
+	}
+	sortByModifyTime(ret, func(r *model.CircuitBreakerRule) time.Time { return r.ModifyTime },
+		func(r *model.CircuitBreakerRule) string { return r.ID })
+	return uint32(len(ret)), paginate(ret, offset, limit), nil
+}
+
+// GetCircuitBreakerRulesForCache get increment circuitbreaker rules
+func (s *memoryStore) GetCircuitBreakerRulesForCache(mtime time.Time, firstUpdate bool) (
+	[]*model.CircuitBreakerRule, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ret := make([]*model.CircuitBreakerRule, 0)
+	for _, item := range s.circuitBreakers {
+		if !isIncrement(item.ModifyTime, mtime, item.Valid, firstUpdate) {
+			continue
+		}
+		ret = append(ret, cloneCircuitBreakerRule(item))
+	}
+	return ret, nil
+}
+
+// EnableCircuitBreakerRule enable specific circuitbreaker rule
+func (s *memoryStore) EnableCircuitBreakerRule(cbRule *model.CircuitBreakerRule) error {
+	return s.EnableCircuitBreakerRuleTx(nil, cbRule)
+}
+
+// EnableCircuitBreakerRuleTx enable specific circuitbreaker rule in transaction
+func (s *memoryStore) EnableCircuitBreakerRuleTx(tx store.Tx, cbRule *model.CircuitBreakerRule) error {
+	if cbRule == nil || cbRule.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "enable circuitbreaker rule missing id")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	saved, ok := s.circuitBreakers[cbRule.ID]
+	if !ok || !saved.Valid {
+		return store.NewStatusError(store.AffectedRowsNotMatch, "circuitbreaker rule not found: "+cbRule.ID)
+	}
+	snapshot(mtx, s.circuitBreakers, cbRule.ID)
+	now := s.now()
+	saved.Enable = cbRule.Enable
+	saved.Revision = cbRule.Revision
+	saved.ModifyBy = modifyBy(cbRule.ModifyBy, saved.ModifyBy)
+	saved.ModifyTime = now
+	if cbRule.Enable {
+		saved.EnableTime = now
+	}
+	return nil
+}
+
+// CreateFaultDetectRule create fault detect rule
+func (s *memoryStore) CreateFaultDetectRule(conf *model.FaultDetectRule) error {
+	return s.CreateFaultDetectRuleTx(nil, conf)
+}
+
+// CreateFaultDetectRuleTx create fault detect rule in transaction
+func (s *memoryStore) CreateFaultDetectRuleTx(tx store.Tx, conf *model.FaultDetectRule) error {
+	if conf == nil || conf.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "create fault detect rule missing id")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	if old, ok := s.faultDetectRules[conf.ID]; ok && old.Valid {
+		return store.NewStatusError(store.DuplicateEntryErr, "fault detect rule already exists: "+conf.ID)
+	}
+	snapshot(mtx, s.faultDetectRules, conf.ID)
+	now := s.now()
+	saved := cloneFaultDetectRule(conf)
+	saved.Valid = true
+	saved.CreateTime = now
+	saved.ModifyTime = now
+	s.faultDetectRules[conf.ID] = saved
+	return nil
+}
+
+// UpdateFaultDetectRule update fault detect rule
+func (s *memoryStore) UpdateFaultDetectRule(conf *model.FaultDetectRule) error {
+	return s.updateFaultDetectRule(nil, conf, nil)
+}
+
+// UpdateFaultDetectRuleTx update fault detect rule in transaction
+func (s *memoryStore) UpdateFaultDetectRuleTx(tx store.Tx, conf *model.FaultDetectRule) error {
+	return s.updateFaultDetectRule(tx, conf, nil)
+}
+
+// updateFaultDetectRule 更新主动探测规则，expectRevision 不为空时只有当前的 Revision 与其一致才会更新
+func (s *memoryStore) updateFaultDetectRule(tx store.Tx, conf *model.FaultDetectRule, expectRevision *string) error {
+	if conf == nil || conf.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "update fault detect rule missing id")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	saved, ok := s.faultDetectRules[conf.ID]
+	if !ok || !saved.Valid {
+		return store.NewStatusError(store.AffectedRowsNotMatch, "fault detect rule not found: "+conf.ID)
+	}
+	if err := checkRevision("fault detect rule", conf.ID, saved.Revision, expectRevision); err != nil {
+		return err
+	}
+	snapshot(mtx, s.faultDetectRules, conf.ID)
+	updated := cloneFaultDetectRule(conf)
+	updated.Valid = true
+	updated.CreateTime = saved.CreateTime
+	updated.CreateBy = saved.CreateBy
+	updated.ModifyBy = modifyBy(updated.ModifyBy, saved.ModifyBy)
+	updated.ModifyTime = s.now()
+	s.faultDetectRules[conf.ID] = updated
+	return nil
+}
+
+// DeleteFaultDetectRule delete fault detect rule, only mark valid as false
+func (s *memoryStore) DeleteFaultDetectRule(id string) error {
+	return s.DeleteFaultDetectRuleTx(nil, id)
+}
+
+// DeleteFaultDetectRuleTx delete fault detect rule in transaction, only mark valid as false
+func (s *memoryStore) DeleteFaultDetectRuleTx(tx store.Tx, id string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	saved, ok := s.faultDetectRules[id]
+	if !ok || !saved.Valid {
+		return nil
+	}
+	snapshot(mtx, s.faultDetectRules, id)
+	saved.Valid = false
+	saved.ModifyTime = s.now()
+	return nil
+}
+
+// HasFaultDetectRule check fault detect rule exists
+func (s *memoryStore) HasFaultDetectRule(id string) (bool, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	saved, ok := s.faultDetectRules[id]
+	return ok && saved.Valid, nil
+}
+
+// HasFaultDetectRuleByName check fault detect rule exists by name
+func (s *memoryStore) HasFaultDetectRuleByName(name string, namespace string) (bool, error) {
+	return s.HasFaultDetectRuleByNameExcludeId(name, namespace, "")
+}
+
+// HasFaultDetectRuleByNameExcludeId check fault detect rule exists by name not this id
+func (s *memoryStore) HasFaultDetectRuleByNameExcludeId(name string, namespace string, id string) (bool, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	for _, item := range s.faultDetectRules {
+		if item.Valid && item.Name == name && item.Namespace == namespace && item.ID != id {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// GetFaultDetectRules get all fault detect rules by query and limit
+func (s *memoryStore) GetFaultDetectRules(filter map[string]string, offset uint32, limit uint32) (
+	uint32, []*model.FaultDetectRule, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ret := make([]*model.FaultDetectRule, 0)
+	for _, item := range s.faultDetectRules {
+		if !item.Valid {
+			continue
+		}
+		if !matchFilters(filter, faultDetectRuleGetters(item)) {
+			continue
+		}
+		ret = append(ret, cloneFaultDetectRule(item))
+	}
+	sortByModifyTime(ret, func(r *model.FaultDetectRule) time.Time { return r.ModifyTime },
+		func(r *model.FaultDetectRule) string { return r.ID })
+	return uint32(len(ret)), paginate(ret, offset, limit), nil
+}
+
+// GetFaultDetectRulesForCache get increment fault detect rules
+func (s *memoryStore) GetFaultDetectRulesForCache(mtime time.Time, firstUpdate bool) (
+	[]*model.FaultDetectRule, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ret := make([]*model.FaultDetectRule, 0)
+	for _, item := range s.faultDetectRules {
+		if !isIncrement(item.ModifyTime, mtime, item.Valid, firstUpdate) {
+			continue
+		}
+		ret = append(ret, cloneFaultDetectRule(item))
+	}
+	return ret, nil
+}
+
+func cloneRateLimit(r *model.RateLimit) *model.RateLimit {
+	ret := *r
+	if r.Proto != nil {
+		ret.Proto = proto.Clone(r.Proto).(*apitraffic.Rule)
+	}
+	return &ret
+}
+
+func cloneCircuitBreakerRule(r *model.CircuitBreakerRule) *model.CircuitBreakerRule {
+	ret := *r
+	if r.Proto != nil {
+		ret.Proto = proto.Clone(r.Proto).(*apifault.CircuitBreakerRule)
+	}
+	return &ret
+}
+
+func cloneFaultDetectRule(r *model.FaultDetectRule) *model.FaultDetectRule {
+	ret := *r
+	if r.Proto != nil {
+		ret.Proto = proto.Clone(r.Proto).(*apifault.FaultDetectRule)
+	}
+	return &ret
+}
+
+func faultDetectRuleGetters(item *model.FaultDetectRule) map[string]func() string {
+	return map[string]func() string{
+		"id":            func() string { return item.ID },
+		"name":          func() string { return item.Name },
+		"namespace":     func() string { return item.Namespace },
+		"dst_service":   func() string { return item.DstService },
+		"dst_namespace": func() string { return item.DstNamespace },
+		"dst_method":    func() string { return item.DstMethod },
+		"description":   func() string { return item.Description },
+	}
+}
@@ -0,0 +1,539 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package memory
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+const (
+	// systemNamespace 系统服务所在的命名空间
+	systemNamespace = "Polaris"
+)
+
+// AddService 保存一个服务
+func (s *memoryStore) AddService(service *model.Service) error {
+	return s.AddServiceTx(nil, service)
+}
+
+// AddServiceTx 在事务中保存一个服务
+func (s *memoryStore) AddServiceTx(tx store.Tx, service *model.Service) error {
+	if service == nil || service.ID == "" || service.Name == "" || service.Namespace == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "add service missing some params")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	if ns, ok := s.namespaces[service.Namespace]; !ok || !ns.Valid {
+		return store.NewStatusError(store.NotFoundNamespace, "namespace not found: "+service.Namespace)
+	}
+	if old, ok := s.services[service.ID]; ok && old.Valid {
+		return store.NewStatusError(store.DuplicateEntryErr, "service id already exists: "+service.ID)
+	}
+	if old := s.findService(service.Name, service.Namespace); old != nil {
+		if old.Valid {
+			return store.NewStatusError(store.DuplicateEntryErr,
+				"service already exists: "+service.Namespace+"/"+service.Name)
+		}
+		// 清理掉已经软删除的同名服务
+		snapshot(mtx, s.services, old.ID)
+		delete(s.services, old.ID)
+	}
+	snapshot(mtx, s.services, service.ID)
+	now := s.now()
+	saved := cloneService(service)
+	saved.Valid = true
+	s.touchService(saved, now)
+	saved.CreateTime = now
+	saved.Ctime = now.Unix()
+	s.services[saved.ID] = saved
+	return nil
+}
+
+// DeleteService 删除服务，实际是把 valid 置为 false
+func (s *memoryStore) DeleteService(id, serviceName, namespaceName string) error {
+	return s.DeleteServiceTx(nil, id, serviceName, namespaceName)
+}
+
+// DeleteServiceTx 在事务中删除服务，实际是把 valid 置为 false
+func (s *memoryStore) DeleteServiceTx(tx store.Tx, id, serviceName, namespaceName string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	saved, ok := s.services[id]
+	if !ok {
+		saved = s.findService(serviceName, namespaceName)
+	}
+	if saved == nil || !saved.Valid {
+		return nil
+	}
+	snapshot(mtx, s.services, saved.ID)
+	saved.Valid = false
+	s.touchService(saved, s.now())
+	return nil
+}
+
+// DeleteServiceAlias 删除服务别名，实际是把 valid 置为 false
+func (s *memoryStore) DeleteServiceAlias(name string, namespace string) error {
+	return s.DeleteServiceAliasTx(nil, name, namespace)
+}
+
+// DeleteServiceAliasTx 在事务中删除服务别名，实际是把 valid 置为 false
+func (s *memoryStore) DeleteServiceAliasTx(tx store.Tx, name string, namespace string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	saved := s.findService(name, namespace)
+	if saved == nil || !saved.Valid || saved.Reference == "" {
+		return nil
+	}
+	snapshot(mtx, s.services, saved.ID)
+	saved.Valid = false
+	s.touchService(saved, s.now())
+	return nil
+}
+
+// UpdateServiceAlias 修改服务别名
+func (s *memoryStore) UpdateServiceAlias(alias *model.Service, needUpdateOwner bool) error {
+	return s.UpdateServiceAliasTx(nil, alias, needUpdateOwner)
+}
+
+// UpdateServiceAliasTx 在事务中修改服务别名
+func (s *memoryStore) UpdateServiceAliasTx(tx store.Tx, alias *model.Service, needUpdateOwner bool) error {
+	if alias == nil || alias.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "update service alias missing id")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	saved, ok := s.services[alias.ID]
+	if !ok || !saved.Valid {
+		return store.NewStatusError(store.AffectedRowsNotMatch, "service alias not found: "+alias.ID)
+	}
+	snapshot(mtx, s.services, alias.ID)
+	saved.Reference = alias.Reference
+	saved.Comment = alias.Comment
+	saved.Token = alias.Token
+	saved.Revision = alias.Revision
+	saved.ExportTo = cloneSet(alias.ExportTo)
+	saved.ModifyBy = modifyBy(alias.ModifyBy, saved.ModifyBy)
+	if needUpdateOwner {
+		saved.Owner = alias.Owner
+	}
+	s.touchService(saved, s.now())
+	return nil
+}
+
+// UpdateService 更新服务
+func (s *memoryStore) UpdateService(service *model.Service, needUpdateOwner bool) error {
+	return s.updateService(nil, service, needUpdateOwner, nil)
+}
+
+// UpdateServiceTx 在事务中更新服务
+func (s *memoryStore) UpdateServiceTx(tx store.Tx, service *model.Service, needUpdateOwner bool) error {
+	return s.updateService(tx, service, needUpdateOwner, nil)
+}
+
+// updateService 更新服务，expectRevision 不为空时只有当前的 Revision 与其一致才会更新
+func (s *memoryStore) updateService(tx store.Tx, service *model.Service, needUpdateOwner bool,
+	expectRevision *string) error {
+	if service == nil || service.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "update service missing id")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	saved, ok := s.services[service.ID]
+	if !ok || !saved.Valid {
+		return store.NewStatusError(store.AffectedRowsNotMatch, "service not found: "+service.ID)
+	}
+	if err := checkRevision("service", service.ID, saved.Revision, expectRevision); err != nil {
+		return err
+	}
+	snapshot(mtx, s.services, service.ID)
+	saved.Business = service.Business
+	saved.Ports = service.Ports
+	saved.Meta = cloneStrings(service.Meta)
+	saved.Comment = service.Comment
+	saved.Department = service.Department
+	saved.CmdbMod1 = service.CmdbMod1
+	saved.CmdbMod2 = service.CmdbMod2
+	saved.CmdbMod3 = service.CmdbMod3
+	saved.Revision = service.Revision
+	saved.PlatformID = service.PlatformID
+	saved.ServicePorts = cloneServicePorts(service.ServicePorts)
+	saved.ExportTo = cloneSet(service.ExportTo)
+	saved.ModifyBy = modifyBy(service.ModifyBy, saved.ModifyBy)
+	if needUpdateOwner {
+		saved.Owner = service.Owner
+	}
+	s.touchService(saved, s.now())
+	return nil
+}
+
+// UpdateServiceToken 更新服务token
+func (s *memoryStore) UpdateServiceToken(serviceID string, token string, revision string) error {
+	return s.UpdateServiceTokenTx(nil, serviceID, token, revision)
+}
+
+// UpdateServiceTokenTx 在事务中更新服务token
+func (s *memoryStore) UpdateServiceTokenTx(tx store.Tx, serviceID string, token string, revision string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mtx, err := checkTx(tx)
+	if err != nil {
+		return err
+	}
+	saved, ok := s.services[serviceID]
+	if !ok || !saved.Valid {
+		return store.NewStatusError(store.AffectedRowsNotMatch, "service not found: "+serviceID)
+	}
+	snapshot(mtx, s.services, serviceID)
+	saved.Token = token
+	saved.Revision = revision
+	s.touchService(saved, s.now())
+	return nil
+}
+
+// GetSourceServiceToken 获取源服务的token信息
+func (s *memoryStore) GetSourceServiceToken(name string, namespace string) (*model.Service, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	saved := s.findService(name, namespace)
+	if saved == nil || !saved.Valid {
+		return nil, nil
+	}
+	return &model.Service{
+		ID:         saved.ID,
+		Name:       saved.Name,
+		Namespace:  saved.Namespace,
+		Token:      saved.Token,
+		PlatformID: saved.PlatformID,
+	}, nil
+}
+
+// GetService 根据服务名和命名空间获取服务的详情
+func (s *memoryStore) GetService(name string, namespace string) (*model.Service, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	saved := s.findService(name, namespace)
+	if saved == nil || !saved.Valid {
+		return nil, nil
+	}
+	return cloneService(saved), nil
+}
+
+// GetServiceByID 根据服务ID查询服务详情
+func (s *memoryStore) GetServiceByID(id string) (*model.Service, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	saved, ok := s.services[id]
+	if !ok || !saved.Valid {
+		return nil, nil
+	}
+	return cloneService(saved), nil
+}
+
+// GetServices 根据相关条件查询对应服务及数目，结果按照 mtime 倒序排列
+func (s *memoryStore) GetServices(serviceFilters, serviceMetas map[string]string,
+	instanceFilters *model.InstanceArgs, offset, limit uint32) (uint32, []*model.Service, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ret := make([]*model.Service, 0, len(s.services))
+	for _, svc := range s.services {
+		if !svc.Valid || !matchFilters(serviceFilters, serviceGetters(svc)) {
+			continue
+		}
+		if !matchMetadata(serviceMetas, svc.Meta) {
+			continue
+		}
+		if instanceFilters != nil && !s.serviceHasInstance(svc.ID, instanceFilters) {
+			continue
+		}
+		ret = append(ret, cloneService(svc))
+	}
+	sortServices(ret)
+	return uint32(len(ret)), paginate(ret, offset, limit), nil
+}
+
+// GetServicesCount 获取所有服务总数
+func (s *memoryStore) GetServicesCount() (uint32, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var count uint32
+	for _, svc := range s.services {
+		if svc.Valid {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// GetMoreServices 获取增量services，disableBusiness 为 true 时只返回系统服务
+func (s *memoryStore) GetMoreServices(mtime time.Time, firstUpdate, disableBusiness, needMeta bool) (
+	map[string]*model.Service, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ret := make(map[string]*model.Service)
+	for _, svc := range s.services {
+		if !isIncrement(svc.ModifyTime, mtime, svc.Valid, firstUpdate) {
+			continue
+		}
+		if disableBusiness && svc.Namespace != systemNamespace {
+			continue
+		}
+		item := cloneService(svc)
+		if !needMeta {
+			item.Meta = nil
+		}
+		ret[item.ID] = item
+	}
+	return ret, nil
+}
+
+// GetServiceAliases 获取服务别名列表
+func (s *memoryStore) GetServiceAliases(filter map[string]string, offset uint32, limit uint32) (
+	uint32, []*model.ServiceAlias, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ret := make([]*model.ServiceAlias, 0)
+	for _, svc := range s.services {
+		if !svc.Valid || svc.Reference == "" {
+			continue
+		}
+		source, ok := s.services[svc.Reference]
+		if !ok || !source.Valid {
+			continue
+		}
+		alias := &model.ServiceAlias{
+			ID:             svc.ID,
+			Alias:          svc.Name,
+			AliasNamespace: svc.Namespace,
+			ServiceID:      source.ID,
+			Service:        source.Name,
+			Namespace:      source.Namespace,
+			Owner:          svc.Owner,
+			Comment:        svc.Comment,
+			CreateTime:     svc.CreateTime,
+			ModifyTime:     svc.ModifyTime,
+			ExportTo:       cloneSet(svc.ExportTo),
+		}
+		getters := map[string]func() string{
+			"alias":           func() string { return alias.Alias },
+			"alias_namespace": func() string { return alias.AliasNamespace },
+			"service":         func() string { return alias.Service },
+			"namespace":       func() string { return alias.Namespace },
+			"owner":           func() string { return alias.Owner },
+		}
+		if !matchFilters(filter, getters) {
+			continue
+		}
+		ret = append(ret, alias)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if !ret[i].ModifyTime.Equal(ret[j].ModifyTime) {
+			return ret[i].ModifyTime.After(ret[j].ModifyTime)
+		}
+		return ret[i].ID < ret[j].ID
+	})
+	return uint32(len(ret)), paginate(ret, offset, limit), nil
+}
+
+// GetSystemServices 获取系统服务
+func (s *memoryStore) GetSystemServices() ([]*model.Service, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ret := make([]*model.Service, 0)
+	for _, svc := range s.services {
+		if svc.Valid && svc.Namespace == systemNamespace {
+			ret = append(ret, cloneService(svc))
+		}
+	}
+	sortServices(ret)
+	return ret, nil
+}
+
+// GetServicesBatch 批量获取服务id、负责人等信息
+func (s *memoryStore) GetServicesBatch(services []*model.Service) ([]*model.Service, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ret := make([]*model.Service, 0, len(services))
+	for _, item := range services {
+		saved := s.findService(item.Name, item.Namespace)
+		if saved == nil || !saved.Valid {
+			continue
+		}
+		ret = append(ret, cloneService(saved))
+	}
+	return ret, nil
+}
+
+// findService 根据服务名以及命名空间查找服务，包括已经软删除的服务，调用方需要持有锁
+func (s *memoryStore) findService(name, namespace string) *model.Service {
+	var found *model.Service
+	for _, svc := range s.services {
+		if svc.Name != name || svc.Namespace != namespace {
+			continue
+		}
+		// 同名服务优先返回有效的记录
+		if found == nil || svc.Valid {
+			found = svc
+		}
+	}
+	return found
+}
+
+// serviceHasInstance 判断服务下是否存在满足条件的有效实例，调用方需要持有锁
+func (s *memoryStore) serviceHasInstance(serviceID string, args *model.InstanceArgs) bool {
+	for _, ins := range s.instances {
+		if !ins.Valid || ins.ServiceID != serviceID {
+			continue
+		}
+		if len(args.Hosts) > 0 && !containsString(args.Hosts, ins.Proto.GetHost().GetValue()) {
+			continue
+		}
+		if len(args.Ports) > 0 && !containsUint32(args.Ports, ins.Proto.GetPort().GetValue()) {
+			continue
+		}
+		if !matchMetadata(args.Meta, ins.Proto.GetMetadata()) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func (s *memoryStore) touchService(svc *model.Service, now time.Time) {
+	svc.ModifyTime = now
+	svc.Mtime = now.Unix()
+}
+
+func serviceGetters(svc *model.Service) map[string]func() string {
+	return map[string]func() string{
+		"id":          func() string { return svc.ID },
+		"name":        func() string { return svc.Name },
+		"namespace":   func() string { return svc.Namespace },
+		"business":    func() string { return svc.Business },
+		"department":  func() string { return svc.Department },
+		"owner":       func() string { return svc.Owner },
+		"cmdb_mod1":   func() string { return svc.CmdbMod1 },
+		"cmdb_mod2":   func() string { return svc.CmdbMod2 },
+		"cmdb_mod3":   func() string { return svc.CmdbMod3 },
+		"platform_id": func() string { return svc.PlatformID },
+		"reference":   func() string { return svc.Reference },
+	}
+}
+
+// matchMetadata 判断 metadata 是否包含 filter 中所有的键值对
+func matchMetadata(filter, metadata map[string]string) bool {
+	for k, v := range filter {
+		value, ok := metadata[k]
+		if !ok {
+			return false
+		}
+		if v != "" && !matchString(v, value) {
+			return false
+		}
+	}
+	return true
+}
+
+func sortServices(services []*model.Service) {
+	sort.Slice(services, func(i, j int) bool {
+		if !services[i].ModifyTime.Equal(services[j].ModifyTime) {
+			return services[i].ModifyTime.After(services[j].ModifyTime)
+		}
+		return strings.Compare(services[i].ID, services[j].ID) < 0
+	})
+}
+
+func cloneService(svc *model.Service) *model.Service {
+	ret := *svc
+	ret.Meta = cloneStrings(svc.Meta)
+	ret.ExportTo = cloneSet(svc.ExportTo)
+	ret.ServicePorts = cloneServicePorts(svc.ServicePorts)
+	return &ret
+}
+
+func cloneServicePorts(ports []*model.ServicePort) []*model.ServicePort {
+	if ports == nil {
+		return nil
+	}
+	ret := make([]*model.ServicePort, 0, len(ports))
+	for _, port := range ports {
+		item := *port
+		ret = append(ret, &item)
+	}
+	return ret
+}
+
+func containsString(items []string, target string) bool {
+	for _, item := range items {
+		if item == target {
+			return true
+		}
+	}
+	return false
+}
+
+func containsUint32(items []uint32, target uint32) bool {
+	for _, item := range items {
+		if item == target {
+			return true
+		}
+	}
+	return false
+}
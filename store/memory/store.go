@@ -0,0 +1,264 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package memory
+
+import (
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/internal/keylock"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+const (
+	// StoreName 内存存储插件的名字
+	StoreName = "memory"
+
+	defaultLockTimeout = 5 * time.Second
+)
+
+func init() {
+	store.Register(StoreName, New())
+}
+
+var (
+	_ store.Store                = (*memoryStore)(nil)
+	_ store.NamingTxStore        = (*memoryStore)(nil)
+	_ store.DistributedLockStore = (*memoryStore)(nil)
+	_ store.LeaderWatchStore     = (*memoryStore)(nil)
+)
+
+// memoryStore 基于内存的 store.Store 参考实现，不依赖任何数据库，主要用于插件开发以及单元测试
+type memoryStore struct {
+	lock sync.RWMutex
+	// now 获取当前时间，用于 CreateTime/ModifyTime 以及增量查询，可通过 WithClock 替换
+	now func() time.Time
+	// host 当前节点的地址，用于 leader 选举
+	host string
+	// locks 事务中 LockNamespace、LockService 等方法持有的资源锁，lockTimeout 为等待资源锁的超时时间
+	locks       *keylock.Locks
+	lockTimeout time.Duration
+
+	namespaces map[string]*model.Namespace
+	// services serviceID -> service, 服务别名同样保存在这里
+	services map[string]*model.Service
+	// instances instanceID -> instance
+	instances        map[string]*model.Instance
+	l5Extends        map[string]*l5Extend
+	l5Sid            map[uint32]uint32
+	routingConfigs   map[string]*model.RoutingConfig
+	routerConfigs    map[string]*model.RouterConfig
+	rateLimits       map[string]*model.RateLimit
+	circuitBreakers  map[string]*model.CircuitBreakerRule
+	faultDetectRules map[string]*model.FaultDetectRule
+	contracts        map[string]*model.ServiceContract
+	clients          map[string]*model.Client
+	grayResources    map[string]*model.GrayResource
+	leaders          map[string]*model.LeaderElection
+	bootstraps       map[string]string
+	distLocks        map[string]*model.DistributedLock
+
+	configGroups      map[string]*model.ConfigFileGroup
+	configFiles       map[string]*model.ConfigFile
+	configReleases    map[string]*model.ConfigFileRelease
+	configHistories   []*model.ConfigFileReleaseHistory
+	configTemplates   map[string]*model.ConfigFileTemplate
+	configIDGenerator uint64
+
+	users      map[string]*model.User
+	groups     map[string]*model.UserGroup
+	strategies map[string]*model.StrategyDetail
+}
+
+// Option 内存存储的可选配置
+type Option func(s *memoryStore)
+
+// WithClock 设置内存存储获取当前时间的方法，便于测试中控制 mtime
+func WithClock(now func() time.Time) Option {
+	return func(s *memoryStore) {
+		s.now = now
+	}
+}
+
+// WithHost 设置当前节点的地址，默认为本机的 hostname
+func WithHost(host string) Option {
+	return func(s *memoryStore) {
+		s.host = host
+	}
+}
+
+// WithLockTimeout 设置事务等待资源锁的超时时间，超时后返回 store.DeadlockErr，默认 5s
+func WithLockTimeout(timeout time.Duration) Option {
+	return func(s *memoryStore) {
+		if timeout > 0 {
+			s.lockTimeout = timeout
+		}
+	}
+}
+
+// New 创建一个空的内存存储
+func New(options ...Option) store.Store {
+	s := &memoryStore{
+		now:         time.Now,
+		host:        localHost(),
+		locks:       keylock.New(),
+		lockTimeout: defaultLockTimeout,
+	}
+	s.reset()
+	for i := range options {
+		options[i](s)
+	}
+	return s
+}
+
+func (s *memoryStore) reset() {
+	s.namespaces = map[string]*model.Namespace{}
+	s.services = map[string]*model.Service{}
+	s.instances = map[string]*model.Instance{}
+	s.l5Extends = map[string]*l5Extend{}
+	s.l5Sid = map[uint32]uint32{}
+	s.routingConfigs = map[string]*model.RoutingConfig{}
+	s.routerConfigs = map[string]*model.RouterConfig{}
+	s.rateLimits = map[string]*model.RateLimit{}
+	s.circuitBreakers = map[string]*model.CircuitBreakerRule{}
+	s.faultDetectRules = map[string]*model.FaultDetectRule{}
+	s.contracts = map[string]*model.ServiceContract{}
+	s.clients = map[string]*model.Client{}
+	s.grayResources = map[string]*model.GrayResource{}
+	s.leaders = map[string]*model.LeaderElection{}
+	s.bootstraps = map[string]string{}
+	s.distLocks = map[string]*model.DistributedLock{}
+	s.configGroups = map[string]*model.ConfigFileGroup{}
+	s.configFiles = map[string]*model.ConfigFile{}
+	s.configReleases = map[string]*model.ConfigFileRelease{}
+	s.configHistories = []*model.ConfigFileReleaseHistory{}
+	s.configTemplates = map[string]*model.ConfigFileTemplate{}
+	s.configIDGenerator = 0
+	s.users = map[string]*model.User{}
+	s.groups = map[string]*model.UserGroup{}
+	s.strategies = map[string]*model.StrategyDetail{}
+}
+
+// Name 存储层的名字
+func (s *memoryStore) Name() string {
+	return StoreName
+}
+
+// Initialize 存储的初始化函数
+func (s *memoryStore) Initialize(c *store.Config) error {
+	return nil
+}
+
+// Destroy 存储的析构函数，清空所有数据
+func (s *memoryStore) Destroy() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.reset()
+	return nil
+}
+
+func localHost() string {
+	host, err := os.Hostname()
+	if err != nil {
+		return "127.0.0.1"
+	}
+	return host
+}
+
+func (s *memoryStore) nextID() uint64 {
+	s.configIDGenerator++
+	return s.configIDGenerator
+}
+
+// matchString 判断 value 是否满足过滤条件，以 * 结尾表示前缀匹配
+func matchString(pattern, value string) bool {
+	if strings.HasSuffix(pattern, "*") {
+		return strings.HasPrefix(value, strings.TrimSuffix(pattern, "*"))
+	}
+	return pattern == value
+}
+
+// matchFilters 判断 getters 取出的字段是否满足所有的过滤条件，未知的过滤字段会被忽略
+func matchFilters(filter map[string]string, getters map[string]func() string) bool {
+	for key, pattern := range filter {
+		getter, ok := getters[key]
+		if !ok {
+			continue
+		}
+		if !matchString(pattern, getter()) {
+			return false
+		}
+	}
+	return true
+}
+
+// paginate 根据 offset 以及 limit 截取数据
+func paginate[T any](items []T, offset, limit uint32) []T {
+	if int(offset) >= len(items) {
+		return []T{}
+	}
+	end := len(items)
+	if limit > 0 && int(offset+limit) < end {
+		end = int(offset + limit)
+	}
+	return items[offset:end]
+}
+
+// isIncrement 判断数据是否需要通过 GetMore* 返回，mtime 之后（包含 mtime）变更的数据都需要返回，
+// 首次全量加载时只返回有效数据
+func isIncrement(modifyTime, mtime time.Time, valid, firstUpdate bool) bool {
+	if firstUpdate && !valid {
+		return false
+	}
+	return !modifyTime.Before(mtime)
+}
+
+// modifyBy 本次修改没有指定操作人时保留原有的操作人
+func modifyBy(operator, saved string) string {
+	if operator == "" {
+		return saved
+	}
+	return operator
+}
+
+func cloneStrings(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	ret := make(map[string]string, len(m))
+	for k, v := range m {
+		ret[k] = v
+	}
+	return ret
+}
+
+// sortByModifyTime 按照修改时间倒序排列，修改时间相同时按照 ID 排序保证结果稳定
+func sortByModifyTime[T any](items []T, mtime func(T) time.Time, id func(T) string) {
+	sort.Slice(items, func(i, j int) bool {
+		ti, tj := mtime(items[i]), mtime(items[j])
+		if !ti.Equal(tj) {
+			return ti.After(tj)
+		}
+		return id(items[i]) < id(items[j])
+	})
+}
@@ -0,0 +1,216 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+var _ store.StreamStore = (*memoryStore)(nil)
+
+// rangeItems 分批遍历 items 返回的数据中满足 match 的数据：先在读锁中收集满足条件的主键并排序，
+// 之后每批数据在读锁中重新读取、再次过滤并由 clone 复制，回调 handler 时不持有锁，
+// 因此内存中只保留一批数据的副本，handler 中可以继续访问存储
+func rangeItems[T any](s *memoryStore, items func() map[string]*T, match func(item *T) bool, clone func(item *T) *T,
+	batchSize int, handler store.BatchHandler[*T]) error {
+	if batchSize <= 0 {
+		batchSize = store.DefaultRangeBatchSize
+	}
+	s.lock.RLock()
+	keys := make([]string, 0)
+	for key, item := range items() {
+		if match(item) {
+			keys = append(keys, key)
+		}
+	}
+	s.lock.RUnlock()
+	sort.Strings(keys)
+
+	for start := 0; start < len(keys); start += batchSize {
+		end := min(start+batchSize, len(keys))
+		batch := make([]*T, 0, end-start)
+		s.lock.RLock()
+		saved := items()
+		for _, key := range keys[start:end] {
+			if item, ok := saved[key]; ok && match(item) {
+				batch = append(batch, clone(item))
+			}
+		}
+		s.lock.RUnlock()
+		if len(batch) == 0 {
+			continue
+		}
+		next, err := handler(batch)
+		if err != nil || !next {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyOf 浅拷贝，用于没有可变字段的数据
+func copyOf[T any](item *T) *T {
+	copied := *item
+	return &copied
+}
+
+// RangeMoreNamespaces 实现 store.StreamStore
+func (s *memoryStore) RangeMoreNamespaces(mtime time.Time, batchSize int, handler store.BatchHandler[*model.Namespace]) error {
+	return rangeItems(s, func() map[string]*model.Namespace { return s.namespaces }, func(ns *model.Namespace) bool {
+		return isIncrement(ns.ModifyTime, mtime, ns.Valid, false)
+	}, cloneNamespace, batchSize, handler)
+}
+
+// RangeMoreServices 实现 store.StreamStore
+func (s *memoryStore) RangeMoreServices(mtime time.Time, firstUpdate, disableBusiness, needMeta bool, batchSize int,
+	handler store.BatchHandler[*model.Service]) error {
+	return rangeItems(s, func() map[string]*model.Service { return s.services }, func(svc *model.Service) bool {
+		return isIncrement(svc.ModifyTime, mtime, svc.Valid, firstUpdate) &&
+			(!disableBusiness || svc.Namespace == systemNamespace)
+	}, func(svc *model.Service) *model.Service {
+		item := cloneService(svc)
+		if !needMeta {
+			item.Meta = nil
+		}
+		return item
+	}, batchSize, handler)
+}
+
+// RangeMoreInstances 实现 store.StreamStore
+func (s *memoryStore) RangeMoreInstances(tx store.Tx, mtime time.Time, firstUpdate, needMeta bool, serviceID []string,
+	batchSize int, handler store.BatchHandler[*model.Instance]) error {
+	return rangeItems(s, func() map[string]*model.Instance { return s.instances }, func(ins *model.Instance) bool {
+		return isIncrement(ins.ModifyTime, mtime, ins.Valid, firstUpdate) &&
+			(len(serviceID) == 0 || containsString(serviceID, ins.ServiceID))
+	}, func(ins *model.Instance) *model.Instance {
+		item := cloneInstance(ins)
+		if !needMeta {
+			item.Proto.Metadata = nil
+		}
+		return item
+	}, batchSize, handler)
+}
+
+// RangeMoreClients 实现 store.StreamStore
+func (s *memoryStore) RangeMoreClients(mtime time.Time, firstUpdate bool, batchSize int, handler store.BatchHandler[*model.Client]) error {
+	return rangeItems(s, func() map[string]*model.Client { return s.clients }, func(client *model.Client) bool {
+		return isIncrement(client.ModifyTime, mtime, client.Valid, firstUpdate)
+	}, cloneClient, batchSize, handler)
+}
+
+// RangeMoreServiceContracts 实现 store.StreamStore
+func (s *memoryStore) RangeMoreServiceContracts(firstUpdate bool, mtime time.Time, batchSize int,
+	handler store.BatchHandler[*model.ServiceContract]) error {
+	return rangeItems(s, func() map[string]*model.ServiceContract { return s.contracts }, func(item *model.ServiceContract) bool {
+		return isIncrement(item.ModifyTime, mtime, item.Valid, firstUpdate)
+	}, cloneServiceContract, batchSize, handler)
+}
+
+// RangeMoreGrayResources 实现 store.StreamStore
+func (s *memoryStore) RangeMoreGrayResources(firstUpdate bool, mtime time.Time, batchSize int,
+	handler store.BatchHandler[*model.GrayResource]) error {
+	return rangeItems(s, func() map[string]*model.GrayResource { return s.grayResources }, func(item *model.GrayResource) bool {
+		return isIncrement(item.ModifyTime, mtime, item.Valid, firstUpdate)
+	}, copyOf[model.GrayResource], batchSize, handler)
+}
+
+// RangeRoutingConfigsForCache 实现 store.StreamStore
+func (s *memoryStore) RangeRoutingConfigsForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler store.BatchHandler[*model.RoutingConfig]) error {
+	return rangeItems(s, func() map[string]*model.RoutingConfig { return s.routingConfigs }, func(conf *model.RoutingConfig) bool {
+		return isIncrement(conf.ModifyTime, mtime, conf.Valid, firstUpdate)
+	}, s.fillRoutingConfig, batchSize, handler)
+}
+
+// RangeRoutingConfigsV2ForCache 实现 store.StreamStore
+func (s *memoryStore) RangeRoutingConfigsV2ForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler store.BatchHandler[*model.RouterConfig]) error {
+	return rangeItems(s, func() map[string]*model.RouterConfig { return s.routerConfigs }, func(conf *model.RouterConfig) bool {
+		return isIncrement(conf.ModifyTime, mtime, conf.Valid, firstUpdate)
+	}, copyOf[model.RouterConfig], batchSize, handler)
+}
+
+// RangeRateLimitsForCache 实现 store.StreamStore
+func (s *memoryStore) RangeRateLimitsForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler store.BatchHandler[*model.RateLimit]) error {
+	return rangeItems(s, func() map[string]*model.RateLimit { return s.rateLimits }, func(item *model.RateLimit) bool {
+		return isIncrement(item.ModifyTime, mtime, item.Valid, firstUpdate)
+	}, cloneRateLimit, batchSize, handler)
+}
+
+// RangeCircuitBreakerRulesForCache 实现 store.StreamStore
+func (s *memoryStore) RangeCircuitBreakerRulesForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler store.BatchHandler[*model.CircuitBreakerRule]) error {
+	return rangeItems(s, func() map[string]*model.CircuitBreakerRule { return s.circuitBreakers },
+		func(item *model.CircuitBreakerRule) bool {
+			return isIncrement(item.ModifyTime, mtime, item.Valid, firstUpdate)
+		}, cloneCircuitBreakerRule, batchSize, handler)
+}
+
+// RangeFaultDetectRulesForCache 实现 store.StreamStore
+func (s *memoryStore) RangeFaultDetectRulesForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler store.BatchHandler[*model.FaultDetectRule]) error {
+	return rangeItems(s, func() map[string]*model.FaultDetectRule { return s.faultDetectRules },
+		func(item *model.FaultDetectRule) bool {
+			return isIncrement(item.ModifyTime, mtime, item.Valid, firstUpdate)
+		}, cloneFaultDetectRule, batchSize, handler)
+}
+
+// RangeMoreConfigGroup 实现 store.StreamStore
+func (s *memoryStore) RangeMoreConfigGroup(firstUpdate bool, mtime time.Time, batchSize int,
+	handler store.BatchHandler[*model.ConfigFileGroup]) error {
+	return rangeItems(s, func() map[string]*model.ConfigFileGroup { return s.configGroups }, func(item *model.ConfigFileGroup) bool {
+		return isIncrement(item.ModifyTime, mtime, item.Valid, firstUpdate)
+	}, cloneConfigFileGroup, batchSize, handler)
+}
+
+// RangeMoreReleaseFile 实现 store.StreamStore
+func (s *memoryStore) RangeMoreReleaseFile(firstUpdate bool, modifyTime time.Time, batchSize int,
+	handler store.BatchHandler[*model.ConfigFileRelease]) error {
+	return rangeItems(s, func() map[string]*model.ConfigFileRelease { return s.configReleases },
+		func(item *model.ConfigFileRelease) bool {
+			return isIncrement(item.ModifyTime, modifyTime, item.Valid, firstUpdate)
+		}, cloneConfigFileRelease, batchSize, handler)
+}
+
+// RangeUsersForCache 实现 store.StreamStore
+func (s *memoryStore) RangeUsersForCache(mtime time.Time, firstUpdate bool, batchSize int, handler store.BatchHandler[*model.User]) error {
+	return rangeItems(s, func() map[string]*model.User { return s.users }, func(item *model.User) bool {
+		return isIncrement(item.ModifyTime, mtime, item.Valid, firstUpdate)
+	}, copyOf[model.User], batchSize, handler)
+}
+
+// RangeGroupsForCache 实现 store.StreamStore
+func (s *memoryStore) RangeGroupsForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler store.BatchHandler[*model.UserGroup]) error {
+	return rangeItems(s, func() map[string]*model.UserGroup { return s.groups }, func(item *model.UserGroup) bool {
+		return isIncrement(item.ModifyTime, mtime, item.Valid, firstUpdate)
+	}, cloneUserGroup, batchSize, handler)
+}
+
+// RangeStrategyDetailsForCache 实现 store.StreamStore
+func (s *memoryStore) RangeStrategyDetailsForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler store.BatchHandler[*model.StrategyDetail]) error {
+	return rangeItems(s, func() map[string]*model.StrategyDetail { return s.strategies }, func(item *model.StrategyDetail) bool {
+		return isIncrement(item.ModifyTime, mtime, item.Valid, firstUpdate)
+	}, cloneStrategy, batchSize, handler)
+}
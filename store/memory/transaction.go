@@ -0,0 +1,391 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package memory
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/internal/keylock"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+var _ store.TxStore = (*memoryStore)(nil)
+
+// CreateTransaction 创建事务对象
+func (s *memoryStore) CreateTransaction() (store.Transaction, error) {
+	return &transaction{s: s}, nil
+}
+
+// StartTx 开启一个原子事务
+func (s *memoryStore) StartTx() (store.Tx, error) {
+	return &memoryTx{s: s}, nil
+}
+
+// StartReadTx 开启一个只读事务
+func (s *memoryStore) StartReadTx() (store.Tx, error) {
+	return &memoryTx{s: s, readOnly: true}, nil
+}
+
+// BeginTx 开启事务，内存事务不提供隔离能力，只支持 store.IsolationDefault
+func (s *memoryStore) BeginTx(opts store.TxOptions) (store.UnifiedTx, error) {
+	if opts.Isolation != store.IsolationDefault {
+		return nil, store.NewStatusError(store.NotSupportedErr, "unsupported isolation level: "+opts.Isolation.String())
+	}
+	return &memoryTx{s: s, readOnly: opts.ReadOnly}, nil
+}
+
+// memoryTx 内存存储的事务，写操作会立即生效并记录回滚动作，Rollback 时按照相反的顺序撤销，
+// 内存事务不提供隔离能力；LockNamespace、LockService 等方法获取的资源锁在事务结束时释放
+type memoryTx struct {
+	s        *memoryStore
+	readOnly bool
+	finished bool
+	undo     []func()
+	// savepoints 保存点以及创建时 undo 的长度
+	savepoints []savepoint
+	// held 事务持有的资源锁，由 heldLock 保护
+	heldLock sync.Mutex
+	held     keylock.Held
+}
+
+// savepoint 内存事务的保存点
+type savepoint struct {
+	name string
+	undo int
+}
+
+// Commit 提交事务并释放所有的资源锁
+func (tx *memoryTx) Commit() error {
+	tx.s.lock.Lock()
+	if tx.finished {
+		tx.s.lock.Unlock()
+		return store.NewStatusError(store.EmptyParamsErr, "transaction already finished")
+	}
+	tx.finished = true
+	tx.undo = nil
+	tx.savepoints = nil
+	tx.s.lock.Unlock()
+
+	tx.release()
+	return nil
+}
+
+// Rollback 回滚事务并释放所有的资源锁
+func (tx *memoryTx) Rollback() error {
+	tx.s.lock.Lock()
+	if tx.finished {
+		tx.s.lock.Unlock()
+		return nil
+	}
+	tx.finished = true
+	tx.revert(0)
+	tx.savepoints = nil
+	tx.s.lock.Unlock()
+
+	tx.release()
+	return nil
+}
+
+// acquire 获取资源锁，等待超过 lockTimeout 时返回 store.DeadlockErr，不能在持有 s.lock 时调用
+func (tx *memoryTx) acquire(key string, exclusive bool) error {
+	tx.heldLock.Lock()
+	defer tx.heldLock.Unlock()
+
+	tx.s.lock.RLock()
+	finished := tx.finished
+	tx.s.lock.RUnlock()
+	if finished {
+		return store.NewStatusError(store.EmptyParamsErr, "transaction already finished")
+	}
+	return tx.held.Acquire(tx.s.locks, key, exclusive, tx.s.lockTimeout)
+}
+
+// release 释放所有的资源锁，事务结束之后调用
+func (tx *memoryTx) release() {
+	tx.heldLock.Lock()
+	defer tx.heldLock.Unlock()
+
+	tx.held.Release()
+}
+
+// revert 按照相反的顺序撤销第 n 个回滚动作之后的修改，调用方需要持有写锁
+func (tx *memoryTx) revert(n int) {
+	for i := len(tx.undo) - 1; i >= n; i-- {
+		tx.undo[i]()
+	}
+	tx.undo = tx.undo[:n]
+}
+
+// GetDelegateTx 获取原始的事务对象
+func (tx *memoryTx) GetDelegateTx() interface{} {
+	return tx
+}
+
+// CreateReadView 内存存储的数据实时可见，无需创建快照
+func (tx *memoryTx) CreateReadView() error {
+	return nil
+}
+
+// Savepoint 创建保存点
+func (tx *memoryTx) Savepoint(name string) error {
+	if err := store.ValidateSavepoint(name); err != nil {
+		return err
+	}
+	tx.s.lock.Lock()
+	defer tx.s.lock.Unlock()
+
+	if tx.finished {
+		return store.NewStatusError(store.EmptyParamsErr, "transaction already finished")
+	}
+	tx.savepoints = append(tx.savepoints, savepoint{name: name, undo: len(tx.undo)})
+	return nil
+}
+
+// RollbackToSavepoint 撤销保存点之后的修改，并删除在它之后创建的保存点
+func (tx *memoryTx) RollbackToSavepoint(name string) error {
+	tx.s.lock.Lock()
+	defer tx.s.lock.Unlock()
+
+	index, err := tx.findSavepoint(name)
+	if err != nil {
+		return err
+	}
+	tx.revert(tx.savepoints[index].undo)
+	tx.savepoints = tx.savepoints[:index+1]
+	return nil
+}
+
+// ReleaseSavepoint 删除保存点以及在它之后创建的保存点
+func (tx *memoryTx) ReleaseSavepoint(name string) error {
+	tx.s.lock.Lock()
+	defer tx.s.lock.Unlock()
+
+	index, err := tx.findSavepoint(name)
+	if err != nil {
+		return err
+	}
+	tx.savepoints = tx.savepoints[:index]
+	return nil
+}
+
+// findSavepoint 返回最近创建的同名保存点，调用方需要持有写锁
+func (tx *memoryTx) findSavepoint(name string) (int, error) {
+	if tx.finished {
+		return 0, store.NewStatusError(store.EmptyParamsErr, "transaction already finished")
+	}
+	for i := len(tx.savepoints) - 1; i >= 0; i-- {
+		if tx.savepoints[i].name == name {
+			return i, nil
+		}
+	}
+	return 0, store.NewStatusError(store.NotFoundResource, "savepoint not found: "+name)
+}
+
+// LockBootstrap 记录 server 启动锁的持有者，事务回滚时恢复，其他事务在当前事务结束之前无法获取同一个启动锁
+func (tx *memoryTx) LockBootstrap(key string, server string) error {
+	if err := tx.acquire("bootstrap/"+key, true); err != nil {
+		return err
+	}
+	tx.s.lock.Lock()
+	defer tx.s.lock.Unlock()
+
+	if _, err := checkTx(tx); err != nil {
+		return err
+	}
+	old, exist := tx.s.bootstraps[key]
+	tx.onRollback(func() {
+		if !exist {
+			delete(tx.s.bootstraps, key)
+			return
+		}
+		tx.s.bootstraps[key] = old
+	})
+	tx.s.bootstraps[key] = server
+	return nil
+}
+
+// LockNamespace 获取命名空间的排他锁，并返回有效的命名空间
+func (tx *memoryTx) LockNamespace(name string) (*model.Namespace, error) {
+	if err := tx.acquire("namespace/"+name, true); err != nil {
+		return nil, err
+	}
+	return tx.s.GetNamespace(name)
+}
+
+// DeleteNamespace 删除命名空间，实际是把 valid 置为 false，事务回滚时恢复
+func (tx *memoryTx) DeleteNamespace(name string) error {
+	tx.s.lock.Lock()
+	defer tx.s.lock.Unlock()
+
+	if _, err := checkTx(tx); err != nil {
+		return err
+	}
+	ns, ok := tx.s.namespaces[name]
+	if !ok {
+		return nil
+	}
+	snapshot(tx, tx.s.namespaces, name)
+	ns.Valid = false
+	ns.ModifyTime = tx.s.now()
+	return nil
+}
+
+// LockService 获取服务的排他锁，并返回有效的服务
+func (tx *memoryTx) LockService(name string, namespace string) (*model.Service, error) {
+	if err := tx.acquire("service/"+namespace+"/"+name, true); err != nil {
+		return nil, err
+	}
+	return tx.s.GetService(name, namespace)
+}
+
+// RLockService 获取服务的共享锁，并返回有效的服务
+func (tx *memoryTx) RLockService(name string, namespace string) (*model.Service, error) {
+	if err := tx.acquire("service/"+namespace+"/"+name, false); err != nil {
+		return nil, err
+	}
+	return tx.s.GetService(name, namespace)
+}
+
+// checkTx 检查事务是否可用于写操作，调用方需要持有写锁
+func checkTx(tx store.Tx) (*memoryTx, error) {
+	if tx == nil {
+		return nil, nil
+	}
+	mtx, ok := tx.GetDelegateTx().(*memoryTx)
+	if !ok {
+		return nil, store.NewStatusError(store.EmptyParamsErr, fmt.Sprintf("unsupported tx type %T", tx))
+	}
+	if mtx.finished {
+		return nil, store.NewStatusError(store.EmptyParamsErr, "transaction already finished")
+	}
+	if mtx.readOnly {
+		return nil, store.NewStatusError(store.EmptyParamsErr, "write operation in read only transaction")
+	}
+	return mtx, nil
+}
+
+// onRollback 记录事务回滚时需要执行的动作，tx 为空时表示非事务操作
+func (tx *memoryTx) onRollback(f func()) {
+	if tx == nil {
+		return
+	}
+	tx.undo = append(tx.undo, f)
+}
+
+// transaction 内存存储的 store.Transaction 实现，LockNamespace、LockService 获取资源的排他锁，
+// RLockService 获取资源的共享锁，锁在 Commit 时释放
+type transaction struct {
+	s *memoryStore
+
+	lock     sync.Mutex
+	held     keylock.Held
+	finished bool
+}
+
+// Commit 提交事务并释放所有的资源锁
+func (t *transaction) Commit() error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.finished = true
+	t.held.Release()
+	return nil
+}
+
+// acquire 获取资源锁，等待超过 lockTimeout 时返回 store.DeadlockErr
+func (t *transaction) acquire(key string, exclusive bool) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.finished {
+		return store.NewStatusError(store.EmptyParamsErr, "transaction already finished")
+	}
+	return t.held.Acquire(t.s.locks, key, exclusive, t.s.lockTimeout)
+}
+
+// LockBootstrap 记录 server 启动锁的持有者，其他事务在当前事务结束之前无法获取同一个启动锁
+func (t *transaction) LockBootstrap(key string, server string) error {
+	if err := t.acquire("bootstrap/"+key, true); err != nil {
+		return err
+	}
+	t.s.lock.Lock()
+	defer t.s.lock.Unlock()
+
+	t.s.bootstraps[key] = server
+	return nil
+}
+
+// LockNamespace 获取命名空间的排他锁，并返回有效的命名空间
+func (t *transaction) LockNamespace(name string) (*model.Namespace, error) {
+	if err := t.acquire("namespace/"+name, true); err != nil {
+		return nil, err
+	}
+	return t.s.GetNamespace(name)
+}
+
+// DeleteNamespace 删除命名空间，实际是把 valid 置为 false
+func (t *transaction) DeleteNamespace(name string) error {
+	t.s.lock.Lock()
+	defer t.s.lock.Unlock()
+
+	ns, ok := t.s.namespaces[name]
+	if !ok {
+		return nil
+	}
+	ns.Valid = false
+	ns.ModifyTime = t.s.now()
+	return nil
+}
+
+// LockService 获取服务的排他锁，并返回有效的服务
+func (t *transaction) LockService(name string, namespace string) (*model.Service, error) {
+	if err := t.acquire("service/"+namespace+"/"+name, true); err != nil {
+		return nil, err
+	}
+	return t.s.GetService(name, namespace)
+}
+
+// RLockService 获取服务的共享锁，并返回有效的服务
+func (t *transaction) RLockService(name string, namespace string) (*model.Service, error) {
+	if err := t.acquire("service/"+namespace+"/"+name, false); err != nil {
+		return nil, err
+	}
+	return t.s.GetService(name, namespace)
+}
+
+// snapshot 记录 key 在 m 中的当前状态，事务回滚时恢复，调用方需要持有写锁
+func snapshot[K comparable, V any](tx *memoryTx, m map[K]*V, key K) {
+	if tx == nil {
+		return
+	}
+	old, exist := m[key]
+	var copied V
+	if exist {
+		copied = *old
+	}
+	tx.onRollback(func() {
+		if !exist {
+			delete(m, key)
+			return
+		}
+		restored := copied
+		m[key] = &restored
+	})
+}
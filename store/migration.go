@@ -0,0 +1,185 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package store
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Migration 一个 schema 版本的变更，Up 从上一个版本升级到 Version，Down 从 Version 回退到上一个版本
+type Migration struct {
+	// Version schema 版本号，从 1 开始，同一个存储插件内唯一
+	Version uint32
+	// Description 变更的说明
+	Description string
+	// Up 升级到 Version
+	Up MigrationFunc
+	// Down 回退到上一个版本，为空时表示该版本不支持回退
+	Down MigrationFunc
+}
+
+// MigrationFunc 在 tx 中执行 schema 变更，插件通过 tx.GetDelegateTx() 获取底层的事务对象。
+// 注意 MySQL 等数据库执行 DDL 时会隐式提交事务，这类变更需要保证可以重复执行
+type MigrationFunc func(tx Tx) error
+
+// SchemaVersionStore 支持 schema 迁移的存储插件需要实现的接口，用于保存已经应用的 schema 版本
+type SchemaVersionStore interface {
+	// StartTx 开启执行迁移的事务，每个 Migration 与版本号的更新在同一个事务中执行
+	StartTx() (Tx, error)
+	// SchemaVersion 获取当前的 schema 版本，尚未执行任何迁移时返回 0
+	SchemaVersion(tx Tx) (uint32, error)
+	// SetSchemaVersion 记录当前的 schema 版本
+	SetSchemaVersion(tx Tx, version uint32) error
+}
+
+var migrations = map[string][]Migration{}
+
+// RegisterMigrations 注册存储插件 name 的 schema 迁移，版本号重复或者缺少 Up 时 panic
+func RegisterMigrations(name string, items ...Migration) {
+	registered := migrations[name]
+	for _, item := range items {
+		if item.Version == 0 || item.Up == nil {
+			panic(fmt.Sprintf("invalid migration: name=%v, version=%v", name, item.Version))
+		}
+		for _, exist := range registered {
+			if exist.Version == item.Version {
+				panic(fmt.Sprintf("existed migration: name=%v, version=%v", name, item.Version))
+			}
+		}
+		registered = append(registered, item)
+	}
+	sort.Slice(registered, func(i, j int) bool {
+		return registered[i].Version < registered[j].Version
+	})
+	migrations[name] = registered
+}
+
+// GetMigrations 获取存储插件 name 注册的全部迁移，按照版本号升序排列
+func GetMigrations(name string) []Migration {
+	return append([]Migration(nil), migrations[name]...)
+}
+
+// LatestSchemaVersion 获取存储插件 name 当前代码要求的 schema 版本，没有注册迁移时返回 0
+func LatestSchemaVersion(name string) uint32 {
+	registered := migrations[name]
+	if len(registered) == 0 {
+		return 0
+	}
+	return registered[len(registered)-1].Version
+}
+
+// CheckSchemaVersion 检查持久化的 schema 版本是否与当前代码要求的版本一致，
+// 不一致时返回 SchemaVersionMismatch，存储插件应当在 Initialize 中调用并拒绝启动
+func CheckSchemaVersion(name string, s SchemaVersionStore) error {
+	current, err := currentSchemaVersion(s)
+	if err != nil {
+		return err
+	}
+	if latest := LatestSchemaVersion(name); current != latest {
+		return NewStatusError(SchemaVersionMismatch, fmt.Sprintf(
+			"store %s schema version is %d, but %d is required, please run migrations first", name, current, latest))
+	}
+	return nil
+}
+
+// MigrateUp 将存储插件 name 的 schema 升级到最新的版本
+func MigrateUp(name string, s SchemaVersionStore) error {
+	return MigrateTo(name, s, LatestSchemaVersion(name))
+}
+
+// MigrateTo 将存储插件 name 的 schema 升级或者回退到 target 版本，target 为 0 时回退全部的迁移。
+// 每个版本在单独的事务中执行，失败时停留在最后一个成功的版本
+func MigrateTo(name string, s SchemaVersionStore, target uint32) error {
+	registered := migrations[name]
+	if target != 0 && findMigration(registered, target) < 0 {
+		return NewStatusError(EmptyParamsErr, fmt.Sprintf("unknown schema version %d for store %s", target, name))
+	}
+	current, err := currentSchemaVersion(s)
+	if err != nil {
+		return err
+	}
+	if current != 0 && findMigration(registered, current) < 0 {
+		return NewStatusError(SchemaVersionMismatch, fmt.Sprintf(
+			"store %s schema version %d is not registered, the binary may be older than the schema", name, current))
+	}
+	for i := range registered {
+		item := registered[i]
+		if item.Version <= current || item.Version > target {
+			continue
+		}
+		if err := applyMigration(s, item.Up, item.Version); err != nil {
+			return fmt.Errorf("migrate store %s up to version %d: %w", name, item.Version, err)
+		}
+	}
+	for i := len(registered) - 1; i >= 0; i-- {
+		item := registered[i]
+		if item.Version > current || item.Version <= target {
+			continue
+		}
+		if item.Down == nil {
+			return NewStatusError(EmptyParamsErr, fmt.Sprintf(
+				"store %s schema version %d does not support rollback", name, item.Version))
+		}
+		var previous uint32
+		if i > 0 {
+			previous = registered[i-1].Version
+		}
+		if err := applyMigration(s, item.Down, previous); err != nil {
+			return fmt.Errorf("migrate store %s down from version %d: %w", name, item.Version, err)
+		}
+	}
+	return nil
+}
+
+// applyMigration 在同一个事务中执行 f 并把 schema 版本更新为 version
+func applyMigration(s SchemaVersionStore, f MigrationFunc, version uint32) error {
+	tx, err := s.StartTx()
+	if err != nil {
+		return err
+	}
+	if err := f(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err := s.SetSchemaVersion(tx, version); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func currentSchemaVersion(s SchemaVersionStore) (uint32, error) {
+	tx, err := s.StartTx()
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	return s.SchemaVersion(tx)
+}
+
+func findMigration(registered []Migration, version uint32) int {
+	for i := range registered {
+		if registered[i].Version == version {
+			return i
+		}
+	}
+	return -1
+}
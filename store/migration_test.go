@@ -0,0 +1,155 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package store_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+)
+
+// versionStore 在内存中记录 schema 版本的 SchemaVersionStore，事务提交时才更新版本以及执行记录
+type versionStore struct {
+	version uint32
+	applied []string
+}
+
+type versionTx struct {
+	store.Tx
+	s       *versionStore
+	version uint32
+	applied []string
+}
+
+func (s *versionStore) StartTx() (store.Tx, error) {
+	return &versionTx{s: s, version: s.version}, nil
+}
+
+func (s *versionStore) SchemaVersion(tx store.Tx) (uint32, error) {
+	return tx.(*versionTx).version, nil
+}
+
+func (s *versionStore) SetSchemaVersion(tx store.Tx, version uint32) error {
+	tx.(*versionTx).version = version
+	return nil
+}
+
+func (tx *versionTx) Commit() error {
+	tx.s.version = tx.version
+	tx.s.applied = append(tx.s.applied, tx.applied...)
+	return nil
+}
+
+func (tx *versionTx) Rollback() error {
+	return nil
+}
+
+var errDDL = errors.New("ddl failed")
+
+// step 记录在 tx 中执行的迁移
+func step(name string) store.MigrationFunc {
+	return func(tx store.Tx) error {
+		vtx := tx.(*versionTx)
+		vtx.applied = append(vtx.applied, name)
+		return nil
+	}
+}
+
+func TestMigrateUpAndDown(t *testing.T) {
+	name := "migration_test_up_down"
+	if len(store.GetMigrations(name)) == 0 {
+		store.RegisterMigrations(name,
+			store.Migration{Version: 2, Up: step("up2"), Down: step("down2")},
+			store.Migration{Version: 1, Up: step("up1"), Down: step("down1")},
+			store.Migration{Version: 3, Up: step("up3"), Down: step("down3")},
+		)
+	}
+	if latest := store.LatestSchemaVersion(name); latest != 3 {
+		t.Fatalf("expect latest version 3, got %d", latest)
+	}
+
+	s := &versionStore{}
+	if err := store.CheckSchemaVersion(name, s); store.Code(err) != store.SchemaVersionMismatch {
+		t.Fatalf("expect SchemaVersionMismatch before migrating, got %v", err)
+	}
+	if err := store.MigrateUp(name, s); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.CheckSchemaVersion(name, s); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.MigrateTo(name, s, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.MigrateTo(name, s, 0); err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprint(s.applied); s.version != 0 || got != "[up1 up2 up3 down3 down2 down1]" {
+		t.Fatalf("unexpected migrations %s, version %d", got, s.version)
+	}
+	if err := store.MigrateTo(name, s, 4); store.Code(err) != store.EmptyParamsErr {
+		t.Fatalf("expect EmptyParamsErr for an unknown target, got %v", err)
+	}
+}
+
+func TestMigrateFailures(t *testing.T) {
+	name := "migration_test_failures"
+	cause := errDDL
+	if len(store.GetMigrations(name)) == 0 {
+		store.RegisterMigrations(name,
+			store.Migration{Version: 1, Up: step("up1")},
+			store.Migration{Version: 2, Up: func(tx store.Tx) error { return cause }},
+		)
+	}
+
+	// 失败时停留在最后一个成功的版本
+	s := &versionStore{}
+	if err := store.MigrateUp(name, s); !errors.Is(err, cause) {
+		t.Fatalf("expect %v, got %v", cause, err)
+	}
+	if s.version != 1 {
+		t.Fatalf("expect version 1 after the failed migration, got %d", s.version)
+	}
+	if err := store.MigrateTo(name, s, 0); store.Code(err) != store.EmptyParamsErr {
+		t.Fatalf("expect EmptyParamsErr for a migration without Down, got %v", err)
+	}
+
+	// 持久化的版本高于当前代码注册的版本
+	newer := &versionStore{version: 7}
+	if err := store.MigrateUp(name, newer); store.Code(err) != store.SchemaVersionMismatch {
+		t.Fatalf("expect SchemaVersionMismatch for an unknown schema version, got %v", err)
+	}
+	if err := store.CheckSchemaVersion(name, newer); store.Code(err) != store.SchemaVersionMismatch {
+		t.Fatalf("expect SchemaVersionMismatch, got %v", err)
+	}
+}
+
+func TestRegisterMigrationsPanics(t *testing.T) {
+	for _, item := range []store.Migration{{Version: 0, Up: step("up")}, {Version: 1}, {Version: 1, Up: step("dup")}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("expect panic for migration %d", item.Version)
+				}
+			}()
+			store.RegisterMigrations("migration_test_panics", store.Migration{Version: 1, Up: step("up1")}, item)
+		}()
+	}
+}
@@ -29,3 +29,21 @@ type LeaderElection struct {
 	ModifyTime time.Time
 	Valid      bool
 }
+
+// DistributedLock 基于存储实现的租约锁
+type DistributedLock struct {
+	Key string
+	// Owner 锁的持有者，锁已经释放时为空
+	Owner string
+	// Token fencing token，锁每次被重新获取时单调递增，持有者续约时保持不变
+	Token uint64
+	// ExpireTime 租约的到期时间
+	ExpireTime time.Time
+	CreateTime time.Time
+	ModifyTime time.Time
+}
+
+// Held 锁在 now 时是否被持有
+func (l *DistributedLock) Held(now time.Time) bool {
+	return l.Owner != "" && now.Before(l.ExpireTime)
+}
@@ -88,7 +88,9 @@ type ModifyStrategyDetail struct {
 	RemovePrincipals []Principal
 	AddResources     []StrategyResource
 	RemoveResources  []StrategyResource
-	ModifyTime       time.Time
+	// Revision 修改后的版本信息，为空时保持原有的版本信息
+	Revision   string
+	ModifyTime time.Time
 }
 
 // ModifyUserGroup 用户组修改
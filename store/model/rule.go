@@ -74,6 +74,8 @@ type CircuitBreakerRule struct {
 	CreateTime   time.Time
 	ModifyTime   time.Time
 	EnableTime   time.Time
+	CreateBy     string
+	ModifyBy     string // 更新时为空则保留原值，只携带ID的删除操作不记录操作人
 }
 
 // FaultDetectRule 故障探测规则
@@ -91,6 +93,8 @@ type FaultDetectRule struct {
 	Valid        bool
 	CreateTime   time.Time
 	ModifyTime   time.Time
+	CreateBy     string
+	ModifyBy     string // 更新时为空则保留原值，只携带ID的删除操作不记录操作人
 }
 
 type RoutingConfig struct {
@@ -133,6 +137,11 @@ type RouterConfig struct {
 	ModifyTime time.Time `json:"mtime"`
 	// enabletime The last time the rules enabled
 	EnableTime time.Time `json:"etime"`
+	// createby Operator who created the rules
+	CreateBy string `json:"createby"`
+	// modifyby Operator who last modified the rules, an empty value keeps the saved one.
+	// ID-only deletions are not audited
+	ModifyBy string `json:"modifyby"`
 }
 
 // RateLimit 限流规则
@@ -154,6 +163,8 @@ type RateLimit struct {
 	CreateTime time.Time
 	ModifyTime time.Time
 	EnableTime time.Time
+	CreateBy   string
+	ModifyBy   string // 更新时为空则保留原值
 }
 
 type ServiceContract struct {
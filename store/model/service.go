@@ -60,6 +60,8 @@ type Service struct {
 	Valid        bool
 	CreateTime   time.Time
 	ModifyTime   time.Time
+	CreateBy     string
+	ModifyBy     string // 更新时为空则保留原值，只携带ID的操作（DeleteService 等）不记录操作人
 	Mtime        int64
 	Ctime        int64
 	ServicePorts []*ServicePort
@@ -104,6 +106,11 @@ type Instance struct {
 	Valid bool
 	// ModifyTime Update time of instance
 	ModifyTime time.Time
+	// CreateBy Operator who registered the instance
+	CreateBy string
+	// ModifyBy Operator who last modified the instance, an empty value keeps the saved one.
+	// ID-only operations (DeleteInstance, health status and isolate setters) are not audited
+	ModifyBy string
 }
 
 // InstanceArgs 用于通过服务实例查询服务的参数
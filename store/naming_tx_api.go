@@ -0,0 +1,471 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package store
+
+import (
+	"fmt"
+
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// NamingTxStore 可选接口，支持在 Tx 中执行服务治理模块写操作的存储插件实现该接口，
+// 各方法的语义与去掉 Tx 后缀的同名方法一致，tx 为 nil 时等价于调用同名的非事务方法
+type NamingTxStore interface {
+	ServiceTxStore
+	InstanceTxStore
+	RoutingConfigTxStore
+	RateLimitTxStore
+	CircuitBreakerTxStore
+	RoutingConfigV2TxStore
+	FaultDetectRuleTxStore
+	ServiceContractTxStore
+}
+
+// ServiceTxStore 在事务中修改服务的接口
+type ServiceTxStore interface {
+	// AddServiceTx 在事务中保存一个服务
+	AddServiceTx(tx Tx, service *model.Service) error
+	// DeleteServiceTx 在事务中删除服务
+	DeleteServiceTx(tx Tx, id, serviceName, namespaceName string) error
+	// DeleteServiceAliasTx 在事务中删除服务别名
+	DeleteServiceAliasTx(tx Tx, name string, namespace string) error
+	// UpdateServiceAliasTx 在事务中修改服务别名
+	UpdateServiceAliasTx(tx Tx, alias *model.Service, needUpdateOwner bool) error
+	// UpdateServiceTx 在事务中更新服务
+	UpdateServiceTx(tx Tx, service *model.Service, needUpdateOwner bool) error
+	// UpdateServiceTokenTx 在事务中更新服务token
+	UpdateServiceTokenTx(tx Tx, serviceID string, token string, revision string) error
+}
+
+// InstanceTxStore 在事务中修改实例的接口
+type InstanceTxStore interface {
+	// AddInstanceTx 在事务中增加一个实例
+	AddInstanceTx(tx Tx, instance *model.Instance) error
+	// BatchAddInstancesTx 在事务中增加多个实例
+	BatchAddInstancesTx(tx Tx, instances []*model.Instance) error
+	// UpdateInstanceTx 在事务中更新实例
+	UpdateInstanceTx(tx Tx, instance *model.Instance) error
+	// DeleteInstanceTx 在事务中删除一个实例，实际是把valid置为false
+	DeleteInstanceTx(tx Tx, instanceID string) error
+	// BatchDeleteInstancesTx 在事务中批量删除实例，flag=1
+	BatchDeleteInstancesTx(tx Tx, ids []interface{}) error
+	// CleanInstanceTx 在事务中清空一个实例，真正删除
+	CleanInstanceTx(tx Tx, instanceID string) error
+	// SetInstanceHealthStatusTx 在事务中设置实例的健康状态
+	SetInstanceHealthStatusTx(tx Tx, instanceID string, flag int, revision string) error
+	// BatchSetInstanceHealthStatusTx 在事务中批量设置实例的健康状态
+	BatchSetInstanceHealthStatusTx(tx Tx, ids []interface{}, healthy int, revision string) error
+	// BatchSetInstanceIsolateTx 在事务中批量修改实例的隔离状态
+	BatchSetInstanceIsolateTx(tx Tx, ids []interface{}, isolate int, revision string) error
+	// BatchAppendInstanceMetadataTx 在事务中追加实例 metadata
+	BatchAppendInstanceMetadataTx(tx Tx, requests []*model.InstanceMetadataRequest) error
+	// BatchRemoveInstanceMetadataTx 在事务中删除实例指定的 metadata
+	BatchRemoveInstanceMetadataTx(tx Tx, requests []*model.InstanceMetadataRequest) error
+}
+
+// RoutingConfigTxStore 在事务中修改v1 版本路由配置的接口
+type RoutingConfigTxStore interface {
+	// CreateRoutingConfigTx 在事务中新增一个路由配置
+	CreateRoutingConfigTx(tx Tx, conf *model.RoutingConfig) error
+	// UpdateRoutingConfigTx 在事务中更新一个路由配置
+	UpdateRoutingConfigTx(tx Tx, conf *model.RoutingConfig) error
+}
+
+// RateLimitTxStore 在事务中修改限流规则的接口
+type RateLimitTxStore interface {
+	// CreateRateLimitTx 在事务中新增限流规则
+	CreateRateLimitTx(tx Tx, limiting *model.RateLimit) error
+	// UpdateRateLimitTx 在事务中更新限流规则
+	UpdateRateLimitTx(tx Tx, limiting *model.RateLimit) error
+	// EnableRateLimitTx 在事务中启用限流规则
+	EnableRateLimitTx(tx Tx, limit *model.RateLimit) error
+	// DeleteRateLimitTx 在事务中删除限流规则
+	DeleteRateLimitTx(tx Tx, limiting *model.RateLimit) error
+}
+
+// CircuitBreakerTxStore 在事务中修改熔断规则的接口
+type CircuitBreakerTxStore interface {
+	// CreateCircuitBreakerRuleTx create general circuitbreaker rule in transaction
+	CreateCircuitBreakerRuleTx(tx Tx, cbRule *model.CircuitBreakerRule) error
+	// UpdateCircuitBreakerRuleTx update general circuitbreaker rule in transaction
+	UpdateCircuitBreakerRuleTx(tx Tx, cbRule *model.CircuitBreakerRule) error
+	// DeleteCircuitBreakerRuleTx delete general circuitbreaker rule in transaction
+	DeleteCircuitBreakerRuleTx(tx Tx, id string) error
+	// EnableCircuitBreakerRuleTx enable specific circuitbreaker rule in transaction
+	EnableCircuitBreakerRuleTx(tx Tx, cbRule *model.CircuitBreakerRule) error
+}
+
+// RoutingConfigV2TxStore 在事务中修改v2 版本路由规则的接口
+type RoutingConfigV2TxStore interface {
+	// EnableRoutingTx 在事务中设置路由规则是否启用
+	EnableRoutingTx(tx Tx, conf *model.RouterConfig) error
+	// DeleteRoutingConfigV2Tx 在事务中删除一个路由配置
+	DeleteRoutingConfigV2Tx(tx Tx, serviceID string) error
+}
+
+// FaultDetectRuleTxStore 在事务中修改主动探测规则的接口
+type FaultDetectRuleTxStore interface {
+	// CreateFaultDetectRuleTx create fault detect rule in transaction
+	CreateFaultDetectRuleTx(tx Tx, conf *model.FaultDetectRule) error
+	// UpdateFaultDetectRuleTx update fault detect rule in transaction
+	UpdateFaultDetectRuleTx(tx Tx, conf *model.FaultDetectRule) error
+	// DeleteFaultDetectRuleTx delete fault detect rule in transaction
+	DeleteFaultDetectRuleTx(tx Tx, id string) error
+}
+
+// ServiceContractTxStore 在事务中修改服务契约的接口
+type ServiceContractTxStore interface {
+	// CreateServiceContractTx 在事务中创建服务契约
+	CreateServiceContractTx(tx Tx, contract *model.ServiceContract) error
+	// UpdateServiceContractTx 在事务中更新服务契约
+	UpdateServiceContractTx(tx Tx, contract *model.ServiceContract) error
+	// DeleteServiceContractTx 在事务中删除服务契约
+	DeleteServiceContractTx(tx Tx, contract *model.ServiceContract) error
+	// AddServiceContractInterfacesTx 在事务中创建服务契约API接口
+	AddServiceContractInterfacesTx(tx Tx, contract *model.ServiceContract) error
+	// AppendServiceContractInterfacesTx 在事务中追加服务契约API接口
+	AppendServiceContractInterfacesTx(tx Tx, contract *model.ServiceContract) error
+	// DeleteServiceContractInterfacesTx 在事务中批量删除服务契约API接口
+	DeleteServiceContractInterfacesTx(tx Tx, contract *model.ServiceContract) error
+}
+
+// NewNamingTxStore s 实现了 NamingTxStore 时直接返回，否则返回的适配器在 tx 为 nil 时调用同名的非事务方法，
+// tx 不为 nil 时返回 NotSupportedErr，不会在事务之外执行写操作
+func NewNamingTxStore(s Store) NamingTxStore {
+	if ns, ok := s.(NamingTxStore); ok {
+		return ns
+	}
+	return &nonTxNamingStore{store: s}
+}
+
+// nonTxNamingStore 基于非事务方法实现的 NamingTxStore
+type nonTxNamingStore struct {
+	store Store
+}
+
+// check tx 不为 nil 时返回 NotSupportedErr
+func (n *nonTxNamingStore) check(tx Tx, method string) error {
+	if tx == nil {
+		return nil
+	}
+	return NewStatusError(NotSupportedErr, fmt.Sprintf("store %s does not support %s", n.store.Name(), method))
+}
+
+// AddServiceTx 实现 NamingTxStore
+func (n *nonTxNamingStore) AddServiceTx(tx Tx, service *model.Service) error {
+	if err := n.check(tx, "AddServiceTx"); err != nil {
+		return err
+	}
+	return n.store.AddService(service)
+}
+
+// DeleteServiceTx 实现 NamingTxStore
+func (n *nonTxNamingStore) DeleteServiceTx(tx Tx, id string, serviceName string, namespaceName string) error {
+	if err := n.check(tx, "DeleteServiceTx"); err != nil {
+		return err
+	}
+	return n.store.DeleteService(id, serviceName, namespaceName)
+}
+
+// DeleteServiceAliasTx 实现 NamingTxStore
+func (n *nonTxNamingStore) DeleteServiceAliasTx(tx Tx, name string, namespace string) error {
+	if err := n.check(tx, "DeleteServiceAliasTx"); err != nil {
+		return err
+	}
+	return n.store.DeleteServiceAlias(name, namespace)
+}
+
+// UpdateServiceAliasTx 实现 NamingTxStore
+func (n *nonTxNamingStore) UpdateServiceAliasTx(tx Tx, alias *model.Service, needUpdateOwner bool) error {
+	if err := n.check(tx, "UpdateServiceAliasTx"); err != nil {
+		return err
+	}
+	return n.store.UpdateServiceAlias(alias, needUpdateOwner)
+}
+
+// UpdateServiceTx 实现 NamingTxStore
+func (n *nonTxNamingStore) UpdateServiceTx(tx Tx, service *model.Service, needUpdateOwner bool) error {
+	if err := n.check(tx, "UpdateServiceTx"); err != nil {
+		return err
+	}
+	return n.store.UpdateService(service, needUpdateOwner)
+}
+
+// UpdateServiceTokenTx 实现 NamingTxStore
+func (n *nonTxNamingStore) UpdateServiceTokenTx(tx Tx, serviceID string, token string, revision string) error {
+	if err := n.check(tx, "UpdateServiceTokenTx"); err != nil {
+		return err
+	}
+	return n.store.UpdateServiceToken(serviceID, token, revision)
+}
+
+// AddInstanceTx 实现 NamingTxStore
+func (n *nonTxNamingStore) AddInstanceTx(tx Tx, instance *model.Instance) error {
+	if err := n.check(tx, "AddInstanceTx"); err != nil {
+		return err
+	}
+	return n.store.AddInstance(instance)
+}
+
+// BatchAddInstancesTx 实现 NamingTxStore
+func (n *nonTxNamingStore) BatchAddInstancesTx(tx Tx, instances []*model.Instance) error {
+	if err := n.check(tx, "BatchAddInstancesTx"); err != nil {
+		return err
+	}
+	return n.store.BatchAddInstances(instances)
+}
+
+// UpdateInstanceTx 实现 NamingTxStore
+func (n *nonTxNamingStore) UpdateInstanceTx(tx Tx, instance *model.Instance) error {
+	if err := n.check(tx, "UpdateInstanceTx"); err != nil {
+		return err
+	}
+	return n.store.UpdateInstance(instance)
+}
+
+// DeleteInstanceTx 实现 NamingTxStore
+func (n *nonTxNamingStore) DeleteInstanceTx(tx Tx, instanceID string) error {
+	if err := n.check(tx, "DeleteInstanceTx"); err != nil {
+		return err
+	}
+	return n.store.DeleteInstance(instanceID)
+}
+
+// BatchDeleteInstancesTx 实现 NamingTxStore
+func (n *nonTxNamingStore) BatchDeleteInstancesTx(tx Tx, ids []interface{}) error {
+	if err := n.check(tx, "BatchDeleteInstancesTx"); err != nil {
+		return err
+	}
+	return n.store.BatchDeleteInstances(ids)
+}
+
+// CleanInstanceTx 实现 NamingTxStore
+func (n *nonTxNamingStore) CleanInstanceTx(tx Tx, instanceID string) error {
+	if err := n.check(tx, "CleanInstanceTx"); err != nil {
+		return err
+	}
+	return n.store.CleanInstance(instanceID)
+}
+
+// SetInstanceHealthStatusTx 实现 NamingTxStore
+func (n *nonTxNamingStore) SetInstanceHealthStatusTx(tx Tx, instanceID string, flag int, revision string) error {
+	if err := n.check(tx, "SetInstanceHealthStatusTx"); err != nil {
+		return err
+	}
+	return n.store.SetInstanceHealthStatus(instanceID, flag, revision)
+}
+
+// BatchSetInstanceHealthStatusTx 实现 NamingTxStore
+func (n *nonTxNamingStore) BatchSetInstanceHealthStatusTx(tx Tx, ids []interface{}, healthy int, revision string) error {
+	if err := n.check(tx, "BatchSetInstanceHealthStatusTx"); err != nil {
+		return err
+	}
+	return n.store.BatchSetInstanceHealthStatus(ids, healthy, revision)
+}
+
+// BatchSetInstanceIsolateTx 实现 NamingTxStore
+func (n *nonTxNamingStore) BatchSetInstanceIsolateTx(tx Tx, ids []interface{}, isolate int, revision string) error {
+	if err := n.check(tx, "BatchSetInstanceIsolateTx"); err != nil {
+		return err
+	}
+	return n.store.BatchSetInstanceIsolate(ids, isolate, revision)
+}
+
+// BatchAppendInstanceMetadataTx 实现 NamingTxStore
+func (n *nonTxNamingStore) BatchAppendInstanceMetadataTx(tx Tx, requests []*model.InstanceMetadataRequest) error {
+	if err := n.check(tx, "BatchAppendInstanceMetadataTx"); err != nil {
+		return err
+	}
+	return n.store.BatchAppendInstanceMetadata(requests)
+}
+
+// BatchRemoveInstanceMetadataTx 实现 NamingTxStore
+func (n *nonTxNamingStore) BatchRemoveInstanceMetadataTx(tx Tx, requests []*model.InstanceMetadataRequest) error {
+	if err := n.check(tx, "BatchRemoveInstanceMetadataTx"); err != nil {
+		return err
+	}
+	return n.store.BatchRemoveInstanceMetadata(requests)
+}
+
+// CreateRoutingConfigTx 实现 NamingTxStore
+func (n *nonTxNamingStore) CreateRoutingConfigTx(tx Tx, conf *model.RoutingConfig) error {
+	if err := n.check(tx, "CreateRoutingConfigTx"); err != nil {
+		return err
+	}
+	return n.store.CreateRoutingConfig(conf)
+}
+
+// UpdateRoutingConfigTx 实现 NamingTxStore
+func (n *nonTxNamingStore) UpdateRoutingConfigTx(tx Tx, conf *model.RoutingConfig) error {
+	if err := n.check(tx, "UpdateRoutingConfigTx"); err != nil {
+		return err
+	}
+	return n.store.UpdateRoutingConfig(conf)
+}
+
+// CreateRateLimitTx 实现 NamingTxStore
+func (n *nonTxNamingStore) CreateRateLimitTx(tx Tx, limiting *model.RateLimit) error {
+	if err := n.check(tx, "CreateRateLimitTx"); err != nil {
+		return err
+	}
+	return n.store.CreateRateLimit(limiting)
+}
+
+// UpdateRateLimitTx 实现 NamingTxStore
+func (n *nonTxNamingStore) UpdateRateLimitTx(tx Tx, limiting *model.RateLimit) error {
+	if err := n.check(tx, "UpdateRateLimitTx"); err != nil {
+		return err
+	}
+	return n.store.UpdateRateLimit(limiting)
+}
+
+// EnableRateLimitTx 实现 NamingTxStore
+func (n *nonTxNamingStore) EnableRateLimitTx(tx Tx, limit *model.RateLimit) error {
+	if err := n.check(tx, "EnableRateLimitTx"); err != nil {
+		return err
+	}
+	return n.store.EnableRateLimit(limit)
+}
+
+// DeleteRateLimitTx 实现 NamingTxStore
+func (n *nonTxNamingStore) DeleteRateLimitTx(tx Tx, limiting *model.RateLimit) error {
+	if err := n.check(tx, "DeleteRateLimitTx"); err != nil {
+		return err
+	}
+	return n.store.DeleteRateLimit(limiting)
+}
+
+// CreateCircuitBreakerRuleTx 实现 NamingTxStore
+func (n *nonTxNamingStore) CreateCircuitBreakerRuleTx(tx Tx, cbRule *model.CircuitBreakerRule) error {
+	if err := n.check(tx, "CreateCircuitBreakerRuleTx"); err != nil {
+		return err
+	}
+	return n.store.CreateCircuitBreakerRule(cbRule)
+}
+
+// UpdateCircuitBreakerRuleTx 实现 NamingTxStore
+func (n *nonTxNamingStore) UpdateCircuitBreakerRuleTx(tx Tx, cbRule *model.CircuitBreakerRule) error {
+	if err := n.check(tx, "UpdateCircuitBreakerRuleTx"); err != nil {
+		return err
+	}
+	return n.store.UpdateCircuitBreakerRule(cbRule)
+}
+
+// DeleteCircuitBreakerRuleTx 实现 NamingTxStore
+func (n *nonTxNamingStore) DeleteCircuitBreakerRuleTx(tx Tx, id string) error {
+	if err := n.check(tx, "DeleteCircuitBreakerRuleTx"); err != nil {
+		return err
+	}
+	return n.store.DeleteCircuitBreakerRule(id)
+}
+
+// EnableCircuitBreakerRuleTx 实现 NamingTxStore
+func (n *nonTxNamingStore) EnableCircuitBreakerRuleTx(tx Tx, cbRule *model.CircuitBreakerRule) error {
+	if err := n.check(tx, "EnableCircuitBreakerRuleTx"); err != nil {
+		return err
+	}
+	return n.store.EnableCircuitBreakerRule(cbRule)
+}
+
+// EnableRoutingTx 实现 NamingTxStore
+func (n *nonTxNamingStore) EnableRoutingTx(tx Tx, conf *model.RouterConfig) error {
+	if err := n.check(tx, "EnableRoutingTx"); err != nil {
+		return err
+	}
+	return n.store.EnableRouting(conf)
+}
+
+// DeleteRoutingConfigV2Tx 实现 NamingTxStore
+func (n *nonTxNamingStore) DeleteRoutingConfigV2Tx(tx Tx, serviceID string) error {
+	if err := n.check(tx, "DeleteRoutingConfigV2Tx"); err != nil {
+		return err
+	}
+	return n.store.DeleteRoutingConfigV2(serviceID)
+}
+
+// CreateFaultDetectRuleTx 实现 NamingTxStore
+func (n *nonTxNamingStore) CreateFaultDetectRuleTx(tx Tx, conf *model.FaultDetectRule) error {
+	if err := n.check(tx, "CreateFaultDetectRuleTx"); err != nil {
+		return err
+	}
+	return n.store.CreateFaultDetectRule(conf)
+}
+
+// UpdateFaultDetectRuleTx 实现 NamingTxStore
+func (n *nonTxNamingStore) UpdateFaultDetectRuleTx(tx Tx, conf *model.FaultDetectRule) error {
+	if err := n.check(tx, "UpdateFaultDetectRuleTx"); err != nil {
+		return err
+	}
+	return n.store.UpdateFaultDetectRule(conf)
+}
+
+// DeleteFaultDetectRuleTx 实现 NamingTxStore
+func (n *nonTxNamingStore) DeleteFaultDetectRuleTx(tx Tx, id string) error {
+	if err := n.check(tx, "DeleteFaultDetectRuleTx"); err != nil {
+		return err
+	}
+	return n.store.DeleteFaultDetectRule(id)
+}
+
+// CreateServiceContractTx 实现 NamingTxStore
+func (n *nonTxNamingStore) CreateServiceContractTx(tx Tx, contract *model.ServiceContract) error {
+	if err := n.check(tx, "CreateServiceContractTx"); err != nil {
+		return err
+	}
+	return n.store.CreateServiceContract(contract)
+}
+
+// UpdateServiceContractTx 实现 NamingTxStore
+func (n *nonTxNamingStore) UpdateServiceContractTx(tx Tx, contract *model.ServiceContract) error {
+	if err := n.check(tx, "UpdateServiceContractTx"); err != nil {
+		return err
+	}
+	return n.store.UpdateServiceContract(contract)
+}
+
+// DeleteServiceContractTx 实现 NamingTxStore
+func (n *nonTxNamingStore) DeleteServiceContractTx(tx Tx, contract *model.ServiceContract) error {
+	if err := n.check(tx, "DeleteServiceContractTx"); err != nil {
+		return err
+	}
+	return n.store.DeleteServiceContract(contract)
+}
+
+// AddServiceContractInterfacesTx 实现 NamingTxStore
+func (n *nonTxNamingStore) AddServiceContractInterfacesTx(tx Tx, contract *model.ServiceContract) error {
+	if err := n.check(tx, "AddServiceContractInterfacesTx"); err != nil {
+		return err
+	}
+	return n.store.AddServiceContractInterfaces(contract)
+}
+
+// AppendServiceContractInterfacesTx 实现 NamingTxStore
+func (n *nonTxNamingStore) AppendServiceContractInterfacesTx(tx Tx, contract *model.ServiceContract) error {
+	if err := n.check(tx, "AppendServiceContractInterfacesTx"); err != nil {
+		return err
+	}
+	return n.store.AppendServiceContractInterfaces(contract)
+}
+
+// DeleteServiceContractInterfacesTx 实现 NamingTxStore
+func (n *nonTxNamingStore) DeleteServiceContractInterfacesTx(tx Tx, contract *model.ServiceContract) error {
+	if err := n.check(tx, "DeleteServiceContractInterfacesTx"); err != nil {
+		return err
+	}
+	return n.store.DeleteServiceContractInterfaces(contract)
+}
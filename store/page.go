@@ -0,0 +1,89 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package store
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	keyTokenPrefix    = "k:"
+	offsetTokenPrefix = "o:"
+)
+
+// PageToken 不透明的翻页标记，由存储层生成，调用方只需要把上一页返回的 NextToken 原样传入；
+// 空的 PageToken 表示查询第一页
+type PageToken string
+
+// Page 基于翻页标记查询到的一页数据
+type Page[T any] struct {
+	Items []T
+	// NextToken 查询下一页使用的翻页标记，为空时表示没有更多数据
+	NextToken PageToken
+}
+
+// EncodePageToken 根据当前页最后一条数据的排序键生成翻页标记
+// 排序键需要唯一且不随数据更新而变化，一般使用主键，存储插件按照排序键升序返回数据，
+// 翻页过程中数据发生变更时不会出现重复或者遗漏已存在的数据
+func EncodePageToken(key string) PageToken {
+	return PageToken(base64.RawURLEncoding.EncodeToString([]byte(keyTokenPrefix + key)))
+}
+
+// DecodePageToken 解析 EncodePageToken 生成的翻页标记，返回上一页最后一条数据的排序键；
+// 空的翻页标记返回空字符串，不合法时返回 EmptyParamsErr
+func DecodePageToken(token PageToken) (string, error) {
+	if token == "" {
+		return "", nil
+	}
+	return decodeToken(token, keyTokenPrefix)
+}
+
+func encodeOffsetToken(offset uint32) PageToken {
+	return PageToken(base64.RawURLEncoding.EncodeToString(
+		[]byte(offsetTokenPrefix + strconv.FormatUint(uint64(offset), 10))))
+}
+
+func decodeOffsetToken(token PageToken) (uint32, error) {
+	if token == "" {
+		return 0, nil
+	}
+	raw, err := decodeToken(token, offsetTokenPrefix)
+	if err != nil {
+		return 0, err
+	}
+	offset, err := strconv.ParseUint(raw, 10, 32)
+	if err != nil {
+		return 0, invalidPageToken(token)
+	}
+	return uint32(offset), nil
+}
+
+func decodeToken(token PageToken, prefix string) (string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(string(token))
+	if err != nil || !strings.HasPrefix(string(data), prefix) {
+		return "", invalidPageToken(token)
+	}
+	return strings.TrimPrefix(string(data), prefix), nil
+}
+
+func invalidPageToken(token PageToken) error {
+	return NewStatusError(EmptyParamsErr, fmt.Sprintf("invalid page token: %s", token))
+}
@@ -0,0 +1,195 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package store
+
+import (
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// CursorStore 可选接口，支持基于翻页标记查询的存储插件实现该接口
+// 过滤条件与对应的 offset 版本方法一致，数据按照排序键升序返回，limit 为 0 时返回全部数据
+type CursorStore interface {
+	// GetNamespacesPage 查询命名空间，排序键为 Name
+	GetNamespacesPage(filter map[string][]string, token PageToken, limit uint32) (*Page[*model.Namespace], error)
+	// GetServicesPage 查询服务，排序键为 ID
+	GetServicesPage(serviceFilters, serviceMetas map[string]string, instanceFilters *model.InstanceArgs, token PageToken, limit uint32) (*Page[*model.Service], error)
+	// GetServiceAliasesPage 查询服务别名，排序键为 ID
+	GetServiceAliasesPage(filter map[string]string, token PageToken, limit uint32) (*Page[*model.ServiceAlias], error)
+	// GetExpandInstancesPage 查询实例详情，排序键为 实例 ID
+	GetExpandInstancesPage(filter, metaFilter map[string]string, token PageToken, limit uint32) (*Page[*model.Instance], error)
+	// GetRoutingConfigsPage 查询路由配置，排序键为 ID
+	GetRoutingConfigsPage(filter map[string]string, token PageToken, limit uint32) (*Page[*model.RoutingConfig], error)
+	// GetExtendRateLimitsPage 查询限流规则，排序键为 ID
+	GetExtendRateLimitsPage(query map[string]string, token PageToken, limit uint32) (*Page[*model.RateLimit], error)
+	// GetCircuitBreakerRulesPage 查询熔断规则，排序键为 ID
+	GetCircuitBreakerRulesPage(filter map[string]string, token PageToken, limit uint32) (*Page[*model.CircuitBreakerRule], error)
+	// GetFaultDetectRulesPage 查询主动探测规则，排序键为 ID
+	GetFaultDetectRulesPage(filter map[string]string, token PageToken, limit uint32) (*Page[*model.FaultDetectRule], error)
+	// QueryConfigFilesPage 查询配置文件，排序键为 namespace、group、name
+	QueryConfigFilesPage(filter map[string]string, token PageToken, limit uint32) (*Page[*model.ConfigFile], error)
+	// QueryConfigFileReleaseHistoriesPage 查询配置发布历史，排序键为 Id
+	QueryConfigFileReleaseHistoriesPage(filter map[string]string, token PageToken, limit uint32) (*Page[*model.ConfigFileReleaseHistory], error)
+	// GetUsersPage 查询用户，排序键为 ID
+	GetUsersPage(filters map[string]string, token PageToken, limit uint32) (*Page[*model.User], error)
+	// GetGroupsPage 查询用户组，排序键为 ID
+	GetGroupsPage(filters map[string]string, token PageToken, limit uint32) (*Page[*model.UserGroup], error)
+	// GetStrategiesPage 查询鉴权策略，排序键为 ID
+	GetStrategiesPage(filters map[string]string, token PageToken, limit uint32) (*Page[*model.StrategyDetail], error)
+}
+
+// NewCursorStore s 实现了 CursorStore 时直接返回，否则使用 offset 版本的方法模拟，
+// 此时翻页标记中保存的是 offset，数据在翻页过程中发生变更时仍然可能出现重复或者遗漏
+func NewCursorStore(s Store) CursorStore {
+	if cs, ok := s.(CursorStore); ok {
+		return cs
+	}
+	return &offsetCursorStore{store: s}
+}
+
+// offsetCursorStore 基于 offset 版本方法实现的 CursorStore
+type offsetCursorStore struct {
+	store Store
+}
+
+// GetNamespacesPage 实现 CursorStore
+func (o *offsetCursorStore) GetNamespacesPage(filter map[string][]string, token PageToken, limit uint32) (*Page[*model.Namespace], error) {
+	return offsetQuery(token, limit, func(offset, limit uint32) (uint32, []*model.Namespace, error) {
+		items, total, err := o.store.GetNamespaces(filter, int(offset), int(limit))
+		return total, items, err
+	})
+}
+
+// GetServicesPage 实现 CursorStore
+func (o *offsetCursorStore) GetServicesPage(serviceFilters, serviceMetas map[string]string, instanceFilters *model.InstanceArgs, token PageToken, limit uint32) (*Page[*model.Service], error) {
+	return offsetQuery(token, limit, func(offset, limit uint32) (uint32, []*model.Service, error) {
+		return o.store.GetServices(serviceFilters, serviceMetas, instanceFilters, offset, limit)
+	})
+}
+
+// GetServiceAliasesPage 实现 CursorStore
+func (o *offsetCursorStore) GetServiceAliasesPage(filter map[string]string, token PageToken, limit uint32) (*Page[*model.ServiceAlias], error) {
+	return offsetQuery(token, limit, func(offset, limit uint32) (uint32, []*model.ServiceAlias, error) {
+		return o.store.GetServiceAliases(filter, offset, limit)
+	})
+}
+
+// GetExpandInstancesPage 实现 CursorStore
+func (o *offsetCursorStore) GetExpandInstancesPage(filter, metaFilter map[string]string, token PageToken, limit uint32) (*Page[*model.Instance], error) {
+	return offsetQuery(token, limit, func(offset, limit uint32) (uint32, []*model.Instance, error) {
+		return o.store.GetExpandInstances(filter, metaFilter, offset, limit)
+	})
+}
+
+// GetRoutingConfigsPage 实现 CursorStore
+func (o *offsetCursorStore) GetRoutingConfigsPage(filter map[string]string, token PageToken, limit uint32) (*Page[*model.RoutingConfig], error) {
+	return offsetQuery(token, limit, func(offset, limit uint32) (uint32, []*model.RoutingConfig, error) {
+		return o.store.GetRoutingConfigs(filter, offset, limit)
+	})
+}
+
+// GetExtendRateLimitsPage 实现 CursorStore
+func (o *offsetCursorStore) GetExtendRateLimitsPage(query map[string]string, token PageToken, limit uint32) (*Page[*model.RateLimit], error) {
+	return offsetQuery(token, limit, func(offset, limit uint32) (uint32, []*model.RateLimit, error) {
+		return o.store.GetExtendRateLimits(query, offset, limit)
+	})
+}
+
+// GetCircuitBreakerRulesPage 实现 CursorStore
+func (o *offsetCursorStore) GetCircuitBreakerRulesPage(filter map[string]string, token PageToken, limit uint32) (*Page[*model.CircuitBreakerRule], error) {
+	return offsetQuery(token, limit, func(offset, limit uint32) (uint32, []*model.CircuitBreakerRule, error) {
+		return o.store.GetCircuitBreakerRules(filter, offset, limit)
+	})
+}
+
+// GetFaultDetectRulesPage 实现 CursorStore
+func (o *offsetCursorStore) GetFaultDetectRulesPage(filter map[string]string, token PageToken, limit uint32) (*Page[*model.FaultDetectRule], error) {
+	return offsetQuery(token, limit, func(offset, limit uint32) (uint32, []*model.FaultDetectRule, error) {
+		return o.store.GetFaultDetectRules(filter, offset, limit)
+	})
+}
+
+// QueryConfigFilesPage 实现 CursorStore
+func (o *offsetCursorStore) QueryConfigFilesPage(filter map[string]string, token PageToken, limit uint32) (*Page[*model.ConfigFile], error) {
+	return offsetQuery(token, limit, func(offset, limit uint32) (uint32, []*model.ConfigFile, error) {
+		return o.store.QueryConfigFiles(filter, offset, limit)
+	})
+}
+
+// QueryConfigFileReleaseHistoriesPage 实现 CursorStore
+func (o *offsetCursorStore) QueryConfigFileReleaseHistoriesPage(filter map[string]string, token PageToken, limit uint32) (*Page[*model.ConfigFileReleaseHistory], error) {
+	return offsetQuery(token, limit, func(offset, limit uint32) (uint32, []*model.ConfigFileReleaseHistory, error) {
+		return o.store.QueryConfigFileReleaseHistories(filter, offset, limit)
+	})
+}
+
+// GetUsersPage 实现 CursorStore
+func (o *offsetCursorStore) GetUsersPage(filters map[string]string, token PageToken, limit uint32) (*Page[*model.User], error) {
+	return offsetQuery(token, limit, func(offset, limit uint32) (uint32, []*model.User, error) {
+		return o.store.GetUsers(filters, offset, limit)
+	})
+}
+
+// GetGroupsPage 实现 CursorStore
+func (o *offsetCursorStore) GetGroupsPage(filters map[string]string, token PageToken, limit uint32) (*Page[*model.UserGroup], error) {
+	return offsetQuery(token, limit, func(offset, limit uint32) (uint32, []*model.UserGroup, error) {
+		return o.store.GetGroups(filters, offset, limit)
+	})
+}
+
+// GetStrategiesPage 实现 CursorStore
+func (o *offsetCursorStore) GetStrategiesPage(filters map[string]string, token PageToken, limit uint32) (*Page[*model.StrategyDetail], error) {
+	return offsetQuery(token, limit, func(offset, limit uint32) (uint32, []*model.StrategyDetail, error) {
+		return o.store.GetStrategies(filters, offset, limit)
+	})
+}
+
+// offsetQuery 根据翻页标记中的 offset 调用 query 查询一页数据；
+// offset 版本的方法在 limit 为 0 时不返回数据，因此 limit 为 0 时按照 DefaultRangeBatchSize 分批查询全部数据
+func offsetQuery[T any](token PageToken, limit uint32, query func(offset, limit uint32) (uint32, []T, error)) (*Page[T], error) {
+	offset, err := decodeOffsetToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 {
+		total, items, err := query(offset, limit)
+		return offsetPage(items, total, offset, err)
+	}
+	all := make([]T, 0)
+	for {
+		total, items, err := query(offset, DefaultRangeBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+		offset += uint32(len(items))
+		if len(items) == 0 || offset >= total {
+			return &Page[T]{Items: all}, nil
+		}
+	}
+}
+
+func offsetPage[T any](items []T, total, offset uint32, err error) (*Page[T], error) {
+	if err != nil {
+		return nil, err
+	}
+	page := &Page[T]{Items: items}
+	if next := offset + uint32(len(items)); len(items) > 0 && next < total {
+		page.NextToken = encodeOffsetToken(next)
+	}
+	return page, nil
+}
@@ -0,0 +1,99 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package store_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/memory"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// sqlLimitStore 与 MySQL 的 LIMIT 一致，limit 为 0 时不返回数据
+type sqlLimitStore struct {
+	store.Store
+	calls int
+}
+
+func (s *sqlLimitStore) GetNamespaces(filter map[string][]string, offset, limit int) ([]*model.Namespace, uint32, error) {
+	s.calls++
+	items, total, err := s.Store.GetNamespaces(filter, offset, limit)
+	if limit == 0 {
+		items = nil
+	}
+	return items, total, err
+}
+
+func TestPageToken(t *testing.T) {
+	token := store.EncodePageToken("ns-1")
+	if key, err := store.DecodePageToken(token); err != nil || key != "ns-1" {
+		t.Fatalf("expect ns-1, got %q: %v", key, err)
+	}
+	if key, err := store.DecodePageToken(""); err != nil || key != "" {
+		t.Fatalf("expect empty key for the first page, got %q: %v", key, err)
+	}
+	for _, bad := range []store.PageToken{"!!!", store.PageToken("bm90LWEtdG9rZW4")} {
+		if _, err := store.DecodePageToken(bad); store.Code(err) != store.EmptyParamsErr {
+			t.Fatalf("expect EmptyParamsErr for %q, got %v", bad, err)
+		}
+	}
+}
+
+func TestOffsetCursorStore(t *testing.T) {
+	m := memory.New()
+	for i := 0; i < 5; i++ {
+		addNamespace(t, m, fmt.Sprintf("ns-%d", i))
+	}
+	s := &sqlLimitStore{Store: m}
+	cs := store.NewCursorStore(s)
+
+	var (
+		names []string
+		token store.PageToken
+	)
+	for {
+		page, err := cs.GetNamespacesPage(nil, token, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, ns := range page.Items {
+			names = append(names, ns.Name)
+		}
+		if token = page.NextToken; token == "" {
+			break
+		}
+	}
+	if len(names) != 5 {
+		t.Fatalf("expect 5 namespaces, got %v", names)
+	}
+
+	// limit 为 0 时分批查询全部数据，而不是把 0 传给 offset 版本的方法
+	s.calls = 0
+	page, err := cs.GetNamespacesPage(nil, "", 0)
+	if err != nil || len(page.Items) != 5 || page.NextToken != "" {
+		t.Fatalf("expect all 5 namespaces, got %d: %v", len(page.Items), err)
+	}
+	if s.calls != 1 {
+		t.Fatalf("expect a single batch, got %d calls", s.calls)
+	}
+	if _, err := cs.GetNamespacesPage(nil, store.EncodePageToken("ns-1"), 2); store.Code(err) != store.EmptyParamsErr {
+		t.Fatalf("expect EmptyParamsErr for a key token, got %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package store
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// poller pollingWatcher 以及 leaderPoller 共用的轮询循环，按照 interval 周期执行 poll，
+// poll 连续失败 maxFailures 次后停止，并通过 Err 返回最后一次失败的错误
+type poller struct {
+	parent      context.Context
+	ctx         context.Context
+	cancel      context.CancelFunc
+	interval    time.Duration
+	maxFailures int
+	done        chan struct{}
+
+	lock sync.Mutex
+	err  error
+}
+
+func newPoller(ctx context.Context, interval time.Duration, maxFailures int) *poller {
+	pollCtx, cancel := context.WithCancel(ctx)
+	return &poller{
+		parent:      ctx,
+		ctx:         pollCtx,
+		cancel:      cancel,
+		interval:    interval,
+		maxFailures: maxFailures,
+		done:        make(chan struct{}),
+	}
+}
+
+// Err 实现 Watcher 以及 LeaderWatcher
+func (p *poller) Err() error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.err
+}
+
+// Stop 实现 Watcher 以及 LeaderWatcher
+func (p *poller) Stop() {
+	p.cancel()
+	<-p.done
+}
+
+// run 循环执行 poll 直到 ctx 结束或者连续失败次数达到上限，退出前调用 closeEvents 关闭事件通道
+func (p *poller) run(poll func() error, closeEvents func()) {
+	defer close(p.done)
+	defer closeEvents()
+	defer p.cancel()
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	failures := 0
+	for {
+		err := poll()
+		if p.ctx.Err() != nil {
+			p.finish(p.parent.Err())
+			return
+		}
+		if err == nil {
+			failures = 0
+		} else if failures++; failures >= p.maxFailures {
+			p.finish(err)
+			return
+		}
+		select {
+		case <-p.ctx.Done():
+			p.finish(p.parent.Err())
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *poller) finish(err error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.err = err
+}
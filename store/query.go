@@ -0,0 +1,278 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package store
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Operator 查询条件的匹配方式
+type Operator string
+
+const (
+	// OpEqual 精确匹配
+	OpEqual Operator = "eq"
+	// OpPrefix 前缀匹配
+	OpPrefix Operator = "prefix"
+	// OpFuzzy 模糊匹配，字段包含给定的关键字即可
+	OpFuzzy Operator = "fuzzy"
+	// OpIn 等于给定的任意一个值
+	OpIn Operator = "in"
+)
+
+// MetadataFieldPrefix metadata 过滤字段的前缀，例如 metadata.env 表示 metadata 中 key 为 env 的值
+const MetadataFieldPrefix = "metadata."
+
+// Condition 单个字段的查询条件
+type Condition struct {
+	Field    string
+	Operator Operator
+	Values   []string
+}
+
+// Order 排序条件
+type Order struct {
+	Field string
+	Desc  bool
+}
+
+// Query 类型化的查询条件，多个 Condition 之间为且的关系，Orders 按照先后顺序排序
+type Query struct {
+	Conditions []Condition
+	Orders     []Order
+	Offset     uint32
+	Limit      uint32
+}
+
+// NewQuery 创建查询条件
+func NewQuery() *Query {
+	return &Query{}
+}
+
+// Equal 增加精确匹配条件
+func (q *Query) Equal(field, value string) *Query {
+	return q.where(field, OpEqual, value)
+}
+
+// Prefix 增加前缀匹配条件
+func (q *Query) Prefix(field, prefix string) *Query {
+	return q.where(field, OpPrefix, prefix)
+}
+
+// Fuzzy 增加模糊匹配条件
+func (q *Query) Fuzzy(field, keyword string) *Query {
+	return q.where(field, OpFuzzy, keyword)
+}
+
+// In 增加多值匹配条件
+func (q *Query) In(field string, values ...string) *Query {
+	return q.where(field, OpIn, values...)
+}
+
+// Metadata 增加 metadata 精确匹配条件
+func (q *Query) Metadata(key, value string) *Query {
+	return q.where(MetadataFieldPrefix+key, OpEqual, value)
+}
+
+// OrderBy 增加排序条件
+func (q *Query) OrderBy(field string, desc bool) *Query {
+	q.Orders = append(q.Orders, Order{Field: field, Desc: desc})
+	return q
+}
+
+// Page 设置分页参数
+func (q *Query) Page(offset, limit uint32) *Query {
+	q.Offset = offset
+	q.Limit = limit
+	return q
+}
+
+func (q *Query) where(field string, op Operator, values ...string) *Query {
+	q.Conditions = append(q.Conditions, Condition{Field: field, Operator: op, Values: values})
+	return q
+}
+
+// FieldSpec 查询字段支持的匹配方式以及是否可以排序
+type FieldSpec struct {
+	Operators []Operator
+	Sortable  bool
+}
+
+// QuerySchema 某一类资源支持的查询字段
+type QuerySchema struct {
+	// Resource 资源名称，用于错误信息
+	Resource string
+	Fields   map[string]FieldSpec
+	// Metadata 是否支持 metadata 精确匹配
+	Metadata bool
+	// MaxLimit 单页的最大数量，为 0 时不限制
+	MaxLimit uint32
+}
+
+// Validate 根据 schema 校验查询条件，不合法时返回 EmptyParamsErr
+func (q *Query) Validate(schema *QuerySchema) error {
+	if q == nil {
+		return nil
+	}
+	for _, cond := range q.Conditions {
+		if err := schema.validateCondition(cond); err != nil {
+			return err
+		}
+	}
+	for _, order := range q.Orders {
+		if spec, ok := schema.Fields[order.Field]; !ok || !spec.Sortable {
+			return schema.invalid("field %q can not be sorted", order.Field)
+		}
+	}
+	if schema.MaxLimit > 0 && q.Limit > schema.MaxLimit {
+		return schema.invalid("limit %d exceeds %d", q.Limit, schema.MaxLimit)
+	}
+	return nil
+}
+
+func (s *QuerySchema) validateCondition(cond Condition) error {
+	switch cond.Operator {
+	case OpIn:
+		if len(cond.Values) == 0 {
+			return s.invalid("field %q requires at least one value", cond.Field)
+		}
+	case OpEqual, OpPrefix, OpFuzzy:
+		if len(cond.Values) != 1 {
+			return s.invalid("field %q requires exactly one value", cond.Field)
+		}
+	default:
+		return s.invalid("unknown operator %q", cond.Operator)
+	}
+
+	if strings.HasPrefix(cond.Field, MetadataFieldPrefix) {
+		if !s.Metadata || cond.Field == MetadataFieldPrefix {
+			return s.invalid("metadata field %q is not supported", cond.Field)
+		}
+		if cond.Operator != OpEqual {
+			return s.invalid("metadata field %q only supports %s", cond.Field, OpEqual)
+		}
+		return nil
+	}
+	spec, ok := s.Fields[cond.Field]
+	if !ok {
+		return s.invalid("unknown field %q", cond.Field)
+	}
+	for _, op := range spec.Operators {
+		if op == cond.Operator {
+			return nil
+		}
+	}
+	return s.invalid("field %q does not support %s", cond.Field, cond.Operator)
+}
+
+func (s *QuerySchema) invalid(format string, args ...interface{}) error {
+	return NewStatusError(EmptyParamsErr, fmt.Sprintf("invalid %s query: ", s.Resource)+fmt.Sprintf(format, args...))
+}
+
+// Match 判断数据是否满足所有的查询条件，get 返回字段的值，字段不存在时返回 false
+func (q *Query) Match(get func(field string) (string, bool)) bool {
+	if q == nil {
+		return true
+	}
+	for _, cond := range q.Conditions {
+		value, ok := get(cond.Field)
+		if !ok || !cond.Match(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// Match 判断字段值是否满足该条件
+func (c Condition) Match(value string) bool {
+	switch c.Operator {
+	case OpEqual:
+		return len(c.Values) == 1 && value == c.Values[0]
+	case OpPrefix:
+		return len(c.Values) == 1 && strings.HasPrefix(value, c.Values[0])
+	case OpFuzzy:
+		return len(c.Values) == 1 && strings.Contains(value, c.Values[0])
+	case OpIn:
+		for _, v := range c.Values {
+			if v == value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Compare 按照排序条件比较两条数据，a 排在 b 之前时返回负数，get 的含义与 Match 一致
+func (q *Query) Compare(a, b func(field string) (string, bool)) int {
+	if q == nil {
+		return 0
+	}
+	for _, order := range q.Orders {
+		va, _ := a(order.Field)
+		vb, _ := b(order.Field)
+		ret := strings.Compare(va, vb)
+		if order.Desc {
+			ret = -ret
+		}
+		if ret != 0 {
+			return ret
+		}
+	}
+	return 0
+}
+
+// LegacyFilters 将查询条件转换为 map[string]string 形式的过滤条件，供尚未支持 Query 的存储插件使用
+// 精确匹配以及单个值的 IN 原样传递，前缀匹配转换为以 * 结尾的值，metadata 条件放在 metaFilter 中；
+// 原有的查询方法默认按照修改时间倒序返回，除此之外的排序、模糊匹配以及多值的 IN 无法表达，返回 EmptyParamsErr
+func (q *Query) LegacyFilters() (filter, metaFilter map[string]string, err error) {
+	filter = make(map[string]string)
+	metaFilter = make(map[string]string)
+	if q == nil {
+		return filter, metaFilter, nil
+	}
+	for _, order := range q.Orders {
+		if order.Field != FieldModifyTime || !order.Desc {
+			return nil, nil, NewStatusError(EmptyParamsErr, "legacy filters only support ordering by mtime desc")
+		}
+	}
+	for _, cond := range q.Conditions {
+		var value string
+		switch {
+		case cond.Operator == OpEqual && len(cond.Values) == 1,
+			cond.Operator == OpIn && len(cond.Values) == 1:
+			value = cond.Values[0]
+		case cond.Operator == OpPrefix && len(cond.Values) == 1:
+			value = cond.Values[0] + "*"
+		default:
+			return nil, nil, NewStatusError(EmptyParamsErr,
+				fmt.Sprintf("legacy filters do not support %s on field %q", cond.Operator, cond.Field))
+		}
+		target := filter
+		field := cond.Field
+		if strings.HasPrefix(field, MetadataFieldPrefix) {
+			target, field = metaFilter, strings.TrimPrefix(field, MetadataFieldPrefix)
+		}
+		if _, exist := target[field]; exist {
+			return nil, nil, NewStatusError(EmptyParamsErr,
+				fmt.Sprintf("legacy filters do not support multiple conditions on field %q", cond.Field))
+		}
+		target[field] = value
+	}
+	return filter, metaFilter, nil
+}
@@ -0,0 +1,243 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package store
+
+import (
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// FieldModifyTime 所有可查询资源都支持按照修改时间排序
+const FieldModifyTime = "mtime"
+
+var (
+	exactOperators = []Operator{OpEqual, OpIn}
+	textOperators  = []Operator{OpEqual, OpPrefix, OpFuzzy, OpIn}
+	mtimeField     = FieldSpec{Sortable: true}
+)
+
+var (
+	// ServiceQuerySchema SearchServices 支持的查询字段
+	ServiceQuerySchema = &QuerySchema{
+		Resource: "service",
+		Fields: map[string]FieldSpec{
+			"id":            {Operators: exactOperators},
+			"name":          {Operators: textOperators, Sortable: true},
+			"namespace":     {Operators: exactOperators, Sortable: true},
+			"business":      {Operators: textOperators},
+			"department":    {Operators: textOperators},
+			"owner":         {Operators: textOperators},
+			"platform_id":   {Operators: exactOperators},
+			"reference":     {Operators: exactOperators},
+			FieldModifyTime: mtimeField,
+		},
+		Metadata: true,
+	}
+	// InstanceQuerySchema SearchInstances 支持的查询字段，healthy、isolate 的取值为 1 或者 0
+	InstanceQuerySchema = &QuerySchema{
+		Resource: "instance",
+		Fields: map[string]FieldSpec{
+			"id":            {Operators: exactOperators},
+			"service_id":    {Operators: exactOperators},
+			"service":       {Operators: textOperators, Sortable: true},
+			"namespace":     {Operators: exactOperators, Sortable: true},
+			"host":          {Operators: []Operator{OpEqual, OpPrefix, OpIn}, Sortable: true},
+			"port":          {Operators: exactOperators},
+			"protocol":      {Operators: exactOperators},
+			"version":       {Operators: []Operator{OpEqual, OpPrefix, OpIn}},
+			"logic_set":     {Operators: exactOperators},
+			"healthy":       {Operators: []Operator{OpEqual}},
+			"isolate":       {Operators: []Operator{OpEqual}},
+			FieldModifyTime: mtimeField,
+		},
+		Metadata: true,
+	}
+	// RoutingConfigQuerySchema SearchRoutingConfigs 支持的查询字段
+	RoutingConfigQuerySchema = &QuerySchema{
+		Resource: "routing config",
+		Fields: map[string]FieldSpec{
+			"id":            {Operators: exactOperators},
+			"service":       {Operators: textOperators, Sortable: true},
+			"namespace":     {Operators: exactOperators, Sortable: true},
+			FieldModifyTime: mtimeField,
+		},
+	}
+	// FaultDetectRuleQuerySchema SearchFaultDetectRules 支持的查询字段
+	FaultDetectRuleQuerySchema = &QuerySchema{
+		Resource: "fault detect rule",
+		Fields: map[string]FieldSpec{
+			"id":            {Operators: exactOperators},
+			"name":          {Operators: textOperators, Sortable: true},
+			"namespace":     {Operators: exactOperators, Sortable: true},
+			"dst_service":   {Operators: textOperators},
+			"dst_namespace": {Operators: exactOperators},
+			"dst_method":    {Operators: textOperators},
+			"description":   {Operators: []Operator{OpFuzzy}},
+			FieldModifyTime: mtimeField,
+		},
+	}
+	// ConfigFileQuerySchema SearchConfigFiles 支持的查询字段
+	ConfigFileQuerySchema = &QuerySchema{
+		Resource: "config file",
+		Fields: map[string]FieldSpec{
+			"namespace":     {Operators: exactOperators, Sortable: true},
+			"group":         {Operators: textOperators, Sortable: true},
+			"name":          {Operators: textOperators, Sortable: true},
+			FieldModifyTime: mtimeField,
+		},
+	}
+	// UserQuerySchema SearchUsers 支持的查询字段
+	UserQuerySchema = &QuerySchema{
+		Resource: "user",
+		Fields: map[string]FieldSpec{
+			"id":            {Operators: exactOperators},
+			"name":          {Operators: textOperators, Sortable: true},
+			"owner":         {Operators: exactOperators},
+			"source":        {Operators: exactOperators},
+			"type":          {Operators: exactOperators},
+			FieldModifyTime: mtimeField,
+		},
+	}
+	// StrategyQuerySchema SearchStrategies 支持的查询字段，default 的取值为 true 或者 false
+	StrategyQuerySchema = &QuerySchema{
+		Resource: "strategy",
+		Fields: map[string]FieldSpec{
+			"id":            {Operators: exactOperators},
+			"name":          {Operators: textOperators, Sortable: true},
+			"owner":         {Operators: exactOperators},
+			"default":       {Operators: []Operator{OpEqual}},
+			FieldModifyTime: mtimeField,
+		},
+	}
+)
+
+// QueryStore 可选接口，支持类型化查询条件的存储插件实现该接口
+// 实现方需要先使用对应的 QuerySchema 校验查询条件，未指定排序时按照修改时间倒序返回
+type QueryStore interface {
+	// SearchServices 查询服务及数目
+	SearchServices(q *Query) (uint32, []*model.Service, error)
+	// SearchInstances 查询实例详情及数目
+	SearchInstances(q *Query) (uint32, []*model.Instance, error)
+	// SearchRoutingConfigs 查询路由配置及数目
+	SearchRoutingConfigs(q *Query) (uint32, []*model.RoutingConfig, error)
+	// SearchFaultDetectRules 查询主动探测规则及数目
+	SearchFaultDetectRules(q *Query) (uint32, []*model.FaultDetectRule, error)
+	// SearchConfigFiles 查询配置文件及数目
+	SearchConfigFiles(q *Query) (uint32, []*model.ConfigFile, error)
+	// SearchUsers 查询用户及数目
+	SearchUsers(q *Query) (uint32, []*model.User, error)
+	// SearchStrategies 查询鉴权策略及数目
+	SearchStrategies(q *Query) (uint32, []*model.StrategyDetail, error)
+}
+
+// NewQueryStore s 实现了 QueryStore 时直接返回，否则将查询条件转换为 map[string]string 形式的过滤条件，
+// 调用原有的查询方法，此时只支持精确匹配、前缀匹配以及单个值的 IN
+func NewQueryStore(s Store) QueryStore {
+	if qs, ok := s.(QueryStore); ok {
+		return qs
+	}
+	return &legacyQueryStore{store: s}
+}
+
+// legacyQueryStore 基于 map[string]string 过滤条件实现的 QueryStore
+type legacyQueryStore struct {
+	store Store
+}
+
+// SearchServices 实现 QueryStore
+func (l *legacyQueryStore) SearchServices(q *Query) (uint32, []*model.Service, error) {
+	filter, metaFilter, err := legacyFilters(q, ServiceQuerySchema)
+	if err != nil {
+		return 0, nil, err
+	}
+	return l.store.GetServices(filter, metaFilter, nil, offsetOf(q), limitOf(q))
+}
+
+// SearchInstances 实现 QueryStore
+func (l *legacyQueryStore) SearchInstances(q *Query) (uint32, []*model.Instance, error) {
+	filter, metaFilter, err := legacyFilters(q, InstanceQuerySchema)
+	if err != nil {
+		return 0, nil, err
+	}
+	return l.store.GetExpandInstances(filter, metaFilter, offsetOf(q), limitOf(q))
+}
+
+// SearchRoutingConfigs 实现 QueryStore
+func (l *legacyQueryStore) SearchRoutingConfigs(q *Query) (uint32, []*model.RoutingConfig, error) {
+	filter, _, err := legacyFilters(q, RoutingConfigQuerySchema)
+	if err != nil {
+		return 0, nil, err
+	}
+	return l.store.GetRoutingConfigs(filter, offsetOf(q), limitOf(q))
+}
+
+// SearchFaultDetectRules 实现 QueryStore
+func (l *legacyQueryStore) SearchFaultDetectRules(q *Query) (uint32, []*model.FaultDetectRule, error) {
+	filter, _, err := legacyFilters(q, FaultDetectRuleQuerySchema)
+	if err != nil {
+		return 0, nil, err
+	}
+	return l.store.GetFaultDetectRules(filter, offsetOf(q), limitOf(q))
+}
+
+// SearchConfigFiles 实现 QueryStore
+func (l *legacyQueryStore) SearchConfigFiles(q *Query) (uint32, []*model.ConfigFile, error) {
+	filter, _, err := legacyFilters(q, ConfigFileQuerySchema)
+	if err != nil {
+		return 0, nil, err
+	}
+	return l.store.QueryConfigFiles(filter, offsetOf(q), limitOf(q))
+}
+
+// SearchUsers 实现 QueryStore
+func (l *legacyQueryStore) SearchUsers(q *Query) (uint32, []*model.User, error) {
+	filter, _, err := legacyFilters(q, UserQuerySchema)
+	if err != nil {
+		return 0, nil, err
+	}
+	return l.store.GetUsers(filter, offsetOf(q), limitOf(q))
+}
+
+// SearchStrategies 实现 QueryStore
+func (l *legacyQueryStore) SearchStrategies(q *Query) (uint32, []*model.StrategyDetail, error) {
+	filter, _, err := legacyFilters(q, StrategyQuerySchema)
+	if err != nil {
+		return 0, nil, err
+	}
+	return l.store.GetStrategies(filter, offsetOf(q), limitOf(q))
+}
+
+func legacyFilters(q *Query, schema *QuerySchema) (map[string]string, map[string]string, error) {
+	if err := q.Validate(schema); err != nil {
+		return nil, nil, err
+	}
+	return q.LegacyFilters()
+}
+
+func offsetOf(q *Query) uint32 {
+	if q == nil {
+		return 0
+	}
+	return q.Offset
+}
+
+func limitOf(q *Query) uint32 {
+	if q == nil {
+		return 0
+	}
+	return q.Limit
+}
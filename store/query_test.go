@@ -0,0 +1,108 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package store_test
+
+import (
+	"testing"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+)
+
+func TestQueryValidate(t *testing.T) {
+	schema := &store.QuerySchema{
+		Resource: "service",
+		Fields: map[string]store.FieldSpec{
+			"name":      {Operators: []store.Operator{store.OpEqual, store.OpPrefix, store.OpIn}, Sortable: true},
+			"namespace": {Operators: []store.Operator{store.OpEqual}},
+		},
+		Metadata: true,
+		MaxLimit: 100,
+	}
+	cases := []struct {
+		name  string
+		query *store.Query
+		valid bool
+	}{
+		{name: "nil", query: nil, valid: true},
+		{name: "supported", query: store.NewQuery().Prefix("name", "svc").Equal("namespace", "default").
+			Metadata("env", "prod").OrderBy("name", true).Page(0, 100), valid: true},
+		{name: "unknown field", query: store.NewQuery().Equal("owner", "polaris")},
+		{name: "unsupported operator", query: store.NewQuery().Fuzzy("name", "svc")},
+		{name: "unknown operator", query: &store.Query{Conditions: []store.Condition{
+			{Field: "name", Operator: "regex", Values: []string{"svc"}}}}},
+		{name: "empty in", query: store.NewQuery().In("name")},
+		{name: "multiple values", query: &store.Query{Conditions: []store.Condition{
+			{Field: "name", Operator: store.OpEqual, Values: []string{"a", "b"}}}}},
+		{name: "metadata prefix", query: store.NewQuery().Prefix(store.MetadataFieldPrefix+"env", "p")},
+		{name: "empty metadata key", query: store.NewQuery().Metadata("", "prod")},
+		{name: "unsortable field", query: store.NewQuery().OrderBy("namespace", false)},
+		{name: "limit too large", query: store.NewQuery().Page(0, 101)},
+	}
+	for _, c := range cases {
+		err := c.query.Validate(schema)
+		if c.valid && err != nil {
+			t.Fatalf("%s: unexpected error %v", c.name, err)
+		}
+		if !c.valid && store.Code(err) != store.EmptyParamsErr {
+			t.Fatalf("%s: expect EmptyParamsErr, got %v", c.name, err)
+		}
+	}
+}
+
+func TestQueryMatchAndCompare(t *testing.T) {
+	fields := func(values map[string]string) func(string) (string, bool) {
+		return func(field string) (string, bool) {
+			v, ok := values[field]
+			return v, ok
+		}
+	}
+	a := fields(map[string]string{"name": "svc-a", "namespace": "default"})
+	b := fields(map[string]string{"name": "svc-b", "namespace": "default"})
+
+	q := store.NewQuery().Prefix("name", "svc").In("namespace", "default", "test").Fuzzy("name", "-a")
+	if !q.Match(a) || q.Match(b) {
+		t.Fatal("unexpected match result")
+	}
+	if store.NewQuery().Equal("owner", "polaris").Match(a) {
+		t.Fatal("missing field should not match")
+	}
+	if q = store.NewQuery().OrderBy("namespace", false).OrderBy("name", true); q.Compare(a, b) <= 0 {
+		t.Fatal("expect svc-b before svc-a when ordering by name desc")
+	}
+}
+
+func TestQueryLegacyFilters(t *testing.T) {
+	filter, metaFilter, err := store.NewQuery().Equal("namespace", "default").Prefix("name", "svc").
+		In("owner", "polaris").Metadata("env", "prod").OrderBy(store.FieldModifyTime, true).LegacyFilters()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filter["namespace"] != "default" || filter["name"] != "svc*" || filter["owner"] != "polaris" || metaFilter["env"] != "prod" {
+		t.Fatalf("unexpected filters %v %v", filter, metaFilter)
+	}
+	for _, q := range []*store.Query{
+		store.NewQuery().Fuzzy("name", "svc"),
+		store.NewQuery().In("name", "a", "b"),
+		store.NewQuery().OrderBy("name", false),
+		store.NewQuery().Equal("name", "a").Prefix("name", "b"),
+	} {
+		if _, _, err := q.LegacyFilters(); store.Code(err) != store.EmptyParamsErr {
+			t.Fatalf("expect EmptyParamsErr for %+v, got %v", q, err)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package retention 按照保留时间定期物理删除软删除（Valid 为 false）的数据
+//
+// Cleaner 只在当前节点为选举 key 的 leader 时执行清理，每一类资源按照 store.RetentionKinds 的顺序
+// 分批调用 store.RetentionStore 的 PurgeDeleted，直到没有超过保留时间的数据为止。保留时间需要大于
+// 各节点缓存的刷新间隔，保证缓存在数据被物理删除之前已经通过 GetMore* 感知到软删除。
+package retention
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+)
+
+const (
+	// DefaultElectionKey 默认的选举 key
+	DefaultElectionKey = "polaris.store.retention"
+	// defaultInterval 默认的清理间隔
+	defaultInterval = 10 * time.Minute
+	// defaultBatchSize 默认每批删除的数量
+	defaultBatchSize = 100
+)
+
+// Elector Cleaner 使用的选举接口，store.AdminStore 以及 election.Elector 都满足该接口
+type Elector interface {
+	// StartLeaderElection 参与 key 的选举
+	StartLeaderElection(key string) error
+	// IsLeader 当前节点是否为 key 的 leader
+	IsLeader(key string) bool
+	// ReleaseLeaderElection 放弃 key 的 leader 身份并退出选举
+	ReleaseLeaderElection(key string) error
+}
+
+// Report 一次清理中每一类资源删除的数量
+type Report map[store.RetentionKind]uint32
+
+// Option Cleaner 的可选配置
+type Option func(o *options)
+
+type options struct {
+	interval    time.Duration
+	batchSize   uint32
+	electionKey string
+	onRun       func(report Report, err error)
+}
+
+// WithInterval 设置后台清理的间隔，默认 10 分钟
+func WithInterval(interval time.Duration) Option {
+	return func(o *options) {
+		if interval > 0 {
+			o.interval = interval
+		}
+	}
+}
+
+// WithBatchSize 设置每批删除的数量，默认 100
+func WithBatchSize(size uint32) Option {
+	return func(o *options) {
+		if size > 0 {
+			o.batchSize = size
+		}
+	}
+}
+
+// WithElectionKey 设置选举 key，默认为 DefaultElectionKey
+func WithElectionKey(key string) Option {
+	return func(o *options) {
+		if key != "" {
+			o.electionKey = key
+		}
+	}
+}
+
+// WithOnRun 设置后台每次清理完成后的回调，当前节点不是 leader 时不会回调
+func WithOnRun(onRun func(report Report, err error)) Option {
+	return func(o *options) {
+		o.onRun = onRun
+	}
+}
+
+// Cleaner 定期清理超过保留时间的软删除数据
+type Cleaner struct {
+	store    store.RetentionStore
+	elector  Elector
+	policies map[store.RetentionKind]time.Duration
+	options
+
+	lock    sync.Mutex
+	running bool
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// New 创建 Cleaner，policies 为每一类资源软删除之后的保留时间，没有配置的资源类型不会被清理
+func New(s store.RetentionStore, elector Elector, policies map[store.RetentionKind]time.Duration,
+	opts ...Option) (*Cleaner, error) {
+	if s == nil || elector == nil {
+		return nil, store.NewStatusError(store.EmptyParamsErr, "retention store and elector are required")
+	}
+	supported := make(map[store.RetentionKind]struct{})
+	for _, kind := range store.RetentionKinds() {
+		supported[kind] = struct{}{}
+	}
+	copied := make(map[store.RetentionKind]time.Duration, len(policies))
+	for kind, retention := range policies {
+		if _, ok := supported[kind]; !ok {
+			return nil, store.NewStatusError(store.EmptyParamsErr, fmt.Sprintf("unsupported retention kind %s", kind))
+		}
+		if retention <= 0 {
+			return nil, store.NewStatusError(store.EmptyParamsErr,
+				fmt.Sprintf("retention of %s must be positive, got %s", kind, retention))
+		}
+		copied[kind] = retention
+	}
+
+	c := &Cleaner{
+		store:    s,
+		elector:  elector,
+		policies: copied,
+		options: options{
+			interval:    defaultInterval,
+			batchSize:   defaultBatchSize,
+			electionKey: DefaultElectionKey,
+		},
+	}
+	for i := range opts {
+		opts[i](&c.options)
+	}
+	return c, nil
+}
+
+// Start 参与选举并在后台按照间隔执行清理，直到 ctx 结束或者调用 Stop
+func (c *Cleaner) Start(ctx context.Context) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.running {
+		return store.NewStatusError(store.EmptyParamsErr, "retention cleaner already started")
+	}
+	if err := c.elector.StartLeaderElection(c.electionKey); err != nil {
+		return err
+	}
+	runCtx, cancel := context.WithCancel(ctx)
+	c.running = true
+	c.cancel = cancel
+	c.done = make(chan struct{})
+	go c.run(runCtx, c.done)
+	return nil
+}
+
+// Stop 停止后台清理并退出选举
+func (c *Cleaner) Stop() error {
+	c.lock.Lock()
+	if !c.running {
+		c.lock.Unlock()
+		return nil
+	}
+	c.running = false
+	c.cancel()
+	done := c.done
+	c.lock.Unlock()
+
+	<-done
+	return c.elector.ReleaseLeaderElection(c.electionKey)
+}
+
+func (c *Cleaner) run(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !c.elector.IsLeader(c.electionKey) {
+				continue
+			}
+			report, err := c.RunOnce(ctx)
+			if c.onRun != nil {
+				c.onRun(report, err)
+			}
+		}
+	}
+}
+
+// RunOnce 立即执行一次清理，当前节点不是 leader 时直接返回空的 Report；
+// 某一类资源清理失败时继续清理其他资源，返回的错误包含所有失败的资源类型
+func (c *Cleaner) RunOnce(ctx context.Context) (Report, error) {
+	report := make(Report)
+	if !c.elector.IsLeader(c.electionKey) {
+		return report, nil
+	}
+	var errs []error
+	for _, kind := range store.RetentionKinds() {
+		retention, ok := c.policies[kind]
+		if !ok {
+			continue
+		}
+		count, err := c.purge(ctx, kind, retention)
+		if count > 0 {
+			report[kind] = count
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("purge %s: %w", kind, err))
+		}
+	}
+	return report, errors.Join(errs...)
+}
+
+// purge 分批删除 kind 中超过保留时间的数据，失去 leader 身份或者 ctx 结束时提前返回
+func (c *Cleaner) purge(ctx context.Context, kind store.RetentionKind, retention time.Duration) (uint32, error) {
+	var total uint32
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+		if !c.elector.IsLeader(c.electionKey) {
+			return total, nil
+		}
+		count, err := c.store.PurgeDeleted(kind, retention, c.batchSize)
+		total += count
+		if err != nil || count < c.batchSize {
+			return total, err
+		}
+	}
+}
@@ -0,0 +1,135 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package retention_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/election/fake"
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/memory"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+	"github.com/polarismesh/polaris-plugin-api/store/retention"
+)
+
+// newStore 创建 total 个服务，其中前 deleted 个被软删除
+func newStore(t *testing.T, total, deleted int) store.Store {
+	t.Helper()
+	s := memory.New()
+	if err := s.AddNamespace(&model.Namespace{Name: "ns", Valid: true}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < total; i++ {
+		id := fmt.Sprintf("svc-%d", i)
+		if err := s.AddService(&model.Service{ID: id, Name: id, Namespace: "ns", Valid: true}); err != nil {
+			t.Fatal(err)
+		}
+		if i < deleted {
+			if err := s.DeleteService(id, id, "ns"); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	return s
+}
+
+func TestCleanerRunOnce(t *testing.T) {
+	s := newStore(t, 5, 4)
+	el := fake.New()
+	c, err := retention.New(s.(store.RetentionStore), el,
+		map[store.RetentionKind]time.Duration{store.RetentionService: time.Nanosecond}, retention.WithBatchSize(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := c.RunOnce(context.Background())
+	if err != nil || len(report) != 0 {
+		t.Fatalf("follower purged: %v, %v", report, err)
+	}
+
+	time.Sleep(time.Millisecond)
+	if err := el.StartLeaderElection(retention.DefaultElectionKey); err != nil {
+		t.Fatal(err)
+	}
+	report, err = c.RunOnce(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report[store.RetentionService] != 4 {
+		t.Fatalf("report: %v", report)
+	}
+	if count, _ := s.GetServicesCount(); count != 1 {
+		t.Fatalf("services left: %d", count)
+	}
+}
+
+func TestCleanerStartStop(t *testing.T) {
+	s := newStore(t, 3, 2)
+	el := fake.New()
+	reports := make(chan retention.Report, 10)
+	c, err := retention.New(s.(store.RetentionStore), el,
+		map[store.RetentionKind]time.Duration{store.RetentionService: time.Nanosecond, store.RetentionUser: time.Hour},
+		retention.WithInterval(20*time.Millisecond),
+		retention.WithOnRun(func(r retention.Report, err error) {
+			if err != nil {
+				t.Error(err)
+			}
+			reports <- r
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond)
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Start(context.Background()); err == nil {
+		t.Fatal("started twice")
+	}
+	select {
+	case r := <-reports:
+		if r[store.RetentionService] != 2 {
+			t.Fatalf("report: %v", r)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("cleaner never ran")
+	}
+	if err := c.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if el.IsLeader(retention.DefaultElectionKey) {
+		t.Fatal("leadership not released")
+	}
+	if err := c.Stop(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewInvalidPolicy(t *testing.T) {
+	s := memory.New().(store.RetentionStore)
+	for _, policies := range []map[store.RetentionKind]time.Duration{
+		{"bogus": time.Hour},
+		{store.RetentionService: 0},
+	} {
+		if _, err := retention.New(s, fake.New(), policies); err == nil {
+			t.Fatalf("policy %v accepted", policies)
+		}
+	}
+}
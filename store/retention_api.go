@@ -0,0 +1,91 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package store
+
+import (
+	"fmt"
+	"time"
+)
+
+// RetentionKind 支持按照保留时间物理删除的资源类型
+type RetentionKind string
+
+const (
+	RetentionService            RetentionKind = "service"
+	RetentionServiceAlias       RetentionKind = "service_alias"
+	RetentionRoutingConfig      RetentionKind = "routing_config"
+	RetentionRouterConfig       RetentionKind = "router_config"
+	RetentionRateLimit          RetentionKind = "ratelimit"
+	RetentionCircuitBreakerRule RetentionKind = "circuitbreaker_rule"
+	RetentionFaultDetectRule    RetentionKind = "fault_detect_rule"
+	RetentionServiceContract    RetentionKind = "service_contract"
+	RetentionConfigFileGroup    RetentionKind = "config_file_group"
+	RetentionConfigFile         RetentionKind = "config_file"
+	RetentionConfigFileRelease  RetentionKind = "config_file_release"
+	RetentionUser               RetentionKind = "user"
+	RetentionUserGroup          RetentionKind = "user_group"
+	RetentionStrategy           RetentionKind = "auth_strategy"
+)
+
+// RetentionKinds 返回全部资源类型，顺序即清理顺序：先清理依赖其他资源的数据，再清理被依赖的数据
+func RetentionKinds() []RetentionKind {
+	return []RetentionKind{
+		RetentionServiceAlias,
+		RetentionRoutingConfig,
+		RetentionRouterConfig,
+		RetentionRateLimit,
+		RetentionCircuitBreakerRule,
+		RetentionFaultDetectRule,
+		RetentionServiceContract,
+		RetentionService,
+		RetentionConfigFileRelease,
+		RetentionConfigFile,
+		RetentionConfigFileGroup,
+		RetentionStrategy,
+		RetentionUserGroup,
+		RetentionUser,
+	}
+}
+
+// RetentionStore 可选接口，支持物理删除软删除数据的存储插件实现该接口
+//
+// 与 BatchCleanDeletedInstances 一致，PurgeDeleted 物理删除 kind 类型中 Valid 为 false 且 ModifyTime 早于
+// 当前时间减去 timeout 的数据，每次最多删除 batchSize 条，返回实际删除的数量；kind 不支持时返回 EmptyParamsErr。
+// 被删除的数据不会再出现在 GetMore* 的增量结果中，timeout 需要大于缓存的刷新间隔，保证缓存已经感知到软删除。
+type RetentionStore interface {
+	// PurgeDeleted batch purge soft deleted resources of kind which mtime time out
+	PurgeDeleted(kind RetentionKind, timeout time.Duration, batchSize uint32) (uint32, error)
+}
+
+// NewRetentionStore s 实现了 RetentionStore 时直接返回，否则返回的适配实现的 PurgeDeleted 返回 NotSupportedErr
+func NewRetentionStore(s Store) RetentionStore {
+	if rs, ok := s.(RetentionStore); ok {
+		return rs
+	}
+	return &unsupportedRetentionStore{store: s}
+}
+
+// unsupportedRetentionStore 没有实现 RetentionStore 的存储插件的适配实现
+type unsupportedRetentionStore struct {
+	store Store
+}
+
+// PurgeDeleted 实现 RetentionStore
+func (u *unsupportedRetentionStore) PurgeDeleted(kind RetentionKind, timeout time.Duration, batchSize uint32) (uint32, error) {
+	return 0, NewStatusError(NotSupportedErr, fmt.Sprintf("store %s does not support PurgeDeleted", u.store.Name()))
+}
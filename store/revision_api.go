@@ -0,0 +1,109 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package store
+
+import (
+	"fmt"
+
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// RevisionStore 可选接口，支持基于 Revision 的条件更新（compare-and-swap）的存储插件实现该接口
+//
+// 各方法的语义与对应的 Update 方法一致，区别在于只有存储中当前的 Revision 等于 expectRevision 时才会写入，
+// 否则返回 DataConflictErr，资源不存在时返回的错误与 Update 方法一致。写入后的 Revision 由调用方在参数中指定，
+// 调用方需要保证每次修改都生成新的 Revision，避免两个控制台基于同一个版本的修改相互覆盖。
+type RevisionStore interface {
+	// UpdateServiceCAS 条件更新服务
+	UpdateServiceCAS(service *model.Service, needUpdateOwner bool, expectRevision string) error
+	// UpdateRoutingConfigCAS 条件更新路由配置
+	UpdateRoutingConfigCAS(conf *model.RoutingConfig, expectRevision string) error
+	// UpdateRoutingConfigV2CAS 条件更新 v2 版本的路由规则
+	UpdateRoutingConfigV2CAS(conf *model.RouterConfig, expectRevision string) error
+	// UpdateRateLimitCAS 条件更新限流规则
+	UpdateRateLimitCAS(limit *model.RateLimit, expectRevision string) error
+	// UpdateCircuitBreakerRuleCAS 条件更新熔断规则
+	UpdateCircuitBreakerRuleCAS(cbRule *model.CircuitBreakerRule, expectRevision string) error
+	// UpdateFaultDetectRuleCAS 条件更新主动探测规则
+	UpdateFaultDetectRuleCAS(conf *model.FaultDetectRule, expectRevision string) error
+	// UpdateStrategyCAS 条件更新鉴权策略，strategy.Revision 为写入后的 Revision
+	UpdateStrategyCAS(strategy *model.ModifyStrategyDetail, expectRevision string) error
+}
+
+// CheckRevision 存储中的 Revision 与 expectRevision 不一致时返回 DataConflictErr，供 RevisionStore 的实现使用
+func CheckRevision(resource, id, savedRevision, expectRevision string) error {
+	if savedRevision == expectRevision {
+		return nil
+	}
+	return NewStatusError(DataConflictErr, fmt.Sprintf("%s %s has been modified, expect revision %s, actual %s",
+		resource, id, expectRevision, savedRevision))
+}
+
+// NewRevisionStore s 实现了 RevisionStore 时直接返回，否则返回的适配实现的所有方法都返回 NotSupportedErr，
+// 读取后再比较写入的方式无法保证多个进程同时修改时的原子性，调用方需要自行决定是否退化为无条件的 Update
+func NewRevisionStore(s Store) RevisionStore {
+	if rs, ok := s.(RevisionStore); ok {
+		return rs
+	}
+	return &unsupportedRevisionStore{store: s}
+}
+
+// unsupportedRevisionStore 没有实现 RevisionStore 的存储插件的适配实现
+type unsupportedRevisionStore struct {
+	store Store
+}
+
+// notSupported 返回 NotSupportedErr
+func (u *unsupportedRevisionStore) notSupported(method string) error {
+	return NewStatusError(NotSupportedErr, fmt.Sprintf("store %s does not support %s", u.store.Name(), method))
+}
+
+// UpdateServiceCAS 实现 RevisionStore
+func (u *unsupportedRevisionStore) UpdateServiceCAS(service *model.Service, needUpdateOwner bool, expectRevision string) error {
+	return u.notSupported("UpdateServiceCAS")
+}
+
+// UpdateRoutingConfigCAS 实现 RevisionStore
+func (u *unsupportedRevisionStore) UpdateRoutingConfigCAS(conf *model.RoutingConfig, expectRevision string) error {
+	return u.notSupported("UpdateRoutingConfigCAS")
+}
+
+// UpdateRoutingConfigV2CAS 实现 RevisionStore
+func (u *unsupportedRevisionStore) UpdateRoutingConfigV2CAS(conf *model.RouterConfig, expectRevision string) error {
+	return u.notSupported("UpdateRoutingConfigV2CAS")
+}
+
+// UpdateRateLimitCAS 实现 RevisionStore
+func (u *unsupportedRevisionStore) UpdateRateLimitCAS(limit *model.RateLimit, expectRevision string) error {
+	return u.notSupported("UpdateRateLimitCAS")
+}
+
+// UpdateCircuitBreakerRuleCAS 实现 RevisionStore
+func (u *unsupportedRevisionStore) UpdateCircuitBreakerRuleCAS(cbRule *model.CircuitBreakerRule, expectRevision string) error {
+	return u.notSupported("UpdateCircuitBreakerRuleCAS")
+}
+
+// UpdateFaultDetectRuleCAS 实现 RevisionStore
+func (u *unsupportedRevisionStore) UpdateFaultDetectRuleCAS(conf *model.FaultDetectRule, expectRevision string) error {
+	return u.notSupported("UpdateFaultDetectRuleCAS")
+}
+
+// UpdateStrategyCAS 实现 RevisionStore
+func (u *unsupportedRevisionStore) UpdateStrategyCAS(strategy *model.ModifyStrategyDetail, expectRevision string) error {
+	return u.notSupported("UpdateStrategyCAS")
+}
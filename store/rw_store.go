@@ -0,0 +1,1766 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package store
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+const (
+	defaultMaxReplicaLag    = 5 * time.Second
+	defaultLagCheckInterval = time.Second
+)
+
+// LagChecker 查询只读副本相对主库的复制延迟
+type LagChecker func(replica Store) (time.Duration, error)
+
+// ReadWriteOption NewReadWriteStore 的可选配置
+type ReadWriteOption func(r *readWriteStore)
+
+// WithLagChecker 设置复制延迟的查询方式，未设置时 GetMore* 等增量查询全部由主库处理
+func WithLagChecker(checker LagChecker) ReadWriteOption {
+	return func(r *readWriteStore) {
+		r.lagChecker = checker
+	}
+}
+
+// WithMaxReplicaLag 设置允许的最大复制延迟，超过该延迟的副本不再处理读请求
+func WithMaxReplicaLag(lag time.Duration) ReadWriteOption {
+	return func(r *readWriteStore) {
+		if lag > 0 {
+			r.maxLag = lag
+		}
+	}
+}
+
+// WithLagCheckInterval 设置复制延迟的缓存时间，避免每次读请求都查询复制延迟
+func WithLagCheckInterval(interval time.Duration) ReadWriteOption {
+	return func(r *readWriteStore) {
+		if interval > 0 {
+			r.checkInterval = interval
+		}
+	}
+}
+
+// NewReadWriteStore 读写分离的 Store，Get*、Query*、Count*、Has* 以及 StartReadTx 轮询交给只读副本处理，
+// 其余方法以及 StartTx 交给主库处理；带有 Tx 参数的方法交给创建该 Tx 的存储处理。
+// GetMore* 以及 *ForCache 增量查询只会选择数据不早于上一次同名查询的副本，否则交给主库处理，保证缓存的数据不会回退。
+// 可选接口中 Search* 以及 *Page 与 Get* 一样交给只读副本，Range* 与增量查询一样处理，条件更新、物理删除以及 Watch 交给主库处理。
+// primary 以及 replicas 需要已经完成初始化，Initialize 不做任何处理，Destroy 会销毁所有的存储
+func NewReadWriteStore(primary Store, replicas []Store, options ...ReadWriteOption) Store {
+	r := &readWriteStore{
+		primary:       primary,
+		maxLag:        defaultMaxReplicaLag,
+		checkInterval: defaultLagCheckInterval,
+		freshness:     make(map[string]time.Time),
+	}
+	for i := range replicas {
+		r.replicas = append(r.replicas, &replica{store: replicas[i]})
+	}
+	for i := range options {
+		options[i](r)
+	}
+	return r
+}
+
+var (
+	_ Store                = (*readWriteStore)(nil)
+	_ TxStore              = (*readWriteStore)(nil)
+	_ NamingTxStore        = (*readWriteStore)(nil)
+	_ DistributedLockStore = (*readWriteStore)(nil)
+	_ LeaderWatchStore     = (*readWriteStore)(nil)
+	_ RevisionStore        = (*readWriteStore)(nil)
+	_ RetentionStore       = (*readWriteStore)(nil)
+	_ QueryStore           = (*readWriteStore)(nil)
+	_ CursorStore          = (*readWriteStore)(nil)
+	_ StreamStore          = (*readWriteStore)(nil)
+	_ WatchableStore       = (*readWriteStore)(nil)
+)
+
+type routeKind int
+
+const (
+	routeWrite routeKind = iota
+	routeRead
+	routeIncremental
+)
+
+// readWriteStore 读写分离的 Store
+type readWriteStore struct {
+	primary       Store
+	replicas      []*replica
+	next          uint32
+	lagChecker    LagChecker
+	maxLag        time.Duration
+	checkInterval time.Duration
+
+	lock sync.Mutex
+	// freshness 每个增量查询方法上一次读取到的数据的时间点
+	freshness map[string]time.Time
+}
+
+// replica 只读副本以及缓存的复制延迟
+type replica struct {
+	store Store
+
+	lock      sync.Mutex
+	checkedAt time.Time
+	lag       time.Duration
+	err       error
+}
+
+// lag 返回副本的复制延迟，known 为 false 表示无法确定复制延迟
+func (r *readWriteStore) lag(rep *replica, now time.Time) (lag time.Duration, known bool) {
+	if r.lagChecker == nil {
+		return 0, false
+	}
+	rep.lock.Lock()
+	defer rep.lock.Unlock()
+
+	if rep.checkedAt.IsZero() || now.Sub(rep.checkedAt) >= r.checkInterval {
+		rep.lag, rep.err = r.lagChecker(rep.store)
+		rep.checkedAt = now
+	}
+	return rep.lag, rep.err == nil
+}
+
+// reader 轮询选择一个可用的副本，没有可用的副本时返回主库
+func (r *readWriteStore) reader() Store {
+	s, _ := r.selectReplica(func(rep *replica, now time.Time) bool {
+		lag, known := r.lag(rep, now)
+		return r.lagChecker == nil || (known && lag <= r.maxLag)
+	})
+	return s
+}
+
+// incrementalReader 选择数据不早于上一次同名增量查询的副本，返回的回调用于在查询成功后记录本次读取的时间点
+func (r *readWriteStore) incrementalReader(method string) (Store, func(err error)) {
+	r.lock.Lock()
+	last := r.freshness[method]
+	r.lock.Unlock()
+
+	s, fresh := r.selectReplica(func(rep *replica, now time.Time) bool {
+		lag, known := r.lag(rep, now)
+		return known && lag <= r.maxLag && !now.Add(-lag).Before(last)
+	})
+	return s, func(err error) {
+		if err != nil {
+			return
+		}
+		r.lock.Lock()
+		defer r.lock.Unlock()
+		if fresh.After(r.freshness[method]) {
+			r.freshness[method] = fresh
+		}
+	}
+}
+
+// selectReplica 从下一个副本开始轮询，返回第一个满足条件的副本以及其数据对应的时间点，都不满足时返回主库
+func (r *readWriteStore) selectReplica(usable func(rep *replica, now time.Time) bool) (Store, time.Time) {
+	now := time.Now()
+	if len(r.replicas) == 0 {
+		return r.primary, now
+	}
+	start := atomic.AddUint32(&r.next, 1)
+	for i := 0; i < len(r.replicas); i++ {
+		rep := r.replicas[(int(start)+i)%len(r.replicas)]
+		if usable(rep, now) {
+			lag, _ := r.lag(rep, now)
+			return rep.store, now.Add(-lag)
+		}
+	}
+	return r.primary, now
+}
+
+// route 根据 Tx 以及方法类型选择处理请求的存储，返回需要传递给该存储的 Tx
+func (r *readWriteStore) route(method string, kind routeKind, tx Tx) (Store, Tx, func(err error)) {
+	noop := func(error) {}
+	switch t := UnwrapTx(tx).(type) {
+	case *readWriteTx:
+		return t.owner, t.Tx, noop
+	case *readWriteUnifiedTx:
+		return t.owner, t.UnifiedTx, noop
+	}
+	if tx != nil || kind == routeWrite {
+		return r.primary, tx, noop
+	}
+	if kind == routeIncremental {
+		s, done := r.incrementalReader(method)
+		return s, nil, done
+	}
+	return r.reader(), nil, noop
+}
+
+// readWriteTx 记录创建 Tx 的存储，后续使用该 Tx 的请求都交给该存储处理
+type readWriteTx struct {
+	Tx
+	owner Store
+}
+
+// readWriteUnifiedTx 记录创建 UnifiedTx 的存储
+type readWriteUnifiedTx struct {
+	UnifiedTx
+	owner Store
+}
+
+// Name 实现 Store
+func (r *readWriteStore) Name() string {
+	return r.primary.Name()
+}
+
+// Initialize 实现 Store，primary 以及 replicas 需要已经完成初始化
+func (r *readWriteStore) Initialize(c *Config) error {
+	return nil
+}
+
+// Destroy 实现 Store
+func (r *readWriteStore) Destroy() error {
+	var firstErr error
+	for _, rep := range r.replicas {
+		if err := rep.store.Destroy(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	if err := r.primary.Destroy(); err != nil && firstErr == nil {
+		firstErr = err
+	}
+	return firstErr
+}
+
+// StartTx 实现 Store
+func (r *readWriteStore) StartTx() (Tx, error) {
+	tx, err := r.primary.StartTx()
+	if err != nil {
+		return nil, err
+	}
+	return &readWriteTx{Tx: tx, owner: r.primary}, nil
+}
+
+// StartReadTx 实现 Store
+func (r *readWriteStore) StartReadTx() (Tx, error) {
+	s := r.reader()
+	tx, err := s.StartReadTx()
+	if err != nil {
+		return nil, err
+	}
+	return &readWriteTx{Tx: tx, owner: s}, nil
+}
+
+// BeginTx 实现 TxStore，只读事务轮询交给只读副本处理，其行锁只在该副本上生效，其余事务交给主库处理
+func (r *readWriteStore) BeginTx(opts TxOptions) (UnifiedTx, error) {
+	s := r.primary
+	if opts.ReadOnly {
+		s = r.reader()
+	}
+	tx, err := NewTxStore(s).BeginTx(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &readWriteUnifiedTx{UnifiedTx: tx, owner: s}, nil
+}
+
+// CreateTransaction 实现 Store
+func (r *readWriteStore) CreateTransaction() (Transaction, error) {
+	return r.primary.CreateTransaction()
+}
+
+// AddNamespace 实现 Store
+func (r *readWriteStore) AddNamespace(namespace *model.Namespace) error {
+	return r.primary.AddNamespace(namespace)
+}
+
+// UpdateNamespace 实现 Store
+func (r *readWriteStore) UpdateNamespace(namespace *model.Namespace) error {
+	return r.primary.UpdateNamespace(namespace)
+}
+
+// UpdateNamespaceToken 实现 Store
+func (r *readWriteStore) UpdateNamespaceToken(name string, token string) error {
+	return r.primary.UpdateNamespaceToken(name, token)
+}
+
+// GetNamespace 实现 Store
+func (r *readWriteStore) GetNamespace(name string) (*model.Namespace, error) {
+	return r.reader().GetNamespace(name)
+}
+
+// GetNamespaces 实现 Store
+func (r *readWriteStore) GetNamespaces(filter map[string][]string, offset int, limit int) ([]*model.Namespace, uint32, error) {
+	return r.reader().GetNamespaces(filter, offset, limit)
+}
+
+// GetMoreNamespaces 实现 Store
+func (r *readWriteStore) GetMoreNamespaces(mtime time.Time) ([]*model.Namespace, error) {
+	target, _, done := r.route("GetMoreNamespaces", routeIncremental, nil)
+	ret0, err := target.GetMoreNamespaces(mtime)
+	done(err)
+	return ret0, err
+}
+
+// AddService 实现 Store
+func (r *readWriteStore) AddService(service *model.Service) error {
+	return r.primary.AddService(service)
+}
+
+// AddServiceTx 实现 Store
+func (r *readWriteStore) AddServiceTx(tx Tx, service *model.Service) error {
+	target, tx, _ := r.route("AddServiceTx", routeWrite, tx)
+	return NewNamingTxStore(target).AddServiceTx(tx, service)
+}
+
+// DeleteService 实现 Store
+func (r *readWriteStore) DeleteService(id string, serviceName string, namespaceName string) error {
+	return r.primary.DeleteService(id, serviceName, namespaceName)
+}
+
+// DeleteServiceTx 实现 Store
+func (r *readWriteStore) DeleteServiceTx(tx Tx, id, serviceName, namespaceName string) error {
+	target, tx, _ := r.route("DeleteServiceTx", routeWrite, tx)
+	return NewNamingTxStore(target).DeleteServiceTx(tx, id, serviceName, namespaceName)
+}
+
+// DeleteServiceAlias 实现 Store
+func (r *readWriteStore) DeleteServiceAlias(name string, namespace string) error {
+	return r.primary.DeleteServiceAlias(name, namespace)
+}
+
+// DeleteServiceAliasTx 实现 Store
+func (r *readWriteStore) DeleteServiceAliasTx(tx Tx, name string, namespace string) error {
+	target, tx, _ := r.route("DeleteServiceAliasTx", routeWrite, tx)
+	return NewNamingTxStore(target).DeleteServiceAliasTx(tx, name, namespace)
+}
+
+// UpdateServiceAlias 实现 Store
+func (r *readWriteStore) UpdateServiceAlias(alias *model.Service, needUpdateOwner bool) error {
+	return r.primary.UpdateServiceAlias(alias, needUpdateOwner)
+}
+
+// UpdateServiceAliasTx 实现 Store
+func (r *readWriteStore) UpdateServiceAliasTx(tx Tx, alias *model.Service, needUpdateOwner bool) error {
+	target, tx, _ := r.route("UpdateServiceAliasTx", routeWrite, tx)
+	return NewNamingTxStore(target).UpdateServiceAliasTx(tx, alias, needUpdateOwner)
+}
+
+// UpdateService 实现 Store
+func (r *readWriteStore) UpdateService(service *model.Service, needUpdateOwner bool) error {
+	return r.primary.UpdateService(service, needUpdateOwner)
+}
+
+// UpdateServiceTx 实现 Store
+func (r *readWriteStore) UpdateServiceTx(tx Tx, service *model.Service, needUpdateOwner bool) error {
+	target, tx, _ := r.route("UpdateServiceTx", routeWrite, tx)
+	return NewNamingTxStore(target).UpdateServiceTx(tx, service, needUpdateOwner)
+}
+
+// UpdateServiceToken 实现 Store
+func (r *readWriteStore) UpdateServiceToken(serviceID string, token string, revision string) error {
+	return r.primary.UpdateServiceToken(serviceID, token, revision)
+}
+
+// UpdateServiceTokenTx 实现 Store
+func (r *readWriteStore) UpdateServiceTokenTx(tx Tx, serviceID string, token string, revision string) error {
+	target, tx, _ := r.route("UpdateServiceTokenTx", routeWrite, tx)
+	return NewNamingTxStore(target).UpdateServiceTokenTx(tx, serviceID, token, revision)
+}
+
+// GetSourceServiceToken 实现 Store
+func (r *readWriteStore) GetSourceServiceToken(name string, namespace string) (*model.Service, error) {
+	return r.reader().GetSourceServiceToken(name, namespace)
+}
+
+// GetService 实现 Store
+func (r *readWriteStore) GetService(name string, namespace string) (*model.Service, error) {
+	return r.reader().GetService(name, namespace)
+}
+
+// GetServiceByID 实现 Store
+func (r *readWriteStore) GetServiceByID(id string) (*model.Service, error) {
+	return r.reader().GetServiceByID(id)
+}
+
+// GetServices 实现 Store
+func (r *readWriteStore) GetServices(serviceFilters map[string]string, serviceMetas map[string]string,
+	instanceFilters *model.InstanceArgs, offset uint32, limit uint32) (uint32, []*model.Service, error) {
+	return r.reader().GetServices(serviceFilters, serviceMetas, instanceFilters, offset, limit)
+}
+
+// GetServicesCount 实现 Store
+func (r *readWriteStore) GetServicesCount() (uint32, error) {
+	return r.reader().GetServicesCount()
+}
+
+// GetMoreServices 实现 Store
+func (r *readWriteStore) GetMoreServices(mtime time.Time, firstUpdate bool, disableBusiness bool,
+	needMeta bool) (map[string]*model.Service, error) {
+	target, _, done := r.route("GetMoreServices", routeIncremental, nil)
+	ret0, err := target.GetMoreServices(mtime, firstUpdate, disableBusiness, needMeta)
+	done(err)
+	return ret0, err
+}
+
+// GetServiceAliases 实现 Store
+func (r *readWriteStore) GetServiceAliases(filter map[string]string, offset uint32, limit uint32) (uint32, []*model.ServiceAlias, error) {
+	return r.reader().GetServiceAliases(filter, offset, limit)
+}
+
+// GetSystemServices 实现 Store
+func (r *readWriteStore) GetSystemServices() ([]*model.Service, error) {
+	return r.reader().GetSystemServices()
+}
+
+// GetServicesBatch 实现 Store
+func (r *readWriteStore) GetServicesBatch(services []*model.Service) ([]*model.Service, error) {
+	return r.reader().GetServicesBatch(services)
+}
+
+// AddInstance 实现 Store
+func (r *readWriteStore) AddInstance(instance *model.Instance) error {
+	return r.primary.AddInstance(instance)
+}
+
+// AddInstanceTx 实现 Store
+func (r *readWriteStore) AddInstanceTx(tx Tx, instance *model.Instance) error {
+	target, tx, _ := r.route("AddInstanceTx", routeWrite, tx)
+	return NewNamingTxStore(target).AddInstanceTx(tx, instance)
+}
+
+// BatchAddInstances 实现 Store
+func (r *readWriteStore) BatchAddInstances(instances []*model.Instance) error {
+	return r.primary.BatchAddInstances(instances)
+}
+
+// BatchAddInstancesTx 实现 Store
+func (r *readWriteStore) BatchAddInstancesTx(tx Tx, instances []*model.Instance) error {
+	target, tx, _ := r.route("BatchAddInstancesTx", routeWrite, tx)
+	return NewNamingTxStore(target).BatchAddInstancesTx(tx, instances)
+}
+
+// UpdateInstance 实现 Store
+func (r *readWriteStore) UpdateInstance(instance *model.Instance) error {
+	return r.primary.UpdateInstance(instance)
+}
+
+// UpdateInstanceTx 实现 Store
+func (r *readWriteStore) UpdateInstanceTx(tx Tx, instance *model.Instance) error {
+	target, tx, _ := r.route("UpdateInstanceTx", routeWrite, tx)
+	return NewNamingTxStore(target).UpdateInstanceTx(tx, instance)
+}
+
+// DeleteInstance 实现 Store
+func (r *readWriteStore) DeleteInstance(instanceID string) error {
+	return r.primary.DeleteInstance(instanceID)
+}
+
+// DeleteInstanceTx 实现 Store
+func (r *readWriteStore) DeleteInstanceTx(tx Tx, instanceID string) error {
+	target, tx, _ := r.route("DeleteInstanceTx", routeWrite, tx)
+	return NewNamingTxStore(target).DeleteInstanceTx(tx, instanceID)
+}
+
+// BatchDeleteInstances 实现 Store
+func (r *readWriteStore) BatchDeleteInstances(ids []interface{}) error {
+	return r.primary.BatchDeleteInstances(ids)
+}
+
+// BatchDeleteInstancesTx 实现 Store
+func (r *readWriteStore) BatchDeleteInstancesTx(tx Tx, ids []interface{}) error {
+	target, tx, _ := r.route("BatchDeleteInstancesTx", routeWrite, tx)
+	return NewNamingTxStore(target).BatchDeleteInstancesTx(tx, ids)
+}
+
+// CleanInstance 实现 Store
+func (r *readWriteStore) CleanInstance(instanceID string) error {
+	return r.primary.CleanInstance(instanceID)
+}
+
+// CleanInstanceTx 实现 Store
+func (r *readWriteStore) CleanInstanceTx(tx Tx, instanceID string) error {
+	target, tx, _ := r.route("CleanInstanceTx", routeWrite, tx)
+	return NewNamingTxStore(target).CleanInstanceTx(tx, instanceID)
+}
+
+// BatchGetInstanceIsolate 实现 Store
+func (r *readWriteStore) BatchGetInstanceIsolate(ids map[string]bool) (map[string]bool, error) {
+	return r.reader().BatchGetInstanceIsolate(ids)
+}
+
+// GetInstancesBrief 实现 Store
+func (r *readWriteStore) GetInstancesBrief(ids map[string]bool) (map[string]*model.Instance, error) {
+	return r.reader().GetInstancesBrief(ids)
+}
+
+// GetInstance 实现 Store
+func (r *readWriteStore) GetInstance(instanceID string) (*model.Instance, error) {
+	return r.reader().GetInstance(instanceID)
+}
+
+// GetInstancesCount 实现 Store
+func (r *readWriteStore) GetInstancesCount() (uint32, error) {
+	return r.reader().GetInstancesCount()
+}
+
+// GetInstancesCountTx 实现 Store
+func (r *readWriteStore) GetInstancesCountTx(tx Tx) (uint32, error) {
+	target, tx, _ := r.route("GetInstancesCountTx", routeRead, tx)
+	return target.GetInstancesCountTx(tx)
+}
+
+// GetInstancesMainByService 实现 Store
+func (r *readWriteStore) GetInstancesMainByService(serviceID string, host string) ([]*model.Instance, error) {
+	return r.reader().GetInstancesMainByService(serviceID, host)
+}
+
+// GetExpandInstances 实现 Store
+func (r *readWriteStore) GetExpandInstances(filter map[string]string, metaFilter map[string]string, offset uint32,
+	limit uint32) (uint32, []*model.Instance, error) {
+	return r.reader().GetExpandInstances(filter, metaFilter, offset, limit)
+}
+
+// GetMoreInstances 实现 Store
+func (r *readWriteStore) GetMoreInstances(tx Tx, mtime time.Time, firstUpdate bool, needMeta bool,
+	serviceID []string) (map[string]*model.Instance, error) {
+	target, tx, done := r.route("GetMoreInstances", routeIncremental, tx)
+	ret0, err := target.GetMoreInstances(tx, mtime, firstUpdate, needMeta, serviceID)
+	done(err)
+	return ret0, err
+}
+
+// SetInstanceHealthStatus 实现 Store
+func (r *readWriteStore) SetInstanceHealthStatus(instanceID string, flag int, revision string) error {
+	return r.primary.SetInstanceHealthStatus(instanceID, flag, revision)
+}
+
+// SetInstanceHealthStatusTx 实现 Store
+func (r *readWriteStore) SetInstanceHealthStatusTx(tx Tx, instanceID string, flag int, revision string) error {
+	target, tx, _ := r.route("SetInstanceHealthStatusTx", routeWrite, tx)
+	return NewNamingTxStore(target).SetInstanceHealthStatusTx(tx, instanceID, flag, revision)
+}
+
+// BatchSetInstanceHealthStatus 实现 Store
+func (r *readWriteStore) BatchSetInstanceHealthStatus(ids []interface{}, healthy int, revision string) error {
+	return r.primary.BatchSetInstanceHealthStatus(ids, healthy, revision)
+}
+
+// BatchSetInstanceHealthStatusTx 实现 Store
+func (r *readWriteStore) BatchSetInstanceHealthStatusTx(tx Tx, ids []interface{}, healthy int, revision string) error {
+	target, tx, _ := r.route("BatchSetInstanceHealthStatusTx", routeWrite, tx)
+	return NewNamingTxStore(target).BatchSetInstanceHealthStatusTx(tx, ids, healthy, revision)
+}
+
+// BatchSetInstanceIsolate 实现 Store
+func (r *readWriteStore) BatchSetInstanceIsolate(ids []interface{}, isolate int, revision string) error {
+	return r.primary.BatchSetInstanceIsolate(ids, isolate, revision)
+}
+
+// BatchSetInstanceIsolateTx 实现 Store
+func (r *readWriteStore) BatchSetInstanceIsolateTx(tx Tx, ids []interface{}, isolate int, revision string) error {
+	target, tx, _ := r.route("BatchSetInstanceIsolateTx", routeWrite, tx)
+	return NewNamingTxStore(target).BatchSetInstanceIsolateTx(tx, ids, isolate, revision)
+}
+
+// BatchAppendInstanceMetadata 实现 Store
+func (r *readWriteStore) BatchAppendInstanceMetadata(requests []*model.InstanceMetadataRequest) error {
+	return r.primary.BatchAppendInstanceMetadata(requests)
+}
+
+// BatchAppendInstanceMetadataTx 实现 Store
+func (r *readWriteStore) BatchAppendInstanceMetadataTx(tx Tx, requests []*model.InstanceMetadataRequest) error {
+	target, tx, _ := r.route("BatchAppendInstanceMetadataTx", routeWrite, tx)
+	return NewNamingTxStore(target).BatchAppendInstanceMetadataTx(tx, requests)
+}
+
+// BatchRemoveInstanceMetadata 实现 Store
+func (r *readWriteStore) BatchRemoveInstanceMetadata(requests []*model.InstanceMetadataRequest) error {
+	return r.primary.BatchRemoveInstanceMetadata(requests)
+}
+
+// BatchRemoveInstanceMetadataTx 实现 Store
+func (r *readWriteStore) BatchRemoveInstanceMetadataTx(tx Tx, requests []*model.InstanceMetadataRequest) error {
+	target, tx, _ := r.route("BatchRemoveInstanceMetadataTx", routeWrite, tx)
+	return NewNamingTxStore(target).BatchRemoveInstanceMetadataTx(tx, requests)
+}
+
+// CreateRoutingConfig 实现 Store
+func (r *readWriteStore) CreateRoutingConfig(conf *model.RoutingConfig) error {
+	return r.primary.CreateRoutingConfig(conf)
+}
+
+// CreateRoutingConfigTx 实现 Store
+func (r *readWriteStore) CreateRoutingConfigTx(tx Tx, conf *model.RoutingConfig) error {
+	target, tx, _ := r.route("CreateRoutingConfigTx", routeWrite, tx)
+	return NewNamingTxStore(target).CreateRoutingConfigTx(tx, conf)
+}
+
+// UpdateRoutingConfig 实现 Store
+func (r *readWriteStore) UpdateRoutingConfig(conf *model.RoutingConfig) error {
+	return r.primary.UpdateRoutingConfig(conf)
+}
+
+// UpdateRoutingConfigTx 实现 Store
+func (r *readWriteStore) UpdateRoutingConfigTx(tx Tx, conf *model.RoutingConfig) error {
+	target, tx, _ := r.route("UpdateRoutingConfigTx", routeWrite, tx)
+	return NewNamingTxStore(target).UpdateRoutingConfigTx(tx, conf)
+}
+
+// DeleteRoutingConfig 实现 Store
+func (r *readWriteStore) DeleteRoutingConfig(serviceID string) error {
+	return r.primary.DeleteRoutingConfig(serviceID)
+}
+
+// DeleteRoutingConfigTx 实现 Store
+func (r *readWriteStore) DeleteRoutingConfigTx(tx Tx, serviceID string) error {
+	target, tx, _ := r.route("DeleteRoutingConfigTx", routeWrite, tx)
+	return target.DeleteRoutingConfigTx(tx, serviceID)
+}
+
+// GetRoutingConfigsForCache 实现 Store
+func (r *readWriteStore) GetRoutingConfigsForCache(mtime time.Time, firstUpdate bool) ([]*model.RoutingConfig, error) {
+	target, _, done := r.route("GetRoutingConfigsForCache", routeIncremental, nil)
+	ret0, err := target.GetRoutingConfigsForCache(mtime, firstUpdate)
+	done(err)
+	return ret0, err
+}
+
+// GetRoutingConfigWithService 实现 Store
+func (r *readWriteStore) GetRoutingConfigWithService(name string, namespace string) (*model.RoutingConfig, error) {
+	return r.reader().GetRoutingConfigWithService(name, namespace)
+}
+
+// GetRoutingConfigWithID 实现 Store
+func (r *readWriteStore) GetRoutingConfigWithID(id string) (*model.RoutingConfig, error) {
+	return r.reader().GetRoutingConfigWithID(id)
+}
+
+// GetRoutingConfigs 实现 Store
+func (r *readWriteStore) GetRoutingConfigs(filter map[string]string, offset uint32, limit uint32) (uint32, []*model.RoutingConfig, error) {
+	return r.reader().GetRoutingConfigs(filter, offset, limit)
+}
+
+// GetL5Extend 实现 Store
+func (r *readWriteStore) GetL5Extend(serviceID string) (map[string]interface{}, error) {
+	return r.reader().GetL5Extend(serviceID)
+}
+
+// SetL5Extend 实现 Store
+func (r *readWriteStore) SetL5Extend(serviceID string, meta map[string]interface{}) (map[string]interface{}, error) {
+	return r.primary.SetL5Extend(serviceID, meta)
+}
+
+// GenNextL5Sid 实现 Store
+func (r *readWriteStore) GenNextL5Sid(layoutID uint32) (string, error) {
+	return r.primary.GenNextL5Sid(layoutID)
+}
+
+// GetMoreL5Extend 实现 Store
+func (r *readWriteStore) GetMoreL5Extend(mtime time.Time) (map[string]map[string]interface{}, error) {
+	target, _, done := r.route("GetMoreL5Extend", routeIncremental, nil)
+	ret0, err := target.GetMoreL5Extend(mtime)
+	done(err)
+	return ret0, err
+}
+
+// GetMoreL5Routes 实现 Store
+func (r *readWriteStore) GetMoreL5Routes(flow uint32) ([]*model.Route, error) {
+	target, _, done := r.route("GetMoreL5Routes", routeIncremental, nil)
+	ret0, err := target.GetMoreL5Routes(flow)
+	done(err)
+	return ret0, err
+}
+
+// GetMoreL5Policies 实现 Store
+func (r *readWriteStore) GetMoreL5Policies(flow uint32) ([]*model.Policy, error) {
+	target, _, done := r.route("GetMoreL5Policies", routeIncremental, nil)
+	ret0, err := target.GetMoreL5Policies(flow)
+	done(err)
+	return ret0, err
+}
+
+// GetMoreL5Sections 实现 Store
+func (r *readWriteStore) GetMoreL5Sections(flow uint32) ([]*model.Section, error) {
+	target, _, done := r.route("GetMoreL5Sections", routeIncremental, nil)
+	ret0, err := target.GetMoreL5Sections(flow)
+	done(err)
+	return ret0, err
+}
+
+// GetMoreL5IPConfigs 实现 Store
+func (r *readWriteStore) GetMoreL5IPConfigs(flow uint32) ([]*model.IPConfig, error) {
+	target, _, done := r.route("GetMoreL5IPConfigs", routeIncremental, nil)
+	ret0, err := target.GetMoreL5IPConfigs(flow)
+	done(err)
+	return ret0, err
+}
+
+// CreateRateLimit 实现 Store
+func (r *readWriteStore) CreateRateLimit(limiting *model.RateLimit) error {
+	return r.primary.CreateRateLimit(limiting)
+}
+
+// CreateRateLimitTx 实现 Store
+func (r *readWriteStore) CreateRateLimitTx(tx Tx, limiting *model.RateLimit) error {
+	target, tx, _ := r.route("CreateRateLimitTx", routeWrite, tx)
+	return NewNamingTxStore(target).CreateRateLimitTx(tx, limiting)
+}
+
+// UpdateRateLimit 实现 Store
+func (r *readWriteStore) UpdateRateLimit(limiting *model.RateLimit) error {
+	return r.primary.UpdateRateLimit(limiting)
+}
+
+// UpdateRateLimitTx 实现 Store
+func (r *readWriteStore) UpdateRateLimitTx(tx Tx, limiting *model.RateLimit) error {
+	target, tx, _ := r.route("UpdateRateLimitTx", routeWrite, tx)
+	return NewNamingTxStore(target).UpdateRateLimitTx(tx, limiting)
+}
+
+// EnableRateLimit 实现 Store
+func (r *readWriteStore) EnableRateLimit(limit *model.RateLimit) error {
+	return r.primary.EnableRateLimit(limit)
+}
+
+// EnableRateLimitTx 实现 Store
+func (r *readWriteStore) EnableRateLimitTx(tx Tx, limit *model.RateLimit) error {
+	target, tx, _ := r.route("EnableRateLimitTx", routeWrite, tx)
+	return NewNamingTxStore(target).EnableRateLimitTx(tx, limit)
+}
+
+// DeleteRateLimit 实现 Store
+func (r *readWriteStore) DeleteRateLimit(limiting *model.RateLimit) error {
+	return r.primary.DeleteRateLimit(limiting)
+}
+
+// DeleteRateLimitTx 实现 Store
+func (r *readWriteStore) DeleteRateLimitTx(tx Tx, limiting *model.RateLimit) error {
+	target, tx, _ := r.route("DeleteRateLimitTx", routeWrite, tx)
+	return NewNamingTxStore(target).DeleteRateLimitTx(tx, limiting)
+}
+
+// GetExtendRateLimits 实现 Store
+func (r *readWriteStore) GetExtendRateLimits(query map[string]string, offset uint32, limit uint32) (uint32, []*model.RateLimit, error) {
+	return r.reader().GetExtendRateLimits(query, offset, limit)
+}
+
+// GetRateLimitWithID 实现 Store
+func (r *readWriteStore) GetRateLimitWithID(id string) (*model.RateLimit, error) {
+	return r.reader().GetRateLimitWithID(id)
+}
+
+// GetRateLimitsForCache 实现 Store
+func (r *readWriteStore) GetRateLimitsForCache(mtime time.Time, firstUpdate bool) ([]*model.RateLimit, error) {
+	target, _, done := r.route("GetRateLimitsForCache", routeIncremental, nil)
+	ret0, err := target.GetRateLimitsForCache(mtime, firstUpdate)
+	done(err)
+	return ret0, err
+}
+
+// CreateCircuitBreakerRule 实现 Store
+func (r *readWriteStore) CreateCircuitBreakerRule(cbRule *model.CircuitBreakerRule) error {
+	return r.primary.CreateCircuitBreakerRule(cbRule)
+}
+
+// CreateCircuitBreakerRuleTx 实现 Store
+func (r *readWriteStore) CreateCircuitBreakerRuleTx(tx Tx, cbRule *model.CircuitBreakerRule) error {
+	target, tx, _ := r.route("CreateCircuitBreakerRuleTx", routeWrite, tx)
+	return NewNamingTxStore(target).CreateCircuitBreakerRuleTx(tx, cbRule)
+}
+
+// UpdateCircuitBreakerRule 实现 Store
+func (r *readWriteStore) UpdateCircuitBreakerRule(cbRule *model.CircuitBreakerRule) error {
+	return r.primary.UpdateCircuitBreakerRule(cbRule)
+}
+
+// UpdateCircuitBreakerRuleTx 实现 Store
+func (r *readWriteStore) UpdateCircuitBreakerRuleTx(tx Tx, cbRule *model.CircuitBreakerRule) error {
+	target, tx, _ := r.route("UpdateCircuitBreakerRuleTx", routeWrite, tx)
+	return NewNamingTxStore(target).UpdateCircuitBreakerRuleTx(tx, cbRule)
+}
+
+// DeleteCircuitBreakerRule 实现 Store
+func (r *readWriteStore) DeleteCircuitBreakerRule(id string) error {
+	return r.primary.DeleteCircuitBreakerRule(id)
+}
+
+// DeleteCircuitBreakerRuleTx 实现 Store
+func (r *readWriteStore) DeleteCircuitBreakerRuleTx(tx Tx, id string) error {
+	target, tx, _ := r.route("DeleteCircuitBreakerRuleTx", routeWrite, tx)
+	return NewNamingTxStore(target).DeleteCircuitBreakerRuleTx(tx, id)
+}
+
+// HasCircuitBreakerRule 实现 Store
+func (r *readWriteStore) HasCircuitBreakerRule(id string) (bool, error) {
+	return r.reader().HasCircuitBreakerRule(id)
+}
+
+// HasCircuitBreakerRuleByName 实现 Store
+func (r *readWriteStore) HasCircuitBreakerRuleByName(name string, namespace string) (bool, error) {
+	return r.reader().HasCircuitBreakerRuleByName(name, namespace)
+}
+
+// HasCircuitBreakerRuleByNameExcludeId 实现 Store
+func (r *readWriteStore) HasCircuitBreakerRuleByNameExcludeId(name string, namespace string, id string) (bool, error) {
+	return r.reader().HasCircuitBreakerRuleByNameExcludeId(name, namespace, id)
+}
+
+// GetCircuitBreakerRules 实现 Store
+func (r *readWriteStore) GetCircuitBreakerRules(filter map[string]string, offset uint32, limit uint32) (uint32,
+	[]*model.CircuitBreakerRule, error) {
+	return r.reader().GetCircuitBreakerRules(filter, offset, limit)
+}
+
+// GetCircuitBreakerRulesForCache 实现 Store
+func (r *readWriteStore) GetCircuitBreakerRulesForCache(mtime time.Time, firstUpdate bool) ([]*model.CircuitBreakerRule, error) {
+	target, _, done := r.route("GetCircuitBreakerRulesForCache", routeIncremental, nil)
+	ret0, err := target.GetCircuitBreakerRulesForCache(mtime, firstUpdate)
+	done(err)
+	return ret0, err
+}
+
+// EnableCircuitBreakerRule 实现 Store
+func (r *readWriteStore) EnableCircuitBreakerRule(cbRule *model.CircuitBreakerRule) error {
+	return r.primary.EnableCircuitBreakerRule(cbRule)
+}
+
+// EnableCircuitBreakerRuleTx 实现 Store
+func (r *readWriteStore) EnableCircuitBreakerRuleTx(tx Tx, cbRule *model.CircuitBreakerRule) error {
+	target, tx, _ := r.route("EnableCircuitBreakerRuleTx", routeWrite, tx)
+	return NewNamingTxStore(target).EnableCircuitBreakerRuleTx(tx, cbRule)
+}
+
+// EnableRouting 实现 Store
+func (r *readWriteStore) EnableRouting(conf *model.RouterConfig) error {
+	return r.primary.EnableRouting(conf)
+}
+
+// EnableRoutingTx 实现 Store
+func (r *readWriteStore) EnableRoutingTx(tx Tx, conf *model.RouterConfig) error {
+	target, tx, _ := r.route("EnableRoutingTx", routeWrite, tx)
+	return NewNamingTxStore(target).EnableRoutingTx(tx, conf)
+}
+
+// CreateRoutingConfigV2 实现 Store
+func (r *readWriteStore) CreateRoutingConfigV2(conf *model.RouterConfig) error {
+	return r.primary.CreateRoutingConfigV2(conf)
+}
+
+// CreateRoutingConfigV2Tx 实现 Store
+func (r *readWriteStore) CreateRoutingConfigV2Tx(tx Tx, conf *model.RouterConfig) error {
+	target, tx, _ := r.route("CreateRoutingConfigV2Tx", routeWrite, tx)
+	return target.CreateRoutingConfigV2Tx(tx, conf)
+}
+
+// UpdateRoutingConfigV2 实现 Store
+func (r *readWriteStore) UpdateRoutingConfigV2(conf *model.RouterConfig) error {
+	return r.primary.UpdateRoutingConfigV2(conf)
+}
+
+// UpdateRoutingConfigV2Tx 实现 Store
+func (r *readWriteStore) UpdateRoutingConfigV2Tx(tx Tx, conf *model.RouterConfig) error {
+	target, tx, _ := r.route("UpdateRoutingConfigV2Tx", routeWrite, tx)
+	return target.UpdateRoutingConfigV2Tx(tx, conf)
+}
+
+// DeleteRoutingConfigV2 实现 Store
+func (r *readWriteStore) DeleteRoutingConfigV2(serviceID string) error {
+	return r.primary.DeleteRoutingConfigV2(serviceID)
+}
+
+// DeleteRoutingConfigV2Tx 实现 Store
+func (r *readWriteStore) DeleteRoutingConfigV2Tx(tx Tx, serviceID string) error {
+	target, tx, _ := r.route("DeleteRoutingConfigV2Tx", routeWrite, tx)
+	return NewNamingTxStore(target).DeleteRoutingConfigV2Tx(tx, serviceID)
+}
+
+// GetRoutingConfigsV2ForCache 实现 Store
+func (r *readWriteStore) GetRoutingConfigsV2ForCache(mtime time.Time, firstUpdate bool) ([]*model.RouterConfig, error) {
+	target, _, done := r.route("GetRoutingConfigsV2ForCache", routeIncremental, nil)
+	ret0, err := target.GetRoutingConfigsV2ForCache(mtime, firstUpdate)
+	done(err)
+	return ret0, err
+}
+
+// GetRoutingConfigV2WithID 实现 Store
+func (r *readWriteStore) GetRoutingConfigV2WithID(id string) (*model.RouterConfig, error) {
+	return r.reader().GetRoutingConfigV2WithID(id)
+}
+
+// GetRoutingConfigV2WithIDTx 实现 Store
+func (r *readWriteStore) GetRoutingConfigV2WithIDTx(tx Tx, id string) (*model.RouterConfig, error) {
+	target, tx, _ := r.route("GetRoutingConfigV2WithIDTx", routeRead, tx)
+	return target.GetRoutingConfigV2WithIDTx(tx, id)
+}
+
+// CreateFaultDetectRule 实现 Store
+func (r *readWriteStore) CreateFaultDetectRule(conf *model.FaultDetectRule) error {
+	return r.primary.CreateFaultDetectRule(conf)
+}
+
+// CreateFaultDetectRuleTx 实现 Store
+func (r *readWriteStore) CreateFaultDetectRuleTx(tx Tx, conf *model.FaultDetectRule) error {
+	target, tx, _ := r.route("CreateFaultDetectRuleTx", routeWrite, tx)
+	return NewNamingTxStore(target).CreateFaultDetectRuleTx(tx, conf)
+}
+
+// UpdateFaultDetectRule 实现 Store
+func (r *readWriteStore) UpdateFaultDetectRule(conf *model.FaultDetectRule) error {
+	return r.primary.UpdateFaultDetectRule(conf)
+}
+
+// UpdateFaultDetectRuleTx 实现 Store
+func (r *readWriteStore) UpdateFaultDetectRuleTx(tx Tx, conf *model.FaultDetectRule) error {
+	target, tx, _ := r.route("UpdateFaultDetectRuleTx", routeWrite, tx)
+	return NewNamingTxStore(target).UpdateFaultDetectRuleTx(tx, conf)
+}
+
+// DeleteFaultDetectRule 实现 Store
+func (r *readWriteStore) DeleteFaultDetectRule(id string) error {
+	return r.primary.DeleteFaultDetectRule(id)
+}
+
+// DeleteFaultDetectRuleTx 实现 Store
+func (r *readWriteStore) DeleteFaultDetectRuleTx(tx Tx, id string) error {
+	target, tx, _ := r.route("DeleteFaultDetectRuleTx", routeWrite, tx)
+	return NewNamingTxStore(target).DeleteFaultDetectRuleTx(tx, id)
+}
+
+// HasFaultDetectRule 实现 Store
+func (r *readWriteStore) HasFaultDetectRule(id string) (bool, error) {
+	return r.reader().HasFaultDetectRule(id)
+}
+
+// HasFaultDetectRuleByName 实现 Store
+func (r *readWriteStore) HasFaultDetectRuleByName(name string, namespace string) (bool, error) {
+	return r.reader().HasFaultDetectRuleByName(name, namespace)
+}
+
+// HasFaultDetectRuleByNameExcludeId 实现 Store
+func (r *readWriteStore) HasFaultDetectRuleByNameExcludeId(name string, namespace string, id string) (bool, error) {
+	return r.reader().HasFaultDetectRuleByNameExcludeId(name, namespace, id)
+}
+
+// GetFaultDetectRules 实现 Store
+func (r *readWriteStore) GetFaultDetectRules(filter map[string]string, offset uint32, limit uint32) (uint32,
+	[]*model.FaultDetectRule, error) {
+	return r.reader().GetFaultDetectRules(filter, offset, limit)
+}
+
+// GetFaultDetectRulesForCache 实现 Store
+func (r *readWriteStore) GetFaultDetectRulesForCache(mtime time.Time, firstUpdate bool) ([]*model.FaultDetectRule, error) {
+	target, _, done := r.route("GetFaultDetectRulesForCache", routeIncremental, nil)
+	ret0, err := target.GetFaultDetectRulesForCache(mtime, firstUpdate)
+	done(err)
+	return ret0, err
+}
+
+// CreateServiceContract 实现 Store
+func (r *readWriteStore) CreateServiceContract(contract *model.ServiceContract) error {
+	return r.primary.CreateServiceContract(contract)
+}
+
+// CreateServiceContractTx 实现 Store
+func (r *readWriteStore) CreateServiceContractTx(tx Tx, contract *model.ServiceContract) error {
+	target, tx, _ := r.route("CreateServiceContractTx", routeWrite, tx)
+	return NewNamingTxStore(target).CreateServiceContractTx(tx, contract)
+}
+
+// UpdateServiceContract 实现 Store
+func (r *readWriteStore) UpdateServiceContract(contract *model.ServiceContract) error {
+	return r.primary.UpdateServiceContract(contract)
+}
+
+// UpdateServiceContractTx 实现 Store
+func (r *readWriteStore) UpdateServiceContractTx(tx Tx, contract *model.ServiceContract) error {
+	target, tx, _ := r.route("UpdateServiceContractTx", routeWrite, tx)
+	return NewNamingTxStore(target).UpdateServiceContractTx(tx, contract)
+}
+
+// DeleteServiceContract 实现 Store
+func (r *readWriteStore) DeleteServiceContract(contract *model.ServiceContract) error {
+	return r.primary.DeleteServiceContract(contract)
+}
+
+// DeleteServiceContractTx 实现 Store
+func (r *readWriteStore) DeleteServiceContractTx(tx Tx, contract *model.ServiceContract) error {
+	target, tx, _ := r.route("DeleteServiceContractTx", routeWrite, tx)
+	return NewNamingTxStore(target).DeleteServiceContractTx(tx, contract)
+}
+
+// GetMoreServiceContracts 实现 Store
+func (r *readWriteStore) GetMoreServiceContracts(firstUpdate bool, mtime time.Time) ([]*model.ServiceContract, error) {
+	target, _, done := r.route("GetMoreServiceContracts", routeIncremental, nil)
+	ret0, err := target.GetMoreServiceContracts(firstUpdate, mtime)
+	done(err)
+	return ret0, err
+}
+
+// GetServiceContract 实现 Store
+func (r *readWriteStore) GetServiceContract(id string) (*model.ServiceContract, error) {
+	return r.reader().GetServiceContract(id)
+}
+
+// AddServiceContractInterfaces 实现 Store
+func (r *readWriteStore) AddServiceContractInterfaces(contract *model.ServiceContract) error {
+	return r.primary.AddServiceContractInterfaces(contract)
+}
+
+// AddServiceContractInterfacesTx 实现 Store
+func (r *readWriteStore) AddServiceContractInterfacesTx(tx Tx, contract *model.ServiceContract) error {
+	target, tx, _ := r.route("AddServiceContractInterfacesTx", routeWrite, tx)
+	return NewNamingTxStore(target).AddServiceContractInterfacesTx(tx, contract)
+}
+
+// AppendServiceContractInterfaces 实现 Store
+func (r *readWriteStore) AppendServiceContractInterfaces(contract *model.ServiceContract) error {
+	return r.primary.AppendServiceContractInterfaces(contract)
+}
+
+// AppendServiceContractInterfacesTx 实现 Store
+func (r *readWriteStore) AppendServiceContractInterfacesTx(tx Tx, contract *model.ServiceContract) error {
+	target, tx, _ := r.route("AppendServiceContractInterfacesTx", routeWrite, tx)
+	return NewNamingTxStore(target).AppendServiceContractInterfacesTx(tx, contract)
+}
+
+// DeleteServiceContractInterfaces 实现 Store
+func (r *readWriteStore) DeleteServiceContractInterfaces(contract *model.ServiceContract) error {
+	return r.primary.DeleteServiceContractInterfaces(contract)
+}
+
+// DeleteServiceContractInterfacesTx 实现 Store
+func (r *readWriteStore) DeleteServiceContractInterfacesTx(tx Tx, contract *model.ServiceContract) error {
+	target, tx, _ := r.route("DeleteServiceContractInterfacesTx", routeWrite, tx)
+	return NewNamingTxStore(target).DeleteServiceContractInterfacesTx(tx, contract)
+}
+
+// CreateConfigFileGroup 实现 Store
+func (r *readWriteStore) CreateConfigFileGroup(fileGroup *model.ConfigFileGroup) (*model.ConfigFileGroup, error) {
+	return r.primary.CreateConfigFileGroup(fileGroup)
+}
+
+// UpdateConfigFileGroup 实现 Store
+func (r *readWriteStore) UpdateConfigFileGroup(fileGroup *model.ConfigFileGroup) error {
+	return r.primary.UpdateConfigFileGroup(fileGroup)
+}
+
+// GetConfigFileGroup 实现 Store
+func (r *readWriteStore) GetConfigFileGroup(namespace string, name string) (*model.ConfigFileGroup, error) {
+	return r.reader().GetConfigFileGroup(namespace, name)
+}
+
+// DeleteConfigFileGroup 实现 Store
+func (r *readWriteStore) DeleteConfigFileGroup(namespace string, name string) error {
+	return r.primary.DeleteConfigFileGroup(namespace, name)
+}
+
+// GetMoreConfigGroup 实现 Store
+func (r *readWriteStore) GetMoreConfigGroup(firstUpdate bool, mtime time.Time) ([]*model.ConfigFileGroup, error) {
+	target, _, done := r.route("GetMoreConfigGroup", routeIncremental, nil)
+	ret0, err := target.GetMoreConfigGroup(firstUpdate, mtime)
+	done(err)
+	return ret0, err
+}
+
+// CountConfigGroups 实现 Store
+func (r *readWriteStore) CountConfigGroups(namespace string) (uint64, error) {
+	return r.reader().CountConfigGroups(namespace)
+}
+
+// LockConfigFile 实现 Store
+func (r *readWriteStore) LockConfigFile(tx Tx, file *model.ConfigFileKey) (*model.ConfigFile, error) {
+	target, tx, _ := r.route("LockConfigFile", routeWrite, tx)
+	return target.LockConfigFile(tx, file)
+}
+
+// CreateConfigFileTx 实现 Store
+func (r *readWriteStore) CreateConfigFileTx(tx Tx, file *model.ConfigFile) error {
+	target, tx, _ := r.route("CreateConfigFileTx", routeWrite, tx)
+	return target.CreateConfigFileTx(tx, file)
+}
+
+// GetConfigFile 实现 Store
+func (r *readWriteStore) GetConfigFile(namespace string, group string, name string) (*model.ConfigFile, error) {
+	return r.reader().GetConfigFile(namespace, group, name)
+}
+
+// GetConfigFileTx 实现 Store
+func (r *readWriteStore) GetConfigFileTx(tx Tx, namespace string, group string, name string) (*model.ConfigFile, error) {
+	target, tx, _ := r.route("GetConfigFileTx", routeRead, tx)
+	return target.GetConfigFileTx(tx, namespace, group, name)
+}
+
+// QueryConfigFiles 实现 Store
+func (r *readWriteStore) QueryConfigFiles(filter map[string]string, offset uint32, limit uint32) (uint32, []*model.ConfigFile, error) {
+	return r.reader().QueryConfigFiles(filter, offset, limit)
+}
+
+// UpdateConfigFileTx 实现 Store
+func (r *readWriteStore) UpdateConfigFileTx(tx Tx, file *model.ConfigFile) error {
+	target, tx, _ := r.route("UpdateConfigFileTx", routeWrite, tx)
+	return target.UpdateConfigFileTx(tx, file)
+}
+
+// DeleteConfigFileTx 实现 Store
+func (r *readWriteStore) DeleteConfigFileTx(tx Tx, namespace string, group string, name string) error {
+	target, tx, _ := r.route("DeleteConfigFileTx", routeWrite, tx)
+	return target.DeleteConfigFileTx(tx, namespace, group, name)
+}
+
+// CountConfigFiles 实现 Store
+func (r *readWriteStore) CountConfigFiles(namespace string, group string) (uint64, error) {
+	return r.reader().CountConfigFiles(namespace, group)
+}
+
+// CountConfigFileEachGroup 实现 Store
+func (r *readWriteStore) CountConfigFileEachGroup() (map[string]map[string]int64, error) {
+	return r.reader().CountConfigFileEachGroup()
+}
+
+// GetConfigFileActiveRelease 实现 Store
+func (r *readWriteStore) GetConfigFileActiveRelease(file *model.ConfigFileKey) (*model.ConfigFileRelease, error) {
+	return r.reader().GetConfigFileActiveRelease(file)
+}
+
+// GetConfigFileActiveReleaseTx 实现 Store
+func (r *readWriteStore) GetConfigFileActiveReleaseTx(tx Tx, file *model.ConfigFileKey) (*model.ConfigFileRelease, error) {
+	target, tx, _ := r.route("GetConfigFileActiveReleaseTx", routeRead, tx)
+	return target.GetConfigFileActiveReleaseTx(tx, file)
+}
+
+// CreateConfigFileReleaseTx 实现 Store
+func (r *readWriteStore) CreateConfigFileReleaseTx(tx Tx, fileRelease *model.ConfigFileRelease) error {
+	target, tx, _ := r.route("CreateConfigFileReleaseTx", routeWrite, tx)
+	return target.CreateConfigFileReleaseTx(tx, fileRelease)
+}
+
+// GetConfigFileRelease 实现 Store
+func (r *readWriteStore) GetConfigFileRelease(req *model.ConfigFileReleaseKey) (*model.ConfigFileRelease, error) {
+	return r.reader().GetConfigFileRelease(req)
+}
+
+// GetConfigFileReleaseTx 实现 Store
+func (r *readWriteStore) GetConfigFileReleaseTx(tx Tx, req *model.ConfigFileReleaseKey) (*model.ConfigFileRelease, error) {
+	target, tx, _ := r.route("GetConfigFileReleaseTx", routeRead, tx)
+	return target.GetConfigFileReleaseTx(tx, req)
+}
+
+// DeleteConfigFileReleaseTx 实现 Store
+func (r *readWriteStore) DeleteConfigFileReleaseTx(tx Tx, data *model.ConfigFileReleaseKey) error {
+	target, tx, _ := r.route("DeleteConfigFileReleaseTx", routeWrite, tx)
+	return target.DeleteConfigFileReleaseTx(tx, data)
+}
+
+// ActiveConfigFileReleaseTx 实现 Store
+func (r *readWriteStore) ActiveConfigFileReleaseTx(tx Tx, release *model.ConfigFileRelease) error {
+	target, tx, _ := r.route("ActiveConfigFileReleaseTx", routeWrite, tx)
+	return target.ActiveConfigFileReleaseTx(tx, release)
+}
+
+// InactiveConfigFileReleaseTx 实现 Store
+func (r *readWriteStore) InactiveConfigFileReleaseTx(tx Tx, release *model.ConfigFileRelease) error {
+	target, tx, _ := r.route("InactiveConfigFileReleaseTx", routeWrite, tx)
+	return target.InactiveConfigFileReleaseTx(tx, release)
+}
+
+// CleanConfigFileReleasesTx 实现 Store
+func (r *readWriteStore) CleanConfigFileReleasesTx(tx Tx, namespace string, group string, fileName string) error {
+	target, tx, _ := r.route("CleanConfigFileReleasesTx", routeWrite, tx)
+	return target.CleanConfigFileReleasesTx(tx, namespace, group, fileName)
+}
+
+// GetMoreReleaseFile 实现 Store
+func (r *readWriteStore) GetMoreReleaseFile(firstUpdate bool, modifyTime time.Time) ([]*model.ConfigFileRelease, error) {
+	target, _, done := r.route("GetMoreReleaseFile", routeIncremental, nil)
+	ret0, err := target.GetMoreReleaseFile(firstUpdate, modifyTime)
+	done(err)
+	return ret0, err
+}
+
+// CountConfigReleases 实现 Store
+func (r *readWriteStore) CountConfigReleases(namespace string, group string, onlyActive bool) (uint64, error) {
+	return r.reader().CountConfigReleases(namespace, group, onlyActive)
+}
+
+// GetConfigFileBetaReleaseTx 实现 Store
+func (r *readWriteStore) GetConfigFileBetaReleaseTx(tx Tx, file *model.ConfigFileKey) (*model.ConfigFileRelease, error) {
+	target, tx, _ := r.route("GetConfigFileBetaReleaseTx", routeRead, tx)
+	return target.GetConfigFileBetaReleaseTx(tx, file)
+}
+
+// CreateConfigFileReleaseHistory 实现 Store
+func (r *readWriteStore) CreateConfigFileReleaseHistory(history *model.ConfigFileReleaseHistory) error {
+	return r.primary.CreateConfigFileReleaseHistory(history)
+}
+
+// QueryConfigFileReleaseHistories 实现 Store
+func (r *readWriteStore) QueryConfigFileReleaseHistories(filter map[string]string, offset uint32, limit uint32) (uint32,
+	[]*model.ConfigFileReleaseHistory, error) {
+	return r.reader().QueryConfigFileReleaseHistories(filter, offset, limit)
+}
+
+// CleanConfigFileReleaseHistory 实现 Store
+func (r *readWriteStore) CleanConfigFileReleaseHistory(endTime time.Time, limit uint64) error {
+	return r.primary.CleanConfigFileReleaseHistory(endTime, limit)
+}
+
+// QueryAllConfigFileTemplates 实现 Store
+func (r *readWriteStore) QueryAllConfigFileTemplates() ([]*model.ConfigFileTemplate, error) {
+	return r.reader().QueryAllConfigFileTemplates()
+}
+
+// CreateConfigFileTemplate 实现 Store
+func (r *readWriteStore) CreateConfigFileTemplate(template *model.ConfigFileTemplate) (*model.ConfigFileTemplate, error) {
+	return r.primary.CreateConfigFileTemplate(template)
+}
+
+// GetConfigFileTemplate 实现 Store
+func (r *readWriteStore) GetConfigFileTemplate(name string) (*model.ConfigFileTemplate, error) {
+	return r.reader().GetConfigFileTemplate(name)
+}
+
+// BatchAddClients 实现 Store
+func (r *readWriteStore) BatchAddClients(clients []*model.Client) error {
+	return r.primary.BatchAddClients(clients)
+}
+
+// BatchDeleteClients 实现 Store
+func (r *readWriteStore) BatchDeleteClients(ids []string) error {
+	return r.primary.BatchDeleteClients(ids)
+}
+
+// GetMoreClients 实现 Store
+func (r *readWriteStore) GetMoreClients(mtime time.Time, firstUpdate bool) (map[string]*model.Client, error) {
+	target, _, done := r.route("GetMoreClients", routeIncremental, nil)
+	ret0, err := target.GetMoreClients(mtime, firstUpdate)
+	done(err)
+	return ret0, err
+}
+
+// StartLeaderElection 实现 Store
+func (r *readWriteStore) StartLeaderElection(key string) error {
+	return r.primary.StartLeaderElection(key)
+}
+
+// IsLeader 实现 Store
+func (r *readWriteStore) IsLeader(key string) bool {
+	return r.primary.IsLeader(key)
+}
+
+// ListLeaderElections 实现 Store
+func (r *readWriteStore) ListLeaderElections() ([]*model.LeaderElection, error) {
+	return r.primary.ListLeaderElections()
+}
+
+// ReleaseLeaderElection 实现 Store
+func (r *readWriteStore) ReleaseLeaderElection(key string) error {
+	return r.primary.ReleaseLeaderElection(key)
+}
+
+// BatchCleanDeletedInstances 实现 Store
+func (r *readWriteStore) BatchCleanDeletedInstances(timeout time.Duration, batchSize uint32) (uint32, error) {
+	return r.primary.BatchCleanDeletedInstances(timeout, batchSize)
+}
+
+// GetUnHealthyInstances 实现 Store
+func (r *readWriteStore) GetUnHealthyInstances(timeout time.Duration, limit uint32) ([]string, error) {
+	return r.reader().GetUnHealthyInstances(timeout, limit)
+}
+
+// BatchCleanDeletedClients 实现 Store
+func (r *readWriteStore) BatchCleanDeletedClients(timeout time.Duration, batchSize uint32) (uint32, error) {
+	return r.primary.BatchCleanDeletedClients(timeout, batchSize)
+}
+
+// AcquireLock 实现 DistributedLockStore
+func (r *readWriteStore) AcquireLock(key string, owner string, ttl time.Duration) (*model.DistributedLock, error) {
+	return NewDistributedLockStore(r.primary).AcquireLock(key, owner, ttl)
+}
+
+// RenewLock 实现 DistributedLockStore
+func (r *readWriteStore) RenewLock(key string, owner string, ttl time.Duration) (*model.DistributedLock, error) {
+	return NewDistributedLockStore(r.primary).RenewLock(key, owner, ttl)
+}
+
+// ReleaseLock 实现 DistributedLockStore
+func (r *readWriteStore) ReleaseLock(key string, owner string) error {
+	return NewDistributedLockStore(r.primary).ReleaseLock(key, owner)
+}
+
+// ListLocks 实现 DistributedLockStore，锁的状态需要实时，因此交给主库处理
+func (r *readWriteStore) ListLocks() ([]*model.DistributedLock, error) {
+	return NewDistributedLockStore(r.primary).ListLocks()
+}
+
+// WatchLeaderElection 实现 LeaderWatchStore，leader 状态需要实时，因此交给主库处理
+func (r *readWriteStore) WatchLeaderElection(ctx context.Context, key string) (LeaderWatcher, error) {
+	return WatchLeaderElection(ctx, r.primary, key)
+}
+
+// CleanGrayResource 实现 Store
+func (r *readWriteStore) CleanGrayResource(tx Tx, data *model.GrayResource) error {
+	target, tx, _ := r.route("CleanGrayResource", routeWrite, tx)
+	return target.CleanGrayResource(tx, data)
+}
+
+// CreateGrayResourceTx 实现 Store
+func (r *readWriteStore) CreateGrayResourceTx(tx Tx, data *model.GrayResource) error {
+	target, tx, _ := r.route("CreateGrayResourceTx", routeWrite, tx)
+	return target.CreateGrayResourceTx(tx, data)
+}
+
+// GetMoreGrayResouces 实现 Store
+func (r *readWriteStore) GetMoreGrayResouces(firstUpdate bool, mtime time.Time) ([]*model.GrayResource, error) {
+	target, _, done := r.route("GetMoreGrayResouces", routeIncremental, nil)
+	ret0, err := target.GetMoreGrayResouces(firstUpdate, mtime)
+	done(err)
+	return ret0, err
+}
+
+// AddUser 实现 Store
+func (r *readWriteStore) AddUser(user *model.User) error {
+	return r.primary.AddUser(user)
+}
+
+// UpdateUser 实现 Store
+func (r *readWriteStore) UpdateUser(user *model.User) error {
+	return r.primary.UpdateUser(user)
+}
+
+// DeleteUser 实现 Store
+func (r *readWriteStore) DeleteUser(user *model.User) error {
+	return r.primary.DeleteUser(user)
+}
+
+// GetSubCount 实现 Store
+func (r *readWriteStore) GetSubCount(user *model.User) (uint32, error) {
+	return r.reader().GetSubCount(user)
+}
+
+// GetUser 实现 Store
+func (r *readWriteStore) GetUser(id string) (*model.User, error) {
+	return r.reader().GetUser(id)
+}
+
+// GetUserByName 实现 Store
+func (r *readWriteStore) GetUserByName(name string, ownerId string) (*model.User, error) {
+	return r.reader().GetUserByName(name, ownerId)
+}
+
+// GetUserByIds 实现 Store
+func (r *readWriteStore) GetUserByIds(ids []string) ([]*model.User, error) {
+	return r.reader().GetUserByIds(ids)
+}
+
+// GetUsers 实现 Store
+func (r *readWriteStore) GetUsers(filters map[string]string, offset uint32, limit uint32) (uint32, []*model.User, error) {
+	return r.reader().GetUsers(filters, offset, limit)
+}
+
+// GetUsersForCache 实现 Store
+func (r *readWriteStore) GetUsersForCache(mtime time.Time, firstUpdate bool) ([]*model.User, error) {
+	target, _, done := r.route("GetUsersForCache", routeIncremental, nil)
+	ret0, err := target.GetUsersForCache(mtime, firstUpdate)
+	done(err)
+	return ret0, err
+}
+
+// AddGroup 实现 Store
+func (r *readWriteStore) AddGroup(group *model.UserGroup) error {
+	return r.primary.AddGroup(group)
+}
+
+// UpdateGroup 实现 Store
+func (r *readWriteStore) UpdateGroup(group *model.ModifyUserGroup) error {
+	return r.primary.UpdateGroup(group)
+}
+
+// DeleteGroup 实现 Store
+func (r *readWriteStore) DeleteGroup(group *model.UserGroup) error {
+	return r.primary.DeleteGroup(group)
+}
+
+// GetGroup 实现 Store
+func (r *readWriteStore) GetGroup(id string) (*model.UserGroup, error) {
+	return r.reader().GetGroup(id)
+}
+
+// GetGroupByName 实现 Store
+func (r *readWriteStore) GetGroupByName(name string, owner string) (*model.UserGroup, error) {
+	return r.reader().GetGroupByName(name, owner)
+}
+
+// GetGroups 实现 Store
+func (r *readWriteStore) GetGroups(filters map[string]string, offset uint32, limit uint32) (uint32, []*model.UserGroup, error) {
+	return r.reader().GetGroups(filters, offset, limit)
+}
+
+// GetGroupsForCache 实现 Store
+func (r *readWriteStore) GetGroupsForCache(mtime time.Time, firstUpdate bool) ([]*model.UserGroup, error) {
+	target, _, done := r.route("GetGroupsForCache", routeIncremental, nil)
+	ret0, err := target.GetGroupsForCache(mtime, firstUpdate)
+	done(err)
+	return ret0, err
+}
+
+// AddStrategy 实现 Store
+func (r *readWriteStore) AddStrategy(strategy *model.StrategyDetail) error {
+	return r.primary.AddStrategy(strategy)
+}
+
+// UpdateStrategy 实现 Store
+func (r *readWriteStore) UpdateStrategy(strategy *model.ModifyStrategyDetail) error {
+	return r.primary.UpdateStrategy(strategy)
+}
+
+// DeleteStrategy 实现 Store
+func (r *readWriteStore) DeleteStrategy(id string) error {
+	return r.primary.DeleteStrategy(id)
+}
+
+// LooseAddStrategyResources 实现 Store
+func (r *readWriteStore) LooseAddStrategyResources(resources []model.StrategyResource) error {
+	return r.primary.LooseAddStrategyResources(resources)
+}
+
+// RemoveStrategyResources 实现 Store
+func (r *readWriteStore) RemoveStrategyResources(resources []model.StrategyResource) error {
+	return r.primary.RemoveStrategyResources(resources)
+}
+
+// GetStrategyResources 实现 Store
+func (r *readWriteStore) GetStrategyResources(principalId string, principalRole string) ([]model.StrategyResource, error) {
+	return r.reader().GetStrategyResources(principalId, principalRole)
+}
+
+// GetDefaultStrategyDetailByPrincipal 实现 Store
+func (r *readWriteStore) GetDefaultStrategyDetailByPrincipal(principalId string, principalType string) (*model.StrategyDetail, error) {
+	return r.reader().GetDefaultStrategyDetailByPrincipal(principalId, principalType)
+}
+
+// GetStrategyDetail 实现 Store
+func (r *readWriteStore) GetStrategyDetail(id string) (*model.StrategyDetail, error) {
+	return r.reader().GetStrategyDetail(id)
+}
+
+// GetStrategies 实现 Store
+func (r *readWriteStore) GetStrategies(filters map[string]string, offset uint32, limit uint32) (uint32, []*model.StrategyDetail, error) {
+	return r.reader().GetStrategies(filters, offset, limit)
+}
+
+// GetStrategyDetailsForCache 实现 Store
+func (r *readWriteStore) GetStrategyDetailsForCache(mtime time.Time, firstUpdate bool) ([]*model.StrategyDetail, error) {
+	target, _, done := r.route("GetStrategyDetailsForCache", routeIncremental, nil)
+	ret0, err := target.GetStrategyDetailsForCache(mtime, firstUpdate)
+	done(err)
+	return ret0, err
+}
+
+// UpdateServiceCAS 实现 RevisionStore
+func (r *readWriteStore) UpdateServiceCAS(service *model.Service, needUpdateOwner bool, expectRevision string) error {
+	return NewRevisionStore(r.primary).UpdateServiceCAS(service, needUpdateOwner, expectRevision)
+}
+
+// UpdateRoutingConfigCAS 实现 RevisionStore
+func (r *readWriteStore) UpdateRoutingConfigCAS(conf *model.RoutingConfig, expectRevision string) error {
+	return NewRevisionStore(r.primary).UpdateRoutingConfigCAS(conf, expectRevision)
+}
+
+// UpdateRoutingConfigV2CAS 实现 RevisionStore
+func (r *readWriteStore) UpdateRoutingConfigV2CAS(conf *model.RouterConfig, expectRevision string) error {
+	return NewRevisionStore(r.primary).UpdateRoutingConfigV2CAS(conf, expectRevision)
+}
+
+// UpdateRateLimitCAS 实现 RevisionStore
+func (r *readWriteStore) UpdateRateLimitCAS(limit *model.RateLimit, expectRevision string) error {
+	return NewRevisionStore(r.primary).UpdateRateLimitCAS(limit, expectRevision)
+}
+
+// UpdateCircuitBreakerRuleCAS 实现 RevisionStore
+func (r *readWriteStore) UpdateCircuitBreakerRuleCAS(cbRule *model.CircuitBreakerRule, expectRevision string) error {
+	return NewRevisionStore(r.primary).UpdateCircuitBreakerRuleCAS(cbRule, expectRevision)
+}
+
+// UpdateFaultDetectRuleCAS 实现 RevisionStore
+func (r *readWriteStore) UpdateFaultDetectRuleCAS(conf *model.FaultDetectRule, expectRevision string) error {
+	return NewRevisionStore(r.primary).UpdateFaultDetectRuleCAS(conf, expectRevision)
+}
+
+// UpdateStrategyCAS 实现 RevisionStore
+func (r *readWriteStore) UpdateStrategyCAS(strategy *model.ModifyStrategyDetail, expectRevision string) error {
+	return NewRevisionStore(r.primary).UpdateStrategyCAS(strategy, expectRevision)
+}
+
+// PurgeDeleted 实现 RetentionStore
+func (r *readWriteStore) PurgeDeleted(kind RetentionKind, timeout time.Duration, batchSize uint32) (uint32, error) {
+	return NewRetentionStore(r.primary).PurgeDeleted(kind, timeout, batchSize)
+}
+
+// SearchServices 实现 QueryStore
+func (r *readWriteStore) SearchServices(q *Query) (uint32, []*model.Service, error) {
+	return NewQueryStore(r.reader()).SearchServices(q)
+}
+
+// SearchInstances 实现 QueryStore
+func (r *readWriteStore) SearchInstances(q *Query) (uint32, []*model.Instance, error) {
+	return NewQueryStore(r.reader()).SearchInstances(q)
+}
+
+// SearchRoutingConfigs 实现 QueryStore
+func (r *readWriteStore) SearchRoutingConfigs(q *Query) (uint32, []*model.RoutingConfig, error) {
+	return NewQueryStore(r.reader()).SearchRoutingConfigs(q)
+}
+
+// SearchFaultDetectRules 实现 QueryStore
+func (r *readWriteStore) SearchFaultDetectRules(q *Query) (uint32, []*model.FaultDetectRule, error) {
+	return NewQueryStore(r.reader()).SearchFaultDetectRules(q)
+}
+
+// SearchConfigFiles 实现 QueryStore
+func (r *readWriteStore) SearchConfigFiles(q *Query) (uint32, []*model.ConfigFile, error) {
+	return NewQueryStore(r.reader()).SearchConfigFiles(q)
+}
+
+// SearchUsers 实现 QueryStore
+func (r *readWriteStore) SearchUsers(q *Query) (uint32, []*model.User, error) {
+	return NewQueryStore(r.reader()).SearchUsers(q)
+}
+
+// SearchStrategies 实现 QueryStore
+func (r *readWriteStore) SearchStrategies(q *Query) (uint32, []*model.StrategyDetail, error) {
+	return NewQueryStore(r.reader()).SearchStrategies(q)
+}
+
+// GetNamespacesPage 实现 CursorStore
+func (r *readWriteStore) GetNamespacesPage(filter map[string][]string, token PageToken, limit uint32) (*Page[*model.Namespace], error) {
+	return NewCursorStore(r.reader()).GetNamespacesPage(filter, token, limit)
+}
+
+// GetServicesPage 实现 CursorStore
+func (r *readWriteStore) GetServicesPage(serviceFilters map[string]string, serviceMetas map[string]string,
+	instanceFilters *model.InstanceArgs, token PageToken, limit uint32) (*Page[*model.Service], error) {
+	return NewCursorStore(r.reader()).GetServicesPage(serviceFilters, serviceMetas, instanceFilters, token, limit)
+}
+
+// GetServiceAliasesPage 实现 CursorStore
+func (r *readWriteStore) GetServiceAliasesPage(filter map[string]string, token PageToken,
+	limit uint32) (*Page[*model.ServiceAlias], error) {
+	return NewCursorStore(r.reader()).GetServiceAliasesPage(filter, token, limit)
+}
+
+// GetExpandInstancesPage 实现 CursorStore
+func (r *readWriteStore) GetExpandInstancesPage(filter map[string]string, metaFilter map[string]string, token PageToken,
+	limit uint32) (*Page[*model.Instance], error) {
+	return NewCursorStore(r.reader()).GetExpandInstancesPage(filter, metaFilter, token, limit)
+}
+
+// GetRoutingConfigsPage 实现 CursorStore
+func (r *readWriteStore) GetRoutingConfigsPage(filter map[string]string, token PageToken,
+	limit uint32) (*Page[*model.RoutingConfig], error) {
+	return NewCursorStore(r.reader()).GetRoutingConfigsPage(filter, token, limit)
+}
+
+// GetExtendRateLimitsPage 实现 CursorStore
+func (r *readWriteStore) GetExtendRateLimitsPage(query map[string]string, token PageToken, limit uint32) (*Page[*model.RateLimit], error) {
+	return NewCursorStore(r.reader()).GetExtendRateLimitsPage(query, token, limit)
+}
+
+// GetCircuitBreakerRulesPage 实现 CursorStore
+func (r *readWriteStore) GetCircuitBreakerRulesPage(filter map[string]string, token PageToken,
+	limit uint32) (*Page[*model.CircuitBreakerRule], error) {
+	return NewCursorStore(r.reader()).GetCircuitBreakerRulesPage(filter, token, limit)
+}
+
+// GetFaultDetectRulesPage 实现 CursorStore
+func (r *readWriteStore) GetFaultDetectRulesPage(filter map[string]string, token PageToken,
+	limit uint32) (*Page[*model.FaultDetectRule], error) {
+	return NewCursorStore(r.reader()).GetFaultDetectRulesPage(filter, token, limit)
+}
+
+// QueryConfigFilesPage 实现 CursorStore
+func (r *readWriteStore) QueryConfigFilesPage(filter map[string]string, token PageToken, limit uint32) (*Page[*model.ConfigFile], error) {
+	return NewCursorStore(r.reader()).QueryConfigFilesPage(filter, token, limit)
+}
+
+// QueryConfigFileReleaseHistoriesPage 实现 CursorStore
+func (r *readWriteStore) QueryConfigFileReleaseHistoriesPage(filter map[string]string, token PageToken,
+	limit uint32) (*Page[*model.ConfigFileReleaseHistory], error) {
+	return NewCursorStore(r.reader()).QueryConfigFileReleaseHistoriesPage(filter, token, limit)
+}
+
+// GetUsersPage 实现 CursorStore
+func (r *readWriteStore) GetUsersPage(filters map[string]string, token PageToken, limit uint32) (*Page[*model.User], error) {
+	return NewCursorStore(r.reader()).GetUsersPage(filters, token, limit)
+}
+
+// GetGroupsPage 实现 CursorStore
+func (r *readWriteStore) GetGroupsPage(filters map[string]string, token PageToken, limit uint32) (*Page[*model.UserGroup], error) {
+	return NewCursorStore(r.reader()).GetGroupsPage(filters, token, limit)
+}
+
+// GetStrategiesPage 实现 CursorStore
+func (r *readWriteStore) GetStrategiesPage(filters map[string]string, token PageToken, limit uint32) (*Page[*model.StrategyDetail], error) {
+	return NewCursorStore(r.reader()).GetStrategiesPage(filters, token, limit)
+}
+
+// RangeMoreNamespaces 实现 StreamStore
+func (r *readWriteStore) RangeMoreNamespaces(mtime time.Time, batchSize int, handler BatchHandler[*model.Namespace]) error {
+	target, _, done := r.route("RangeMoreNamespaces", routeIncremental, nil)
+	err := NewStreamStore(target).RangeMoreNamespaces(mtime, batchSize, handler)
+	done(err)
+	return err
+}
+
+// RangeMoreServices 实现 StreamStore
+func (r *readWriteStore) RangeMoreServices(mtime time.Time, firstUpdate bool, disableBusiness bool, needMeta bool,
+	batchSize int, handler BatchHandler[*model.Service]) error {
+	target, _, done := r.route("RangeMoreServices", routeIncremental, nil)
+	err := NewStreamStore(target).RangeMoreServices(mtime, firstUpdate, disableBusiness, needMeta, batchSize, handler)
+	done(err)
+	return err
+}
+
+// RangeMoreInstances 实现 StreamStore
+func (r *readWriteStore) RangeMoreInstances(tx Tx, mtime time.Time, firstUpdate bool, needMeta bool, serviceID []string,
+	batchSize int, handler BatchHandler[*model.Instance]) error {
+	target, tx, done := r.route("RangeMoreInstances", routeIncremental, tx)
+	err := NewStreamStore(target).RangeMoreInstances(tx, mtime, firstUpdate, needMeta, serviceID, batchSize, handler)
+	done(err)
+	return err
+}
+
+// RangeMoreClients 实现 StreamStore
+func (r *readWriteStore) RangeMoreClients(mtime time.Time, firstUpdate bool, batchSize int, handler BatchHandler[*model.Client]) error {
+	target, _, done := r.route("RangeMoreClients", routeIncremental, nil)
+	err := NewStreamStore(target).RangeMoreClients(mtime, firstUpdate, batchSize, handler)
+	done(err)
+	return err
+}
+
+// RangeMoreServiceContracts 实现 StreamStore
+func (r *readWriteStore) RangeMoreServiceContracts(firstUpdate bool, mtime time.Time, batchSize int,
+	handler BatchHandler[*model.ServiceContract]) error {
+	target, _, done := r.route("RangeMoreServiceContracts", routeIncremental, nil)
+	err := NewStreamStore(target).RangeMoreServiceContracts(firstUpdate, mtime, batchSize, handler)
+	done(err)
+	return err
+}
+
+// RangeMoreGrayResources 实现 StreamStore
+func (r *readWriteStore) RangeMoreGrayResources(firstUpdate bool, mtime time.Time, batchSize int,
+	handler BatchHandler[*model.GrayResource]) error {
+	target, _, done := r.route("RangeMoreGrayResources", routeIncremental, nil)
+	err := NewStreamStore(target).RangeMoreGrayResources(firstUpdate, mtime, batchSize, handler)
+	done(err)
+	return err
+}
+
+// RangeRoutingConfigsForCache 实现 StreamStore
+func (r *readWriteStore) RangeRoutingConfigsForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler BatchHandler[*model.RoutingConfig]) error {
+	target, _, done := r.route("RangeRoutingConfigsForCache", routeIncremental, nil)
+	err := NewStreamStore(target).RangeRoutingConfigsForCache(mtime, firstUpdate, batchSize, handler)
+	done(err)
+	return err
+}
+
+// RangeRoutingConfigsV2ForCache 实现 StreamStore
+func (r *readWriteStore) RangeRoutingConfigsV2ForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler BatchHandler[*model.RouterConfig]) error {
+	target, _, done := r.route("RangeRoutingConfigsV2ForCache", routeIncremental, nil)
+	err := NewStreamStore(target).RangeRoutingConfigsV2ForCache(mtime, firstUpdate, batchSize, handler)
+	done(err)
+	return err
+}
+
+// RangeRateLimitsForCache 实现 StreamStore
+func (r *readWriteStore) RangeRateLimitsForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler BatchHandler[*model.RateLimit]) error {
+	target, _, done := r.route("RangeRateLimitsForCache", routeIncremental, nil)
+	err := NewStreamStore(target).RangeRateLimitsForCache(mtime, firstUpdate, batchSize, handler)
+	done(err)
+	return err
+}
+
+// RangeCircuitBreakerRulesForCache 实现 StreamStore
+func (r *readWriteStore) RangeCircuitBreakerRulesForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler BatchHandler[*model.CircuitBreakerRule]) error {
+	target, _, done := r.route("RangeCircuitBreakerRulesForCache", routeIncremental, nil)
+	err := NewStreamStore(target).RangeCircuitBreakerRulesForCache(mtime, firstUpdate, batchSize, handler)
+	done(err)
+	return err
+}
+
+// RangeFaultDetectRulesForCache 实现 StreamStore
+func (r *readWriteStore) RangeFaultDetectRulesForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler BatchHandler[*model.FaultDetectRule]) error {
+	target, _, done := r.route("RangeFaultDetectRulesForCache", routeIncremental, nil)
+	err := NewStreamStore(target).RangeFaultDetectRulesForCache(mtime, firstUpdate, batchSize, handler)
+	done(err)
+	return err
+}
+
+// RangeMoreConfigGroup 实现 StreamStore
+func (r *readWriteStore) RangeMoreConfigGroup(firstUpdate bool, mtime time.Time, batchSize int,
+	handler BatchHandler[*model.ConfigFileGroup]) error {
+	target, _, done := r.route("RangeMoreConfigGroup", routeIncremental, nil)
+	err := NewStreamStore(target).RangeMoreConfigGroup(firstUpdate, mtime, batchSize, handler)
+	done(err)
+	return err
+}
+
+// RangeMoreReleaseFile 实现 StreamStore
+func (r *readWriteStore) RangeMoreReleaseFile(firstUpdate bool, modifyTime time.Time, batchSize int,
+	handler BatchHandler[*model.ConfigFileRelease]) error {
+	target, _, done := r.route("RangeMoreReleaseFile", routeIncremental, nil)
+	err := NewStreamStore(target).RangeMoreReleaseFile(firstUpdate, modifyTime, batchSize, handler)
+	done(err)
+	return err
+}
+
+// RangeUsersForCache 实现 StreamStore
+func (r *readWriteStore) RangeUsersForCache(mtime time.Time, firstUpdate bool, batchSize int, handler BatchHandler[*model.User]) error {
+	target, _, done := r.route("RangeUsersForCache", routeIncremental, nil)
+	err := NewStreamStore(target).RangeUsersForCache(mtime, firstUpdate, batchSize, handler)
+	done(err)
+	return err
+}
+
+// RangeGroupsForCache 实现 StreamStore
+func (r *readWriteStore) RangeGroupsForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler BatchHandler[*model.UserGroup]) error {
+	target, _, done := r.route("RangeGroupsForCache", routeIncremental, nil)
+	err := NewStreamStore(target).RangeGroupsForCache(mtime, firstUpdate, batchSize, handler)
+	done(err)
+	return err
+}
+
+// RangeStrategyDetailsForCache 实现 StreamStore
+func (r *readWriteStore) RangeStrategyDetailsForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler BatchHandler[*model.StrategyDetail]) error {
+	target, _, done := r.route("RangeStrategyDetailsForCache", routeIncremental, nil)
+	err := NewStreamStore(target).RangeStrategyDetailsForCache(mtime, firstUpdate, batchSize, handler)
+	done(err)
+	return err
+}
+
+// Watch 实现 WatchableStore，变更需要实时，因此交给主库处理
+func (r *readWriteStore) Watch(ctx context.Context, kind ResourceKind, cursor Cursor) (Watcher, error) {
+	return Watch(ctx, r.primary, kind, cursor)
+}
@@ -0,0 +1,105 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package store_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/memory"
+)
+
+func TestReadWriteStoreRouting(t *testing.T) {
+	primary, replica := memory.New(), memory.New()
+	addNamespace(t, replica, "replica")
+	s := store.NewReadWriteStore(primary, []store.Store{replica})
+
+	if ns, _ := s.GetNamespace("replica"); ns == nil {
+		t.Fatal("read is not routed to the replica")
+	}
+	addNamespace(t, s, "primary")
+	if ns, _ := primary.GetNamespace("primary"); ns == nil {
+		t.Fatal("write is not routed to the primary")
+	}
+	if ns, _ := replica.GetNamespace("primary"); ns != nil {
+		t.Fatal("write is routed to the replica")
+	}
+}
+
+func TestReadWriteStoreLag(t *testing.T) {
+	primary, replica := memory.New(), memory.New()
+	addNamespace(t, primary, "primary")
+	addNamespace(t, replica, "replica")
+	lag := time.Duration(0)
+	s := store.NewReadWriteStore(primary, []store.Store{replica},
+		store.WithLagChecker(func(store.Store) (time.Duration, error) { return lag, nil }),
+		store.WithMaxReplicaLag(time.Minute), store.WithLagCheckInterval(time.Nanosecond))
+
+	names := func() []string {
+		items, err := s.GetMoreNamespaces(time.Time{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ret := make([]string, 0, len(items))
+		for _, ns := range items {
+			ret = append(ret, ns.Name)
+		}
+		return ret
+	}
+	if got := names(); len(got) != 1 || got[0] != "replica" {
+		t.Fatalf("expect the replica, got %v", got)
+	}
+	// 复制延迟超过上限时交给主库
+	lag = time.Hour
+	if got := names(); len(got) != 1 || got[0] != "primary" {
+		t.Fatalf("expect the primary, got %v", got)
+	}
+	// 延迟恢复到上限以内，但副本的数据早于上一次增量查询读取到的数据，仍然交给主库
+	lag = time.Second
+	if got := names(); len(got) != 1 || got[0] != "primary" {
+		t.Fatalf("incremental read went backwards: %v", got)
+	}
+	if ns, _ := s.GetNamespace("replica"); ns == nil {
+		t.Fatal("plain read is not routed to the replica")
+	}
+}
+
+func TestReadWriteStoreTx(t *testing.T) {
+	primary, replica := memory.New(), memory.New()
+	addNamespace(t, primary, "ns")
+	addService(t, primary, "svc", "svc", "ns")
+	addInstance(t, primary, "ins", "ns", "svc")
+	s := store.NewReadWriteStore(primary, []store.Store{replica})
+
+	tx, err := s.StartTx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	// 带有 Tx 参数的查询交给创建该 Tx 的主库处理
+	items, err := s.GetMoreInstances(tx, time.Time{}, true, false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items["ins"] == nil {
+		t.Fatalf("expect instances of the primary, got %v", items)
+	}
+}
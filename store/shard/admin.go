@@ -0,0 +1,81 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package shard
+
+import (
+	"context"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// BatchCleanDeletedInstances 依次在每个分片上清理软删除的实例，所有分片合计最多清理 batchSize 条
+func (s *shardedStore) BatchCleanDeletedInstances(timeout time.Duration, batchSize uint32) (uint32, error) {
+	var total uint32
+	for _, shard := range s.shards {
+		if total >= batchSize {
+			break
+		}
+		count, err := shard.BatchCleanDeletedInstances(timeout, batchSize-total)
+		total += count
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// GetUnHealthyInstances 合并所有分片上心跳超时的实例，最多返回 limit 条
+func (s *shardedStore) GetUnHealthyInstances(timeout time.Duration, limit uint32) ([]string, error) {
+	ids, err := concat(s.shards, func(shard store.Store) ([]string, error) {
+		return shard.GetUnHealthyInstances(timeout, limit)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if uint32(len(ids)) > limit {
+		ids = ids[:limit]
+	}
+	return ids, nil
+}
+
+// WatchLeaderElection 选主与命名空间无关，交给默认分片处理
+func (s *shardedStore) WatchLeaderElection(ctx context.Context, key string) (store.LeaderWatcher, error) {
+	return store.WatchLeaderElection(ctx, s.Store, key)
+}
+
+// AcquireLock 分布式锁与命名空间无关，交给默认分片处理
+func (s *shardedStore) AcquireLock(key string, owner string, ttl time.Duration) (*model.DistributedLock, error) {
+	return store.NewDistributedLockStore(s.Store).AcquireLock(key, owner, ttl)
+}
+
+// RenewLock 实现 store.DistributedLockStore
+func (s *shardedStore) RenewLock(key string, owner string, ttl time.Duration) (*model.DistributedLock, error) {
+	return store.NewDistributedLockStore(s.Store).RenewLock(key, owner, ttl)
+}
+
+// ReleaseLock 实现 store.DistributedLockStore
+func (s *shardedStore) ReleaseLock(key string, owner string) error {
+	return store.NewDistributedLockStore(s.Store).ReleaseLock(key, owner)
+}
+
+// ListLocks 实现 store.DistributedLockStore
+func (s *shardedStore) ListLocks() ([]*model.DistributedLock, error) {
+	return store.NewDistributedLockStore(s.Store).ListLocks()
+}
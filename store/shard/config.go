@@ -0,0 +1,283 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package shard
+
+import (
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// txShard 返回命名空间所在的分片，并将事务绑定到该分片
+func (s *shardedStore) txShard(tx store.Tx, namespace string) (store.Store, store.Tx, error) {
+	index := s.shardIndex(namespace)
+	tx, err := s.bindTx(tx, index)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.shards[index], tx, nil
+}
+
+// CreateConfigFileGroup 创建文件分组
+func (s *shardedStore) CreateConfigFileGroup(fileGroup *model.ConfigFileGroup) (*model.ConfigFileGroup, error) {
+	return s.shard(fileGroup.Namespace).CreateConfigFileGroup(fileGroup)
+}
+
+// UpdateConfigFileGroup 更新文件分组
+func (s *shardedStore) UpdateConfigFileGroup(fileGroup *model.ConfigFileGroup) error {
+	return s.shard(fileGroup.Namespace).UpdateConfigFileGroup(fileGroup)
+}
+
+// GetConfigFileGroup 获取单个配置文件组
+func (s *shardedStore) GetConfigFileGroup(namespace, name string) (*model.ConfigFileGroup, error) {
+	return s.shard(namespace).GetConfigFileGroup(namespace, name)
+}
+
+// DeleteConfigFileGroup 删除配置文件组
+func (s *shardedStore) DeleteConfigFileGroup(namespace, name string) error {
+	return s.shard(namespace).DeleteConfigFileGroup(namespace, name)
+}
+
+// GetMoreConfigGroup 获取配置分组
+func (s *shardedStore) GetMoreConfigGroup(firstUpdate bool, mtime time.Time) ([]*model.ConfigFileGroup, error) {
+	return concat(s.shards, func(shard store.Store) ([]*model.ConfigFileGroup, error) {
+		return shard.GetMoreConfigGroup(firstUpdate, mtime)
+	})
+}
+
+// CountConfigGroups 获取一个命名空间下的配置分组数量
+func (s *shardedStore) CountConfigGroups(namespace string) (uint64, error) {
+	return s.shard(namespace).CountConfigGroups(namespace)
+}
+
+// LockConfigFile 加锁配置文件
+func (s *shardedStore) LockConfigFile(tx store.Tx, file *model.ConfigFileKey) (*model.ConfigFile, error) {
+	shard, tx, err := s.txShard(tx, file.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	return shard.LockConfigFile(tx, file)
+}
+
+// CreateConfigFileTx 创建配置文件
+func (s *shardedStore) CreateConfigFileTx(tx store.Tx, file *model.ConfigFile) error {
+	shard, tx, err := s.txShard(tx, file.Namespace)
+	if err != nil {
+		return err
+	}
+	return shard.CreateConfigFileTx(tx, file)
+}
+
+// GetConfigFile 获取配置文件
+func (s *shardedStore) GetConfigFile(namespace, group, name string) (*model.ConfigFile, error) {
+	return s.shard(namespace).GetConfigFile(namespace, group, name)
+}
+
+// GetConfigFileTx 获取配置文件
+func (s *shardedStore) GetConfigFileTx(tx store.Tx, namespace, group, name string) (*model.ConfigFile, error) {
+	shard, tx, err := s.txShard(tx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return shard.GetConfigFileTx(tx, namespace, group, name)
+}
+
+// QueryConfigFiles 过滤条件指定了命名空间时只查询对应的分片，否则合并所有分片的结果
+func (s *shardedStore) QueryConfigFiles(filter map[string]string, offset uint32, limit uint32) (
+	uint32, []*model.ConfigFile, error) {
+	if shard, ok := s.filterShard(filter); ok {
+		return shard.QueryConfigFiles(filter, offset, limit)
+	}
+	return mergePage(s.shards, offset, limit, configFileOrder,
+		func(shard store.Store, limit uint32) (uint32, []*model.ConfigFile, error) {
+			return shard.QueryConfigFiles(filter, 0, limit)
+		})
+}
+
+// UpdateConfigFileTx 更新配置文件
+func (s *shardedStore) UpdateConfigFileTx(tx store.Tx, file *model.ConfigFile) error {
+	shard, tx, err := s.txShard(tx, file.Namespace)
+	if err != nil {
+		return err
+	}
+	return shard.UpdateConfigFileTx(tx, file)
+}
+
+// DeleteConfigFileTx 删除配置文件
+func (s *shardedStore) DeleteConfigFileTx(tx store.Tx, namespace, group, name string) error {
+	shard, tx, err := s.txShard(tx, namespace)
+	if err != nil {
+		return err
+	}
+	return shard.DeleteConfigFileTx(tx, namespace, group, name)
+}
+
+// CountConfigFiles 获取一个配置文件组下的文件数量
+func (s *shardedStore) CountConfigFiles(namespace, group string) (uint64, error) {
+	return s.shard(namespace).CountConfigFiles(namespace, group)
+}
+
+// CountConfigFileEachGroup 统计 namespace.group 下的配置文件数量
+func (s *shardedStore) CountConfigFileEachGroup() (map[string]map[string]int64, error) {
+	results, err := fanOut(s.shards, func(_ int, shard store.Store) (map[string]map[string]int64, error) {
+		return shard.CountConfigFileEachGroup()
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := make(map[string]map[string]int64)
+	for _, counts := range results {
+		for namespace, groups := range counts {
+			if _, ok := ret[namespace]; !ok {
+				ret[namespace] = make(map[string]int64, len(groups))
+			}
+			for group, count := range groups {
+				ret[namespace][group] += count
+			}
+		}
+	}
+	return ret, nil
+}
+
+// GetConfigFileActiveRelease 获取配置文件处于 Active 的配置发布记录
+func (s *shardedStore) GetConfigFileActiveRelease(file *model.ConfigFileKey) (*model.ConfigFileRelease, error) {
+	return s.shard(file.Namespace).GetConfigFileActiveRelease(file)
+}
+
+// GetConfigFileActiveReleaseTx 获取配置文件处于 Active 的配置发布记录
+func (s *shardedStore) GetConfigFileActiveReleaseTx(tx store.Tx,
+	file *model.ConfigFileKey) (*model.ConfigFileRelease, error) {
+	shard, tx, err := s.txShard(tx, file.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	return shard.GetConfigFileActiveReleaseTx(tx, file)
+}
+
+// CreateConfigFileReleaseTx 创建配置文件发布
+func (s *shardedStore) CreateConfigFileReleaseTx(tx store.Tx, fileRelease *model.ConfigFileRelease) error {
+	shard, tx, err := s.txShard(tx, fileRelease.Namespace)
+	if err != nil {
+		return err
+	}
+	return shard.CreateConfigFileReleaseTx(tx, fileRelease)
+}
+
+// GetConfigFileRelease 获取配置文件发布内容，只获取 flag=0 的记录
+func (s *shardedStore) GetConfigFileRelease(req *model.ConfigFileReleaseKey) (*model.ConfigFileRelease, error) {
+	return s.shard(req.Namespace).GetConfigFileRelease(req)
+}
+
+// GetConfigFileReleaseTx 在已开启的事务中获取配置文件发布内容，只获取 flag=0 的记录
+func (s *shardedStore) GetConfigFileReleaseTx(tx store.Tx,
+	req *model.ConfigFileReleaseKey) (*model.ConfigFileRelease, error) {
+	shard, tx, err := s.txShard(tx, req.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	return shard.GetConfigFileReleaseTx(tx, req)
+}
+
+// DeleteConfigFileReleaseTx 删除配置文件发布内容
+func (s *shardedStore) DeleteConfigFileReleaseTx(tx store.Tx, data *model.ConfigFileReleaseKey) error {
+	shard, tx, err := s.txShard(tx, data.Namespace)
+	if err != nil {
+		return err
+	}
+	return shard.DeleteConfigFileReleaseTx(tx, data)
+}
+
+// ActiveConfigFileReleaseTx 指定激活发布的配置文件
+func (s *shardedStore) ActiveConfigFileReleaseTx(tx store.Tx, release *model.ConfigFileRelease) error {
+	shard, tx, err := s.txShard(tx, release.Namespace)
+	if err != nil {
+		return err
+	}
+	return shard.ActiveConfigFileReleaseTx(tx, release)
+}
+
+// InactiveConfigFileReleaseTx 指定失效发布的配置文件
+func (s *shardedStore) InactiveConfigFileReleaseTx(tx store.Tx, release *model.ConfigFileRelease) error {
+	shard, tx, err := s.txShard(tx, release.Namespace)
+	if err != nil {
+		return err
+	}
+	return shard.InactiveConfigFileReleaseTx(tx, release)
+}
+
+// CleanConfigFileReleasesTx 清空配置文件发布
+func (s *shardedStore) CleanConfigFileReleasesTx(tx store.Tx, namespace, group, fileName string) error {
+	shard, tx, err := s.txShard(tx, namespace)
+	if err != nil {
+		return err
+	}
+	return shard.CleanConfigFileReleasesTx(tx, namespace, group, fileName)
+}
+
+// GetMoreReleaseFile 获取最近更新的配置文件发布
+func (s *shardedStore) GetMoreReleaseFile(firstUpdate bool, modifyTime time.Time) (
+	[]*model.ConfigFileRelease, error) {
+	return concat(s.shards, func(shard store.Store) ([]*model.ConfigFileRelease, error) {
+		return shard.GetMoreReleaseFile(firstUpdate, modifyTime)
+	})
+}
+
+// CountConfigReleases 获取一个配置文件组下的文件数量
+func (s *shardedStore) CountConfigReleases(namespace, group string, onlyActive bool) (uint64, error) {
+	return s.shard(namespace).CountConfigReleases(namespace, group, onlyActive)
+}
+
+// GetConfigFileBetaReleaseTx 获取灰度发布的配置文件信息
+func (s *shardedStore) GetConfigFileBetaReleaseTx(tx store.Tx,
+	file *model.ConfigFileKey) (*model.ConfigFileRelease, error) {
+	shard, tx, err := s.txShard(tx, file.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	return shard.GetConfigFileBetaReleaseTx(tx, file)
+}
+
+// CreateConfigFileReleaseHistory 创建配置文件发布历史记录
+func (s *shardedStore) CreateConfigFileReleaseHistory(history *model.ConfigFileReleaseHistory) error {
+	return s.shard(history.Namespace).CreateConfigFileReleaseHistory(history)
+}
+
+// QueryConfigFileReleaseHistories 过滤条件指定了命名空间时只查询对应的分片，
+// 否则合并所有分片的结果并按照修改时间倒序排列
+func (s *shardedStore) QueryConfigFileReleaseHistories(filter map[string]string, offset, limit uint32) (
+	uint32, []*model.ConfigFileReleaseHistory, error) {
+	if shard, ok := s.filterShard(filter); ok {
+		return shard.QueryConfigFileReleaseHistories(filter, offset, limit)
+	}
+	return mergePage(s.shards, offset, limit, func(a, b *model.ConfigFileReleaseHistory) bool {
+		if !a.ModifyTime.Equal(b.ModifyTime) {
+			return a.ModifyTime.After(b.ModifyTime)
+		}
+		return a.Id > b.Id
+	}, func(shard store.Store, limit uint32) (uint32, []*model.ConfigFileReleaseHistory, error) {
+		return shard.QueryConfigFileReleaseHistories(filter, 0, limit)
+	})
+}
+
+// CleanConfigFileReleaseHistory 在所有分片上清理配置发布历史
+func (s *shardedStore) CleanConfigFileReleaseHistory(endTime time.Time, limit uint64) error {
+	return fanOutEach(s.shards, func(shard store.Store) error {
+		return shard.CleanConfigFileReleaseHistory(endTime, limit)
+	})
+}
@@ -0,0 +1,192 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package shard
+
+import (
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// contractIndex 返回服务契约所在的分片，契约没有携带命名空间时根据ID在所有分片上查找，
+// 找不到时交给事务已经绑定的分片或者默认分片处理
+func (s *shardedStore) contractIndex(tx store.Tx, contract *model.ServiceContract) (int, error) {
+	if contract == nil {
+		return txIndex(tx), nil
+	}
+	if contract.Namespace != "" {
+		return s.shardIndex(contract.Namespace), nil
+	}
+	found, err := fanOut(s.shards, func(_ int, shard store.Store) (bool, error) {
+		saved, err := shard.GetServiceContract(contract.ID)
+		return saved != nil, err
+	})
+	if err != nil {
+		return 0, err
+	}
+	for i := range found {
+		if found[i] {
+			return i, nil
+		}
+	}
+	return txIndex(tx), nil
+}
+
+// contractShard 返回服务契约所在的分片
+func (s *shardedStore) contractShard(contract *model.ServiceContract) (store.Store, error) {
+	index, err := s.contractIndex(nil, contract)
+	if err != nil {
+		return nil, err
+	}
+	return s.shards[index], nil
+}
+
+// contractTx 将事务绑定到服务契约所在的分片
+func (s *shardedStore) contractTx(tx store.Tx, contract *model.ServiceContract) (store.NamingTxStore, store.Tx, error) {
+	index, err := s.contractIndex(tx, contract)
+	if err != nil {
+		return nil, nil, err
+	}
+	if tx, err = s.bindTx(tx, index); err != nil {
+		return nil, nil, err
+	}
+	return store.NewNamingTxStore(s.shards[index]), tx, nil
+}
+
+// CreateServiceContract 在契约所属命名空间的分片上创建服务契约
+func (s *shardedStore) CreateServiceContract(contract *model.ServiceContract) error {
+	shard, err := s.contractShard(contract)
+	if err != nil {
+		return err
+	}
+	return shard.CreateServiceContract(contract)
+}
+
+// UpdateServiceContract 在契约所在的分片上更新服务契约
+func (s *shardedStore) UpdateServiceContract(contract *model.ServiceContract) error {
+	shard, err := s.contractShard(contract)
+	if err != nil {
+		return err
+	}
+	return shard.UpdateServiceContract(contract)
+}
+
+// DeleteServiceContract 在契约所在的分片上删除服务契约
+func (s *shardedStore) DeleteServiceContract(contract *model.ServiceContract) error {
+	shard, err := s.contractShard(contract)
+	if err != nil {
+		return err
+	}
+	return shard.DeleteServiceContract(contract)
+}
+
+// GetMoreServiceContracts 合并所有分片的增量服务契约
+func (s *shardedStore) GetMoreServiceContracts(firstUpdate bool, mtime time.Time) ([]*model.ServiceContract, error) {
+	return concat(s.shards, func(shard store.Store) ([]*model.ServiceContract, error) {
+		return shard.GetMoreServiceContracts(firstUpdate, mtime)
+	})
+}
+
+// GetServiceContract 在所有分片上查询服务契约
+func (s *shardedStore) GetServiceContract(id string) (*model.ServiceContract, error) {
+	return first(s.shards, func(shard store.Store) (*model.ServiceContract, error) {
+		return shard.GetServiceContract(id)
+	})
+}
+
+// AddServiceContractInterfaces 在契约所在的分片上创建服务契约API接口
+func (s *shardedStore) AddServiceContractInterfaces(contract *model.ServiceContract) error {
+	shard, err := s.contractShard(contract)
+	if err != nil {
+		return err
+	}
+	return shard.AddServiceContractInterfaces(contract)
+}
+
+// AppendServiceContractInterfaces 在契约所在的分片上追加服务契约API接口
+func (s *shardedStore) AppendServiceContractInterfaces(contract *model.ServiceContract) error {
+	shard, err := s.contractShard(contract)
+	if err != nil {
+		return err
+	}
+	return shard.AppendServiceContractInterfaces(contract)
+}
+
+// DeleteServiceContractInterfaces 在契约所在的分片上批量删除服务契约API接口
+func (s *shardedStore) DeleteServiceContractInterfaces(contract *model.ServiceContract) error {
+	shard, err := s.contractShard(contract)
+	if err != nil {
+		return err
+	}
+	return shard.DeleteServiceContractInterfaces(contract)
+}
+
+// CreateServiceContractTx 在契约所属命名空间的分片上执行
+func (s *shardedStore) CreateServiceContractTx(tx store.Tx, contract *model.ServiceContract) error {
+	target, tx, err := s.contractTx(tx, contract)
+	if err != nil {
+		return err
+	}
+	return target.CreateServiceContractTx(tx, contract)
+}
+
+// UpdateServiceContractTx 在契约所在的分片上执行
+func (s *shardedStore) UpdateServiceContractTx(tx store.Tx, contract *model.ServiceContract) error {
+	target, tx, err := s.contractTx(tx, contract)
+	if err != nil {
+		return err
+	}
+	return target.UpdateServiceContractTx(tx, contract)
+}
+
+// DeleteServiceContractTx 在契约所在的分片上执行
+func (s *shardedStore) DeleteServiceContractTx(tx store.Tx, contract *model.ServiceContract) error {
+	target, tx, err := s.contractTx(tx, contract)
+	if err != nil {
+		return err
+	}
+	return target.DeleteServiceContractTx(tx, contract)
+}
+
+// AddServiceContractInterfacesTx 在契约所在的分片上执行
+func (s *shardedStore) AddServiceContractInterfacesTx(tx store.Tx, contract *model.ServiceContract) error {
+	target, tx, err := s.contractTx(tx, contract)
+	if err != nil {
+		return err
+	}
+	return target.AddServiceContractInterfacesTx(tx, contract)
+}
+
+// AppendServiceContractInterfacesTx 在契约所在的分片上执行
+func (s *shardedStore) AppendServiceContractInterfacesTx(tx store.Tx, contract *model.ServiceContract) error {
+	target, tx, err := s.contractTx(tx, contract)
+	if err != nil {
+		return err
+	}
+	return target.AppendServiceContractInterfacesTx(tx, contract)
+}
+
+// DeleteServiceContractInterfacesTx 在契约所在的分片上执行
+func (s *shardedStore) DeleteServiceContractInterfacesTx(tx store.Tx, contract *model.ServiceContract) error {
+	target, tx, err := s.contractTx(tx, contract)
+	if err != nil {
+		return err
+	}
+	return target.DeleteServiceContractInterfacesTx(tx, contract)
+}
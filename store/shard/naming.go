@@ -0,0 +1,602 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package shard
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// AddNamespace Save a namespace
+func (s *shardedStore) AddNamespace(namespace *model.Namespace) error {
+	return s.shard(namespace.Name).AddNamespace(namespace)
+}
+
+// UpdateNamespace Update namespace
+func (s *shardedStore) UpdateNamespace(namespace *model.Namespace) error {
+	return s.shard(namespace.Name).UpdateNamespace(namespace)
+}
+
+// UpdateNamespaceToken Update namespace token
+func (s *shardedStore) UpdateNamespaceToken(name string, token string) error {
+	return s.shard(name).UpdateNamespaceToken(name, token)
+}
+
+// GetNamespace Get namespace details
+func (s *shardedStore) GetNamespace(name string) (*model.Namespace, error) {
+	return s.shard(name).GetNamespace(name)
+}
+
+// GetNamespaces 合并所有分片的命名空间，按照名字排序之后分页
+func (s *shardedStore) GetNamespaces(filter map[string][]string, offset, limit int) (
+	[]*model.Namespace, uint32, error) {
+	if offset < 0 {
+		offset = 0
+	}
+	if limit < 0 {
+		limit = 0
+	}
+	total, items, err := mergePage(s.shards, uint32(offset), uint32(limit),
+		func(a, b *model.Namespace) bool { return a.Name < b.Name },
+		func(shard store.Store, limit uint32) (uint32, []*model.Namespace, error) {
+			items, total, err := shard.GetNamespaces(filter, 0, int(limit))
+			return total, items, err
+		})
+	return items, total, err
+}
+
+// GetMoreNamespaces Get incremental data
+func (s *shardedStore) GetMoreNamespaces(mtime time.Time) ([]*model.Namespace, error) {
+	return concat(s.shards, func(shard store.Store) ([]*model.Namespace, error) {
+		return shard.GetMoreNamespaces(mtime)
+	})
+}
+
+// AddService 保存一个服务
+func (s *shardedStore) AddService(service *model.Service) error {
+	return s.shard(service.Namespace).AddService(service)
+}
+
+// AddServiceTx 在事务中保存一个服务
+func (s *shardedStore) AddServiceTx(tx store.Tx, service *model.Service) error {
+	target, tx, err := s.txShard(tx, service.Namespace)
+	if err != nil {
+		return err
+	}
+	return store.NewNamingTxStore(target).AddServiceTx(tx, service)
+}
+
+// DeleteService 删除服务
+func (s *shardedStore) DeleteService(id, serviceName, namespaceName string) error {
+	return s.shard(namespaceName).DeleteService(id, serviceName, namespaceName)
+}
+
+// DeleteServiceTx 在事务中删除服务
+func (s *shardedStore) DeleteServiceTx(tx store.Tx, id, serviceName, namespaceName string) error {
+	target, tx, err := s.txShard(tx, namespaceName)
+	if err != nil {
+		return err
+	}
+	return store.NewNamingTxStore(target).DeleteServiceTx(tx, id, serviceName, namespaceName)
+}
+
+// DeleteServiceAlias 删除服务别名
+func (s *shardedStore) DeleteServiceAlias(name string, namespace string) error {
+	return s.shard(namespace).DeleteServiceAlias(name, namespace)
+}
+
+// DeleteServiceAliasTx 在事务中删除服务别名
+func (s *shardedStore) DeleteServiceAliasTx(tx store.Tx, name string, namespace string) error {
+	target, tx, err := s.txShard(tx, namespace)
+	if err != nil {
+		return err
+	}
+	return store.NewNamingTxStore(target).DeleteServiceAliasTx(tx, name, namespace)
+}
+
+// UpdateServiceAlias 修改服务别名
+func (s *shardedStore) UpdateServiceAlias(alias *model.Service, needUpdateOwner bool) error {
+	return s.shard(alias.Namespace).UpdateServiceAlias(alias, needUpdateOwner)
+}
+
+// UpdateServiceAliasTx 在事务中修改服务别名
+func (s *shardedStore) UpdateServiceAliasTx(tx store.Tx, alias *model.Service, needUpdateOwner bool) error {
+	target, tx, err := s.txShard(tx, alias.Namespace)
+	if err != nil {
+		return err
+	}
+	return store.NewNamingTxStore(target).UpdateServiceAliasTx(tx, alias, needUpdateOwner)
+}
+
+// UpdateService 更新服务
+func (s *shardedStore) UpdateService(service *model.Service, needUpdateOwner bool) error {
+	return s.shard(service.Namespace).UpdateService(service, needUpdateOwner)
+}
+
+// UpdateServiceTx 在事务中更新服务
+func (s *shardedStore) UpdateServiceTx(tx store.Tx, service *model.Service, needUpdateOwner bool) error {
+	target, tx, err := s.txShard(tx, service.Namespace)
+	if err != nil {
+		return err
+	}
+	return store.NewNamingTxStore(target).UpdateServiceTx(tx, service, needUpdateOwner)
+}
+
+// UpdateServiceToken 先找到服务所在的分片再更新，服务不存在时交给默认分片处理
+func (s *shardedStore) UpdateServiceToken(serviceID string, token string, revision string) error {
+	target := s.shards[0]
+	svc, err := s.GetServiceByID(serviceID)
+	if err != nil {
+		return err
+	}
+	if svc != nil {
+		target = s.shard(svc.Namespace)
+	}
+	return target.UpdateServiceToken(serviceID, token, revision)
+}
+
+// UpdateServiceTokenTx 先找到服务所在的分片再更新，服务不存在时交给事务已经绑定的分片或者默认分片处理
+func (s *shardedStore) UpdateServiceTokenTx(tx store.Tx, serviceID string, token string, revision string) error {
+	index := txIndex(tx)
+	svc, err := s.GetServiceByID(serviceID)
+	if err != nil {
+		return err
+	}
+	if svc != nil {
+		index = s.shardIndex(svc.Namespace)
+	}
+	if tx, err = s.bindTx(tx, index); err != nil {
+		return err
+	}
+	return store.NewNamingTxStore(s.shards[index]).UpdateServiceTokenTx(tx, serviceID, token, revision)
+}
+
+// GetSourceServiceToken 获取源服务的token信息
+func (s *shardedStore) GetSourceServiceToken(name string, namespace string) (*model.Service, error) {
+	return s.shard(namespace).GetSourceServiceToken(name, namespace)
+}
+
+// GetService 根据服务名和命名空间获取服务的详情
+func (s *shardedStore) GetService(name string, namespace string) (*model.Service, error) {
+	return s.shard(namespace).GetService(name, namespace)
+}
+
+// GetServiceByID 在所有分片上根据服务ID查询服务详情
+func (s *shardedStore) GetServiceByID(id string) (*model.Service, error) {
+	return first(s.shards, func(shard store.Store) (*model.Service, error) {
+		return shard.GetServiceByID(id)
+	})
+}
+
+// GetServices 过滤条件指定了命名空间时只查询对应的分片，否则合并所有分片的结果
+func (s *shardedStore) GetServices(serviceFilters, serviceMetas map[string]string,
+	instanceFilters *model.InstanceArgs, offset, limit uint32) (uint32, []*model.Service, error) {
+	if shard, ok := s.filterShard(serviceFilters); ok {
+		return shard.GetServices(serviceFilters, serviceMetas, instanceFilters, offset, limit)
+	}
+	return mergePage(s.shards, offset, limit, serviceOrder,
+		func(shard store.Store, limit uint32) (uint32, []*model.Service, error) {
+			return shard.GetServices(serviceFilters, serviceMetas, instanceFilters, 0, limit)
+		})
+}
+
+// GetServicesCount 获取所有服务总数
+func (s *shardedStore) GetServicesCount() (uint32, error) {
+	return sum(s.shards, func(shard store.Store) (uint32, error) {
+		return shard.GetServicesCount()
+	})
+}
+
+// GetMoreServices 获取增量services
+func (s *shardedStore) GetMoreServices(mtime time.Time, firstUpdate, disableBusiness, needMeta bool) (
+	map[string]*model.Service, error) {
+	return union(s.shards, func(shard store.Store) (map[string]*model.Service, error) {
+		return shard.GetMoreServices(mtime, firstUpdate, disableBusiness, needMeta)
+	})
+}
+
+// GetServiceAliases 获取服务别名列表
+func (s *shardedStore) GetServiceAliases(filter map[string]string, offset uint32, limit uint32) (
+	uint32, []*model.ServiceAlias, error) {
+	return mergePage(s.shards, offset, limit, aliasOrder,
+		func(shard store.Store, limit uint32) (uint32, []*model.ServiceAlias, error) {
+			return shard.GetServiceAliases(filter, 0, limit)
+		})
+}
+
+// GetSystemServices 获取系统服务
+func (s *shardedStore) GetSystemServices() ([]*model.Service, error) {
+	return concat(s.shards, func(shard store.Store) ([]*model.Service, error) {
+		return shard.GetSystemServices()
+	})
+}
+
+// GetServicesBatch 按照命名空间分组之后在对应的分片上批量获取服务
+func (s *shardedStore) GetServicesBatch(services []*model.Service) ([]*model.Service, error) {
+	groups := make(map[int][]*model.Service)
+	for _, svc := range services {
+		index := s.shardIndex(svc.Namespace)
+		groups[index] = append(groups[index], svc)
+	}
+	results, err := fanOut(s.shards, func(index int, shard store.Store) ([]*model.Service, error) {
+		if len(groups[index]) == 0 {
+			return nil, nil
+		}
+		return shard.GetServicesBatch(groups[index])
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*model.Service, 0, len(services))
+	for _, items := range results {
+		ret = append(ret, items...)
+	}
+	return ret, nil
+}
+
+// AddInstance 增加一个实例
+func (s *shardedStore) AddInstance(instance *model.Instance) error {
+	return s.shard(instance.Proto.GetNamespace().GetValue()).AddInstance(instance)
+}
+
+// AddInstanceTx 在事务中增加一个实例
+func (s *shardedStore) AddInstanceTx(tx store.Tx, instance *model.Instance) error {
+	target, tx, err := s.txShard(tx, instance.Proto.GetNamespace().GetValue())
+	if err != nil {
+		return err
+	}
+	return store.NewNamingTxStore(target).AddInstanceTx(tx, instance)
+}
+
+// BatchAddInstances 按照命名空间分组之后在对应的分片上批量增加实例，不保证跨分片的原子性
+func (s *shardedStore) BatchAddInstances(instances []*model.Instance) error {
+	groups := make(map[int][]*model.Instance)
+	for _, ins := range instances {
+		index := s.shardIndex(ins.Proto.GetNamespace().GetValue())
+		groups[index] = append(groups[index], ins)
+	}
+	for index, items := range groups {
+		if err := s.shards[index].BatchAddInstances(items); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BatchAddInstancesTx 在事务中增加多个实例，事务只能绑定一个分片，所有实例需要属于同一个分片，
+// tx 为空时与 BatchAddInstances 一致
+func (s *shardedStore) BatchAddInstancesTx(tx store.Tx, instances []*model.Instance) error {
+	if tx == nil {
+		return s.BatchAddInstances(instances)
+	}
+	if len(instances) == 0 {
+		return nil
+	}
+	index := s.shardIndex(instances[0].Proto.GetNamespace().GetValue())
+	for _, ins := range instances[1:] {
+		if s.shardIndex(ins.Proto.GetNamespace().GetValue()) != index {
+			return errCrossShard
+		}
+	}
+	tx, err := s.bindTx(tx, index)
+	if err != nil {
+		return err
+	}
+	return store.NewNamingTxStore(s.shards[index]).BatchAddInstancesTx(tx, instances)
+}
+
+// UpdateInstance 更新实例
+func (s *shardedStore) UpdateInstance(instance *model.Instance) error {
+	return s.shard(instance.Proto.GetNamespace().GetValue()).UpdateInstance(instance)
+}
+
+// UpdateInstanceTx 在事务中更新实例
+func (s *shardedStore) UpdateInstanceTx(tx store.Tx, instance *model.Instance) error {
+	target, tx, err := s.txShard(tx, instance.Proto.GetNamespace().GetValue())
+	if err != nil {
+		return err
+	}
+	return store.NewNamingTxStore(target).UpdateInstanceTx(tx, instance)
+}
+
+// DeleteInstance 在所有分片上删除该实例
+func (s *shardedStore) DeleteInstance(instanceID string) error {
+	return fanOutEach(s.shards, func(shard store.Store) error {
+		return shard.DeleteInstance(instanceID)
+	})
+}
+
+// DeleteInstanceTx 在实例所在的分片上删除一个实例
+func (s *shardedStore) DeleteInstanceTx(tx store.Tx, instanceID string) error {
+	if tx == nil {
+		return s.DeleteInstance(instanceID)
+	}
+	target, tx, err := s.instanceTxShard(tx, []string{instanceID})
+	if err != nil {
+		return err
+	}
+	return store.NewNamingTxStore(target).DeleteInstanceTx(tx, instanceID)
+}
+
+// BatchDeleteInstances 在所有分片上批量删除实例
+func (s *shardedStore) BatchDeleteInstances(ids []interface{}) error {
+	return fanOutEach(s.shards, func(shard store.Store) error {
+		return shard.BatchDeleteInstances(ids)
+	})
+}
+
+// BatchDeleteInstancesTx 在实例所在的分片上批量删除实例
+func (s *shardedStore) BatchDeleteInstancesTx(tx store.Tx, ids []interface{}) error {
+	if tx == nil {
+		return s.BatchDeleteInstances(ids)
+	}
+	target, tx, err := s.instanceTxShard(tx, stringIDs(ids))
+	if err != nil {
+		return err
+	}
+	return store.NewNamingTxStore(target).BatchDeleteInstancesTx(tx, ids)
+}
+
+// CleanInstance 在所有分片上清空该实例的数据
+func (s *shardedStore) CleanInstance(instanceID string) error {
+	return fanOutEach(s.shards, func(shard store.Store) error {
+		return shard.CleanInstance(instanceID)
+	})
+}
+
+// CleanInstanceTx 在实例所在的分片上清空一个实例
+func (s *shardedStore) CleanInstanceTx(tx store.Tx, instanceID string) error {
+	if tx == nil {
+		return s.CleanInstance(instanceID)
+	}
+	target, tx, err := s.instanceTxShard(tx, []string{instanceID})
+	if err != nil {
+		return err
+	}
+	return store.NewNamingTxStore(target).CleanInstanceTx(tx, instanceID)
+}
+
+// BatchGetInstanceIsolate 检查ID是否存在，并且返回存在的ID，以及ID的隔离状态
+func (s *shardedStore) BatchGetInstanceIsolate(ids map[string]bool) (map[string]bool, error) {
+	return union(s.shards, func(shard store.Store) (map[string]bool, error) {
+		return shard.BatchGetInstanceIsolate(ids)
+	})
+}
+
+// GetInstancesBrief 获取实例关联的token
+func (s *shardedStore) GetInstancesBrief(ids map[string]bool) (map[string]*model.Instance, error) {
+	return union(s.shards, func(shard store.Store) (map[string]*model.Instance, error) {
+		return shard.GetInstancesBrief(ids)
+	})
+}
+
+// GetInstance 在所有分片上查询实例详情
+func (s *shardedStore) GetInstance(instanceID string) (*model.Instance, error) {
+	return first(s.shards, func(shard store.Store) (*model.Instance, error) {
+		return shard.GetInstance(instanceID)
+	})
+}
+
+// GetInstancesCount 获取有效的实例总数
+func (s *shardedStore) GetInstancesCount() (uint32, error) {
+	return sum(s.shards, func(shard store.Store) (uint32, error) {
+		return shard.GetInstancesCount()
+	})
+}
+
+// GetInstancesCountTx 获取有效的实例总数，事务已经绑定的分片在事务中查询
+func (s *shardedStore) GetInstancesCountTx(tx store.Tx) (uint32, error) {
+	bound, delegate := boundTx(tx)
+	results, err := fanOut(s.shards, func(index int, shard store.Store) (uint32, error) {
+		if index == bound {
+			return shard.GetInstancesCountTx(delegate)
+		}
+		return shard.GetInstancesCount()
+	})
+	var total uint32
+	for _, count := range results {
+		total += count
+	}
+	return total, err
+}
+
+// GetInstancesMainByService 根据服务和Host获取实例（不包括metadata）
+func (s *shardedStore) GetInstancesMainByService(serviceID, host string) ([]*model.Instance, error) {
+	return concat(s.shards, func(shard store.Store) ([]*model.Instance, error) {
+		return shard.GetInstancesMainByService(serviceID, host)
+	})
+}
+
+// GetExpandInstances 过滤条件指定了命名空间时只查询对应的分片，否则合并所有分片的结果
+func (s *shardedStore) GetExpandInstances(filter, metaFilter map[string]string, offset uint32, limit uint32) (
+	uint32, []*model.Instance, error) {
+	if shard, ok := s.filterShard(filter); ok {
+		return shard.GetExpandInstances(filter, metaFilter, offset, limit)
+	}
+	return mergePage(s.shards, offset, limit, instanceOrder,
+		func(shard store.Store, limit uint32) (uint32, []*model.Instance, error) {
+			return shard.GetExpandInstances(filter, metaFilter, 0, limit)
+		})
+}
+
+// GetMoreInstances 根据mtime获取增量instances，事务已经绑定的分片在事务中查询
+func (s *shardedStore) GetMoreInstances(tx store.Tx, mtime time.Time, firstUpdate, needMeta bool,
+	serviceID []string) (map[string]*model.Instance, error) {
+	bound, delegate := boundTx(tx)
+	results, err := fanOut(s.shards, func(index int, shard store.Store) (map[string]*model.Instance, error) {
+		if index == bound {
+			return shard.GetMoreInstances(delegate, mtime, firstUpdate, needMeta, serviceID)
+		}
+		return shard.GetMoreInstances(nil, mtime, firstUpdate, needMeta, serviceID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := make(map[string]*model.Instance)
+	for _, items := range results {
+		for id, ins := range items {
+			ret[id] = ins
+		}
+	}
+	return ret, nil
+}
+
+// SetInstanceHealthStatus 设置实例的健康状态
+func (s *shardedStore) SetInstanceHealthStatus(instanceID string, flag int, revision string) error {
+	return fanOutEach(s.shards, func(shard store.Store) error {
+		return shard.SetInstanceHealthStatus(instanceID, flag, revision)
+	})
+}
+
+// SetInstanceHealthStatusTx 在实例所在的分片上设置实例的健康状态
+func (s *shardedStore) SetInstanceHealthStatusTx(tx store.Tx, instanceID string, flag int, revision string) error {
+	if tx == nil {
+		return s.SetInstanceHealthStatus(instanceID, flag, revision)
+	}
+	target, tx, err := s.instanceTxShard(tx, []string{instanceID})
+	if err != nil {
+		return err
+	}
+	return store.NewNamingTxStore(target).SetInstanceHealthStatusTx(tx, instanceID, flag, revision)
+}
+
+// BatchSetInstanceHealthStatus 批量设置实例的健康状态
+func (s *shardedStore) BatchSetInstanceHealthStatus(ids []interface{}, healthy int, revision string) error {
+	return fanOutEach(s.shards, func(shard store.Store) error {
+		return shard.BatchSetInstanceHealthStatus(ids, healthy, revision)
+	})
+}
+
+// BatchSetInstanceHealthStatusTx 在实例所在的分片上批量设置实例的健康状态
+func (s *shardedStore) BatchSetInstanceHealthStatusTx(tx store.Tx, ids []interface{}, healthy int, revision string) error {
+	if tx == nil {
+		return s.BatchSetInstanceHealthStatus(ids, healthy, revision)
+	}
+	target, tx, err := s.instanceTxShard(tx, stringIDs(ids))
+	if err != nil {
+		return err
+	}
+	return store.NewNamingTxStore(target).BatchSetInstanceHealthStatusTx(tx, ids, healthy, revision)
+}
+
+// BatchSetInstanceIsolate 批量修改实例的隔离状态
+func (s *shardedStore) BatchSetInstanceIsolate(ids []interface{}, isolate int, revision string) error {
+	return fanOutEach(s.shards, func(shard store.Store) error {
+		return shard.BatchSetInstanceIsolate(ids, isolate, revision)
+	})
+}
+
+// BatchSetInstanceIsolateTx 在实例所在的分片上批量修改实例的隔离状态
+func (s *shardedStore) BatchSetInstanceIsolateTx(tx store.Tx, ids []interface{}, isolate int, revision string) error {
+	if tx == nil {
+		return s.BatchSetInstanceIsolate(ids, isolate, revision)
+	}
+	target, tx, err := s.instanceTxShard(tx, stringIDs(ids))
+	if err != nil {
+		return err
+	}
+	return store.NewNamingTxStore(target).BatchSetInstanceIsolateTx(tx, ids, isolate, revision)
+}
+
+// BatchAppendInstanceMetadata 追加实例 metadata
+func (s *shardedStore) BatchAppendInstanceMetadata(requests []*model.InstanceMetadataRequest) error {
+	return fanOutEach(s.shards, func(shard store.Store) error {
+		return shard.BatchAppendInstanceMetadata(requests)
+	})
+}
+
+// BatchAppendInstanceMetadataTx 在实例所在的分片上追加实例 metadata
+func (s *shardedStore) BatchAppendInstanceMetadataTx(tx store.Tx, requests []*model.InstanceMetadataRequest) error {
+	if tx == nil {
+		return s.BatchAppendInstanceMetadata(requests)
+	}
+	target, tx, err := s.instanceTxShard(tx, metadataInstanceIDs(requests))
+	if err != nil {
+		return err
+	}
+	return store.NewNamingTxStore(target).BatchAppendInstanceMetadataTx(tx, requests)
+}
+
+// BatchRemoveInstanceMetadata 删除实例指定的 metadata
+func (s *shardedStore) BatchRemoveInstanceMetadata(requests []*model.InstanceMetadataRequest) error {
+	return fanOutEach(s.shards, func(shard store.Store) error {
+		return shard.BatchRemoveInstanceMetadata(requests)
+	})
+}
+
+// BatchRemoveInstanceMetadataTx 在实例所在的分片上删除实例指定的 metadata
+func (s *shardedStore) BatchRemoveInstanceMetadataTx(tx store.Tx, requests []*model.InstanceMetadataRequest) error {
+	if tx == nil {
+		return s.BatchRemoveInstanceMetadata(requests)
+	}
+	target, tx, err := s.instanceTxShard(tx, metadataInstanceIDs(requests))
+	if err != nil {
+		return err
+	}
+	return store.NewNamingTxStore(target).BatchRemoveInstanceMetadataTx(tx, requests)
+}
+
+// instanceTxShard 找到实例所在的分片并绑定事务，事务只能绑定一个分片，实例分布在多个分片上时返回错误，
+// tx 为空时调用方直接在所有分片上执行。
+// 只能找到有效的实例，实例都不存在时交给事务已经绑定的分片或者默认分片处理
+func (s *shardedStore) instanceTxShard(tx store.Tx, ids []string) (store.Store, store.Tx, error) {
+	query := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		query[id] = true
+	}
+	found, err := fanOut(s.shards, func(_ int, shard store.Store) (bool, error) {
+		exists, err := shard.BatchGetInstanceIsolate(query)
+		return len(exists) > 0, err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	index, located := txIndex(tx), false
+	for i := range found {
+		if !found[i] {
+			continue
+		}
+		if located && i != index {
+			return nil, nil, errCrossShard
+		}
+		index, located = i, true
+	}
+	if tx, err = s.bindTx(tx, index); err != nil {
+		return nil, nil, err
+	}
+	return s.shards[index], tx, nil
+}
+
+func stringIDs(ids []interface{}) []string {
+	ret := make([]string, 0, len(ids))
+	for _, id := range ids {
+		ret = append(ret, fmt.Sprint(id))
+	}
+	return ret
+}
+
+func metadataInstanceIDs(requests []*model.InstanceMetadataRequest) []string {
+	ret := make([]string, 0, len(requests))
+	for _, req := range requests {
+		ret = append(ret, req.InstanceID)
+	}
+	return ret
+}
@@ -0,0 +1,342 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package shard
+
+import (
+	"context"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// UpdateServiceCAS 在服务所在的分片上条件更新服务
+func (s *shardedStore) UpdateServiceCAS(service *model.Service, needUpdateOwner bool, expectRevision string) error {
+	return store.NewRevisionStore(s.shard(service.Namespace)).UpdateServiceCAS(service, needUpdateOwner, expectRevision)
+}
+
+// UpdateRoutingConfigCAS 规则保存在默认分片上
+func (s *shardedStore) UpdateRoutingConfigCAS(conf *model.RoutingConfig, expectRevision string) error {
+	return store.NewRevisionStore(s.Store).UpdateRoutingConfigCAS(conf, expectRevision)
+}
+
+// UpdateRoutingConfigV2CAS 规则保存在默认分片上
+func (s *shardedStore) UpdateRoutingConfigV2CAS(conf *model.RouterConfig, expectRevision string) error {
+	return store.NewRevisionStore(s.Store).UpdateRoutingConfigV2CAS(conf, expectRevision)
+}
+
+// UpdateRateLimitCAS 规则保存在默认分片上
+func (s *shardedStore) UpdateRateLimitCAS(limit *model.RateLimit, expectRevision string) error {
+	return store.NewRevisionStore(s.Store).UpdateRateLimitCAS(limit, expectRevision)
+}
+
+// UpdateCircuitBreakerRuleCAS 规则保存在默认分片上
+func (s *shardedStore) UpdateCircuitBreakerRuleCAS(cbRule *model.CircuitBreakerRule, expectRevision string) error {
+	return store.NewRevisionStore(s.Store).UpdateCircuitBreakerRuleCAS(cbRule, expectRevision)
+}
+
+// UpdateFaultDetectRuleCAS 规则保存在默认分片上
+func (s *shardedStore) UpdateFaultDetectRuleCAS(conf *model.FaultDetectRule, expectRevision string) error {
+	return store.NewRevisionStore(s.Store).UpdateFaultDetectRuleCAS(conf, expectRevision)
+}
+
+// UpdateStrategyCAS 鉴权策略保存在默认分片上
+func (s *shardedStore) UpdateStrategyCAS(strategy *model.ModifyStrategyDetail, expectRevision string) error {
+	return store.NewRevisionStore(s.Store).UpdateStrategyCAS(strategy, expectRevision)
+}
+
+// PurgeDeleted 依次在每个分片上物理删除，所有分片合计最多删除 batchSize 条
+func (s *shardedStore) PurgeDeleted(kind store.RetentionKind, timeout time.Duration, batchSize uint32) (uint32, error) {
+	var total uint32
+	for _, shard := range s.shards {
+		if batchSize > 0 && total >= batchSize {
+			break
+		}
+		remain := batchSize
+		if batchSize > 0 {
+			remain = batchSize - total
+		}
+		count, err := store.NewRetentionStore(shard).PurgeDeleted(kind, timeout, remain)
+		total += count
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// SearchServices 跨分片查询，基于合并所有分片结果的查询方法实现
+func (s *shardedStore) SearchServices(q *store.Query) (uint32, []*model.Service, error) {
+	return store.NewQueryStore(s.merged()).SearchServices(q)
+}
+
+// SearchInstances 跨分片查询，基于合并所有分片结果的查询方法实现
+func (s *shardedStore) SearchInstances(q *store.Query) (uint32, []*model.Instance, error) {
+	return store.NewQueryStore(s.merged()).SearchInstances(q)
+}
+
+// SearchRoutingConfigs 实现 store.QueryStore，数据保存在默认分片上
+func (s *shardedStore) SearchRoutingConfigs(q *store.Query) (uint32, []*model.RoutingConfig, error) {
+	return store.NewQueryStore(s.Store).SearchRoutingConfigs(q)
+}
+
+// SearchFaultDetectRules 实现 store.QueryStore，数据保存在默认分片上
+func (s *shardedStore) SearchFaultDetectRules(q *store.Query) (uint32, []*model.FaultDetectRule, error) {
+	return store.NewQueryStore(s.Store).SearchFaultDetectRules(q)
+}
+
+// SearchConfigFiles 跨分片查询，基于合并所有分片结果的查询方法实现
+func (s *shardedStore) SearchConfigFiles(q *store.Query) (uint32, []*model.ConfigFile, error) {
+	return store.NewQueryStore(s.merged()).SearchConfigFiles(q)
+}
+
+// SearchUsers 实现 store.QueryStore，数据保存在默认分片上
+func (s *shardedStore) SearchUsers(q *store.Query) (uint32, []*model.User, error) {
+	return store.NewQueryStore(s.Store).SearchUsers(q)
+}
+
+// SearchStrategies 实现 store.QueryStore，数据保存在默认分片上
+func (s *shardedStore) SearchStrategies(q *store.Query) (uint32, []*model.StrategyDetail, error) {
+	return store.NewQueryStore(s.Store).SearchStrategies(q)
+}
+
+// GetNamespacesPage 跨分片查询，基于合并所有分片结果的查询方法实现
+func (s *shardedStore) GetNamespacesPage(filter map[string][]string, token store.PageToken,
+	limit uint32) (*store.Page[*model.Namespace], error) {
+	return store.NewCursorStore(s.merged()).GetNamespacesPage(filter, token, limit)
+}
+
+// GetServicesPage 过滤条件指定了命名空间时交给对应的分片，否则基于合并所有分片结果的查询方法实现
+func (s *shardedStore) GetServicesPage(serviceFilters map[string]string, serviceMetas map[string]string,
+	instanceFilters *model.InstanceArgs, token store.PageToken, limit uint32) (*store.Page[*model.Service], error) {
+	if shard, ok := s.filterShard(serviceFilters); ok {
+		return store.NewCursorStore(shard).GetServicesPage(serviceFilters, serviceMetas, instanceFilters, token, limit)
+	}
+	return store.NewCursorStore(s.merged()).GetServicesPage(serviceFilters, serviceMetas, instanceFilters, token, limit)
+}
+
+// GetServiceAliasesPage 跨分片查询，基于合并所有分片结果的查询方法实现
+func (s *shardedStore) GetServiceAliasesPage(filter map[string]string, token store.PageToken,
+	limit uint32) (*store.Page[*model.ServiceAlias], error) {
+	return store.NewCursorStore(s.merged()).GetServiceAliasesPage(filter, token, limit)
+}
+
+// GetExpandInstancesPage 过滤条件指定了命名空间时交给对应的分片，否则基于合并所有分片结果的查询方法实现
+func (s *shardedStore) GetExpandInstancesPage(filter map[string]string, metaFilter map[string]string,
+	token store.PageToken, limit uint32) (*store.Page[*model.Instance], error) {
+	if shard, ok := s.filterShard(filter); ok {
+		return store.NewCursorStore(shard).GetExpandInstancesPage(filter, metaFilter, token, limit)
+	}
+	return store.NewCursorStore(s.merged()).GetExpandInstancesPage(filter, metaFilter, token, limit)
+}
+
+// GetRoutingConfigsPage 实现 store.CursorStore，数据保存在默认分片上
+func (s *shardedStore) GetRoutingConfigsPage(filter map[string]string, token store.PageToken,
+	limit uint32) (*store.Page[*model.RoutingConfig], error) {
+	return store.NewCursorStore(s.Store).GetRoutingConfigsPage(filter, token, limit)
+}
+
+// GetExtendRateLimitsPage 实现 store.CursorStore，数据保存在默认分片上
+func (s *shardedStore) GetExtendRateLimitsPage(query map[string]string, token store.PageToken,
+	limit uint32) (*store.Page[*model.RateLimit], error) {
+	return store.NewCursorStore(s.Store).GetExtendRateLimitsPage(query, token, limit)
+}
+
+// GetCircuitBreakerRulesPage 实现 store.CursorStore，数据保存在默认分片上
+func (s *shardedStore) GetCircuitBreakerRulesPage(filter map[string]string, token store.PageToken,
+	limit uint32) (*store.Page[*model.CircuitBreakerRule], error) {
+	return store.NewCursorStore(s.Store).GetCircuitBreakerRulesPage(filter, token, limit)
+}
+
+// GetFaultDetectRulesPage 实现 store.CursorStore，数据保存在默认分片上
+func (s *shardedStore) GetFaultDetectRulesPage(filter map[string]string, token store.PageToken,
+	limit uint32) (*store.Page[*model.FaultDetectRule], error) {
+	return store.NewCursorStore(s.Store).GetFaultDetectRulesPage(filter, token, limit)
+}
+
+// QueryConfigFilesPage 过滤条件指定了命名空间时交给对应的分片，否则基于合并所有分片结果的查询方法实现
+func (s *shardedStore) QueryConfigFilesPage(filter map[string]string, token store.PageToken,
+	limit uint32) (*store.Page[*model.ConfigFile], error) {
+	if shard, ok := s.filterShard(filter); ok {
+		return store.NewCursorStore(shard).QueryConfigFilesPage(filter, token, limit)
+	}
+	return store.NewCursorStore(s.merged()).QueryConfigFilesPage(filter, token, limit)
+}
+
+// QueryConfigFileReleaseHistoriesPage 过滤条件指定了命名空间时交给对应的分片，否则基于合并所有分片结果的查询方法实现
+func (s *shardedStore) QueryConfigFileReleaseHistoriesPage(filter map[string]string, token store.PageToken,
+	limit uint32) (*store.Page[*model.ConfigFileReleaseHistory], error) {
+	if shard, ok := s.filterShard(filter); ok {
+		return store.NewCursorStore(shard).QueryConfigFileReleaseHistoriesPage(filter, token, limit)
+	}
+	return store.NewCursorStore(s.merged()).QueryConfigFileReleaseHistoriesPage(filter, token, limit)
+}
+
+// GetUsersPage 实现 store.CursorStore，数据保存在默认分片上
+func (s *shardedStore) GetUsersPage(filters map[string]string, token store.PageToken, limit uint32) (*store.Page[*model.User], error) {
+	return store.NewCursorStore(s.Store).GetUsersPage(filters, token, limit)
+}
+
+// GetGroupsPage 实现 store.CursorStore，数据保存在默认分片上
+func (s *shardedStore) GetGroupsPage(filters map[string]string, token store.PageToken,
+	limit uint32) (*store.Page[*model.UserGroup], error) {
+	return store.NewCursorStore(s.Store).GetGroupsPage(filters, token, limit)
+}
+
+// GetStrategiesPage 实现 store.CursorStore，数据保存在默认分片上
+func (s *shardedStore) GetStrategiesPage(filters map[string]string, token store.PageToken,
+	limit uint32) (*store.Page[*model.StrategyDetail], error) {
+	return store.NewCursorStore(s.Store).GetStrategiesPage(filters, token, limit)
+}
+
+// RangeMoreNamespaces 依次遍历每个分片
+func (s *shardedStore) RangeMoreNamespaces(mtime time.Time, batchSize int, handler store.BatchHandler[*model.Namespace]) error {
+	return rangeShards(s.shards, handler, func(_ int, shard store.Store, handler store.BatchHandler[*model.Namespace]) error {
+		return store.NewStreamStore(shard).RangeMoreNamespaces(mtime, batchSize, handler)
+	})
+}
+
+// RangeMoreServices 依次遍历每个分片
+func (s *shardedStore) RangeMoreServices(mtime time.Time, firstUpdate bool, disableBusiness bool, needMeta bool,
+	batchSize int, handler store.BatchHandler[*model.Service]) error {
+	return rangeShards(s.shards, handler, func(_ int, shard store.Store, handler store.BatchHandler[*model.Service]) error {
+		return store.NewStreamStore(shard).RangeMoreServices(mtime, firstUpdate, disableBusiness, needMeta, batchSize, handler)
+	})
+}
+
+// RangeMoreInstances 依次遍历每个分片，Tx 只在其绑定的分片上使用
+func (s *shardedStore) RangeMoreInstances(tx store.Tx, mtime time.Time, firstUpdate bool, needMeta bool,
+	serviceID []string, batchSize int, handler store.BatchHandler[*model.Instance]) error {
+	bound, delegate := boundTx(tx)
+	return rangeShards(s.shards, handler, func(index int, shard store.Store, handler store.BatchHandler[*model.Instance]) error {
+		var shardTx store.Tx
+		if index == bound {
+			shardTx = delegate
+		}
+		return store.NewStreamStore(shard).RangeMoreInstances(shardTx, mtime, firstUpdate, needMeta, serviceID, batchSize, handler)
+	})
+}
+
+// RangeMoreClients 实现 store.StreamStore，数据保存在默认分片上
+func (s *shardedStore) RangeMoreClients(mtime time.Time, firstUpdate bool, batchSize int, handler store.BatchHandler[*model.Client]) error {
+	return store.NewStreamStore(s.Store).RangeMoreClients(mtime, firstUpdate, batchSize, handler)
+}
+
+// RangeMoreServiceContracts 依次遍历每个分片
+func (s *shardedStore) RangeMoreServiceContracts(firstUpdate bool, mtime time.Time, batchSize int,
+	handler store.BatchHandler[*model.ServiceContract]) error {
+	return rangeShards(s.shards, handler, func(_ int, shard store.Store, handler store.BatchHandler[*model.ServiceContract]) error {
+		return store.NewStreamStore(shard).RangeMoreServiceContracts(firstUpdate, mtime, batchSize, handler)
+	})
+}
+
+// RangeMoreGrayResources 实现 store.StreamStore，数据保存在默认分片上
+func (s *shardedStore) RangeMoreGrayResources(firstUpdate bool, mtime time.Time, batchSize int,
+	handler store.BatchHandler[*model.GrayResource]) error {
+	return store.NewStreamStore(s.Store).RangeMoreGrayResources(firstUpdate, mtime, batchSize, handler)
+}
+
+// RangeRoutingConfigsForCache 实现 store.StreamStore，数据保存在默认分片上
+func (s *shardedStore) RangeRoutingConfigsForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler store.BatchHandler[*model.RoutingConfig]) error {
+	return store.NewStreamStore(s.Store).RangeRoutingConfigsForCache(mtime, firstUpdate, batchSize, handler)
+}
+
+// RangeRoutingConfigsV2ForCache 实现 store.StreamStore，数据保存在默认分片上
+func (s *shardedStore) RangeRoutingConfigsV2ForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler store.BatchHandler[*model.RouterConfig]) error {
+	return store.NewStreamStore(s.Store).RangeRoutingConfigsV2ForCache(mtime, firstUpdate, batchSize, handler)
+}
+
+// RangeRateLimitsForCache 实现 store.StreamStore，数据保存在默认分片上
+func (s *shardedStore) RangeRateLimitsForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler store.BatchHandler[*model.RateLimit]) error {
+	return store.NewStreamStore(s.Store).RangeRateLimitsForCache(mtime, firstUpdate, batchSize, handler)
+}
+
+// RangeCircuitBreakerRulesForCache 实现 store.StreamStore，数据保存在默认分片上
+func (s *shardedStore) RangeCircuitBreakerRulesForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler store.BatchHandler[*model.CircuitBreakerRule]) error {
+	return store.NewStreamStore(s.Store).RangeCircuitBreakerRulesForCache(mtime, firstUpdate, batchSize, handler)
+}
+
+// RangeFaultDetectRulesForCache 实现 store.StreamStore，数据保存在默认分片上
+func (s *shardedStore) RangeFaultDetectRulesForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler store.BatchHandler[*model.FaultDetectRule]) error {
+	return store.NewStreamStore(s.Store).RangeFaultDetectRulesForCache(mtime, firstUpdate, batchSize, handler)
+}
+
+// RangeMoreConfigGroup 依次遍历每个分片
+func (s *shardedStore) RangeMoreConfigGroup(firstUpdate bool, mtime time.Time, batchSize int,
+	handler store.BatchHandler[*model.ConfigFileGroup]) error {
+	return rangeShards(s.shards, handler, func(_ int, shard store.Store, handler store.BatchHandler[*model.ConfigFileGroup]) error {
+		return store.NewStreamStore(shard).RangeMoreConfigGroup(firstUpdate, mtime, batchSize, handler)
+	})
+}
+
+// RangeMoreReleaseFile 依次遍历每个分片
+func (s *shardedStore) RangeMoreReleaseFile(firstUpdate bool, modifyTime time.Time, batchSize int,
+	handler store.BatchHandler[*model.ConfigFileRelease]) error {
+	return rangeShards(s.shards, handler, func(_ int, shard store.Store, handler store.BatchHandler[*model.ConfigFileRelease]) error {
+		return store.NewStreamStore(shard).RangeMoreReleaseFile(firstUpdate, modifyTime, batchSize, handler)
+	})
+}
+
+// RangeUsersForCache 实现 store.StreamStore，数据保存在默认分片上
+func (s *shardedStore) RangeUsersForCache(mtime time.Time, firstUpdate bool, batchSize int, handler store.BatchHandler[*model.User]) error {
+	return store.NewStreamStore(s.Store).RangeUsersForCache(mtime, firstUpdate, batchSize, handler)
+}
+
+// RangeGroupsForCache 实现 store.StreamStore，数据保存在默认分片上
+func (s *shardedStore) RangeGroupsForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler store.BatchHandler[*model.UserGroup]) error {
+	return store.NewStreamStore(s.Store).RangeGroupsForCache(mtime, firstUpdate, batchSize, handler)
+}
+
+// RangeStrategyDetailsForCache 实现 store.StreamStore，数据保存在默认分片上
+func (s *shardedStore) RangeStrategyDetailsForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler store.BatchHandler[*model.StrategyDetail]) error {
+	return store.NewStreamStore(s.Store).RangeStrategyDetailsForCache(mtime, firstUpdate, batchSize, handler)
+}
+
+// Watch 基于合并所有分片结果的 GetMore* 轮询监听变更
+func (s *shardedStore) Watch(ctx context.Context, kind store.ResourceKind, cursor store.Cursor) (store.Watcher, error) {
+	return store.NewPollingWatchStore(s.merged()).Watch(ctx, kind, cursor)
+}
+
+// merged 只暴露 store.Store 的方法，供可选接口的默认实现基于合并所有分片结果的查询方法实现
+func (s *shardedStore) merged() store.Store {
+	return struct{ store.Store }{Store: s}
+}
+
+// rangeShards 依次在每个分片上遍历，handler 要求停止或者返回错误时不再遍历后续的分片
+func rangeShards[T any](shards []store.Store, handler store.BatchHandler[T],
+	call func(index int, shard store.Store, handler store.BatchHandler[T]) error) error {
+	stopped := false
+	wrapped := func(items []T) (bool, error) {
+		more, err := handler(items)
+		stopped = !more || err != nil
+		return more, err
+	}
+	for i := range shards {
+		if err := call(i, shards[i], wrapped); err != nil || stopped {
+			return err
+		}
+	}
+	return nil
+}
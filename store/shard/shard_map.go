@@ -0,0 +1,59 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package shard
+
+import (
+	"hash/fnv"
+)
+
+// ShardMap 根据命名空间选择数据所在的分片
+type ShardMap interface {
+	// Shard 返回命名空间所在分片的下标，取值范围为 [0, 分片数量)
+	Shard(namespace string) int
+}
+
+// ShardMapFunc 函数形式的 ShardMap
+type ShardMapFunc func(namespace string) int
+
+// Shard 实现 ShardMap
+func (f ShardMapFunc) Shard(namespace string) int {
+	return f(namespace)
+}
+
+// NewHashShardMap 按照命名空间的哈希值将命名空间均匀分布到 shards 个分片上
+func NewHashShardMap(shards int) ShardMap {
+	return ShardMapFunc(func(namespace string) int {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(namespace))
+		return int(h.Sum32() % uint32(shards))
+	})
+}
+
+// NewStaticShardMap 按照 assignments 指定命名空间所在的分片，未指定的命名空间交给 fallback 处理
+func NewStaticShardMap(assignments map[string]int, fallback ShardMap) ShardMap {
+	copied := make(map[string]int, len(assignments))
+	for namespace, shard := range assignments {
+		copied[namespace] = shard
+	}
+	return ShardMapFunc(func(namespace string) int {
+		if shard, ok := copied[namespace]; ok {
+			return shard
+		}
+		return fallback.Shard(namespace)
+	})
+}
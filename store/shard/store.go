@@ -0,0 +1,267 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package shard 提供按照命名空间分片的 store.Store 实现
+//
+// 命名空间、服务、实例、服务契约以及配置中心相关的数据按照命名空间保存在不同的分片上，
+// 其余与命名空间无关的数据（路由、限流、熔断、客户端、灰度资源、L5、鉴权等）保存在第一个分片上。
+// 跨命名空间的查询会并发访问所有分片并合并结果，跨分片的写操作不具备原子性。
+package shard
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// StoreName 分片存储的名字
+const StoreName = "shard"
+
+// New 创建分片存储，shards 需要已经完成初始化，shards[0] 同时保存与命名空间无关的数据；
+// 服务别名需要与其指向的服务位于同一个分片
+func New(shards []store.Store, shardMap ShardMap) (store.Store, error) {
+	if len(shards) == 0 {
+		return nil, store.NewStatusError(store.EmptyParamsErr, "at least one shard is required")
+	}
+	for i := range shards {
+		if shards[i] == nil {
+			return nil, store.NewStatusError(store.EmptyParamsErr, fmt.Sprintf("shard %d is nil", i))
+		}
+	}
+	if shardMap == nil {
+		return nil, store.NewStatusError(store.EmptyParamsErr, "shard map is required")
+	}
+	return &shardedStore{
+		Store:    shards[0],
+		shards:   shards,
+		shardMap: shardMap,
+	}, nil
+}
+
+var (
+	_ store.Store                = (*shardedStore)(nil)
+	_ store.NamingTxStore        = (*shardedStore)(nil)
+	_ store.DistributedLockStore = (*shardedStore)(nil)
+	_ store.LeaderWatchStore     = (*shardedStore)(nil)
+	_ store.RevisionStore        = (*shardedStore)(nil)
+	_ store.RetentionStore       = (*shardedStore)(nil)
+	_ store.QueryStore           = (*shardedStore)(nil)
+	_ store.CursorStore          = (*shardedStore)(nil)
+	_ store.StreamStore          = (*shardedStore)(nil)
+	_ store.WatchableStore       = (*shardedStore)(nil)
+)
+
+// shardedStore 与命名空间相关的方法都按照分片覆盖，未覆盖的方法只涉及与命名空间无关的数据，交给默认分片处理
+type shardedStore struct {
+	store.Store
+	shards   []store.Store
+	shardMap ShardMap
+}
+
+// Name 存储层的名字
+func (s *shardedStore) Name() string {
+	return StoreName
+}
+
+// Initialize 分片需要已经完成初始化，这里不做任何处理
+func (s *shardedStore) Initialize(c *store.Config) error {
+	return nil
+}
+
+// Destroy 销毁所有的分片
+func (s *shardedStore) Destroy() error {
+	var firstErr error
+	for _, shard := range s.shards {
+		if err := shard.Destroy(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (s *shardedStore) shardIndex(namespace string) int {
+	index := s.shardMap.Shard(namespace)
+	if index < 0 || index >= len(s.shards) {
+		index = ((index % len(s.shards)) + len(s.shards)) % len(s.shards)
+	}
+	return index
+}
+
+// shard 返回命名空间所在的分片
+func (s *shardedStore) shard(namespace string) store.Store {
+	return s.shards[s.shardIndex(namespace)]
+}
+
+// filterShard 过滤条件中精确指定了命名空间时返回对应的分片
+func (s *shardedStore) filterShard(filter map[string]string) (store.Store, bool) {
+	namespace, ok := filter["namespace"]
+	if !ok || namespace == "" || namespace[len(namespace)-1] == '*' {
+		return nil, false
+	}
+	return s.shard(namespace), true
+}
+
+// fanOut 并发在所有分片上执行 call，按照分片的顺序返回结果，任意一个分片失败时返回该错误
+func fanOut[T any](shards []store.Store, call func(index int, shard store.Store) (T, error)) ([]T, error) {
+	results := make([]T, len(shards))
+	errs := make([]error, len(shards))
+	wg := sync.WaitGroup{}
+	for i := range shards {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = call(i, shards[i])
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// fanOutEach 在所有分片上执行没有返回值的 call
+func fanOutEach(shards []store.Store, call func(shard store.Store) error) error {
+	_, err := fanOut(shards, func(_ int, shard store.Store) (struct{}, error) {
+		return struct{}{}, call(shard)
+	})
+	return err
+}
+
+// concat 合并所有分片返回的列表
+func concat[T any](shards []store.Store, call func(shard store.Store) ([]T, error)) ([]T, error) {
+	results, err := fanOut(shards, func(_ int, shard store.Store) ([]T, error) {
+		return call(shard)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]T, 0)
+	for _, items := range results {
+		ret = append(ret, items...)
+	}
+	return ret, nil
+}
+
+// union 合并所有分片返回的 map
+func union[K comparable, V any](shards []store.Store, call func(shard store.Store) (map[K]V, error)) (map[K]V, error) {
+	results, err := fanOut(shards, func(_ int, shard store.Store) (map[K]V, error) {
+		return call(shard)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := make(map[K]V)
+	for _, items := range results {
+		for k, v := range items {
+			ret[k] = v
+		}
+	}
+	return ret, nil
+}
+
+// first 返回第一个分片查询到的非空结果
+func first[T any](shards []store.Store, call func(shard store.Store) (*T, error)) (*T, error) {
+	results, err := fanOut(shards, func(_ int, shard store.Store) (*T, error) {
+		return call(shard)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range results {
+		if item != nil {
+			return item, nil
+		}
+	}
+	return nil, nil
+}
+
+// sum 累加所有分片返回的数量
+func sum[T uint32 | uint64](shards []store.Store, call func(shard store.Store) (T, error)) (T, error) {
+	results, err := fanOut(shards, func(_ int, shard store.Store) (T, error) {
+		return call(shard)
+	})
+	var total T
+	for _, count := range results {
+		total += count
+	}
+	return total, err
+}
+
+// mergePage 在所有分片上查询前 offset+limit 条数据，合并排序之后再分页，limit 为 0 时表示不限制
+func mergePage[T any](shards []store.Store, offset, limit uint32, less func(a, b T) bool,
+	query func(shard store.Store, limit uint32) (uint32, []T, error)) (uint32, []T, error) {
+	var window uint32
+	if limit > 0 && offset+limit > offset {
+		window = offset + limit
+	}
+	type page struct {
+		total uint32
+		items []T
+	}
+	results, err := fanOut(shards, func(_ int, shard store.Store) (page, error) {
+		total, items, err := query(shard, window)
+		return page{total: total, items: items}, err
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	var total uint32
+	merged := make([]T, 0)
+	for _, p := range results {
+		total += p.total
+		merged = append(merged, p.items...)
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return less(merged[i], merged[j])
+	})
+	if int(offset) >= len(merged) {
+		return total, []T{}, nil
+	}
+	merged = merged[offset:]
+	if limit > 0 && int(limit) < len(merged) {
+		merged = merged[:limit]
+	}
+	return total, merged, nil
+}
+
+// newerFirst 按照修改时间倒序排列，修改时间相同时按照 key 升序排列
+func newerFirst[T any](mtime func(T) time.Time, key func(T) string) func(a, b T) bool {
+	return func(a, b T) bool {
+		if ma, mb := mtime(a), mtime(b); !ma.Equal(mb) {
+			return ma.After(mb)
+		}
+		return key(a) < key(b)
+	}
+}
+
+var (
+	serviceOrder = newerFirst(func(svc *model.Service) time.Time { return svc.ModifyTime },
+		func(svc *model.Service) string { return svc.ID })
+	aliasOrder = newerFirst(func(alias *model.ServiceAlias) time.Time { return alias.ModifyTime },
+		func(alias *model.ServiceAlias) string { return alias.ID })
+	instanceOrder = newerFirst(func(ins *model.Instance) time.Time { return ins.ModifyTime },
+		func(ins *model.Instance) string { return ins.Proto.GetId().GetValue() })
+	configFileOrder = newerFirst(func(file *model.ConfigFile) time.Time { return file.ModifyTime },
+		func(file *model.ConfigFile) string { return file.Namespace + "@" + file.Group + "@" + file.Name })
+)
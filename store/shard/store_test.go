@@ -0,0 +1,179 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package shard_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/memory"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+	"github.com/polarismesh/polaris-plugin-api/store/shard"
+)
+
+// newSharded 创建两个分片，na 位于分片 0，nb 位于分片 1
+func newSharded(t *testing.T) (store.Store, []store.Store) {
+	t.Helper()
+	shards := []store.Store{memory.New(), memory.New()}
+	shardMap := shard.NewStaticShardMap(map[string]int{"na": 0, "nb": 1}, shard.NewHashShardMap(len(shards)))
+	s, err := shard.New(shards, shardMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ns := range []string{"na", "nb"} {
+		if err := s.AddNamespace(&model.Namespace{Name: ns}); err != nil {
+			t.Fatal(err)
+		}
+		for i, name := range []string{"s1", "s2"} {
+			mtime := time.Now().Add(time.Duration(i) * time.Second)
+			svc := &model.Service{ID: ns + "-" + name, Name: name, Namespace: ns, CreateTime: mtime, ModifyTime: mtime}
+			if err := s.AddService(svc); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	return s, shards
+}
+
+func TestStaticShardMap(t *testing.T) {
+	m := shard.NewStaticShardMap(map[string]int{"na": 1}, shard.NewHashShardMap(4))
+	if m.Shard("na") != 1 {
+		t.Fatalf("expect assigned shard 1, got %d", m.Shard("na"))
+	}
+	if index := m.Shard("other"); index != m.Shard("other") || index < 0 || index >= 4 {
+		t.Fatalf("hash shard is not stable or out of range: %d", index)
+	}
+}
+
+func TestShardedStoreRouting(t *testing.T) {
+	s, shards := newSharded(t)
+	if ns, _ := shards[1].GetNamespace("nb"); ns == nil {
+		t.Fatal("nb is not saved on shard 1")
+	}
+	if ns, _ := shards[0].GetNamespace("nb"); ns != nil {
+		t.Fatal("nb is saved on shard 0")
+	}
+	if svc, _ := shards[1].GetService("s1", "nb"); svc == nil {
+		t.Fatal("service is not saved on the shard of its namespace")
+	}
+	if svc, _ := s.GetServiceByID("nb-s1"); svc == nil {
+		t.Fatal("service is not found across shards")
+	}
+	if count, _ := s.GetServicesCount(); count != 4 {
+		t.Fatalf("expect 4 services, got %d", count)
+	}
+}
+
+func TestShardedStorePagination(t *testing.T) {
+	s, _ := newSharded(t)
+	total, items, err := s.GetServices(nil, nil, nil, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 4 || len(items) != 4 {
+		t.Fatalf("expect 4 services, got %d of %d", len(items), total)
+	}
+	total, page, err := s.GetServices(nil, nil, nil, 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 4 || len(page) != 2 || page[0].ID != items[1].ID || page[1].ID != items[2].ID {
+		t.Fatalf("merged page does not match the full result: %v", page)
+	}
+	total, _, err = s.GetServices(map[string]string{"namespace": "nb"}, nil, nil, 0, 10)
+	if err != nil || total != 2 {
+		t.Fatalf("expect 2 services in nb, got %d: %v", total, err)
+	}
+}
+
+func TestShardedStoreTx(t *testing.T) {
+	s, _ := newSharded(t)
+	tx, err := s.StartTx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	if _, err := s.GetConfigFileTx(tx, "na", "group", "file"); err != nil {
+		t.Fatal(err)
+	}
+	// 事务第一次使用时绑定分片，之后不能再访问其他分片
+	if _, err := s.GetConfigFileTx(tx, "nb", "group", "file"); err == nil {
+		t.Fatal("tx is used across shards")
+	}
+}
+
+func TestNewValidatesShards(t *testing.T) {
+	if _, err := shard.New(nil, shard.NewHashShardMap(1)); store.Code(err) != store.EmptyParamsErr {
+		t.Fatalf("expect EmptyParamsErr for empty shards, got %v", err)
+	}
+	if _, err := shard.New([]store.Store{memory.New()}, nil); store.Code(err) != store.EmptyParamsErr {
+		t.Fatalf("expect EmptyParamsErr for nil shard map, got %v", err)
+	}
+}
+
+func TestShardedStoreContracts(t *testing.T) {
+	s, shards := newSharded(t)
+	contract := &model.ServiceContract{ID: "c1", Namespace: "nb", Service: "s1", Name: "api", Content: "v1"}
+	if err := s.CreateServiceContract(contract); err != nil {
+		t.Fatal(err)
+	}
+	if saved, _ := shards[1].GetServiceContract("c1"); saved == nil {
+		t.Fatal("contract is not saved on the shard of its namespace")
+	}
+	if saved, _ := shards[0].GetServiceContract("c1"); saved != nil {
+		t.Fatal("contract is saved on shard 0")
+	}
+
+	// 只携带ID的修改根据ID找到契约所在的分片
+	if err := s.UpdateServiceContract(&model.ServiceContract{ID: "c1", Content: "v2"}); err != nil {
+		t.Fatal(err)
+	}
+	if saved, _ := s.GetServiceContract("c1"); saved == nil || saved.Content != "v2" {
+		t.Fatalf("contract is not updated: %+v", saved)
+	}
+	if items, err := s.GetMoreServiceContracts(true, time.Time{}); err != nil || len(items) != 1 {
+		t.Fatalf("expect 1 contract, got %d: %v", len(items), err)
+	}
+	if err := s.DeleteServiceContract(&model.ServiceContract{ID: "c1"}); err != nil {
+		t.Fatal(err)
+	}
+	if saved, _ := shards[1].GetServiceContract("c1"); saved != nil {
+		t.Fatal("contract is not deleted on its shard")
+	}
+}
+
+func TestShardedStoreDelegateTx(t *testing.T) {
+	s, _ := newSharded(t)
+	tx, err := s.StartTx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	// 未绑定分片的事务绑定到默认分片
+	if tx.GetDelegateTx() == nil {
+		t.Fatal("expect the delegate tx of the default shard")
+	}
+	if _, err := s.GetConfigFileTx(tx, "nb", "group", "file"); err == nil {
+		t.Fatal("tx bound to the default shard is used on shard 1")
+	}
+}
@@ -0,0 +1,461 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package shard
+
+import (
+	"sync"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// errCrossShard 事务只能绑定一个分片
+var errCrossShard = store.NewStatusError(store.EmptyParamsErr, "transaction across shards is not supported")
+
+// StartTx 开启一个原子事务，事务在第一次使用时绑定到对应的分片，不支持跨分片的事务
+func (s *shardedStore) StartTx() (store.Tx, error) {
+	return &shardTx{s: s}, nil
+}
+
+// StartReadTx 开启一个只读事务，事务在第一次使用时绑定到对应的分片，不支持跨分片的事务
+func (s *shardedStore) StartReadTx() (store.Tx, error) {
+	return &shardTx{s: s, readOnly: true}, nil
+}
+
+// bindTx 将事务绑定到 index 对应的分片，返回该分片上的事务
+func (s *shardedStore) bindTx(tx store.Tx, index int) (store.Tx, error) {
+	stx, ok := store.UnwrapTx(tx).(*shardTx)
+	if !ok {
+		return tx, nil
+	}
+	stx.lock.Lock()
+	defer stx.lock.Unlock()
+
+	if stx.delegate != nil {
+		if stx.index != index {
+			return nil, errCrossShard
+		}
+		return stx.delegate, nil
+	}
+	var (
+		delegate store.Tx
+		err      error
+	)
+	if stx.readOnly {
+		delegate, err = s.shards[index].StartReadTx()
+	} else {
+		delegate, err = s.shards[index].StartTx()
+	}
+	if err != nil {
+		return nil, err
+	}
+	stx.index, stx.delegate = index, delegate
+	return delegate, nil
+}
+
+// boundTx 返回事务已经绑定的分片以及该分片上的事务，未绑定时 index 为 -1
+func boundTx(tx store.Tx) (int, store.Tx) {
+	stx, ok := store.UnwrapTx(tx).(*shardTx)
+	if !ok {
+		return -1, nil
+	}
+	stx.lock.Lock()
+	defer stx.lock.Unlock()
+	if stx.delegate == nil {
+		return -1, nil
+	}
+	return stx.index, stx.delegate
+}
+
+// txIndex 返回事务已经绑定的分片，未绑定时返回默认分片
+func txIndex(tx store.Tx) int {
+	if index, _ := boundTx(tx); index >= 0 {
+		return index
+	}
+	return 0
+}
+
+// shardTx 延迟绑定分片的事务
+type shardTx struct {
+	s        *shardedStore
+	readOnly bool
+
+	lock     sync.Mutex
+	index    int
+	delegate store.Tx
+}
+
+// Commit 提交事务
+func (tx *shardTx) Commit() error {
+	if _, delegate := boundTx(tx); delegate != nil {
+		return delegate.Commit()
+	}
+	return nil
+}
+
+// Rollback 回滚事务
+func (tx *shardTx) Rollback() error {
+	if _, delegate := boundTx(tx); delegate != nil {
+		return delegate.Rollback()
+	}
+	return nil
+}
+
+// GetDelegateTx 获取分片上的原始事务对象，事务未绑定分片时绑定到默认分片
+func (tx *shardTx) GetDelegateTx() interface{} {
+	delegate, err := tx.s.bindTx(tx, txIndex(tx))
+	if err != nil {
+		return nil
+	}
+	return delegate.GetDelegateTx()
+}
+
+// CreateReadView 创建快照读视图，事务需要已经绑定分片
+func (tx *shardTx) CreateReadView() error {
+	if _, delegate := boundTx(tx); delegate != nil {
+		return delegate.CreateReadView()
+	}
+	return store.NewStatusError(store.EmptyParamsErr, "transaction is not bound to any shard")
+}
+
+// locker 将事务绑定到 index 对应的分片，返回该分片上能够加锁的事务
+func (tx *shardTx) locker(index int) (store.TxLocker, error) {
+	delegate, err := tx.s.bindTx(tx, index)
+	if err != nil {
+		return nil, err
+	}
+	locker, ok := delegate.(store.TxLocker)
+	if !ok {
+		return nil, store.NewStatusError(store.NotSupportedErr, "transaction of shard can not hold locks")
+	}
+	return locker, nil
+}
+
+// LockBootstrap 在默认分片上加锁
+func (tx *shardTx) LockBootstrap(key string, server string) error {
+	locker, err := tx.locker(0)
+	if err != nil {
+		return err
+	}
+	return locker.LockBootstrap(key, server)
+}
+
+// LockNamespace 在命名空间所在的分片上加锁
+func (tx *shardTx) LockNamespace(name string) (*model.Namespace, error) {
+	locker, err := tx.locker(tx.s.shardIndex(name))
+	if err != nil {
+		return nil, err
+	}
+	return locker.LockNamespace(name)
+}
+
+// DeleteNamespace 在命名空间所在的分片上删除命名空间
+func (tx *shardTx) DeleteNamespace(name string) error {
+	locker, err := tx.locker(tx.s.shardIndex(name))
+	if err != nil {
+		return err
+	}
+	return locker.DeleteNamespace(name)
+}
+
+// LockService 在服务所在的分片上加锁
+func (tx *shardTx) LockService(name string, namespace string) (*model.Service, error) {
+	locker, err := tx.locker(tx.s.shardIndex(namespace))
+	if err != nil {
+		return nil, err
+	}
+	return locker.LockService(name, namespace)
+}
+
+// RLockService 在服务所在的分片上加共享锁
+func (tx *shardTx) RLockService(name string, namespace string) (*model.Service, error) {
+	locker, err := tx.locker(tx.s.shardIndex(namespace))
+	if err != nil {
+		return nil, err
+	}
+	return locker.RLockService(name, namespace)
+}
+
+// CreateTransaction 创建事务对象，每个分片上的事务在第一次使用时创建
+func (s *shardedStore) CreateTransaction() (store.Transaction, error) {
+	return &shardTransaction{s: s, txs: make(map[int]store.Transaction)}, nil
+}
+
+// shardTransaction 按照命名空间把操作交给对应分片上的事务，Commit 时依次提交，不保证跨分片的原子性
+type shardTransaction struct {
+	s     *shardedStore
+	order []int
+	txs   map[int]store.Transaction
+}
+
+func (t *shardTransaction) get(index int) (store.Transaction, error) {
+	if tx, ok := t.txs[index]; ok {
+		return tx, nil
+	}
+	tx, err := t.s.shards[index].CreateTransaction()
+	if err != nil {
+		return nil, err
+	}
+	t.txs[index] = tx
+	t.order = append(t.order, index)
+	return tx, nil
+}
+
+// Commit Transaction
+func (t *shardTransaction) Commit() error {
+	for _, index := range t.order {
+		if err := t.txs[index].Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LockBootstrap 在默认分片上加锁
+func (t *shardTransaction) LockBootstrap(key string, server string) error {
+	tx, err := t.get(0)
+	if err != nil {
+		return err
+	}
+	return tx.LockBootstrap(key, server)
+}
+
+// LockNamespace Row it locks Namespace
+func (t *shardTransaction) LockNamespace(name string) (*model.Namespace, error) {
+	tx, err := t.get(t.s.shardIndex(name))
+	if err != nil {
+		return nil, err
+	}
+	return tx.LockNamespace(name)
+}
+
+// DeleteNamespace Delete Namespace
+func (t *shardTransaction) DeleteNamespace(name string) error {
+	tx, err := t.get(t.s.shardIndex(name))
+	if err != nil {
+		return err
+	}
+	return tx.DeleteNamespace(name)
+}
+
+// LockService Row it locks service
+func (t *shardTransaction) LockService(name string, namespace string) (*model.Service, error) {
+	tx, err := t.get(t.s.shardIndex(namespace))
+	if err != nil {
+		return nil, err
+	}
+	return tx.LockService(name, namespace)
+}
+
+// RLockService Shared lock service
+func (t *shardTransaction) RLockService(name string, namespace string) (*model.Service, error) {
+	tx, err := t.get(t.s.shardIndex(namespace))
+	if err != nil {
+		return nil, err
+	}
+	return tx.RLockService(name, namespace)
+}
+
+// DeleteRoutingConfigTx 在默认分片上执行
+func (s *shardedStore) DeleteRoutingConfigTx(tx store.Tx, serviceID string) error {
+	tx, err := s.bindTx(tx, 0)
+	if err != nil {
+		return err
+	}
+	return s.shards[0].DeleteRoutingConfigTx(tx, serviceID)
+}
+
+// CreateRoutingConfigV2Tx 在默认分片上执行
+func (s *shardedStore) CreateRoutingConfigV2Tx(tx store.Tx, conf *model.RouterConfig) error {
+	tx, err := s.bindTx(tx, 0)
+	if err != nil {
+		return err
+	}
+	return s.shards[0].CreateRoutingConfigV2Tx(tx, conf)
+}
+
+// UpdateRoutingConfigV2Tx 在默认分片上执行
+func (s *shardedStore) UpdateRoutingConfigV2Tx(tx store.Tx, conf *model.RouterConfig) error {
+	tx, err := s.bindTx(tx, 0)
+	if err != nil {
+		return err
+	}
+	return s.shards[0].UpdateRoutingConfigV2Tx(tx, conf)
+}
+
+// GetRoutingConfigV2WithIDTx 在默认分片上执行
+func (s *shardedStore) GetRoutingConfigV2WithIDTx(tx store.Tx, id string) (*model.RouterConfig, error) {
+	tx, err := s.bindTx(tx, 0)
+	if err != nil {
+		return nil, err
+	}
+	return s.shards[0].GetRoutingConfigV2WithIDTx(tx, id)
+}
+
+// CleanGrayResource 在默认分片上执行
+func (s *shardedStore) CleanGrayResource(tx store.Tx, data *model.GrayResource) error {
+	tx, err := s.bindTx(tx, 0)
+	if err != nil {
+		return err
+	}
+	return s.shards[0].CleanGrayResource(tx, data)
+}
+
+// CreateGrayResourceTx 在默认分片上执行
+func (s *shardedStore) CreateGrayResourceTx(tx store.Tx, data *model.GrayResource) error {
+	tx, err := s.bindTx(tx, 0)
+	if err != nil {
+		return err
+	}
+	return s.shards[0].CreateGrayResourceTx(tx, data)
+}
+
+// CreateRoutingConfigTx 在默认分片上执行
+func (s *shardedStore) CreateRoutingConfigTx(tx store.Tx, conf *model.RoutingConfig) error {
+	tx, err := s.bindTx(tx, 0)
+	if err != nil {
+		return err
+	}
+	return store.NewNamingTxStore(s.shards[0]).CreateRoutingConfigTx(tx, conf)
+}
+
+// UpdateRoutingConfigTx 在默认分片上执行
+func (s *shardedStore) UpdateRoutingConfigTx(tx store.Tx, conf *model.RoutingConfig) error {
+	tx, err := s.bindTx(tx, 0)
+	if err != nil {
+		return err
+	}
+	return store.NewNamingTxStore(s.shards[0]).UpdateRoutingConfigTx(tx, conf)
+}
+
+// CreateRateLimitTx 在默认分片上执行
+func (s *shardedStore) CreateRateLimitTx(tx store.Tx, limiting *model.RateLimit) error {
+	tx, err := s.bindTx(tx, 0)
+	if err != nil {
+		return err
+	}
+	return store.NewNamingTxStore(s.shards[0]).CreateRateLimitTx(tx, limiting)
+}
+
+// UpdateRateLimitTx 在默认分片上执行
+func (s *shardedStore) UpdateRateLimitTx(tx store.Tx, limiting *model.RateLimit) error {
+	tx, err := s.bindTx(tx, 0)
+	if err != nil {
+		return err
+	}
+	return store.NewNamingTxStore(s.shards[0]).UpdateRateLimitTx(tx, limiting)
+}
+
+// EnableRateLimitTx 在默认分片上执行
+func (s *shardedStore) EnableRateLimitTx(tx store.Tx, limit *model.RateLimit) error {
+	tx, err := s.bindTx(tx, 0)
+	if err != nil {
+		return err
+	}
+	return store.NewNamingTxStore(s.shards[0]).EnableRateLimitTx(tx, limit)
+}
+
+// DeleteRateLimitTx 在默认分片上执行
+func (s *shardedStore) DeleteRateLimitTx(tx store.Tx, limiting *model.RateLimit) error {
+	tx, err := s.bindTx(tx, 0)
+	if err != nil {
+		return err
+	}
+	return store.NewNamingTxStore(s.shards[0]).DeleteRateLimitTx(tx, limiting)
+}
+
+// CreateCircuitBreakerRuleTx 在默认分片上执行
+func (s *shardedStore) CreateCircuitBreakerRuleTx(tx store.Tx, cbRule *model.CircuitBreakerRule) error {
+	tx, err := s.bindTx(tx, 0)
+	if err != nil {
+		return err
+	}
+	return store.NewNamingTxStore(s.shards[0]).CreateCircuitBreakerRuleTx(tx, cbRule)
+}
+
+// UpdateCircuitBreakerRuleTx 在默认分片上执行
+func (s *shardedStore) UpdateCircuitBreakerRuleTx(tx store.Tx, cbRule *model.CircuitBreakerRule) error {
+	tx, err := s.bindTx(tx, 0)
+	if err != nil {
+		return err
+	}
+	return store.NewNamingTxStore(s.shards[0]).UpdateCircuitBreakerRuleTx(tx, cbRule)
+}
+
+// DeleteCircuitBreakerRuleTx 在默认分片上执行
+func (s *shardedStore) DeleteCircuitBreakerRuleTx(tx store.Tx, id string) error {
+	tx, err := s.bindTx(tx, 0)
+	if err != nil {
+		return err
+	}
+	return store.NewNamingTxStore(s.shards[0]).DeleteCircuitBreakerRuleTx(tx, id)
+}
+
+// EnableCircuitBreakerRuleTx 在默认分片上执行
+func (s *shardedStore) EnableCircuitBreakerRuleTx(tx store.Tx, cbRule *model.CircuitBreakerRule) error {
+	tx, err := s.bindTx(tx, 0)
+	if err != nil {
+		return err
+	}
+	return store.NewNamingTxStore(s.shards[0]).EnableCircuitBreakerRuleTx(tx, cbRule)
+}
+
+// EnableRoutingTx 在默认分片上执行
+func (s *shardedStore) EnableRoutingTx(tx store.Tx, conf *model.RouterConfig) error {
+	tx, err := s.bindTx(tx, 0)
+	if err != nil {
+		return err
+	}
+	return store.NewNamingTxStore(s.shards[0]).EnableRoutingTx(tx, conf)
+}
+
+// DeleteRoutingConfigV2Tx 在默认分片上执行
+func (s *shardedStore) DeleteRoutingConfigV2Tx(tx store.Tx, serviceID string) error {
+	tx, err := s.bindTx(tx, 0)
+	if err != nil {
+		return err
+	}
+	return store.NewNamingTxStore(s.shards[0]).DeleteRoutingConfigV2Tx(tx, serviceID)
+}
+
+// CreateFaultDetectRuleTx 在默认分片上执行
+func (s *shardedStore) CreateFaultDetectRuleTx(tx store.Tx, conf *model.FaultDetectRule) error {
+	tx, err := s.bindTx(tx, 0)
+	if err != nil {
+		return err
+	}
+	return store.NewNamingTxStore(s.shards[0]).CreateFaultDetectRuleTx(tx, conf)
+}
+
+// UpdateFaultDetectRuleTx 在默认分片上执行
+func (s *shardedStore) UpdateFaultDetectRuleTx(tx store.Tx, conf *model.FaultDetectRule) error {
+	tx, err := s.bindTx(tx, 0)
+	if err != nil {
+		return err
+	}
+	return store.NewNamingTxStore(s.shards[0]).UpdateFaultDetectRuleTx(tx, conf)
+}
+
+// DeleteFaultDetectRuleTx 在默认分片上执行
+func (s *shardedStore) DeleteFaultDetectRuleTx(tx store.Tx, id string) error {
+	tx, err := s.bindTx(tx, 0)
+	if err != nil {
+		return err
+	}
+	return store.NewNamingTxStore(s.shards[0]).DeleteFaultDetectRuleTx(tx, id)
+}
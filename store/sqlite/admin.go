@@ -0,0 +1,183 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// StartLeaderElection SQLite 存储只有单个节点，发起选举的节点直接成为 leader
+func (s *sqliteStore) StartLeaderElection(key string) error {
+	return s.update(nil, func(q querier) error {
+		now := s.now()
+		saved, err := s.leaders.get(q, key)
+		if err != nil {
+			return err
+		}
+		if saved == nil {
+			saved = &model.LeaderElection{ElectKey: key, Ctime: now.Unix(), CreateTime: now}
+		}
+		saved.Host = s.host
+		saved.Valid = true
+		saved.ModifyTime = now
+		saved.Mtime = now.Unix()
+		return s.leaders.put(q, saved)
+	})
+}
+
+// IsLeader whether it is leader node
+func (s *sqliteStore) IsLeader(key string) bool {
+	count, err := query(s, nil, func(q querier) (uint64, error) {
+		return s.leaders.count(q, "id = ? AND name = ? AND valid = 1", key, s.host)
+	})
+	return err == nil && count > 0
+}
+
+// ListLeaderElections list all leaderelection
+func (s *sqliteStore) ListLeaderElections() ([]*model.LeaderElection, error) {
+	return query(s, nil, func(q querier) ([]*model.LeaderElection, error) {
+		return s.leaders.find(q, "1 = 1 ORDER BY id")
+	})
+}
+
+// ReleaseLeaderElection force release leader status
+func (s *sqliteStore) ReleaseLeaderElection(key string) error {
+	return s.update(nil, func(q querier) error {
+		saved, err := s.leaders.get(q, key)
+		if err != nil || saved == nil || !saved.Valid {
+			return err
+		}
+		now := s.now()
+		saved.Valid = false
+		saved.ModifyTime = now
+		saved.Mtime = now.Unix()
+		return s.leaders.put(q, saved)
+	})
+}
+
+// BatchCleanDeletedInstances batch clean soft deleted instances which mtime time out
+func (s *sqliteStore) BatchCleanDeletedInstances(timeout time.Duration, batchSize uint32) (uint32, error) {
+	return s.cleanDeleted(s.instances.name, "", timeout, batchSize)
+}
+
+// GetUnHealthyInstances get unhealthy instances which mtime time out
+func (s *sqliteStore) GetUnHealthyInstances(timeout time.Duration, limit uint32) ([]string, error) {
+	deadline := s.now().Add(-timeout)
+	instances, err := query(s, nil, func(q querier) ([]*model.Instance, error) {
+		return s.instances.valid(q, "mtime <= ? ORDER BY id", deadline.UnixNano())
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]string, 0)
+	for _, ins := range instances {
+		if uint32(len(ret)) >= limit {
+			break
+		}
+		if !ins.Proto.GetEnableHealthCheck().GetValue() || ins.Proto.GetHealthy().GetValue() {
+			continue
+		}
+		ret = append(ret, instanceID(ins))
+	}
+	return ret, nil
+}
+
+// BatchCleanDeletedClients batch clean soft deleted clients which mtime time out
+func (s *sqliteStore) BatchCleanDeletedClients(timeout time.Duration, batchSize uint32) (uint32, error) {
+	return s.cleanDeleted(s.clients.name, "", timeout, batchSize)
+}
+
+// cleanDeleted 物理删除 table 中软删除超过 timeout 的数据，最多删除 batchSize 条，filter 不为空时只删除同时满足 filter 的数据
+func (s *sqliteStore) cleanDeleted(table, filter string, timeout time.Duration, batchSize uint32) (uint32, error) {
+	if filter != "" {
+		filter = " AND " + filter
+	}
+	deadline := s.now().Add(-timeout)
+	var count int64
+	err := s.update(nil, func(q querier) error {
+		result, err := q.ExecContext(context.Background(), "DELETE FROM "+table+" WHERE id IN (SELECT id FROM "+table+
+			" WHERE valid = 0 AND mtime <= ?"+filter+" LIMIT ?)", deadline.UnixNano(), batchSize)
+		if err != nil {
+			return err
+		}
+		count, err = result.RowsAffected()
+		return err
+	})
+	return uint32(count), err
+}
+
+// AcquireLock 获取分布式锁
+func (s *sqliteStore) AcquireLock(key string, owner string, ttl time.Duration) (*model.DistributedLock, error) {
+	return s.updateLock(key, func(saved *model.DistributedLock, now time.Time) (*model.DistributedLock, error) {
+		return store.AcquireLease(saved, key, owner, ttl, now)
+	})
+}
+
+// RenewLock 续约分布式锁
+func (s *sqliteStore) RenewLock(key string, owner string, ttl time.Duration) (*model.DistributedLock, error) {
+	return s.updateLock(key, func(saved *model.DistributedLock, now time.Time) (*model.DistributedLock, error) {
+		return store.RenewLease(saved, key, owner, ttl, now)
+	})
+}
+
+// ReleaseLock 释放分布式锁
+func (s *sqliteStore) ReleaseLock(key string, owner string) error {
+	_, err := s.updateLock(key, func(saved *model.DistributedLock, now time.Time) (*model.DistributedLock, error) {
+		return store.ReleaseLease(saved, key, owner, now)
+	})
+	return err
+}
+
+// ListLocks 列出所有被持有并且没有过期的锁
+func (s *sqliteStore) ListLocks() ([]*model.DistributedLock, error) {
+	locks, err := query(s, nil, func(q querier) ([]*model.DistributedLock, error) {
+		return s.distLocks.valid(q, "1 = 1 ORDER BY id")
+	})
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	ret := make([]*model.DistributedLock, 0, len(locks))
+	for _, lock := range locks {
+		if lock.Held(now) {
+			ret = append(ret, lock)
+		}
+	}
+	return ret, nil
+}
+
+// updateLock 在写事务中根据锁当前的状态计算新的状态并保存，next 返回 nil 时不做修改
+func (s *sqliteStore) updateLock(key string,
+	next func(saved *model.DistributedLock, now time.Time) (*model.DistributedLock, error)) (*model.DistributedLock, error) {
+	var lock *model.DistributedLock
+	err := s.update(nil, func(q querier) error {
+		saved, err := s.distLocks.get(q, key)
+		if err != nil {
+			return err
+		}
+		if lock, err = next(saved, s.now()); err != nil || lock == nil {
+			return err
+		}
+		return s.distLocks.put(q, lock)
+	})
+	return lock, err
+}
@@ -0,0 +1,607 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqlite
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// AddUser Create a user
+func (s *sqliteStore) AddUser(user *model.User) error {
+	if user == nil || user.ID == "" || user.Name == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "add user missing some params")
+	}
+	return s.update(nil, func(q querier) error {
+		old, err := s.users.get(q, user.ID)
+		if err != nil {
+			return err
+		}
+		if old != nil && old.Valid {
+			return store.NewStatusError(store.DuplicateEntryErr, "user already exists: "+user.ID)
+		}
+		count, err := s.users.count(q, "name = ? AND parent = ? AND valid = 1", user.Name, user.Owner)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return store.NewStatusError(store.DuplicateEntryErr, "user name already exists: "+user.Name)
+		}
+		now := s.now()
+		saved := *user
+		saved.Valid = true
+		saved.CreateTime = now
+		saved.ModifyTime = now
+		return s.users.put(q, &saved)
+	})
+}
+
+// UpdateUser Update user
+func (s *sqliteStore) UpdateUser(user *model.User) error {
+	if user == nil || user.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "update user missing id")
+	}
+	return s.update(nil, func(q querier) error {
+		saved, err := s.users.get(q, user.ID)
+		if err != nil {
+			return err
+		}
+		if saved == nil || !saved.Valid {
+			return store.NewStatusError(store.NotFoundUser, "user not found: "+user.ID)
+		}
+		saved.Password = user.Password
+		saved.Mobile = user.Mobile
+		saved.Email = user.Email
+		saved.Token = user.Token
+		saved.TokenEnable = user.TokenEnable
+		saved.Comment = user.Comment
+		saved.ModifyTime = s.now()
+		return s.users.put(q, saved)
+	})
+}
+
+// DeleteUser delete users, the user will also be removed from all user groups
+func (s *sqliteStore) DeleteUser(user *model.User) error {
+	if user == nil || user.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "delete user missing id")
+	}
+	return s.update(nil, func(q querier) error {
+		saved, err := s.users.get(q, user.ID)
+		if err != nil || saved == nil || !saved.Valid {
+			return err
+		}
+		now := s.now()
+		saved.Valid = false
+		saved.ModifyTime = now
+		if err := s.users.put(q, saved); err != nil {
+			return err
+		}
+		groups, err := s.groups.valid(q, "")
+		if err != nil {
+			return err
+		}
+		for _, group := range groups {
+			if !containsString(group.UserIds, user.ID) {
+				continue
+			}
+			group.UserIds = removeStrings(group.UserIds, []string{user.ID})
+			group.ModifyTime = now
+			if err := s.groups.put(q, group); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetSubCount Number of getting a child account
+func (s *sqliteStore) GetSubCount(user *model.User) (uint32, error) {
+	if user == nil {
+		return 0, store.NewStatusError(store.EmptyParamsErr, "get sub count missing user")
+	}
+	count, err := query(s, nil, func(q querier) (uint64, error) {
+		return s.users.count(q, "parent = ? AND valid = 1", user.ID)
+	})
+	return uint32(count), err
+}
+
+// GetUser Obtain user
+func (s *sqliteStore) GetUser(id string) (*model.User, error) {
+	return query(s, nil, func(q querier) (*model.User, error) {
+		return s.users.first(q, "id = ? AND valid = 1", id)
+	})
+}
+
+// GetUserByName Get a unique user according to Name + Owner
+func (s *sqliteStore) GetUserByName(name, ownerId string) (*model.User, error) {
+	return query(s, nil, func(q querier) (*model.User, error) {
+		return s.users.first(q, "name = ? AND parent = ? AND valid = 1", name, ownerId)
+	})
+}
+
+// GetUserByIds Get users according to USER IDS batch
+func (s *sqliteStore) GetUserByIds(ids []string) ([]*model.User, error) {
+	if len(ids) == 0 {
+		return []*model.User{}, nil
+	}
+	in, args := inClause(ids)
+	users, err := query(s, nil, func(q querier) ([]*model.User, error) {
+		return s.users.valid(q, "id IN "+in, args...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*model.User, len(users))
+	for _, item := range users {
+		byID[item.ID] = item
+	}
+	ret := make([]*model.User, 0, len(ids))
+	for _, id := range ids {
+		if item, ok := byID[id]; ok {
+			ret = append(ret, item)
+		}
+	}
+	return ret, nil
+}
+
+// GetUsers Query user list, group_id 用于查询用户组下的用户
+func (s *sqliteStore) GetUsers(filters map[string]string, offset uint32, limit uint32) (uint32, []*model.User, error) {
+	var (
+		users   []*model.User
+		members []string
+	)
+	err := s.view(nil, func(q querier) error {
+		if groupID, ok := filters["group_id"]; ok {
+			group, err := s.groups.first(q, "id = ? AND valid = 1", groupID)
+			if err != nil {
+				return err
+			}
+			if group != nil {
+				members = group.UserIds
+			}
+		}
+		var err error
+		users, err = s.users.valid(q, "")
+		return err
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	ret := make([]*model.User, 0, len(users))
+	for _, item := range users {
+		if _, ok := filters["group_id"]; ok && !containsString(members, item.ID) {
+			continue
+		}
+		if matchFilters(filters, userGetters(item)) {
+			ret = append(ret, item)
+		}
+	}
+	sortByModifyTime(ret, func(u *model.User) time.Time { return u.ModifyTime },
+		func(u *model.User) string { return u.ID })
+	return uint32(len(ret)), paginate(ret, offset, limit), nil
+}
+
+// GetUsersForCache Used to refresh user cache
+func (s *sqliteStore) GetUsersForCache(mtime time.Time, firstUpdate bool) ([]*model.User, error) {
+	return query(s, nil, func(q querier) ([]*model.User, error) {
+		return s.users.since(q, mtime, firstUpdate)
+	})
+}
+
+// AddGroup Add a user group
+func (s *sqliteStore) AddGroup(group *model.UserGroup) error {
+	if group == nil || group.ID == "" || group.Name == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "add user group missing some params")
+	}
+	return s.update(nil, func(q querier) error {
+		old, err := s.groups.get(q, group.ID)
+		if err != nil {
+			return err
+		}
+		if old != nil && old.Valid {
+			return store.NewStatusError(store.DuplicateEntryErr, "user group already exists: "+group.ID)
+		}
+		now := s.now()
+		saved := *group
+		saved.Valid = true
+		saved.CreateTime = now
+		saved.ModifyTime = now
+		return s.groups.put(q, &saved)
+	})
+}
+
+// UpdateGroup Update user group
+func (s *sqliteStore) UpdateGroup(group *model.ModifyUserGroup) error {
+	if group == nil || group.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "update user group missing id")
+	}
+	return s.update(nil, func(q querier) error {
+		saved, err := s.groups.get(q, group.ID)
+		if err != nil {
+			return err
+		}
+		if saved == nil || !saved.Valid {
+			return store.NewStatusError(store.NotFoundUserGroup, "user group not found: "+group.ID)
+		}
+		saved.Token = group.Token
+		saved.TokenEnable = group.TokenEnable
+		saved.Comment = group.Comment
+		saved.UserIds = removeStrings(saved.UserIds, group.RemoveUserIds)
+		for _, id := range group.AddUserIds {
+			if !containsString(saved.UserIds, id) {
+				saved.UserIds = append(saved.UserIds, id)
+			}
+		}
+		saved.ModifyTime = s.now()
+		return s.groups.put(q, saved)
+	})
+}
+
+// DeleteGroup Delete user group
+func (s *sqliteStore) DeleteGroup(group *model.UserGroup) error {
+	if group == nil || group.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "delete user group missing id")
+	}
+	return s.update(nil, func(q querier) error {
+		saved, err := s.groups.get(q, group.ID)
+		if err != nil || saved == nil || !saved.Valid {
+			return err
+		}
+		saved.Valid = false
+		saved.ModifyTime = s.now()
+		return s.groups.put(q, saved)
+	})
+}
+
+// GetGroup Get user group details
+func (s *sqliteStore) GetGroup(id string) (*model.UserGroup, error) {
+	return query(s, nil, func(q querier) (*model.UserGroup, error) {
+		return s.groups.first(q, "id = ? AND valid = 1", id)
+	})
+}
+
+// GetGroupByName Get user groups according to Name and Owner
+func (s *sqliteStore) GetGroupByName(name, owner string) (*model.UserGroup, error) {
+	return query(s, nil, func(q querier) (*model.UserGroup, error) {
+		return s.groups.first(q, "name = ? AND parent = ? AND valid = 1", name, owner)
+	})
+}
+
+// GetGroups Get a list of user groups, user_id 用于查询用户所在的用户组
+func (s *sqliteStore) GetGroups(filters map[string]string, offset uint32, limit uint32) (
+	uint32, []*model.UserGroup, error) {
+	groups, err := query(s, nil, func(q querier) ([]*model.UserGroup, error) {
+		return s.groups.valid(q, "")
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	ret := make([]*model.UserGroup, 0, len(groups))
+	for _, item := range groups {
+		item := item
+		if userID, ok := filters["user_id"]; ok && !containsString(item.UserIds, userID) {
+			continue
+		}
+		getters := map[string]func() string{
+			"id":    func() string { return item.ID },
+			"name":  func() string { return item.Name },
+			"owner": func() string { return item.Owner },
+		}
+		if matchFilters(filters, getters) {
+			ret = append(ret, item)
+		}
+	}
+	sortByModifyTime(ret, func(g *model.UserGroup) time.Time { return g.ModifyTime },
+		func(g *model.UserGroup) string { return g.ID })
+	return uint32(len(ret)), paginate(ret, offset, limit), nil
+}
+
+// GetGroupsForCache Refresh of getting user groups for cache
+func (s *sqliteStore) GetGroupsForCache(mtime time.Time, firstUpdate bool) ([]*model.UserGroup, error) {
+	return query(s, nil, func(q querier) ([]*model.UserGroup, error) {
+		return s.groups.since(q, mtime, firstUpdate)
+	})
+}
+
+// AddStrategy Create authentication strategy
+func (s *sqliteStore) AddStrategy(strategy *model.StrategyDetail) error {
+	if strategy == nil || strategy.ID == "" || strategy.Name == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "add strategy missing some params")
+	}
+	return s.update(nil, func(q querier) error {
+		old, err := s.strategies.get(q, strategy.ID)
+		if err != nil {
+			return err
+		}
+		if old != nil && old.Valid {
+			return store.NewStatusError(store.DuplicateEntryErr, "strategy already exists: "+strategy.ID)
+		}
+		now := s.now()
+		saved := *strategy
+		saved.Principals = append([]model.Principal(nil), strategy.Principals...)
+		saved.Resources = append([]model.StrategyResource(nil), strategy.Resources...)
+		saved.Valid = true
+		saved.CreateTime = now
+		saved.ModifyTime = now
+		for i := range saved.Principals {
+			saved.Principals[i].StrategyID = saved.ID
+		}
+		for i := range saved.Resources {
+			saved.Resources[i].StrategyID = saved.ID
+		}
+		return s.strategies.put(q, &saved)
+	})
+}
+
+// UpdateStrategy Update authentication strategy
+func (s *sqliteStore) UpdateStrategy(strategy *model.ModifyStrategyDetail) error {
+	return s.updateStrategy(strategy, nil)
+}
+
+// updateStrategy 更新鉴权策略，expectRevision 不为空时只有当前的 Revision 与其一致才会更新
+func (s *sqliteStore) updateStrategy(strategy *model.ModifyStrategyDetail, expectRevision *string) error {
+	if strategy == nil || strategy.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "update strategy missing id")
+	}
+	return s.update(nil, func(q querier) error {
+		saved, err := s.strategies.get(q, strategy.ID)
+		if err != nil {
+			return err
+		}
+		if saved == nil || !saved.Valid {
+			return store.NewStatusError(store.NotFoundResource, "strategy not found: "+strategy.ID)
+		}
+		if err := checkRevision("strategy", strategy.ID, saved.Revision, expectRevision); err != nil {
+			return err
+		}
+		if strategy.Name != "" {
+			saved.Name = strategy.Name
+		}
+		saved.Action = strategy.Action
+		saved.Comment = strategy.Comment
+		saved.Principals = removePrincipals(saved.Principals, strategy.RemovePrincipals)
+		for _, item := range strategy.AddPrincipals {
+			item.StrategyID = saved.ID
+			if !containsPrincipal(saved.Principals, item) {
+				saved.Principals = append(saved.Principals, item)
+			}
+		}
+		saved.Resources = removeResources(saved.Resources, strategy.RemoveResources)
+		for _, item := range strategy.AddResources {
+			item.StrategyID = saved.ID
+			if !containsResource(saved.Resources, item) {
+				saved.Resources = append(saved.Resources, item)
+			}
+		}
+		if strategy.Revision != "" {
+			saved.Revision = strategy.Revision
+		}
+		saved.ModifyTime = s.now()
+		return s.strategies.put(q, saved)
+	})
+}
+
+// DeleteStrategy Delete authentication strategy
+func (s *sqliteStore) DeleteStrategy(id string) error {
+	return s.update(nil, func(q querier) error {
+		saved, err := s.strategies.get(q, id)
+		if err != nil || saved == nil || !saved.Valid {
+			return err
+		}
+		saved.Valid = false
+		saved.ModifyTime = s.now()
+		return s.strategies.put(q, saved)
+	})
+}
+
+// LooseAddStrategyResources 添加策略的资源，忽略已经存在的资源
+func (s *sqliteStore) LooseAddStrategyResources(resources []model.StrategyResource) error {
+	return s.update(nil, func(q querier) error {
+		now := s.now()
+		for _, item := range resources {
+			saved, err := s.strategies.first(q, "id = ? AND valid = 1", item.StrategyID)
+			if err != nil {
+				return err
+			}
+			if saved == nil || containsResource(saved.Resources, item) {
+				continue
+			}
+			saved.Resources = append(saved.Resources, item)
+			saved.ModifyTime = now
+			if err := s.strategies.put(q, saved); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// RemoveStrategyResources 清理所有策略中关联的对应资源
+func (s *sqliteStore) RemoveStrategyResources(resources []model.StrategyResource) error {
+	return s.update(nil, func(q querier) error {
+		strategies, err := s.strategies.find(q, "")
+		if err != nil {
+			return err
+		}
+		now := s.now()
+		for _, saved := range strategies {
+			remain := removeResources(saved.Resources, resources)
+			if len(remain) == len(saved.Resources) {
+				continue
+			}
+			saved.Resources = remain
+			saved.ModifyTime = now
+			if err := s.strategies.put(q, saved); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetStrategyResources Gets a Principal's corresponding resource ID data information
+func (s *sqliteStore) GetStrategyResources(principalId string, principalRole string) (
+	[]model.StrategyResource, error) {
+	strategies, err := query(s, nil, func(q querier) ([]*model.StrategyDetail, error) {
+		return s.strategies.valid(q, "")
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]model.StrategyResource, 0)
+	for _, saved := range strategies {
+		if hasPrincipal(saved.Principals, principalId, principalRole) {
+			ret = append(ret, saved.Resources...)
+		}
+	}
+	return ret, nil
+}
+
+// GetDefaultStrategyDetailByPrincipal Get a default policy for a Principal
+func (s *sqliteStore) GetDefaultStrategyDetailByPrincipal(principalId string,
+	principalType string) (*model.StrategyDetail, error) {
+	strategies, err := query(s, nil, func(q querier) ([]*model.StrategyDetail, error) {
+		return s.strategies.valid(q, "")
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, saved := range strategies {
+		if saved.Default && hasPrincipal(saved.Principals, principalId, principalType) {
+			return saved, nil
+		}
+	}
+	return nil, nil
+}
+
+// GetStrategyDetail Get strategy details
+func (s *sqliteStore) GetStrategyDetail(id string) (*model.StrategyDetail, error) {
+	return query(s, nil, func(q querier) (*model.StrategyDetail, error) {
+		return s.strategies.first(q, "id = ? AND valid = 1", id)
+	})
+}
+
+// GetStrategies Get a list of strategies
+func (s *sqliteStore) GetStrategies(filters map[string]string, offset uint32, limit uint32) (uint32,
+	[]*model.StrategyDetail, error) {
+	strategies, err := query(s, nil, func(q querier) ([]*model.StrategyDetail, error) {
+		return s.strategies.valid(q, "")
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	ret := make([]*model.StrategyDetail, 0, len(strategies))
+	for _, item := range strategies {
+		if principalID, ok := filters["principal_id"]; ok &&
+			!hasPrincipal(item.Principals, principalID, filters["principal_type"]) {
+			continue
+		}
+		if resID, ok := filters["res_id"]; ok && !hasResource(item.Resources, resID, filters["res_type"]) {
+			continue
+		}
+		if matchFilters(filters, strategyGetters(item)) {
+			ret = append(ret, item)
+		}
+	}
+	sortByModifyTime(ret, func(d *model.StrategyDetail) time.Time { return d.ModifyTime },
+		func(d *model.StrategyDetail) string { return d.ID })
+	return uint32(len(ret)), paginate(ret, offset, limit), nil
+}
+
+// GetStrategyDetailsForCache Used to refresh policy cache
+func (s *sqliteStore) GetStrategyDetailsForCache(mtime time.Time, firstUpdate bool) (
+	[]*model.StrategyDetail, error) {
+	return query(s, nil, func(q querier) ([]*model.StrategyDetail, error) {
+		return s.strategies.since(q, mtime, firstUpdate)
+	})
+}
+
+// hasPrincipal principalRole 为空时只比较 principalId
+func hasPrincipal(principals []model.Principal, principalID, principalRole string) bool {
+	for _, item := range principals {
+		if item.PrincipalID == principalID && (principalRole == "" || item.PrincipalRole == principalRole) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasResource resType 为空时只比较 resID
+func hasResource(resources []model.StrategyResource, resID, resType string) bool {
+	for _, item := range resources {
+		if item.ResID == resID && (resType == "" || strconv.Itoa(int(item.ResType)) == resType) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsPrincipal(principals []model.Principal, target model.Principal) bool {
+	return hasPrincipal(principals, target.PrincipalID, target.PrincipalRole)
+}
+
+func containsResource(resources []model.StrategyResource, target model.StrategyResource) bool {
+	for _, item := range resources {
+		if item.ResType == target.ResType && item.ResID == target.ResID {
+			return true
+		}
+	}
+	return false
+}
+
+func removePrincipals(principals []model.Principal, removed []model.Principal) []model.Principal {
+	ret := make([]model.Principal, 0, len(principals))
+	for _, item := range principals {
+		if !containsPrincipal(removed, item) {
+			ret = append(ret, item)
+		}
+	}
+	return ret
+}
+
+func removeResources(resources []model.StrategyResource, removed []model.StrategyResource) []model.StrategyResource {
+	ret := make([]model.StrategyResource, 0, len(resources))
+	for _, item := range resources {
+		if !containsResource(removed, item) {
+			ret = append(ret, item)
+		}
+	}
+	return ret
+}
+
+func userGetters(item *model.User) map[string]func() string {
+	return map[string]func() string{
+		"id":     func() string { return item.ID },
+		"name":   func() string { return item.Name },
+		"owner":  func() string { return item.Owner },
+		"source": func() string { return item.Source },
+		"type":   func() string { return item.Type },
+	}
+}
+
+func strategyGetters(item *model.StrategyDetail) map[string]func() string {
+	return map[string]func() string{
+		"id":      func() string { return item.ID },
+		"name":    func() string { return item.Name },
+		"owner":   func() string { return item.Owner },
+		"default": func() string { return strconv.FormatBool(item.Default) },
+	}
+}
@@ -0,0 +1,132 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqlite
+
+import (
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// BatchAddClients insert the client info, existing clients will be overwritten
+func (s *sqliteStore) BatchAddClients(clients []*model.Client) error {
+	for _, client := range clients {
+		if client == nil || client.Proto.GetId().GetValue() == "" {
+			return store.NewStatusError(store.EmptyParamsErr, "add client missing id")
+		}
+	}
+	return s.update(nil, func(q querier) error {
+		now := s.now()
+		for _, client := range clients {
+			old, err := s.clients.get(q, client.Proto.GetId().GetValue())
+			if err != nil {
+				return err
+			}
+			saved := *client
+			saved.Valid = true
+			saved.ModifyTime = now
+			if old != nil && old.Valid {
+				saved.CreateTime = old.CreateTime
+			} else {
+				saved.CreateTime = now
+			}
+			if err := s.clients.put(q, &saved); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// BatchDeleteClients delete the client info, only mark valid as false
+func (s *sqliteStore) BatchDeleteClients(ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	in, args := inClause(ids)
+	return s.update(nil, func(q querier) error {
+		clients, err := s.clients.valid(q, "id IN "+in, args...)
+		if err != nil {
+			return err
+		}
+		now := s.now()
+		for _, client := range clients {
+			client.Valid = false
+			client.ModifyTime = now
+			if err := s.clients.put(q, client); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetMoreClients 根据mtime获取增量clients
+func (s *sqliteStore) GetMoreClients(mtime time.Time, firstUpdate bool) (map[string]*model.Client, error) {
+	clients, err := query(s, nil, func(q querier) ([]*model.Client, error) {
+		return s.clients.since(q, mtime, firstUpdate)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := make(map[string]*model.Client, len(clients))
+	for _, client := range clients {
+		ret[client.Proto.GetId().GetValue()] = client
+	}
+	return ret, nil
+}
+
+// CleanGrayResource 删除灰度资源，实际是把valid置为false
+func (s *sqliteStore) CleanGrayResource(tx store.Tx, data *model.GrayResource) error {
+	if data == nil || data.Name == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "clean gray resource missing name")
+	}
+	return s.update(tx, func(q querier) error {
+		saved, err := s.grayResources.get(q, data.Name)
+		if err != nil || saved == nil || !saved.Valid {
+			return err
+		}
+		saved.Valid = false
+		saved.ModifyBy = data.ModifyBy
+		saved.ModifyTime = s.now()
+		return s.grayResources.put(q, saved)
+	})
+}
+
+// CreateGrayResourceTx 创建灰度资源，已存在的同名资源会被覆盖
+func (s *sqliteStore) CreateGrayResourceTx(tx store.Tx, data *model.GrayResource) error {
+	if data == nil || data.Name == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "create gray resource missing name")
+	}
+	return s.update(tx, func(q querier) error {
+		now := s.now()
+		saved := *data
+		saved.Valid = true
+		saved.CreateTime = now
+		saved.ModifyTime = now
+		return s.grayResources.put(q, &saved)
+	})
+}
+
+// GetMoreGrayResouces 获取增量的灰度资源
+func (s *sqliteStore) GetMoreGrayResouces(firstUpdate bool, mtime time.Time) ([]*model.GrayResource, error) {
+	return query(s, nil, func(q querier) ([]*model.GrayResource, error) {
+		return s.grayResources.since(q, mtime, firstUpdate)
+	})
+}
@@ -0,0 +1,169 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqlite
+
+import (
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// LockConfigFile 加锁配置文件，SQLite 的写事务本身是串行的，这里只返回有效的配置文件
+func (s *sqliteStore) LockConfigFile(tx store.Tx, file *model.ConfigFileKey) (*model.ConfigFile, error) {
+	if file == nil {
+		return nil, store.NewStatusError(store.EmptyParamsErr, "lock config file missing file key")
+	}
+	return s.GetConfigFileTx(tx, file.Namespace, file.Group, file.Name)
+}
+
+// CreateConfigFileTx 创建配置文件
+func (s *sqliteStore) CreateConfigFileTx(tx store.Tx, file *model.ConfigFile) error {
+	if file == nil || file.Namespace == "" || file.Group == "" || file.Name == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "create config file missing some params")
+	}
+	return s.update(tx, func(q querier) error {
+		key := configFileKey(file.Namespace, file.Group, file.Name)
+		old, err := s.configFiles.get(q, key)
+		if err != nil {
+			return err
+		}
+		if old != nil && old.Valid {
+			return store.NewStatusError(store.DuplicateEntryErr, "config file already exists: "+key)
+		}
+		saved := *file
+		if saved.Id, err = s.nextID(q); err != nil {
+			return err
+		}
+		now := s.now()
+		saved.Valid = true
+		saved.CreateTime = now
+		saved.ModifyTime = now
+		return s.configFiles.put(q, &saved)
+	})
+}
+
+// GetConfigFile 获取配置文件
+func (s *sqliteStore) GetConfigFile(namespace, group, name string) (*model.ConfigFile, error) {
+	return s.GetConfigFileTx(nil, namespace, group, name)
+}
+
+// GetConfigFileTx 获取配置文件
+func (s *sqliteStore) GetConfigFileTx(tx store.Tx, namespace, group, name string) (*model.ConfigFile, error) {
+	return query(s, tx, func(q querier) (*model.ConfigFile, error) {
+		return s.configFiles.first(q, "id = ? AND valid = 1", configFileKey(namespace, group, name))
+	})
+}
+
+// QueryConfigFiles 翻页查询配置文件，group、name可为模糊匹配
+func (s *sqliteStore) QueryConfigFiles(filter map[string]string, offset uint32, limit uint32) (
+	uint32, []*model.ConfigFile, error) {
+	files, err := query(s, nil, func(q querier) ([]*model.ConfigFile, error) {
+		return s.configFiles.valid(q, "")
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	ret := make([]*model.ConfigFile, 0, len(files))
+	for _, item := range files {
+		if matchFilters(filter, configFileGetters(item)) {
+			ret = append(ret, item)
+		}
+	}
+	sortByModifyTime(ret, func(f *model.ConfigFile) time.Time { return f.ModifyTime },
+		func(f *model.ConfigFile) string { return configFileKey(f.Namespace, f.Group, f.Name) })
+	return uint32(len(ret)), paginate(ret, offset, limit), nil
+}
+
+// UpdateConfigFileTx 更新配置文件
+func (s *sqliteStore) UpdateConfigFileTx(tx store.Tx, file *model.ConfigFile) error {
+	if file == nil || file.Namespace == "" || file.Group == "" || file.Name == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "update config file missing some params")
+	}
+	return s.update(tx, func(q querier) error {
+		key := configFileKey(file.Namespace, file.Group, file.Name)
+		saved, err := s.configFiles.get(q, key)
+		if err != nil {
+			return err
+		}
+		if saved == nil || !saved.Valid {
+			return store.NewStatusError(store.AffectedRowsNotMatch, "config file not found: "+key)
+		}
+		saved.OriginContent = file.OriginContent
+		saved.Content = file.Content
+		saved.Comment = file.Comment
+		saved.Format = file.Format
+		saved.Metadata = file.Metadata
+		saved.Encrypt = file.Encrypt
+		saved.EncryptAlgo = file.EncryptAlgo
+		saved.Status = file.Status
+		saved.ModifyBy = file.ModifyBy
+		saved.ModifyTime = s.now()
+		return s.configFiles.put(q, saved)
+	})
+}
+
+// DeleteConfigFileTx 删除配置文件，实际是把valid置为false
+func (s *sqliteStore) DeleteConfigFileTx(tx store.Tx, namespace, group, name string) error {
+	return s.update(tx, func(q querier) error {
+		saved, err := s.configFiles.get(q, configFileKey(namespace, group, name))
+		if err != nil || saved == nil || !saved.Valid {
+			return err
+		}
+		saved.Valid = false
+		saved.ModifyTime = s.now()
+		return s.configFiles.put(q, saved)
+	})
+}
+
+// CountConfigFiles 获取一个配置文件组下的文件数量
+func (s *sqliteStore) CountConfigFiles(namespace, group string) (uint64, error) {
+	return query(s, nil, func(q querier) (uint64, error) {
+		return s.configFiles.count(q, "namespace = ? AND parent = ? AND valid = 1", namespace, group)
+	})
+}
+
+// CountConfigFileEachGroup 统计 namespace.group 下的配置文件数量
+func (s *sqliteStore) CountConfigFileEachGroup() (map[string]map[string]int64, error) {
+	files, err := query(s, nil, func(q querier) ([]*model.ConfigFile, error) {
+		return s.configFiles.valid(q, "")
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := make(map[string]map[string]int64)
+	for _, item := range files {
+		if _, ok := ret[item.Namespace]; !ok {
+			ret[item.Namespace] = map[string]int64{}
+		}
+		ret[item.Namespace][item.Group]++
+	}
+	return ret, nil
+}
+
+func configFileKey(namespace, group, name string) string {
+	return model.ConfigFileKey{Namespace: namespace, Group: group, Name: name}.String()
+}
+
+func configFileGetters(item *model.ConfigFile) map[string]func() string {
+	return map[string]func() string{
+		"namespace": func() string { return item.Namespace },
+		"group":     func() string { return item.Group },
+		"name":      func() string { return item.Name },
+	}
+}
@@ -0,0 +1,119 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqlite
+
+import (
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// CreateConfigFileGroup 创建配置文件组
+func (s *sqliteStore) CreateConfigFileGroup(fileGroup *model.ConfigFileGroup) (*model.ConfigFileGroup, error) {
+	if fileGroup == nil || fileGroup.Namespace == "" || fileGroup.Name == "" {
+		return nil, store.NewStatusError(store.EmptyParamsErr, "create config file group missing some params")
+	}
+	saved := *fileGroup
+	err := s.update(nil, func(q querier) error {
+		key := configGroupKey(fileGroup.Namespace, fileGroup.Name)
+		old, err := s.configGroups.get(q, key)
+		if err != nil {
+			return err
+		}
+		if old != nil && old.Valid {
+			return store.NewStatusError(store.DuplicateEntryErr, "config file group already exists: "+key)
+		}
+		if saved.Id, err = s.nextID(q); err != nil {
+			return err
+		}
+		now := s.now()
+		saved.Valid = true
+		saved.CreateTime = now
+		saved.ModifyTime = now
+		return s.configGroups.put(q, &saved)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+// UpdateConfigFileGroup 更新配置文件组
+func (s *sqliteStore) UpdateConfigFileGroup(fileGroup *model.ConfigFileGroup) error {
+	if fileGroup == nil || fileGroup.Namespace == "" || fileGroup.Name == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "update config file group missing some params")
+	}
+	return s.update(nil, func(q querier) error {
+		key := configGroupKey(fileGroup.Namespace, fileGroup.Name)
+		saved, err := s.configGroups.get(q, key)
+		if err != nil {
+			return err
+		}
+		if saved == nil || !saved.Valid {
+			return store.NewStatusError(store.AffectedRowsNotMatch, "config file group not found: "+key)
+		}
+		saved.Comment = fileGroup.Comment
+		saved.Owner = fileGroup.Owner
+		saved.Business = fileGroup.Business
+		saved.Department = fileGroup.Department
+		saved.Metadata = fileGroup.Metadata
+		saved.ModifyBy = fileGroup.ModifyBy
+		saved.Revision = fileGroup.Revision
+		saved.ModifyTime = s.now()
+		return s.configGroups.put(q, saved)
+	})
+}
+
+// GetConfigFileGroup 获取单个配置文件组
+func (s *sqliteStore) GetConfigFileGroup(namespace, name string) (*model.ConfigFileGroup, error) {
+	return query(s, nil, func(q querier) (*model.ConfigFileGroup, error) {
+		return s.configGroups.first(q, "id = ? AND valid = 1", configGroupKey(namespace, name))
+	})
+}
+
+// DeleteConfigFileGroup 删除配置文件组，实际是把valid置为false
+func (s *sqliteStore) DeleteConfigFileGroup(namespace, name string) error {
+	return s.update(nil, func(q querier) error {
+		saved, err := s.configGroups.get(q, configGroupKey(namespace, name))
+		if err != nil || saved == nil || !saved.Valid {
+			return err
+		}
+		saved.Valid = false
+		saved.ModifyTime = s.now()
+		return s.configGroups.put(q, saved)
+	})
+}
+
+// GetMoreConfigGroup 获取增量的配置分组
+func (s *sqliteStore) GetMoreConfigGroup(firstUpdate bool, mtime time.Time) ([]*model.ConfigFileGroup, error) {
+	return query(s, nil, func(q querier) ([]*model.ConfigFileGroup, error) {
+		return s.configGroups.since(q, mtime, firstUpdate)
+	})
+}
+
+// CountConfigGroups 获取一个命名空间下的配置分组数量
+func (s *sqliteStore) CountConfigGroups(namespace string) (uint64, error) {
+	return query(s, nil, func(q querier) (uint64, error) {
+		return s.configGroups.count(q, "namespace = ? AND valid = 1", namespace)
+	})
+}
+
+func configGroupKey(namespace, name string) string {
+	return namespace + "@" + name
+}
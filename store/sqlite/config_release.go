@@ -0,0 +1,378 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqlite
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// GetConfigFileActiveRelease 获取配置文件处于 Active 的全量发布记录
+func (s *sqliteStore) GetConfigFileActiveRelease(file *model.ConfigFileKey) (*model.ConfigFileRelease, error) {
+	return s.GetConfigFileActiveReleaseTx(nil, file)
+}
+
+// GetConfigFileActiveReleaseTx 获取配置文件处于 Active 的全量发布记录
+func (s *sqliteStore) GetConfigFileActiveReleaseTx(tx store.Tx, file *model.ConfigFileKey) (
+	*model.ConfigFileRelease, error) {
+	return s.getActiveRelease(tx, file, model.ReleaseTypeFull)
+}
+
+// GetConfigFileBetaReleaseTx 获取灰度发布的配置文件信息
+func (s *sqliteStore) GetConfigFileBetaReleaseTx(tx store.Tx, file *model.ConfigFileKey) (
+	*model.ConfigFileRelease, error) {
+	return s.getActiveRelease(tx, file, model.ReleaseTypeGray)
+}
+
+func (s *sqliteStore) getActiveRelease(tx store.Tx, file *model.ConfigFileKey, releaseType model.ReleaseType) (
+	*model.ConfigFileRelease, error) {
+	if file == nil {
+		return nil, store.NewStatusError(store.EmptyParamsErr, "get active release missing file key")
+	}
+	return query(s, tx, func(q querier) (*model.ConfigFileRelease, error) {
+		releases, err := s.configReleases.valid(q, "namespace = ? AND parent = ? AND name = ?",
+			file.Namespace, file.Group, file.Name)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range releases {
+			if item.Active && item.ReleaseType == releaseType {
+				return item, nil
+			}
+		}
+		return nil, nil
+	})
+}
+
+// CreateConfigFileReleaseTx 创建配置文件发布，新的发布会处于 Active 状态，并且版本号递增，
+// 同一个配置文件同一类型的其他发布会被置为失效
+func (s *sqliteStore) CreateConfigFileReleaseTx(tx store.Tx, fileRelease *model.ConfigFileRelease) error {
+	if fileRelease == nil || fileRelease.SimpleConfigFileRelease == nil ||
+		fileRelease.ConfigFileReleaseKey == nil || fileRelease.Name == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "create config file release missing some params")
+	}
+	return s.update(tx, func(q querier) error {
+		key := configReleaseKey(fileRelease.ConfigFileReleaseKey)
+		old, err := s.configReleases.get(q, key)
+		if err != nil {
+			return err
+		}
+		if old != nil && old.Valid {
+			return store.NewStatusError(store.DuplicateEntryErr, "config file release already exists: "+key)
+		}
+		now := s.now()
+		maxVersion, err := s.inactiveReleases(q, fileRelease.ConfigFileReleaseKey, now)
+		if err != nil {
+			return err
+		}
+		saved := copyConfigFileRelease(fileRelease)
+		if saved.Id, err = s.nextID(q); err != nil {
+			return err
+		}
+		saved.Version = maxVersion + 1
+		saved.Active = true
+		saved.Valid = true
+		saved.CreateTime = now
+		saved.ModifyTime = now
+		return s.configReleases.put(q, saved)
+	})
+}
+
+// GetConfigFileRelease 获取配置文件发布内容，只获取 flag=0 的记录
+func (s *sqliteStore) GetConfigFileRelease(req *model.ConfigFileReleaseKey) (*model.ConfigFileRelease, error) {
+	return s.GetConfigFileReleaseTx(nil, req)
+}
+
+// GetConfigFileReleaseTx 在已开启的事务中获取配置文件发布内容，只获取 flag=0 的记录
+func (s *sqliteStore) GetConfigFileReleaseTx(tx store.Tx, req *model.ConfigFileReleaseKey) (
+	*model.ConfigFileRelease, error) {
+	if req == nil {
+		return nil, store.NewStatusError(store.EmptyParamsErr, "get config file release missing key")
+	}
+	return query(s, tx, func(q querier) (*model.ConfigFileRelease, error) {
+		return s.configReleases.first(q, "id = ? AND valid = 1", configReleaseKey(req))
+	})
+}
+
+// DeleteConfigFileReleaseTx 删除配置文件发布内容，实际是把valid置为false
+func (s *sqliteStore) DeleteConfigFileReleaseTx(tx store.Tx, data *model.ConfigFileReleaseKey) error {
+	if data == nil {
+		return store.NewStatusError(store.EmptyParamsErr, "delete config file release missing key")
+	}
+	return s.update(tx, func(q querier) error {
+		saved, err := s.configReleases.first(q, "id = ? AND valid = 1", configReleaseKey(data))
+		if err != nil || saved == nil {
+			return err
+		}
+		saved.Valid = false
+		saved.Active = false
+		saved.ModifyTime = s.now()
+		return s.configReleases.put(q, saved)
+	})
+}
+
+// ActiveConfigFileReleaseTx 指定激活发布的配置文件，同一个配置文件同一类型的其他发布会被置为失效
+func (s *sqliteStore) ActiveConfigFileReleaseTx(tx store.Tx, release *model.ConfigFileRelease) error {
+	if release == nil || release.SimpleConfigFileRelease == nil || release.ConfigFileReleaseKey == nil {
+		return store.NewStatusError(store.EmptyParamsErr, "active config file release missing key")
+	}
+	return s.update(tx, func(q querier) error {
+		key := configReleaseKey(release.ConfigFileReleaseKey)
+		saved, err := s.configReleases.first(q, "id = ? AND valid = 1", key)
+		if err != nil {
+			return err
+		}
+		if saved == nil {
+			return store.NewStatusError(store.AffectedRowsNotMatch, "config file release not found: "+key)
+		}
+		now := s.now()
+		maxVersion, err := s.inactiveReleases(q, saved.ConfigFileReleaseKey, now)
+		if err != nil {
+			return err
+		}
+		saved.Active = true
+		saved.Version = maxVersion + 1
+		saved.ModifyBy = release.ModifyBy
+		saved.ReleaseDescription = release.ReleaseDescription
+		saved.ModifyTime = now
+		return s.configReleases.put(q, saved)
+	})
+}
+
+// InactiveConfigFileReleaseTx 指定失效发布的配置文件
+func (s *sqliteStore) InactiveConfigFileReleaseTx(tx store.Tx, release *model.ConfigFileRelease) error {
+	if release == nil || release.SimpleConfigFileRelease == nil || release.ConfigFileReleaseKey == nil {
+		return store.NewStatusError(store.EmptyParamsErr, "inactive config file release missing key")
+	}
+	return s.update(tx, func(q querier) error {
+		saved, err := s.configReleases.first(q, "id = ? AND valid = 1", configReleaseKey(release.ConfigFileReleaseKey))
+		if err != nil || saved == nil {
+			return err
+		}
+		saved.Active = false
+		saved.ModifyBy = release.ModifyBy
+		saved.ModifyTime = s.now()
+		return s.configReleases.put(q, saved)
+	})
+}
+
+// CleanConfigFileReleasesTx 清空配置文件发布，实际是把valid置为false
+func (s *sqliteStore) CleanConfigFileReleasesTx(tx store.Tx, namespace, group, fileName string) error {
+	return s.update(tx, func(q querier) error {
+		releases, err := s.configReleases.valid(q, "namespace = ? AND parent = ? AND name = ?", namespace, group, fileName)
+		if err != nil {
+			return err
+		}
+		now := s.now()
+		for _, item := range releases {
+			item.Valid = false
+			item.Active = false
+			item.ModifyTime = now
+			if err := s.configReleases.put(q, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetMoreReleaseFile 获取最近更新的配置文件发布
+func (s *sqliteStore) GetMoreReleaseFile(firstUpdate bool, modifyTime time.Time) ([]*model.ConfigFileRelease, error) {
+	ret, err := query(s, nil, func(q querier) ([]*model.ConfigFileRelease, error) {
+		return s.configReleases.since(q, modifyTime, firstUpdate)
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Id < ret[j].Id
+	})
+	return ret, nil
+}
+
+// CountConfigReleases 获取一个配置文件组下的发布数量
+func (s *sqliteStore) CountConfigReleases(namespace, group string, onlyActive bool) (uint64, error) {
+	releases, err := query(s, nil, func(q querier) ([]*model.ConfigFileRelease, error) {
+		return s.configReleases.valid(q, "namespace = ? AND parent = ?", namespace, group)
+	})
+	if err != nil {
+		return 0, err
+	}
+	var count uint64
+	for _, item := range releases {
+		if onlyActive && !item.Active {
+			continue
+		}
+		count++
+	}
+	return count, nil
+}
+
+// inactiveReleases 将同一个配置文件同一类型的发布置为失效，并返回当前最大的版本号，需要在写事务中调用
+func (s *sqliteStore) inactiveReleases(q querier, file *model.ConfigFileReleaseKey, now time.Time) (uint64, error) {
+	releases, err := s.configReleases.find(q, "namespace = ? AND parent = ? AND name = ?",
+		file.Namespace, file.Group, file.FileName)
+	if err != nil {
+		return 0, err
+	}
+	var maxVersion uint64
+	for _, item := range releases {
+		if item.Version > maxVersion {
+			maxVersion = item.Version
+		}
+		if !item.Valid || !item.Active || item.ReleaseType != file.ReleaseType {
+			continue
+		}
+		item.Active = false
+		item.ModifyTime = now
+		if err := s.configReleases.put(q, item); err != nil {
+			return 0, err
+		}
+	}
+	return maxVersion, nil
+}
+
+func configReleaseKey(key *model.ConfigFileReleaseKey) string {
+	return key.Namespace + "@" + key.Group + "@" + key.FileName + "@" + key.Name
+}
+
+// copyConfigFileRelease 复制发布记录，避免修改调用方传入的对象
+func copyConfigFileRelease(release *model.ConfigFileRelease) *model.ConfigFileRelease {
+	ret := model.NewConfigFileRelease()
+	ret.Content = release.Content
+	*ret.SimpleConfigFileRelease = *release.SimpleConfigFileRelease
+	ret.ConfigFileReleaseKey = &model.ConfigFileReleaseKey{}
+	*ret.ConfigFileReleaseKey = *release.ConfigFileReleaseKey
+	return ret
+}
+
+// CreateConfigFileReleaseHistory 创建配置文件发布历史记录
+func (s *sqliteStore) CreateConfigFileReleaseHistory(history *model.ConfigFileReleaseHistory) error {
+	if history == nil {
+		return store.NewStatusError(store.EmptyParamsErr, "create config file release history missing params")
+	}
+	return s.update(nil, func(q querier) error {
+		saved := *history
+		var err error
+		if saved.Id, err = s.nextID(q); err != nil {
+			return err
+		}
+		now := s.now()
+		saved.Valid = true
+		saved.CreateTime = now
+		saved.ModifyTime = now
+		return s.configHistories.put(q, &saved)
+	})
+}
+
+// QueryConfigFileReleaseHistories 获取配置文件的发布历史记录，结果按照 ID 倒序排列，
+// endId 用于向前翻页，只返回 ID 小于 endId 的记录
+func (s *sqliteStore) QueryConfigFileReleaseHistories(filter map[string]string, offset, limit uint32) (
+	uint32, []*model.ConfigFileReleaseHistory, error) {
+	where := "1 = 1"
+	args := make([]interface{}, 0, 1)
+	if v, ok := filter["endId"]; ok {
+		if endID, _ := strconv.ParseUint(v, 10, 64); endID > 0 {
+			where = "id < ?"
+			args = append(args, sequenceKey(endID))
+		}
+	}
+	histories, err := query(s, nil, func(q querier) ([]*model.ConfigFileReleaseHistory, error) {
+		return s.configHistories.valid(q, where+" ORDER BY id DESC", args...)
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	ret := make([]*model.ConfigFileReleaseHistory, 0, len(histories))
+	for _, item := range histories {
+		item := item
+		getters := map[string]func() string{
+			"namespace": func() string { return item.Namespace },
+			"group":     func() string { return item.Group },
+			"name":      func() string { return item.FileName },
+		}
+		if matchFilters(filter, getters) {
+			ret = append(ret, item)
+		}
+	}
+	return uint32(len(ret)), paginate(ret, offset, limit), nil
+}
+
+// CleanConfigFileReleaseHistory 清理 endTime 之前创建的配置发布历史，最多清理 limit 条
+func (s *sqliteStore) CleanConfigFileReleaseHistory(endTime time.Time, limit uint64) error {
+	return s.update(nil, func(q querier) error {
+		table := s.configHistories.name
+		_, err := q.ExecContext(context.Background(), "DELETE FROM "+table+" WHERE id IN (SELECT id FROM "+table+
+			" WHERE mtime < ? ORDER BY id LIMIT ?)", endTime.UnixNano(), limit)
+		return err
+	})
+}
+
+// QueryAllConfigFileTemplates query all config file templates
+func (s *sqliteStore) QueryAllConfigFileTemplates() ([]*model.ConfigFileTemplate, error) {
+	ret, err := query(s, nil, func(q querier) ([]*model.ConfigFileTemplate, error) {
+		return s.configTemplates.find(q, "")
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Id < ret[j].Id
+	})
+	return ret, nil
+}
+
+// CreateConfigFileTemplate create config file template
+func (s *sqliteStore) CreateConfigFileTemplate(template *model.ConfigFileTemplate) (*model.ConfigFileTemplate, error) {
+	if template == nil || template.Name == "" {
+		return nil, store.NewStatusError(store.EmptyParamsErr, "create config file template missing name")
+	}
+	saved := *template
+	err := s.update(nil, func(q querier) error {
+		old, err := s.configTemplates.get(q, template.Name)
+		if err != nil {
+			return err
+		}
+		if old != nil {
+			return store.NewStatusError(store.DuplicateEntryErr, "config file template already exists: "+template.Name)
+		}
+		if saved.Id, err = s.nextID(q); err != nil {
+			return err
+		}
+		now := s.now()
+		saved.CreateTime = now
+		saved.ModifyTime = now
+		return s.configTemplates.put(q, &saved)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+// GetConfigFileTemplate get config file template by name
+func (s *sqliteStore) GetConfigFileTemplate(name string) (*model.ConfigFileTemplate, error) {
+	return query(s, nil, func(q querier) (*model.ConfigFileTemplate, error) {
+		return s.configTemplates.get(q, name)
+	})
+}
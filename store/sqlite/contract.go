@@ -0,0 +1,213 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqlite
+
+import (
+	"time"
+
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// CreateServiceContract 创建服务契约
+func (s *sqliteStore) CreateServiceContract(contract *model.ServiceContract) error {
+	return s.CreateServiceContractTx(nil, contract)
+}
+
+// CreateServiceContractTx 在事务中创建服务契约
+func (s *sqliteStore) CreateServiceContractTx(tx store.Tx, contract *model.ServiceContract) error {
+	if contract == nil || contract.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "create service contract missing id")
+	}
+	return s.update(tx, func(q querier) error {
+		old, err := s.contracts.get(q, contract.ID)
+		if err != nil {
+			return err
+		}
+		if old != nil && old.Valid {
+			return store.NewStatusError(store.DuplicateEntryErr, "service contract already exists: "+contract.ID)
+		}
+		now := s.now()
+		saved := *contract
+		saved.Valid = true
+		saved.CreateTime = now
+		saved.ModifyTime = now
+		return s.contracts.put(q, &saved)
+	})
+}
+
+// UpdateServiceContract 更新服务契约
+func (s *sqliteStore) UpdateServiceContract(contract *model.ServiceContract) error {
+	return s.UpdateServiceContractTx(nil, contract)
+}
+
+// UpdateServiceContractTx 在事务中更新服务契约
+func (s *sqliteStore) UpdateServiceContractTx(tx store.Tx, contract *model.ServiceContract) error {
+	if contract == nil || contract.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "update service contract missing id")
+	}
+	return s.update(tx, func(q querier) error {
+		saved, err := s.contracts.get(q, contract.ID)
+		if err != nil {
+			return err
+		}
+		if saved == nil || !saved.Valid {
+			return store.NewStatusError(store.AffectedRowsNotMatch, "service contract not found: "+contract.ID)
+		}
+		saved.Content = contract.Content
+		saved.Revision = contract.Revision
+		saved.ModifyTime = s.now()
+		return s.contracts.put(q, saved)
+	})
+}
+
+// DeleteServiceContract 删除服务契约，实际是把valid置为false
+func (s *sqliteStore) DeleteServiceContract(contract *model.ServiceContract) error {
+	return s.DeleteServiceContractTx(nil, contract)
+}
+
+// DeleteServiceContractTx 在事务中删除服务契约，实际是把valid置为false
+func (s *sqliteStore) DeleteServiceContractTx(tx store.Tx, contract *model.ServiceContract) error {
+	if contract == nil || contract.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "delete service contract missing id")
+	}
+	return s.update(tx, func(q querier) error {
+		saved, err := s.contracts.get(q, contract.ID)
+		if err != nil || saved == nil || !saved.Valid {
+			return err
+		}
+		saved.Valid = false
+		saved.ModifyTime = s.now()
+		return s.contracts.put(q, saved)
+	})
+}
+
+// GetMoreServiceContracts 用于缓存加载数据
+func (s *sqliteStore) GetMoreServiceContracts(firstUpdate bool, mtime time.Time) ([]*model.ServiceContract, error) {
+	return query(s, nil, func(q querier) ([]*model.ServiceContract, error) {
+		return s.contracts.since(q, mtime, firstUpdate)
+	})
+}
+
+// GetServiceContract 查询服务契约数据
+func (s *sqliteStore) GetServiceContract(id string) (*model.ServiceContract, error) {
+	return query(s, nil, func(q querier) (*model.ServiceContract, error) {
+		return s.contracts.first(q, "id = ? AND valid = 1", id)
+	})
+}
+
+// AddServiceContractInterfaces 创建服务契约API接口，会覆盖契约中同一来源的全部接口
+func (s *sqliteStore) AddServiceContractInterfaces(contract *model.ServiceContract) error {
+	return s.AddServiceContractInterfacesTx(nil, contract)
+}
+
+// AddServiceContractInterfacesTx 在事务中创建服务契约API接口，会覆盖契约中同一来源的全部接口
+func (s *sqliteStore) AddServiceContractInterfacesTx(tx store.Tx, contract *model.ServiceContract) error {
+	return s.updateContractInterfaces(tx, contract, func(saved *model.ServiceContract, now time.Time) {
+		if contract.ClientInterfaces != nil {
+			saved.ClientInterfaces = map[string]*model.InterfaceDescriptor{}
+		}
+		if contract.ManualInterfaces != nil {
+			saved.ManualInterfaces = map[string]*model.InterfaceDescriptor{}
+		}
+		putContractInterfaces(saved, contract, now)
+	})
+}
+
+// AppendServiceContractInterfaces 追加服务契约API接口
+func (s *sqliteStore) AppendServiceContractInterfaces(contract *model.ServiceContract) error {
+	return s.AppendServiceContractInterfacesTx(nil, contract)
+}
+
+// AppendServiceContractInterfacesTx 在事务中追加服务契约API接口
+func (s *sqliteStore) AppendServiceContractInterfacesTx(tx store.Tx, contract *model.ServiceContract) error {
+	return s.updateContractInterfaces(tx, contract, func(saved *model.ServiceContract, now time.Time) {
+		putContractInterfaces(saved, contract, now)
+	})
+}
+
+// DeleteServiceContractInterfaces 批量删除服务契约API接口
+func (s *sqliteStore) DeleteServiceContractInterfaces(contract *model.ServiceContract) error {
+	return s.DeleteServiceContractInterfacesTx(nil, contract)
+}
+
+// DeleteServiceContractInterfacesTx 在事务中批量删除服务契约API接口
+func (s *sqliteStore) DeleteServiceContractInterfacesTx(tx store.Tx, contract *model.ServiceContract) error {
+	return s.updateContractInterfaces(tx, contract, func(saved *model.ServiceContract, now time.Time) {
+		for _, interfaces := range []map[string]*model.InterfaceDescriptor{
+			contract.ClientInterfaces, contract.ManualInterfaces} {
+			for id := range interfaces {
+				delete(saved.ClientInterfaces, id)
+				delete(saved.ManualInterfaces, id)
+			}
+		}
+	})
+}
+
+func (s *sqliteStore) updateContractInterfaces(tx store.Tx, contract *model.ServiceContract,
+	update func(saved *model.ServiceContract, now time.Time)) error {
+	if contract == nil || contract.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "service contract interfaces missing contract id")
+	}
+	return s.update(tx, func(q querier) error {
+		saved, err := s.contracts.get(q, contract.ID)
+		if err != nil {
+			return err
+		}
+		if saved == nil || !saved.Valid {
+			return store.NewStatusError(store.NotFoundResource, "service contract not found: "+contract.ID)
+		}
+		now := s.now()
+		update(saved, now)
+		saved.Revision = contract.Revision
+		saved.ModifyTime = now
+		return s.contracts.put(q, saved)
+	})
+}
+
+// putContractInterfaces 将 contract 中的接口写入 saved，接口按照 ID 区分
+func putContractInterfaces(saved, contract *model.ServiceContract, now time.Time) {
+	if saved.ClientInterfaces == nil {
+		saved.ClientInterfaces = map[string]*model.InterfaceDescriptor{}
+	}
+	if saved.ManualInterfaces == nil {
+		saved.ManualInterfaces = map[string]*model.InterfaceDescriptor{}
+	}
+	for _, interfaces := range []map[string]*model.InterfaceDescriptor{
+		contract.ClientInterfaces, contract.ManualInterfaces} {
+		for id, item := range interfaces {
+			copied := *item
+			copied.ContractID = saved.ID
+			copied.Valid = true
+			copied.ModifyTime = now
+			if copied.CreateTime.IsZero() {
+				copied.CreateTime = now
+			}
+			if copied.ID == "" {
+				copied.ID = id
+			}
+			if copied.Source == apiservice.InterfaceDescriptor_Client {
+				saved.ClientInterfaces[copied.ID] = &copied
+			} else {
+				saved.ManualInterfaces[copied.ID] = &copied
+			}
+		}
+	}
+}
@@ -0,0 +1,177 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqlite
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 过滤条件的语义与内存存储保持一致：以 * 结尾表示前缀匹配，未知的过滤字段会被忽略
+
+// matchString 判断 value 是否满足过滤条件，以 * 结尾表示前缀匹配
+func matchString(pattern, value string) bool {
+	if strings.HasSuffix(pattern, "*") {
+		return strings.HasPrefix(value, strings.TrimSuffix(pattern, "*"))
+	}
+	return pattern == value
+}
+
+// matchFilters 判断 getters 取出的字段是否满足所有的过滤条件
+func matchFilters(filter map[string]string, getters map[string]func() string) bool {
+	for key, pattern := range filter {
+		getter, ok := getters[key]
+		if !ok {
+			continue
+		}
+		if !matchString(pattern, getter()) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchAnyFilters filter 中同一个 key 的多个值只要满足其中一个即可
+func matchAnyFilters(filter map[string][]string, getters map[string]func() string) bool {
+	for key, patterns := range filter {
+		getter, ok := getters[key]
+		if !ok || len(patterns) == 0 {
+			continue
+		}
+		matched := false
+		for _, pattern := range patterns {
+			if matchString(pattern, getter()) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// matchMetadata 判断 metadata 是否包含 filter 中所有的键值对
+func matchMetadata(filter, metadata map[string]string) bool {
+	for k, v := range filter {
+		value, ok := metadata[k]
+		if !ok {
+			return false
+		}
+		if v != "" && !matchString(v, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// paginate 根据 offset 以及 limit 截取数据
+func paginate[T any](items []T, offset, limit uint32) []T {
+	if int(offset) >= len(items) {
+		return []T{}
+	}
+	end := len(items)
+	if limit > 0 && int(offset+limit) < end {
+		end = int(offset + limit)
+	}
+	return items[offset:end]
+}
+
+// modifyBy 本次修改没有指定操作人时保留原有的操作人
+func modifyBy(operator, saved string) string {
+	if operator == "" {
+		return saved
+	}
+	return operator
+}
+
+// sortByModifyTime 按照修改时间倒序排列，修改时间相同时按照 ID 排序保证结果稳定
+func sortByModifyTime[T any](items []T, mtime func(T) time.Time, id func(T) string) {
+	sort.Slice(items, func(i, j int) bool {
+		ti, tj := mtime(items[i]), mtime(items[j])
+		if !ti.Equal(tj) {
+			return ti.After(tj)
+		}
+		return id(items[i]) < id(items[j])
+	})
+}
+
+// inClause 生成 IN 查询的占位符以及参数
+func inClause[T any](values []T) (string, []interface{}) {
+	args := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		args = append(args, v)
+	}
+	return "(" + strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ") + ")", args
+}
+
+// stringIDs 将 interface{} 类型的 ID 转换为字符串
+func stringIDs(ids []interface{}) []string {
+	ret := make([]string, 0, len(ids))
+	for _, id := range ids {
+		ret = append(ret, fmt.Sprint(id))
+	}
+	return ret
+}
+
+func containsString(items []string, target string) bool {
+	for _, item := range items {
+		if item == target {
+			return true
+		}
+	}
+	return false
+}
+
+func containsUint32(items []uint32, target uint32) bool {
+	for _, item := range items {
+		if item == target {
+			return true
+		}
+	}
+	return false
+}
+
+func removeStrings(items []string, removed []string) []string {
+	ret := make([]string, 0, len(items))
+	for _, item := range items {
+		if !containsString(removed, item) {
+			ret = append(ret, item)
+		}
+	}
+	return ret
+}
+
+func formatBool(v bool) string {
+	if v {
+		return "1"
+	}
+	return "0"
+}
+
+func formatTime(t time.Time) string {
+	return t.Format("2006-01-02 15:04:05")
+}
+
+// sequenceKey 将自增 ID 转换为可以按照字典序排序的主键
+func sequenceKey(id uint64) string {
+	return fmt.Sprintf("%020d", id)
+}
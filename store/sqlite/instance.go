@@ -0,0 +1,459 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqlite
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// AddInstance 增加一个实例，已存在的同 ID 实例会被覆盖
+func (s *sqliteStore) AddInstance(instance *model.Instance) error {
+	return s.AddInstanceTx(nil, instance)
+}
+
+// AddInstanceTx 在事务中增加一个实例，已存在的同 ID 实例会被覆盖
+func (s *sqliteStore) AddInstanceTx(tx store.Tx, instance *model.Instance) error {
+	return s.BatchAddInstancesTx(tx, []*model.Instance{instance})
+}
+
+// BatchAddInstances 增加多个实例
+func (s *sqliteStore) BatchAddInstances(instances []*model.Instance) error {
+	return s.BatchAddInstancesTx(nil, instances)
+}
+
+// BatchAddInstancesTx 在事务中增加多个实例
+func (s *sqliteStore) BatchAddInstancesTx(tx store.Tx, instances []*model.Instance) error {
+	for _, ins := range instances {
+		if ins == nil || ins.Proto == nil || ins.Proto.GetId().GetValue() == "" || ins.ServiceID == "" {
+			return store.NewStatusError(store.EmptyParamsErr, "add instance missing some params")
+		}
+	}
+	return s.update(tx, func(q querier) error {
+		for _, ins := range instances {
+			svc, err := s.services.get(q, ins.ServiceID)
+			if err != nil {
+				return err
+			}
+			if svc == nil || !svc.Valid {
+				return store.NewStatusError(store.NotFoundService, "service not found: "+ins.ServiceID)
+			}
+		}
+		now := s.now()
+		for _, ins := range instances {
+			saved := *ins
+			saved.Proto = proto.Clone(ins.Proto).(*apiservice.Instance)
+			saved.Valid = true
+			saved.ModifyTime = now
+			saved.Proto.Ctime = wrapperspb.String(formatTime(now))
+			saved.Proto.Mtime = wrapperspb.String(formatTime(now))
+			if err := s.instances.put(q, &saved); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// UpdateInstance 更新实例
+func (s *sqliteStore) UpdateInstance(instance *model.Instance) error {
+	return s.UpdateInstanceTx(nil, instance)
+}
+
+// UpdateInstanceTx 在事务中更新实例
+func (s *sqliteStore) UpdateInstanceTx(tx store.Tx, instance *model.Instance) error {
+	if instance == nil || instance.Proto == nil || instance.Proto.GetId().GetValue() == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "update instance missing id")
+	}
+	return s.update(tx, func(q querier) error {
+		id := instanceID(instance)
+		saved, err := s.instances.get(q, id)
+		if err != nil {
+			return err
+		}
+		if saved == nil || !saved.Valid {
+			return store.NewStatusError(store.AffectedRowsNotMatch, "instance not found: "+id)
+		}
+		now := s.now()
+		ctime := saved.Proto.GetCtime()
+		saved.Proto = proto.Clone(instance.Proto).(*apiservice.Instance)
+		saved.Proto.Ctime = ctime
+		saved.ModifyBy = modifyBy(instance.ModifyBy, saved.ModifyBy)
+		touchInstance(saved, now)
+		return s.instances.put(q, saved)
+	})
+}
+
+// DeleteInstance 删除一个实例，实际是把valid置为false
+func (s *sqliteStore) DeleteInstance(instanceID string) error {
+	return s.DeleteInstanceTx(nil, instanceID)
+}
+
+// DeleteInstanceTx 在事务中删除一个实例，实际是把valid置为false
+func (s *sqliteStore) DeleteInstanceTx(tx store.Tx, instanceID string) error {
+	return s.BatchDeleteInstancesTx(tx, []interface{}{instanceID})
+}
+
+// BatchDeleteInstances 批量删除实例，实际是把valid置为false
+func (s *sqliteStore) BatchDeleteInstances(ids []interface{}) error {
+	return s.BatchDeleteInstancesTx(nil, ids)
+}
+
+// BatchDeleteInstancesTx 在事务中批量删除实例，实际是把valid置为false
+func (s *sqliteStore) BatchDeleteInstancesTx(tx store.Tx, ids []interface{}) error {
+	return s.batchUpdateInstances(tx, stringIDs(ids), func(ins *model.Instance) {
+		ins.Valid = false
+	})
+}
+
+// CleanInstance 清空一个实例，真正删除，只有已经软删除的实例才会被清理
+func (s *sqliteStore) CleanInstance(instanceID string) error {
+	return s.CleanInstanceTx(nil, instanceID)
+}
+
+// CleanInstanceTx 在事务中清空一个实例，真正删除，只有已经软删除的实例才会被清理
+func (s *sqliteStore) CleanInstanceTx(tx store.Tx, instanceID string) error {
+	return s.update(tx, func(q querier) error {
+		_, err := s.instances.remove(q, "id = ? AND valid = 0", instanceID)
+		return err
+	})
+}
+
+// BatchGetInstanceIsolate 检查ID是否存在，并且返回存在的ID，以及ID的隔离状态
+func (s *sqliteStore) BatchGetInstanceIsolate(ids map[string]bool) (map[string]bool, error) {
+	instances, err := s.validInstances(nil, ids)
+	if err != nil {
+		return nil, err
+	}
+	ret := make(map[string]bool, len(instances))
+	for _, ins := range instances {
+		ret[instanceID(ins)] = ins.Proto.GetIsolate().GetValue()
+	}
+	return ret, nil
+}
+
+// GetInstancesBrief 获取实例的基础信息以及关联服务的token
+func (s *sqliteStore) GetInstancesBrief(ids map[string]bool) (map[string]*model.Instance, error) {
+	ret := make(map[string]*model.Instance, len(ids))
+	err := s.view(nil, func(q querier) error {
+		instances, err := s.validInstances(q, ids)
+		if err != nil {
+			return err
+		}
+		for _, ins := range instances {
+			brief := &apiservice.Instance{
+				Id:        ins.Proto.GetId(),
+				Host:      ins.Proto.GetHost(),
+				Port:      ins.Proto.GetPort(),
+				Service:   ins.Proto.GetService(),
+				Namespace: ins.Proto.GetNamespace(),
+				VpcId:     ins.Proto.GetVpcId(),
+			}
+			svc, err := s.services.get(q, ins.ServiceID)
+			if err != nil {
+				return err
+			}
+			if svc != nil {
+				brief.ServiceToken = wrapperspb.String(svc.Token)
+			}
+			ret[instanceID(ins)] = &model.Instance{
+				Proto:             brief,
+				ServiceID:         ins.ServiceID,
+				ServicePlatformID: ins.ServicePlatformID,
+				Valid:             ins.Valid,
+				ModifyTime:        ins.ModifyTime,
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// GetInstance 查询一个实例的详情，只返回有效的数据
+func (s *sqliteStore) GetInstance(instanceID string) (*model.Instance, error) {
+	return query(s, nil, func(q querier) (*model.Instance, error) {
+		return s.instances.first(q, "id = ? AND valid = 1", instanceID)
+	})
+}
+
+// GetInstancesCount 获取有效的实例总数
+func (s *sqliteStore) GetInstancesCount() (uint32, error) {
+	return s.GetInstancesCountTx(nil)
+}
+
+// GetInstancesCountTx 获取有效的实例总数
+func (s *sqliteStore) GetInstancesCountTx(tx store.Tx) (uint32, error) {
+	count, err := query(s, tx, func(q querier) (uint64, error) {
+		return s.instances.count(q, "valid = 1")
+	})
+	return uint32(count), err
+}
+
+// GetInstancesMainByService 根据服务和Host获取实例（不包括metadata）
+func (s *sqliteStore) GetInstancesMainByService(serviceID, host string) ([]*model.Instance, error) {
+	ret, err := query(s, nil, func(q querier) ([]*model.Instance, error) {
+		return s.instances.valid(q, "parent = ? AND name = ?", serviceID, host)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, ins := range ret {
+		ins.Proto.Metadata = nil
+	}
+	sortInstances(ret)
+	return ret, nil
+}
+
+// GetExpandInstances 根据过滤条件查看实例详情及对应数目
+func (s *sqliteStore) GetExpandInstances(filter, metaFilter map[string]string, offset uint32, limit uint32) (
+	uint32, []*model.Instance, error) {
+	instances, err := query(s, nil, func(q querier) ([]*model.Instance, error) {
+		return s.instances.valid(q, "")
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	filter = normalizeBoolFilters(filter)
+	ret := make([]*model.Instance, 0, len(instances))
+	for _, ins := range instances {
+		if !matchFilters(filter, instanceGetters(ins)) || !matchMetadata(metaFilter, ins.Proto.GetMetadata()) {
+			continue
+		}
+		ret = append(ret, ins)
+	}
+	sortInstances(ret)
+	return uint32(len(ret)), paginate(ret, offset, limit), nil
+}
+
+// GetMoreInstances 根据mtime获取增量instances，serviceID 不为空时只返回这些服务下的实例
+func (s *sqliteStore) GetMoreInstances(tx store.Tx, mtime time.Time, firstUpdate, needMeta bool,
+	serviceID []string) (map[string]*model.Instance, error) {
+	instances, err := query(s, tx, func(q querier) ([]*model.Instance, error) {
+		return s.instances.since(q, mtime, firstUpdate)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := make(map[string]*model.Instance, len(instances))
+	for _, ins := range instances {
+		if len(serviceID) > 0 && !containsString(serviceID, ins.ServiceID) {
+			continue
+		}
+		if !needMeta {
+			ins.Proto.Metadata = nil
+		}
+		ret[instanceID(ins)] = ins
+	}
+	return ret, nil
+}
+
+// SetInstanceHealthStatus 设置实例的健康状态
+func (s *sqliteStore) SetInstanceHealthStatus(instanceID string, flag int, revision string) error {
+	return s.SetInstanceHealthStatusTx(nil, instanceID, flag, revision)
+}
+
+// SetInstanceHealthStatusTx 在事务中设置实例的健康状态
+func (s *sqliteStore) SetInstanceHealthStatusTx(tx store.Tx, instanceID string, flag int, revision string) error {
+	return s.BatchSetInstanceHealthStatusTx(tx, []interface{}{instanceID}, flag, revision)
+}
+
+// BatchSetInstanceHealthStatus 批量设置实例的健康状态
+func (s *sqliteStore) BatchSetInstanceHealthStatus(ids []interface{}, healthy int, revision string) error {
+	return s.BatchSetInstanceHealthStatusTx(nil, ids, healthy, revision)
+}
+
+// BatchSetInstanceHealthStatusTx 在事务中批量设置实例的健康状态
+func (s *sqliteStore) BatchSetInstanceHealthStatusTx(tx store.Tx, ids []interface{}, healthy int, revision string) error {
+	return s.batchUpdateInstances(tx, stringIDs(ids), func(ins *model.Instance) {
+		ins.Proto.Healthy = wrapperspb.Bool(healthy > 0)
+		ins.Proto.Revision = wrapperspb.String(revision)
+	})
+}
+
+// BatchSetInstanceIsolate 批量修改实例的隔离状态
+func (s *sqliteStore) BatchSetInstanceIsolate(ids []interface{}, isolate int, revision string) error {
+	return s.BatchSetInstanceIsolateTx(nil, ids, isolate, revision)
+}
+
+// BatchSetInstanceIsolateTx 在事务中批量修改实例的隔离状态
+func (s *sqliteStore) BatchSetInstanceIsolateTx(tx store.Tx, ids []interface{}, isolate int, revision string) error {
+	return s.batchUpdateInstances(tx, stringIDs(ids), func(ins *model.Instance) {
+		ins.Proto.Isolate = wrapperspb.Bool(isolate > 0)
+		ins.Proto.Revision = wrapperspb.String(revision)
+	})
+}
+
+// BatchAppendInstanceMetadata 追加实例 metadata
+func (s *sqliteStore) BatchAppendInstanceMetadata(requests []*model.InstanceMetadataRequest) error {
+	return s.BatchAppendInstanceMetadataTx(nil, requests)
+}
+
+// BatchAppendInstanceMetadataTx 在事务中追加实例 metadata
+func (s *sqliteStore) BatchAppendInstanceMetadataTx(tx store.Tx, requests []*model.InstanceMetadataRequest) error {
+	return s.update(tx, func(q querier) error {
+		now := s.now()
+		for _, req := range requests {
+			saved, err := s.instances.first(q, "id = ? AND valid = 1", req.InstanceID)
+			if err != nil {
+				return err
+			}
+			if saved == nil {
+				continue
+			}
+			if saved.Proto.Metadata == nil {
+				saved.Proto.Metadata = map[string]string{}
+			}
+			for k, v := range req.Metadata {
+				saved.Proto.Metadata[k] = v
+			}
+			saved.Proto.Revision = wrapperspb.String(req.Revision)
+			touchInstance(saved, now)
+			if err := s.instances.put(q, saved); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// BatchRemoveInstanceMetadata 删除实例指定的 metadata
+func (s *sqliteStore) BatchRemoveInstanceMetadata(requests []*model.InstanceMetadataRequest) error {
+	return s.BatchRemoveInstanceMetadataTx(nil, requests)
+}
+
+// BatchRemoveInstanceMetadataTx 在事务中删除实例指定的 metadata
+func (s *sqliteStore) BatchRemoveInstanceMetadataTx(tx store.Tx, requests []*model.InstanceMetadataRequest) error {
+	return s.update(tx, func(q querier) error {
+		now := s.now()
+		for _, req := range requests {
+			saved, err := s.instances.first(q, "id = ? AND valid = 1", req.InstanceID)
+			if err != nil {
+				return err
+			}
+			if saved == nil {
+				continue
+			}
+			for _, key := range req.Keys {
+				delete(saved.Proto.Metadata, key)
+			}
+			saved.Proto.Revision = wrapperspb.String(req.Revision)
+			touchInstance(saved, now)
+			if err := s.instances.put(q, saved); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// validInstances 根据 ID 批量查询有效的实例
+func (s *sqliteStore) validInstances(q querier, ids map[string]bool) ([]*model.Instance, error) {
+	if len(ids) == 0 {
+		return []*model.Instance{}, nil
+	}
+	keys := make([]string, 0, len(ids))
+	for id := range ids {
+		keys = append(keys, id)
+	}
+	in, args := inClause(keys)
+	if q != nil {
+		return s.instances.valid(q, "id IN "+in, args...)
+	}
+	return query(s, nil, func(q querier) ([]*model.Instance, error) {
+		return s.instances.valid(q, "id IN "+in, args...)
+	})
+}
+
+// batchUpdateInstances 批量修改有效的实例，并刷新实例的修改时间
+func (s *sqliteStore) batchUpdateInstances(tx store.Tx, ids []string, update func(*model.Instance)) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	in, args := inClause(ids)
+	return s.update(tx, func(q querier) error {
+		instances, err := s.instances.valid(q, "id IN "+in, args...)
+		if err != nil {
+			return err
+		}
+		now := s.now()
+		for _, ins := range instances {
+			update(ins)
+			touchInstance(ins, now)
+			if err := s.instances.put(q, ins); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func touchInstance(ins *model.Instance, now time.Time) {
+	ins.ModifyTime = now
+	ins.Proto.Mtime = wrapperspb.String(formatTime(now))
+}
+
+func instanceGetters(ins *model.Instance) map[string]func() string {
+	return map[string]func() string{
+		"id":            func() string { return ins.Proto.GetId().GetValue() },
+		"service_id":    func() string { return ins.ServiceID },
+		"name":          func() string { return ins.Proto.GetService().GetValue() },
+		"service":       func() string { return ins.Proto.GetService().GetValue() },
+		"namespace":     func() string { return ins.Proto.GetNamespace().GetValue() },
+		"host":          func() string { return ins.Proto.GetHost().GetValue() },
+		"port":          func() string { return strconv.FormatUint(uint64(ins.Proto.GetPort().GetValue()), 10) },
+		"protocol":      func() string { return ins.Proto.GetProtocol().GetValue() },
+		"version":       func() string { return ins.Proto.GetVersion().GetValue() },
+		"logic_set":     func() string { return ins.Proto.GetLogicSet().GetValue() },
+		"health_status": func() string { return formatBool(ins.Proto.GetHealthy().GetValue()) },
+		"healthy":       func() string { return formatBool(ins.Proto.GetHealthy().GetValue()) },
+		"isolate":       func() string { return formatBool(ins.Proto.GetIsolate().GetValue()) },
+	}
+}
+
+// normalizeBoolFilters 将布尔类型的过滤条件统一转为 1/0 的形式
+func normalizeBoolFilters(filter map[string]string) map[string]string {
+	ret := make(map[string]string, len(filter))
+	for k, v := range filter {
+		switch k {
+		case "health_status", "healthy", "isolate":
+			if b, err := strconv.ParseBool(v); err == nil {
+				v = formatBool(b)
+			}
+		}
+		ret[k] = v
+	}
+	return ret
+}
+
+func sortInstances(instances []*model.Instance) {
+	sortByModifyTime(instances, func(ins *model.Instance) time.Time { return ins.ModifyTime }, instanceID)
+}
+
+func instanceID(ins *model.Instance) string {
+	return ins.Proto.GetId().GetValue()
+}
@@ -0,0 +1,103 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqlite
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// l5Extend 服务的 L5 扩展数据
+type l5Extend struct {
+	ServiceID  string                 `json:"service_id"`
+	Meta       map[string]interface{} `json:"meta"`
+	ModifyTime time.Time              `json:"modify_time"`
+}
+
+// GetL5Extend 获取扩展数据
+func (s *sqliteStore) GetL5Extend(serviceID string) (map[string]interface{}, error) {
+	saved, err := query(s, nil, func(q querier) (*l5Extend, error) {
+		return s.l5Extends.get(q, serviceID)
+	})
+	if err != nil || saved == nil {
+		return nil, err
+	}
+	return saved.Meta, nil
+}
+
+// SetL5Extend 设置meta里保存的扩展数据，SQLite 存储会保存全部的数据，因此剩余的meta为空
+func (s *sqliteStore) SetL5Extend(serviceID string, meta map[string]interface{}) (map[string]interface{}, error) {
+	err := s.update(nil, func(q querier) error {
+		return s.l5Extends.put(q, &l5Extend{ServiceID: serviceID, Meta: meta, ModifyTime: s.now()})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{}, nil
+}
+
+// GenNextL5Sid 获取module
+func (s *sqliteStore) GenNextL5Sid(layoutID uint32) (string, error) {
+	var next uint64
+	err := s.update(nil, func(q querier) error {
+		var err error
+		next, err = nextSequence(q, fmt.Sprintf("l5_sid_%d", layoutID))
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d:%d", layoutID, next), nil
+}
+
+// GetMoreL5Extend 获取增量数据
+func (s *sqliteStore) GetMoreL5Extend(mtime time.Time) (map[string]map[string]interface{}, error) {
+	extends, err := query(s, nil, func(q querier) ([]*l5Extend, error) {
+		return s.l5Extends.since(q, mtime, false)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := make(map[string]map[string]interface{}, len(extends))
+	for _, ext := range extends {
+		ret[ext.ServiceID] = ext.Meta
+	}
+	return ret, nil
+}
+
+// GetMoreL5Routes 获取Route增量数据，SQLite 存储不保存 L5 的路由数据
+func (s *sqliteStore) GetMoreL5Routes(flow uint32) ([]*model.Route, error) {
+	return []*model.Route{}, nil
+}
+
+// GetMoreL5Policies 获取Policy增量数据，SQLite 存储不保存 L5 的路由数据
+func (s *sqliteStore) GetMoreL5Policies(flow uint32) ([]*model.Policy, error) {
+	return []*model.Policy{}, nil
+}
+
+// GetMoreL5Sections 获取Section增量数据，SQLite 存储不保存 L5 的路由数据
+func (s *sqliteStore) GetMoreL5Sections(flow uint32) ([]*model.Section, error) {
+	return []*model.Section{}, nil
+}
+
+// GetMoreL5IPConfigs 获取IP Config增量数据，SQLite 存储不保存 L5 的路由数据
+func (s *sqliteStore) GetMoreL5IPConfigs(flow uint32) ([]*model.IPConfig, error) {
+	return []*model.IPConfig{}, nil
+}
@@ -0,0 +1,147 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+)
+
+// schemaVersionDDL 保存 schema 版本的表，不属于任何一个 Migration，打开数据库时创建
+const schemaVersionDDL = `CREATE TABLE IF NOT EXISTS schema_version (
+	name TEXT NOT NULL PRIMARY KEY,
+	version INTEGER NOT NULL
+)`
+
+func init() {
+	store.RegisterMigrations(StoreName, store.Migration{
+		Version:     1,
+		Description: "create resource tables",
+		Up: func(tx store.Tx) error {
+			return execTx(tx, baseline().schema()...)
+		},
+		Down: func(tx store.Tx) error {
+			tables := baseline().tables()
+			statements := make([]string, 0, len(tables)+1)
+			for _, t := range tables {
+				statements = append(statements, "DROP TABLE IF EXISTS "+t.tableName())
+			}
+			return execTx(tx, append(statements, "DROP TABLE IF EXISTS sequence")...)
+		},
+	}, store.Migration{
+		Version:     2,
+		Description: "create distributed lock table",
+		Up: func(tx store.Tx) error {
+			return execTx(tx, baseline().distLocks.schema()...)
+		},
+		Down: func(tx store.Tx) error {
+			return execTx(tx, "DROP TABLE IF EXISTS "+baseline().distLocks.tableName())
+		},
+	})
+}
+
+// SchemaVersion 获取当前的 schema 版本，尚未执行任何迁移时返回 0
+func (s *sqliteStore) SchemaVersion(tx store.Tx) (uint32, error) {
+	return query(s, tx, func(q querier) (uint32, error) {
+		rows, err := q.QueryContext(context.Background(), "SELECT version FROM schema_version WHERE name = ?", StoreName)
+		if err != nil {
+			return 0, err
+		}
+		defer func() {
+			_ = rows.Close()
+		}()
+
+		var version uint32
+		if rows.Next() {
+			if err := rows.Scan(&version); err != nil {
+				return 0, err
+			}
+		}
+		return version, rows.Err()
+	})
+}
+
+// SetSchemaVersion 记录当前的 schema 版本
+func (s *sqliteStore) SetSchemaVersion(tx store.Tx, version uint32) error {
+	return s.update(tx, func(q querier) error {
+		_, err := q.ExecContext(context.Background(), "INSERT INTO schema_version (name, version) VALUES (?, ?) "+
+			"ON CONFLICT(name) DO UPDATE SET version = excluded.version", StoreName, version)
+		return err
+	})
+}
+
+// checkSchema 新建的数据库以及开启 autoMigrate 时升级到最新版本，否则 schema 版本不一致时拒绝启动
+func (s *sqliteStore) checkSchema() error {
+	tx, err := s.StartTx()
+	if err != nil {
+		return err
+	}
+	current, err := s.SchemaVersion(tx)
+	_ = tx.Rollback()
+	if err != nil {
+		return err
+	}
+	if current == 0 || (s.autoMigrate && current < store.LatestSchemaVersion(StoreName)) {
+		if err := store.MigrateUp(StoreName, s); err != nil {
+			return err
+		}
+	}
+	return store.CheckSchemaVersion(StoreName, s)
+}
+
+// baseline 用于生成建表语句的存储对象，表结构与具体的数据库文件无关
+func baseline() *sqliteStore {
+	s := &sqliteStore{}
+	s.initTables()
+	return s
+}
+
+// tables 版本 1 创建的资源表，之后新增的表由各自的迁移创建
+func (s *sqliteStore) tables() []schema {
+	return []schema{
+		s.namespaces, s.services, s.instances, s.l5Extends, s.routingConfigs, s.routerConfigs, s.rateLimits,
+		s.circuitBreakers, s.faultDetectRules, s.contracts, s.clients, s.grayResources, s.leaders, s.bootstraps,
+		s.configGroups, s.configFiles, s.configReleases, s.configHistories, s.configTemplates,
+		s.users, s.groups, s.strategies,
+	}
+}
+
+// schema 全部的建表语句以及索引
+func (s *sqliteStore) schema() []string {
+	statements := []string{sequenceDDL}
+	for _, t := range s.tables() {
+		statements = append(statements, t.schema()...)
+	}
+	return statements
+}
+
+// execTx 在迁移的事务中依次执行 statements
+func execTx(tx store.Tx, statements ...string) error {
+	stx, err := checkTx(tx, true)
+	if err != nil {
+		return err
+	}
+	for _, statement := range statements {
+		if _, err := stx.conn.ExecContext(context.Background(), statement); err != nil {
+			return fmt.Errorf("exec %q: %w", statement, err)
+		}
+	}
+	return nil
+}
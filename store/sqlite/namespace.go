@@ -0,0 +1,138 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqlite
+
+import (
+	"sort"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// AddNamespace Save a namespace
+func (s *sqliteStore) AddNamespace(namespace *model.Namespace) error {
+	if namespace == nil || namespace.Name == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "add namespace missing name")
+	}
+	return s.update(nil, func(q querier) error {
+		old, err := s.namespaces.get(q, namespace.Name)
+		if err != nil {
+			return err
+		}
+		if old != nil && old.Valid {
+			return store.NewStatusError(store.DuplicateEntryErr, "namespace already exists: "+namespace.Name)
+		}
+		now := s.now()
+		saved := *namespace
+		saved.Valid = true
+		saved.CreateTime = now
+		saved.ModifyTime = now
+		return s.namespaces.put(q, &saved)
+	})
+}
+
+// UpdateNamespace Update namespace
+func (s *sqliteStore) UpdateNamespace(namespace *model.Namespace) error {
+	if namespace == nil || namespace.Name == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "update namespace missing name")
+	}
+	return s.update(nil, func(q querier) error {
+		saved, err := s.validNamespace(q, namespace.Name)
+		if err != nil {
+			return err
+		}
+		saved.Owner = namespace.Owner
+		saved.Comment = namespace.Comment
+		saved.ServiceExportTo = namespace.ServiceExportTo
+		saved.ModifyTime = s.now()
+		return s.namespaces.put(q, saved)
+	})
+}
+
+// UpdateNamespaceToken Update namespace token
+func (s *sqliteStore) UpdateNamespaceToken(name string, token string) error {
+	if name == "" || token == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "update namespace token missing some params")
+	}
+	return s.update(nil, func(q querier) error {
+		saved, err := s.validNamespace(q, name)
+		if err != nil {
+			return err
+		}
+		saved.Token = token
+		saved.ModifyTime = s.now()
+		return s.namespaces.put(q, saved)
+	})
+}
+
+// GetNamespace Get the details of the namespace according to Name
+func (s *sqliteStore) GetNamespace(name string) (*model.Namespace, error) {
+	return query(s, nil, func(q querier) (*model.Namespace, error) {
+		return s.namespaces.first(q, "id = ? AND valid = 1", name)
+	})
+}
+
+// GetNamespaces Query Namespace from the database, filter 中同一个 key 的多个值为或的关系
+func (s *sqliteStore) GetNamespaces(filter map[string][]string, offset, limit int) ([]*model.Namespace, uint32, error) {
+	namespaces, err := query(s, nil, func(q querier) ([]*model.Namespace, error) {
+		return s.namespaces.valid(q, "")
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	ret := make([]*model.Namespace, 0, len(namespaces))
+	for _, ns := range namespaces {
+		getters := map[string]func() string{
+			"name":  func() string { return ns.Name },
+			"owner": func() string { return ns.Owner },
+		}
+		if matchAnyFilters(filter, getters) {
+			ret = append(ret, ns)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Name < ret[j].Name
+	})
+	if offset < 0 {
+		offset = 0
+	}
+	if limit < 0 {
+		limit = 0
+	}
+	return paginate(ret, uint32(offset), uint32(limit)), uint32(len(ret)), nil
+}
+
+// GetMoreNamespaces Get incremental data
+func (s *sqliteStore) GetMoreNamespaces(mtime time.Time) ([]*model.Namespace, error) {
+	return query(s, nil, func(q querier) ([]*model.Namespace, error) {
+		return s.namespaces.since(q, mtime, false)
+	})
+}
+
+// validNamespace 查询有效的命名空间，不存在时返回 store.AffectedRowsNotMatch
+func (s *sqliteStore) validNamespace(q querier, name string) (*model.Namespace, error) {
+	saved, err := s.namespaces.get(q, name)
+	if err != nil {
+		return nil, err
+	}
+	if saved == nil || !saved.Valid {
+		return nil, store.NewStatusError(store.AffectedRowsNotMatch, "namespace not found: "+name)
+	}
+	return saved, nil
+}
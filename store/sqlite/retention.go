@@ -0,0 +1,64 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqlite
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+)
+
+var _ store.RetentionStore = (*sqliteStore)(nil)
+
+// PurgeDeleted batch purge soft deleted resources of kind which mtime time out
+func (s *sqliteStore) PurgeDeleted(kind store.RetentionKind, timeout time.Duration, batchSize uint32) (uint32, error) {
+	switch kind {
+	case store.RetentionService:
+		// 服务别名的 parent 为指向的服务 ID
+		return s.cleanDeleted(s.services.name, "parent = ''", timeout, batchSize)
+	case store.RetentionServiceAlias:
+		return s.cleanDeleted(s.services.name, "parent <> ''", timeout, batchSize)
+	case store.RetentionRoutingConfig:
+		return s.cleanDeleted(s.routingConfigs.name, "", timeout, batchSize)
+	case store.RetentionRouterConfig:
+		return s.cleanDeleted(s.routerConfigs.name, "", timeout, batchSize)
+	case store.RetentionRateLimit:
+		return s.cleanDeleted(s.rateLimits.name, "", timeout, batchSize)
+	case store.RetentionCircuitBreakerRule:
+		return s.cleanDeleted(s.circuitBreakers.name, "", timeout, batchSize)
+	case store.RetentionFaultDetectRule:
+		return s.cleanDeleted(s.faultDetectRules.name, "", timeout, batchSize)
+	case store.RetentionServiceContract:
+		return s.cleanDeleted(s.contracts.name, "", timeout, batchSize)
+	case store.RetentionConfigFileGroup:
+		return s.cleanDeleted(s.configGroups.name, "", timeout, batchSize)
+	case store.RetentionConfigFile:
+		return s.cleanDeleted(s.configFiles.name, "", timeout, batchSize)
+	case store.RetentionConfigFileRelease:
+		return s.cleanDeleted(s.configReleases.name, "", timeout, batchSize)
+	case store.RetentionUser:
+		return s.cleanDeleted(s.users.name, "", timeout, batchSize)
+	case store.RetentionUserGroup:
+		return s.cleanDeleted(s.groups.name, "", timeout, batchSize)
+	case store.RetentionStrategy:
+		return s.cleanDeleted(s.strategies.name, "", timeout, batchSize)
+	default:
+		return 0, store.NewStatusError(store.EmptyParamsErr, fmt.Sprintf("unsupported retention kind %s", kind))
+	}
+}
@@ -0,0 +1,68 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqlite
+
+import (
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+var _ store.RevisionStore = (*sqliteStore)(nil)
+
+// UpdateServiceCAS 条件更新服务
+func (s *sqliteStore) UpdateServiceCAS(service *model.Service, needUpdateOwner bool, expectRevision string) error {
+	return s.updateService(nil, service, needUpdateOwner, &expectRevision)
+}
+
+// UpdateRoutingConfigCAS 条件更新路由配置
+func (s *sqliteStore) UpdateRoutingConfigCAS(conf *model.RoutingConfig, expectRevision string) error {
+	return s.updateRoutingConfig(nil, conf, &expectRevision)
+}
+
+// UpdateRoutingConfigV2CAS 条件更新 v2 版本的路由规则
+func (s *sqliteStore) UpdateRoutingConfigV2CAS(conf *model.RouterConfig, expectRevision string) error {
+	return s.updateRoutingConfigV2(nil, conf, &expectRevision)
+}
+
+// UpdateRateLimitCAS 条件更新限流规则
+func (s *sqliteStore) UpdateRateLimitCAS(limit *model.RateLimit, expectRevision string) error {
+	return s.updateRateLimit(nil, limit, &expectRevision)
+}
+
+// UpdateCircuitBreakerRuleCAS 条件更新熔断规则
+func (s *sqliteStore) UpdateCircuitBreakerRuleCAS(cbRule *model.CircuitBreakerRule, expectRevision string) error {
+	return s.updateCircuitBreakerRule(nil, cbRule, &expectRevision)
+}
+
+// UpdateFaultDetectRuleCAS 条件更新主动探测规则
+func (s *sqliteStore) UpdateFaultDetectRuleCAS(conf *model.FaultDetectRule, expectRevision string) error {
+	return s.updateFaultDetectRule(nil, conf, &expectRevision)
+}
+
+// UpdateStrategyCAS 条件更新鉴权策略
+func (s *sqliteStore) UpdateStrategyCAS(strategy *model.ModifyStrategyDetail, expectRevision string) error {
+	return s.updateStrategy(strategy, &expectRevision)
+}
+
+// checkRevision expectRevision 为空时表示无条件更新
+func checkRevision(resource, id, savedRevision string, expectRevision *string) error {
+	if expectRevision == nil {
+		return nil
+	}
+	return store.CheckRevision(resource, id, savedRevision, *expectRevision)
+}
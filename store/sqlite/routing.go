@@ -0,0 +1,350 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqlite
+
+import (
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// CreateRoutingConfig 新增一个路由配置
+func (s *sqliteStore) CreateRoutingConfig(conf *model.RoutingConfig) error {
+	return s.CreateRoutingConfigTx(nil, conf)
+}
+
+// CreateRoutingConfigTx 在事务中新增一个路由配置
+func (s *sqliteStore) CreateRoutingConfigTx(tx store.Tx, conf *model.RoutingConfig) error {
+	if conf == nil || conf.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "create routing config missing service id")
+	}
+	return s.update(tx, func(q querier) error {
+		svc, err := s.services.get(q, conf.ID)
+		if err != nil {
+			return err
+		}
+		if svc == nil || !svc.Valid {
+			return store.NewStatusError(store.NotFoundService, "service not found: "+conf.ID)
+		}
+		old, err := s.routingConfigs.get(q, conf.ID)
+		if err != nil {
+			return err
+		}
+		if old != nil && old.Valid {
+			return store.NewStatusError(store.DuplicateEntryErr, "routing config already exists: "+conf.ID)
+		}
+		now := s.now()
+		saved := *conf
+		saved.Valid = true
+		saved.CreateTime = now
+		saved.ModifyTime = now
+		return s.routingConfigs.put(q, &saved)
+	})
+}
+
+// UpdateRoutingConfig 更新一个路由配置
+func (s *sqliteStore) UpdateRoutingConfig(conf *model.RoutingConfig) error {
+	return s.updateRoutingConfig(nil, conf, nil)
+}
+
+// UpdateRoutingConfigTx 在事务中更新一个路由配置
+func (s *sqliteStore) UpdateRoutingConfigTx(tx store.Tx, conf *model.RoutingConfig) error {
+	return s.updateRoutingConfig(tx, conf, nil)
+}
+
+// updateRoutingConfig 更新一个路由配置，expectRevision 不为空时只有当前的 Revision 与其一致才会更新
+func (s *sqliteStore) updateRoutingConfig(tx store.Tx, conf *model.RoutingConfig, expectRevision *string) error {
+	if conf == nil || conf.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "update routing config missing service id")
+	}
+	return s.update(tx, func(q querier) error {
+		saved, err := s.routingConfigs.get(q, conf.ID)
+		if err != nil {
+			return err
+		}
+		if saved == nil || !saved.Valid {
+			return store.NewStatusError(store.AffectedRowsNotMatch, "routing config not found: "+conf.ID)
+		}
+		if err := checkRevision("routing config", conf.ID, saved.Revision, expectRevision); err != nil {
+			return err
+		}
+		saved.InBounds = conf.InBounds
+		saved.OutBounds = conf.OutBounds
+		saved.Revision = conf.Revision
+		saved.ModifyTime = s.now()
+		return s.routingConfigs.put(q, saved)
+	})
+}
+
+// DeleteRoutingConfig 删除一个路由配置，实际是把valid置为false
+func (s *sqliteStore) DeleteRoutingConfig(serviceID string) error {
+	return s.DeleteRoutingConfigTx(nil, serviceID)
+}
+
+// DeleteRoutingConfigTx 删除一个路由配置，实际是把valid置为false
+func (s *sqliteStore) DeleteRoutingConfigTx(tx store.Tx, serviceID string) error {
+	return s.update(tx, func(q querier) error {
+		saved, err := s.routingConfigs.get(q, serviceID)
+		if err != nil || saved == nil || !saved.Valid {
+			return err
+		}
+		saved.Valid = false
+		saved.ModifyTime = s.now()
+		return s.routingConfigs.put(q, saved)
+	})
+}
+
+// GetRoutingConfigsForCache 通过mtime拉取增量的路由配置信息
+func (s *sqliteStore) GetRoutingConfigsForCache(mtime time.Time, firstUpdate bool) ([]*model.RoutingConfig, error) {
+	return query(s, nil, func(q querier) ([]*model.RoutingConfig, error) {
+		configs, err := s.routingConfigs.since(q, mtime, firstUpdate)
+		if err != nil {
+			return nil, err
+		}
+		return s.fillRoutingConfigs(q, configs)
+	})
+}
+
+// GetRoutingConfigWithService 根据服务名和命名空间拉取路由配置
+func (s *sqliteStore) GetRoutingConfigWithService(name string, namespace string) (*model.RoutingConfig, error) {
+	return query(s, nil, func(q querier) (*model.RoutingConfig, error) {
+		svc, err := s.services.first(q, "namespace = ? AND name = ? AND valid = 1", namespace, name)
+		if err != nil || svc == nil {
+			return nil, err
+		}
+		saved, err := s.routingConfigs.first(q, "id = ? AND valid = 1", svc.ID)
+		if err != nil || saved == nil {
+			return nil, err
+		}
+		saved.ServiceName = svc.Name
+		saved.NamespaceName = svc.Namespace
+		return saved, nil
+	})
+}
+
+// GetRoutingConfigWithID 根据服务ID拉取路由配置
+func (s *sqliteStore) GetRoutingConfigWithID(id string) (*model.RoutingConfig, error) {
+	return query(s, nil, func(q querier) (*model.RoutingConfig, error) {
+		saved, err := s.routingConfigs.first(q, "id = ? AND valid = 1", id)
+		if err != nil || saved == nil {
+			return nil, err
+		}
+		configs, err := s.fillRoutingConfigs(q, []*model.RoutingConfig{saved})
+		if err != nil {
+			return nil, err
+		}
+		return configs[0], nil
+	})
+}
+
+// GetRoutingConfigs 查询路由配置列表
+func (s *sqliteStore) GetRoutingConfigs(filter map[string]string, offset uint32, limit uint32) (
+	uint32, []*model.RoutingConfig, error) {
+	configs, err := query(s, nil, func(q querier) ([]*model.RoutingConfig, error) {
+		configs, err := s.routingConfigs.valid(q, "")
+		if err != nil {
+			return nil, err
+		}
+		return s.fillRoutingConfigs(q, configs)
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	ret := make([]*model.RoutingConfig, 0, len(configs))
+	for _, conf := range configs {
+		if matchFilters(filter, routingConfigGetters(conf)) {
+			ret = append(ret, conf)
+		}
+	}
+	sortByModifyTime(ret, func(conf *model.RoutingConfig) time.Time { return conf.ModifyTime },
+		func(conf *model.RoutingConfig) string { return conf.ID })
+	return uint32(len(ret)), paginate(ret, offset, limit), nil
+}
+
+// fillRoutingConfigs 补充路由配置所属服务的服务名以及命名空间
+func (s *sqliteStore) fillRoutingConfigs(q querier, configs []*model.RoutingConfig) ([]*model.RoutingConfig, error) {
+	if len(configs) == 0 {
+		return configs, nil
+	}
+	ids := make([]string, 0, len(configs))
+	for _, conf := range configs {
+		ids = append(ids, conf.ID)
+	}
+	in, args := inClause(ids)
+	services, err := s.services.find(q, "id IN "+in, args...)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*model.Service, len(services))
+	for _, svc := range services {
+		byID[svc.ID] = svc
+	}
+	for _, conf := range configs {
+		if svc, ok := byID[conf.ID]; ok {
+			conf.ServiceName = svc.Name
+			conf.NamespaceName = svc.Namespace
+		}
+	}
+	return configs, nil
+}
+
+// EnableRouting 设置路由规则是否启用
+func (s *sqliteStore) EnableRouting(conf *model.RouterConfig) error {
+	return s.EnableRoutingTx(nil, conf)
+}
+
+// EnableRoutingTx 在事务中设置路由规则是否启用
+func (s *sqliteStore) EnableRoutingTx(tx store.Tx, conf *model.RouterConfig) error {
+	if conf == nil || conf.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "enable routing config missing id")
+	}
+	return s.update(tx, func(q querier) error {
+		saved, err := s.routerConfigs.get(q, conf.ID)
+		if err != nil {
+			return err
+		}
+		if saved == nil || !saved.Valid {
+			return store.NewStatusError(store.AffectedRowsNotMatch, "routing config not found: "+conf.ID)
+		}
+		now := s.now()
+		saved.Enable = conf.Enable
+		saved.Revision = conf.Revision
+		saved.ModifyBy = modifyBy(conf.ModifyBy, saved.ModifyBy)
+		saved.ModifyTime = now
+		if conf.Enable {
+			saved.EnableTime = now
+		}
+		return s.routerConfigs.put(q, saved)
+	})
+}
+
+// CreateRoutingConfigV2 新增一个路由配置
+func (s *sqliteStore) CreateRoutingConfigV2(conf *model.RouterConfig) error {
+	return s.CreateRoutingConfigV2Tx(nil, conf)
+}
+
+// CreateRoutingConfigV2Tx 新增一个路由配置
+func (s *sqliteStore) CreateRoutingConfigV2Tx(tx store.Tx, conf *model.RouterConfig) error {
+	if conf == nil || conf.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "create routing config missing id")
+	}
+	return s.update(tx, func(q querier) error {
+		old, err := s.routerConfigs.get(q, conf.ID)
+		if err != nil {
+			return err
+		}
+		if old != nil && old.Valid {
+			return store.NewStatusError(store.DuplicateEntryErr, "routing config already exists: "+conf.ID)
+		}
+		now := s.now()
+		saved := *conf
+		saved.Valid = true
+		saved.CreateTime = now
+		saved.ModifyTime = now
+		if saved.Enable {
+			saved.EnableTime = now
+		}
+		return s.routerConfigs.put(q, &saved)
+	})
+}
+
+// UpdateRoutingConfigV2 更新一个路由配置
+func (s *sqliteStore) UpdateRoutingConfigV2(conf *model.RouterConfig) error {
+	return s.UpdateRoutingConfigV2Tx(nil, conf)
+}
+
+// UpdateRoutingConfigV2Tx 更新一个路由配置
+func (s *sqliteStore) UpdateRoutingConfigV2Tx(tx store.Tx, conf *model.RouterConfig) error {
+	return s.updateRoutingConfigV2(tx, conf, nil)
+}
+
+// updateRoutingConfigV2 更新一个路由配置，expectRevision 不为空时只有当前的 Revision 与其一致才会更新
+func (s *sqliteStore) updateRoutingConfigV2(tx store.Tx, conf *model.RouterConfig, expectRevision *string) error {
+	if conf == nil || conf.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "update routing config missing id")
+	}
+	return s.update(tx, func(q querier) error {
+		saved, err := s.routerConfigs.get(q, conf.ID)
+		if err != nil {
+			return err
+		}
+		if saved == nil || !saved.Valid {
+			return store.NewStatusError(store.AffectedRowsNotMatch, "routing config not found: "+conf.ID)
+		}
+		if err := checkRevision("routing config", conf.ID, saved.Revision, expectRevision); err != nil {
+			return err
+		}
+		saved.Name = conf.Name
+		saved.Namespace = conf.Namespace
+		saved.Policy = conf.Policy
+		saved.Config = conf.Config
+		saved.Priority = conf.Priority
+		saved.Revision = conf.Revision
+		saved.Description = conf.Description
+		saved.ModifyBy = modifyBy(conf.ModifyBy, saved.ModifyBy)
+		saved.ModifyTime = s.now()
+		return s.routerConfigs.put(q, saved)
+	})
+}
+
+// DeleteRoutingConfigV2 删除一个路由配置，实际是把valid置为false
+func (s *sqliteStore) DeleteRoutingConfigV2(ruleID string) error {
+	return s.DeleteRoutingConfigV2Tx(nil, ruleID)
+}
+
+// DeleteRoutingConfigV2Tx 在事务中删除一个路由配置，实际是把valid置为false
+func (s *sqliteStore) DeleteRoutingConfigV2Tx(tx store.Tx, ruleID string) error {
+	return s.update(tx, func(q querier) error {
+		saved, err := s.routerConfigs.get(q, ruleID)
+		if err != nil || saved == nil || !saved.Valid {
+			return err
+		}
+		saved.Valid = false
+		saved.ModifyTime = s.now()
+		return s.routerConfigs.put(q, saved)
+	})
+}
+
+// GetRoutingConfigsV2ForCache 通过mtime拉取增量的路由配置信息
+func (s *sqliteStore) GetRoutingConfigsV2ForCache(mtime time.Time, firstUpdate bool) ([]*model.RouterConfig, error) {
+	return query(s, nil, func(q querier) ([]*model.RouterConfig, error) {
+		return s.routerConfigs.since(q, mtime, firstUpdate)
+	})
+}
+
+// GetRoutingConfigV2WithID 根据规则ID拉取路由配置
+func (s *sqliteStore) GetRoutingConfigV2WithID(id string) (*model.RouterConfig, error) {
+	return s.GetRoutingConfigV2WithIDTx(nil, id)
+}
+
+// GetRoutingConfigV2WithIDTx 根据规则ID拉取路由配置
+func (s *sqliteStore) GetRoutingConfigV2WithIDTx(tx store.Tx, id string) (*model.RouterConfig, error) {
+	return query(s, tx, func(q querier) (*model.RouterConfig, error) {
+		return s.routerConfigs.first(q, "id = ? AND valid = 1", id)
+	})
+}
+
+func routingConfigGetters(item *model.RoutingConfig) map[string]func() string {
+	return map[string]func() string{
+		"id":        func() string { return item.ID },
+		"name":      func() string { return item.ServiceName },
+		"service":   func() string { return item.ServiceName },
+		"namespace": func() string { return item.NamespaceName },
+	}
+}
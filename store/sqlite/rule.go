@@ -0,0 +1,517 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqlite
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// CreateRateLimit 新增限流规则
+func (s *sqliteStore) CreateRateLimit(limit *model.RateLimit) error {
+	return s.CreateRateLimitTx(nil, limit)
+}
+
+// CreateRateLimitTx 在事务中新增限流规则
+func (s *sqliteStore) CreateRateLimitTx(tx store.Tx, limit *model.RateLimit) error {
+	if limit == nil || limit.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "create rate limit missing id")
+	}
+	return s.update(tx, func(q querier) error {
+		old, err := s.rateLimits.get(q, limit.ID)
+		if err != nil {
+			return err
+		}
+		if old != nil && old.Valid {
+			return store.NewStatusError(store.DuplicateEntryErr, "rate limit already exists: "+limit.ID)
+		}
+		now := s.now()
+		saved := *limit
+		saved.Valid = true
+		saved.CreateTime = now
+		saved.ModifyTime = now
+		if !saved.Disable {
+			saved.EnableTime = now
+		}
+		return s.rateLimits.put(q, &saved)
+	})
+}
+
+// UpdateRateLimit 更新限流规则
+func (s *sqliteStore) UpdateRateLimit(limit *model.RateLimit) error {
+	return s.updateRateLimit(nil, limit, nil)
+}
+
+// UpdateRateLimitTx 在事务中更新限流规则
+func (s *sqliteStore) UpdateRateLimitTx(tx store.Tx, limit *model.RateLimit) error {
+	return s.updateRateLimit(tx, limit, nil)
+}
+
+// updateRateLimit 更新限流规则，expectRevision 不为空时只有当前的 Revision 与其一致才会更新
+func (s *sqliteStore) updateRateLimit(tx store.Tx, limit *model.RateLimit, expectRevision *string) error {
+	if limit == nil || limit.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "update rate limit missing id")
+	}
+	return s.update(tx, func(q querier) error {
+		saved, err := s.rateLimits.get(q, limit.ID)
+		if err != nil {
+			return err
+		}
+		if saved == nil || !saved.Valid {
+			return store.NewStatusError(store.AffectedRowsNotMatch, "rate limit not found: "+limit.ID)
+		}
+		if err := checkRevision("rate limit", limit.ID, saved.Revision, expectRevision); err != nil {
+			return err
+		}
+		now := s.now()
+		updated := *limit
+		updated.Valid = true
+		updated.CreateTime = saved.CreateTime
+		updated.CreateBy = saved.CreateBy
+		updated.ModifyBy = modifyBy(updated.ModifyBy, saved.ModifyBy)
+		updated.ModifyTime = now
+		updated.EnableTime = saved.EnableTime
+		if saved.Disable && !updated.Disable {
+			updated.EnableTime = now
+		}
+		return s.rateLimits.put(q, &updated)
+	})
+}
+
+// EnableRateLimit 启用限流规则
+func (s *sqliteStore) EnableRateLimit(limit *model.RateLimit) error {
+	return s.EnableRateLimitTx(nil, limit)
+}
+
+// EnableRateLimitTx 在事务中启用限流规则
+func (s *sqliteStore) EnableRateLimitTx(tx store.Tx, limit *model.RateLimit) error {
+	if limit == nil || limit.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "enable rate limit missing id")
+	}
+	return s.update(tx, func(q querier) error {
+		saved, err := s.rateLimits.get(q, limit.ID)
+		if err != nil {
+			return err
+		}
+		if saved == nil || !saved.Valid {
+			return store.NewStatusError(store.AffectedRowsNotMatch, "rate limit not found: "+limit.ID)
+		}
+		now := s.now()
+		saved.Disable = limit.Disable
+		saved.Revision = limit.Revision
+		saved.ModifyBy = modifyBy(limit.ModifyBy, saved.ModifyBy)
+		saved.ModifyTime = now
+		if !limit.Disable {
+			saved.EnableTime = now
+		}
+		return s.rateLimits.put(q, saved)
+	})
+}
+
+// DeleteRateLimit 删除限流规则，实际是把valid置为false
+func (s *sqliteStore) DeleteRateLimit(limit *model.RateLimit) error {
+	return s.DeleteRateLimitTx(nil, limit)
+}
+
+// DeleteRateLimitTx 在事务中删除限流规则，实际是把valid置为false
+func (s *sqliteStore) DeleteRateLimitTx(tx store.Tx, limit *model.RateLimit) error {
+	if limit == nil || limit.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "delete rate limit missing id")
+	}
+	return s.update(tx, func(q querier) error {
+		saved, err := s.rateLimits.get(q, limit.ID)
+		if err != nil || saved == nil || !saved.Valid {
+			return err
+		}
+		saved.Valid = false
+		saved.Revision = limit.Revision
+		saved.ModifyBy = modifyBy(limit.ModifyBy, saved.ModifyBy)
+		saved.ModifyTime = s.now()
+		return s.rateLimits.put(q, saved)
+	})
+}
+
+// GetExtendRateLimits 根据过滤条件拉取限流规则
+func (s *sqliteStore) GetExtendRateLimits(filter map[string]string, offset uint32, limit uint32) (
+	uint32, []*model.RateLimit, error) {
+	limits, err := query(s, nil, func(q querier) ([]*model.RateLimit, error) {
+		return s.rateLimits.valid(q, "")
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	ret := make([]*model.RateLimit, 0, len(limits))
+	for _, item := range limits {
+		item := item
+		getters := map[string]func() string{
+			"id":        func() string { return item.ID },
+			"name":      func() string { return item.Name },
+			"service":   func() string { return item.ServiceName },
+			"namespace": func() string { return item.NamespaceName },
+			"method":    func() string { return item.Method },
+			"disable":   func() string { return strconv.FormatBool(item.Disable) },
+		}
+		if matchFilters(filter, getters) {
+			ret = append(ret, item)
+		}
+	}
+	sortByModifyTime(ret, func(r *model.RateLimit) time.Time { return r.ModifyTime },
+		func(r *model.RateLimit) string { return r.ID })
+	return uint32(len(ret)), paginate(ret, offset, limit), nil
+}
+
+// GetRateLimitWithID 根据限流ID拉取限流规则
+func (s *sqliteStore) GetRateLimitWithID(id string) (*model.RateLimit, error) {
+	return query(s, nil, func(q querier) (*model.RateLimit, error) {
+		return s.rateLimits.first(q, "id = ? AND valid = 1", id)
+	})
+}
+
+// GetRateLimitsForCache 根据修改时间拉取增量限流规则
+func (s *sqliteStore) GetRateLimitsForCache(mtime time.Time, firstUpdate bool) ([]*model.RateLimit, error) {
+	return query(s, nil, func(q querier) ([]*model.RateLimit, error) {
+		return s.rateLimits.since(q, mtime, firstUpdate)
+	})
+}
+
+// CreateCircuitBreakerRule create general circuitbreaker rule
+func (s *sqliteStore) CreateCircuitBreakerRule(cbRule *model.CircuitBreakerRule) error {
+	return s.CreateCircuitBreakerRuleTx(nil, cbRule)
+}
+
+// CreateCircuitBreakerRuleTx create general circuitbreaker rule in transaction
+func (s *sqliteStore) CreateCircuitBreakerRuleTx(tx store.Tx, cbRule *model.CircuitBreakerRule) error {
+	if cbRule == nil || cbRule.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "create circuitbreaker rule missing id")
+	}
+	return s.update(tx, func(q querier) error {
+		old, err := s.circuitBreakers.get(q, cbRule.ID)
+		if err != nil {
+			return err
+		}
+		if old != nil && old.Valid {
+			return store.NewStatusError(store.DuplicateEntryErr, "circuitbreaker rule already exists: "+cbRule.ID)
+		}
+		now := s.now()
+		saved := *cbRule
+		saved.Valid = true
+		saved.CreateTime = now
+		saved.ModifyTime = now
+		if saved.Enable {
+			saved.EnableTime = now
+		}
+		return s.circuitBreakers.put(q, &saved)
+	})
+}
+
+// UpdateCircuitBreakerRule update general circuitbreaker rule
+func (s *sqliteStore) UpdateCircuitBreakerRule(cbRule *model.CircuitBreakerRule) error {
+	return s.updateCircuitBreakerRule(nil, cbRule, nil)
+}
+
+// UpdateCircuitBreakerRuleTx update general circuitbreaker rule in transaction
+func (s *sqliteStore) UpdateCircuitBreakerRuleTx(tx store.Tx, cbRule *model.CircuitBreakerRule) error {
+	return s.updateCircuitBreakerRule(tx, cbRule, nil)
+}
+
+// updateCircuitBreakerRule 更新熔断规则，expectRevision 不为空时只有当前的 Revision 与其一致才会更新
+func (s *sqliteStore) updateCircuitBreakerRule(tx store.Tx, cbRule *model.CircuitBreakerRule, expectRevision *string) error {
+	if cbRule == nil || cbRule.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "update circuitbreaker rule missing id")
+	}
+	return s.update(tx, func(q querier) error {
+		saved, err := s.circuitBreakers.get(q, cbRule.ID)
+		if err != nil {
+			return err
+		}
+		if saved == nil || !saved.Valid {
+			return store.NewStatusError(store.AffectedRowsNotMatch, "circuitbreaker rule not found: "+cbRule.ID)
+		}
+		if err := checkRevision("circuitbreaker rule", cbRule.ID, saved.Revision, expectRevision); err != nil {
+			return err
+		}
+		now := s.now()
+		updated := *cbRule
+		updated.Valid = true
+		updated.CreateTime = saved.CreateTime
+		updated.CreateBy = saved.CreateBy
+		updated.ModifyBy = modifyBy(updated.ModifyBy, saved.ModifyBy)
+		updated.ModifyTime = now
+		updated.EnableTime = saved.EnableTime
+		if !saved.Enable && updated.Enable {
+			updated.EnableTime = now
+		}
+		return s.circuitBreakers.put(q, &updated)
+	})
+}
+
+// DeleteCircuitBreakerRule delete general circuitbreaker rule, only mark valid as false
+func (s *sqliteStore) DeleteCircuitBreakerRule(id string) error {
+	return s.DeleteCircuitBreakerRuleTx(nil, id)
+}
+
+// DeleteCircuitBreakerRuleTx delete general circuitbreaker rule in transaction, only mark valid as false
+func (s *sqliteStore) DeleteCircuitBreakerRuleTx(tx store.Tx, id string) error {
+	return s.update(tx, func(q querier) error {
+		saved, err := s.circuitBreakers.get(q, id)
+		if err != nil || saved == nil || !saved.Valid {
+			return err
+		}
+		saved.Valid = false
+		saved.ModifyTime = s.now()
+		return s.circuitBreakers.put(q, saved)
+	})
+}
+
+// HasCircuitBreakerRule check circuitbreaker rule exists
+func (s *sqliteStore) HasCircuitBreakerRule(id string) (bool, error) {
+	count, err := query(s, nil, func(q querier) (uint64, error) {
+		return s.circuitBreakers.count(q, "id = ? AND valid = 1", id)
+	})
+	return count > 0, err
+}
+
+// HasCircuitBreakerRuleByName check circuitbreaker rule exists for name
+func (s *sqliteStore) HasCircuitBreakerRuleByName(name string, namespace string) (bool, error) {
+	return s.HasCircuitBreakerRuleByNameExcludeId(name, namespace, "")
+}
+
+// HasCircuitBreakerRuleByNameExcludeId check circuitbreaker rule exists for name not this id
+func (s *sqliteStore) HasCircuitBreakerRuleByNameExcludeId(name string, namespace string, id string) (bool, error) {
+	count, err := query(s, nil, func(q querier) (uint64, error) {
+		return s.circuitBreakers.count(q, "namespace = ? AND name = ? AND id != ? AND valid = 1", namespace, name, id)
+	})
+	return count > 0, err
+}
+
+// GetCircuitBreakerRules get all circuitbreaker rules by query and limit
+func (s *sqliteStore) GetCircuitBreakerRules(filter map[string]string, offset uint32, limit uint32) (
+	uint32, []*model.CircuitBreakerRule, error) {
+	rules, err := query(s, nil, func(q querier) ([]*model.CircuitBreakerRule, error) {
+		return s.circuitBreakers.valid(q, "")
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	ret := make([]*model.CircuitBreakerRule, 0, len(rules))
+	for _, item := range rules {
+		item := item
+		getters := map[string]func() string{
+			"id":            func() string { return item.ID },
+			"name":          func() string { return item.Name },
+			"namespace":     func() string { return item.Namespace },
+			"enable":        func() string { return strconv.FormatBool(item.Enable) },
+			"level":         func() string { return strconv.Itoa(item.Level) },
+			"src_service":   func() string { return item.SrcService },
+			"src_namespace": func() string { return item.SrcNamespace },
+			"dst_service":   func() string { return item.DstService },
+			"dst_namespace": func() string { return item.DstNamespace },
+			"dst_method":    func() string { return item.DstMethod },
+			"description":   func() string { return item.Description },
+		}
+		if matchFilters(filter, getters) {
+			ret = append(ret, item)
+		}
+	}
+	sortByModifyTime(ret, func(r *model.CircuitBreakerRule) time.Time { return r.ModifyTime },
+		func(r *model.CircuitBreakerRule) string { return r.ID })
+	return uint32(len(ret)), paginate(ret, offset, limit), nil
+}
+
+// GetCircuitBreakerRulesForCache get increment circuitbreaker rules
+func (s *sqliteStore) GetCircuitBreakerRulesForCache(mtime time.Time, firstUpdate bool) (
+	[]*model.CircuitBreakerRule, error) {
+	return query(s, nil, func(q querier) ([]*model.CircuitBreakerRule, error) {
+		return s.circuitBreakers.since(q, mtime, firstUpdate)
+	})
+}
+
+// EnableCircuitBreakerRule enable specific circuitbreaker rule
+func (s *sqliteStore) EnableCircuitBreakerRule(cbRule *model.CircuitBreakerRule) error {
+	return s.EnableCircuitBreakerRuleTx(nil, cbRule)
+}
+
+// EnableCircuitBreakerRuleTx enable specific circuitbreaker rule in transaction
+func (s *sqliteStore) EnableCircuitBreakerRuleTx(tx store.Tx, cbRule *model.CircuitBreakerRule) error {
+	if cbRule == nil || cbRule.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "enable circuitbreaker rule missing id")
+	}
+	return s.update(tx, func(q querier) error {
+		saved, err := s.circuitBreakers.get(q, cbRule.ID)
+		if err != nil {
+			return err
+		}
+		if saved == nil || !saved.Valid {
+			return store.NewStatusError(store.AffectedRowsNotMatch, "circuitbreaker rule not found: "+cbRule.ID)
+		}
+		now := s.now()
+		saved.Enable = cbRule.Enable
+		saved.Revision = cbRule.Revision
+		saved.ModifyBy = modifyBy(cbRule.ModifyBy, saved.ModifyBy)
+		saved.ModifyTime = now
+		if cbRule.Enable {
+			saved.EnableTime = now
+		}
+		return s.circuitBreakers.put(q, saved)
+	})
+}
+
+// CreateFaultDetectRule create fault detect rule
+func (s *sqliteStore) CreateFaultDetectRule(conf *model.FaultDetectRule) error {
+	return s.CreateFaultDetectRuleTx(nil, conf)
+}
+
+// CreateFaultDetectRuleTx create fault detect rule in transaction
+func (s *sqliteStore) CreateFaultDetectRuleTx(tx store.Tx, conf *model.FaultDetectRule) error {
+	if conf == nil || conf.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "create fault detect rule missing id")
+	}
+	return s.update(tx, func(q querier) error {
+		old, err := s.faultDetectRules.get(q, conf.ID)
+		if err != nil {
+			return err
+		}
+		if old != nil && old.Valid {
+			return store.NewStatusError(store.DuplicateEntryErr, "fault detect rule already exists: "+conf.ID)
+		}
+		now := s.now()
+		saved := *conf
+		saved.Valid = true
+		saved.CreateTime = now
+		saved.ModifyTime = now
+		return s.faultDetectRules.put(q, &saved)
+	})
+}
+
+// UpdateFaultDetectRule update fault detect rule
+func (s *sqliteStore) UpdateFaultDetectRule(conf *model.FaultDetectRule) error {
+	return s.updateFaultDetectRule(nil, conf, nil)
+}
+
+// UpdateFaultDetectRuleTx update fault detect rule in transaction
+func (s *sqliteStore) UpdateFaultDetectRuleTx(tx store.Tx, conf *model.FaultDetectRule) error {
+	return s.updateFaultDetectRule(tx, conf, nil)
+}
+
+// updateFaultDetectRule 更新主动探测规则，expectRevision 不为空时只有当前的 Revision 与其一致才会更新
+func (s *sqliteStore) updateFaultDetectRule(tx store.Tx, conf *model.FaultDetectRule, expectRevision *string) error {
+	if conf == nil || conf.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "update fault detect rule missing id")
+	}
+	return s.update(tx, func(q querier) error {
+		saved, err := s.faultDetectRules.get(q, conf.ID)
+		if err != nil {
+			return err
+		}
+		if saved == nil || !saved.Valid {
+			return store.NewStatusError(store.AffectedRowsNotMatch, "fault detect rule not found: "+conf.ID)
+		}
+		if err := checkRevision("fault detect rule", conf.ID, saved.Revision, expectRevision); err != nil {
+			return err
+		}
+		updated := *conf
+		updated.Valid = true
+		updated.CreateTime = saved.CreateTime
+		updated.CreateBy = saved.CreateBy
+		updated.ModifyBy = modifyBy(updated.ModifyBy, saved.ModifyBy)
+		updated.ModifyTime = s.now()
+		return s.faultDetectRules.put(q, &updated)
+	})
+}
+
+// DeleteFaultDetectRule delete fault detect rule, only mark valid as false
+func (s *sqliteStore) DeleteFaultDetectRule(id string) error {
+	return s.DeleteFaultDetectRuleTx(nil, id)
+}
+
+// DeleteFaultDetectRuleTx delete fault detect rule in transaction, only mark valid as false
+func (s *sqliteStore) DeleteFaultDetectRuleTx(tx store.Tx, id string) error {
+	return s.update(tx, func(q querier) error {
+		saved, err := s.faultDetectRules.get(q, id)
+		if err != nil || saved == nil || !saved.Valid {
+			return err
+		}
+		saved.Valid = false
+		saved.ModifyTime = s.now()
+		return s.faultDetectRules.put(q, saved)
+	})
+}
+
+// HasFaultDetectRule check fault detect rule exists
+func (s *sqliteStore) HasFaultDetectRule(id string) (bool, error) {
+	count, err := query(s, nil, func(q querier) (uint64, error) {
+		return s.faultDetectRules.count(q, "id = ? AND valid = 1", id)
+	})
+	return count > 0, err
+}
+
+// HasFaultDetectRuleByName check fault detect rule exists by name
+func (s *sqliteStore) HasFaultDetectRuleByName(name string, namespace string) (bool, error) {
+	return s.HasFaultDetectRuleByNameExcludeId(name, namespace, "")
+}
+
+// HasFaultDetectRuleByNameExcludeId check fault detect rule exists by name not this id
+func (s *sqliteStore) HasFaultDetectRuleByNameExcludeId(name string, namespace string, id string) (bool, error) {
+	count, err := query(s, nil, func(q querier) (uint64, error) {
+		return s.faultDetectRules.count(q, "namespace = ? AND name = ? AND id != ? AND valid = 1", namespace, name, id)
+	})
+	return count > 0, err
+}
+
+// GetFaultDetectRules get all fault detect rules by query and limit
+func (s *sqliteStore) GetFaultDetectRules(filter map[string]string, offset uint32, limit uint32) (
+	uint32, []*model.FaultDetectRule, error) {
+	rules, err := query(s, nil, func(q querier) ([]*model.FaultDetectRule, error) {
+		return s.faultDetectRules.valid(q, "")
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	ret := make([]*model.FaultDetectRule, 0, len(rules))
+	for _, item := range rules {
+		if matchFilters(filter, faultDetectRuleGetters(item)) {
+			ret = append(ret, item)
+		}
+	}
+	sortByModifyTime(ret, func(r *model.FaultDetectRule) time.Time { return r.ModifyTime },
+		func(r *model.FaultDetectRule) string { return r.ID })
+	return uint32(len(ret)), paginate(ret, offset, limit), nil
+}
+
+// GetFaultDetectRulesForCache get increment fault detect rules
+func (s *sqliteStore) GetFaultDetectRulesForCache(mtime time.Time, firstUpdate bool) (
+	[]*model.FaultDetectRule, error) {
+	return query(s, nil, func(q querier) ([]*model.FaultDetectRule, error) {
+		return s.faultDetectRules.since(q, mtime, firstUpdate)
+	})
+}
+
+func faultDetectRuleGetters(item *model.FaultDetectRule) map[string]func() string {
+	return map[string]func() string{
+		"id":            func() string { return item.ID },
+		"name":          func() string { return item.Name },
+		"namespace":     func() string { return item.Namespace },
+		"dst_service":   func() string { return item.DstService },
+		"dst_namespace": func() string { return item.DstNamespace },
+		"dst_method":    func() string { return item.DstMethod },
+		"description":   func() string { return item.Description },
+	}
+}
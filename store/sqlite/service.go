@@ -0,0 +1,457 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqlite
+
+import (
+	"sort"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+const (
+	// systemNamespace 系统服务所在的命名空间
+	systemNamespace = "Polaris"
+)
+
+// AddService 保存一个服务
+func (s *sqliteStore) AddService(service *model.Service) error {
+	return s.AddServiceTx(nil, service)
+}
+
+// AddServiceTx 在事务中保存一个服务
+func (s *sqliteStore) AddServiceTx(tx store.Tx, service *model.Service) error {
+	if service == nil || service.ID == "" || service.Name == "" || service.Namespace == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "add service missing some params")
+	}
+	return s.update(tx, func(q querier) error {
+		ns, err := s.namespaces.get(q, service.Namespace)
+		if err != nil {
+			return err
+		}
+		if ns == nil || !ns.Valid {
+			return store.NewStatusError(store.NotFoundNamespace, "namespace not found: "+service.Namespace)
+		}
+		old, err := s.services.get(q, service.ID)
+		if err != nil {
+			return err
+		}
+		if old != nil && old.Valid {
+			return store.NewStatusError(store.DuplicateEntryErr, "service id already exists: "+service.ID)
+		}
+		if old, err = s.findService(q, service.Name, service.Namespace); err != nil {
+			return err
+		}
+		if old != nil {
+			if old.Valid {
+				return store.NewStatusError(store.DuplicateEntryErr,
+					"service already exists: "+service.Namespace+"/"+service.Name)
+			}
+			// 清理掉已经软删除的同名服务
+			if _, err := s.services.remove(q, "id = ?", old.ID); err != nil {
+				return err
+			}
+		}
+		now := s.now()
+		saved := *service
+		saved.Valid = true
+		touchService(&saved, now)
+		saved.CreateTime = now
+		saved.Ctime = now.Unix()
+		return s.services.put(q, &saved)
+	})
+}
+
+// DeleteService 删除服务，实际是把 valid 置为 false
+func (s *sqliteStore) DeleteService(id, serviceName, namespaceName string) error {
+	return s.DeleteServiceTx(nil, id, serviceName, namespaceName)
+}
+
+// DeleteServiceTx 在事务中删除服务，实际是把 valid 置为 false
+func (s *sqliteStore) DeleteServiceTx(tx store.Tx, id, serviceName, namespaceName string) error {
+	return s.update(tx, func(q querier) error {
+		saved, err := s.services.get(q, id)
+		if err != nil {
+			return err
+		}
+		if saved == nil {
+			if saved, err = s.findService(q, serviceName, namespaceName); err != nil {
+				return err
+			}
+		}
+		if saved == nil || !saved.Valid {
+			return nil
+		}
+		saved.Valid = false
+		touchService(saved, s.now())
+		return s.services.put(q, saved)
+	})
+}
+
+// DeleteServiceAlias 删除服务别名，实际是把 valid 置为 false
+func (s *sqliteStore) DeleteServiceAlias(name string, namespace string) error {
+	return s.DeleteServiceAliasTx(nil, name, namespace)
+}
+
+// DeleteServiceAliasTx 在事务中删除服务别名，实际是把 valid 置为 false
+func (s *sqliteStore) DeleteServiceAliasTx(tx store.Tx, name string, namespace string) error {
+	return s.update(tx, func(q querier) error {
+		saved, err := s.findService(q, name, namespace)
+		if err != nil {
+			return err
+		}
+		if saved == nil || !saved.Valid || saved.Reference == "" {
+			return nil
+		}
+		saved.Valid = false
+		touchService(saved, s.now())
+		return s.services.put(q, saved)
+	})
+}
+
+// UpdateServiceAlias 修改服务别名
+func (s *sqliteStore) UpdateServiceAlias(alias *model.Service, needUpdateOwner bool) error {
+	return s.UpdateServiceAliasTx(nil, alias, needUpdateOwner)
+}
+
+// UpdateServiceAliasTx 在事务中修改服务别名
+func (s *sqliteStore) UpdateServiceAliasTx(tx store.Tx, alias *model.Service, needUpdateOwner bool) error {
+	if alias == nil || alias.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "update service alias missing id")
+	}
+	return s.update(tx, func(q querier) error {
+		saved, err := s.services.get(q, alias.ID)
+		if err != nil {
+			return err
+		}
+		if saved == nil || !saved.Valid {
+			return store.NewStatusError(store.AffectedRowsNotMatch, "service alias not found: "+alias.ID)
+		}
+		saved.Reference = alias.Reference
+		saved.Comment = alias.Comment
+		saved.Token = alias.Token
+		saved.Revision = alias.Revision
+		saved.ExportTo = alias.ExportTo
+		saved.ModifyBy = modifyBy(alias.ModifyBy, saved.ModifyBy)
+		if needUpdateOwner {
+			saved.Owner = alias.Owner
+		}
+		touchService(saved, s.now())
+		return s.services.put(q, saved)
+	})
+}
+
+// UpdateService 更新服务
+func (s *sqliteStore) UpdateService(service *model.Service, needUpdateOwner bool) error {
+	return s.updateService(nil, service, needUpdateOwner, nil)
+}
+
+// UpdateServiceTx 在事务中更新服务
+func (s *sqliteStore) UpdateServiceTx(tx store.Tx, service *model.Service, needUpdateOwner bool) error {
+	return s.updateService(tx, service, needUpdateOwner, nil)
+}
+
+// updateService 更新服务，expectRevision 不为空时只有当前的 Revision 与其一致才会更新
+func (s *sqliteStore) updateService(tx store.Tx, service *model.Service, needUpdateOwner bool,
+	expectRevision *string) error {
+	if service == nil || service.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "update service missing id")
+	}
+	return s.update(tx, func(q querier) error {
+		saved, err := s.services.get(q, service.ID)
+		if err != nil {
+			return err
+		}
+		if saved == nil || !saved.Valid {
+			return store.NewStatusError(store.AffectedRowsNotMatch, "service not found: "+service.ID)
+		}
+		if err := checkRevision("service", service.ID, saved.Revision, expectRevision); err != nil {
+			return err
+		}
+		saved.Business = service.Business
+		saved.Ports = service.Ports
+		saved.Meta = service.Meta
+		saved.Comment = service.Comment
+		saved.Department = service.Department
+		saved.CmdbMod1 = service.CmdbMod1
+		saved.CmdbMod2 = service.CmdbMod2
+		saved.CmdbMod3 = service.CmdbMod3
+		saved.Revision = service.Revision
+		saved.PlatformID = service.PlatformID
+		saved.ServicePorts = service.ServicePorts
+		saved.ExportTo = service.ExportTo
+		saved.ModifyBy = modifyBy(service.ModifyBy, saved.ModifyBy)
+		if needUpdateOwner {
+			saved.Owner = service.Owner
+		}
+		touchService(saved, s.now())
+		return s.services.put(q, saved)
+	})
+}
+
+// UpdateServiceToken 更新服务token
+func (s *sqliteStore) UpdateServiceToken(serviceID string, token string, revision string) error {
+	return s.UpdateServiceTokenTx(nil, serviceID, token, revision)
+}
+
+// UpdateServiceTokenTx 在事务中更新服务token
+func (s *sqliteStore) UpdateServiceTokenTx(tx store.Tx, serviceID string, token string, revision string) error {
+	return s.update(tx, func(q querier) error {
+		saved, err := s.services.get(q, serviceID)
+		if err != nil {
+			return err
+		}
+		if saved == nil || !saved.Valid {
+			return store.NewStatusError(store.AffectedRowsNotMatch, "service not found: "+serviceID)
+		}
+		saved.Token = token
+		saved.Revision = revision
+		touchService(saved, s.now())
+		return s.services.put(q, saved)
+	})
+}
+
+// GetSourceServiceToken 获取源服务的token信息
+func (s *sqliteStore) GetSourceServiceToken(name string, namespace string) (*model.Service, error) {
+	saved, err := s.GetService(name, namespace)
+	if err != nil || saved == nil {
+		return nil, err
+	}
+	return &model.Service{
+		ID:         saved.ID,
+		Name:       saved.Name,
+		Namespace:  saved.Namespace,
+		Token:      saved.Token,
+		PlatformID: saved.PlatformID,
+	}, nil
+}
+
+// GetService 根据服务名和命名空间获取服务的详情
+func (s *sqliteStore) GetService(name string, namespace string) (*model.Service, error) {
+	return query(s, nil, func(q querier) (*model.Service, error) {
+		return s.services.first(q, "namespace = ? AND name = ? AND valid = 1", namespace, name)
+	})
+}
+
+// GetServiceByID 根据服务ID查询服务详情
+func (s *sqliteStore) GetServiceByID(id string) (*model.Service, error) {
+	return query(s, nil, func(q querier) (*model.Service, error) {
+		return s.services.first(q, "id = ? AND valid = 1", id)
+	})
+}
+
+// GetServices 根据相关条件查询对应服务及数目，结果按照 mtime 倒序排列
+func (s *sqliteStore) GetServices(serviceFilters, serviceMetas map[string]string,
+	instanceFilters *model.InstanceArgs, offset, limit uint32) (uint32, []*model.Service, error) {
+	services, err := query(s, nil, func(q querier) ([]*model.Service, error) {
+		services, err := s.services.valid(q, "")
+		if err != nil || instanceFilters == nil {
+			return services, err
+		}
+		instances, err := s.instances.valid(q, "")
+		if err != nil {
+			return nil, err
+		}
+		matched := make(map[string]struct{})
+		for _, ins := range instances {
+			if matchInstanceArgs(ins, instanceFilters) {
+				matched[ins.ServiceID] = struct{}{}
+			}
+		}
+		ret := make([]*model.Service, 0, len(services))
+		for _, svc := range services {
+			if _, ok := matched[svc.ID]; ok {
+				ret = append(ret, svc)
+			}
+		}
+		return ret, nil
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	ret := make([]*model.Service, 0, len(services))
+	for _, svc := range services {
+		if !matchFilters(serviceFilters, serviceGetters(svc)) || !matchMetadata(serviceMetas, svc.Meta) {
+			continue
+		}
+		ret = append(ret, svc)
+	}
+	sortServices(ret)
+	return uint32(len(ret)), paginate(ret, offset, limit), nil
+}
+
+// GetServicesCount 获取所有服务总数
+func (s *sqliteStore) GetServicesCount() (uint32, error) {
+	count, err := query(s, nil, func(q querier) (uint64, error) {
+		return s.services.count(q, "valid = 1")
+	})
+	return uint32(count), err
+}
+
+// GetMoreServices 获取增量services，disableBusiness 为 true 时只返回系统服务
+func (s *sqliteStore) GetMoreServices(mtime time.Time, firstUpdate, disableBusiness, needMeta bool) (
+	map[string]*model.Service, error) {
+	services, err := query(s, nil, func(q querier) ([]*model.Service, error) {
+		return s.services.since(q, mtime, firstUpdate)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := make(map[string]*model.Service, len(services))
+	for _, svc := range services {
+		if disableBusiness && svc.Namespace != systemNamespace {
+			continue
+		}
+		if !needMeta {
+			svc.Meta = nil
+		}
+		ret[svc.ID] = svc
+	}
+	return ret, nil
+}
+
+// GetServiceAliases 获取服务别名列表
+func (s *sqliteStore) GetServiceAliases(filter map[string]string, offset uint32, limit uint32) (
+	uint32, []*model.ServiceAlias, error) {
+	var aliases, sources []*model.Service
+	err := s.view(nil, func(q querier) error {
+		var err error
+		if aliases, err = s.services.valid(q, "parent != ''"); err != nil {
+			return err
+		}
+		sources, err = s.services.valid(q, "")
+		return err
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	sourceByID := make(map[string]*model.Service, len(sources))
+	for _, svc := range sources {
+		sourceByID[svc.ID] = svc
+	}
+	ret := make([]*model.ServiceAlias, 0)
+	for _, svc := range aliases {
+		source, ok := sourceByID[svc.Reference]
+		if !ok {
+			continue
+		}
+		alias := &model.ServiceAlias{
+			ID:             svc.ID,
+			Alias:          svc.Name,
+			AliasNamespace: svc.Namespace,
+			ServiceID:      source.ID,
+			Service:        source.Name,
+			Namespace:      source.Namespace,
+			Owner:          svc.Owner,
+			Comment:        svc.Comment,
+			CreateTime:     svc.CreateTime,
+			ModifyTime:     svc.ModifyTime,
+			ExportTo:       svc.ExportTo,
+		}
+		getters := map[string]func() string{
+			"alias":           func() string { return alias.Alias },
+			"alias_namespace": func() string { return alias.AliasNamespace },
+			"service":         func() string { return alias.Service },
+			"namespace":       func() string { return alias.Namespace },
+			"owner":           func() string { return alias.Owner },
+		}
+		if !matchFilters(filter, getters) {
+			continue
+		}
+		ret = append(ret, alias)
+	}
+	sortByModifyTime(ret, func(a *model.ServiceAlias) time.Time { return a.ModifyTime },
+		func(a *model.ServiceAlias) string { return a.ID })
+	return uint32(len(ret)), paginate(ret, offset, limit), nil
+}
+
+// GetSystemServices 获取系统服务
+func (s *sqliteStore) GetSystemServices() ([]*model.Service, error) {
+	ret, err := query(s, nil, func(q querier) ([]*model.Service, error) {
+		return s.services.valid(q, "namespace = ?", systemNamespace)
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortServices(ret)
+	return ret, nil
+}
+
+// GetServicesBatch 批量获取服务id、负责人等信息
+func (s *sqliteStore) GetServicesBatch(services []*model.Service) ([]*model.Service, error) {
+	return query(s, nil, func(q querier) ([]*model.Service, error) {
+		ret := make([]*model.Service, 0, len(services))
+		for _, item := range services {
+			saved, err := s.services.first(q, "namespace = ? AND name = ? AND valid = 1", item.Namespace, item.Name)
+			if err != nil {
+				return nil, err
+			}
+			if saved != nil {
+				ret = append(ret, saved)
+			}
+		}
+		return ret, nil
+	})
+}
+
+// findService 根据服务名以及命名空间查找服务，包括已经软删除的服务，同名服务优先返回有效的记录
+func (s *sqliteStore) findService(q querier, name, namespace string) (*model.Service, error) {
+	return s.services.first(q, "namespace = ? AND name = ? ORDER BY valid DESC", namespace, name)
+}
+
+// matchInstanceArgs 判断实例是否满足 GetServices 的实例过滤条件
+func matchInstanceArgs(ins *model.Instance, args *model.InstanceArgs) bool {
+	if len(args.Hosts) > 0 && !containsString(args.Hosts, ins.Proto.GetHost().GetValue()) {
+		return false
+	}
+	if len(args.Ports) > 0 && !containsUint32(args.Ports, ins.Proto.GetPort().GetValue()) {
+		return false
+	}
+	return matchMetadata(args.Meta, ins.Proto.GetMetadata())
+}
+
+func touchService(svc *model.Service, now time.Time) {
+	svc.ModifyTime = now
+	svc.Mtime = now.Unix()
+}
+
+func serviceGetters(svc *model.Service) map[string]func() string {
+	return map[string]func() string{
+		"id":          func() string { return svc.ID },
+		"name":        func() string { return svc.Name },
+		"namespace":   func() string { return svc.Namespace },
+		"business":    func() string { return svc.Business },
+		"department":  func() string { return svc.Department },
+		"owner":       func() string { return svc.Owner },
+		"cmdb_mod1":   func() string { return svc.CmdbMod1 },
+		"cmdb_mod2":   func() string { return svc.CmdbMod2 },
+		"cmdb_mod3":   func() string { return svc.CmdbMod3 },
+		"platform_id": func() string { return svc.PlatformID },
+		"reference":   func() string { return svc.Reference },
+	}
+}
+
+func sortServices(services []*model.Service) {
+	sort.Slice(services, func(i, j int) bool {
+		if !services[i].ModifyTime.Equal(services[j].ModifyTime) {
+			return services[i].ModifyTime.After(services[j].ModifyTime)
+		}
+		return services[i].ID < services[j].ID
+	})
+}
@@ -0,0 +1,97 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqlite_test
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+	"github.com/polarismesh/polaris-plugin-api/store/sqlite"
+	"github.com/polarismesh/polaris-plugin-api/store/storetest"
+)
+
+func TestStore(t *testing.T) {
+	dir := t.TempDir()
+	seq := 0
+	storetest.RunSuite(t, func() (store.Store, error) {
+		seq++
+		return sqlite.Open(filepath.Join(dir, fmt.Sprintf("polaris-%d.db", seq)))
+	})
+}
+
+func TestReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "polaris.db")
+	s, err := sqlite.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddNamespace(&model.Namespace{Name: "ns", Owner: "polaris"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Destroy(); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = sqlite.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = s.Destroy()
+	}()
+	ns, err := s.GetNamespace("ns")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ns == nil || ns.Owner != "polaris" {
+		t.Fatalf("namespace is not persisted: %+v", ns)
+	}
+}
+
+func TestErrorClassifier(t *testing.T) {
+	s, err := sqlite.Open(filepath.Join(t.TempDir(), "polaris.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = s.Destroy()
+	}()
+
+	// 分类器在 Initialize 时注册，之后 store.Error 可以识别 SQLite 驱动返回的原生错误
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "raw.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+	if _, err := db.Exec("CREATE TABLE t (id TEXT PRIMARY KEY)"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("INSERT INTO t (id) VALUES ('a')"); err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Exec("INSERT INTO t (id) VALUES ('a')")
+	if code := store.Code(store.Error(err)); code != store.DuplicateEntryErr {
+		t.Fatalf("expect DuplicateEntryErr, got %v: %v", code, err)
+	}
+}
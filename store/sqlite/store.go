@@ -0,0 +1,330 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package sqlite 基于 SQLite 的 store.Store 实现，数据保存在本地文件中，不依赖外部数据库，
+// 适用于边缘站点、单机部署以及本地开发。
+//
+// 每类资源保存在一张表中，主键、命名空间、名字、父资源、修改时间以及是否有效保存为可以索引的列，
+// 完整的数据以 JSON 的形式保存在 data 列。所有的写操作都在 SQLite 的写事务中执行，
+// SQLite 同一时刻只允许一个写事务，持有 StartTx 开启的事务时，同一个 goroutine 中不应该再执行非事务的写操作，
+// 否则会在等待 busyTimeout 之后返回 store.DeadlockErr。
+//
+// 插件是单独的 Go module，SQLite 驱动及其依赖不会引入到 polaris-plugin-api 中，需要时单独引入。
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	// SQLite 驱动，纯 Go 实现，不依赖 cgo
+	_ "modernc.org/sqlite"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+	"github.com/polarismesh/polaris-plugin-api/store/sqlite/internal/keylock"
+)
+
+const (
+	// StoreName SQLite 存储插件的名字
+	StoreName = "sqlite"
+	// OptionPath 配置项：数据库文件的路径
+	OptionPath = "path"
+	// OptionBusyTimeout 配置项：等待数据库写锁以及 Transaction 资源锁的超时时间，例如 "5s"
+	OptionBusyTimeout = "busyTimeout"
+	// OptionAutoMigrate 配置项：启动时 schema 版本落后是否自动执行迁移，默认拒绝启动
+	OptionAutoMigrate = "autoMigrate"
+
+	defaultPath        = "polaris.db"
+	defaultBusyTimeout = 5 * time.Second
+)
+
+func init() {
+	store.Register(StoreName, New())
+}
+
+// registerClassifier 第一次初始化存储时注册 SQLite 的错误分类器，未使用 SQLite 存储时不影响其他存储的错误识别
+var registerClassifier sync.Once
+
+var (
+	_ store.Store                = (*sqliteStore)(nil)
+	_ store.NamingTxStore        = (*sqliteStore)(nil)
+	_ store.DistributedLockStore = (*sqliteStore)(nil)
+)
+
+// sqliteStore 基于 SQLite 的 store.Store 实现
+type sqliteStore struct {
+	db          *sql.DB
+	path        string
+	busyTimeout time.Duration
+	autoMigrate bool
+	// now 获取当前时间，用于 CreateTime/ModifyTime 以及增量查询，可通过 WithClock 替换
+	now func() time.Time
+	// host 当前节点的地址，用于 leader 选举
+	host string
+	// locks Transaction 中 LockNamespace、LockService 等方法持有的资源锁
+	locks *keylock.Locks
+
+	namespaces       *table[model.Namespace]
+	services         *table[model.Service]
+	instances        *table[model.Instance]
+	l5Extends        *table[l5Extend]
+	routingConfigs   *table[model.RoutingConfig]
+	routerConfigs    *table[model.RouterConfig]
+	rateLimits       *table[model.RateLimit]
+	circuitBreakers  *table[model.CircuitBreakerRule]
+	faultDetectRules *table[model.FaultDetectRule]
+	contracts        *table[model.ServiceContract]
+	clients          *table[model.Client]
+	grayResources    *table[model.GrayResource]
+	leaders          *table[model.LeaderElection]
+	bootstraps       *table[bootstrapLock]
+	distLocks        *table[model.DistributedLock]
+	configGroups     *table[model.ConfigFileGroup]
+	configFiles      *table[model.ConfigFile]
+	configReleases   *table[model.ConfigFileRelease]
+	configHistories  *table[model.ConfigFileReleaseHistory]
+	configTemplates  *table[model.ConfigFileTemplate]
+	users            *table[model.User]
+	groups           *table[model.UserGroup]
+	strategies       *table[model.StrategyDetail]
+}
+
+// Option SQLite 存储的可选配置
+type Option func(s *sqliteStore)
+
+// WithPath 设置数据库文件的路径，Initialize 时配置中的 path 优先
+func WithPath(path string) Option {
+	return func(s *sqliteStore) {
+		s.path = path
+	}
+}
+
+// WithBusyTimeout 设置等待数据库写锁以及 Transaction 资源锁的超时时间
+func WithBusyTimeout(timeout time.Duration) Option {
+	return func(s *sqliteStore) {
+		if timeout > 0 {
+			s.busyTimeout = timeout
+		}
+	}
+}
+
+// WithAutoMigrate 设置启动时 schema 版本落后是否自动执行迁移，Initialize 时配置中的 autoMigrate 优先
+func WithAutoMigrate(autoMigrate bool) Option {
+	return func(s *sqliteStore) {
+		s.autoMigrate = autoMigrate
+	}
+}
+
+// WithClock 设置获取当前时间的方法，便于测试中控制 mtime
+func WithClock(now func() time.Time) Option {
+	return func(s *sqliteStore) {
+		s.now = now
+	}
+}
+
+// WithHost 设置当前节点的地址，默认为本机的 hostname
+func WithHost(host string) Option {
+	return func(s *sqliteStore) {
+		s.host = host
+	}
+}
+
+// New 创建一个 SQLite 存储，需要调用 Initialize 之后才能使用
+func New(options ...Option) store.Store {
+	s := &sqliteStore{
+		path:        defaultPath,
+		busyTimeout: defaultBusyTimeout,
+		now:         time.Now,
+		host:        localHost(),
+		locks:       keylock.New(),
+	}
+	s.initTables()
+	for i := range options {
+		options[i](s)
+	}
+	return s
+}
+
+// Open 打开 path 对应的数据库文件并完成初始化，文件不存在时会自动创建
+func Open(path string, options ...Option) (store.Store, error) {
+	s := New(append([]Option{WithPath(path)}, options...)...)
+	if err := s.Initialize(&store.Config{Name: StoreName}); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Name 存储层的名字
+func (s *sqliteStore) Name() string {
+	return StoreName
+}
+
+// Initialize 打开数据库文件并检查 schema 版本，新建的数据库会直接初始化到最新的版本
+func (s *sqliteStore) Initialize(c *store.Config) error {
+	registerClassifier.Do(func() {
+		store.RegisterErrorClassifier(StoreName, classifyError)
+	})
+	if c != nil {
+		if path, ok := c.Option[OptionPath].(string); ok && path != "" {
+			s.path = path
+		}
+		if value, ok := c.Option[OptionBusyTimeout].(string); ok && value != "" {
+			timeout, err := time.ParseDuration(value)
+			if err != nil {
+				return store.NewStatusError(store.EmptyParamsErr, "invalid sqlite busyTimeout: "+value)
+			}
+			s.busyTimeout = timeout
+		}
+		switch value := c.Option[OptionAutoMigrate].(type) {
+		case bool:
+			s.autoMigrate = value
+		case string:
+			s.autoMigrate = value == "true"
+		}
+	}
+	if s.db != nil {
+		return nil
+	}
+	db, err := sql.Open("sqlite", s.dsn())
+	if err != nil {
+		return store.Error(err)
+	}
+	if _, err := db.ExecContext(context.Background(), schemaVersionDDL); err != nil {
+		_ = db.Close()
+		return store.Error(err)
+	}
+	s.db = db
+	if err := s.checkSchema(); err != nil {
+		_ = db.Close()
+		s.db = nil
+		return err
+	}
+	return nil
+}
+
+// Destroy 关闭数据库，数据保留在文件中
+func (s *sqliteStore) Destroy() error {
+	if s.db == nil {
+		return nil
+	}
+	err := s.db.Close()
+	s.db = nil
+	return err
+}
+
+// dsn 开启 WAL，读操作不会阻塞写操作，写操作之间通过 busy_timeout 排队
+func (s *sqliteStore) dsn() string {
+	return fmt.Sprintf("file:%s?_pragma=busy_timeout(%d)&_pragma=journal_mode(WAL)&_pragma=synchronous(NORMAL)",
+		s.path, s.busyTimeout.Milliseconds())
+}
+
+func (s *sqliteStore) initTables() {
+	s.namespaces = newTable("namespace", func(ns *model.Namespace) row {
+		return row{id: ns.Name, name: ns.Name, mtime: ns.ModifyTime, valid: ns.Valid}
+	})
+	s.services = newTable("service", func(svc *model.Service) row {
+		return row{id: svc.ID, namespace: svc.Namespace, name: svc.Name, parent: svc.Reference,
+			mtime: svc.ModifyTime, valid: svc.Valid}
+	})
+	s.instances = newTable("instance", func(ins *model.Instance) row {
+		return row{id: instanceID(ins), namespace: ins.Proto.GetNamespace().GetValue(),
+			name: ins.Proto.GetHost().GetValue(), parent: ins.ServiceID, mtime: ins.ModifyTime, valid: ins.Valid}
+	})
+	s.l5Extends = newTable("l5_extend", func(ext *l5Extend) row {
+		return row{id: ext.ServiceID, mtime: ext.ModifyTime, valid: true}
+	})
+	s.routingConfigs = newTable("routing_config", func(conf *model.RoutingConfig) row {
+		return row{id: conf.ID, mtime: conf.ModifyTime, valid: conf.Valid}
+	})
+	s.routerConfigs = newTable("routing_config_v2", func(conf *model.RouterConfig) row {
+		return row{id: conf.ID, namespace: conf.Namespace, name: conf.Name, mtime: conf.ModifyTime, valid: conf.Valid}
+	})
+	s.rateLimits = newTable("ratelimit_config", func(limit *model.RateLimit) row {
+		return row{id: limit.ID, name: limit.Name, parent: limit.ServiceID, mtime: limit.ModifyTime, valid: limit.Valid}
+	})
+	s.circuitBreakers = newTable("circuitbreaker_rule_v2", func(rule *model.CircuitBreakerRule) row {
+		return row{id: rule.ID, namespace: rule.Namespace, name: rule.Name, mtime: rule.ModifyTime, valid: rule.Valid}
+	})
+	s.faultDetectRules = newTable("fault_detect_rule", func(rule *model.FaultDetectRule) row {
+		return row{id: rule.ID, namespace: rule.Namespace, name: rule.Name, mtime: rule.ModifyTime, valid: rule.Valid}
+	})
+	s.contracts = newTable("service_contract", func(contract *model.ServiceContract) row {
+		return row{id: contract.ID, namespace: contract.Namespace, name: contract.Name, parent: contract.Service,
+			mtime: contract.ModifyTime, valid: contract.Valid}
+	})
+	s.clients = newTable("client", func(client *model.Client) row {
+		return row{id: client.Proto.GetId().GetValue(), mtime: client.ModifyTime, valid: client.Valid}
+	})
+	s.grayResources = newTable("gray_resource", func(gray *model.GrayResource) row {
+		return row{id: gray.Name, name: gray.Name, mtime: gray.ModifyTime, valid: gray.Valid}
+	})
+	s.leaders = newTable("leader_election", func(leader *model.LeaderElection) row {
+		return row{id: leader.ElectKey, name: leader.Host, mtime: leader.ModifyTime, valid: leader.Valid}
+	})
+	s.bootstraps = newTable("start_lock", func(lock *bootstrapLock) row {
+		return row{id: lock.Key, name: lock.Server, mtime: lock.ModifyTime, valid: true}
+	})
+	s.distLocks = newTable("distributed_lock", func(lock *model.DistributedLock) row {
+		return row{id: lock.Key, name: lock.Owner, mtime: lock.ModifyTime, valid: lock.Owner != ""}
+	})
+	s.configGroups = newTable("config_file_group", func(group *model.ConfigFileGroup) row {
+		return row{id: configGroupKey(group.Namespace, group.Name), namespace: group.Namespace, name: group.Name,
+			mtime: group.ModifyTime, valid: group.Valid}
+	})
+	s.configFiles = newTable("config_file", func(file *model.ConfigFile) row {
+		return row{id: configFileKey(file.Namespace, file.Group, file.Name), namespace: file.Namespace,
+			name: file.Name, parent: file.Group, mtime: file.ModifyTime, valid: file.Valid}
+	})
+	s.configReleases = newTable("config_file_release", func(release *model.ConfigFileRelease) row {
+		return row{id: configReleaseKey(release.ConfigFileReleaseKey), namespace: release.Namespace,
+			name: release.FileName, parent: release.Group, mtime: release.ModifyTime, valid: release.Valid}
+	})
+	s.configHistories = newTable("config_file_release_history", func(history *model.ConfigFileReleaseHistory) row {
+		return row{id: sequenceKey(history.Id), namespace: history.Namespace, name: history.FileName,
+			parent: history.Group, mtime: history.CreateTime, valid: history.Valid}
+	})
+	s.configTemplates = newTable("config_file_template", func(template *model.ConfigFileTemplate) row {
+		return row{id: template.Name, name: template.Name, mtime: template.ModifyTime, valid: true}
+	})
+	s.users = newTable("user", func(user *model.User) row {
+		return row{id: user.ID, name: user.Name, parent: user.Owner, mtime: user.ModifyTime, valid: user.Valid}
+	})
+	s.groups = newTable("user_group", func(group *model.UserGroup) row {
+		return row{id: group.ID, name: group.Name, parent: group.Owner, mtime: group.ModifyTime, valid: group.Valid}
+	})
+	s.strategies = newTable("auth_strategy", func(strategy *model.StrategyDetail) row {
+		return row{id: strategy.ID, name: strategy.Name, parent: strategy.Owner, mtime: strategy.ModifyTime,
+			valid: strategy.Valid}
+	})
+}
+
+// nextID 生成配置中心资源的自增 ID，需要在写事务中调用
+func (s *sqliteStore) nextID(q querier) (uint64, error) {
+	return nextSequence(q, "config_id")
+}
+
+func localHost() string {
+	host, err := os.Hostname()
+	if err != nil {
+		return "127.0.0.1"
+	}
+	return host
+}
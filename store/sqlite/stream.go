@@ -0,0 +1,187 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqlite
+
+import (
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+var _ store.StreamStore = (*sqliteStore)(nil)
+
+// rangeSince 以主键为游标分批遍历 t 中 mtime 之后变更的数据，每批数据使用一条单独的 LIMIT 查询，
+// 内存中只保留一批数据，回调 handler 时不占用数据库连接；tx 不为空时在调用方的事务中查询。
+// fill 在回调之前处理每批数据，可以过滤掉部分数据，因此回调的数据可能少于 batchSize
+func rangeSince[T any](s *sqliteStore, tx store.Tx, t *table[T], mtime time.Time, firstUpdate bool, batchSize int,
+	fill func(q querier, items []*T) ([]*T, error), handler store.BatchHandler[*T]) error {
+	if batchSize <= 0 {
+		batchSize = store.DefaultRangeBatchSize
+	}
+	after := ""
+	for {
+		var (
+			fetched int
+			last    string
+		)
+		items, err := query(s, tx, func(q querier) ([]*T, error) {
+			items, err := t.sincePage(q, mtime, firstUpdate, after, batchSize)
+			if err != nil || len(items) == 0 {
+				return nil, err
+			}
+			fetched, last = len(items), t.row(items[len(items)-1]).id
+			if fill == nil {
+				return items, nil
+			}
+			return fill(q, items)
+		})
+		if err != nil {
+			return err
+		}
+		if len(items) > 0 {
+			next, err := handler(items)
+			if err != nil || !next {
+				return err
+			}
+		}
+		if fetched < batchSize {
+			return nil
+		}
+		after = last
+	}
+}
+
+// RangeMoreNamespaces 实现 store.StreamStore
+func (s *sqliteStore) RangeMoreNamespaces(mtime time.Time, batchSize int, handler store.BatchHandler[*model.Namespace]) error {
+	return rangeSince(s, nil, s.namespaces, mtime, false, batchSize, nil, handler)
+}
+
+// RangeMoreServices 实现 store.StreamStore
+func (s *sqliteStore) RangeMoreServices(mtime time.Time, firstUpdate, disableBusiness, needMeta bool, batchSize int,
+	handler store.BatchHandler[*model.Service]) error {
+	return rangeSince(s, nil, s.services, mtime, firstUpdate, batchSize,
+		func(q querier, items []*model.Service) ([]*model.Service, error) {
+			ret := items[:0]
+			for _, svc := range items {
+				if disableBusiness && svc.Namespace != systemNamespace {
+					continue
+				}
+				if !needMeta {
+					svc.Meta = nil
+				}
+				ret = append(ret, svc)
+			}
+			return ret, nil
+		}, handler)
+}
+
+// RangeMoreInstances 实现 store.StreamStore
+func (s *sqliteStore) RangeMoreInstances(tx store.Tx, mtime time.Time, firstUpdate, needMeta bool, serviceID []string,
+	batchSize int, handler store.BatchHandler[*model.Instance]) error {
+	return rangeSince(s, tx, s.instances, mtime, firstUpdate, batchSize,
+		func(q querier, items []*model.Instance) ([]*model.Instance, error) {
+			ret := items[:0]
+			for _, ins := range items {
+				if len(serviceID) > 0 && !containsString(serviceID, ins.ServiceID) {
+					continue
+				}
+				if !needMeta {
+					ins.Proto.Metadata = nil
+				}
+				ret = append(ret, ins)
+			}
+			return ret, nil
+		}, handler)
+}
+
+// RangeMoreClients 实现 store.StreamStore
+func (s *sqliteStore) RangeMoreClients(mtime time.Time, firstUpdate bool, batchSize int, handler store.BatchHandler[*model.Client]) error {
+	return rangeSince(s, nil, s.clients, mtime, firstUpdate, batchSize, nil, handler)
+}
+
+// RangeMoreServiceContracts 实现 store.StreamStore
+func (s *sqliteStore) RangeMoreServiceContracts(firstUpdate bool, mtime time.Time, batchSize int,
+	handler store.BatchHandler[*model.ServiceContract]) error {
+	return rangeSince(s, nil, s.contracts, mtime, firstUpdate, batchSize, nil, handler)
+}
+
+// RangeMoreGrayResources 实现 store.StreamStore
+func (s *sqliteStore) RangeMoreGrayResources(firstUpdate bool, mtime time.Time, batchSize int,
+	handler store.BatchHandler[*model.GrayResource]) error {
+	return rangeSince(s, nil, s.grayResources, mtime, firstUpdate, batchSize, nil, handler)
+}
+
+// RangeRoutingConfigsForCache 实现 store.StreamStore
+func (s *sqliteStore) RangeRoutingConfigsForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler store.BatchHandler[*model.RoutingConfig]) error {
+	return rangeSince(s, nil, s.routingConfigs, mtime, firstUpdate, batchSize, s.fillRoutingConfigs, handler)
+}
+
+// RangeRoutingConfigsV2ForCache 实现 store.StreamStore
+func (s *sqliteStore) RangeRoutingConfigsV2ForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler store.BatchHandler[*model.RouterConfig]) error {
+	return rangeSince(s, nil, s.routerConfigs, mtime, firstUpdate, batchSize, nil, handler)
+}
+
+// RangeRateLimitsForCache 实现 store.StreamStore
+func (s *sqliteStore) RangeRateLimitsForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler store.BatchHandler[*model.RateLimit]) error {
+	return rangeSince(s, nil, s.rateLimits, mtime, firstUpdate, batchSize, nil, handler)
+}
+
+// RangeCircuitBreakerRulesForCache 实现 store.StreamStore
+func (s *sqliteStore) RangeCircuitBreakerRulesForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler store.BatchHandler[*model.CircuitBreakerRule]) error {
+	return rangeSince(s, nil, s.circuitBreakers, mtime, firstUpdate, batchSize, nil, handler)
+}
+
+// RangeFaultDetectRulesForCache 实现 store.StreamStore
+func (s *sqliteStore) RangeFaultDetectRulesForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler store.BatchHandler[*model.FaultDetectRule]) error {
+	return rangeSince(s, nil, s.faultDetectRules, mtime, firstUpdate, batchSize, nil, handler)
+}
+
+// RangeMoreConfigGroup 实现 store.StreamStore
+func (s *sqliteStore) RangeMoreConfigGroup(firstUpdate bool, mtime time.Time, batchSize int,
+	handler store.BatchHandler[*model.ConfigFileGroup]) error {
+	return rangeSince(s, nil, s.configGroups, mtime, firstUpdate, batchSize, nil, handler)
+}
+
+// RangeMoreReleaseFile 实现 store.StreamStore
+func (s *sqliteStore) RangeMoreReleaseFile(firstUpdate bool, modifyTime time.Time, batchSize int,
+	handler store.BatchHandler[*model.ConfigFileRelease]) error {
+	return rangeSince(s, nil, s.configReleases, modifyTime, firstUpdate, batchSize, nil, handler)
+}
+
+// RangeUsersForCache 实现 store.StreamStore
+func (s *sqliteStore) RangeUsersForCache(mtime time.Time, firstUpdate bool, batchSize int, handler store.BatchHandler[*model.User]) error {
+	return rangeSince(s, nil, s.users, mtime, firstUpdate, batchSize, nil, handler)
+}
+
+// RangeGroupsForCache 实现 store.StreamStore
+func (s *sqliteStore) RangeGroupsForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler store.BatchHandler[*model.UserGroup]) error {
+	return rangeSince(s, nil, s.groups, mtime, firstUpdate, batchSize, nil, handler)
+}
+
+// RangeStrategyDetailsForCache 实现 store.StreamStore
+func (s *sqliteStore) RangeStrategyDetailsForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler store.BatchHandler[*model.StrategyDetail]) error {
+	return rangeSince(s, nil, s.strategies, mtime, firstUpdate, batchSize, nil, handler)
+}
@@ -0,0 +1,223 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// querier 执行 SQL 的对象，*sql.DB 以及 *sql.Conn 都实现了该接口
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// schema 表的建表语句
+type schema interface {
+	tableName() string
+	schema() []string
+}
+
+// row 资源在表中可以索引的列
+type row struct {
+	id        string
+	namespace string
+	name      string
+	parent    string
+	mtime     time.Time
+	valid     bool
+}
+
+// table 以 JSON 文档的形式保存一类资源，row 返回的字段保存为可以索引的列，
+// 用于按照主键、命名空间、名字、父资源、修改时间以及是否有效过滤数据
+type table[T any] struct {
+	name string
+	row  func(item *T) row
+}
+
+func newTable[T any](name string, row func(item *T) row) *table[T] {
+	return &table[T]{name: name, row: row}
+}
+
+func (t *table[T]) tableName() string {
+	return t.name
+}
+
+func (t *table[T]) schema() []string {
+	return []string{
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	id TEXT NOT NULL PRIMARY KEY,
+	namespace TEXT NOT NULL DEFAULT '',
+	name TEXT NOT NULL DEFAULT '',
+	parent TEXT NOT NULL DEFAULT '',
+	mtime INTEGER NOT NULL DEFAULT 0,
+	valid INTEGER NOT NULL DEFAULT 1,
+	data TEXT NOT NULL
+)`, t.name),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_name ON %s (namespace, name)", t.name, t.name),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_parent ON %s (parent)", t.name, t.name),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_mtime ON %s (mtime)", t.name, t.name),
+	}
+}
+
+// get 根据主键查询，不存在时返回 nil
+func (t *table[T]) get(q querier, id string) (*T, error) {
+	return t.first(q, "id = ?", id)
+}
+
+// first 查询满足条件的第一条数据，不存在时返回 nil
+func (t *table[T]) first(q querier, where string, args ...interface{}) (*T, error) {
+	items, err := t.find(q, where+" LIMIT 1", args...)
+	if err != nil || len(items) == 0 {
+		return nil, err
+	}
+	return items[0], nil
+}
+
+// find 查询满足条件的数据，where 为空时返回全部数据，结果按照主键排序
+func (t *table[T]) find(q querier, where string, args ...interface{}) ([]*T, error) {
+	query := "SELECT data FROM " + t.name
+	if where != "" {
+		query += " WHERE " + where
+	}
+	rows, err := q.QueryContext(context.Background(), query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	ret := make([]*T, 0)
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		item := new(T)
+		if err := json.Unmarshal(data, item); err != nil {
+			return nil, fmt.Errorf("decode %s: %w", t.name, err)
+		}
+		ret = append(ret, item)
+	}
+	return ret, rows.Err()
+}
+
+// valid 查询满足条件的有效数据
+func (t *table[T]) valid(q querier, where string, args ...interface{}) ([]*T, error) {
+	if where == "" {
+		return t.find(q, "valid = 1")
+	}
+	return t.find(q, "valid = 1 AND "+where, args...)
+}
+
+// since 查询 mtime 之后（包含 mtime）变更的数据，首次全量加载时只返回有效数据
+func (t *table[T]) since(q querier, mtime time.Time, firstUpdate bool) ([]*T, error) {
+	if firstUpdate {
+		return t.find(q, "mtime >= ? AND valid = 1", mtime.UnixNano())
+	}
+	return t.find(q, "mtime >= ?", mtime.UnixNano())
+}
+
+// sincePage 按照主键顺序查询 mtime 之后（包含 mtime）变更并且主键大于 after 的数据，最多返回 limit 条，
+// 过滤条件与 since 一致，用于以主键为游标分批遍历
+func (t *table[T]) sincePage(q querier, mtime time.Time, firstUpdate bool, after string, limit int) ([]*T, error) {
+	where := "mtime >= ? AND id > ?"
+	if firstUpdate {
+		where += " AND valid = 1"
+	}
+	return t.find(q, where+" ORDER BY id LIMIT ?", mtime.UnixNano(), after, limit)
+}
+
+// count 统计满足条件的数据条数
+func (t *table[T]) count(q querier, where string, args ...interface{}) (uint64, error) {
+	query := "SELECT COUNT(*) FROM " + t.name
+	if where != "" {
+		query += " WHERE " + where
+	}
+	rows, err := q.QueryContext(context.Background(), query, args...)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var count uint64
+	if rows.Next() {
+		if err := rows.Scan(&count); err != nil {
+			return 0, err
+		}
+	}
+	return count, rows.Err()
+}
+
+// put 保存数据，主键相同的数据会被覆盖
+func (t *table[T]) put(q querier, item *T) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", t.name, err)
+	}
+	r := t.row(item)
+	_, err = q.ExecContext(context.Background(), "INSERT OR REPLACE INTO "+t.name+
+		" (id, namespace, name, parent, mtime, valid, data) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		r.id, r.namespace, r.name, r.parent, r.mtime.UnixNano(), r.valid, string(data))
+	return err
+}
+
+// remove 删除满足条件的数据，返回删除的条数
+func (t *table[T]) remove(q querier, where string, args ...interface{}) (int64, error) {
+	result, err := q.ExecContext(context.Background(), "DELETE FROM "+t.name+" WHERE "+where, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const sequenceDDL = `CREATE TABLE IF NOT EXISTS sequence (
+	name TEXT NOT NULL PRIMARY KEY,
+	value INTEGER NOT NULL
+)`
+
+// nextSequence 递增名为 name 的序列并返回递增之后的值，需要在写事务中调用
+func nextSequence(q querier, name string) (uint64, error) {
+	ctx := context.Background()
+	if _, err := q.ExecContext(ctx, "INSERT INTO sequence (name, value) VALUES (?, 1) "+
+		"ON CONFLICT(name) DO UPDATE SET value = value + 1", name); err != nil {
+		return 0, err
+	}
+	rows, err := q.QueryContext(ctx, "SELECT value FROM sequence WHERE name = ?", name)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var value uint64
+	if rows.Next() {
+		if err := rows.Scan(&value); err != nil {
+			return 0, err
+		}
+	}
+	return value, rows.Err()
+}
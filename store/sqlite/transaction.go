@@ -0,0 +1,446 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+	"github.com/polarismesh/polaris-plugin-api/store/sqlite/internal/keylock"
+)
+
+var _ store.TxStore = (*sqliteStore)(nil)
+
+// CreateTransaction 创建事务对象
+func (s *sqliteStore) CreateTransaction() (store.Transaction, error) {
+	return &transaction{s: s}, nil
+}
+
+// StartTx 开启一个写事务，开启时即获取数据库的写锁
+func (s *sqliteStore) StartTx() (store.Tx, error) {
+	return s.begin(false)
+}
+
+// StartReadTx 开启一个只读事务
+func (s *sqliteStore) StartReadTx() (store.Tx, error) {
+	return s.begin(true)
+}
+
+// BeginTx 开启事务，SQLite 的事务都是串行化的，因此支持所有的隔离级别；
+// 写事务开启时即获取数据库的写锁，行锁在事务结束时释放
+func (s *sqliteStore) BeginTx(opts store.TxOptions) (store.UnifiedTx, error) {
+	if opts.Isolation < store.IsolationDefault || opts.Isolation > store.IsolationSerializable {
+		return nil, store.NewStatusError(store.NotSupportedErr, "unsupported isolation level: "+opts.Isolation.String())
+	}
+	return s.begin(opts.ReadOnly)
+}
+
+// begin 在独占的数据库连接上开启事务
+func (s *sqliteStore) begin(readOnly bool) (*sqliteTx, error) {
+	if s.db == nil {
+		return nil, store.NewStatusError(store.EmptyParamsErr, "sqlite store is not initialized")
+	}
+	ctx := context.Background()
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return nil, store.Error(err)
+	}
+	statement := "BEGIN IMMEDIATE"
+	if readOnly {
+		statement = "BEGIN"
+	}
+	if _, err := conn.ExecContext(ctx, statement); err != nil {
+		_ = conn.Close()
+		return nil, store.Error(err)
+	}
+	return &sqliteTx{s: s, conn: conn, readOnly: readOnly}, nil
+}
+
+// update 在写事务中执行 f，tx 不为空时使用调用方的事务，否则开启新的事务并在 f 成功后提交
+func (s *sqliteStore) update(tx store.Tx, f func(q querier) error) error {
+	if tx != nil {
+		stx, err := checkTx(tx, true)
+		if err != nil {
+			return err
+		}
+		return store.Error(f(stx.conn))
+	}
+	stx, err := s.begin(false)
+	if err != nil {
+		return err
+	}
+	if err := f(stx.conn); err != nil {
+		_ = stx.Rollback()
+		return store.Error(err)
+	}
+	return stx.Commit()
+}
+
+// view 在只读事务中执行 f，保证多条查询读取到同一个快照，tx 不为空时使用调用方的事务
+func (s *sqliteStore) view(tx store.Tx, f func(q querier) error) error {
+	if tx != nil {
+		stx, err := checkTx(tx, false)
+		if err != nil {
+			return err
+		}
+		return store.Error(f(stx.conn))
+	}
+	stx, err := s.begin(true)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = stx.Rollback()
+	}()
+	return store.Error(f(stx.conn))
+}
+
+// query 在只读事务中执行 f 并返回查询结果
+func query[V any](s *sqliteStore, tx store.Tx, f func(q querier) (V, error)) (V, error) {
+	var ret V
+	err := s.view(tx, func(q querier) error {
+		var err error
+		ret, err = f(q)
+		return err
+	})
+	return ret, err
+}
+
+// sqliteTx 基于独占数据库连接的事务，StartTx 开启的事务会立即获取数据库的写锁，
+// StartReadTx 开启的事务在第一次读取时创建快照
+type sqliteTx struct {
+	s        *sqliteStore
+	conn     *sql.Conn
+	readOnly bool
+
+	lock     sync.Mutex
+	held     keylock.Held
+	finished bool
+}
+
+// Commit 提交事务
+func (tx *sqliteTx) Commit() error {
+	tx.lock.Lock()
+	defer tx.lock.Unlock()
+
+	if tx.finished {
+		return store.NewStatusError(store.EmptyParamsErr, "transaction already finished")
+	}
+	tx.finished = true
+	defer func() {
+		_ = tx.conn.Close()
+		tx.held.Release()
+	}()
+	if _, err := tx.conn.ExecContext(context.Background(), "COMMIT"); err != nil {
+		_, _ = tx.conn.ExecContext(context.Background(), "ROLLBACK")
+		return store.Error(err)
+	}
+	return nil
+}
+
+// Rollback 回滚事务
+func (tx *sqliteTx) Rollback() error {
+	tx.lock.Lock()
+	defer tx.lock.Unlock()
+
+	if tx.finished {
+		return nil
+	}
+	tx.finished = true
+	defer func() {
+		_ = tx.conn.Close()
+		tx.held.Release()
+	}()
+	_, err := tx.conn.ExecContext(context.Background(), "ROLLBACK")
+	return store.Error(err)
+}
+
+// GetDelegateTx 获取原始的事务对象
+func (tx *sqliteTx) GetDelegateTx() interface{} {
+	return tx
+}
+
+// CreateReadView 立即读取一次数据库，使得事务之后的查询都基于当前的快照
+func (tx *sqliteTx) CreateReadView() error {
+	tx.lock.Lock()
+	defer tx.lock.Unlock()
+
+	if tx.finished {
+		return store.NewStatusError(store.EmptyParamsErr, "transaction already finished")
+	}
+	rows, err := tx.conn.QueryContext(context.Background(), "SELECT COUNT(*) FROM sqlite_master")
+	if err != nil {
+		return store.Error(err)
+	}
+	return store.Error(rows.Close())
+}
+
+// Savepoint 创建保存点
+func (tx *sqliteTx) Savepoint(name string) error {
+	return tx.savepoint("SAVEPOINT", name)
+}
+
+// RollbackToSavepoint 撤销保存点之后的修改，并删除在它之后创建的保存点
+func (tx *sqliteTx) RollbackToSavepoint(name string) error {
+	return tx.savepoint("ROLLBACK TO", name)
+}
+
+// ReleaseSavepoint 删除保存点以及在它之后创建的保存点
+func (tx *sqliteTx) ReleaseSavepoint(name string) error {
+	return tx.savepoint("RELEASE", name)
+}
+
+// savepoint 执行保存点相关的语句，保存点不存在时返回 store.NotFoundResource
+func (tx *sqliteTx) savepoint(statement string, name string) error {
+	if err := store.ValidateSavepoint(name); err != nil {
+		return err
+	}
+	tx.lock.Lock()
+	defer tx.lock.Unlock()
+
+	if tx.finished {
+		return store.NewStatusError(store.EmptyParamsErr, "transaction already finished")
+	}
+	_, err := tx.conn.ExecContext(context.Background(), statement+" "+name)
+	if err != nil && statement != "SAVEPOINT" && strings.Contains(err.Error(), "no such savepoint") {
+		return store.NewStatusError(store.NotFoundResource, "savepoint not found: "+name)
+	}
+	return store.Error(err)
+}
+
+// LockBootstrap 记录 server 启动锁的持有者，其他事务在当前事务结束之前无法获取同一个启动锁
+func (tx *sqliteTx) LockBootstrap(key string, server string) error {
+	if err := tx.acquire("bootstrap/"+key, true); err != nil {
+		return err
+	}
+	return tx.s.update(tx, func(q querier) error {
+		return tx.s.bootstraps.put(q, &bootstrapLock{Key: key, Server: server, ModifyTime: tx.s.now()})
+	})
+}
+
+// LockNamespace 获取命名空间的排他锁，并返回事务中有效的命名空间
+func (tx *sqliteTx) LockNamespace(name string) (*model.Namespace, error) {
+	if err := tx.acquire("namespace/"+name, true); err != nil {
+		return nil, err
+	}
+	return query(tx.s, tx, func(q querier) (*model.Namespace, error) {
+		return tx.s.namespaces.first(q, "id = ? AND valid = 1", name)
+	})
+}
+
+// DeleteNamespace 删除命名空间，实际是把 valid 置为 false
+func (tx *sqliteTx) DeleteNamespace(name string) error {
+	return tx.s.update(tx, func(q querier) error {
+		saved, err := tx.s.namespaces.get(q, name)
+		if err != nil || saved == nil {
+			return err
+		}
+		saved.Valid = false
+		saved.ModifyTime = tx.s.now()
+		return tx.s.namespaces.put(q, saved)
+	})
+}
+
+// LockService 获取服务的排他锁，并返回事务中有效的服务
+func (tx *sqliteTx) LockService(name string, namespace string) (*model.Service, error) {
+	return tx.lockService(name, namespace, true)
+}
+
+// RLockService 获取服务的共享锁，并返回事务中有效的服务
+func (tx *sqliteTx) RLockService(name string, namespace string) (*model.Service, error) {
+	return tx.lockService(name, namespace, false)
+}
+
+func (tx *sqliteTx) lockService(name string, namespace string, exclusive bool) (*model.Service, error) {
+	if err := tx.acquire("service/"+namespace+"/"+name, exclusive); err != nil {
+		return nil, err
+	}
+	return query(tx.s, tx, func(q querier) (*model.Service, error) {
+		return tx.s.services.first(q, "namespace = ? AND name = ? AND valid = 1", namespace, name)
+	})
+}
+
+// acquire 获取资源锁，与 CreateTransaction 创建的 Transaction 共享同一组资源锁
+func (tx *sqliteTx) acquire(key string, exclusive bool) error {
+	tx.lock.Lock()
+	defer tx.lock.Unlock()
+
+	if tx.finished {
+		return store.NewStatusError(store.EmptyParamsErr, "transaction already finished")
+	}
+	return tx.held.Acquire(tx.s.locks, key, exclusive, tx.s.busyTimeout)
+}
+
+// checkTx 检查事务是否可用，write 为 true 时不允许使用只读事务
+func checkTx(tx store.Tx, write bool) (*sqliteTx, error) {
+	stx, ok := tx.GetDelegateTx().(*sqliteTx)
+	if !ok {
+		return nil, store.NewStatusError(store.EmptyParamsErr, fmt.Sprintf("unsupported tx type %T", tx))
+	}
+	stx.lock.Lock()
+	defer stx.lock.Unlock()
+
+	if stx.finished {
+		return nil, store.NewStatusError(store.EmptyParamsErr, "transaction already finished")
+	}
+	if write && stx.readOnly {
+		return nil, store.NewStatusError(store.EmptyParamsErr, "write operation in read only transaction")
+	}
+	return stx, nil
+}
+
+// bootstrapLock server 启动锁的持有者
+type bootstrapLock struct {
+	Key        string
+	Server     string
+	ModifyTime time.Time
+}
+
+// transaction SQLite 存储的 store.Transaction 实现，LockNamespace、LockService 获取资源的排他锁，
+// RLockService 获取资源的共享锁，锁在 Commit 时释放；DeleteNamespace、LockBootstrap 的修改在 Commit 时提交
+type transaction struct {
+	s *sqliteStore
+
+	lock     sync.Mutex
+	tx       *sqliteTx
+	held     keylock.Held
+	finished bool
+}
+
+// Commit 提交事务并释放所有的资源锁
+func (t *transaction) Commit() error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.finished {
+		return nil
+	}
+	t.finished = true
+	defer t.held.Release()
+	if t.tx == nil {
+		return nil
+	}
+	return t.tx.Commit()
+}
+
+// LockBootstrap 记录 server 启动锁的持有者，其他 Transaction 在 Commit 之前无法获取同一个启动锁
+func (t *transaction) LockBootstrap(key string, server string) error {
+	if err := t.acquire("bootstrap/"+key, true); err != nil {
+		return err
+	}
+	return t.write(func(q querier) error {
+		return t.s.bootstraps.put(q, &bootstrapLock{Key: key, Server: server, ModifyTime: t.s.now()})
+	})
+}
+
+// LockNamespace 获取命名空间的排他锁，并返回有效的命名空间
+func (t *transaction) LockNamespace(name string) (*model.Namespace, error) {
+	if err := t.acquire("namespace/"+name, true); err != nil {
+		return nil, err
+	}
+	return t.s.GetNamespace(name)
+}
+
+// DeleteNamespace 删除命名空间，实际是把 valid 置为 false，修改在 Commit 时提交
+func (t *transaction) DeleteNamespace(name string) error {
+	return t.write(func(q querier) error {
+		saved, err := t.s.namespaces.get(q, name)
+		if err != nil || saved == nil {
+			return err
+		}
+		saved.Valid = false
+		saved.ModifyTime = t.s.now()
+		return t.s.namespaces.put(q, saved)
+	})
+}
+
+// LockService 获取服务的排他锁，并返回有效的服务
+func (t *transaction) LockService(name string, namespace string) (*model.Service, error) {
+	if err := t.acquire("service/"+namespace+"/"+name, true); err != nil {
+		return nil, err
+	}
+	return t.s.GetService(name, namespace)
+}
+
+// RLockService 获取服务的共享锁，并返回有效的服务
+func (t *transaction) RLockService(name string, namespace string) (*model.Service, error) {
+	if err := t.acquire("service/"+namespace+"/"+name, false); err != nil {
+		return nil, err
+	}
+	return t.s.GetService(name, namespace)
+}
+
+// acquire 获取资源锁，等待超过 busyTimeout 时返回 store.DeadlockErr
+func (t *transaction) acquire(key string, exclusive bool) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.finished {
+		return store.NewStatusError(store.EmptyParamsErr, "transaction already finished")
+	}
+	return t.held.Acquire(t.s.locks, key, exclusive, t.s.busyTimeout)
+}
+
+// write 在 Transaction 关联的写事务中执行 f，写事务在第一次写入时开启
+func (t *transaction) write(f func(q querier) error) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.finished {
+		return store.NewStatusError(store.EmptyParamsErr, "transaction already finished")
+	}
+	if t.tx == nil {
+		tx, err := t.s.begin(false)
+		if err != nil {
+			return err
+		}
+		t.tx = tx
+	}
+	return t.s.update(t.tx, f)
+}
+
+// classifyError 识别 SQLite 的错误码，数据库被锁定时返回 store.DeadlockErr，调用方可以重试
+func classifyError(err error) (store.StatusCode, bool) {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return store.Unknown, false
+	}
+	switch sqliteErr.Code() {
+	case sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY, sqlite3.SQLITE_CONSTRAINT_UNIQUE:
+		return store.DuplicateEntryErr, true
+	case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+		return store.ForeignKeyErr, true
+	case sqlite3.SQLITE_TOOBIG:
+		return store.OutOfRangeErr, true
+	}
+	switch sqliteErr.Code() & 0xff {
+	case sqlite3.SQLITE_BUSY, sqlite3.SQLITE_LOCKED:
+		return store.DeadlockErr, true
+	}
+	return store.Unknown, false
+}
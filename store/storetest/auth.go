@@ -0,0 +1,160 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package storetest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+var userCases = []testCase{
+	{
+		name: "crud and incremental",
+		run: func(t *testing.T, s store.Store) {
+			owner := &model.User{ID: "owner", Name: "owner", Token: "t0", TokenEnable: true}
+			mustNil(t, s.AddUser(owner))
+			for _, id := range []string{"user-1", "user-2"} {
+				mustNil(t, s.AddUser(&model.User{ID: id, Name: id, Owner: owner.ID, Token: "t-" + id}))
+			}
+			expectCode(t, s.AddUser(owner), store.DuplicateEntryErr)
+
+			sub, err := s.GetSubCount(owner)
+			mustNil(t, err)
+			expectTrue(t, sub == 2, "expect 2 sub users, got %d", sub)
+
+			byName, err := s.GetUserByName("user-1", owner.ID)
+			mustNil(t, err)
+			expectTrue(t, byName != nil && byName.ID == "user-1", "get user by name failed")
+			byIds, err := s.GetUserByIds([]string{"user-1", "user-2", "not-exist"})
+			mustNil(t, err)
+			expectTrue(t, len(byIds) == 2, "expect 2 users by ids, got %d", len(byIds))
+
+			byName.Comment = "updated"
+			mustNil(t, s.UpdateUser(byName))
+			total, list, err := s.GetUsers(map[string]string{"owner": owner.ID}, 0, 1)
+			mustNil(t, err)
+			expectTrue(t, total == 2 && len(list) == 1, "query users expect 1 of 2, got total=%d len=%d",
+				total, len(list))
+
+			mustNil(t, s.DeleteUser(byName))
+			deleted, err := s.GetUser(byName.ID)
+			mustNil(t, err)
+			expectTrue(t, deleted == nil, "deleted user should not be returned")
+
+			more, err := s.GetUsersForCache(byName.ModifyTime, false)
+			mustNil(t, err)
+			expectTrue(t, findUser(more, byName.ID) != nil && !findUser(more, byName.ID).Valid,
+				"incremental load should contain soft deleted user")
+			first, err := s.GetUsersForCache(time.Time{}, true)
+			mustNil(t, err)
+			expectTrue(t, len(first) == 2, "first update should only load valid users, got %d", len(first))
+		},
+	},
+}
+
+var groupCases = []testCase{
+	{
+		name: "crud and incremental",
+		run: func(t *testing.T, s store.Store) {
+			group := &model.UserGroup{ID: "group-1", Name: "group", Owner: "owner", Token: "t",
+				UserIds: []string{"user-1"}}
+			mustNil(t, s.AddGroup(group))
+			expectCode(t, s.AddGroup(group), store.DuplicateEntryErr)
+
+			mustNil(t, s.UpdateGroup(&model.ModifyUserGroup{ID: group.ID, Owner: group.Owner, Token: "t2",
+				Comment: "updated", AddUserIds: []string{"user-2"}, RemoveUserIds: []string{"user-1"}}))
+			saved, err := s.GetGroup(group.ID)
+			mustNil(t, err)
+			expectTrue(t, saved != nil && len(saved.UserIds) == 1 && saved.UserIds[0] == "user-2",
+				"update group users failed: %v", saved)
+
+			byName, err := s.GetGroupByName("group", "owner")
+			mustNil(t, err)
+			expectTrue(t, byName != nil && byName.ID == group.ID, "get group by name failed")
+			total, _, err := s.GetGroups(map[string]string{"name": "gro*"}, 0, 10)
+			mustNil(t, err)
+			expectTrue(t, total == 1, "expect 1 group, got %d", total)
+
+			mustNil(t, s.DeleteGroup(group))
+			deleted, err := s.GetGroup(group.ID)
+			mustNil(t, err)
+			expectTrue(t, deleted == nil, "deleted group should not be returned")
+
+			more, err := s.GetGroupsForCache(saved.ModifyTime, false)
+			mustNil(t, err)
+			expectTrue(t, len(more) == 1 && !more[0].Valid, "incremental load should contain soft deleted group")
+		},
+	},
+}
+
+var strategyCases = []testCase{
+	{
+		name: "crud and resources",
+		run: func(t *testing.T, s store.Store) {
+			strategy := &model.StrategyDetail{
+				ID: "strategy-1", Name: "strategy", Action: "READ_WRITE", Owner: "owner", Default: true,
+				Principals: []model.Principal{{PrincipalID: "user-1", PrincipalRole: "1"}},
+				Resources:  []model.StrategyResource{{ResType: 0, ResID: testNamespace}},
+			}
+			mustNil(t, s.AddStrategy(strategy))
+			expectCode(t, s.AddStrategy(strategy), store.DuplicateEntryErr)
+
+			mustNil(t, s.LooseAddStrategyResources([]model.StrategyResource{
+				{StrategyID: strategy.ID, ResType: 1, ResID: "svc-1"},
+				{StrategyID: strategy.ID, ResType: 1, ResID: "svc-1"},
+			}))
+			resources, err := s.GetStrategyResources("user-1", "1")
+			mustNil(t, err)
+			expectTrue(t, len(resources) == 2, "expect 2 resources, got %d", len(resources))
+
+			mustNil(t, s.RemoveStrategyResources([]model.StrategyResource{{ResType: 1, ResID: "svc-1"}}))
+			def, err := s.GetDefaultStrategyDetailByPrincipal("user-1", "1")
+			mustNil(t, err)
+			expectTrue(t, def != nil && len(def.Resources) == 1, "get default strategy failed")
+
+			mustNil(t, s.UpdateStrategy(&model.ModifyStrategyDetail{
+				ID: strategy.ID, Action: "READ_WRITE", Comment: "updated",
+				AddPrincipals: []model.Principal{{PrincipalID: "user-2", PrincipalRole: "1"}},
+			}))
+			total, list, err := s.GetStrategies(map[string]string{"principal_id": "user-2"}, 0, 10)
+			mustNil(t, err)
+			expectTrue(t, total == 1 && list[0].Comment == "updated", "query strategies failed")
+
+			mustNil(t, s.DeleteStrategy(strategy.ID))
+			deleted, err := s.GetStrategyDetail(strategy.ID)
+			mustNil(t, err)
+			expectTrue(t, deleted == nil, "deleted strategy should not be returned")
+
+			more, err := s.GetStrategyDetailsForCache(list[0].ModifyTime, false)
+			mustNil(t, err)
+			expectTrue(t, len(more) == 1 && !more[0].Valid, "incremental load should contain soft deleted strategy")
+		},
+	},
+}
+
+func findUser(items []*model.User, id string) *model.User {
+	for _, item := range items {
+		if item.ID == id {
+			return item
+		}
+	}
+	return nil
+}
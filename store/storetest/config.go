@@ -0,0 +1,278 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package storetest
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+var configGroupCases = []testCase{
+	{
+		name: "crud and incremental",
+		run: func(t *testing.T, s store.Store) {
+			group := &model.ConfigFileGroup{Namespace: testNamespace, Name: "group", Comment: "c", Owner: "polaris",
+				Metadata: map[string]string{"env": "test"}}
+			created, err := s.CreateConfigFileGroup(group)
+			mustNil(t, err)
+			expectTrue(t, created != nil && created.Id > 0, "create config group should return id")
+			_, err = s.CreateConfigFileGroup(group)
+			expectCode(t, err, store.DuplicateEntryErr)
+
+			group.Comment = "updated"
+			mustNil(t, s.UpdateConfigFileGroup(group))
+			saved, err := s.GetConfigFileGroup(testNamespace, "group")
+			mustNil(t, err)
+			expectTrue(t, saved != nil && saved.Comment == "updated" && saved.Metadata["env"] == "test",
+				"update config group failed")
+
+			count, err := s.CountConfigGroups(testNamespace)
+			mustNil(t, err)
+			expectTrue(t, count == 1, "expect 1 group, got %d", count)
+
+			mustNil(t, s.DeleteConfigFileGroup(testNamespace, "group"))
+			deleted, err := s.GetConfigFileGroup(testNamespace, "group")
+			mustNil(t, err)
+			expectTrue(t, deleted == nil, "deleted group should not be returned")
+
+			more, err := s.GetMoreConfigGroup(false, saved.ModifyTime)
+			mustNil(t, err)
+			expectTrue(t, len(more) == 1 && !more[0].Valid, "incremental load should contain soft deleted group")
+			first, err := s.GetMoreConfigGroup(true, time.Time{})
+			mustNil(t, err)
+			expectTrue(t, len(first) == 0, "first update should only load valid groups")
+		},
+	},
+}
+
+var configFileCases = []testCase{
+	{
+		name: "crud in transaction",
+		run: func(t *testing.T, s store.Store) {
+			for i := 0; i < 3; i++ {
+				tx, err := s.StartTx()
+				mustNil(t, err)
+				mustNil(t, s.CreateConfigFileTx(tx, newConfigFile("file-"+strconv.Itoa(i)+".yaml")))
+				mustNil(t, tx.Commit())
+			}
+
+			tx, err := s.StartTx()
+			mustNil(t, err)
+			err = s.CreateConfigFileTx(tx, newConfigFile("file-0.yaml"))
+			expectCode(t, err, store.DuplicateEntryErr)
+			mustNil(t, tx.Rollback())
+
+			total, list, err := s.QueryConfigFiles(map[string]string{"namespace": testNamespace, "name": "file-*"}, 0, 2)
+			mustNil(t, err)
+			expectTrue(t, total == 3 && len(list) == 2, "query files expect 2 of 3, got total=%d len=%d",
+				total, len(list))
+
+			count, err := s.CountConfigFiles(testNamespace, "group")
+			mustNil(t, err)
+			expectTrue(t, count == 3, "expect 3 files, got %d", count)
+			each, err := s.CountConfigFileEachGroup()
+			mustNil(t, err)
+			expectTrue(t, each[testNamespace]["group"] == 3, "count each group failed: %v", each)
+
+			tx, err = s.StartTx()
+			mustNil(t, err)
+			locked, err := s.LockConfigFile(tx, &model.ConfigFileKey{Namespace: testNamespace, Group: "group",
+				Name: "file-0.yaml"})
+			mustNil(t, err)
+			expectTrue(t, locked != nil, "lock config file should return the file")
+			locked.Content = "updated"
+			mustNil(t, s.UpdateConfigFileTx(tx, locked))
+			mustNil(t, s.DeleteConfigFileTx(tx, testNamespace, "group", "file-1.yaml"))
+			mustNil(t, tx.Commit())
+
+			saved, err := s.GetConfigFile(testNamespace, "group", "file-0.yaml")
+			mustNil(t, err)
+			expectTrue(t, saved.Content == "updated", "update config file failed")
+			deleted, err := s.GetConfigFile(testNamespace, "group", "file-1.yaml")
+			mustNil(t, err)
+			expectTrue(t, deleted == nil, "deleted config file should not be returned")
+		},
+	},
+	{
+		name: "rollback",
+		run: func(t *testing.T, s store.Store) {
+			tx, err := s.StartTx()
+			mustNil(t, err)
+			mustNil(t, s.CreateConfigFileTx(tx, newConfigFile("file.yaml")))
+			mustNil(t, tx.Rollback())
+
+			saved, err := s.GetConfigFile(testNamespace, "group", "file.yaml")
+			mustNil(t, err)
+			expectTrue(t, saved == nil, "config file should not exist after rollback")
+		},
+	},
+}
+
+var configReleaseCases = []testCase{
+	{
+		name: "release and active",
+		run: func(t *testing.T, s store.Store) {
+			tx, err := s.StartTx()
+			mustNil(t, err)
+			mustNil(t, s.CreateConfigFileTx(tx, newConfigFile("file.yaml")))
+			mustNil(t, s.CreateConfigFileReleaseTx(tx, newConfigFileRelease("release-1", "v1")))
+			mustNil(t, tx.Commit())
+
+			fileKey := &model.ConfigFileKey{Namespace: testNamespace, Group: "group", Name: "file.yaml"}
+			active, err := s.GetConfigFileActiveRelease(fileKey)
+			mustNil(t, err)
+			expectTrue(t, active != nil && active.Name == "release-1", "release should be active after create")
+
+			tx, err = s.StartTx()
+			mustNil(t, err)
+			mustNil(t, s.CreateConfigFileReleaseTx(tx, newConfigFileRelease("release-2", "v2")))
+			mustNil(t, tx.Commit())
+
+			active, err = s.GetConfigFileActiveRelease(fileKey)
+			mustNil(t, err)
+			expectTrue(t, active != nil && active.Name == "release-2", "new release should be active")
+			first, err := s.GetConfigFileRelease(newConfigFileRelease("release-1", "").ConfigFileReleaseKey)
+			mustNil(t, err)
+			expectTrue(t, first != nil && !first.Active, "old release should be inactive")
+			expectTrue(t, active.Version > first.Version, "release version should increase")
+
+			count, err := s.CountConfigReleases(testNamespace, "group", true)
+			mustNil(t, err)
+			expectTrue(t, count == 1, "expect 1 active release, got %d", count)
+
+			tx, err = s.StartTx()
+			mustNil(t, err)
+			mustNil(t, s.ActiveConfigFileReleaseTx(tx, first))
+			mustNil(t, tx.Commit())
+			active, err = s.GetConfigFileActiveRelease(fileKey)
+			mustNil(t, err)
+			expectTrue(t, active != nil && active.Name == "release-1", "rollback release should be active")
+
+			more, err := s.GetMoreReleaseFile(false, first.ModifyTime)
+			mustNil(t, err)
+			expectTrue(t, len(more) == 2, "incremental load should contain both releases, got %d", len(more))
+		},
+	},
+	{
+		name: "delete and clean",
+		run: func(t *testing.T, s store.Store) {
+			tx, err := s.StartTx()
+			mustNil(t, err)
+			mustNil(t, s.CreateConfigFileTx(tx, newConfigFile("file.yaml")))
+			mustNil(t, s.CreateConfigFileReleaseTx(tx, newConfigFileRelease("release-1", "v1")))
+			mustNil(t, s.CreateConfigFileReleaseTx(tx, newConfigFileRelease("release-2", "v2")))
+			mustNil(t, tx.Commit())
+
+			tx, err = s.StartTx()
+			mustNil(t, err)
+			mustNil(t, s.DeleteConfigFileReleaseTx(tx, newConfigFileRelease("release-1", "").ConfigFileReleaseKey))
+			mustNil(t, tx.Commit())
+			deleted, err := s.GetConfigFileRelease(newConfigFileRelease("release-1", "").ConfigFileReleaseKey)
+			mustNil(t, err)
+			expectTrue(t, deleted == nil, "deleted release should not be returned")
+
+			tx, err = s.StartTx()
+			mustNil(t, err)
+			mustNil(t, s.CleanConfigFileReleasesTx(tx, testNamespace, "group", "file.yaml"))
+			mustNil(t, tx.Commit())
+			count, err := s.CountConfigReleases(testNamespace, "group", false)
+			mustNil(t, err)
+			expectTrue(t, count == 0, "expect no release after clean, got %d", count)
+
+			first, err := s.GetMoreReleaseFile(true, time.Time{})
+			mustNil(t, err)
+			expectTrue(t, len(first) == 0, "first update should only load valid releases")
+		},
+	},
+}
+
+var configHistoryCases = []testCase{
+	{
+		name: "create query and clean",
+		run: func(t *testing.T, s store.Store) {
+			for i := 0; i < 3; i++ {
+				mustNil(t, s.CreateConfigFileReleaseHistory(&model.ConfigFileReleaseHistory{
+					Name: "release-" + strconv.Itoa(i), Namespace: testNamespace, Group: "group",
+					FileName: "file.yaml", Content: "v" + strconv.Itoa(i), Version: uint64(i + 1),
+				}))
+			}
+			filter := map[string]string{"namespace": testNamespace, "group": "group", "name": "file.yaml"}
+			total, list, err := s.QueryConfigFileReleaseHistories(filter, 0, 2)
+			mustNil(t, err)
+			expectTrue(t, total == 3 && len(list) == 2, "query histories expect 2 of 3, got total=%d len=%d",
+				total, len(list))
+			expectTrue(t, list[0].Id > list[1].Id, "histories should be ordered by id desc")
+
+			mustNil(t, s.CleanConfigFileReleaseHistory(future(list[0].CreateTime), 10))
+			total, _, err = s.QueryConfigFileReleaseHistories(filter, 0, 10)
+			mustNil(t, err)
+			expectTrue(t, total == 0, "expect no history after clean, got %d", total)
+		},
+	},
+}
+
+var configTemplateCases = []testCase{
+	{
+		name: "create and get",
+		run: func(t *testing.T, s store.Store) {
+			created, err := s.CreateConfigFileTemplate(&model.ConfigFileTemplate{Name: "tpl", Content: "a: 1",
+				Format: "yaml"})
+			mustNil(t, err)
+			expectTrue(t, created != nil && created.Id > 0, "create template should return id")
+			_, err = s.CreateConfigFileTemplate(&model.ConfigFileTemplate{Name: "tpl"})
+			expectCode(t, err, store.DuplicateEntryErr)
+
+			saved, err := s.GetConfigFileTemplate("tpl")
+			mustNil(t, err)
+			expectTrue(t, saved != nil && saved.Content == "a: 1", "get template failed")
+			all, err := s.QueryAllConfigFileTemplates()
+			mustNil(t, err)
+			expectTrue(t, len(all) == 1, "expect 1 template, got %d", len(all))
+		},
+	},
+}
+
+func newConfigFile(name string) *model.ConfigFile {
+	return &model.ConfigFile{
+		Name:      name,
+		Namespace: testNamespace,
+		Group:     "group",
+		Content:   "a: 1",
+		Format:    "yaml",
+		Metadata:  map[string]string{"env": "test"},
+		CreateBy:  "polaris",
+		ModifyBy:  "polaris",
+	}
+}
+
+func newConfigFileRelease(name, content string) *model.ConfigFileRelease {
+	release := model.NewConfigFileRelease()
+	release.Name = name
+	release.Namespace = testNamespace
+	release.Group = "group"
+	release.FileName = "file.yaml"
+	release.Content = content
+	release.Format = "yaml"
+	release.CreateBy = "polaris"
+	release.ModifyBy = "polaris"
+	return release
+}
@@ -0,0 +1,97 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package storetest
+
+import (
+	"testing"
+
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+const (
+	testNamespace = "storetest"
+)
+
+// prepareNamespace 创建测试使用的命名空间
+func prepareNamespace(t *testing.T, s store.Store, name string) *model.Namespace {
+	t.Helper()
+	ns := &model.Namespace{
+		Name:    name,
+		Comment: "storetest namespace",
+		Token:   "token-" + name,
+		Owner:   "polaris",
+	}
+	mustNil(t, s.AddNamespace(ns))
+	saved, err := s.GetNamespace(name)
+	mustNil(t, err)
+	expectTrue(t, saved != nil, "namespace %s not found after add", name)
+	return saved
+}
+
+// prepareService 创建测试使用的服务，所在的命名空间需要提前创建
+func prepareService(t *testing.T, s store.Store, id, name string) *model.Service {
+	t.Helper()
+	svc := newService(id, name)
+	mustNil(t, s.AddService(svc))
+	saved, err := s.GetServiceByID(id)
+	mustNil(t, err)
+	expectTrue(t, saved != nil, "service %s not found after add", id)
+	return saved
+}
+
+func newService(id, name string) *model.Service {
+	return &model.Service{
+		ID:        id,
+		Name:      name,
+		Namespace: testNamespace,
+		Business:  "storetest",
+		Meta:      map[string]string{"env": "test"},
+		Token:     "token-" + id,
+		Owner:     "polaris",
+		Revision:  "revision-" + id,
+		CreateBy:  "polaris",
+		ModifyBy:  "polaris",
+	}
+}
+
+func newInstance(svc *model.Service, id, host string, port uint32) *model.Instance {
+	return &model.Instance{
+		Proto: &apiservice.Instance{
+			Id:                wrapperspb.String(id),
+			Service:           wrapperspb.String(svc.Name),
+			Namespace:         wrapperspb.String(svc.Namespace),
+			Host:              wrapperspb.String(host),
+			Port:              wrapperspb.UInt32(port),
+			Protocol:          wrapperspb.String("grpc"),
+			Version:           wrapperspb.String("1.0.0"),
+			Weight:            wrapperspb.UInt32(100),
+			EnableHealthCheck: wrapperspb.Bool(true),
+			Healthy:           wrapperspb.Bool(true),
+			Isolate:           wrapperspb.Bool(false),
+			Metadata:          map[string]string{"env": "test"},
+			Revision:          wrapperspb.String("revision-" + id),
+		},
+		ServiceID: svc.ID,
+		CreateBy:  "polaris",
+		ModifyBy:  "polaris",
+	}
+}
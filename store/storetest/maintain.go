@@ -0,0 +1,508 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package storetest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+const (
+	// electionTimeout 部分存储的 leader 选举是异步进行的，需要等待一段时间
+	electionTimeout = 10 * time.Second
+)
+
+var clientCases = []testCase{
+	{
+		name: "add delete and clean",
+		run: func(t *testing.T, s store.Store) {
+			mustNil(t, s.BatchAddClients([]*model.Client{newClient("client-1"), newClient("client-2")}))
+			first, err := s.GetMoreClients(time.Time{}, true)
+			mustNil(t, err)
+			expectTrue(t, len(first) == 2, "expect 2 clients, got %d", len(first))
+			before := first["client-1"]
+
+			mustNil(t, s.BatchDeleteClients([]string{"client-1"}))
+			more, err := s.GetMoreClients(before.ModifyTime, false)
+			mustNil(t, err)
+			expectTrue(t, more["client-1"] != nil && !more["client-1"].Valid,
+				"incremental load should contain soft deleted client")
+
+			cleaned, err := s.BatchCleanDeletedClients(0, 10)
+			mustNil(t, err)
+			expectTrue(t, cleaned == 1, "expect 1 cleaned client, got %d", cleaned)
+			all, err := s.GetMoreClients(time.Time{}, false)
+			mustNil(t, err)
+			expectTrue(t, len(all) == 1 && all["client-2"] != nil, "only valid client should remain")
+		},
+	},
+}
+
+var grayCases = []testCase{
+	{
+		name: "create and clean",
+		run: func(t *testing.T, s store.Store) {
+			tx, err := s.StartTx()
+			mustNil(t, err)
+			mustNil(t, s.CreateGrayResourceTx(tx, &model.GrayResource{Name: "gray", MatchRule: "{}",
+				CreateBy: "polaris", ModifyBy: "polaris"}))
+			mustNil(t, tx.Commit())
+
+			first, err := s.GetMoreGrayResouces(true, time.Time{})
+			mustNil(t, err)
+			expectTrue(t, len(first) == 1 && first[0].Valid, "expect 1 gray resource, got %d", len(first))
+
+			tx, err = s.StartTx()
+			mustNil(t, err)
+			mustNil(t, s.CleanGrayResource(tx, &model.GrayResource{Name: "gray", ModifyBy: "polaris"}))
+			mustNil(t, tx.Commit())
+
+			more, err := s.GetMoreGrayResouces(false, first[0].ModifyTime)
+			mustNil(t, err)
+			expectTrue(t, len(more) == 1 && !more[0].Valid, "incremental load should contain cleaned gray resource")
+		},
+	},
+}
+
+var adminCases = []testCase{
+	{
+		name: "leader election",
+		run: func(t *testing.T, s store.Store) {
+			const key = "storetest-election"
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			watcher, err := store.WatchLeaderElection(ctx, s, key)
+			mustNil(t, err)
+			defer watcher.Stop()
+			event := waitLeaderEvent(t, watcher, func(e store.LeaderChangeEvent) bool { return true })
+			expectTrue(t, event.Key == key && !event.Leader, "first event should report current state")
+
+			mustNil(t, s.StartLeaderElection(key))
+			eventually(t, electionTimeout, func() bool { return s.IsLeader(key) }, "node should become leader")
+			event = waitLeaderEvent(t, watcher, func(e store.LeaderChangeEvent) bool { return e.Leader })
+			expectTrue(t, event.LeaderHost != "", "leader event should carry leader host")
+
+			elections, err := s.ListLeaderElections()
+			mustNil(t, err)
+			found := false
+			for _, item := range elections {
+				if item.ElectKey == key {
+					found = true
+				}
+			}
+			expectTrue(t, found, "leader election %s should be listed", key)
+
+			mustNil(t, s.ReleaseLeaderElection(key))
+			eventually(t, electionTimeout, func() bool { return !s.IsLeader(key) }, "node should release leader")
+			waitLeaderEvent(t, watcher, func(e store.LeaderChangeEvent) bool { return !e.Leader })
+
+			cancel()
+			for range watcher.Events() {
+			}
+			expectTrue(t, watcher.Err() == context.Canceled, "watcher should stop with ctx error, got %v", watcher.Err())
+		},
+	},
+	{
+		name: "distributed lock",
+		run: func(t *testing.T, s store.Store) {
+			ls, ok := s.(store.DistributedLockStore)
+			if !ok {
+				t.Skip("store does not implement store.DistributedLockStore")
+			}
+			const key = "storetest-lock"
+			lock, err := ls.AcquireLock(key, "owner-1", time.Minute)
+			mustNil(t, err)
+			expectTrue(t, lock.Owner == "owner-1" && lock.Token > 0, "lock should be held by owner-1")
+			token := lock.Token
+
+			_, err = ls.AcquireLock(key, "owner-2", time.Minute)
+			expectCode(t, err, store.DataConflictErr)
+			_, err = ls.RenewLock(key, "owner-2", time.Minute)
+			expectCode(t, err, store.DataConflictErr)
+			expectCode(t, ls.ReleaseLock(key, "owner-2"), store.DataConflictErr)
+			lock, err = ls.RenewLock(key, "owner-1", time.Minute)
+			mustNil(t, err)
+			expectTrue(t, lock.Token == token, "renew should keep fencing token")
+
+			locks, err := ls.ListLocks()
+			mustNil(t, err)
+			expectTrue(t, len(locks) == 1 && locks[0].Key == key, "expect 1 held lock, got %d", len(locks))
+
+			mustNil(t, ls.ReleaseLock(key, "owner-1"))
+			mustNil(t, ls.ReleaseLock(key, "owner-1"))
+			locks, err = ls.ListLocks()
+			mustNil(t, err)
+			expectTrue(t, len(locks) == 0, "released lock should not be listed")
+			_, err = ls.RenewLock(key, "owner-1", time.Minute)
+			expectCode(t, err, store.DataConflictErr)
+
+			lock, err = ls.AcquireLock(key, "owner-2", 50*time.Millisecond)
+			mustNil(t, err)
+			expectTrue(t, lock.Token > token, "fencing token should increase, got %d", lock.Token)
+			token = lock.Token
+			time.Sleep(100 * time.Millisecond)
+			lock, err = ls.AcquireLock(key, "owner-1", time.Minute)
+			mustNil(t, err)
+			expectTrue(t, lock.Token > token, "expired lock should be taken over with a new token")
+			mustNil(t, ls.ReleaseLock(key, "owner-1"))
+
+			_, err = ls.AcquireLock(key, "owner-1", 0)
+			expectCode(t, err, store.EmptyParamsErr)
+		},
+	},
+	{
+		name: "clean deleted instances",
+		run: func(t *testing.T, s store.Store) {
+			prepareNamespace(t, s, testNamespace)
+			svc := prepareService(t, s, "svc-1", "svc")
+			mustNil(t, s.AddInstance(newInstance(svc, "ins-1", "127.0.0.1", 8080)))
+			mustNil(t, s.AddInstance(newInstance(svc, "ins-2", "127.0.0.1", 8081)))
+			mustNil(t, s.DeleteInstance("ins-1"))
+
+			cleaned, err := s.BatchCleanDeletedInstances(0, 10)
+			mustNil(t, err)
+			expectTrue(t, cleaned == 1, "expect 1 cleaned instance, got %d", cleaned)
+			count, err := s.GetInstancesCount()
+			mustNil(t, err)
+			expectTrue(t, count == 1, "valid instance should not be cleaned")
+		},
+	},
+	{
+		name: "purge deleted",
+		run: func(t *testing.T, s store.Store) {
+			rs, ok := s.(store.RetentionStore)
+			if !ok {
+				t.Skip("store does not implement store.RetentionStore")
+			}
+			prepareNamespace(t, s, testNamespace)
+			prepareService(t, s, "svc-1", "svc-1")
+			prepareService(t, s, "svc-2", "svc-2")
+			prepareService(t, s, "svc-3", "svc-3")
+			mustNil(t, s.DeleteService("svc-1", "svc-1", testNamespace))
+			mustNil(t, s.DeleteService("svc-2", "svc-2", testNamespace))
+
+			purged, err := rs.PurgeDeleted(store.RetentionService, time.Hour, 10)
+			mustNil(t, err)
+			expectTrue(t, purged == 0, "recently deleted service should be retained, got %d", purged)
+			purged, err = rs.PurgeDeleted(store.RetentionServiceAlias, 0, 10)
+			mustNil(t, err)
+			expectTrue(t, purged == 0, "service should not be purged as alias, got %d", purged)
+			purged, err = rs.PurgeDeleted(store.RetentionService, 0, 1)
+			mustNil(t, err)
+			expectTrue(t, purged == 1, "expect 1 purged service in a batch, got %d", purged)
+			purged, err = rs.PurgeDeleted(store.RetentionService, 0, 10)
+			mustNil(t, err)
+			expectTrue(t, purged == 1, "expect the rest purged, got %d", purged)
+
+			for _, id := range []string{"svc-1", "svc-2"} {
+				saved, err := s.GetServiceByID(id)
+				mustNil(t, err)
+				expectTrue(t, saved == nil, "purged service %s should not exist", id)
+			}
+			saved, err := s.GetServiceByID("svc-3")
+			mustNil(t, err)
+			expectTrue(t, saved != nil && saved.Valid, "valid service should not be purged")
+
+			_, err = rs.PurgeDeleted(store.RetentionKind("unknown"), 0, 10)
+			expectCode(t, err, store.EmptyParamsErr)
+		},
+	},
+	{
+		name: "unhealthy instances",
+		run: func(t *testing.T, s store.Store) {
+			prepareNamespace(t, s, testNamespace)
+			svc := prepareService(t, s, "svc-1", "svc")
+			mustNil(t, s.AddInstance(newInstance(svc, "ins-1", "127.0.0.1", 8080)))
+			mustNil(t, s.AddInstance(newInstance(svc, "ins-2", "127.0.0.1", 8081)))
+			mustNil(t, s.SetInstanceHealthStatus("ins-1", 0, "revision-2"))
+
+			ids, err := s.GetUnHealthyInstances(-time.Hour, 10)
+			mustNil(t, err)
+			expectTrue(t, len(ids) == 1 && ids[0] == "ins-1", "expect ins-1 unhealthy, got %v", ids)
+		},
+	},
+}
+
+var transactionCases = []testCase{
+	{
+		name: "commit and rollback",
+		run: func(t *testing.T, s store.Store) {
+			tx, err := s.StartTx()
+			mustNil(t, err)
+			mustNil(t, s.CreateRoutingConfigV2Tx(tx, &model.RouterConfig{ID: "rule-1", Name: "rule",
+				Namespace: testNamespace}))
+			mustNil(t, tx.Commit())
+
+			tx, err = s.StartTx()
+			mustNil(t, err)
+			mustNil(t, s.UpdateRoutingConfigV2Tx(tx, &model.RouterConfig{ID: "rule-1", Name: "rule",
+				Namespace: testNamespace, Config: "changed"}))
+			mustNil(t, s.CreateRoutingConfigV2Tx(tx, &model.RouterConfig{ID: "rule-2", Name: "rule-2",
+				Namespace: testNamespace}))
+			mustNil(t, tx.Rollback())
+
+			saved, err := s.GetRoutingConfigV2WithID("rule-1")
+			mustNil(t, err)
+			expectTrue(t, saved != nil && saved.Config == "", "update should be rolled back")
+			notExist, err := s.GetRoutingConfigV2WithID("rule-2")
+			mustNil(t, err)
+			expectTrue(t, notExist == nil, "create should be rolled back")
+		},
+	},
+	{
+		name: "atomic import",
+		run: func(t *testing.T, s store.Store) {
+			ns, ok := s.(store.NamingTxStore)
+			if !ok {
+				t.Skip("store does not implement store.NamingTxStore")
+			}
+			prepareNamespace(t, s, testNamespace)
+			svc := newService("svc-1", "svc")
+			limit := &model.RateLimit{ID: "limit-1", ServiceName: "svc", NamespaceName: testNamespace,
+				Name: "limit", Rule: "{}", Revision: "r1"}
+			contract := &model.ServiceContract{ID: "contract-1", Namespace: testNamespace, Service: "svc",
+				Name: "contract", Protocol: "http", Version: "v1", Revision: "r1"}
+			importAll := func(tx store.Tx) {
+				mustNil(t, ns.AddServiceTx(tx, svc))
+				mustNil(t, ns.BatchAddInstancesTx(tx, []*model.Instance{
+					newInstance(svc, "ins-1", "127.0.0.1", 8080), newInstance(svc, "ins-2", "127.0.0.1", 8081)}))
+				mustNil(t, ns.CreateRoutingConfigTx(tx, &model.RoutingConfig{ID: svc.ID, InBounds: "[]",
+					OutBounds: "[]", Revision: "r1"}))
+				mustNil(t, ns.CreateRateLimitTx(tx, limit))
+				mustNil(t, ns.CreateServiceContractTx(tx, contract))
+			}
+
+			tx, err := s.StartTx()
+			mustNil(t, err)
+			importAll(tx)
+			mustNil(t, tx.Rollback())
+			saved, err := s.GetServiceByID(svc.ID)
+			mustNil(t, err)
+			expectTrue(t, saved == nil, "service should be rolled back")
+			count, err := s.GetInstancesCount()
+			mustNil(t, err)
+			expectTrue(t, count == 0, "instances should be rolled back, got %d", count)
+			savedLimit, err := s.GetRateLimitWithID(limit.ID)
+			mustNil(t, err)
+			expectTrue(t, savedLimit == nil, "rate limit should be rolled back")
+			savedContract, err := s.GetServiceContract(contract.ID)
+			mustNil(t, err)
+			expectTrue(t, savedContract == nil, "service contract should be rolled back")
+
+			tx, err = s.StartTx()
+			mustNil(t, err)
+			importAll(tx)
+			mustNil(t, tx.Commit())
+			count, err = s.GetInstancesCount()
+			mustNil(t, err)
+			expectTrue(t, count == 2, "expect 2 instances, got %d", count)
+			conf, err := s.GetRoutingConfigWithID(svc.ID)
+			mustNil(t, err)
+			expectTrue(t, conf != nil, "routing config should be committed")
+
+			tx, err = s.StartTx()
+			mustNil(t, err)
+			mustNil(t, ns.BatchSetInstanceIsolateTx(tx, []interface{}{"ins-1"}, 1, "r2"))
+			mustNil(t, ns.BatchAppendInstanceMetadataTx(tx, []*model.InstanceMetadataRequest{
+				{InstanceID: "ins-2", Revision: "r2", Metadata: map[string]string{"k": "v"}}}))
+			mustNil(t, ns.DeleteRateLimitTx(tx, limit))
+			mustNil(t, ns.DeleteServiceTx(tx, svc.ID, svc.Name, svc.Namespace))
+			mustNil(t, tx.Rollback())
+			ins, err := s.GetInstance("ins-1")
+			mustNil(t, err)
+			expectTrue(t, ins != nil && !ins.Proto.GetIsolate().GetValue(), "isolate should be rolled back")
+			ins, err = s.GetInstance("ins-2")
+			mustNil(t, err)
+			expectTrue(t, ins != nil && ins.Proto.GetMetadata()["k"] == "", "metadata should be rolled back")
+			savedLimit, err = s.GetRateLimitWithID(limit.ID)
+			mustNil(t, err)
+			expectTrue(t, savedLimit != nil, "rate limit delete should be rolled back")
+			saved, err = s.GetServiceByID(svc.ID)
+			mustNil(t, err)
+			expectTrue(t, saved != nil, "service delete should be rolled back")
+		},
+	},
+	{
+		name: "read view",
+		run: func(t *testing.T, s store.Store) {
+			prepareNamespace(t, s, testNamespace)
+			svc := prepareService(t, s, "svc-1", "svc")
+			mustNil(t, s.AddInstance(newInstance(svc, "ins-1", "127.0.0.1", 8080)))
+
+			tx, err := s.StartReadTx()
+			mustNil(t, err)
+			defer func() {
+				_ = tx.Rollback()
+			}()
+			mustNil(t, tx.CreateReadView())
+			count, err := s.GetInstancesCountTx(tx)
+			mustNil(t, err)
+			expectTrue(t, count == 1, "expect 1 instance in read view, got %d", count)
+			instances, err := s.GetMoreInstances(tx, time.Time{}, true, false, nil)
+			mustNil(t, err)
+			expectTrue(t, len(instances) == 1, "expect 1 instance in read view, got %d", len(instances))
+		},
+	},
+	{
+		name: "legacy transaction",
+		run: func(t *testing.T, s store.Store) {
+			prepareNamespace(t, s, testNamespace)
+			prepareService(t, s, "svc-1", "svc")
+
+			tx, err := s.CreateTransaction()
+			mustNil(t, err)
+			mustNil(t, tx.LockBootstrap("storetest", "127.0.0.1"))
+			ns, err := tx.LockNamespace(testNamespace)
+			mustNil(t, err)
+			expectTrue(t, ns != nil, "lock namespace should return namespace")
+			svc, err := tx.LockService("svc", testNamespace)
+			mustNil(t, err)
+			expectTrue(t, svc != nil && svc.ID == "svc-1", "lock service should return service")
+			svc, err = tx.RLockService("svc", testNamespace)
+			mustNil(t, err)
+			expectTrue(t, svc != nil, "rlock service should return service")
+			mustNil(t, tx.Commit())
+		},
+	},
+	{
+		name: "legacy transaction row lock",
+		run: func(t *testing.T, s store.Store) {
+			prepareNamespace(t, s, testNamespace)
+			prepareService(t, s, "svc-1", "svc")
+
+			holder, err := s.CreateTransaction()
+			mustNil(t, err)
+			_, err = holder.LockService("svc", testNamespace)
+			mustNil(t, err)
+
+			locked := make(chan error, 1)
+			go func() {
+				waiter, err := s.CreateTransaction()
+				if err == nil {
+					_, err = waiter.LockService("svc", testNamespace)
+				}
+				if err == nil {
+					err = waiter.Commit()
+				}
+				locked <- err
+			}()
+			select {
+			case err := <-locked:
+				t.Fatalf("lock service should wait for the holder to commit, got %v", err)
+			case <-time.After(100 * time.Millisecond):
+			}
+			mustNil(t, holder.Commit())
+			select {
+			case err := <-locked:
+				mustNil(t, err)
+			case <-time.After(electionTimeout):
+				t.Fatalf("lock service should succeed after the holder commits")
+			}
+		},
+	},
+	{
+		name: "with tx retry",
+		run: func(t *testing.T, s store.Store) {
+			prepareNamespace(t, s, testNamespace)
+			prepareService(t, s, "svc-1", "svc")
+
+			attempts := 0
+			err := store.WithTx(s, func(tx store.UnifiedTx) error {
+				attempts++
+				svc, err := tx.LockService("svc", testNamespace)
+				if err != nil {
+					return err
+				}
+				expectTrue(t, svc != nil && svc.ID == "svc-1", "lock service should return service")
+				id := fmt.Sprintf("rule-%d", attempts)
+				if err := s.CreateRoutingConfigV2Tx(tx, &model.RouterConfig{ID: id, Name: id,
+					Namespace: testNamespace}); err != nil {
+					return err
+				}
+				if attempts == 1 {
+					return store.NewStatusError(store.DeadlockErr, "storetest deadlock")
+				}
+				return nil
+			}, store.WithRetryBackoff(0))
+			mustNil(t, err)
+			expectTrue(t, attempts == 2, "expect 2 attempts, got %d", attempts)
+			first, err := s.GetRoutingConfigV2WithID("rule-1")
+			mustNil(t, err)
+			expectTrue(t, first == nil, "failed attempt should be rolled back")
+			second, err := s.GetRoutingConfigV2WithID("rule-2")
+			mustNil(t, err)
+			expectTrue(t, second != nil, "retried attempt should be committed")
+
+			err = store.WithTx(s, func(tx store.UnifiedTx) error {
+				return store.NewStatusError(store.DeadlockErr, "storetest deadlock")
+			}, store.WithDeadlockRetries(0))
+			expectCode(t, err, store.DeadlockErr)
+		},
+	},
+	{
+		name: "savepoint",
+		run: func(t *testing.T, s store.Store) {
+			ts, ok := s.(store.TxStore)
+			if !ok {
+				t.Skip("store does not implement store.TxStore")
+			}
+			tx, err := ts.BeginTx(store.TxOptions{})
+			mustNil(t, err)
+			mustNil(t, s.CreateRoutingConfigV2Tx(tx, &model.RouterConfig{ID: "rule-1", Name: "rule-1",
+				Namespace: testNamespace}))
+			mustNil(t, tx.Savepoint("outer"))
+			mustNil(t, s.CreateRoutingConfigV2Tx(tx, &model.RouterConfig{ID: "rule-2", Name: "rule-2",
+				Namespace: testNamespace}))
+			mustNil(t, tx.Savepoint("inner"))
+			mustNil(t, s.CreateRoutingConfigV2Tx(tx, &model.RouterConfig{ID: "rule-3", Name: "rule-3",
+				Namespace: testNamespace}))
+			mustNil(t, tx.RollbackToSavepoint("inner"))
+			mustNil(t, tx.ReleaseSavepoint("inner"))
+			expectCode(t, tx.RollbackToSavepoint("inner"), store.NotFoundResource)
+			mustNil(t, tx.RollbackToSavepoint("outer"))
+			mustNil(t, s.CreateRoutingConfigV2Tx(tx, &model.RouterConfig{ID: "rule-4", Name: "rule-4",
+				Namespace: testNamespace}))
+			expectCode(t, tx.Savepoint("bad name"), store.EmptyParamsErr)
+			mustNil(t, tx.Commit())
+
+			for id, exist := range map[string]bool{"rule-1": true, "rule-2": false, "rule-3": false, "rule-4": true} {
+				saved, err := s.GetRoutingConfigV2WithID(id)
+				mustNil(t, err)
+				expectTrue(t, (saved != nil) == exist, "%s exist should be %v", id, exist)
+			}
+		},
+	},
+}
+
+func newClient(id string) *model.Client {
+	return &model.Client{
+		Proto: &apiservice.Client{
+			Id:      wrapperspb.String(id),
+			Host:    wrapperspb.String("127.0.0.1"),
+			Version: wrapperspb.String("1.0.0"),
+		},
+	}
+}
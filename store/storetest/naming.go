@@ -0,0 +1,402 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package storetest
+
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+var namespaceCases = []testCase{
+	{
+		name: "add and get",
+		run: func(t *testing.T, s store.Store) {
+			saved := prepareNamespace(t, s, testNamespace)
+			expectTrue(t, saved.Valid, "namespace should be valid")
+			expectTrue(t, saved.Owner == "polaris", "unexpected owner %s", saved.Owner)
+			expectTrue(t, !saved.ModifyTime.IsZero(), "namespace mtime should be set by store")
+
+			notExist, err := s.GetNamespace("not-exist")
+			mustNil(t, err)
+			expectTrue(t, notExist == nil, "get not exist namespace should return nil")
+		},
+	},
+	{
+		name: "add duplicate",
+		run: func(t *testing.T, s store.Store) {
+			prepareNamespace(t, s, testNamespace)
+			err := s.AddNamespace(&model.Namespace{Name: testNamespace})
+			expectCode(t, err, store.DuplicateEntryErr)
+		},
+	},
+	{
+		name: "update",
+		run: func(t *testing.T, s store.Store) {
+			prepareNamespace(t, s, testNamespace)
+			mustNil(t, s.UpdateNamespace(&model.Namespace{Name: testNamespace, Comment: "updated", Owner: "other"}))
+			mustNil(t, s.UpdateNamespaceToken(testNamespace, "new-token"))
+
+			saved, err := s.GetNamespace(testNamespace)
+			mustNil(t, err)
+			expectTrue(t, saved.Comment == "updated", "unexpected comment %s", saved.Comment)
+			expectTrue(t, saved.Token == "new-token", "unexpected token %s", saved.Token)
+
+			err = s.UpdateNamespace(&model.Namespace{Name: "not-exist"})
+			expectCode(t, err, store.AffectedRowsNotMatch)
+		},
+	},
+	{
+		name: "list with page",
+		run: func(t *testing.T, s store.Store) {
+			for _, name := range []string{"ns-a", "ns-b", "ns-c"} {
+				prepareNamespace(t, s, name)
+			}
+			list, total, err := s.GetNamespaces(map[string][]string{"name": {"ns-a", "ns-b"}}, 0, 10)
+			mustNil(t, err)
+			expectTrue(t, total == 2 && len(list) == 2, "expect 2 namespaces, got total=%d len=%d", total, len(list))
+
+			list, total, err = s.GetNamespaces(map[string][]string{}, 1, 1)
+			mustNil(t, err)
+			expectTrue(t, total == 3 && len(list) == 1, "expect page of 1 in 3, got total=%d len=%d", total, len(list))
+		},
+	},
+	{
+		name: "incremental with soft delete",
+		run: func(t *testing.T, s store.Store) {
+			saved := prepareNamespace(t, s, testNamespace)
+
+			all, err := s.GetMoreNamespaces(time.Time{})
+			mustNil(t, err)
+			expectTrue(t, findNamespace(all, testNamespace) != nil, "incremental load should contain namespace")
+
+			tx, err := s.CreateTransaction()
+			mustNil(t, err)
+			mustNil(t, tx.DeleteNamespace(testNamespace))
+			mustNil(t, tx.Commit())
+
+			deleted, err := s.GetNamespace(testNamespace)
+			mustNil(t, err)
+			expectTrue(t, deleted == nil, "deleted namespace should not be returned")
+
+			more, err := s.GetMoreNamespaces(saved.ModifyTime)
+			mustNil(t, err)
+			item := findNamespace(more, testNamespace)
+			expectTrue(t, item != nil && !item.Valid, "incremental load should contain soft deleted namespace")
+
+			none, err := s.GetMoreNamespaces(future(item.ModifyTime))
+			mustNil(t, err)
+			expectTrue(t, len(none) == 0, "incremental load after latest mtime should be empty, got %d", len(none))
+		},
+	},
+}
+
+var serviceCases = []testCase{
+	{
+		name: "add requires namespace",
+		run: func(t *testing.T, s store.Store) {
+			err := s.AddService(newService("svc-1", "svc"))
+			expectCode(t, err, store.NotFoundNamespace)
+		},
+	},
+	{
+		name: "add and get",
+		run: func(t *testing.T, s store.Store) {
+			prepareNamespace(t, s, testNamespace)
+			saved := prepareService(t, s, "svc-1", "svc")
+			expectTrue(t, saved.Valid, "service should be valid")
+			expectTrue(t, saved.Meta["env"] == "test", "service meta lost: %v", saved.Meta)
+
+			byName, err := s.GetService("svc", testNamespace)
+			mustNil(t, err)
+			expectTrue(t, byName != nil && byName.ID == "svc-1", "get service by name failed")
+
+			token, err := s.GetSourceServiceToken("svc", testNamespace)
+			mustNil(t, err)
+			expectTrue(t, token != nil && token.Token == saved.Token, "get source service token failed")
+
+			count, err := s.GetServicesCount()
+			mustNil(t, err)
+			expectTrue(t, count == 1, "expect 1 service, got %d", count)
+		},
+	},
+	{
+		name: "add duplicate",
+		run: func(t *testing.T, s store.Store) {
+			prepareNamespace(t, s, testNamespace)
+			prepareService(t, s, "svc-1", "svc")
+			expectCode(t, s.AddService(newService("svc-2", "svc")), store.DuplicateEntryErr)
+		},
+	},
+	{
+		name: "update",
+		run: func(t *testing.T, s store.Store) {
+			prepareNamespace(t, s, testNamespace)
+			svc := prepareService(t, s, "svc-1", "svc")
+			svc.Comment = "updated"
+			svc.Owner = "other"
+			svc.Revision = "revision-2"
+			svc.CreateBy, svc.ModifyBy = "", "operator"
+			mustNil(t, s.UpdateService(svc, false))
+
+			saved, err := s.GetServiceByID("svc-1")
+			mustNil(t, err)
+			expectTrue(t, saved.Comment == "updated", "unexpected comment %s", saved.Comment)
+			expectTrue(t, saved.Owner == "polaris", "owner should not be updated when needUpdateOwner=false")
+			expectTrue(t, saved.CreateBy == "polaris" && saved.ModifyBy == "operator",
+				"unexpected operators %s/%s", saved.CreateBy, saved.ModifyBy)
+
+			svc.ModifyBy = ""
+			mustNil(t, s.UpdateService(svc, false))
+			saved, err = s.GetServiceByID("svc-1")
+			mustNil(t, err)
+			expectTrue(t, saved.ModifyBy == "operator", "empty operator should keep %s, got %s", "operator", saved.ModifyBy)
+
+			mustNil(t, s.UpdateServiceToken("svc-1", "new-token", "revision-3"))
+			saved, err = s.GetServiceByID("svc-1")
+			mustNil(t, err)
+			expectTrue(t, saved.Token == "new-token", "unexpected token %s", saved.Token)
+
+			expectCode(t, s.UpdateService(newService("not-exist", "not-exist"), true), store.AffectedRowsNotMatch)
+		},
+	},
+	{
+		name: "list with filter and page",
+		run: func(t *testing.T, s store.Store) {
+			prepareNamespace(t, s, testNamespace)
+			prepareService(t, s, "svc-1", "order-a")
+			prepareService(t, s, "svc-2", "order-b")
+			prepareService(t, s, "svc-3", "user")
+
+			total, list, err := s.GetServices(map[string]string{"name": "order*"}, nil, nil, 0, 10)
+			mustNil(t, err)
+			expectTrue(t, total == 2 && len(list) == 2, "fuzzy query expect 2, got total=%d len=%d", total, len(list))
+
+			total, list, err = s.GetServices(map[string]string{}, nil, nil, 0, 2)
+			mustNil(t, err)
+			expectTrue(t, total == 3 && len(list) == 2, "page query expect 2 of 3, got total=%d len=%d", total, len(list))
+
+			batch, err := s.GetServicesBatch([]*model.Service{{Name: "user", Namespace: testNamespace}})
+			mustNil(t, err)
+			expectTrue(t, len(batch) == 1 && batch[0].ID == "svc-3", "get services batch failed")
+		},
+	},
+	{
+		name: "alias",
+		run: func(t *testing.T, s store.Store) {
+			prepareNamespace(t, s, testNamespace)
+			source := prepareService(t, s, "svc-1", "svc")
+			alias := newService("alias-1", "svc-alias")
+			alias.Reference = source.ID
+			mustNil(t, s.AddService(alias))
+
+			total, aliases, err := s.GetServiceAliases(map[string]string{"service": "svc"}, 0, 10)
+			mustNil(t, err)
+			expectTrue(t, total == 1 && len(aliases) == 1, "expect 1 alias, got %d", total)
+			expectTrue(t, aliases[0].Alias == "svc-alias" && aliases[0].ServiceID == source.ID,
+				"unexpected alias %+v", aliases[0])
+
+			mustNil(t, s.DeleteServiceAlias("svc-alias", testNamespace))
+			total, _, err = s.GetServiceAliases(map[string]string{"service": "svc"}, 0, 10)
+			mustNil(t, err)
+			expectTrue(t, total == 0, "deleted alias should not be listed")
+		},
+	},
+	{
+		name: "soft delete and incremental",
+		run: func(t *testing.T, s store.Store) {
+			prepareNamespace(t, s, testNamespace)
+			saved := prepareService(t, s, "svc-1", "svc")
+
+			mustNil(t, s.DeleteService(saved.ID, saved.Name, saved.Namespace))
+			deleted, err := s.GetServiceByID(saved.ID)
+			mustNil(t, err)
+			expectTrue(t, deleted == nil, "deleted service should not be returned")
+
+			first, err := s.GetMoreServices(time.Time{}, true, false, true)
+			mustNil(t, err)
+			expectTrue(t, first[saved.ID] == nil, "first update should only load valid services")
+
+			more, err := s.GetMoreServices(saved.ModifyTime, false, false, true)
+			mustNil(t, err)
+			item := more[saved.ID]
+			expectTrue(t, item != nil && !item.Valid, "incremental load should contain soft deleted service")
+
+			none, err := s.GetMoreServices(future(item.ModifyTime), false, false, true)
+			mustNil(t, err)
+			expectTrue(t, len(none) == 0, "incremental load after latest mtime should be empty")
+
+			// 软删除后可以重新创建同名服务
+			prepareService(t, s, "svc-2", "svc")
+		},
+	},
+}
+
+var instanceCases = []testCase{
+	{
+		name: "add requires service",
+		run: func(t *testing.T, s store.Store) {
+			prepareNamespace(t, s, testNamespace)
+			ins := newInstance(newService("not-exist", "not-exist"), "ins-1", "127.0.0.1", 8080)
+			expectCode(t, s.AddInstance(ins), store.NotFoundService)
+		},
+	},
+	{
+		name: "add and get",
+		run: func(t *testing.T, s store.Store) {
+			prepareNamespace(t, s, testNamespace)
+			svc := prepareService(t, s, "svc-1", "svc")
+			mustNil(t, s.BatchAddInstances([]*model.Instance{
+				newInstance(svc, "ins-1", "127.0.0.1", 8080),
+				newInstance(svc, "ins-2", "127.0.0.1", 8081),
+				newInstance(svc, "ins-3", "127.0.0.2", 8080),
+			}))
+
+			saved, err := s.GetInstance("ins-1")
+			mustNil(t, err)
+			expectTrue(t, saved != nil && saved.Valid && saved.ServiceID == svc.ID, "get instance failed")
+			expectTrue(t, saved.Proto.GetMetadata()["env"] == "test", "instance metadata lost")
+
+			count, err := s.GetInstancesCount()
+			mustNil(t, err)
+			expectTrue(t, count == 3, "expect 3 instances, got %d", count)
+
+			main, err := s.GetInstancesMainByService(svc.ID, "127.0.0.1")
+			mustNil(t, err)
+			expectTrue(t, len(main) == 2, "expect 2 instances on host, got %d", len(main))
+
+			total, list, err := s.GetExpandInstances(map[string]string{"host": "127.0.0.1"}, nil, 0, 1)
+			mustNil(t, err)
+			expectTrue(t, total == 2 && len(list) == 1, "expand query expect 1 of 2, got total=%d len=%d",
+				total, len(list))
+
+			isolate, err := s.BatchGetInstanceIsolate(map[string]bool{"ins-1": true, "not-exist": true})
+			mustNil(t, err)
+			_, exist := isolate["not-exist"]
+			expectTrue(t, len(isolate) == 1 && !exist, "isolate check should only return existing ids")
+
+			brief, err := s.GetInstancesBrief(map[string]bool{"ins-1": true})
+			mustNil(t, err)
+			expectTrue(t, brief["ins-1"] != nil && brief["ins-1"].Proto.GetServiceToken().GetValue() == svc.Token,
+				"instance brief should carry the service token")
+		},
+	},
+	{
+		name: "update",
+		run: func(t *testing.T, s store.Store) {
+			prepareNamespace(t, s, testNamespace)
+			svc := prepareService(t, s, "svc-1", "svc")
+			ins := newInstance(svc, "ins-1", "127.0.0.1", 8080)
+			mustNil(t, s.AddInstance(ins))
+
+			ins.Proto.Weight = wrapperspb.UInt32(50)
+			ins.CreateBy, ins.ModifyBy = "", "operator"
+			mustNil(t, s.UpdateInstance(ins))
+			saved, err := s.GetInstance("ins-1")
+			mustNil(t, err)
+			expectTrue(t, saved.Proto.GetWeight().GetValue() == 50, "unexpected weight %d", saved.Proto.GetWeight().GetValue())
+			expectTrue(t, saved.CreateBy == "polaris" && saved.ModifyBy == "operator",
+				"unexpected operators %s/%s", saved.CreateBy, saved.ModifyBy)
+
+			// 没有指定操作人时保留原有的操作人
+			ins.ModifyBy = ""
+			mustNil(t, s.UpdateInstance(ins))
+			saved, err = s.GetInstance("ins-1")
+			mustNil(t, err)
+			expectTrue(t, saved.ModifyBy == "operator", "empty operator should keep %s, got %s", "operator", saved.ModifyBy)
+
+			expectCode(t, s.UpdateInstance(newInstance(svc, "not-exist", "127.0.0.1", 8081)), store.AffectedRowsNotMatch)
+		},
+	},
+	{
+		name: "update status",
+		run: func(t *testing.T, s store.Store) {
+			prepareNamespace(t, s, testNamespace)
+			svc := prepareService(t, s, "svc-1", "svc")
+			mustNil(t, s.AddInstance(newInstance(svc, "ins-1", "127.0.0.1", 8080)))
+
+			mustNil(t, s.SetInstanceHealthStatus("ins-1", 0, "revision-2"))
+			mustNil(t, s.BatchSetInstanceIsolate([]interface{}{"ins-1"}, 1, "revision-3"))
+			saved, err := s.GetInstance("ins-1")
+			mustNil(t, err)
+			expectTrue(t, !saved.Proto.GetHealthy().GetValue(), "instance should be unhealthy")
+			expectTrue(t, saved.Proto.GetIsolate().GetValue(), "instance should be isolated")
+
+			mustNil(t, s.BatchAppendInstanceMetadata([]*model.InstanceMetadataRequest{{
+				InstanceID: "ins-1", Revision: "revision-4", Metadata: map[string]string{"zone": "a"},
+			}}))
+			mustNil(t, s.BatchRemoveInstanceMetadata([]*model.InstanceMetadataRequest{{
+				InstanceID: "ins-1", Revision: "revision-5", Keys: []string{"env"},
+			}}))
+			saved, err = s.GetInstance("ins-1")
+			mustNil(t, err)
+			_, hasEnv := saved.Proto.GetMetadata()["env"]
+			expectTrue(t, saved.Proto.GetMetadata()["zone"] == "a" && !hasEnv,
+				"unexpected metadata %v", saved.Proto.GetMetadata())
+		},
+	},
+	{
+		name: "soft delete and clean",
+		run: func(t *testing.T, s store.Store) {
+			prepareNamespace(t, s, testNamespace)
+			svc := prepareService(t, s, "svc-1", "svc")
+			mustNil(t, s.AddInstance(newInstance(svc, "ins-1", "127.0.0.1", 8080)))
+			mustNil(t, s.AddInstance(newInstance(svc, "ins-2", "127.0.0.1", 8081)))
+			before, err := s.GetInstance("ins-1")
+			mustNil(t, err)
+
+			// CleanInstance 只会清理已经软删除的实例
+			mustNil(t, s.CleanInstance("ins-2"))
+			alive, err := s.GetInstance("ins-2")
+			mustNil(t, err)
+			expectTrue(t, alive != nil, "clean instance should not remove valid instance")
+
+			mustNil(t, s.DeleteInstance("ins-1"))
+			deleted, err := s.GetInstance("ins-1")
+			mustNil(t, err)
+			expectTrue(t, deleted == nil, "deleted instance should not be returned")
+
+			first, err := s.GetMoreInstances(nil, time.Time{}, true, true, nil)
+			mustNil(t, err)
+			expectTrue(t, first["ins-1"] == nil && first["ins-2"] != nil, "first update should only load valid instances")
+
+			more, err := s.GetMoreInstances(nil, before.ModifyTime, false, true, []string{svc.ID})
+			mustNil(t, err)
+			item := more["ins-1"]
+			expectTrue(t, item != nil && !item.Valid, "incremental load should contain soft deleted instance")
+
+			mustNil(t, s.CleanInstance("ins-1"))
+			more, err = s.GetMoreInstances(nil, time.Time{}, false, true, nil)
+			mustNil(t, err)
+			expectTrue(t, more["ins-1"] == nil, "cleaned instance should be removed")
+		},
+	},
+}
+
+func findNamespace(items []*model.Namespace, name string) *model.Namespace {
+	for _, item := range items {
+		if item.Name == name {
+			return item
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package storetest
+
+import (
+	"testing"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// revisionCases 校验条件更新，未实现 store.RevisionStore 的插件跳过
+var revisionCases = []testCase{
+	{
+		name: "rate limit compare and swap",
+		run: func(t *testing.T, s store.Store) {
+			rs := revisionStore(t, s)
+			limit := &model.RateLimit{ID: "limit-1", ServiceName: "svc", NamespaceName: testNamespace,
+				Name: "limit", Rule: "{}", Revision: "r1"}
+			mustNil(t, s.CreateRateLimit(limit))
+
+			limit.Rule, limit.Revision = "{\"a\":1}", "r2"
+			expectCode(t, rs.UpdateRateLimitCAS(limit, "r0"), store.DataConflictErr)
+			mustNil(t, rs.UpdateRateLimitCAS(limit, "r1"))
+			saved, err := s.GetRateLimitWithID(limit.ID)
+			mustNil(t, err)
+			expectTrue(t, saved != nil && saved.Revision == "r2" && saved.Rule == limit.Rule, "rate limit should be updated")
+
+			// 另一个控制台基于旧版本的修改不能覆盖已经写入的数据
+			stale := *limit
+			stale.Rule, stale.Revision = "{\"b\":1}", "r3"
+			expectCode(t, rs.UpdateRateLimitCAS(&stale, "r1"), store.DataConflictErr)
+			saved, err = s.GetRateLimitWithID(limit.ID)
+			mustNil(t, err)
+			expectTrue(t, saved.Rule == limit.Rule, "conflicting update should not be written")
+
+			expectCode(t, rs.UpdateRateLimitCAS(&model.RateLimit{ID: "not-exist"}, ""), store.AffectedRowsNotMatch)
+		},
+	},
+	{
+		name: "service compare and swap",
+		run: func(t *testing.T, s store.Store) {
+			prepareNamespace(t, s, testNamespace)
+			svc := prepareService(t, s, "svc-1", "svc")
+			rs := revisionStore(t, s)
+
+			update := *svc
+			update.Comment, update.Revision = "updated", "revision-2"
+			expectCode(t, rs.UpdateServiceCAS(&update, false, "stale"), store.DataConflictErr)
+			mustNil(t, rs.UpdateServiceCAS(&update, false, svc.Revision))
+			saved, err := s.GetServiceByID(svc.ID)
+			mustNil(t, err)
+			expectTrue(t, saved.Revision == "revision-2" && saved.Comment == "updated", "service should be updated")
+		},
+	},
+	{
+		name: "strategy compare and swap",
+		run: func(t *testing.T, s store.Store) {
+			mustNil(t, s.AddStrategy(&model.StrategyDetail{ID: "strategy-1", Name: "strategy", Action: "READ_WRITE",
+				Owner: "owner", Revision: "r1"}))
+			rs := revisionStore(t, s)
+
+			modify := &model.ModifyStrategyDetail{ID: "strategy-1", Action: "READ_WRITE", Comment: "updated", Revision: "r2"}
+			mustNil(t, rs.UpdateStrategyCAS(modify, "r1"))
+			expectCode(t, rs.UpdateStrategyCAS(modify, "r1"), store.DataConflictErr)
+			saved, err := s.GetStrategyDetail("strategy-1")
+			mustNil(t, err)
+			expectTrue(t, saved.Revision == "r2" && saved.Comment == "updated", "strategy should be updated")
+		},
+	},
+}
+
+// revisionStore 插件未实现 store.RevisionStore 时跳过当前用例
+func revisionStore(t *testing.T, s store.Store) store.RevisionStore {
+	t.Helper()
+	rs, ok := s.(store.RevisionStore)
+	if !ok {
+		t.Skip("store does not implement store.RevisionStore")
+	}
+	return rs
+}
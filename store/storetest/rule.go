@@ -0,0 +1,273 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package storetest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+var routingConfigCases = []testCase{
+	{
+		name: "crud and incremental",
+		run: func(t *testing.T, s store.Store) {
+			prepareNamespace(t, s, testNamespace)
+			svc := prepareService(t, s, "svc-1", "svc")
+			conf := &model.RoutingConfig{ID: svc.ID, InBounds: "in", OutBounds: "out", Revision: "r1"}
+			mustNil(t, s.CreateRoutingConfig(conf))
+			expectCode(t, s.CreateRoutingConfig(conf), store.DuplicateEntryErr)
+
+			saved, err := s.GetRoutingConfigWithService(svc.Name, svc.Namespace)
+			mustNil(t, err)
+			expectTrue(t, saved != nil && saved.InBounds == "in", "get routing config by service failed")
+
+			conf.InBounds = "in-2"
+			mustNil(t, s.UpdateRoutingConfig(conf))
+			saved, err = s.GetRoutingConfigWithID(svc.ID)
+			mustNil(t, err)
+			expectTrue(t, saved.InBounds == "in-2", "update routing config failed")
+
+			total, _, err := s.GetRoutingConfigs(map[string]string{"name": svc.Name}, 0, 10)
+			mustNil(t, err)
+			expectTrue(t, total == 1, "expect 1 routing config, got %d", total)
+
+			mustNil(t, s.DeleteRoutingConfig(svc.ID))
+			deleted, err := s.GetRoutingConfigWithID(svc.ID)
+			mustNil(t, err)
+			expectTrue(t, deleted == nil, "deleted routing config should not be returned")
+
+			more, err := s.GetRoutingConfigsForCache(saved.ModifyTime, false)
+			mustNil(t, err)
+			expectTrue(t, len(more) == 1 && !more[0].Valid, "incremental load should contain soft deleted config")
+			first, err := s.GetRoutingConfigsForCache(time.Time{}, true)
+			mustNil(t, err)
+			expectTrue(t, len(first) == 0, "first update should only load valid configs")
+
+			expectCode(t, s.UpdateRoutingConfig(conf), store.AffectedRowsNotMatch)
+		},
+	},
+}
+
+var routingConfigV2Cases = []testCase{
+	{
+		name: "crud and incremental",
+		run: func(t *testing.T, s store.Store) {
+			conf := &model.RouterConfig{ID: "rule-1", Name: "rule", Namespace: testNamespace, Config: "{}",
+				Revision: "r1", CreateBy: "polaris", ModifyBy: "polaris"}
+			mustNil(t, s.CreateRoutingConfigV2(conf))
+			expectCode(t, s.CreateRoutingConfigV2(conf), store.DuplicateEntryErr)
+
+			conf.Enable = true
+			conf.Revision = "r2"
+			mustNil(t, s.EnableRouting(conf))
+			saved, err := s.GetRoutingConfigV2WithID(conf.ID)
+			mustNil(t, err)
+			expectTrue(t, saved != nil && saved.Enable, "enable routing config failed")
+
+			conf.Config = "{\"a\":1}"
+			conf.CreateBy, conf.ModifyBy = "", "operator"
+			mustNil(t, s.UpdateRoutingConfigV2(conf))
+			saved, err = s.GetRoutingConfigV2WithID(conf.ID)
+			mustNil(t, err)
+			expectTrue(t, saved.Config == conf.Config, "update routing config failed")
+			expectTrue(t, saved.CreateBy == "polaris" && saved.ModifyBy == "operator",
+				"unexpected operators %s/%s", saved.CreateBy, saved.ModifyBy)
+
+			mustNil(t, s.DeleteRoutingConfigV2(conf.ID))
+			deleted, err := s.GetRoutingConfigV2WithID(conf.ID)
+			mustNil(t, err)
+			expectTrue(t, deleted == nil, "deleted routing config should not be returned")
+
+			more, err := s.GetRoutingConfigsV2ForCache(saved.ModifyTime, false)
+			mustNil(t, err)
+			expectTrue(t, len(more) == 1 && !more[0].Valid, "incremental load should contain soft deleted config")
+
+			expectCode(t, s.UpdateRoutingConfigV2(conf), store.AffectedRowsNotMatch)
+		},
+	},
+}
+
+var rateLimitCases = []testCase{
+	{
+		name: "crud and incremental",
+		run: func(t *testing.T, s store.Store) {
+			limit := &model.RateLimit{ID: "limit-1", ServiceName: "svc", NamespaceName: testNamespace,
+				Name: "limit", Rule: "{}", Revision: "r1", Disable: true, CreateBy: "polaris", ModifyBy: "polaris"}
+			mustNil(t, s.CreateRateLimit(limit))
+			expectCode(t, s.CreateRateLimit(limit), store.DuplicateEntryErr)
+
+			limit.Disable = false
+			mustNil(t, s.EnableRateLimit(limit))
+			saved, err := s.GetRateLimitWithID(limit.ID)
+			mustNil(t, err)
+			expectTrue(t, saved != nil && !saved.Disable, "enable rate limit failed")
+
+			limit.Rule = "{\"a\":1}"
+			limit.CreateBy, limit.ModifyBy = "", "operator"
+			mustNil(t, s.UpdateRateLimit(limit))
+			total, list, err := s.GetExtendRateLimits(map[string]string{"name": "lim*"}, 0, 10)
+			mustNil(t, err)
+			expectTrue(t, total == 1 && list[0].Rule == limit.Rule, "query rate limits failed")
+			expectTrue(t, list[0].CreateBy == "polaris" && list[0].ModifyBy == "operator",
+				"unexpected operators %s/%s", list[0].CreateBy, list[0].ModifyBy)
+
+			mustNil(t, s.DeleteRateLimit(limit))
+			deleted, err := s.GetRateLimitWithID(limit.ID)
+			mustNil(t, err)
+			expectTrue(t, deleted == nil, "deleted rate limit should not be returned")
+
+			more, err := s.GetRateLimitsForCache(saved.ModifyTime, false)
+			mustNil(t, err)
+			expectTrue(t, len(more) == 1 && !more[0].Valid, "incremental load should contain soft deleted rule")
+
+			expectCode(t, s.UpdateRateLimit(limit), store.AffectedRowsNotMatch)
+		},
+	},
+}
+
+var circuitBreakerCases = []testCase{
+	{
+		name: "crud and incremental",
+		run: func(t *testing.T, s store.Store) {
+			rule := &model.CircuitBreakerRule{ID: "cb-1", Name: "cb", Namespace: testNamespace,
+				DstService: "svc", DstNamespace: testNamespace, Rule: "{}", Revision: "r1",
+				CreateBy: "polaris", ModifyBy: "polaris"}
+			mustNil(t, s.CreateCircuitBreakerRule(rule))
+			expectCode(t, s.CreateCircuitBreakerRule(rule), store.DuplicateEntryErr)
+
+			exist, err := s.HasCircuitBreakerRule(rule.ID)
+			mustNil(t, err)
+			expectTrue(t, exist, "circuitbreaker rule should exist")
+			exist, err = s.HasCircuitBreakerRuleByName(rule.Name, rule.Namespace)
+			mustNil(t, err)
+			expectTrue(t, exist, "circuitbreaker rule should exist by name")
+			exist, err = s.HasCircuitBreakerRuleByNameExcludeId(rule.Name, rule.Namespace, rule.ID)
+			mustNil(t, err)
+			expectTrue(t, !exist, "circuitbreaker rule should be excluded by id")
+
+			rule.Enable = true
+			mustNil(t, s.EnableCircuitBreakerRule(rule))
+			rule.Description = "updated"
+			rule.CreateBy, rule.ModifyBy = "", "operator"
+			mustNil(t, s.UpdateCircuitBreakerRule(rule))
+			total, list, err := s.GetCircuitBreakerRules(map[string]string{"id": rule.ID}, 0, 10)
+			mustNil(t, err)
+			expectTrue(t, total == 1 && list[0].Enable && list[0].Description == "updated",
+				"query circuitbreaker rules failed")
+			expectTrue(t, list[0].CreateBy == "polaris" && list[0].ModifyBy == "operator",
+				"unexpected operators %s/%s", list[0].CreateBy, list[0].ModifyBy)
+
+			mustNil(t, s.DeleteCircuitBreakerRule(rule.ID))
+			exist, err = s.HasCircuitBreakerRule(rule.ID)
+			mustNil(t, err)
+			expectTrue(t, !exist, "deleted circuitbreaker rule should not exist")
+
+			more, err := s.GetCircuitBreakerRulesForCache(list[0].ModifyTime, false)
+			mustNil(t, err)
+			expectTrue(t, len(more) == 1 && !more[0].Valid, "incremental load should contain soft deleted rule")
+
+			expectCode(t, s.UpdateCircuitBreakerRule(rule), store.AffectedRowsNotMatch)
+		},
+	},
+}
+
+var faultDetectCases = []testCase{
+	{
+		name: "crud and incremental",
+		run: func(t *testing.T, s store.Store) {
+			rule := &model.FaultDetectRule{ID: "fd-1", Name: "fd", Namespace: testNamespace,
+				DstService: "svc", DstNamespace: testNamespace, Rule: "{}", Revision: "r1",
+				CreateBy: "polaris", ModifyBy: "polaris"}
+			mustNil(t, s.CreateFaultDetectRule(rule))
+			expectCode(t, s.CreateFaultDetectRule(rule), store.DuplicateEntryErr)
+
+			exist, err := s.HasFaultDetectRuleByName(rule.Name, rule.Namespace)
+			mustNil(t, err)
+			expectTrue(t, exist, "fault detect rule should exist by name")
+			exist, err = s.HasFaultDetectRuleByNameExcludeId(rule.Name, rule.Namespace, rule.ID)
+			mustNil(t, err)
+			expectTrue(t, !exist, "fault detect rule should be excluded by id")
+
+			rule.Description = "updated"
+			rule.CreateBy, rule.ModifyBy = "", "operator"
+			mustNil(t, s.UpdateFaultDetectRule(rule))
+			total, list, err := s.GetFaultDetectRules(map[string]string{"dst_service": "svc"}, 0, 10)
+			mustNil(t, err)
+			expectTrue(t, total == 1 && list[0].Description == "updated", "query fault detect rules failed")
+			expectTrue(t, list[0].CreateBy == "polaris" && list[0].ModifyBy == "operator",
+				"unexpected operators %s/%s", list[0].CreateBy, list[0].ModifyBy)
+
+			mustNil(t, s.DeleteFaultDetectRule(rule.ID))
+			exist, err = s.HasFaultDetectRule(rule.ID)
+			mustNil(t, err)
+			expectTrue(t, !exist, "deleted fault detect rule should not exist")
+
+			more, err := s.GetFaultDetectRulesForCache(list[0].ModifyTime, false)
+			mustNil(t, err)
+			expectTrue(t, len(more) == 1 && !more[0].Valid, "incremental load should contain soft deleted rule")
+
+			expectCode(t, s.UpdateFaultDetectRule(rule), store.AffectedRowsNotMatch)
+		},
+	},
+}
+
+var serviceContractCases = []testCase{
+	{
+		name: "crud and interfaces",
+		run: func(t *testing.T, s store.Store) {
+			contract := &model.ServiceContract{ID: "contract-1", Namespace: testNamespace, Service: "svc",
+				Name: "contract", Protocol: "http", Version: "v1", Revision: "r1"}
+			mustNil(t, s.CreateServiceContract(contract))
+			expectCode(t, s.CreateServiceContract(contract), store.DuplicateEntryErr)
+
+			contract.ManualInterfaces = map[string]*model.InterfaceDescriptor{
+				"api-1": {ID: "api-1", Method: "GET", Path: "/a"},
+				"api-2": {ID: "api-2", Method: "GET", Path: "/b"},
+			}
+			mustNil(t, s.AddServiceContractInterfaces(contract))
+			contract.ManualInterfaces = map[string]*model.InterfaceDescriptor{
+				"api-3": {ID: "api-3", Method: "POST", Path: "/c"},
+			}
+			mustNil(t, s.AppendServiceContractInterfaces(contract))
+			contract.ManualInterfaces = map[string]*model.InterfaceDescriptor{"api-1": {ID: "api-1"}}
+			mustNil(t, s.DeleteServiceContractInterfaces(contract))
+
+			saved, err := s.GetServiceContract(contract.ID)
+			mustNil(t, err)
+			expectTrue(t, saved != nil && len(saved.ManualInterfaces) == 2, "unexpected interfaces %v",
+				saved.ManualInterfaces)
+
+			contract.Content = "updated"
+			mustNil(t, s.UpdateServiceContract(contract))
+			mustNil(t, s.DeleteServiceContract(contract))
+			deleted, err := s.GetServiceContract(contract.ID)
+			mustNil(t, err)
+			expectTrue(t, deleted == nil, "deleted contract should not be returned")
+
+			more, err := s.GetMoreServiceContracts(false, saved.ModifyTime)
+			mustNil(t, err)
+			expectTrue(t, len(more) == 1 && !more[0].Valid, "incremental load should contain soft deleted contract")
+			first, err := s.GetMoreServiceContracts(true, time.Time{})
+			mustNil(t, err)
+			expectTrue(t, len(first) == 0, "first update should only load valid contracts")
+		},
+	},
+}
@@ -0,0 +1,122 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package storetest
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// streamCases 校验分批遍历，未实现 store.StreamStore 的插件跳过
+var streamCases = []testCase{
+	{
+		name: "range services in batches",
+		run: func(t *testing.T, s store.Store) {
+			ss := streamStore(t, s)
+			prepareNamespace(t, s, testNamespace)
+			for i := 0; i < 5; i++ {
+				prepareService(t, s, fmt.Sprintf("svc-%d", i), fmt.Sprintf("svc-%d", i))
+			}
+			var sizes []int
+			seen := make(map[string]bool)
+			mustNil(t, ss.RangeMoreServices(time.Time{}, true, false, false, 2, func(items []*model.Service) (bool, error) {
+				sizes = append(sizes, len(items))
+				for _, svc := range items {
+					expectTrue(t, !seen[svc.ID], "service %s returned twice", svc.ID)
+					expectTrue(t, svc.Meta == nil, "meta should be dropped when needMeta is false")
+					seen[svc.ID] = true
+				}
+				return true, nil
+			}))
+			expectTrue(t, fmt.Sprint(sizes) == "[2 2 1]", "expect batches [2 2 1], got %v", sizes)
+			expectTrue(t, len(seen) == 5, "expect 5 services, got %d", len(seen))
+		},
+	},
+	{
+		name: "range instances of services",
+		run: func(t *testing.T, s store.Store) {
+			ss := streamStore(t, s)
+			prepareNamespace(t, s, testNamespace)
+			for _, id := range []string{"svc-1", "svc-2"} {
+				svc := prepareService(t, s, id, id)
+				for i := 0; i < 3; i++ {
+					mustNil(t, s.AddInstance(newInstance(svc, fmt.Sprintf("%s-ins-%d", id, i), "127.0.0.1", uint32(8080+i))))
+				}
+			}
+			var sizes []int
+			mustNil(t, ss.RangeMoreInstances(nil, time.Time{}, true, true, []string{"svc-2"}, 2,
+				func(items []*model.Instance) (bool, error) {
+					sizes = append(sizes, len(items))
+					for _, ins := range items {
+						expectTrue(t, ins.ServiceID == "svc-2", "instance %s of another service", ins.Proto.GetId().GetValue())
+						expectTrue(t, len(ins.Proto.GetMetadata()) > 0, "metadata should be kept when needMeta is true")
+					}
+					return true, nil
+				}))
+			total := 0
+			for _, size := range sizes {
+				expectTrue(t, size <= 2, "batch larger than batchSize: %v", sizes)
+				total += size
+			}
+			expectTrue(t, total == 3, "expect 3 instances, got %d", total)
+		},
+	},
+	{
+		name: "range stops",
+		run: func(t *testing.T, s store.Store) {
+			ss := streamStore(t, s)
+			for i := 0; i < 3; i++ {
+				prepareNamespace(t, s, fmt.Sprintf("ns-%d", i))
+			}
+			calls := 0
+			mustNil(t, ss.RangeMoreNamespaces(time.Time{}, 1, func(items []*model.Namespace) (bool, error) {
+				calls++
+				return false, nil
+			}))
+			expectTrue(t, calls == 1, "range should stop when handler returns false, got %d calls", calls)
+
+			failed := errors.New("failed")
+			err := ss.RangeMoreNamespaces(time.Time{}, 1, func(items []*model.Namespace) (bool, error) {
+				return true, failed
+			})
+			expectTrue(t, errors.Is(err, failed), "handler error should be returned, got %v", err)
+
+			calls = 0
+			mustNil(t, ss.RangeMoreNamespaces(future(time.Now()), 1, func(items []*model.Namespace) (bool, error) {
+				calls++
+				return true, nil
+			}))
+			expectTrue(t, calls == 0, "handler should not be called without data")
+		},
+	},
+}
+
+// streamStore 插件未实现 store.StreamStore 时跳过当前用例
+func streamStore(t *testing.T, s store.Store) store.StreamStore {
+	t.Helper()
+	ss, ok := s.(store.StreamStore)
+	if !ok {
+		t.Skip("store does not implement store.StreamStore")
+	}
+	return ss
+}
@@ -0,0 +1,159 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package storetest 提供 store 插件的一致性测试套件，store 插件在自身的单元测试中调用 RunSuite，
+// 即可校验插件是否满足 store.Store 接口注释中约定的行为，例如：
+//
+//	func TestStore(t *testing.T) {
+//		storetest.RunSuite(t, func() (store.Store, error) {
+//			return newTestStore()
+//		})
+//	}
+package storetest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+)
+
+// Factory 创建一个可用的、数据为空的 store.Store，每个测试用例都会调用一次，
+// 用例结束后会调用 store.Store.Destroy 释放资源
+type Factory func() (store.Store, error)
+
+// testCase 单个测试用例
+type testCase struct {
+	name string
+	run  func(t *testing.T, s store.Store)
+}
+
+// RunSuite 针对 factory 创建的存储运行完整的一致性测试
+func RunSuite(t *testing.T, factory Factory) {
+	suites := []struct {
+		name  string
+		cases []testCase
+	}{
+		{name: "NamespaceStore", cases: namespaceCases},
+		{name: "ServiceStore", cases: serviceCases},
+		{name: "InstanceStore", cases: instanceCases},
+		{name: "RoutingConfigStore", cases: routingConfigCases},
+		{name: "RoutingConfigStoreV2", cases: routingConfigV2Cases},
+		{name: "RateLimitStore", cases: rateLimitCases},
+		{name: "CircuitBreakerStore", cases: circuitBreakerCases},
+		{name: "FaultDetectRuleStore", cases: faultDetectCases},
+		{name: "ServiceContractStore", cases: serviceContractCases},
+		{name: "ClientStore", cases: clientCases},
+		{name: "GrayStore", cases: grayCases},
+		{name: "ConfigFileGroupStore", cases: configGroupCases},
+		{name: "ConfigFileStore", cases: configFileCases},
+		{name: "ConfigFileReleaseStore", cases: configReleaseCases},
+		{name: "ConfigFileReleaseHistoryStore", cases: configHistoryCases},
+		{name: "ConfigFileTemplateStore", cases: configTemplateCases},
+		{name: "UserStore", cases: userCases},
+		{name: "GroupStore", cases: groupCases},
+		{name: "StrategyStore", cases: strategyCases},
+		{name: "RevisionStore", cases: revisionCases},
+		{name: "StreamStore", cases: streamCases},
+		{name: "AdminStore", cases: adminCases},
+		{name: "Transaction", cases: transactionCases},
+	}
+	for _, suite := range suites {
+		suite := suite
+		t.Run(suite.name, func(t *testing.T) {
+			for _, c := range suite.cases {
+				c := c
+				t.Run(c.name, func(t *testing.T) {
+					c.run(t, newStore(t, factory))
+				})
+			}
+		})
+	}
+}
+
+func newStore(t *testing.T, factory Factory) store.Store {
+	t.Helper()
+	s, err := factory()
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := s.Destroy(); err != nil {
+			t.Errorf("destroy store: %v", err)
+		}
+	})
+	return s
+}
+
+// mustNil 要求 err 为空
+func mustNil(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// expectCode 要求 err 对应的状态码为 code
+func expectCode(t *testing.T, err error, code store.StatusCode) {
+	t.Helper()
+	if got := store.Code(err); got != code {
+		t.Fatalf("expect status code %d, got %d (err: %v)", code, got, err)
+	}
+}
+
+// expectTrue 要求条件为真
+func expectTrue(t *testing.T, ok bool, format string, args ...interface{}) {
+	t.Helper()
+	if !ok {
+		t.Fatalf(format, args...)
+	}
+}
+
+// eventually 在 timeout 内等待条件成立，用于异步生效的操作，例如 leader 选举
+func eventually(t *testing.T, timeout time.Duration, cond func() bool, format string, args ...interface{}) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf(format, args...)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// future 返回一个晚于 mtime 的时间，增量接口以该时间为起点时不应返回任何数据
+func future(mtime time.Time) time.Time {
+	return mtime.Add(time.Hour)
+}
+
+// waitLeaderEvent 等待满足 match 的 leader 变化事件
+func waitLeaderEvent(t *testing.T, watcher store.LeaderWatcher,
+	match func(e store.LeaderChangeEvent) bool) store.LeaderChangeEvent {
+	t.Helper()
+	timeout := time.After(electionTimeout)
+	for {
+		select {
+		case event, ok := <-watcher.Events():
+			expectTrue(t, ok, "leader watcher stopped: %v", watcher.Err())
+			if match(event) {
+				return event
+			}
+		case <-timeout:
+			t.Fatalf("wait for leader change event timeout")
+		}
+	}
+}
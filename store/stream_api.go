@@ -0,0 +1,250 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package store
+
+import (
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// DefaultRangeBatchSize Range* 方法未指定 batchSize 时每批数据的数量
+const DefaultRangeBatchSize = 1000
+
+// BatchHandler 分批处理数据的回调函数
+// 返回值：bool，是否继续遍历
+// 返回值：error，回调函数处理结果，error不为nil，则停止遍历过程，并且通过Range*返回error
+type BatchHandler[T any] func(items []T) (bool, error)
+
+// StreamStore 可选接口，以分批回调的方式遍历 GetMore* 的结果，用于在首次全量加载时控制内存占用
+// 过滤条件与对应的 GetMore* 方法一致，batchSize 小于等于 0 时使用 DefaultRangeBatchSize，
+// handler 中的数据在回调返回后不会再被存储层使用，调用方可以直接持有
+type StreamStore interface {
+	// RangeMoreNamespaces 分批遍历增量命名空间，参见 GetMoreNamespaces
+	RangeMoreNamespaces(mtime time.Time, batchSize int, handler BatchHandler[*model.Namespace]) error
+	// RangeMoreServices 分批遍历增量服务，参见 GetMoreServices
+	RangeMoreServices(mtime time.Time, firstUpdate, disableBusiness, needMeta bool, batchSize int, handler BatchHandler[*model.Service]) error
+	// RangeMoreInstances 分批遍历增量实例，参见 GetMoreInstances
+	RangeMoreInstances(tx Tx, mtime time.Time, firstUpdate, needMeta bool, serviceID []string, batchSize int, handler BatchHandler[*model.Instance]) error
+	// RangeMoreClients 分批遍历增量客户端，参见 GetMoreClients
+	RangeMoreClients(mtime time.Time, firstUpdate bool, batchSize int, handler BatchHandler[*model.Client]) error
+	// RangeMoreServiceContracts 分批遍历增量服务契约，参见 GetMoreServiceContracts
+	RangeMoreServiceContracts(firstUpdate bool, mtime time.Time, batchSize int, handler BatchHandler[*model.ServiceContract]) error
+	// RangeMoreGrayResources 分批遍历增量灰度资源，参见 GetMoreGrayResouces
+	RangeMoreGrayResources(firstUpdate bool, mtime time.Time, batchSize int, handler BatchHandler[*model.GrayResource]) error
+	// RangeRoutingConfigsForCache 分批遍历增量的 v1 版本路由配置，参见 GetRoutingConfigsForCache
+	RangeRoutingConfigsForCache(mtime time.Time, firstUpdate bool, batchSize int, handler BatchHandler[*model.RoutingConfig]) error
+	// RangeRoutingConfigsV2ForCache 分批遍历增量的 v2 版本路由配置，参见 GetRoutingConfigsV2ForCache
+	RangeRoutingConfigsV2ForCache(mtime time.Time, firstUpdate bool, batchSize int, handler BatchHandler[*model.RouterConfig]) error
+	// RangeRateLimitsForCache 分批遍历增量限流规则，参见 GetRateLimitsForCache
+	RangeRateLimitsForCache(mtime time.Time, firstUpdate bool, batchSize int, handler BatchHandler[*model.RateLimit]) error
+	// RangeCircuitBreakerRulesForCache 分批遍历增量熔断规则，参见 GetCircuitBreakerRulesForCache
+	RangeCircuitBreakerRulesForCache(mtime time.Time, firstUpdate bool, batchSize int, handler BatchHandler[*model.CircuitBreakerRule]) error
+	// RangeFaultDetectRulesForCache 分批遍历增量主动探测规则，参见 GetFaultDetectRulesForCache
+	RangeFaultDetectRulesForCache(mtime time.Time, firstUpdate bool, batchSize int, handler BatchHandler[*model.FaultDetectRule]) error
+	// RangeMoreConfigGroup 分批遍历增量配置分组，参见 GetMoreConfigGroup
+	RangeMoreConfigGroup(firstUpdate bool, mtime time.Time, batchSize int, handler BatchHandler[*model.ConfigFileGroup]) error
+	// RangeMoreReleaseFile 分批遍历增量配置发布，参见 GetMoreReleaseFile
+	RangeMoreReleaseFile(firstUpdate bool, modifyTime time.Time, batchSize int, handler BatchHandler[*model.ConfigFileRelease]) error
+	// RangeUsersForCache 分批遍历增量用户，参见 GetUsersForCache
+	RangeUsersForCache(mtime time.Time, firstUpdate bool, batchSize int, handler BatchHandler[*model.User]) error
+	// RangeGroupsForCache 分批遍历增量用户组，参见 GetGroupsForCache
+	RangeGroupsForCache(mtime time.Time, firstUpdate bool, batchSize int, handler BatchHandler[*model.UserGroup]) error
+	// RangeStrategyDetailsForCache 分批遍历增量鉴权策略，参见 GetStrategyDetailsForCache
+	RangeStrategyDetailsForCache(mtime time.Time, firstUpdate bool, batchSize int, handler BatchHandler[*model.StrategyDetail]) error
+}
+
+// NewStreamStore s 实现了 StreamStore 时直接返回，内置的 memory 以及 sqlite 存储都原生实现了分批查询；
+// 未实现 StreamStore 的第三方存储调用 GetMore* 一次性查询之后再分批回调，
+// 此时只统一了调用方式，无法降低存储层查询时的内存占用
+func NewStreamStore(s Store) StreamStore {
+	if ss, ok := s.(StreamStore); ok {
+		return ss
+	}
+	return &batchStreamStore{store: s}
+}
+
+// batchStreamStore 基于 GetMore* 实现的 StreamStore
+type batchStreamStore struct {
+	store Store
+}
+
+// RangeMoreNamespaces 实现 StreamStore
+func (b *batchStreamStore) RangeMoreNamespaces(mtime time.Time, batchSize int, handler BatchHandler[*model.Namespace]) error {
+	items, err := b.store.GetMoreNamespaces(mtime)
+	if err != nil {
+		return err
+	}
+	return RangeBatches(items, batchSize, handler)
+}
+
+// RangeMoreServices 实现 StreamStore
+func (b *batchStreamStore) RangeMoreServices(mtime time.Time, firstUpdate, disableBusiness, needMeta bool, batchSize int, handler BatchHandler[*model.Service]) error {
+	items, err := b.store.GetMoreServices(mtime, firstUpdate, disableBusiness, needMeta)
+	if err != nil {
+		return err
+	}
+	return RangeBatches(mapValues(items), batchSize, handler)
+}
+
+// RangeMoreInstances 实现 StreamStore
+func (b *batchStreamStore) RangeMoreInstances(tx Tx, mtime time.Time, firstUpdate, needMeta bool, serviceID []string, batchSize int, handler BatchHandler[*model.Instance]) error {
+	items, err := b.store.GetMoreInstances(tx, mtime, firstUpdate, needMeta, serviceID)
+	if err != nil {
+		return err
+	}
+	return RangeBatches(mapValues(items), batchSize, handler)
+}
+
+// RangeMoreClients 实现 StreamStore
+func (b *batchStreamStore) RangeMoreClients(mtime time.Time, firstUpdate bool, batchSize int, handler BatchHandler[*model.Client]) error {
+	items, err := b.store.GetMoreClients(mtime, firstUpdate)
+	if err != nil {
+		return err
+	}
+	return RangeBatches(mapValues(items), batchSize, handler)
+}
+
+// RangeMoreServiceContracts 实现 StreamStore
+func (b *batchStreamStore) RangeMoreServiceContracts(firstUpdate bool, mtime time.Time, batchSize int, handler BatchHandler[*model.ServiceContract]) error {
+	items, err := b.store.GetMoreServiceContracts(firstUpdate, mtime)
+	if err != nil {
+		return err
+	}
+	return RangeBatches(items, batchSize, handler)
+}
+
+// RangeMoreGrayResources 实现 StreamStore
+func (b *batchStreamStore) RangeMoreGrayResources(firstUpdate bool, mtime time.Time, batchSize int, handler BatchHandler[*model.GrayResource]) error {
+	items, err := b.store.GetMoreGrayResouces(firstUpdate, mtime)
+	if err != nil {
+		return err
+	}
+	return RangeBatches(items, batchSize, handler)
+}
+
+// RangeRoutingConfigsForCache 实现 StreamStore
+func (b *batchStreamStore) RangeRoutingConfigsForCache(mtime time.Time, firstUpdate bool, batchSize int, handler BatchHandler[*model.RoutingConfig]) error {
+	items, err := b.store.GetRoutingConfigsForCache(mtime, firstUpdate)
+	if err != nil {
+		return err
+	}
+	return RangeBatches(items, batchSize, handler)
+}
+
+// RangeRoutingConfigsV2ForCache 实现 StreamStore
+func (b *batchStreamStore) RangeRoutingConfigsV2ForCache(mtime time.Time, firstUpdate bool, batchSize int, handler BatchHandler[*model.RouterConfig]) error {
+	items, err := b.store.GetRoutingConfigsV2ForCache(mtime, firstUpdate)
+	if err != nil {
+		return err
+	}
+	return RangeBatches(items, batchSize, handler)
+}
+
+// RangeRateLimitsForCache 实现 StreamStore
+func (b *batchStreamStore) RangeRateLimitsForCache(mtime time.Time, firstUpdate bool, batchSize int, handler BatchHandler[*model.RateLimit]) error {
+	items, err := b.store.GetRateLimitsForCache(mtime, firstUpdate)
+	if err != nil {
+		return err
+	}
+	return RangeBatches(items, batchSize, handler)
+}
+
+// RangeCircuitBreakerRulesForCache 实现 StreamStore
+func (b *batchStreamStore) RangeCircuitBreakerRulesForCache(mtime time.Time, firstUpdate bool, batchSize int, handler BatchHandler[*model.CircuitBreakerRule]) error {
+	items, err := b.store.GetCircuitBreakerRulesForCache(mtime, firstUpdate)
+	if err != nil {
+		return err
+	}
+	return RangeBatches(items, batchSize, handler)
+}
+
+// RangeFaultDetectRulesForCache 实现 StreamStore
+func (b *batchStreamStore) RangeFaultDetectRulesForCache(mtime time.Time, firstUpdate bool, batchSize int, handler BatchHandler[*model.FaultDetectRule]) error {
+	items, err := b.store.GetFaultDetectRulesForCache(mtime, firstUpdate)
+	if err != nil {
+		return err
+	}
+	return RangeBatches(items, batchSize, handler)
+}
+
+// RangeMoreConfigGroup 实现 StreamStore
+func (b *batchStreamStore) RangeMoreConfigGroup(firstUpdate bool, mtime time.Time, batchSize int, handler BatchHandler[*model.ConfigFileGroup]) error {
+	items, err := b.store.GetMoreConfigGroup(firstUpdate, mtime)
+	if err != nil {
+		return err
+	}
+	return RangeBatches(items, batchSize, handler)
+}
+
+// RangeMoreReleaseFile 实现 StreamStore
+func (b *batchStreamStore) RangeMoreReleaseFile(firstUpdate bool, modifyTime time.Time, batchSize int, handler BatchHandler[*model.ConfigFileRelease]) error {
+	items, err := b.store.GetMoreReleaseFile(firstUpdate, modifyTime)
+	if err != nil {
+		return err
+	}
+	return RangeBatches(items, batchSize, handler)
+}
+
+// RangeUsersForCache 实现 StreamStore
+func (b *batchStreamStore) RangeUsersForCache(mtime time.Time, firstUpdate bool, batchSize int, handler BatchHandler[*model.User]) error {
+	items, err := b.store.GetUsersForCache(mtime, firstUpdate)
+	if err != nil {
+		return err
+	}
+	return RangeBatches(items, batchSize, handler)
+}
+
+// RangeGroupsForCache 实现 StreamStore
+func (b *batchStreamStore) RangeGroupsForCache(mtime time.Time, firstUpdate bool, batchSize int, handler BatchHandler[*model.UserGroup]) error {
+	items, err := b.store.GetGroupsForCache(mtime, firstUpdate)
+	if err != nil {
+		return err
+	}
+	return RangeBatches(items, batchSize, handler)
+}
+
+// RangeStrategyDetailsForCache 实现 StreamStore
+func (b *batchStreamStore) RangeStrategyDetailsForCache(mtime time.Time, firstUpdate bool, batchSize int, handler BatchHandler[*model.StrategyDetail]) error {
+	items, err := b.store.GetStrategyDetailsForCache(mtime, firstUpdate)
+	if err != nil {
+		return err
+	}
+	return RangeBatches(items, batchSize, handler)
+}
+
+// RangeBatches 将 items 按照 batchSize 分批回调 handler，供存储插件实现 StreamStore 时使用
+func RangeBatches[T any](items []T, batchSize int, handler BatchHandler[T]) error {
+	if batchSize <= 0 {
+		batchSize = DefaultRangeBatchSize
+	}
+	for start := 0; start < len(items); start += batchSize {
+		end := start + batchSize
+		if end > len(items) {
+			end = len(items)
+		}
+		next, err := handler(items[start:end:end])
+		if err != nil {
+			return err
+		}
+		if !next {
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package store_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/memory"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+func TestRangeBatches(t *testing.T) {
+	var sizes []int
+	err := store.RangeBatches([]int{1, 2, 3, 4, 5, 6, 7}, 3, func(items []int) (bool, error) {
+		sizes = append(sizes, len(items))
+		// 回调中追加数据不能覆盖下一批数据
+		_ = append(items, 0)
+		return true, nil
+	})
+	if err != nil || fmt.Sprint(sizes) != "[3 3 1]" {
+		t.Fatalf("sizes %v, err %v", sizes, err)
+	}
+	calls := 0
+	_ = store.RangeBatches(make([]int, store.DefaultRangeBatchSize+1), 0, func(items []int) (bool, error) {
+		calls++
+		return calls < 1, nil
+	})
+	if calls != 1 {
+		t.Fatalf("expect to stop after the first batch, got %d calls", calls)
+	}
+}
+
+func TestStreamStoreFallback(t *testing.T) {
+	m := memory.New()
+	if _, ok := m.(store.StreamStore); !ok {
+		t.Fatal("memory store should implement StreamStore natively")
+	}
+	for i := 0; i < 5; i++ {
+		addNamespace(t, m, fmt.Sprintf("ns-%d", i))
+	}
+	var sizes []int
+	ss := store.NewStreamStore(legacyStore{Store: m})
+	err := ss.RangeMoreNamespaces(time.Time{}, 2, func(items []*model.Namespace) (bool, error) {
+		sizes = append(sizes, len(items))
+		return true, nil
+	})
+	if err != nil || fmt.Sprint(sizes) != "[2 2 1]" {
+		t.Fatalf("sizes %v, err %v", sizes, err)
+	}
+}
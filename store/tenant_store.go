@@ -0,0 +1,3073 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// NewTenantStore 返回只能访问 tenant 租户数据的 Store 视图，用于在同一个存储中托管多个租户。
+// 命名空间按照 Owner 划分租户，命名空间下的服务、实例、规则、配置等资源都属于该命名空间的租户；
+// 用户、用户组以及鉴权策略按照 Owner 划分，ID 为 tenant 的主账户同样属于该租户。
+// 写入时 Owner 为空则填充为 tenant，Owner 为其他租户或者操作其他租户的资源时返回 TenantMismatchErr；
+// 读取、GetMore* 增量查询以及计数只返回该租户的数据，其他租户的资源视为不存在。
+// 客户端、灰度资源、配置模板、L5 路由表、选主以及分布式锁不属于任何租户，直接透传；
+// 跨租户的清理操作 BatchCleanDeletedInstances、CleanConfigFileReleaseHistory 以及 PurgeDeleted 返回 TenantMismatchErr。
+// 分页查询会先从底层存储中查询出全部满足过滤条件的数据，按照租户过滤之后再分页；
+// 服务别名以及实例的分页查询将租户的命名空间下推到过滤条件中，在每个命名空间上分页查询之后再合并。
+// 租户的命名空间以及服务集合缓存在视图中，按照修改时间增量刷新，通过视图写入命名空间时重新加载。
+// 可选接口中的条件更新与对应的 Update 方法做相同的校验后交给底层存储；Search*、*Page、Range* 以及 Watch
+// 基于视图本身的查询方法实现，不直接使用底层存储的实现，以保证只返回该租户的数据。
+// 视图不负责底层存储的生命周期，Initialize 以及 Destroy 不做任何处理
+func NewTenantStore(s Store, tenant string) Store {
+	return &tenantStore{store: s, tenant: tenant}
+}
+
+var (
+	_ Store                = (*tenantStore)(nil)
+	_ NamingTxStore        = (*tenantStore)(nil)
+	_ DistributedLockStore = (*tenantStore)(nil)
+	_ LeaderWatchStore     = (*tenantStore)(nil)
+	_ RevisionStore        = (*tenantStore)(nil)
+	_ RetentionStore       = (*tenantStore)(nil)
+	_ QueryStore           = (*tenantStore)(nil)
+	_ CursorStore          = (*tenantStore)(nil)
+	_ StreamStore          = (*tenantStore)(nil)
+	_ WatchableStore       = (*tenantStore)(nil)
+)
+
+// tenantStore 租户视图
+type tenantStore struct {
+	store  Store
+	tenant string
+	// ownedNamespaces 当前租户的命名空间集合
+	ownedNamespaces ownedSet
+	// ownedServices 当前租户的服务 ID 集合
+	ownedServices ownedSet
+}
+
+func (t *tenantStore) mismatch(kind, key string) error {
+	return NewStatusError(TenantMismatchErr, fmt.Sprintf("%s %s does not belong to tenant %s", kind, key, t.tenant))
+}
+
+func (t *tenantStore) unsupported(method string) error {
+	return NewStatusError(TenantMismatchErr, fmt.Sprintf("%s crosses tenants, not supported by tenant %s", method, t.tenant))
+}
+
+// claim 写入数据时 Owner 为空则填充为当前租户，不为空时需要与当前租户一致
+func (t *tenantStore) claim(owner *string, kind, key string) error {
+	if *owner == "" {
+		*owner = t.tenant
+		return nil
+	}
+	if *owner != t.tenant {
+		return t.mismatch(kind, key)
+	}
+	return nil
+}
+
+// fillOwner 服务以及配置分组的 Owner 不参与租户划分，只在为空时填充为当前租户
+func (t *tenantStore) fillOwner(owner *string) {
+	if *owner == "" {
+		*owner = t.tenant
+	}
+}
+
+// ownsNamespace 命名空间是否存在（包括已删除的命名空间），以及是否属于当前租户，
+// 归属按照租户的命名空间集合判断，已删除的命名空间同样保留原有的归属
+func (t *tenantStore) ownsNamespace(name string) (exist, owned bool, err error) {
+	names, err := t.namespaces()
+	if err != nil {
+		return false, false, err
+	}
+	if contains(names, name) {
+		return true, true, nil
+	}
+	return t.ownedNamespaces.known(name), false, nil
+}
+
+// visibleNamespace 命名空间下的资源对当前租户是否可见
+func (t *tenantStore) visibleNamespace(name string) (bool, error) {
+	_, owned, err := t.ownsNamespace(name)
+	return owned, err
+}
+
+// checkNamespace 在命名空间下新增资源时，命名空间需要存在并且属于当前租户
+func (t *tenantStore) checkNamespace(name string) error {
+	if err := t.guardNamespace(name); err != nil {
+		return err
+	}
+	ns, err := t.store.GetNamespace(name)
+	switch {
+	case err != nil:
+		return err
+	case ns == nil:
+		return NewStatusError(NotFoundNamespace, fmt.Sprintf("namespace %s not found", name))
+	case ns.Owner != t.tenant:
+		return t.mismatch("namespace", name)
+	}
+	return nil
+}
+
+// guardNamespace 操作命名空间下已有的资源时，命名空间（包括已删除的命名空间）属于其他租户则拒绝，
+// 从未出现过的命名空间交给底层存储处理
+func (t *tenantStore) guardNamespace(name string) error {
+	exist, owned, err := t.ownsNamespace(name)
+	if err != nil {
+		return err
+	}
+	if exist && !owned {
+		return t.mismatch("namespace", name)
+	}
+	return nil
+}
+
+// guard 已有资源所在的命名空间需要属于当前租户
+func (t *tenantStore) guard(namespace, kind, key string) error {
+	owned, err := t.visibleNamespace(namespace)
+	if err != nil {
+		return err
+	}
+	if !owned {
+		return t.mismatch(kind, key)
+	}
+	return nil
+}
+
+// namespaces 当前租户的全部命名空间，包括已删除的命名空间，返回的集合不能修改
+func (t *tenantStore) namespaces() (map[string]struct{}, error) {
+	owned, changed, err := t.ownedNamespaces.refresh(func(since time.Time) (map[string]bool, time.Time, error) {
+		items, err := t.store.GetMoreNamespaces(since)
+		if err != nil {
+			return nil, since, err
+		}
+		changes := make(map[string]bool, len(items))
+		for _, ns := range items {
+			changes[ns.Name] = ns.Owner == t.tenant
+			if ns.ModifyTime.After(since) {
+				since = ns.ModifyTime
+			}
+		}
+		return changes, since, nil
+	})
+	if changed {
+		// 命名空间的归属发生变化时，已有的服务也可能发生变化，需要重新加载
+		t.ownedServices.reset()
+	}
+	return owned, err
+}
+
+// invalidateNamespaces 通过视图写入命名空间之后重新加载租户的命名空间以及服务集合
+func (t *tenantStore) invalidateNamespaces() {
+	t.ownedNamespaces.reset()
+	t.ownedServices.reset()
+}
+
+// ownedSet 当前租户拥有的资源集合，第一次使用时全量加载，之后按照修改时间增量刷新。
+// 集合采用写时复制，已经返回给调用方的集合不会被修改
+type ownedSet struct {
+	lock  sync.Mutex
+	keys  map[string]struct{}
+	since time.Time
+	// seen 加载过的全部资源，包括属于其他租户的资源
+	seen map[string]struct{}
+}
+
+// refresh 使用 load 加载修改时间不早于 since 的数据，load 返回每条数据是否属于当前租户以及最新的修改时间；
+// 返回最新的集合以及集合是否发生了变化
+func (o *ownedSet) refresh(load func(since time.Time) (map[string]bool, time.Time, error)) (
+	map[string]struct{}, bool, error) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	since := o.since
+	if o.keys == nil {
+		o.keys = make(map[string]struct{})
+		o.seen = make(map[string]struct{})
+	} else {
+		since = since.Add(-defaultPollLookback)
+	}
+	changes, latest, err := load(since)
+	if err != nil {
+		return nil, false, err
+	}
+	var next map[string]struct{}
+	for key, owned := range changes {
+		o.seen[key] = struct{}{}
+		if owned == contains(o.keys, key) {
+			continue
+		}
+		if next == nil {
+			next = make(map[string]struct{}, len(o.keys)+1)
+			for k := range o.keys {
+				next[k] = struct{}{}
+			}
+		}
+		if owned {
+			next[key] = struct{}{}
+		} else {
+			delete(next, key)
+		}
+	}
+	if next != nil {
+		o.keys = next
+	}
+	if latest.After(o.since) {
+		o.since = latest
+	}
+	return o.keys, next != nil, nil
+}
+
+// reset 下一次使用时重新全量加载
+func (o *ownedSet) reset() {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.keys = nil
+	o.seen = nil
+	o.since = time.Time{}
+}
+
+// known 资源是否加载过，包括属于其他租户的资源
+func (o *ownedSet) known(key string) bool {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	return contains(o.seen, key)
+}
+
+// tenantScope 当前租户的全部命名空间以及服务 ID，包括已删除的数据
+type tenantScope struct {
+	namespaces map[string]struct{}
+	services   map[string]struct{}
+}
+
+// owns 规则等资源优先按照命名空间判断是否属于当前租户，没有命名空间时按照服务判断
+func (s *tenantScope) owns(namespace, serviceID string) bool {
+	if namespace != "" {
+		return contains(s.namespaces, namespace)
+	}
+	return contains(s.services, serviceID)
+}
+
+func (t *tenantStore) scope() (*tenantScope, error) {
+	owned, err := t.namespaces()
+	if err != nil {
+		return nil, err
+	}
+	services, _, err := t.ownedServices.refresh(func(since time.Time) (map[string]bool, time.Time, error) {
+		items, err := t.store.GetMoreServices(since, false, false, false)
+		if err != nil {
+			return nil, since, err
+		}
+		changes := make(map[string]bool, len(items))
+		for id, svc := range items {
+			changes[id] = contains(owned, svc.Namespace)
+			if svc.ModifyTime.After(since) {
+				since = svc.ModifyTime
+			}
+		}
+		return changes, since, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tenantScope{namespaces: owned, services: services}, nil
+}
+
+// checkScope 新增规则时，规则所在的命名空间以及服务需要属于当前租户
+func (t *tenantStore) checkScope(kind, namespace, serviceID string) error {
+	if namespace == "" && serviceID == "" {
+		return NewStatusError(TenantMismatchErr,
+			fmt.Sprintf("%s without namespace and service can not be scoped to tenant %s", kind, t.tenant))
+	}
+	if namespace != "" {
+		if err := t.checkNamespace(namespace); err != nil {
+			return err
+		}
+	}
+	if serviceID != "" {
+		return t.checkService(serviceID)
+	}
+	return nil
+}
+
+// visibleService 服务对当前租户是否可见
+func (t *tenantStore) visibleService(id string) (bool, error) {
+	svc, err := t.store.GetServiceByID(id)
+	if err != nil || svc == nil {
+		return false, err
+	}
+	return t.visibleNamespace(svc.Namespace)
+}
+
+// checkService 在服务下新增资源时，服务需要存在并且属于当前租户
+func (t *tenantStore) checkService(id string) error {
+	svc, err := t.store.GetServiceByID(id)
+	if err != nil {
+		return err
+	}
+	if svc == nil {
+		return NewStatusError(NotFoundService, fmt.Sprintf("service %s not found", id))
+	}
+	return t.guard(svc.Namespace, "service", id)
+}
+
+// guardService 操作已有的服务时，服务（包括已删除的服务）需要属于当前租户，
+// 从未出现过的服务交给底层存储处理，id 为空时返回 NotFoundService
+func (t *tenantStore) guardService(id string) error {
+	if id == "" {
+		return NewStatusError(NotFoundService, "service id is empty")
+	}
+	scope, err := t.scope()
+	if err != nil {
+		return err
+	}
+	if contains(scope.services, id) {
+		return nil
+	}
+	if t.ownedServices.known(id) {
+		return t.mismatch("service", id)
+	}
+	return nil
+}
+
+// guardInstances 操作已有的实例时，实例属于其他租户则拒绝，不存在的实例交给底层存储处理
+func (t *tenantStore) guardInstances(ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	query := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		query[id] = true
+	}
+	briefs, err := t.store.GetInstancesBrief(query)
+	if err != nil || len(briefs) == 0 {
+		return err
+	}
+	owned, err := t.namespaces()
+	if err != nil {
+		return err
+	}
+	for id, ins := range briefs {
+		if !contains(owned, instanceNamespace(ins)) {
+			return t.mismatch("instance", id)
+		}
+	}
+	return nil
+}
+
+// checkInstance 新增或者更新实例时，实例所在的命名空间以及服务需要属于当前租户
+func (t *tenantStore) checkInstance(instance *model.Instance) error {
+	if err := t.guardInstances(instance.Proto.GetId().GetValue()); err != nil {
+		return err
+	}
+	if namespace := instanceNamespace(instance); namespace != "" {
+		if err := t.checkNamespace(namespace); err != nil {
+			return err
+		}
+	}
+	if instance.ServiceID != "" {
+		return t.checkService(instance.ServiceID)
+	}
+	return nil
+}
+
+func instanceNamespace(instance *model.Instance) string {
+	return instance.Proto.GetNamespace().GetValue()
+}
+
+func instanceIDs(ids []interface{}) []string {
+	ret := make([]string, 0, len(ids))
+	for _, id := range ids {
+		ret = append(ret, fmt.Sprint(id))
+	}
+	return ret
+}
+
+func contains(set map[string]struct{}, key string) bool {
+	_, ok := set[key]
+	return ok
+}
+
+// keep 返回满足条件的数据
+func keep[T any](items []T, pred func(T) bool) []T {
+	ret := make([]T, 0, len(items))
+	for _, item := range items {
+		if pred(item) {
+			ret = append(ret, item)
+		}
+	}
+	return ret
+}
+
+// keepMap 返回满足条件的数据
+func keepMap[K comparable, V any](items map[K]V, pred func(K, V) bool) map[K]V {
+	ret := make(map[K]V, len(items))
+	for k, v := range items {
+		if pred(k, v) {
+			ret[k] = v
+		}
+	}
+	return ret
+}
+
+// pageOf 过滤之后再分页，返回过滤之后的总数，limit 为 0 时表示不限制
+func pageOf[T any](items []T, pred func(T) bool, offset, limit uint32) (uint32, []T) {
+	items = keep(items, pred)
+	return uint32(len(items)), paginate(items, offset, limit)
+}
+
+// paginate 根据 offset 以及 limit 截取数据，limit 为 0 时表示不限制
+func paginate[T any](items []T, offset, limit uint32) []T {
+	if int(offset) >= len(items) {
+		return []T{}
+	}
+	items = items[offset:]
+	if limit > 0 && int(limit) < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
+
+// pageByNamespace 将当前租户的命名空间下推到过滤条件的 key 字段中分页查询。
+// 只涉及一个命名空间时直接交给底层存储分页，否则在每个命名空间上查询前 offset+limit 条数据，
+// 按照 less 合并排序之后再分页，limit 为 0 时表示不限制
+func pageByNamespace[T any](t *tenantStore, filter map[string]string, key string, offset, limit uint32,
+	less func(a, b T) bool, query func(filter map[string]string, offset, limit uint32) (uint32, []T, error)) (
+	uint32, []T, error) {
+	owned, err := t.namespaces()
+	if err != nil {
+		return 0, nil, err
+	}
+	pattern, pinned := filter[key]
+	names := make([]string, 0, len(owned))
+	for name := range owned {
+		if !pinned || matchNamespace(pattern, name) {
+			names = append(names, name)
+		}
+	}
+	switch len(names) {
+	case 0:
+		return 0, []T{}, nil
+	case 1:
+		return query(withFilter(filter, key, names[0]), offset, limit)
+	}
+	var window uint32
+	if limit > 0 && offset+limit > offset {
+		window = offset + limit
+	}
+	var total uint32
+	merged := make([]T, 0)
+	for _, name := range names {
+		count, items, err := query(withFilter(filter, key, name), 0, window)
+		if err != nil {
+			return 0, nil, err
+		}
+		total += count
+		merged = append(merged, items...)
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return less(merged[i], merged[j])
+	})
+	return total, paginate(merged, offset, limit), nil
+}
+
+// matchNamespace 与底层存储的过滤条件保持一致，以 * 结尾表示前缀匹配
+func matchNamespace(pattern, name string) bool {
+	if strings.HasSuffix(pattern, "*") {
+		return strings.HasPrefix(name, strings.TrimSuffix(pattern, "*"))
+	}
+	return pattern == name
+}
+
+// withFilter 返回设置了 key 字段的过滤条件副本
+func withFilter(filter map[string]string, key, value string) map[string]string {
+	ret := make(map[string]string, len(filter)+1)
+	for k, v := range filter {
+		ret[k] = v
+	}
+	ret[key] = value
+	return ret
+}
+
+// newerFirst 与底层存储的排序保持一致，按照修改时间倒序排列，修改时间相同时按照 key 升序排列
+func newerFirst[T any](mtime func(T) time.Time, key func(T) string) func(a, b T) bool {
+	return func(a, b T) bool {
+		if ma, mb := mtime(a), mtime(b); !ma.Equal(mb) {
+			return ma.After(mb)
+		}
+		return key(a) < key(b)
+	}
+}
+
+var (
+	aliasOrder = newerFirst(func(alias *model.ServiceAlias) time.Time { return alias.ModifyTime },
+		func(alias *model.ServiceAlias) string { return alias.ID })
+	instanceOrder = newerFirst(func(ins *model.Instance) time.Time { return ins.ModifyTime },
+		func(ins *model.Instance) string { return ins.Proto.GetId().GetValue() })
+)
+
+// Name 底层存储的名字
+func (t *tenantStore) Name() string {
+	return t.store.Name()
+}
+
+// Initialize 视图不负责底层存储的初始化
+func (t *tenantStore) Initialize(c *Config) error {
+	return nil
+}
+
+// Destroy 视图不负责底层存储的销毁
+func (t *tenantStore) Destroy() error {
+	return nil
+}
+
+// CreateTransaction 创建事务，事务中只能锁定以及删除当前租户的命名空间和服务
+func (t *tenantStore) CreateTransaction() (Transaction, error) {
+	tx, err := t.store.CreateTransaction()
+	if err != nil {
+		return nil, err
+	}
+	return &tenantTransaction{Transaction: tx, tenant: t}, nil
+}
+
+// StartTx 开启事务
+func (t *tenantStore) StartTx() (Tx, error) {
+	return t.store.StartTx()
+}
+
+// StartReadTx 开启只读事务
+func (t *tenantStore) StartReadTx() (Tx, error) {
+	return t.store.StartReadTx()
+}
+
+// tenantTransaction 租户视图的事务
+type tenantTransaction struct {
+	Transaction
+	tenant *tenantStore
+}
+
+// LockNamespace 锁定命名空间，其他租户的命名空间视为不存在
+func (t *tenantTransaction) LockNamespace(name string) (*model.Namespace, error) {
+	if owned, err := t.tenant.visibleNamespace(name); err != nil || !owned {
+		return nil, err
+	}
+	return t.Transaction.LockNamespace(name)
+}
+
+// DeleteNamespace 删除命名空间，不能删除其他租户的命名空间
+func (t *tenantTransaction) DeleteNamespace(name string) error {
+	if err := t.tenant.guardNamespace(name); err != nil {
+		return err
+	}
+	defer t.tenant.invalidateNamespaces()
+	return t.Transaction.DeleteNamespace(name)
+}
+
+// LockService 锁定服务，其他租户的服务视为不存在
+func (t *tenantTransaction) LockService(name string, namespace string) (*model.Service, error) {
+	if owned, err := t.tenant.visibleNamespace(namespace); err != nil || !owned {
+		return nil, err
+	}
+	return t.Transaction.LockService(name, namespace)
+}
+
+// RLockService 共享锁定服务，其他租户的服务视为不存在
+func (t *tenantTransaction) RLockService(name string, namespace string) (*model.Service, error) {
+	if owned, err := t.tenant.visibleNamespace(namespace); err != nil || !owned {
+		return nil, err
+	}
+	return t.Transaction.RLockService(name, namespace)
+}
+
+func (t *tenantStore) ownNamespace(namespace *model.Namespace) bool {
+	return namespace.Owner == t.tenant
+}
+
+// AddNamespace 保存一个命名空间，Owner 为空时填充为当前租户
+func (t *tenantStore) AddNamespace(namespace *model.Namespace) error {
+	if err := t.claim(&namespace.Owner, "namespace", namespace.Name); err != nil {
+		return err
+	}
+	defer t.invalidateNamespaces()
+	return t.store.AddNamespace(namespace)
+}
+
+// UpdateNamespace 更新命名空间，不能更新其他租户的命名空间
+func (t *tenantStore) UpdateNamespace(namespace *model.Namespace) error {
+	if err := t.guardNamespace(namespace.Name); err != nil {
+		return err
+	}
+	if err := t.claim(&namespace.Owner, "namespace", namespace.Name); err != nil {
+		return err
+	}
+	defer t.invalidateNamespaces()
+	return t.store.UpdateNamespace(namespace)
+}
+
+// UpdateNamespaceToken 更新命名空间的 token
+func (t *tenantStore) UpdateNamespaceToken(name string, token string) error {
+	if err := t.guardNamespace(name); err != nil {
+		return err
+	}
+	return t.store.UpdateNamespaceToken(name, token)
+}
+
+// GetNamespace 查询命名空间，其他租户的命名空间返回 nil
+func (t *tenantStore) GetNamespace(name string) (*model.Namespace, error) {
+	ns, err := t.store.GetNamespace(name)
+	if err != nil || ns == nil || !t.ownNamespace(ns) {
+		return nil, err
+	}
+	return ns, nil
+}
+
+// GetNamespaces 查询当前租户的命名空间
+func (t *tenantStore) GetNamespaces(filter map[string][]string, offset int, limit int) (
+	[]*model.Namespace, uint32, error) {
+	if offset < 0 {
+		offset = 0
+	}
+	if limit < 0 {
+		limit = 0
+	}
+	items, _, err := t.store.GetNamespaces(filter, 0, 0)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, items := pageOf(items, t.ownNamespace, uint32(offset), uint32(limit))
+	return items, total, nil
+}
+
+// GetMoreNamespaces 增量查询当前租户的命名空间
+func (t *tenantStore) GetMoreNamespaces(mtime time.Time) ([]*model.Namespace, error) {
+	items, err := t.store.GetMoreNamespaces(mtime)
+	if err != nil {
+		return nil, err
+	}
+	return keep(items, t.ownNamespace), nil
+}
+
+// checkAddService 新增服务时命名空间需要属于当前租户，别名指向的服务同样需要属于当前租户
+func (t *tenantStore) checkAddService(service *model.Service) error {
+	if err := t.checkNamespace(service.Namespace); err != nil {
+		return err
+	}
+	if service.Reference != "" {
+		if err := t.checkService(service.Reference); err != nil {
+			return err
+		}
+	}
+	t.fillOwner(&service.Owner)
+	return nil
+}
+
+// checkUpdateService 更新服务时，已有的服务以及更新后的命名空间都需要属于当前租户
+func (t *tenantStore) checkUpdateService(service *model.Service) error {
+	if err := t.guardService(service.ID); err != nil {
+		return err
+	}
+	if err := t.checkNamespace(service.Namespace); err != nil {
+		return err
+	}
+	if service.Reference != "" {
+		return t.checkService(service.Reference)
+	}
+	return nil
+}
+
+// checkDeleteService 删除服务时，服务以及所在的命名空间不能属于其他租户
+func (t *tenantStore) checkDeleteService(id, namespace string) error {
+	if err := t.guardNamespace(namespace); err != nil {
+		return err
+	}
+	if id == "" {
+		// 按照名称删除，命名空间已经校验过
+		return nil
+	}
+	return t.guardService(id)
+}
+
+// AddService 保存一个服务
+func (t *tenantStore) AddService(service *model.Service) error {
+	if err := t.checkAddService(service); err != nil {
+		return err
+	}
+	return t.store.AddService(service)
+}
+
+// AddServiceTx 在事务中保存一个服务
+func (t *tenantStore) AddServiceTx(tx Tx, service *model.Service) error {
+	if err := t.checkAddService(service); err != nil {
+		return err
+	}
+	return NewNamingTxStore(t.store).AddServiceTx(tx, service)
+}
+
+// DeleteService 删除服务
+func (t *tenantStore) DeleteService(id string, serviceName string, namespaceName string) error {
+	if err := t.checkDeleteService(id, namespaceName); err != nil {
+		return err
+	}
+	return t.store.DeleteService(id, serviceName, namespaceName)
+}
+
+// DeleteServiceTx 在事务中删除服务
+func (t *tenantStore) DeleteServiceTx(tx Tx, id string, serviceName string, namespaceName string) error {
+	if err := t.checkDeleteService(id, namespaceName); err != nil {
+		return err
+	}
+	return NewNamingTxStore(t.store).DeleteServiceTx(tx, id, serviceName, namespaceName)
+}
+
+// DeleteServiceAlias 删除服务别名
+func (t *tenantStore) DeleteServiceAlias(name string, namespace string) error {
+	if err := t.guardNamespace(namespace); err != nil {
+		return err
+	}
+	return t.store.DeleteServiceAlias(name, namespace)
+}
+
+// DeleteServiceAliasTx 在事务中删除服务别名
+func (t *tenantStore) DeleteServiceAliasTx(tx Tx, name string, namespace string) error {
+	if err := t.guardNamespace(namespace); err != nil {
+		return err
+	}
+	return NewNamingTxStore(t.store).DeleteServiceAliasTx(tx, name, namespace)
+}
+
+// UpdateServiceAlias 修改服务别名
+func (t *tenantStore) UpdateServiceAlias(alias *model.Service, needUpdateOwner bool) error {
+	if err := t.checkUpdateService(alias); err != nil {
+		return err
+	}
+	return t.store.UpdateServiceAlias(alias, needUpdateOwner)
+}
+
+// UpdateServiceAliasTx 在事务中修改服务别名
+func (t *tenantStore) UpdateServiceAliasTx(tx Tx, alias *model.Service, needUpdateOwner bool) error {
+	if err := t.checkUpdateService(alias); err != nil {
+		return err
+	}
+	return NewNamingTxStore(t.store).UpdateServiceAliasTx(tx, alias, needUpdateOwner)
+}
+
+// UpdateService 更新服务
+func (t *tenantStore) UpdateService(service *model.Service, needUpdateOwner bool) error {
+	if err := t.checkUpdateService(service); err != nil {
+		return err
+	}
+	return t.store.UpdateService(service, needUpdateOwner)
+}
+
+// UpdateServiceTx 在事务中更新服务
+func (t *tenantStore) UpdateServiceTx(tx Tx, service *model.Service, needUpdateOwner bool) error {
+	if err := t.checkUpdateService(service); err != nil {
+		return err
+	}
+	return NewNamingTxStore(t.store).UpdateServiceTx(tx, service, needUpdateOwner)
+}
+
+// UpdateServiceToken 更新服务 token
+func (t *tenantStore) UpdateServiceToken(serviceID string, token string, revision string) error {
+	if err := t.guardService(serviceID); err != nil {
+		return err
+	}
+	return t.store.UpdateServiceToken(serviceID, token, revision)
+}
+
+// UpdateServiceTokenTx 在事务中更新服务 token
+func (t *tenantStore) UpdateServiceTokenTx(tx Tx, serviceID string, token string, revision string) error {
+	if err := t.guardService(serviceID); err != nil {
+		return err
+	}
+	return NewNamingTxStore(t.store).UpdateServiceTokenTx(tx, serviceID, token, revision)
+}
+
+// GetSourceServiceToken 获取源服务的 token 信息，其他租户的服务返回 nil
+func (t *tenantStore) GetSourceServiceToken(name string, namespace string) (*model.Service, error) {
+	if owned, err := t.visibleNamespace(namespace); err != nil || !owned {
+		return nil, err
+	}
+	return t.store.GetSourceServiceToken(name, namespace)
+}
+
+// GetService 根据服务名和命名空间查询服务，其他租户的服务返回 nil
+func (t *tenantStore) GetService(name string, namespace string) (*model.Service, error) {
+	if owned, err := t.visibleNamespace(namespace); err != nil || !owned {
+		return nil, err
+	}
+	return t.store.GetService(name, namespace)
+}
+
+// GetServiceByID 根据服务 ID 查询服务，其他租户的服务返回 nil
+func (t *tenantStore) GetServiceByID(id string) (*model.Service, error) {
+	svc, err := t.store.GetServiceByID(id)
+	if err != nil || svc == nil {
+		return nil, err
+	}
+	if owned, err := t.visibleNamespace(svc.Namespace); err != nil || !owned {
+		return nil, err
+	}
+	return svc, nil
+}
+
+// GetServices 查询当前租户的服务
+func (t *tenantStore) GetServices(serviceFilters map[string]string, serviceMetas map[string]string,
+	instanceFilters *model.InstanceArgs, offset uint32, limit uint32) (uint32, []*model.Service, error) {
+	owned, err := t.namespaces()
+	if err != nil {
+		return 0, nil, err
+	}
+	_, items, err := t.store.GetServices(serviceFilters, serviceMetas, instanceFilters, 0, 0)
+	if err != nil {
+		return 0, nil, err
+	}
+	total, items := pageOf(items, func(svc *model.Service) bool {
+		return contains(owned, svc.Namespace)
+	}, offset, limit)
+	return total, items, nil
+}
+
+// GetServicesCount 当前租户的服务总数，按照命名空间分别统计
+func (t *tenantStore) GetServicesCount() (uint32, error) {
+	owned, err := t.namespaces()
+	if err != nil {
+		return 0, err
+	}
+	var count uint32
+	for name := range owned {
+		total, _, err := t.store.GetServices(map[string]string{"namespace": name}, nil, nil, 0, 1)
+		if err != nil {
+			return 0, err
+		}
+		count += total
+	}
+	return count, nil
+}
+
+// GetMoreServices 增量查询当前租户的服务
+func (t *tenantStore) GetMoreServices(mtime time.Time, firstUpdate bool, disableBusiness bool, needMeta bool) (
+	map[string]*model.Service, error) {
+	owned, err := t.namespaces()
+	if err != nil {
+		return nil, err
+	}
+	items, err := t.store.GetMoreServices(mtime, firstUpdate, disableBusiness, needMeta)
+	if err != nil {
+		return nil, err
+	}
+	return keepMap(items, func(_ string, svc *model.Service) bool {
+		return contains(owned, svc.Namespace)
+	}), nil
+}
+
+// GetServiceAliases 查询当前租户的服务别名，别名所在的命名空间需要属于当前租户
+func (t *tenantStore) GetServiceAliases(filter map[string]string, offset uint32, limit uint32) (
+	uint32, []*model.ServiceAlias, error) {
+	return pageByNamespace(t, filter, "alias_namespace", offset, limit, aliasOrder,
+		func(filter map[string]string, offset, limit uint32) (uint32, []*model.ServiceAlias, error) {
+			return t.store.GetServiceAliases(filter, offset, limit)
+		})
+}
+
+// GetSystemServices 查询当前租户的系统服务
+func (t *tenantStore) GetSystemServices() ([]*model.Service, error) {
+	owned, err := t.namespaces()
+	if err != nil {
+		return nil, err
+	}
+	items, err := t.store.GetSystemServices()
+	if err != nil {
+		return nil, err
+	}
+	return keep(items, func(svc *model.Service) bool {
+		return contains(owned, svc.Namespace)
+	}), nil
+}
+
+// GetServicesBatch 批量查询服务，其他租户的服务不会返回
+func (t *tenantStore) GetServicesBatch(services []*model.Service) ([]*model.Service, error) {
+	owned, err := t.namespaces()
+	if err != nil {
+		return nil, err
+	}
+	inTenant := func(svc *model.Service) bool {
+		return contains(owned, svc.Namespace)
+	}
+	services = keep(services, inTenant)
+	if len(services) == 0 {
+		return []*model.Service{}, nil
+	}
+	items, err := t.store.GetServicesBatch(services)
+	if err != nil {
+		return nil, err
+	}
+	return keep(items, inTenant), nil
+}
+
+// checkInstances 批量新增或者更新实例时逐个检查实例
+func (t *tenantStore) checkInstances(instances []*model.Instance) error {
+	for _, instance := range instances {
+		if err := t.checkInstance(instance); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func metadataInstanceIDs(requests []*model.InstanceMetadataRequest) []string {
+	ret := make([]string, 0, len(requests))
+	for _, req := range requests {
+		ret = append(ret, req.InstanceID)
+	}
+	return ret
+}
+
+// visibleInstances 过滤掉其他租户的实例 ID
+func (t *tenantStore) visibleInstances(ids map[string]bool) (map[string]bool, error) {
+	briefs, err := t.GetInstancesBrief(ids)
+	if err != nil {
+		return nil, err
+	}
+	return keepMap(ids, func(id string, _ bool) bool {
+		_, ok := briefs[id]
+		return ok
+	}), nil
+}
+
+// AddInstance 新增一个实例
+func (t *tenantStore) AddInstance(instance *model.Instance) error {
+	if err := t.checkInstance(instance); err != nil {
+		return err
+	}
+	return t.store.AddInstance(instance)
+}
+
+// AddInstanceTx 在事务中新增一个实例
+func (t *tenantStore) AddInstanceTx(tx Tx, instance *model.Instance) error {
+	if err := t.checkInstance(instance); err != nil {
+		return err
+	}
+	return NewNamingTxStore(t.store).AddInstanceTx(tx, instance)
+}
+
+// BatchAddInstances 批量增加实例
+func (t *tenantStore) BatchAddInstances(instances []*model.Instance) error {
+	if err := t.checkInstances(instances); err != nil {
+		return err
+	}
+	return t.store.BatchAddInstances(instances)
+}
+
+// BatchAddInstancesTx 在事务中批量增加实例
+func (t *tenantStore) BatchAddInstancesTx(tx Tx, instances []*model.Instance) error {
+	if err := t.checkInstances(instances); err != nil {
+		return err
+	}
+	return NewNamingTxStore(t.store).BatchAddInstancesTx(tx, instances)
+}
+
+// UpdateInstance 更新实例
+func (t *tenantStore) UpdateInstance(instance *model.Instance) error {
+	if err := t.checkInstance(instance); err != nil {
+		return err
+	}
+	return t.store.UpdateInstance(instance)
+}
+
+// UpdateInstanceTx 在事务中更新实例
+func (t *tenantStore) UpdateInstanceTx(tx Tx, instance *model.Instance) error {
+	if err := t.checkInstance(instance); err != nil {
+		return err
+	}
+	return NewNamingTxStore(t.store).UpdateInstanceTx(tx, instance)
+}
+
+// DeleteInstance 逻辑删除实例
+func (t *tenantStore) DeleteInstance(instanceID string) error {
+	if err := t.guardInstances(instanceID); err != nil {
+		return err
+	}
+	return t.store.DeleteInstance(instanceID)
+}
+
+// DeleteInstanceTx 在事务中逻辑删除实例
+func (t *tenantStore) DeleteInstanceTx(tx Tx, instanceID string) error {
+	if err := t.guardInstances(instanceID); err != nil {
+		return err
+	}
+	return NewNamingTxStore(t.store).DeleteInstanceTx(tx, instanceID)
+}
+
+// BatchDeleteInstances 批量逻辑删除实例
+func (t *tenantStore) BatchDeleteInstances(ids []interface{}) error {
+	if err := t.guardInstances(instanceIDs(ids)...); err != nil {
+		return err
+	}
+	return t.store.BatchDeleteInstances(ids)
+}
+
+// BatchDeleteInstancesTx 在事务中批量逻辑删除实例
+func (t *tenantStore) BatchDeleteInstancesTx(tx Tx, ids []interface{}) error {
+	if err := t.guardInstances(instanceIDs(ids)...); err != nil {
+		return err
+	}
+	return NewNamingTxStore(t.store).BatchDeleteInstancesTx(tx, ids)
+}
+
+// CleanInstance 物理删除实例
+func (t *tenantStore) CleanInstance(instanceID string) error {
+	if err := t.guardInstances(instanceID); err != nil {
+		return err
+	}
+	return t.store.CleanInstance(instanceID)
+}
+
+// CleanInstanceTx 在事务中物理删除实例
+func (t *tenantStore) CleanInstanceTx(tx Tx, instanceID string) error {
+	if err := t.guardInstances(instanceID); err != nil {
+		return err
+	}
+	return NewNamingTxStore(t.store).CleanInstanceTx(tx, instanceID)
+}
+
+// BatchGetInstanceIsolate 查询实例的隔离状态，其他租户的实例不会返回
+func (t *tenantStore) BatchGetInstanceIsolate(ids map[string]bool) (map[string]bool, error) {
+	visible, err := t.visibleInstances(ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(visible) == 0 {
+		return map[string]bool{}, nil
+	}
+	return t.store.BatchGetInstanceIsolate(visible)
+}
+
+// GetInstancesBrief 查询实例的简要信息，其他租户的实例不会返回
+func (t *tenantStore) GetInstancesBrief(ids map[string]bool) (map[string]*model.Instance, error) {
+	owned, err := t.namespaces()
+	if err != nil {
+		return nil, err
+	}
+	items, err := t.store.GetInstancesBrief(ids)
+	if err != nil {
+		return nil, err
+	}
+	return keepMap(items, func(_ string, ins *model.Instance) bool {
+		return contains(owned, instanceNamespace(ins))
+	}), nil
+}
+
+// GetInstance 查询实例，其他租户的实例返回 nil
+func (t *tenantStore) GetInstance(instanceID string) (*model.Instance, error) {
+	ins, err := t.store.GetInstance(instanceID)
+	if err != nil || ins == nil {
+		return nil, err
+	}
+	if owned, err := t.visibleNamespace(instanceNamespace(ins)); err != nil || !owned {
+		return nil, err
+	}
+	return ins, nil
+}
+
+// countInstances 统计当前租户的有效实例数
+func (t *tenantStore) countInstances(tx Tx) (uint32, error) {
+	owned, err := t.namespaces()
+	if err != nil {
+		return 0, err
+	}
+	items, err := t.store.GetMoreInstances(tx, time.Time{}, true, false, nil)
+	if err != nil {
+		return 0, err
+	}
+	var count uint32
+	for _, ins := range items {
+		if ins.Valid && contains(owned, instanceNamespace(ins)) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// GetInstancesCount 当前租户的实例总数
+func (t *tenantStore) GetInstancesCount() (uint32, error) {
+	return t.countInstances(nil)
+}
+
+// GetInstancesCountTx 在事务中查询当前租户的实例总数
+func (t *tenantStore) GetInstancesCountTx(tx Tx) (uint32, error) {
+	return t.countInstances(tx)
+}
+
+// GetInstancesMainByService 根据服务和 host 查询实例，其他租户的服务返回空
+func (t *tenantStore) GetInstancesMainByService(serviceID string, host string) ([]*model.Instance, error) {
+	if owned, err := t.visibleService(serviceID); err != nil || !owned {
+		return nil, err
+	}
+	return t.store.GetInstancesMainByService(serviceID, host)
+}
+
+// GetExpandInstances 查询当前租户的实例
+func (t *tenantStore) GetExpandInstances(filter map[string]string, metaFilter map[string]string,
+	offset uint32, limit uint32) (uint32, []*model.Instance, error) {
+	return pageByNamespace(t, filter, "namespace", offset, limit, instanceOrder,
+		func(filter map[string]string, offset, limit uint32) (uint32, []*model.Instance, error) {
+			return t.store.GetExpandInstances(filter, metaFilter, offset, limit)
+		})
+}
+
+// GetMoreInstances 增量查询当前租户的实例
+func (t *tenantStore) GetMoreInstances(tx Tx, mtime time.Time, firstUpdate bool, needMeta bool,
+	serviceID []string) (map[string]*model.Instance, error) {
+	owned, err := t.namespaces()
+	if err != nil {
+		return nil, err
+	}
+	items, err := t.store.GetMoreInstances(tx, mtime, firstUpdate, needMeta, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	return keepMap(items, func(_ string, ins *model.Instance) bool {
+		return contains(owned, instanceNamespace(ins))
+	}), nil
+}
+
+// SetInstanceHealthStatus 设置实例的健康状态
+func (t *tenantStore) SetInstanceHealthStatus(instanceID string, flag int, revision string) error {
+	if err := t.guardInstances(instanceID); err != nil {
+		return err
+	}
+	return t.store.SetInstanceHealthStatus(instanceID, flag, revision)
+}
+
+// SetInstanceHealthStatusTx 在事务中设置实例的健康状态
+func (t *tenantStore) SetInstanceHealthStatusTx(tx Tx, instanceID string, flag int, revision string) error {
+	if err := t.guardInstances(instanceID); err != nil {
+		return err
+	}
+	return NewNamingTxStore(t.store).SetInstanceHealthStatusTx(tx, instanceID, flag, revision)
+}
+
+// BatchSetInstanceHealthStatus 批量设置实例的健康状态
+func (t *tenantStore) BatchSetInstanceHealthStatus(ids []interface{}, healthy int, revision string) error {
+	if err := t.guardInstances(instanceIDs(ids)...); err != nil {
+		return err
+	}
+	return t.store.BatchSetInstanceHealthStatus(ids, healthy, revision)
+}
+
+// BatchSetInstanceHealthStatusTx 在事务中批量设置实例的健康状态
+func (t *tenantStore) BatchSetInstanceHealthStatusTx(tx Tx, ids []interface{}, healthy int, revision string) error {
+	if err := t.guardInstances(instanceIDs(ids)...); err != nil {
+		return err
+	}
+	return NewNamingTxStore(t.store).BatchSetInstanceHealthStatusTx(tx, ids, healthy, revision)
+}
+
+// BatchSetInstanceIsolate 批量设置实例的隔离状态
+func (t *tenantStore) BatchSetInstanceIsolate(ids []interface{}, isolate int, revision string) error {
+	if err := t.guardInstances(instanceIDs(ids)...); err != nil {
+		return err
+	}
+	return t.store.BatchSetInstanceIsolate(ids, isolate, revision)
+}
+
+// BatchSetInstanceIsolateTx 在事务中批量设置实例的隔离状态
+func (t *tenantStore) BatchSetInstanceIsolateTx(tx Tx, ids []interface{}, isolate int, revision string) error {
+	if err := t.guardInstances(instanceIDs(ids)...); err != nil {
+		return err
+	}
+	return NewNamingTxStore(t.store).BatchSetInstanceIsolateTx(tx, ids, isolate, revision)
+}
+
+// BatchAppendInstanceMetadata 追加实例的元数据
+func (t *tenantStore) BatchAppendInstanceMetadata(requests []*model.InstanceMetadataRequest) error {
+	if err := t.guardInstances(metadataInstanceIDs(requests)...); err != nil {
+		return err
+	}
+	return t.store.BatchAppendInstanceMetadata(requests)
+}
+
+// BatchAppendInstanceMetadataTx 在事务中追加实例的元数据
+func (t *tenantStore) BatchAppendInstanceMetadataTx(tx Tx, requests []*model.InstanceMetadataRequest) error {
+	if err := t.guardInstances(metadataInstanceIDs(requests)...); err != nil {
+		return err
+	}
+	return NewNamingTxStore(t.store).BatchAppendInstanceMetadataTx(tx, requests)
+}
+
+// BatchRemoveInstanceMetadata 删除实例的元数据
+func (t *tenantStore) BatchRemoveInstanceMetadata(requests []*model.InstanceMetadataRequest) error {
+	if err := t.guardInstances(metadataInstanceIDs(requests)...); err != nil {
+		return err
+	}
+	return t.store.BatchRemoveInstanceMetadata(requests)
+}
+
+// BatchRemoveInstanceMetadataTx 在事务中删除实例的元数据
+func (t *tenantStore) BatchRemoveInstanceMetadataTx(tx Tx, requests []*model.InstanceMetadataRequest) error {
+	if err := t.guardInstances(metadataInstanceIDs(requests)...); err != nil {
+		return err
+	}
+	return NewNamingTxStore(t.store).BatchRemoveInstanceMetadataTx(tx, requests)
+}
+
+// CreateRoutingConfig 新增一个路由配置
+func (t *tenantStore) CreateRoutingConfig(conf *model.RoutingConfig) error {
+	if err := t.checkScope("routing config", conf.NamespaceName, conf.ID); err != nil {
+		return err
+	}
+	return t.store.CreateRoutingConfig(conf)
+}
+
+// CreateRoutingConfigTx 在事务中新增一个路由配置
+func (t *tenantStore) CreateRoutingConfigTx(tx Tx, conf *model.RoutingConfig) error {
+	if err := t.checkScope("routing config", conf.NamespaceName, conf.ID); err != nil {
+		return err
+	}
+	return NewNamingTxStore(t.store).CreateRoutingConfigTx(tx, conf)
+}
+
+// UpdateRoutingConfig 更新一个路由配置
+func (t *tenantStore) UpdateRoutingConfig(conf *model.RoutingConfig) error {
+	if err := t.checkScope("routing config", conf.NamespaceName, conf.ID); err != nil {
+		return err
+	}
+	return t.store.UpdateRoutingConfig(conf)
+}
+
+// UpdateRoutingConfigTx 在事务中更新一个路由配置
+func (t *tenantStore) UpdateRoutingConfigTx(tx Tx, conf *model.RoutingConfig) error {
+	if err := t.checkScope("routing config", conf.NamespaceName, conf.ID); err != nil {
+		return err
+	}
+	return NewNamingTxStore(t.store).UpdateRoutingConfigTx(tx, conf)
+}
+
+// DeleteRoutingConfig 删除一个路由配置
+func (t *tenantStore) DeleteRoutingConfig(serviceID string) error {
+	if err := t.guardService(serviceID); err != nil {
+		return err
+	}
+	return t.store.DeleteRoutingConfig(serviceID)
+}
+
+// DeleteRoutingConfigTx 在事务中删除一个路由配置
+func (t *tenantStore) DeleteRoutingConfigTx(tx Tx, serviceID string) error {
+	if err := t.guardService(serviceID); err != nil {
+		return err
+	}
+	return t.store.DeleteRoutingConfigTx(tx, serviceID)
+}
+
+// GetRoutingConfigsForCache 增量查询当前租户的路由配置
+func (t *tenantStore) GetRoutingConfigsForCache(mtime time.Time, firstUpdate bool) ([]*model.RoutingConfig, error) {
+	scope, err := t.scope()
+	if err != nil {
+		return nil, err
+	}
+	items, err := t.store.GetRoutingConfigsForCache(mtime, firstUpdate)
+	if err != nil {
+		return nil, err
+	}
+	return keep(items, func(conf *model.RoutingConfig) bool {
+		return scope.owns(conf.NamespaceName, conf.ID)
+	}), nil
+}
+
+// GetRoutingConfigWithService 根据服务名和命名空间查询路由配置，其他租户的路由配置返回 nil
+func (t *tenantStore) GetRoutingConfigWithService(name string, namespace string) (*model.RoutingConfig, error) {
+	if owned, err := t.visibleNamespace(namespace); err != nil || !owned {
+		return nil, err
+	}
+	return t.store.GetRoutingConfigWithService(name, namespace)
+}
+
+// GetRoutingConfigWithID 根据服务 ID 查询路由配置，其他租户的路由配置返回 nil
+func (t *tenantStore) GetRoutingConfigWithID(id string) (*model.RoutingConfig, error) {
+	if owned, err := t.visibleService(id); err != nil || !owned {
+		return nil, err
+	}
+	return t.store.GetRoutingConfigWithID(id)
+}
+
+// GetRoutingConfigs 查询当前租户的路由配置
+func (t *tenantStore) GetRoutingConfigs(filter map[string]string, offset uint32, limit uint32) (
+	uint32, []*model.RoutingConfig, error) {
+	scope, err := t.scope()
+	if err != nil {
+		return 0, nil, err
+	}
+	_, items, err := t.store.GetRoutingConfigs(filter, 0, 0)
+	if err != nil {
+		return 0, nil, err
+	}
+	total, items := pageOf(items, func(conf *model.RoutingConfig) bool {
+		return scope.owns(conf.NamespaceName, conf.ID)
+	}, offset, limit)
+	return total, items, nil
+}
+
+// GetL5Extend 查询服务的 L5 扩展信息，其他租户的服务返回 nil
+func (t *tenantStore) GetL5Extend(serviceID string) (map[string]interface{}, error) {
+	if owned, err := t.visibleService(serviceID); err != nil || !owned {
+		return nil, err
+	}
+	return t.store.GetL5Extend(serviceID)
+}
+
+// SetL5Extend 设置服务的 L5 扩展信息
+func (t *tenantStore) SetL5Extend(serviceID string, meta map[string]interface{}) (map[string]interface{}, error) {
+	if err := t.checkService(serviceID); err != nil {
+		return nil, err
+	}
+	return t.store.SetL5Extend(serviceID, meta)
+}
+
+// GenNextL5Sid 生成 L5 sid，sid 不属于任何租户
+func (t *tenantStore) GenNextL5Sid(layoutID uint32) (string, error) {
+	return t.store.GenNextL5Sid(layoutID)
+}
+
+// GetMoreL5Extend 增量查询当前租户服务的 L5 扩展信息
+func (t *tenantStore) GetMoreL5Extend(mtime time.Time) (map[string]map[string]interface{}, error) {
+	scope, err := t.scope()
+	if err != nil {
+		return nil, err
+	}
+	items, err := t.store.GetMoreL5Extend(mtime)
+	if err != nil {
+		return nil, err
+	}
+	return keepMap(items, func(serviceID string, _ map[string]interface{}) bool {
+		return contains(scope.services, serviceID)
+	}), nil
+}
+
+// GetMoreL5Routes 查询 L5 路由，L5 路由表不属于任何租户
+func (t *tenantStore) GetMoreL5Routes(flow uint32) ([]*model.Route, error) {
+	return t.store.GetMoreL5Routes(flow)
+}
+
+// GetMoreL5Policies 查询 L5 策略，L5 策略表不属于任何租户
+func (t *tenantStore) GetMoreL5Policies(flow uint32) ([]*model.Policy, error) {
+	return t.store.GetMoreL5Policies(flow)
+}
+
+// GetMoreL5Sections 查询 L5 号段，L5 号段表不属于任何租户
+func (t *tenantStore) GetMoreL5Sections(flow uint32) ([]*model.Section, error) {
+	return t.store.GetMoreL5Sections(flow)
+}
+
+// GetMoreL5IPConfigs 查询 L5 IP 配置，L5 IP 配置表不属于任何租户
+func (t *tenantStore) GetMoreL5IPConfigs(flow uint32) ([]*model.IPConfig, error) {
+	return t.store.GetMoreL5IPConfigs(flow)
+}
+
+// guardRateLimit 已有的限流规则不能属于其他租户，不存在时交给底层存储处理
+func (t *tenantStore) guardRateLimit(id string) error {
+	if id == "" {
+		return nil
+	}
+	saved, err := t.store.GetRateLimitWithID(id)
+	if err != nil || saved == nil {
+		return err
+	}
+	scope, err := t.scope()
+	if err != nil {
+		return err
+	}
+	if !scope.owns(saved.NamespaceName, saved.ServiceID) {
+		return t.mismatch("rate limit", id)
+	}
+	return nil
+}
+
+// checkRateLimit 已有的限流规则以及规则所在的命名空间、服务都需要属于当前租户，只携带 ID 时只检查已有的规则
+func (t *tenantStore) checkRateLimit(limit *model.RateLimit) error {
+	if err := t.guardRateLimit(limit.ID); err != nil {
+		return err
+	}
+	if limit.ID != "" && limit.NamespaceName == "" && limit.ServiceID == "" {
+		return nil
+	}
+	return t.checkScope("rate limit", limit.NamespaceName, limit.ServiceID)
+}
+
+// CreateRateLimit 新增限流规则
+func (t *tenantStore) CreateRateLimit(limiting *model.RateLimit) error {
+	if err := t.checkRateLimit(limiting); err != nil {
+		return err
+	}
+	return t.store.CreateRateLimit(limiting)
+}
+
+// CreateRateLimitTx 在事务中新增限流规则
+func (t *tenantStore) CreateRateLimitTx(tx Tx, limiting *model.RateLimit) error {
+	if err := t.checkRateLimit(limiting); err != nil {
+		return err
+	}
+	return NewNamingTxStore(t.store).CreateRateLimitTx(tx, limiting)
+}
+
+// UpdateRateLimit 更新限流规则
+func (t *tenantStore) UpdateRateLimit(limiting *model.RateLimit) error {
+	if err := t.checkRateLimit(limiting); err != nil {
+		return err
+	}
+	return t.store.UpdateRateLimit(limiting)
+}
+
+// UpdateRateLimitTx 在事务中更新限流规则
+func (t *tenantStore) UpdateRateLimitTx(tx Tx, limiting *model.RateLimit) error {
+	if err := t.checkRateLimit(limiting); err != nil {
+		return err
+	}
+	return NewNamingTxStore(t.store).UpdateRateLimitTx(tx, limiting)
+}
+
+// EnableRateLimit 启用限流规则
+func (t *tenantStore) EnableRateLimit(limit *model.RateLimit) error {
+	if err := t.checkRateLimit(limit); err != nil {
+		return err
+	}
+	return t.store.EnableRateLimit(limit)
+}
+
+// EnableRateLimitTx 在事务中启用限流规则
+func (t *tenantStore) EnableRateLimitTx(tx Tx, limit *model.RateLimit) error {
+	if err := t.checkRateLimit(limit); err != nil {
+		return err
+	}
+	return NewNamingTxStore(t.store).EnableRateLimitTx(tx, limit)
+}
+
+// DeleteRateLimit 删除限流规则
+func (t *tenantStore) DeleteRateLimit(limiting *model.RateLimit) error {
+	if err := t.checkRateLimit(limiting); err != nil {
+		return err
+	}
+	return t.store.DeleteRateLimit(limiting)
+}
+
+// DeleteRateLimitTx 在事务中删除限流规则
+func (t *tenantStore) DeleteRateLimitTx(tx Tx, limiting *model.RateLimit) error {
+	if err := t.checkRateLimit(limiting); err != nil {
+		return err
+	}
+	return NewNamingTxStore(t.store).DeleteRateLimitTx(tx, limiting)
+}
+
+// GetExtendRateLimits 查询当前租户的限流规则
+func (t *tenantStore) GetExtendRateLimits(query map[string]string, offset uint32, limit uint32) (
+	uint32, []*model.RateLimit, error) {
+	scope, err := t.scope()
+	if err != nil {
+		return 0, nil, err
+	}
+	_, items, err := t.store.GetExtendRateLimits(query, 0, 0)
+	if err != nil {
+		return 0, nil, err
+	}
+	total, items := pageOf(items, func(rule *model.RateLimit) bool {
+		return scope.owns(rule.NamespaceName, rule.ServiceID)
+	}, offset, limit)
+	return total, items, nil
+}
+
+// GetRateLimitWithID 根据 ID 查询限流规则，其他租户的限流规则返回 nil
+func (t *tenantStore) GetRateLimitWithID(id string) (*model.RateLimit, error) {
+	rule, err := t.store.GetRateLimitWithID(id)
+	if err != nil || rule == nil {
+		return nil, err
+	}
+	scope, err := t.scope()
+	if err != nil || !scope.owns(rule.NamespaceName, rule.ServiceID) {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// GetRateLimitsForCache 增量查询当前租户的限流规则
+func (t *tenantStore) GetRateLimitsForCache(mtime time.Time, firstUpdate bool) ([]*model.RateLimit, error) {
+	scope, err := t.scope()
+	if err != nil {
+		return nil, err
+	}
+	items, err := t.store.GetRateLimitsForCache(mtime, firstUpdate)
+	if err != nil {
+		return nil, err
+	}
+	return keep(items, func(rule *model.RateLimit) bool {
+		return scope.owns(rule.NamespaceName, rule.ServiceID)
+	}), nil
+}
+
+// checkRule 更新规则时已有的规则不能属于其他租户，携带命名空间时命名空间需要属于当前租户
+func (t *tenantStore) checkRule(guard func(id string) error, id, namespace string) error {
+	if err := guard(id); err != nil {
+		return err
+	}
+	if namespace == "" {
+		return nil
+	}
+	return t.checkNamespace(namespace)
+}
+
+// circuitBreakerNamespace 查询熔断规则所在的命名空间，存储没有提供按照 ID 查询的方法，从全量数据中查找
+func (t *tenantStore) circuitBreakerNamespace(id string) (string, bool, error) {
+	items, err := t.store.GetCircuitBreakerRulesForCache(time.Time{}, false)
+	if err != nil {
+		return "", false, err
+	}
+	for _, rule := range items {
+		if rule.ID == id {
+			return rule.Namespace, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// guardCircuitBreakerRule 已有的熔断规则不能属于其他租户，不存在时交给底层存储处理
+func (t *tenantStore) guardCircuitBreakerRule(id string) error {
+	if id == "" {
+		return nil
+	}
+	namespace, exist, err := t.circuitBreakerNamespace(id)
+	if err != nil || !exist {
+		return err
+	}
+	return t.guard(namespace, "circuit breaker rule", id)
+}
+
+// CreateCircuitBreakerRule 新增熔断规则
+func (t *tenantStore) CreateCircuitBreakerRule(cbRule *model.CircuitBreakerRule) error {
+	if err := t.checkScope("circuit breaker rule", cbRule.Namespace, ""); err != nil {
+		return err
+	}
+	return t.store.CreateCircuitBreakerRule(cbRule)
+}
+
+// CreateCircuitBreakerRuleTx 在事务中新增熔断规则
+func (t *tenantStore) CreateCircuitBreakerRuleTx(tx Tx, cbRule *model.CircuitBreakerRule) error {
+	if err := t.checkScope("circuit breaker rule", cbRule.Namespace, ""); err != nil {
+		return err
+	}
+	return NewNamingTxStore(t.store).CreateCircuitBreakerRuleTx(tx, cbRule)
+}
+
+// UpdateCircuitBreakerRule 更新熔断规则
+func (t *tenantStore) UpdateCircuitBreakerRule(cbRule *model.CircuitBreakerRule) error {
+	if err := t.checkRule(t.guardCircuitBreakerRule, cbRule.ID, cbRule.Namespace); err != nil {
+		return err
+	}
+	return t.store.UpdateCircuitBreakerRule(cbRule)
+}
+
+// UpdateCircuitBreakerRuleTx 在事务中更新熔断规则
+func (t *tenantStore) UpdateCircuitBreakerRuleTx(tx Tx, cbRule *model.CircuitBreakerRule) error {
+	if err := t.checkRule(t.guardCircuitBreakerRule, cbRule.ID, cbRule.Namespace); err != nil {
+		return err
+	}
+	return NewNamingTxStore(t.store).UpdateCircuitBreakerRuleTx(tx, cbRule)
+}
+
+// DeleteCircuitBreakerRule 删除熔断规则
+func (t *tenantStore) DeleteCircuitBreakerRule(id string) error {
+	if err := t.guardCircuitBreakerRule(id); err != nil {
+		return err
+	}
+	return t.store.DeleteCircuitBreakerRule(id)
+}
+
+// DeleteCircuitBreakerRuleTx 在事务中删除熔断规则
+func (t *tenantStore) DeleteCircuitBreakerRuleTx(tx Tx, id string) error {
+	if err := t.guardCircuitBreakerRule(id); err != nil {
+		return err
+	}
+	return NewNamingTxStore(t.store).DeleteCircuitBreakerRuleTx(tx, id)
+}
+
+// HasCircuitBreakerRule 当前租户是否存在该熔断规则
+func (t *tenantStore) HasCircuitBreakerRule(id string) (bool, error) {
+	exist, err := t.store.HasCircuitBreakerRule(id)
+	if err != nil || !exist {
+		return false, err
+	}
+	namespace, _, err := t.circuitBreakerNamespace(id)
+	if err != nil {
+		return false, err
+	}
+	return t.visibleNamespace(namespace)
+}
+
+// HasCircuitBreakerRuleByName 当前租户的命名空间下是否存在同名的熔断规则
+func (t *tenantStore) HasCircuitBreakerRuleByName(name string, namespace string) (bool, error) {
+	if owned, err := t.visibleNamespace(namespace); err != nil || !owned {
+		return false, err
+	}
+	return t.store.HasCircuitBreakerRuleByName(name, namespace)
+}
+
+// HasCircuitBreakerRuleByNameExcludeId 当前租户的命名空间下是否存在除 id 之外的同名熔断规则
+func (t *tenantStore) HasCircuitBreakerRuleByNameExcludeId(name string, namespace string, id string) (bool, error) {
+	if owned, err := t.visibleNamespace(namespace); err != nil || !owned {
+		return false, err
+	}
+	return t.store.HasCircuitBreakerRuleByNameExcludeId(name, namespace, id)
+}
+
+// GetCircuitBreakerRules 查询当前租户的熔断规则
+func (t *tenantStore) GetCircuitBreakerRules(filter map[string]string, offset uint32, limit uint32) (
+	uint32, []*model.CircuitBreakerRule, error) {
+	owned, err := t.namespaces()
+	if err != nil {
+		return 0, nil, err
+	}
+	_, items, err := t.store.GetCircuitBreakerRules(filter, 0, 0)
+	if err != nil {
+		return 0, nil, err
+	}
+	total, items := pageOf(items, func(rule *model.CircuitBreakerRule) bool {
+		return contains(owned, rule.Namespace)
+	}, offset, limit)
+	return total, items, nil
+}
+
+// GetCircuitBreakerRulesForCache 增量查询当前租户的熔断规则
+func (t *tenantStore) GetCircuitBreakerRulesForCache(mtime time.Time, firstUpdate bool) (
+	[]*model.CircuitBreakerRule, error) {
+	owned, err := t.namespaces()
+	if err != nil {
+		return nil, err
+	}
+	items, err := t.store.GetCircuitBreakerRulesForCache(mtime, firstUpdate)
+	if err != nil {
+		return nil, err
+	}
+	return keep(items, func(rule *model.CircuitBreakerRule) bool {
+		return contains(owned, rule.Namespace)
+	}), nil
+}
+
+// EnableCircuitBreakerRule 启用熔断规则
+func (t *tenantStore) EnableCircuitBreakerRule(cbRule *model.CircuitBreakerRule) error {
+	if err := t.checkRule(t.guardCircuitBreakerRule, cbRule.ID, cbRule.Namespace); err != nil {
+		return err
+	}
+	return t.store.EnableCircuitBreakerRule(cbRule)
+}
+
+// EnableCircuitBreakerRuleTx 在事务中启用熔断规则
+func (t *tenantStore) EnableCircuitBreakerRuleTx(tx Tx, cbRule *model.CircuitBreakerRule) error {
+	if err := t.checkRule(t.guardCircuitBreakerRule, cbRule.ID, cbRule.Namespace); err != nil {
+		return err
+	}
+	return NewNamingTxStore(t.store).EnableCircuitBreakerRuleTx(tx, cbRule)
+}
+
+// guardRoutingV2 已有的路由规则不能属于其他租户，不存在时交给底层存储处理
+func (t *tenantStore) guardRoutingV2(id string) error {
+	if id == "" {
+		return nil
+	}
+	saved, err := t.store.GetRoutingConfigV2WithID(id)
+	if err != nil || saved == nil {
+		return err
+	}
+	return t.guard(saved.Namespace, "routing config", id)
+}
+
+// EnableRouting 启用路由规则
+func (t *tenantStore) EnableRouting(conf *model.RouterConfig) error {
+	if err := t.checkRule(t.guardRoutingV2, conf.ID, conf.Namespace); err != nil {
+		return err
+	}
+	return t.store.EnableRouting(conf)
+}
+
+// EnableRoutingTx 在事务中启用路由规则
+func (t *tenantStore) EnableRoutingTx(tx Tx, conf *model.RouterConfig) error {
+	if err := t.checkRule(t.guardRoutingV2, conf.ID, conf.Namespace); err != nil {
+		return err
+	}
+	return NewNamingTxStore(t.store).EnableRoutingTx(tx, conf)
+}
+
+// CreateRoutingConfigV2 新增路由规则
+func (t *tenantStore) CreateRoutingConfigV2(conf *model.RouterConfig) error {
+	if err := t.checkScope("routing config", conf.Namespace, ""); err != nil {
+		return err
+	}
+	return t.store.CreateRoutingConfigV2(conf)
+}
+
+// CreateRoutingConfigV2Tx 在事务中新增路由规则
+func (t *tenantStore) CreateRoutingConfigV2Tx(tx Tx, conf *model.RouterConfig) error {
+	if err := t.checkScope("routing config", conf.Namespace, ""); err != nil {
+		return err
+	}
+	return t.store.CreateRoutingConfigV2Tx(tx, conf)
+}
+
+// UpdateRoutingConfigV2 更新路由规则
+func (t *tenantStore) UpdateRoutingConfigV2(conf *model.RouterConfig) error {
+	if err := t.checkRule(t.guardRoutingV2, conf.ID, conf.Namespace); err != nil {
+		return err
+	}
+	return t.store.UpdateRoutingConfigV2(conf)
+}
+
+// UpdateRoutingConfigV2Tx 在事务中更新路由规则
+func (t *tenantStore) UpdateRoutingConfigV2Tx(tx Tx, conf *model.RouterConfig) error {
+	if err := t.checkRule(t.guardRoutingV2, conf.ID, conf.Namespace); err != nil {
+		return err
+	}
+	return t.store.UpdateRoutingConfigV2Tx(tx, conf)
+}
+
+// DeleteRoutingConfigV2 删除路由规则
+func (t *tenantStore) DeleteRoutingConfigV2(serviceID string) error {
+	if err := t.guardRoutingV2(serviceID); err != nil {
+		return err
+	}
+	return t.store.DeleteRoutingConfigV2(serviceID)
+}
+
+// DeleteRoutingConfigV2Tx 在事务中删除路由规则
+func (t *tenantStore) DeleteRoutingConfigV2Tx(tx Tx, serviceID string) error {
+	if err := t.guardRoutingV2(serviceID); err != nil {
+		return err
+	}
+	return NewNamingTxStore(t.store).DeleteRoutingConfigV2Tx(tx, serviceID)
+}
+
+// GetRoutingConfigsV2ForCache 增量查询当前租户的路由规则
+func (t *tenantStore) GetRoutingConfigsV2ForCache(mtime time.Time, firstUpdate bool) ([]*model.RouterConfig, error) {
+	owned, err := t.namespaces()
+	if err != nil {
+		return nil, err
+	}
+	items, err := t.store.GetRoutingConfigsV2ForCache(mtime, firstUpdate)
+	if err != nil {
+		return nil, err
+	}
+	return keep(items, func(conf *model.RouterConfig) bool {
+		return contains(owned, conf.Namespace)
+	}), nil
+}
+
+// GetRoutingConfigV2WithID 根据 ID 查询路由规则，其他租户的路由规则返回 nil
+func (t *tenantStore) GetRoutingConfigV2WithID(id string) (*model.RouterConfig, error) {
+	conf, err := t.store.GetRoutingConfigV2WithID(id)
+	if err != nil || conf == nil {
+		return nil, err
+	}
+	if owned, err := t.visibleNamespace(conf.Namespace); err != nil || !owned {
+		return nil, err
+	}
+	return conf, nil
+}
+
+// GetRoutingConfigV2WithIDTx 在事务中根据 ID 查询路由规则，其他租户的路由规则返回 nil
+func (t *tenantStore) GetRoutingConfigV2WithIDTx(tx Tx, id string) (*model.RouterConfig, error) {
+	conf, err := t.store.GetRoutingConfigV2WithIDTx(tx, id)
+	if err != nil || conf == nil {
+		return nil, err
+	}
+	if owned, err := t.visibleNamespace(conf.Namespace); err != nil || !owned {
+		return nil, err
+	}
+	return conf, nil
+}
+
+// faultDetectNamespace 查询探测规则所在的命名空间，存储没有提供按照 ID 查询的方法，从全量数据中查找
+func (t *tenantStore) faultDetectNamespace(id string) (string, bool, error) {
+	items, err := t.store.GetFaultDetectRulesForCache(time.Time{}, false)
+	if err != nil {
+		return "", false, err
+	}
+	for _, rule := range items {
+		if rule.ID == id {
+			return rule.Namespace, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// guardFaultDetectRule 已有的探测规则不能属于其他租户，不存在时交给底层存储处理
+func (t *tenantStore) guardFaultDetectRule(id string) error {
+	if id == "" {
+		return nil
+	}
+	namespace, exist, err := t.faultDetectNamespace(id)
+	if err != nil || !exist {
+		return err
+	}
+	return t.guard(namespace, "fault detect rule", id)
+}
+
+// CreateFaultDetectRule 新增探测规则
+func (t *tenantStore) CreateFaultDetectRule(conf *model.FaultDetectRule) error {
+	if err := t.checkScope("fault detect rule", conf.Namespace, ""); err != nil {
+		return err
+	}
+	return t.store.CreateFaultDetectRule(conf)
+}
+
+// CreateFaultDetectRuleTx 在事务中新增探测规则
+func (t *tenantStore) CreateFaultDetectRuleTx(tx Tx, conf *model.FaultDetectRule) error {
+	if err := t.checkScope("fault detect rule", conf.Namespace, ""); err != nil {
+		return err
+	}
+	return NewNamingTxStore(t.store).CreateFaultDetectRuleTx(tx, conf)
+}
+
+// UpdateFaultDetectRule 更新探测规则
+func (t *tenantStore) UpdateFaultDetectRule(conf *model.FaultDetectRule) error {
+	if err := t.checkRule(t.guardFaultDetectRule, conf.ID, conf.Namespace); err != nil {
+		return err
+	}
+	return t.store.UpdateFaultDetectRule(conf)
+}
+
+// UpdateFaultDetectRuleTx 在事务中更新探测规则
+func (t *tenantStore) UpdateFaultDetectRuleTx(tx Tx, conf *model.FaultDetectRule) error {
+	if err := t.checkRule(t.guardFaultDetectRule, conf.ID, conf.Namespace); err != nil {
+		return err
+	}
+	return NewNamingTxStore(t.store).UpdateFaultDetectRuleTx(tx, conf)
+}
+
+// DeleteFaultDetectRule 删除探测规则
+func (t *tenantStore) DeleteFaultDetectRule(id string) error {
+	if err := t.guardFaultDetectRule(id); err != nil {
+		return err
+	}
+	return t.store.DeleteFaultDetectRule(id)
+}
+
+// DeleteFaultDetectRuleTx 在事务中删除探测规则
+func (t *tenantStore) DeleteFaultDetectRuleTx(tx Tx, id string) error {
+	if err := t.guardFaultDetectRule(id); err != nil {
+		return err
+	}
+	return NewNamingTxStore(t.store).DeleteFaultDetectRuleTx(tx, id)
+}
+
+// HasFaultDetectRule 当前租户是否存在该探测规则
+func (t *tenantStore) HasFaultDetectRule(id string) (bool, error) {
+	exist, err := t.store.HasFaultDetectRule(id)
+	if err != nil || !exist {
+		return false, err
+	}
+	namespace, _, err := t.faultDetectNamespace(id)
+	if err != nil {
+		return false, err
+	}
+	return t.visibleNamespace(namespace)
+}
+
+// HasFaultDetectRuleByName 当前租户的命名空间下是否存在同名的探测规则
+func (t *tenantStore) HasFaultDetectRuleByName(name string, namespace string) (bool, error) {
+	if owned, err := t.visibleNamespace(namespace); err != nil || !owned {
+		return false, err
+	}
+	return t.store.HasFaultDetectRuleByName(name, namespace)
+}
+
+// HasFaultDetectRuleByNameExcludeId 当前租户的命名空间下是否存在除 id 之外的同名探测规则
+func (t *tenantStore) HasFaultDetectRuleByNameExcludeId(name string, namespace string, id string) (bool, error) {
+	if owned, err := t.visibleNamespace(namespace); err != nil || !owned {
+		return false, err
+	}
+	return t.store.HasFaultDetectRuleByNameExcludeId(name, namespace, id)
+}
+
+// GetFaultDetectRules 查询当前租户的探测规则
+func (t *tenantStore) GetFaultDetectRules(filter map[string]string, offset uint32, limit uint32) (
+	uint32, []*model.FaultDetectRule, error) {
+	owned, err := t.namespaces()
+	if err != nil {
+		return 0, nil, err
+	}
+	_, items, err := t.store.GetFaultDetectRules(filter, 0, 0)
+	if err != nil {
+		return 0, nil, err
+	}
+	total, items := pageOf(items, func(rule *model.FaultDetectRule) bool {
+		return contains(owned, rule.Namespace)
+	}, offset, limit)
+	return total, items, nil
+}
+
+// GetFaultDetectRulesForCache 增量查询当前租户的探测规则
+func (t *tenantStore) GetFaultDetectRulesForCache(mtime time.Time, firstUpdate bool) ([]*model.FaultDetectRule, error) {
+	owned, err := t.namespaces()
+	if err != nil {
+		return nil, err
+	}
+	items, err := t.store.GetFaultDetectRulesForCache(mtime, firstUpdate)
+	if err != nil {
+		return nil, err
+	}
+	return keep(items, func(rule *model.FaultDetectRule) bool {
+		return contains(owned, rule.Namespace)
+	}), nil
+}
+
+// guardServiceContract 已有的服务契约不能属于其他租户，不存在时交给底层存储处理
+func (t *tenantStore) guardServiceContract(id string) error {
+	if id == "" {
+		return nil
+	}
+	saved, err := t.store.GetServiceContract(id)
+	if err != nil || saved == nil {
+		return err
+	}
+	return t.guard(saved.Namespace, "service contract", id)
+}
+
+// CreateServiceContract 新增服务契约
+func (t *tenantStore) CreateServiceContract(contract *model.ServiceContract) error {
+	if err := t.checkScope("service contract", contract.Namespace, ""); err != nil {
+		return err
+	}
+	return t.store.CreateServiceContract(contract)
+}
+
+// CreateServiceContractTx 在事务中新增服务契约
+func (t *tenantStore) CreateServiceContractTx(tx Tx, contract *model.ServiceContract) error {
+	if err := t.checkScope("service contract", contract.Namespace, ""); err != nil {
+		return err
+	}
+	return NewNamingTxStore(t.store).CreateServiceContractTx(tx, contract)
+}
+
+// UpdateServiceContract 更新服务契约
+func (t *tenantStore) UpdateServiceContract(contract *model.ServiceContract) error {
+	if err := t.checkRule(t.guardServiceContract, contract.ID, contract.Namespace); err != nil {
+		return err
+	}
+	return t.store.UpdateServiceContract(contract)
+}
+
+// UpdateServiceContractTx 在事务中更新服务契约
+func (t *tenantStore) UpdateServiceContractTx(tx Tx, contract *model.ServiceContract) error {
+	if err := t.checkRule(t.guardServiceContract, contract.ID, contract.Namespace); err != nil {
+		return err
+	}
+	return NewNamingTxStore(t.store).UpdateServiceContractTx(tx, contract)
+}
+
+// DeleteServiceContract 删除服务契约
+func (t *tenantStore) DeleteServiceContract(contract *model.ServiceContract) error {
+	if err := t.checkRule(t.guardServiceContract, contract.ID, contract.Namespace); err != nil {
+		return err
+	}
+	return t.store.DeleteServiceContract(contract)
+}
+
+// DeleteServiceContractTx 在事务中删除服务契约
+func (t *tenantStore) DeleteServiceContractTx(tx Tx, contract *model.ServiceContract) error {
+	if err := t.checkRule(t.guardServiceContract, contract.ID, contract.Namespace); err != nil {
+		return err
+	}
+	return NewNamingTxStore(t.store).DeleteServiceContractTx(tx, contract)
+}
+
+// GetMoreServiceContracts 增量查询当前租户的服务契约
+func (t *tenantStore) GetMoreServiceContracts(firstUpdate bool, mtime time.Time) ([]*model.ServiceContract, error) {
+	owned, err := t.namespaces()
+	if err != nil {
+		return nil, err
+	}
+	items, err := t.store.GetMoreServiceContracts(firstUpdate, mtime)
+	if err != nil {
+		return nil, err
+	}
+	return keep(items, func(contract *model.ServiceContract) bool {
+		return contains(owned, contract.Namespace)
+	}), nil
+}
+
+// GetServiceContract 查询服务契约，其他租户的服务契约返回 nil
+func (t *tenantStore) GetServiceContract(id string) (*model.ServiceContract, error) {
+	contract, err := t.store.GetServiceContract(id)
+	if err != nil || contract == nil {
+		return nil, err
+	}
+	if owned, err := t.visibleNamespace(contract.Namespace); err != nil || !owned {
+		return nil, err
+	}
+	return contract, nil
+}
+
+// AddServiceContractInterfaces 创建服务契约的接口
+func (t *tenantStore) AddServiceContractInterfaces(contract *model.ServiceContract) error {
+	if err := t.checkRule(t.guardServiceContract, contract.ID, contract.Namespace); err != nil {
+		return err
+	}
+	return t.store.AddServiceContractInterfaces(contract)
+}
+
+// AddServiceContractInterfacesTx 在事务中创建服务契约的接口
+func (t *tenantStore) AddServiceContractInterfacesTx(tx Tx, contract *model.ServiceContract) error {
+	if err := t.checkRule(t.guardServiceContract, contract.ID, contract.Namespace); err != nil {
+		return err
+	}
+	return NewNamingTxStore(t.store).AddServiceContractInterfacesTx(tx, contract)
+}
+
+// AppendServiceContractInterfaces 追加服务契约的接口
+func (t *tenantStore) AppendServiceContractInterfaces(contract *model.ServiceContract) error {
+	if err := t.checkRule(t.guardServiceContract, contract.ID, contract.Namespace); err != nil {
+		return err
+	}
+	return t.store.AppendServiceContractInterfaces(contract)
+}
+
+// AppendServiceContractInterfacesTx 在事务中追加服务契约的接口
+func (t *tenantStore) AppendServiceContractInterfacesTx(tx Tx, contract *model.ServiceContract) error {
+	if err := t.checkRule(t.guardServiceContract, contract.ID, contract.Namespace); err != nil {
+		return err
+	}
+	return NewNamingTxStore(t.store).AppendServiceContractInterfacesTx(tx, contract)
+}
+
+// DeleteServiceContractInterfaces 删除服务契约的接口
+func (t *tenantStore) DeleteServiceContractInterfaces(contract *model.ServiceContract) error {
+	if err := t.checkRule(t.guardServiceContract, contract.ID, contract.Namespace); err != nil {
+		return err
+	}
+	return t.store.DeleteServiceContractInterfaces(contract)
+}
+
+// DeleteServiceContractInterfacesTx 在事务中删除服务契约的接口
+func (t *tenantStore) DeleteServiceContractInterfacesTx(tx Tx, contract *model.ServiceContract) error {
+	if err := t.checkRule(t.guardServiceContract, contract.ID, contract.Namespace); err != nil {
+		return err
+	}
+	return NewNamingTxStore(t.store).DeleteServiceContractInterfacesTx(tx, contract)
+}
+
+// CreateConfigFileGroup 创建配置文件组，Owner 为空时填充为当前租户
+func (t *tenantStore) CreateConfigFileGroup(fileGroup *model.ConfigFileGroup) (*model.ConfigFileGroup, error) {
+	if err := t.checkNamespace(fileGroup.Namespace); err != nil {
+		return nil, err
+	}
+	t.fillOwner(&fileGroup.Owner)
+	return t.store.CreateConfigFileGroup(fileGroup)
+}
+
+// UpdateConfigFileGroup 更新配置文件组
+func (t *tenantStore) UpdateConfigFileGroup(fileGroup *model.ConfigFileGroup) error {
+	if err := t.checkNamespace(fileGroup.Namespace); err != nil {
+		return err
+	}
+	return t.store.UpdateConfigFileGroup(fileGroup)
+}
+
+// GetConfigFileGroup 查询配置文件组，其他租户的配置文件组返回 nil
+func (t *tenantStore) GetConfigFileGroup(namespace string, name string) (*model.ConfigFileGroup, error) {
+	if owned, err := t.visibleNamespace(namespace); err != nil || !owned {
+		return nil, err
+	}
+	return t.store.GetConfigFileGroup(namespace, name)
+}
+
+// DeleteConfigFileGroup 删除配置文件组
+func (t *tenantStore) DeleteConfigFileGroup(namespace string, name string) error {
+	if err := t.guardNamespace(namespace); err != nil {
+		return err
+	}
+	return t.store.DeleteConfigFileGroup(namespace, name)
+}
+
+// GetMoreConfigGroup 增量查询当前租户的配置文件组
+func (t *tenantStore) GetMoreConfigGroup(firstUpdate bool, mtime time.Time) ([]*model.ConfigFileGroup, error) {
+	owned, err := t.namespaces()
+	if err != nil {
+		return nil, err
+	}
+	items, err := t.store.GetMoreConfigGroup(firstUpdate, mtime)
+	if err != nil {
+		return nil, err
+	}
+	return keep(items, func(group *model.ConfigFileGroup) bool {
+		return contains(owned, group.Namespace)
+	}), nil
+}
+
+// CountConfigGroups 统计命名空间下的配置文件组数量，其他租户的命名空间返回 0
+func (t *tenantStore) CountConfigGroups(namespace string) (uint64, error) {
+	if owned, err := t.visibleNamespace(namespace); err != nil || !owned {
+		return 0, err
+	}
+	return t.store.CountConfigGroups(namespace)
+}
+
+// LockConfigFile 加锁配置文件，其他租户的配置文件返回 nil
+func (t *tenantStore) LockConfigFile(tx Tx, file *model.ConfigFileKey) (*model.ConfigFile, error) {
+	if owned, err := t.visibleNamespace(file.Namespace); err != nil || !owned {
+		return nil, err
+	}
+	return t.store.LockConfigFile(tx, file)
+}
+
+// CreateConfigFileTx 在事务中创建配置文件
+func (t *tenantStore) CreateConfigFileTx(tx Tx, file *model.ConfigFile) error {
+	if err := t.checkNamespace(file.Namespace); err != nil {
+		return err
+	}
+	return t.store.CreateConfigFileTx(tx, file)
+}
+
+// GetConfigFile 查询配置文件，其他租户的配置文件返回 nil
+func (t *tenantStore) GetConfigFile(namespace string, group string, name string) (*model.ConfigFile, error) {
+	if owned, err := t.visibleNamespace(namespace); err != nil || !owned {
+		return nil, err
+	}
+	return t.store.GetConfigFile(namespace, group, name)
+}
+
+// GetConfigFileTx 在事务中查询配置文件，其他租户的配置文件返回 nil
+func (t *tenantStore) GetConfigFileTx(tx Tx, namespace string, group string, name string) (*model.ConfigFile, error) {
+	if owned, err := t.visibleNamespace(namespace); err != nil || !owned {
+		return nil, err
+	}
+	return t.store.GetConfigFileTx(tx, namespace, group, name)
+}
+
+// QueryConfigFiles 查询当前租户的配置文件
+func (t *tenantStore) QueryConfigFiles(filter map[string]string, offset uint32, limit uint32) (
+	uint32, []*model.ConfigFile, error) {
+	owned, err := t.namespaces()
+	if err != nil {
+		return 0, nil, err
+	}
+	_, items, err := t.store.QueryConfigFiles(filter, 0, 0)
+	if err != nil {
+		return 0, nil, err
+	}
+	total, items := pageOf(items, func(file *model.ConfigFile) bool {
+		return contains(owned, file.Namespace)
+	}, offset, limit)
+	return total, items, nil
+}
+
+// UpdateConfigFileTx 在事务中更新配置文件
+func (t *tenantStore) UpdateConfigFileTx(tx Tx, file *model.ConfigFile) error {
+	if err := t.checkNamespace(file.Namespace); err != nil {
+		return err
+	}
+	return t.store.UpdateConfigFileTx(tx, file)
+}
+
+// DeleteConfigFileTx 在事务中删除配置文件
+func (t *tenantStore) DeleteConfigFileTx(tx Tx, namespace string, group string, name string) error {
+	if err := t.guardNamespace(namespace); err != nil {
+		return err
+	}
+	return t.store.DeleteConfigFileTx(tx, namespace, group, name)
+}
+
+// CountConfigFiles 统计配置文件组下的文件数量，其他租户的命名空间返回 0
+func (t *tenantStore) CountConfigFiles(namespace string, group string) (uint64, error) {
+	if owned, err := t.visibleNamespace(namespace); err != nil || !owned {
+		return 0, err
+	}
+	return t.store.CountConfigFiles(namespace, group)
+}
+
+// CountConfigFileEachGroup 统计当前租户每个配置文件组下的文件数量
+func (t *tenantStore) CountConfigFileEachGroup() (map[string]map[string]int64, error) {
+	owned, err := t.namespaces()
+	if err != nil {
+		return nil, err
+	}
+	items, err := t.store.CountConfigFileEachGroup()
+	if err != nil {
+		return nil, err
+	}
+	return keepMap(items, func(namespace string, _ map[string]int64) bool {
+		return contains(owned, namespace)
+	}), nil
+}
+
+// GetConfigFileActiveRelease 查询配置文件的生效发布，其他租户的配置发布返回 nil
+func (t *tenantStore) GetConfigFileActiveRelease(file *model.ConfigFileKey) (*model.ConfigFileRelease, error) {
+	if owned, err := t.visibleNamespace(file.Namespace); err != nil || !owned {
+		return nil, err
+	}
+	return t.store.GetConfigFileActiveRelease(file)
+}
+
+// GetConfigFileActiveReleaseTx 在事务中查询配置文件的生效发布，其他租户的配置发布返回 nil
+func (t *tenantStore) GetConfigFileActiveReleaseTx(tx Tx, file *model.ConfigFileKey) (*model.ConfigFileRelease, error) {
+	if owned, err := t.visibleNamespace(file.Namespace); err != nil || !owned {
+		return nil, err
+	}
+	return t.store.GetConfigFileActiveReleaseTx(tx, file)
+}
+
+// CreateConfigFileReleaseTx 在事务中创建配置发布
+func (t *tenantStore) CreateConfigFileReleaseTx(tx Tx, fileRelease *model.ConfigFileRelease) error {
+	if err := t.checkNamespace(fileRelease.Namespace); err != nil {
+		return err
+	}
+	return t.store.CreateConfigFileReleaseTx(tx, fileRelease)
+}
+
+// GetConfigFileRelease 查询配置发布，其他租户的配置发布返回 nil
+func (t *tenantStore) GetConfigFileRelease(req *model.ConfigFileReleaseKey) (*model.ConfigFileRelease, error) {
+	if owned, err := t.visibleNamespace(req.Namespace); err != nil || !owned {
+		return nil, err
+	}
+	return t.store.GetConfigFileRelease(req)
+}
+
+// GetConfigFileReleaseTx 在事务中查询配置发布，其他租户的配置发布返回 nil
+func (t *tenantStore) GetConfigFileReleaseTx(tx Tx, req *model.ConfigFileReleaseKey) (*model.ConfigFileRelease, error) {
+	if owned, err := t.visibleNamespace(req.Namespace); err != nil || !owned {
+		return nil, err
+	}
+	return t.store.GetConfigFileReleaseTx(tx, req)
+}
+
+// DeleteConfigFileReleaseTx 在事务中删除配置发布
+func (t *tenantStore) DeleteConfigFileReleaseTx(tx Tx, data *model.ConfigFileReleaseKey) error {
+	if err := t.guardNamespace(data.Namespace); err != nil {
+		return err
+	}
+	return t.store.DeleteConfigFileReleaseTx(tx, data)
+}
+
+// ActiveConfigFileReleaseTx 在事务中使配置发布生效
+func (t *tenantStore) ActiveConfigFileReleaseTx(tx Tx, release *model.ConfigFileRelease) error {
+	if err := t.guardNamespace(release.Namespace); err != nil {
+		return err
+	}
+	return t.store.ActiveConfigFileReleaseTx(tx, release)
+}
+
+// InactiveConfigFileReleaseTx 在事务中使配置发布失效
+func (t *tenantStore) InactiveConfigFileReleaseTx(tx Tx, release *model.ConfigFileRelease) error {
+	if err := t.guardNamespace(release.Namespace); err != nil {
+		return err
+	}
+	return t.store.InactiveConfigFileReleaseTx(tx, release)
+}
+
+// CleanConfigFileReleasesTx 在事务中清空配置文件的发布
+func (t *tenantStore) CleanConfigFileReleasesTx(tx Tx, namespace string, group string, fileName string) error {
+	if err := t.guardNamespace(namespace); err != nil {
+		return err
+	}
+	return t.store.CleanConfigFileReleasesTx(tx, namespace, group, fileName)
+}
+
+// GetMoreReleaseFile 增量查询当前租户的配置发布
+func (t *tenantStore) GetMoreReleaseFile(firstUpdate bool, modifyTime time.Time) ([]*model.ConfigFileRelease, error) {
+	owned, err := t.namespaces()
+	if err != nil {
+		return nil, err
+	}
+	items, err := t.store.GetMoreReleaseFile(firstUpdate, modifyTime)
+	if err != nil {
+		return nil, err
+	}
+	return keep(items, func(release *model.ConfigFileRelease) bool {
+		return contains(owned, release.Namespace)
+	}), nil
+}
+
+// CountConfigReleases 统计配置文件组下的发布数量，其他租户的命名空间返回 0
+func (t *tenantStore) CountConfigReleases(namespace string, group string, onlyActive bool) (uint64, error) {
+	if owned, err := t.visibleNamespace(namespace); err != nil || !owned {
+		return 0, err
+	}
+	return t.store.CountConfigReleases(namespace, group, onlyActive)
+}
+
+// GetConfigFileBetaReleaseTx 在事务中查询配置文件的灰度发布，其他租户的配置发布返回 nil
+func (t *tenantStore) GetConfigFileBetaReleaseTx(tx Tx, file *model.ConfigFileKey) (*model.ConfigFileRelease, error) {
+	if owned, err := t.visibleNamespace(file.Namespace); err != nil || !owned {
+		return nil, err
+	}
+	return t.store.GetConfigFileBetaReleaseTx(tx, file)
+}
+
+// CreateConfigFileReleaseHistory 创建配置发布历史
+func (t *tenantStore) CreateConfigFileReleaseHistory(history *model.ConfigFileReleaseHistory) error {
+	if err := t.checkNamespace(history.Namespace); err != nil {
+		return err
+	}
+	return t.store.CreateConfigFileReleaseHistory(history)
+}
+
+// QueryConfigFileReleaseHistories 查询当前租户的配置发布历史
+func (t *tenantStore) QueryConfigFileReleaseHistories(filter map[string]string, offset uint32, limit uint32) (
+	uint32, []*model.ConfigFileReleaseHistory, error) {
+	owned, err := t.namespaces()
+	if err != nil {
+		return 0, nil, err
+	}
+	_, items, err := t.store.QueryConfigFileReleaseHistories(filter, 0, 0)
+	if err != nil {
+		return 0, nil, err
+	}
+	total, items := pageOf(items, func(history *model.ConfigFileReleaseHistory) bool {
+		return contains(owned, history.Namespace)
+	}, offset, limit)
+	return total, items, nil
+}
+
+// CleanConfigFileReleaseHistory 按照时间清理发布历史会跨越租户，租户视图不支持
+func (t *tenantStore) CleanConfigFileReleaseHistory(endTime time.Time, limit uint64) error {
+	return t.unsupported("CleanConfigFileReleaseHistory")
+}
+
+// QueryAllConfigFileTemplates 查询配置模板，配置模板不属于任何租户
+func (t *tenantStore) QueryAllConfigFileTemplates() ([]*model.ConfigFileTemplate, error) {
+	return t.store.QueryAllConfigFileTemplates()
+}
+
+// CreateConfigFileTemplate 创建配置模板，配置模板不属于任何租户
+func (t *tenantStore) CreateConfigFileTemplate(template *model.ConfigFileTemplate) (*model.ConfigFileTemplate, error) {
+	return t.store.CreateConfigFileTemplate(template)
+}
+
+// GetConfigFileTemplate 查询配置模板，配置模板不属于任何租户
+func (t *tenantStore) GetConfigFileTemplate(name string) (*model.ConfigFileTemplate, error) {
+	return t.store.GetConfigFileTemplate(name)
+}
+
+// BatchAddClients 增加客户端，客户端不属于任何租户
+func (t *tenantStore) BatchAddClients(clients []*model.Client) error {
+	return t.store.BatchAddClients(clients)
+}
+
+// BatchDeleteClients 删除客户端，客户端不属于任何租户
+func (t *tenantStore) BatchDeleteClients(ids []string) error {
+	return t.store.BatchDeleteClients(ids)
+}
+
+// GetMoreClients 增量查询客户端，客户端不属于任何租户
+func (t *tenantStore) GetMoreClients(mtime time.Time, firstUpdate bool) (map[string]*model.Client, error) {
+	return t.store.GetMoreClients(mtime, firstUpdate)
+}
+
+// StartLeaderElection 参与选主
+func (t *tenantStore) StartLeaderElection(key string) error {
+	return t.store.StartLeaderElection(key)
+}
+
+// IsLeader 当前节点是否为 key 的主节点
+func (t *tenantStore) IsLeader(key string) bool {
+	return t.store.IsLeader(key)
+}
+
+// ListLeaderElections 查询所有的选主结果
+func (t *tenantStore) ListLeaderElections() ([]*model.LeaderElection, error) {
+	return t.store.ListLeaderElections()
+}
+
+// ReleaseLeaderElection 放弃主节点身份
+func (t *tenantStore) ReleaseLeaderElection(key string) error {
+	return t.store.ReleaseLeaderElection(key)
+}
+
+// BatchCleanDeletedInstances 清理已删除的实例会跨越租户，租户视图不支持
+func (t *tenantStore) BatchCleanDeletedInstances(timeout time.Duration, batchSize uint32) (uint32, error) {
+	return 0, t.unsupported("BatchCleanDeletedInstances")
+}
+
+// GetUnHealthyInstances 查询不健康的实例，其他租户的实例不会返回，因此返回的数量可能少于 limit
+func (t *tenantStore) GetUnHealthyInstances(timeout time.Duration, limit uint32) ([]string, error) {
+	ids, err := t.store.GetUnHealthyInstances(timeout, limit)
+	if err != nil || len(ids) == 0 {
+		return ids, err
+	}
+	query := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		query[id] = true
+	}
+	visible, err := t.GetInstancesBrief(query)
+	if err != nil {
+		return nil, err
+	}
+	return keep(ids, func(id string) bool {
+		_, ok := visible[id]
+		return ok
+	}), nil
+}
+
+// BatchCleanDeletedClients 清理已删除的客户端，客户端不属于任何租户
+func (t *tenantStore) BatchCleanDeletedClients(timeout time.Duration, batchSize uint32) (uint32, error) {
+	return t.store.BatchCleanDeletedClients(timeout, batchSize)
+}
+
+// AcquireLock 获取分布式锁
+func (t *tenantStore) AcquireLock(key string, owner string, ttl time.Duration) (*model.DistributedLock, error) {
+	return NewDistributedLockStore(t.store).AcquireLock(key, owner, ttl)
+}
+
+// RenewLock 续约分布式锁
+func (t *tenantStore) RenewLock(key string, owner string, ttl time.Duration) (*model.DistributedLock, error) {
+	return NewDistributedLockStore(t.store).RenewLock(key, owner, ttl)
+}
+
+// ReleaseLock 释放分布式锁
+func (t *tenantStore) ReleaseLock(key string, owner string) error {
+	return NewDistributedLockStore(t.store).ReleaseLock(key, owner)
+}
+
+// ListLocks 查询所有的分布式锁
+func (t *tenantStore) ListLocks() ([]*model.DistributedLock, error) {
+	return NewDistributedLockStore(t.store).ListLocks()
+}
+
+// WatchLeaderElection 监听选主结果的变化
+func (t *tenantStore) WatchLeaderElection(ctx context.Context, key string) (LeaderWatcher, error) {
+	return WatchLeaderElection(ctx, t.store, key)
+}
+
+// CleanGrayResource 清理灰度资源，灰度资源不属于任何租户
+func (t *tenantStore) CleanGrayResource(tx Tx, data *model.GrayResource) error {
+	return t.store.CleanGrayResource(tx, data)
+}
+
+// CreateGrayResourceTx 在事务中创建灰度资源，灰度资源不属于任何租户
+func (t *tenantStore) CreateGrayResourceTx(tx Tx, data *model.GrayResource) error {
+	return t.store.CreateGrayResourceTx(tx, data)
+}
+
+// GetMoreGrayResouces 增量查询灰度资源，灰度资源不属于任何租户
+func (t *tenantStore) GetMoreGrayResouces(firstUpdate bool, mtime time.Time) ([]*model.GrayResource, error) {
+	return t.store.GetMoreGrayResouces(firstUpdate, mtime)
+}
+
+// ownUser 用户是否属于当前租户，主账户自身同样属于当前租户
+func (t *tenantStore) ownUser(user *model.User) bool {
+	return user.Owner == t.tenant || user.ID == t.tenant
+}
+
+// claimUser 写入用户时 Owner 为空则填充为当前租户，主账户自身的 Owner 保持为空
+func (t *tenantStore) claimUser(user *model.User) error {
+	if user.ID == t.tenant && user.Owner == "" {
+		return nil
+	}
+	return t.claim(&user.Owner, "user", user.ID)
+}
+
+// guardUser 已有的用户不能属于其他租户，不存在时交给底层存储处理
+func (t *tenantStore) guardUser(id string) error {
+	saved, err := t.store.GetUser(id)
+	if err != nil || saved == nil {
+		return err
+	}
+	if !t.ownUser(saved) {
+		return t.mismatch("user", id)
+	}
+	return nil
+}
+
+// AddUser 创建用户，Owner 为空时填充为当前租户
+func (t *tenantStore) AddUser(user *model.User) error {
+	if err := t.claimUser(user); err != nil {
+		return err
+	}
+	return t.store.AddUser(user)
+}
+
+// UpdateUser 更新用户
+func (t *tenantStore) UpdateUser(user *model.User) error {
+	if err := t.guardUser(user.ID); err != nil {
+		return err
+	}
+	if err := t.claimUser(user); err != nil {
+		return err
+	}
+	return t.store.UpdateUser(user)
+}
+
+// DeleteUser 删除用户
+func (t *tenantStore) DeleteUser(user *model.User) error {
+	if err := t.guardUser(user.ID); err != nil {
+		return err
+	}
+	return t.store.DeleteUser(user)
+}
+
+// GetSubCount 查询主账户下的子账户数量，其他租户的账户返回 0
+func (t *tenantStore) GetSubCount(user *model.User) (uint32, error) {
+	if saved, err := t.GetUser(user.ID); err != nil || saved == nil {
+		return 0, err
+	}
+	return t.store.GetSubCount(user)
+}
+
+// GetUser 查询用户，其他租户的用户返回 nil
+func (t *tenantStore) GetUser(id string) (*model.User, error) {
+	user, err := t.store.GetUser(id)
+	if err != nil || user == nil || !t.ownUser(user) {
+		return nil, err
+	}
+	return user, nil
+}
+
+// GetUserByName 根据用户名查询用户，其他租户的用户返回 nil
+func (t *tenantStore) GetUserByName(name string, ownerId string) (*model.User, error) {
+	user, err := t.store.GetUserByName(name, ownerId)
+	if err != nil || user == nil || !t.ownUser(user) {
+		return nil, err
+	}
+	return user, nil
+}
+
+// GetUserByIds 批量查询用户，其他租户的用户不会返回
+func (t *tenantStore) GetUserByIds(ids []string) ([]*model.User, error) {
+	items, err := t.store.GetUserByIds(ids)
+	if err != nil {
+		return nil, err
+	}
+	return keep(items, t.ownUser), nil
+}
+
+// GetUsers 查询当前租户的用户
+func (t *tenantStore) GetUsers(filters map[string]string, offset uint32, limit uint32) (uint32, []*model.User, error) {
+	_, items, err := t.store.GetUsers(filters, 0, 0)
+	if err != nil {
+		return 0, nil, err
+	}
+	total, items := pageOf(items, t.ownUser, offset, limit)
+	return total, items, nil
+}
+
+// GetUsersForCache 增量查询当前租户的用户
+func (t *tenantStore) GetUsersForCache(mtime time.Time, firstUpdate bool) ([]*model.User, error) {
+	items, err := t.store.GetUsersForCache(mtime, firstUpdate)
+	if err != nil {
+		return nil, err
+	}
+	return keep(items, t.ownUser), nil
+}
+
+func (t *tenantStore) ownGroup(group *model.UserGroup) bool {
+	return group.Owner == t.tenant
+}
+
+// guardGroup 已有的用户组不能属于其他租户，不存在时交给底层存储处理
+func (t *tenantStore) guardGroup(id string) error {
+	saved, err := t.store.GetGroup(id)
+	if err != nil || saved == nil {
+		return err
+	}
+	if !t.ownGroup(saved) {
+		return t.mismatch("user group", id)
+	}
+	return nil
+}
+
+// guardMembers 用户组的成员需要是当前租户的用户
+func (t *tenantStore) guardMembers(ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	users, err := t.store.GetUserByIds(ids)
+	if err != nil {
+		return err
+	}
+	for _, user := range users {
+		if !t.ownUser(user) {
+			return t.mismatch("user", user.ID)
+		}
+	}
+	return nil
+}
+
+// AddGroup 创建用户组，Owner 为空时填充为当前租户
+func (t *tenantStore) AddGroup(group *model.UserGroup) error {
+	if err := t.claim(&group.Owner, "user group", group.ID); err != nil {
+		return err
+	}
+	if err := t.guardMembers(group.UserIds); err != nil {
+		return err
+	}
+	return t.store.AddGroup(group)
+}
+
+// UpdateGroup 更新用户组
+func (t *tenantStore) UpdateGroup(group *model.ModifyUserGroup) error {
+	if err := t.guardGroup(group.ID); err != nil {
+		return err
+	}
+	if group.Owner != "" && group.Owner != t.tenant {
+		return t.mismatch("user group", group.ID)
+	}
+	if err := t.guardMembers(group.AddUserIds); err != nil {
+		return err
+	}
+	return t.store.UpdateGroup(group)
+}
+
+// DeleteGroup 删除用户组
+func (t *tenantStore) DeleteGroup(group *model.UserGroup) error {
+	if err := t.guardGroup(group.ID); err != nil {
+		return err
+	}
+	return t.store.DeleteGroup(group)
+}
+
+// GetGroup 查询用户组，其他租户的用户组返回 nil
+func (t *tenantStore) GetGroup(id string) (*model.UserGroup, error) {
+	group, err := t.store.GetGroup(id)
+	if err != nil || group == nil || !t.ownGroup(group) {
+		return nil, err
+	}
+	return group, nil
+}
+
+// GetGroupByName 根据名字查询用户组，其他租户的用户组返回 nil
+func (t *tenantStore) GetGroupByName(name string, owner string) (*model.UserGroup, error) {
+	group, err := t.store.GetGroupByName(name, owner)
+	if err != nil || group == nil || !t.ownGroup(group) {
+		return nil, err
+	}
+	return group, nil
+}
+
+// GetGroups 查询当前租户的用户组
+func (t *tenantStore) GetGroups(filters map[string]string, offset uint32, limit uint32) (
+	uint32, []*model.UserGroup, error) {
+	_, items, err := t.store.GetGroups(filters, 0, 0)
+	if err != nil {
+		return 0, nil, err
+	}
+	total, items := pageOf(items, t.ownGroup, offset, limit)
+	return total, items, nil
+}
+
+// GetGroupsForCache 增量查询当前租户的用户组
+func (t *tenantStore) GetGroupsForCache(mtime time.Time, firstUpdate bool) ([]*model.UserGroup, error) {
+	items, err := t.store.GetGroupsForCache(mtime, firstUpdate)
+	if err != nil {
+		return nil, err
+	}
+	return keep(items, t.ownGroup), nil
+}
+
+func (t *tenantStore) ownStrategy(strategy *model.StrategyDetail) bool {
+	return strategy.Owner == t.tenant
+}
+
+// guardStrategy 已有的鉴权策略不能属于其他租户，不存在时交给底层存储处理
+func (t *tenantStore) guardStrategy(id string) error {
+	saved, err := t.store.GetStrategyDetail(id)
+	if err != nil || saved == nil {
+		return err
+	}
+	if !t.ownStrategy(saved) {
+		return t.mismatch("strategy", id)
+	}
+	return nil
+}
+
+// guardStrategyResources 资源关联的鉴权策略需要属于当前租户
+func (t *tenantStore) guardStrategyResources(resources []model.StrategyResource) error {
+	checked := make(map[string]struct{})
+	for _, res := range resources {
+		if contains(checked, res.StrategyID) {
+			continue
+		}
+		if err := t.guardStrategy(res.StrategyID); err != nil {
+			return err
+		}
+		checked[res.StrategyID] = struct{}{}
+	}
+	return nil
+}
+
+// AddStrategy 创建鉴权策略，Owner 为空时填充为当前租户
+func (t *tenantStore) AddStrategy(strategy *model.StrategyDetail) error {
+	if err := t.claim(&strategy.Owner, "strategy", strategy.ID); err != nil {
+		return err
+	}
+	return t.store.AddStrategy(strategy)
+}
+
+// UpdateStrategy 更新鉴权策略
+func (t *tenantStore) UpdateStrategy(strategy *model.ModifyStrategyDetail) error {
+	if err := t.guardStrategy(strategy.ID); err != nil {
+		return err
+	}
+	return t.store.UpdateStrategy(strategy)
+}
+
+// DeleteStrategy 删除鉴权策略
+func (t *tenantStore) DeleteStrategy(id string) error {
+	if err := t.guardStrategy(id); err != nil {
+		return err
+	}
+	return t.store.DeleteStrategy(id)
+}
+
+// LooseAddStrategyResources 松散添加鉴权策略的资源
+func (t *tenantStore) LooseAddStrategyResources(resources []model.StrategyResource) error {
+	if err := t.guardStrategyResources(resources); err != nil {
+		return err
+	}
+	return t.store.LooseAddStrategyResources(resources)
+}
+
+// RemoveStrategyResources 删除鉴权策略的资源
+func (t *tenantStore) RemoveStrategyResources(resources []model.StrategyResource) error {
+	if err := t.guardStrategyResources(resources); err != nil {
+		return err
+	}
+	return t.store.RemoveStrategyResources(resources)
+}
+
+// GetStrategyResources 查询成员关联的鉴权资源，只返回当前租户的鉴权策略中的资源
+func (t *tenantStore) GetStrategyResources(principalId string, principalRole string) ([]model.StrategyResource, error) {
+	strategies, err := t.GetStrategyDetailsForCache(time.Time{}, false)
+	if err != nil {
+		return nil, err
+	}
+	owned := make(map[string]struct{}, len(strategies))
+	for _, strategy := range strategies {
+		owned[strategy.ID] = struct{}{}
+	}
+	items, err := t.store.GetStrategyResources(principalId, principalRole)
+	if err != nil {
+		return nil, err
+	}
+	return keep(items, func(res model.StrategyResource) bool {
+		return contains(owned, res.StrategyID)
+	}), nil
+}
+
+// GetDefaultStrategyDetailByPrincipal 查询成员的默认鉴权策略，其他租户的鉴权策略返回 nil
+func (t *tenantStore) GetDefaultStrategyDetailByPrincipal(principalId string, principalType string) (
+	*model.StrategyDetail, error) {
+	strategy, err := t.store.GetDefaultStrategyDetailByPrincipal(principalId, principalType)
+	if err != nil || strategy == nil || !t.ownStrategy(strategy) {
+		return nil, err
+	}
+	return strategy, nil
+}
+
+// GetStrategyDetail 查询鉴权策略，其他租户的鉴权策略返回 nil
+func (t *tenantStore) GetStrategyDetail(id string) (*model.StrategyDetail, error) {
+	strategy, err := t.store.GetStrategyDetail(id)
+	if err != nil || strategy == nil || !t.ownStrategy(strategy) {
+		return nil, err
+	}
+	return strategy, nil
+}
+
+// GetStrategies 查询当前租户的鉴权策略
+func (t *tenantStore) GetStrategies(filters map[string]string, offset uint32, limit uint32) (
+	uint32, []*model.StrategyDetail, error) {
+	_, items, err := t.store.GetStrategies(filters, 0, 0)
+	if err != nil {
+		return 0, nil, err
+	}
+	total, items := pageOf(items, t.ownStrategy, offset, limit)
+	return total, items, nil
+}
+
+// GetStrategyDetailsForCache 增量查询当前租户的鉴权策略
+func (t *tenantStore) GetStrategyDetailsForCache(mtime time.Time, firstUpdate bool) ([]*model.StrategyDetail, error) {
+	items, err := t.store.GetStrategyDetailsForCache(mtime, firstUpdate)
+	if err != nil {
+		return nil, err
+	}
+	return keep(items, t.ownStrategy), nil
+}
+
+// UpdateServiceCAS 与 UpdateService 的校验一致，校验通过后条件更新
+func (t *tenantStore) UpdateServiceCAS(service *model.Service, needUpdateOwner bool, expectRevision string) error {
+	if err := t.checkUpdateService(service); err != nil {
+		return err
+	}
+	return NewRevisionStore(t.store).UpdateServiceCAS(service, needUpdateOwner, expectRevision)
+}
+
+// UpdateRoutingConfigCAS 与 UpdateRoutingConfig 的校验一致，校验通过后条件更新
+func (t *tenantStore) UpdateRoutingConfigCAS(conf *model.RoutingConfig, expectRevision string) error {
+	if err := t.checkScope("routing config", conf.NamespaceName, conf.ID); err != nil {
+		return err
+	}
+	return NewRevisionStore(t.store).UpdateRoutingConfigCAS(conf, expectRevision)
+}
+
+// UpdateRoutingConfigV2CAS 与 UpdateRoutingConfigV2 的校验一致，校验通过后条件更新
+func (t *tenantStore) UpdateRoutingConfigV2CAS(conf *model.RouterConfig, expectRevision string) error {
+	if err := t.checkRule(t.guardRoutingV2, conf.ID, conf.Namespace); err != nil {
+		return err
+	}
+	return NewRevisionStore(t.store).UpdateRoutingConfigV2CAS(conf, expectRevision)
+}
+
+// UpdateRateLimitCAS 与 UpdateRateLimit 的校验一致，校验通过后条件更新
+func (t *tenantStore) UpdateRateLimitCAS(limit *model.RateLimit, expectRevision string) error {
+	if err := t.checkRateLimit(limit); err != nil {
+		return err
+	}
+	return NewRevisionStore(t.store).UpdateRateLimitCAS(limit, expectRevision)
+}
+
+// UpdateCircuitBreakerRuleCAS 与 UpdateCircuitBreakerRule 的校验一致，校验通过后条件更新
+func (t *tenantStore) UpdateCircuitBreakerRuleCAS(cbRule *model.CircuitBreakerRule, expectRevision string) error {
+	if err := t.checkRule(t.guardCircuitBreakerRule, cbRule.ID, cbRule.Namespace); err != nil {
+		return err
+	}
+	return NewRevisionStore(t.store).UpdateCircuitBreakerRuleCAS(cbRule, expectRevision)
+}
+
+// UpdateFaultDetectRuleCAS 与 UpdateFaultDetectRule 的校验一致，校验通过后条件更新
+func (t *tenantStore) UpdateFaultDetectRuleCAS(conf *model.FaultDetectRule, expectRevision string) error {
+	if err := t.checkRule(t.guardFaultDetectRule, conf.ID, conf.Namespace); err != nil {
+		return err
+	}
+	return NewRevisionStore(t.store).UpdateFaultDetectRuleCAS(conf, expectRevision)
+}
+
+// UpdateStrategyCAS 与 UpdateStrategy 的校验一致，校验通过后条件更新
+func (t *tenantStore) UpdateStrategyCAS(strategy *model.ModifyStrategyDetail, expectRevision string) error {
+	if err := t.guardStrategy(strategy.ID); err != nil {
+		return err
+	}
+	return NewRevisionStore(t.store).UpdateStrategyCAS(strategy, expectRevision)
+}
+
+// PurgeDeleted 物理删除会跨越租户，不支持
+func (t *tenantStore) PurgeDeleted(kind RetentionKind, timeout time.Duration, batchSize uint32) (uint32, error) {
+	return 0, t.unsupported("PurgeDeleted")
+}
+
+// SearchServices 实现 QueryStore
+func (t *tenantStore) SearchServices(q *Query) (uint32, []*model.Service, error) {
+	return (&legacyQueryStore{store: t}).SearchServices(q)
+}
+
+// SearchInstances 实现 QueryStore
+func (t *tenantStore) SearchInstances(q *Query) (uint32, []*model.Instance, error) {
+	return (&legacyQueryStore{store: t}).SearchInstances(q)
+}
+
+// SearchRoutingConfigs 实现 QueryStore
+func (t *tenantStore) SearchRoutingConfigs(q *Query) (uint32, []*model.RoutingConfig, error) {
+	return (&legacyQueryStore{store: t}).SearchRoutingConfigs(q)
+}
+
+// SearchFaultDetectRules 实现 QueryStore
+func (t *tenantStore) SearchFaultDetectRules(q *Query) (uint32, []*model.FaultDetectRule, error) {
+	return (&legacyQueryStore{store: t}).SearchFaultDetectRules(q)
+}
+
+// SearchConfigFiles 实现 QueryStore
+func (t *tenantStore) SearchConfigFiles(q *Query) (uint32, []*model.ConfigFile, error) {
+	return (&legacyQueryStore{store: t}).SearchConfigFiles(q)
+}
+
+// SearchUsers 实现 QueryStore
+func (t *tenantStore) SearchUsers(q *Query) (uint32, []*model.User, error) {
+	return (&legacyQueryStore{store: t}).SearchUsers(q)
+}
+
+// SearchStrategies 实现 QueryStore
+func (t *tenantStore) SearchStrategies(q *Query) (uint32, []*model.StrategyDetail, error) {
+	return (&legacyQueryStore{store: t}).SearchStrategies(q)
+}
+
+// GetNamespacesPage 实现 CursorStore
+func (t *tenantStore) GetNamespacesPage(filter map[string][]string, token PageToken, limit uint32) (*Page[*model.Namespace], error) {
+	return (&offsetCursorStore{store: t}).GetNamespacesPage(filter, token, limit)
+}
+
+// GetServicesPage 实现 CursorStore
+func (t *tenantStore) GetServicesPage(serviceFilters map[string]string, serviceMetas map[string]string,
+	instanceFilters *model.InstanceArgs, token PageToken, limit uint32) (*Page[*model.Service], error) {
+	return (&offsetCursorStore{store: t}).GetServicesPage(serviceFilters, serviceMetas, instanceFilters, token, limit)
+}
+
+// GetServiceAliasesPage 实现 CursorStore
+func (t *tenantStore) GetServiceAliasesPage(filter map[string]string, token PageToken, limit uint32) (*Page[*model.ServiceAlias], error) {
+	return (&offsetCursorStore{store: t}).GetServiceAliasesPage(filter, token, limit)
+}
+
+// GetExpandInstancesPage 实现 CursorStore
+func (t *tenantStore) GetExpandInstancesPage(filter map[string]string, metaFilter map[string]string, token PageToken,
+	limit uint32) (*Page[*model.Instance], error) {
+	return (&offsetCursorStore{store: t}).GetExpandInstancesPage(filter, metaFilter, token, limit)
+}
+
+// GetRoutingConfigsPage 实现 CursorStore
+func (t *tenantStore) GetRoutingConfigsPage(filter map[string]string, token PageToken, limit uint32) (*Page[*model.RoutingConfig], error) {
+	return (&offsetCursorStore{store: t}).GetRoutingConfigsPage(filter, token, limit)
+}
+
+// GetExtendRateLimitsPage 实现 CursorStore
+func (t *tenantStore) GetExtendRateLimitsPage(query map[string]string, token PageToken, limit uint32) (*Page[*model.RateLimit], error) {
+	return (&offsetCursorStore{store: t}).GetExtendRateLimitsPage(query, token, limit)
+}
+
+// GetCircuitBreakerRulesPage 实现 CursorStore
+func (t *tenantStore) GetCircuitBreakerRulesPage(filter map[string]string, token PageToken,
+	limit uint32) (*Page[*model.CircuitBreakerRule], error) {
+	return (&offsetCursorStore{store: t}).GetCircuitBreakerRulesPage(filter, token, limit)
+}
+
+// GetFaultDetectRulesPage 实现 CursorStore
+func (t *tenantStore) GetFaultDetectRulesPage(filter map[string]string, token PageToken,
+	limit uint32) (*Page[*model.FaultDetectRule], error) {
+	return (&offsetCursorStore{store: t}).GetFaultDetectRulesPage(filter, token, limit)
+}
+
+// QueryConfigFilesPage 实现 CursorStore
+func (t *tenantStore) QueryConfigFilesPage(filter map[string]string, token PageToken, limit uint32) (*Page[*model.ConfigFile], error) {
+	return (&offsetCursorStore{store: t}).QueryConfigFilesPage(filter, token, limit)
+}
+
+// QueryConfigFileReleaseHistoriesPage 实现 CursorStore
+func (t *tenantStore) QueryConfigFileReleaseHistoriesPage(filter map[string]string, token PageToken,
+	limit uint32) (*Page[*model.ConfigFileReleaseHistory], error) {
+	return (&offsetCursorStore{store: t}).QueryConfigFileReleaseHistoriesPage(filter, token, limit)
+}
+
+// GetUsersPage 实现 CursorStore
+func (t *tenantStore) GetUsersPage(filters map[string]string, token PageToken, limit uint32) (*Page[*model.User], error) {
+	return (&offsetCursorStore{store: t}).GetUsersPage(filters, token, limit)
+}
+
+// GetGroupsPage 实现 CursorStore
+func (t *tenantStore) GetGroupsPage(filters map[string]string, token PageToken, limit uint32) (*Page[*model.UserGroup], error) {
+	return (&offsetCursorStore{store: t}).GetGroupsPage(filters, token, limit)
+}
+
+// GetStrategiesPage 实现 CursorStore
+func (t *tenantStore) GetStrategiesPage(filters map[string]string, token PageToken, limit uint32) (*Page[*model.StrategyDetail], error) {
+	return (&offsetCursorStore{store: t}).GetStrategiesPage(filters, token, limit)
+}
+
+// RangeMoreNamespaces 实现 StreamStore
+func (t *tenantStore) RangeMoreNamespaces(mtime time.Time, batchSize int, handler BatchHandler[*model.Namespace]) error {
+	return (&batchStreamStore{store: t}).RangeMoreNamespaces(mtime, batchSize, handler)
+}
+
+// RangeMoreServices 实现 StreamStore
+func (t *tenantStore) RangeMoreServices(mtime time.Time, firstUpdate bool, disableBusiness bool, needMeta bool,
+	batchSize int, handler BatchHandler[*model.Service]) error {
+	return (&batchStreamStore{store: t}).RangeMoreServices(mtime, firstUpdate, disableBusiness, needMeta, batchSize, handler)
+}
+
+// RangeMoreInstances 实现 StreamStore
+func (t *tenantStore) RangeMoreInstances(tx Tx, mtime time.Time, firstUpdate bool, needMeta bool, serviceID []string,
+	batchSize int, handler BatchHandler[*model.Instance]) error {
+	return (&batchStreamStore{store: t}).RangeMoreInstances(tx, mtime, firstUpdate, needMeta, serviceID, batchSize, handler)
+}
+
+// RangeMoreClients 实现 StreamStore
+func (t *tenantStore) RangeMoreClients(mtime time.Time, firstUpdate bool, batchSize int, handler BatchHandler[*model.Client]) error {
+	return (&batchStreamStore{store: t}).RangeMoreClients(mtime, firstUpdate, batchSize, handler)
+}
+
+// RangeMoreServiceContracts 实现 StreamStore
+func (t *tenantStore) RangeMoreServiceContracts(firstUpdate bool, mtime time.Time, batchSize int,
+	handler BatchHandler[*model.ServiceContract]) error {
+	return (&batchStreamStore{store: t}).RangeMoreServiceContracts(firstUpdate, mtime, batchSize, handler)
+}
+
+// RangeMoreGrayResources 实现 StreamStore
+func (t *tenantStore) RangeMoreGrayResources(firstUpdate bool, mtime time.Time, batchSize int,
+	handler BatchHandler[*model.GrayResource]) error {
+	return (&batchStreamStore{store: t}).RangeMoreGrayResources(firstUpdate, mtime, batchSize, handler)
+}
+
+// RangeRoutingConfigsForCache 实现 StreamStore
+func (t *tenantStore) RangeRoutingConfigsForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler BatchHandler[*model.RoutingConfig]) error {
+	return (&batchStreamStore{store: t}).RangeRoutingConfigsForCache(mtime, firstUpdate, batchSize, handler)
+}
+
+// RangeRoutingConfigsV2ForCache 实现 StreamStore
+func (t *tenantStore) RangeRoutingConfigsV2ForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler BatchHandler[*model.RouterConfig]) error {
+	return (&batchStreamStore{store: t}).RangeRoutingConfigsV2ForCache(mtime, firstUpdate, batchSize, handler)
+}
+
+// RangeRateLimitsForCache 实现 StreamStore
+func (t *tenantStore) RangeRateLimitsForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler BatchHandler[*model.RateLimit]) error {
+	return (&batchStreamStore{store: t}).RangeRateLimitsForCache(mtime, firstUpdate, batchSize, handler)
+}
+
+// RangeCircuitBreakerRulesForCache 实现 StreamStore
+func (t *tenantStore) RangeCircuitBreakerRulesForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler BatchHandler[*model.CircuitBreakerRule]) error {
+	return (&batchStreamStore{store: t}).RangeCircuitBreakerRulesForCache(mtime, firstUpdate, batchSize, handler)
+}
+
+// RangeFaultDetectRulesForCache 实现 StreamStore
+func (t *tenantStore) RangeFaultDetectRulesForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler BatchHandler[*model.FaultDetectRule]) error {
+	return (&batchStreamStore{store: t}).RangeFaultDetectRulesForCache(mtime, firstUpdate, batchSize, handler)
+}
+
+// RangeMoreConfigGroup 实现 StreamStore
+func (t *tenantStore) RangeMoreConfigGroup(firstUpdate bool, mtime time.Time, batchSize int,
+	handler BatchHandler[*model.ConfigFileGroup]) error {
+	return (&batchStreamStore{store: t}).RangeMoreConfigGroup(firstUpdate, mtime, batchSize, handler)
+}
+
+// RangeMoreReleaseFile 实现 StreamStore
+func (t *tenantStore) RangeMoreReleaseFile(firstUpdate bool, modifyTime time.Time, batchSize int,
+	handler BatchHandler[*model.ConfigFileRelease]) error {
+	return (&batchStreamStore{store: t}).RangeMoreReleaseFile(firstUpdate, modifyTime, batchSize, handler)
+}
+
+// RangeUsersForCache 实现 StreamStore
+func (t *tenantStore) RangeUsersForCache(mtime time.Time, firstUpdate bool, batchSize int, handler BatchHandler[*model.User]) error {
+	return (&batchStreamStore{store: t}).RangeUsersForCache(mtime, firstUpdate, batchSize, handler)
+}
+
+// RangeGroupsForCache 实现 StreamStore
+func (t *tenantStore) RangeGroupsForCache(mtime time.Time, firstUpdate bool, batchSize int, handler BatchHandler[*model.UserGroup]) error {
+	return (&batchStreamStore{store: t}).RangeGroupsForCache(mtime, firstUpdate, batchSize, handler)
+}
+
+// RangeStrategyDetailsForCache 实现 StreamStore
+func (t *tenantStore) RangeStrategyDetailsForCache(mtime time.Time, firstUpdate bool, batchSize int,
+	handler BatchHandler[*model.StrategyDetail]) error {
+	return (&batchStreamStore{store: t}).RangeStrategyDetailsForCache(mtime, firstUpdate, batchSize, handler)
+}
+
+// Watch 实现 WatchableStore
+func (t *tenantStore) Watch(ctx context.Context, kind ResourceKind, cursor Cursor) (Watcher, error) {
+	return NewPollingWatchStore(t).Watch(ctx, kind, cursor)
+}
@@ -0,0 +1,181 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package store_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/memory"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+func expectMismatch(t *testing.T, err error) {
+	t.Helper()
+	if store.Code(err) != store.TenantMismatchErr {
+		t.Fatalf("expect TenantMismatchErr, got %v", err)
+	}
+}
+
+func TestTenantStoreIsolation(t *testing.T) {
+	inner := memory.New()
+	a, b := store.NewTenantStore(inner, "a"), store.NewTenantStore(inner, "b")
+	addNamespace(t, a, "na")
+	addNamespace(t, b, "nb")
+	expectMismatch(t, a.AddNamespace(&model.Namespace{Name: "nx", Owner: "b"}))
+	if ns, _ := inner.GetNamespace("na"); ns == nil || ns.Owner != "a" {
+		t.Fatalf("owner is not filled: %+v", ns)
+	}
+
+	addService(t, a, "sa", "sa", "na")
+	addService(t, b, "sb", "sb", "nb")
+	expectMismatch(t, a.AddService(&model.Service{ID: "sx", Name: "sx", Namespace: "nb"}))
+	addInstance(t, a, "ia", "na", "sa")
+	addInstance(t, b, "ib", "nb", "sb")
+	expectMismatch(t, a.DeleteInstance("ib"))
+
+	if svc, _ := a.GetServiceByID("sb"); svc != nil {
+		t.Fatal("service of another tenant is visible")
+	}
+	if ns, _ := a.GetNamespace("nb"); ns != nil {
+		t.Fatal("namespace of another tenant is visible")
+	}
+	services, err := a.GetMoreServices(time.Time{}, true, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(services) != 1 || services["sa"] == nil {
+		t.Fatalf("expect only sa, got %v", services)
+	}
+	if count, _ := a.GetServicesCount(); count != 1 {
+		t.Fatalf("expect 1 service, got %d", count)
+	}
+	if count, _ := inner.GetServicesCount(); count != 2 {
+		t.Fatalf("expect 2 services in the inner store, got %d", count)
+	}
+	_, err = a.BatchCleanDeletedInstances(time.Second, 10)
+	expectMismatch(t, err)
+}
+
+func TestTenantStoreInstanceIDs(t *testing.T) {
+	inner := memory.New()
+	a, b := store.NewTenantStore(inner, "a"), store.NewTenantStore(inner, "b")
+	addNamespace(t, b, "nb")
+	addService(t, b, "sb", "sb", "nb")
+	addInstance(t, b, "1", "nb", "sb")
+
+	// 实例 ID 与存储插件一样按照 fmt.Sprint 的结果匹配，非字符串的 ID 同样需要校验租户
+	expectMismatch(t, a.BatchDeleteInstances([]interface{}{1}))
+	expectMismatch(t, a.BatchSetInstanceHealthStatus([]interface{}{1}, 0, "revision"))
+	expectMismatch(t, a.BatchSetInstanceIsolate([]interface{}{1}, 1, "revision"))
+}
+
+func TestTenantStorePagination(t *testing.T) {
+	inner := memory.New()
+	a := store.NewTenantStore(inner, "a")
+	for _, ns := range []string{"n1", "n2"} {
+		addNamespace(t, a, ns)
+		addService(t, a, "s-"+ns, "svc", ns)
+	}
+	if err := inner.AddNamespace(&model.Namespace{Name: "other", Owner: "b"}); err != nil {
+		t.Fatal(err)
+	}
+	addService(t, inner, "s-other", "svc", "other")
+	namespaces := []string{"n1", "n2", "other"}
+	for i := 0; i < 6; i++ {
+		ns := namespaces[i%len(namespaces)]
+		addInstance(t, inner, fmt.Sprintf("ins-%d", i), ns, "s-"+ns)
+	}
+
+	seen := make(map[string]bool)
+	for offset := uint32(0); offset < 4; offset++ {
+		total, items, err := a.GetExpandInstances(map[string]string{}, nil, offset, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != 4 || len(items) != 1 {
+			t.Fatalf("offset %d: expect 1 of 4 instances, got %d of %d", offset, len(items), total)
+		}
+		seen[items[0].Proto.GetId().GetValue()] = true
+	}
+	if len(seen) != 4 {
+		t.Fatalf("pages overlap: %v", seen)
+	}
+	if total, _, _ := a.GetExpandInstances(map[string]string{"namespace": "other"}, nil, 0, 10); total != 0 {
+		t.Fatalf("instances of another tenant are visible: %d", total)
+	}
+	if total, _, _ := a.GetExpandInstances(map[string]string{"namespace": "n*"}, nil, 0, 10); total != 4 {
+		t.Fatalf("expect 4 instances by prefix, got %d", total)
+	}
+
+	// 绕过视图写入的命名空间在下一次查询时增量加载
+	if err := inner.AddNamespace(&model.Namespace{Name: "n3", Owner: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	addService(t, inner, "s-n3", "svc", "n3")
+	if count, _ := a.GetServicesCount(); count != 3 {
+		t.Fatalf("expect 3 services, got %d", count)
+	}
+}
+
+func TestTenantStoreDeletedResources(t *testing.T) {
+	inner := memory.New()
+	a, b := store.NewTenantStore(inner, "a"), store.NewTenantStore(inner, "b")
+	addNamespace(t, b, "nb")
+	addNamespace(t, b, "gone")
+	addService(t, b, "sb", "sb", "nb")
+	if err := b.DeleteService("sb", "sb", "nb"); err != nil {
+		t.Fatal(err)
+	}
+	tx, err := b.CreateTransaction()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.DeleteNamespace("gone"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if ns, _ := inner.GetNamespace("gone"); ns != nil {
+		t.Fatal("namespace is not deleted")
+	}
+
+	// 已删除的命名空间以及服务仍然属于原来的租户
+	expectMismatch(t, a.UpdateNamespace(&model.Namespace{Name: "gone"}))
+	expectMismatch(t, a.UpdateNamespaceToken("gone", "token"))
+	expectMismatch(t, a.DeleteConfigFileGroup("gone", "group"))
+	expectMismatch(t, a.DeleteServiceAlias("alias", "gone"))
+	expectMismatch(t, a.AddService(&model.Service{ID: "sx", Name: "sx", Namespace: "gone"}))
+	expectMismatch(t, a.UpdateServiceToken("sb", "token", "revision"))
+	expectMismatch(t, a.DeleteRoutingConfig("sb"))
+	if err := a.UpdateServiceToken("", "token", "revision"); store.Code(err) != store.NotFoundService {
+		t.Fatalf("expect NotFoundService for an empty id, got %v", err)
+	}
+	namespaces, err := inner.GetMoreNamespaces(time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ns := range namespaces {
+		if ns.Owner != "b" {
+			t.Fatalf("owner of %s is rewritten to %s", ns.Name, ns.Owner)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package transfer
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+)
+
+// Checkpoint 记录已经完成迁移的资源类型，用于中断后继续迁移
+type Checkpoint interface {
+	// Done 返回资源类型是否已经完成迁移以及完成时源数据的校验和
+	Done(kind Kind) (checksum string, ok bool)
+	// MarkDone 记录资源类型已经完成迁移
+	MarkDone(kind Kind, checksum string) error
+}
+
+// NewFileCheckpoint 创建保存在本地 JSON 文件中的断点记录，文件不存在时从头开始迁移
+func NewFileCheckpoint(path string) (Checkpoint, error) {
+	c := &fileCheckpoint{path: path, done: map[Kind]string{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &c.done); err != nil {
+		return nil, store.NewStatusError(store.Unknown, "parse transfer checkpoint "+path+" failed: "+err.Error())
+	}
+	return c, nil
+}
+
+type fileCheckpoint struct {
+	lock sync.Mutex
+	path string
+	done map[Kind]string
+}
+
+// Done 返回资源类型是否已经完成迁移
+func (c *fileCheckpoint) Done(kind Kind) (string, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	checksum, ok := c.done[kind]
+	return checksum, ok
+}
+
+// MarkDone 记录资源类型已经完成迁移，先写临时文件再替换，避免中断时留下不完整的文件
+func (c *fileCheckpoint) MarkDone(kind Kind, checksum string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.done[kind] = checksum
+	data, err := json.MarshalIndent(c.done, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path)
+}
@@ -0,0 +1,473 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package transfer
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// steps 全部资源的迁移步骤，被依赖的资源排在前面
+var steps = []step{
+	namespaces,
+	services,
+	serviceAliases,
+	instances,
+	routingConfigs,
+	routerConfigs,
+	rateLimits,
+	circuitBreakerRules,
+	faultDetectRules,
+	serviceContracts,
+	configFileGroups,
+	configFiles,
+	configFileReleases,
+	configFileReleaseHistories,
+	configFileTemplates,
+	users,
+	userGroups,
+	strategies,
+}
+
+var namespaces = &resource[model.Namespace]{
+	name: KindNamespace,
+	list: func(s store.Store, _ store.Tx, _ uint32) ([]*model.Namespace, error) {
+		items, err := s.GetMoreNamespaces(time.Time{})
+		return valid(items, func(item *model.Namespace) bool { return item.Valid },
+			func(a, b *model.Namespace) bool { return a.Name < b.Name }), err
+	},
+	key: func(item *model.Namespace) string { return item.Name },
+	normalize: func(item *model.Namespace) *model.Namespace {
+		ret := *item
+		ret.Valid, ret.CreateTime, ret.ModifyTime = false, time.Time{}, time.Time{}
+		ret.ServiceExportTo = nilIfEmpty(ret.ServiceExportTo)
+		return &ret
+	},
+	create: each(func(s store.Store, item *model.Namespace) error { return s.AddNamespace(item) }),
+}
+
+var services = newServiceResource(KindService, false)
+
+// serviceAliases 别名需要在其指向的服务之后写入
+var serviceAliases = newServiceResource(KindServiceAlias, true)
+
+func newServiceResource(kind Kind, alias bool) *resource[model.Service] {
+	return &resource[model.Service]{
+		name: kind,
+		list: func(s store.Store, _ store.Tx, _ uint32) ([]*model.Service, error) {
+			items, err := s.GetMoreServices(time.Time{}, true, false, true)
+			return valid(values(items), func(item *model.Service) bool { return item.Valid && (item.Reference != "") == alias },
+				func(a, b *model.Service) bool { return a.ID < b.ID }), err
+		},
+		key: func(item *model.Service) string { return joinKey(item.Namespace, item.Name) },
+		normalize: func(item *model.Service) *model.Service {
+			ret := *item
+			ret.Valid, ret.CreateTime, ret.ModifyTime, ret.Ctime, ret.Mtime = false, time.Time{}, time.Time{}, 0, 0
+			ret.Meta = nilIfEmpty(ret.Meta)
+			ret.ExportTo = nilIfEmpty(ret.ExportTo)
+			if len(ret.ServicePorts) == 0 {
+				ret.ServicePorts = nil
+			}
+			return &ret
+		},
+		create: each(func(s store.Store, item *model.Service) error { return s.AddService(item) }),
+	}
+}
+
+var instances = &resource[model.Instance]{
+	name: KindInstance,
+	list: func(s store.Store, tx store.Tx, _ uint32) ([]*model.Instance, error) {
+		items, err := s.GetMoreInstances(tx, time.Time{}, true, true, nil)
+		return valid(values(items), func(item *model.Instance) bool { return item.Valid },
+			func(a, b *model.Instance) bool { return instanceID(a) < instanceID(b) }), err
+	},
+	key: func(item *model.Instance) string { return instanceID(item) },
+	normalize: func(item *model.Instance) *model.Instance {
+		ret := *item
+		ret.Valid, ret.ModifyTime = false, time.Time{}
+		if item.Proto != nil {
+			ret.Proto = proto.Clone(item.Proto).(*apiservice.Instance)
+			ret.Proto.Ctime, ret.Proto.Mtime = nil, nil
+			ret.Proto.Metadata = nilIfEmpty(ret.Proto.Metadata)
+		}
+		return &ret
+	},
+	create: func(s store.Store, items []*model.Instance) error { return s.BatchAddInstances(items) },
+}
+
+func instanceID(item *model.Instance) string {
+	return item.Proto.GetId().GetValue()
+}
+
+var routingConfigs = &resource[model.RoutingConfig]{
+	name: KindRoutingConfig,
+	list: func(s store.Store, _ store.Tx, _ uint32) ([]*model.RoutingConfig, error) {
+		items, err := s.GetRoutingConfigsForCache(time.Time{}, true)
+		return valid(items, func(item *model.RoutingConfig) bool { return item.Valid },
+			func(a, b *model.RoutingConfig) bool { return a.ID < b.ID }), err
+	},
+	key: func(item *model.RoutingConfig) string { return item.ID },
+	normalize: func(item *model.RoutingConfig) *model.RoutingConfig {
+		ret := *item
+		ret.Valid, ret.CreateTime, ret.ModifyTime = false, time.Time{}, time.Time{}
+		return &ret
+	},
+	create: each(func(s store.Store, item *model.RoutingConfig) error { return s.CreateRoutingConfig(item) }),
+}
+
+var routerConfigs = &resource[model.RouterConfig]{
+	name: KindRouterConfig,
+	list: func(s store.Store, _ store.Tx, _ uint32) ([]*model.RouterConfig, error) {
+		items, err := s.GetRoutingConfigsV2ForCache(time.Time{}, true)
+		return valid(items, func(item *model.RouterConfig) bool { return item.Valid },
+			func(a, b *model.RouterConfig) bool { return a.ID < b.ID }), err
+	},
+	key: func(item *model.RouterConfig) string { return item.ID },
+	normalize: func(item *model.RouterConfig) *model.RouterConfig {
+		ret := *item
+		ret.Valid, ret.CreateTime, ret.ModifyTime, ret.EnableTime = false, time.Time{}, time.Time{}, time.Time{}
+		return &ret
+	},
+	create: each(func(s store.Store, item *model.RouterConfig) error { return s.CreateRoutingConfigV2(item) }),
+}
+
+// 规则的 Proto 字段只是 Rule 字段的解析结果，不参与比较
+var rateLimits = &resource[model.RateLimit]{
+	name: KindRateLimit,
+	list: func(s store.Store, _ store.Tx, _ uint32) ([]*model.RateLimit, error) {
+		items, err := s.GetRateLimitsForCache(time.Time{}, true)
+		return valid(items, func(item *model.RateLimit) bool { return item.Valid },
+			func(a, b *model.RateLimit) bool { return a.ID < b.ID }), err
+	},
+	key: func(item *model.RateLimit) string { return item.ID },
+	normalize: func(item *model.RateLimit) *model.RateLimit {
+		ret := *item
+		ret.Proto = nil
+		ret.Valid, ret.CreateTime, ret.ModifyTime, ret.EnableTime = false, time.Time{}, time.Time{}, time.Time{}
+		return &ret
+	},
+	create: each(func(s store.Store, item *model.RateLimit) error { return s.CreateRateLimit(item) }),
+}
+
+var circuitBreakerRules = &resource[model.CircuitBreakerRule]{
+	name: KindCircuitBreakerRule,
+	list: func(s store.Store, _ store.Tx, _ uint32) ([]*model.CircuitBreakerRule, error) {
+		items, err := s.GetCircuitBreakerRulesForCache(time.Time{}, true)
+		return valid(items, func(item *model.CircuitBreakerRule) bool { return item.Valid },
+			func(a, b *model.CircuitBreakerRule) bool { return a.ID < b.ID }), err
+	},
+	key: func(item *model.CircuitBreakerRule) string { return item.ID },
+	normalize: func(item *model.CircuitBreakerRule) *model.CircuitBreakerRule {
+		ret := *item
+		ret.Proto = nil
+		ret.Valid, ret.CreateTime, ret.ModifyTime, ret.EnableTime = false, time.Time{}, time.Time{}, time.Time{}
+		return &ret
+	},
+	create: each(func(s store.Store, item *model.CircuitBreakerRule) error { return s.CreateCircuitBreakerRule(item) }),
+}
+
+var faultDetectRules = &resource[model.FaultDetectRule]{
+	name: KindFaultDetectRule,
+	list: func(s store.Store, _ store.Tx, _ uint32) ([]*model.FaultDetectRule, error) {
+		items, err := s.GetFaultDetectRulesForCache(time.Time{}, true)
+		return valid(items, func(item *model.FaultDetectRule) bool { return item.Valid },
+			func(a, b *model.FaultDetectRule) bool { return a.ID < b.ID }), err
+	},
+	key: func(item *model.FaultDetectRule) string { return item.ID },
+	normalize: func(item *model.FaultDetectRule) *model.FaultDetectRule {
+		ret := *item
+		ret.Proto = nil
+		ret.Valid, ret.CreateTime, ret.ModifyTime = false, time.Time{}, time.Time{}
+		return &ret
+	},
+	create: each(func(s store.Store, item *model.FaultDetectRule) error { return s.CreateFaultDetectRule(item) }),
+}
+
+var serviceContracts = &resource[model.ServiceContract]{
+	name: KindServiceContract,
+	list: func(s store.Store, _ store.Tx, _ uint32) ([]*model.ServiceContract, error) {
+		items, err := s.GetMoreServiceContracts(true, time.Time{})
+		return valid(items, func(item *model.ServiceContract) bool { return item.Valid },
+			func(a, b *model.ServiceContract) bool { return a.ID < b.ID }), err
+	},
+	key: func(item *model.ServiceContract) string { return item.ID },
+	normalize: func(item *model.ServiceContract) *model.ServiceContract {
+		ret := *item
+		ret.Valid, ret.CreateTime, ret.ModifyTime = false, time.Time{}, time.Time{}
+		ret.ClientInterfaces = normalizeInterfaces(item.ClientInterfaces)
+		ret.ManualInterfaces = normalizeInterfaces(item.ManualInterfaces)
+		return &ret
+	},
+	create: each(func(s store.Store, item *model.ServiceContract) error {
+		if err := s.CreateServiceContract(item); err != nil {
+			return err
+		}
+		if len(item.ClientInterfaces) == 0 && len(item.ManualInterfaces) == 0 {
+			return nil
+		}
+		return s.AppendServiceContractInterfaces(item)
+	}),
+}
+
+func normalizeInterfaces(items map[string]*model.InterfaceDescriptor) map[string]*model.InterfaceDescriptor {
+	if len(items) == 0 {
+		return nil
+	}
+	ret := make(map[string]*model.InterfaceDescriptor, len(items))
+	for id, item := range items {
+		copied := *item
+		copied.Valid, copied.CreateTime, copied.ModifyTime = false, time.Time{}, time.Time{}
+		ret[id] = &copied
+	}
+	return ret
+}
+
+var configFileGroups = &resource[model.ConfigFileGroup]{
+	name: KindConfigFileGroup,
+	list: func(s store.Store, _ store.Tx, _ uint32) ([]*model.ConfigFileGroup, error) {
+		items, err := s.GetMoreConfigGroup(true, time.Time{})
+		return valid(items, func(item *model.ConfigFileGroup) bool { return item.Valid },
+			func(a, b *model.ConfigFileGroup) bool { return a.Id < b.Id }), err
+	},
+	key: func(item *model.ConfigFileGroup) string { return joinKey(item.Namespace, item.Name) },
+	normalize: func(item *model.ConfigFileGroup) *model.ConfigFileGroup {
+		ret := *item
+		ret.Id, ret.Valid, ret.CreateTime, ret.ModifyTime = 0, false, time.Time{}, time.Time{}
+		ret.Metadata = nilIfEmpty(ret.Metadata)
+		return &ret
+	},
+	create: each(func(s store.Store, item *model.ConfigFileGroup) error {
+		_, err := s.CreateConfigFileGroup(item)
+		return err
+	}),
+}
+
+var configFiles = &resource[model.ConfigFile]{
+	name: KindConfigFile,
+	list: func(s store.Store, _ store.Tx, batchSize uint32) ([]*model.ConfigFile, error) {
+		ret := make([]*model.ConfigFile, 0)
+		for offset := uint32(0); ; offset += batchSize {
+			total, items, err := s.QueryConfigFiles(map[string]string{}, offset, batchSize)
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, items...)
+			if len(items) == 0 || offset+batchSize >= total {
+				break
+			}
+		}
+		return valid(ret, func(item *model.ConfigFile) bool { return item.Valid },
+			func(a, b *model.ConfigFile) bool { return a.Id < b.Id }), nil
+	},
+	key: func(item *model.ConfigFile) string { return joinKey(item.Namespace, item.Group, item.Name) },
+	normalize: func(item *model.ConfigFile) *model.ConfigFile {
+		ret := *item
+		ret.Id, ret.Valid, ret.CreateTime, ret.ModifyTime = 0, false, time.Time{}, time.Time{}
+		ret.Metadata = nilIfEmpty(ret.Metadata)
+		return &ret
+	},
+	create: inTx(func(s store.Store, tx store.Tx, item *model.ConfigFile) error {
+		return s.CreateConfigFileTx(tx, item)
+	}),
+}
+
+// configFileReleases 目标存储会重新编号发布的版本，创建后所有发布都处于激活状态，需要按照源数据修正
+var configFileReleases = &resource[model.ConfigFileRelease]{
+	name: KindConfigFileRelease,
+	list: func(s store.Store, tx store.Tx, _ uint32) ([]*model.ConfigFileRelease, error) {
+		items, err := s.GetMoreReleaseFile(true, time.Time{})
+		if err != nil {
+			return nil, err
+		}
+		ret := make([]*model.ConfigFileRelease, 0, len(items))
+		for _, item := range items {
+			if !item.Valid {
+				continue
+			}
+			// 增量接口不一定返回发布内容，这里重新读取完整的发布记录
+			full, err := getConfigFileRelease(s, tx, item.ConfigFileReleaseKey)
+			if err != nil {
+				return nil, err
+			}
+			if full != nil {
+				ret = append(ret, full)
+			}
+		}
+		return valid(ret, func(item *model.ConfigFileRelease) bool { return item.Valid },
+			func(a, b *model.ConfigFileRelease) bool { return a.Id < b.Id }), nil
+	},
+	key: func(item *model.ConfigFileRelease) string {
+		return joinKey(item.Namespace, item.Group, item.FileName, item.Name)
+	},
+	normalize: func(item *model.ConfigFileRelease) *model.ConfigFileRelease {
+		simple := *item.SimpleConfigFileRelease
+		key := *item.ConfigFileReleaseKey
+		key.Id = 0
+		simple.ConfigFileReleaseKey = &key
+		simple.Version, simple.Valid, simple.CreateTime, simple.ModifyTime = 0, false, time.Time{}, time.Time{}
+		simple.Metadata = nilIfEmpty(simple.Metadata)
+		if len(simple.BetaLabels) == 0 {
+			simple.BetaLabels = nil
+		}
+		return &model.ConfigFileRelease{SimpleConfigFileRelease: &simple, Content: item.Content}
+	},
+	create: inTx(func(s store.Store, tx store.Tx, item *model.ConfigFileRelease) error {
+		return s.CreateConfigFileReleaseTx(tx, item)
+	}),
+	reconcile: func(s store.Store, src, saved *model.ConfigFileRelease) error {
+		if src.Active == saved.Active {
+			return nil
+		}
+		tx, err := s.StartTx()
+		if err != nil {
+			return err
+		}
+		if src.Active {
+			err = s.ActiveConfigFileReleaseTx(tx, src)
+		} else {
+			err = s.InactiveConfigFileReleaseTx(tx, src)
+		}
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+		return tx.Commit()
+	},
+}
+
+func getConfigFileRelease(s store.Store, tx store.Tx, key *model.ConfigFileReleaseKey) (*model.ConfigFileRelease, error) {
+	if tx == nil {
+		return s.GetConfigFileRelease(key)
+	}
+	return s.GetConfigFileReleaseTx(tx, key)
+}
+
+// configFileReleaseHistories 发布历史没有业务主键，同一个键的多条历史按照 ID 顺序编号
+var configFileReleaseHistories = &resource[model.ConfigFileReleaseHistory]{
+	name: KindConfigFileReleaseHistory,
+	list: func(s store.Store, _ store.Tx, batchSize uint32) ([]*model.ConfigFileReleaseHistory, error) {
+		ret := make([]*model.ConfigFileReleaseHistory, 0)
+		filter := map[string]string{}
+		for {
+			// 历史按照 ID 倒序返回，使用 endId 向前翻页
+			_, items, err := s.QueryConfigFileReleaseHistories(filter, 0, batchSize)
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, items...)
+			if uint32(len(items)) < batchSize {
+				break
+			}
+			filter["endId"] = strconv.FormatUint(items[len(items)-1].Id, 10)
+		}
+		return valid(ret, func(item *model.ConfigFileReleaseHistory) bool { return item.Valid },
+			func(a, b *model.ConfigFileReleaseHistory) bool { return a.Id < b.Id }), nil
+	},
+	key: func(item *model.ConfigFileReleaseHistory) string {
+		return joinKey(item.Namespace, item.Group, item.FileName, item.Name, strconv.FormatUint(item.Version, 10))
+	},
+	normalize: func(item *model.ConfigFileReleaseHistory) *model.ConfigFileReleaseHistory {
+		ret := *item
+		ret.Id, ret.Valid, ret.CreateTime, ret.ModifyTime = 0, false, time.Time{}, time.Time{}
+		ret.Metadata = nilIfEmpty(ret.Metadata)
+		return &ret
+	},
+	create: each(func(s store.Store, item *model.ConfigFileReleaseHistory) error {
+		return s.CreateConfigFileReleaseHistory(item)
+	}),
+}
+
+var configFileTemplates = &resource[model.ConfigFileTemplate]{
+	name: KindConfigFileTemplate,
+	list: func(s store.Store, _ store.Tx, _ uint32) ([]*model.ConfigFileTemplate, error) {
+		items, err := s.QueryAllConfigFileTemplates()
+		return valid(items, func(item *model.ConfigFileTemplate) bool { return true },
+			func(a, b *model.ConfigFileTemplate) bool { return a.Id < b.Id }), err
+	},
+	key: func(item *model.ConfigFileTemplate) string { return item.Name },
+	normalize: func(item *model.ConfigFileTemplate) *model.ConfigFileTemplate {
+		ret := *item
+		ret.Id, ret.CreateTime, ret.ModifyTime = 0, time.Time{}, time.Time{}
+		return &ret
+	},
+	create: each(func(s store.Store, item *model.ConfigFileTemplate) error {
+		_, err := s.CreateConfigFileTemplate(item)
+		return err
+	}),
+}
+
+var users = &resource[model.User]{
+	name: KindUser,
+	list: func(s store.Store, _ store.Tx, _ uint32) ([]*model.User, error) {
+		items, err := s.GetUsersForCache(time.Time{}, true)
+		return valid(items, func(item *model.User) bool { return item.Valid },
+			func(a, b *model.User) bool { return a.ID < b.ID }), err
+	},
+	key: func(item *model.User) string { return item.ID },
+	normalize: func(item *model.User) *model.User {
+		ret := *item
+		ret.Valid, ret.CreateTime, ret.ModifyTime = false, time.Time{}, time.Time{}
+		return &ret
+	},
+	create: each(func(s store.Store, item *model.User) error { return s.AddUser(item) }),
+}
+
+var userGroups = &resource[model.UserGroup]{
+	name: KindUserGroup,
+	list: func(s store.Store, _ store.Tx, _ uint32) ([]*model.UserGroup, error) {
+		items, err := s.GetGroupsForCache(time.Time{}, true)
+		return valid(items, func(item *model.UserGroup) bool { return item.Valid },
+			func(a, b *model.UserGroup) bool { return a.ID < b.ID }), err
+	},
+	key: func(item *model.UserGroup) string { return item.ID },
+	normalize: func(item *model.UserGroup) *model.UserGroup {
+		ret := *item
+		ret.Valid, ret.CreateTime, ret.ModifyTime = false, time.Time{}, time.Time{}
+		ret.UserIds = sortedCopy(item.UserIds, func(a, b string) bool { return a < b })
+		return &ret
+	},
+	create: each(func(s store.Store, item *model.UserGroup) error { return s.AddGroup(item) }),
+}
+
+var strategies = &resource[model.StrategyDetail]{
+	name: KindStrategy,
+	list: func(s store.Store, _ store.Tx, _ uint32) ([]*model.StrategyDetail, error) {
+		items, err := s.GetStrategyDetailsForCache(time.Time{}, true)
+		return valid(items, func(item *model.StrategyDetail) bool { return item.Valid },
+			func(a, b *model.StrategyDetail) bool { return a.ID < b.ID }), err
+	},
+	key: func(item *model.StrategyDetail) string { return item.ID },
+	normalize: func(item *model.StrategyDetail) *model.StrategyDetail {
+		ret := *item
+		ret.Valid, ret.CreateTime, ret.ModifyTime = false, time.Time{}, time.Time{}
+		ret.Principals = sortedCopy(item.Principals, func(a, b model.Principal) bool {
+			return joinKey(a.PrincipalRole, a.PrincipalID) < joinKey(b.PrincipalRole, b.PrincipalID)
+		})
+		ret.Resources = sortedCopy(item.Resources, func(a, b model.StrategyResource) bool {
+			return a.ResType < b.ResType || (a.ResType == b.ResType && a.ResID < b.ResID)
+		})
+		return &ret
+	},
+	create: each(func(s store.Store, item *model.StrategyDetail) error { return s.AddStrategy(item) }),
+}
@@ -0,0 +1,334 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package transfer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+)
+
+// step 一类资源的迁移步骤
+type step interface {
+	kind() Kind
+	transfer(src, dst store.Store, write bool, o *options) (*KindReport, error)
+	backup(s store.Store, tx store.Tx, enc *json.Encoder, o *options) (*KindReport, error)
+	restore(dst store.Store, section *SnapshotSection, dec *json.Decoder, o *options) (*KindReport, error)
+}
+
+// resource 描述一类资源如何读取、比较以及写入
+type resource[T any] struct {
+	name Kind
+	// list 读取存储中全部有效的资源，返回顺序需要稳定，键相同的资源按照返回顺序编号；
+	// tx 不为空时，支持事务的查询需要在 tx 的读视图中进行
+	list func(s store.Store, tx store.Tx, batchSize uint32) ([]*T, error)
+	// key 资源在两个存储之间保持不变的唯一标识
+	key func(item *T) string
+	// normalize 返回去掉存储自动生成字段后的副本，用于计算摘要，不能修改 item
+	normalize func(item *T) *T
+	// create 将一批资源写入存储
+	create func(s store.Store, items []*T) error
+	// reconcile 可选，用于修正创建时无法指定的状态，src 为源资源，saved 为目标存储中已写入的资源
+	reconcile func(s store.Store, src, saved *T) error
+}
+
+// indexed 按照 key 索引的资源以及摘要
+type indexed[T any] struct {
+	keys    []string
+	items   map[string]*T
+	digests map[string]string
+}
+
+func (r *resource[T]) kind() Kind {
+	return r.name
+}
+
+func (r *resource[T]) load(s store.Store, tx store.Tx, batchSize uint32) (*indexed[T], error) {
+	items, err := r.list(s, tx, batchSize)
+	if err != nil {
+		return nil, err
+	}
+	return r.index(items)
+}
+
+func (r *resource[T]) index(items []*T) (*indexed[T], error) {
+	ret := &indexed[T]{
+		keys:    make([]string, 0, len(items)),
+		items:   make(map[string]*T, len(items)),
+		digests: make(map[string]string, len(items)),
+	}
+	seen := make(map[string]int, len(items))
+	for _, item := range items {
+		base := r.key(item)
+		key := base
+		if n := seen[base]; n > 0 {
+			key = fmt.Sprintf("%s#%d", base, n)
+		}
+		seen[base]++
+		digest, err := digest(r.normalize(item))
+		if err != nil {
+			return nil, err
+		}
+		ret.keys = append(ret.keys, key)
+		ret.items[key] = item
+		ret.digests[key] = digest
+	}
+	return ret, nil
+}
+
+func (r *resource[T]) transfer(src, dst store.Store, write bool, o *options) (*KindReport, error) {
+	source, err := r.load(src, nil, o.batchSize)
+	if err != nil {
+		return nil, err
+	}
+	return r.apply(dst, source, write, o)
+}
+
+func (r *resource[T]) backup(s store.Store, tx store.Tx, enc *json.Encoder, o *options) (*KindReport, error) {
+	source, err := r.load(s, tx, o.batchSize)
+	if err != nil {
+		return nil, err
+	}
+	report := &KindReport{Kind: r.name, Source: len(source.keys), Checksum: source.checksum()}
+	if err := enc.Encode(&SnapshotSection{Kind: r.name, Count: report.Source, Checksum: report.Checksum}); err != nil {
+		return report, err
+	}
+	for _, key := range source.keys {
+		line, err := encodeItem(source.items[key])
+		if err != nil {
+			return report, err
+		}
+		if err := enc.Encode(line); err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+func (r *resource[T]) restore(dst store.Store, section *SnapshotSection, dec *json.Decoder, o *options) (
+	*KindReport, error) {
+	items := make([]*T, 0, section.Count)
+	for i := 0; i < section.Count; i++ {
+		var line json.RawMessage
+		err := dec.Decode(&line)
+		item := new(T)
+		if err == nil {
+			err = decodeItem(line, item)
+		}
+		if err != nil {
+			return nil, snapshotError(fmt.Sprintf("read %s %d/%d", r.name, i+1, section.Count), err)
+		}
+		items = append(items, item)
+	}
+	source, err := r.index(items)
+	if err != nil {
+		return nil, err
+	}
+	if checksum := source.checksum(); checksum != section.Checksum {
+		return nil, store.NewStatusError(store.DataConflictErr,
+			fmt.Sprintf("snapshot section %s is corrupted, checksum %s, expect %s", r.name, checksum, section.Checksum))
+	}
+	return r.apply(dst, source, true, o)
+}
+
+// apply 将 source 中缺失的资源写入 dst（write 为 false 时只做比较），并校验 dst 中的数据与 source 一致
+func (r *resource[T]) apply(dst store.Store, source *indexed[T], write bool, o *options) (*KindReport, error) {
+	report := &KindReport{Kind: r.name, Source: len(source.keys), Checksum: source.checksum()}
+	if write && o.checkpoint != nil {
+		if checksum, ok := o.checkpoint.Done(r.name); ok && checksum == report.Checksum {
+			report.Resumed = true
+			return report, nil
+		}
+	}
+	target, err := r.load(dst, nil, o.batchSize)
+	if err != nil {
+		return report, err
+	}
+
+	pending := make([]*T, 0)
+	for _, key := range source.keys {
+		if _, ok := target.items[key]; !ok {
+			pending = append(pending, source.items[key])
+		}
+	}
+	report.Existing = len(source.keys) - len(pending)
+	if write {
+		if target, err = r.write(dst, source, target, pending, report, o); err != nil {
+			return report, err
+		}
+	}
+
+	for _, key := range source.keys {
+		if target.digests[key] != source.digests[key] {
+			report.Mismatched = append(report.Mismatched, key)
+		}
+	}
+	for _, key := range target.keys {
+		if _, ok := source.items[key]; !ok {
+			report.Extra++
+		}
+	}
+	if len(report.Mismatched) > 0 {
+		return report, mismatchError(r.name, report.Mismatched)
+	}
+	if write && o.checkpoint != nil {
+		if err := o.checkpoint.MarkDone(r.name, report.Checksum); err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+// write 分批写入缺失的资源并执行 reconcile，返回重新读取的目标存储数据
+func (r *resource[T]) write(dst store.Store, source, target *indexed[T], pending []*T, report *KindReport,
+	o *options) (*indexed[T], error) {
+	batchSize := int(o.batchSize)
+	for start := 0; start < len(pending); start += batchSize {
+		end := min(start+batchSize, len(pending))
+		if err := r.create(dst, pending[start:end]); err != nil {
+			return nil, err
+		}
+		report.Copied += end - start
+	}
+	if report.Copied == 0 && r.reconcile == nil {
+		return target, nil
+	}
+	target, err := r.load(dst, nil, o.batchSize)
+	if err != nil || r.reconcile == nil {
+		return target, err
+	}
+
+	reconciled := false
+	for _, key := range source.keys {
+		saved, ok := target.items[key]
+		if !ok || target.digests[key] == source.digests[key] {
+			continue
+		}
+		if err := r.reconcile(dst, source.items[key], saved); err != nil {
+			return nil, err
+		}
+		reconciled = true
+	}
+	if !reconciled {
+		return target, nil
+	}
+	return r.load(dst, nil, o.batchSize)
+}
+
+// checksum 源存储中该类资源的整体校验和，与资源的读取顺序无关
+func (i *indexed[T]) checksum() string {
+	keys := append([]string(nil), i.keys...)
+	sort.Strings(keys)
+	h := sha256.New()
+	for _, key := range keys {
+		_, _ = h.Write([]byte(key + " " + i.digests[key] + "\n"))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// digest 计算单个资源的摘要
+func digest(item any) (string, error) {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return "", store.NewStatusError(store.Unknown, "marshal resource for checksum failed: "+err.Error())
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// joinKey 拼接资源的唯一标识
+func joinKey(parts ...string) string {
+	return strings.Join(parts, "/")
+}
+
+// each 逐条写入资源
+func each[T any](create func(s store.Store, item *T) error) func(s store.Store, items []*T) error {
+	return func(s store.Store, items []*T) error {
+		for _, item := range items {
+			if err := create(s, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// inTx 在同一个事务中写入一批资源
+func inTx[T any](create func(s store.Store, tx store.Tx, item *T) error) func(s store.Store, items []*T) error {
+	return func(s store.Store, items []*T) error {
+		tx, err := s.StartTx()
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			if err := create(s, tx, item); err != nil {
+				_ = tx.Rollback()
+				return err
+			}
+		}
+		return tx.Commit()
+	}
+}
+
+// valid 过滤掉已经被逻辑删除的资源，并按照 less 排序
+func valid[T any](items []*T, isValid func(item *T) bool, less func(a, b *T) bool) []*T {
+	ret := make([]*T, 0, len(items))
+	for _, item := range items {
+		if isValid(item) {
+			ret = append(ret, item)
+		}
+	}
+	sort.SliceStable(ret, func(i, j int) bool {
+		return less(ret[i], ret[j])
+	})
+	return ret
+}
+
+// values 将 map 形式返回的资源转换为列表
+func values[T any](items map[string]*T) []*T {
+	ret := make([]*T, 0, len(items))
+	for _, item := range items {
+		ret = append(ret, item)
+	}
+	return ret
+}
+
+// nilIfEmpty 部分存储会将空 map 读取为 nil，比较前统一处理
+func nilIfEmpty[M ~map[K]V, K comparable, V any](m M) M {
+	if len(m) == 0 {
+		return nil
+	}
+	return m
+}
+
+// sortedCopy 复制并排序列表，空列表统一为 nil
+func sortedCopy[T any](items []T, less func(a, b T) bool) []T {
+	if len(items) == 0 {
+		return nil
+	}
+	ret := append([]T(nil), items...)
+	sort.Slice(ret, func(i, j int) bool {
+		return less(ret[i], ret[j])
+	})
+	return ret
+}
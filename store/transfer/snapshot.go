@@ -0,0 +1,257 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package transfer
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+)
+
+const (
+	// SnapshotFormat 快照第一行中的格式名称
+	SnapshotFormat = "polaris-store-snapshot"
+	// SnapshotVersion 当前写入的快照格式版本，Restore 只能读取不高于该版本的快照
+	SnapshotVersion uint32 = 1
+)
+
+// SnapshotHeader 快照的第一行
+//
+// 快照采用 JSON Lines 格式，与具体的存储实现无关：第一行为 SnapshotHeader，之后按照 Kinds 的顺序，
+// 每一类资源先写一行 SnapshotSection，随后的 Count 行为该类资源的 model 对象，每行一个。
+// model 对象使用 encoding/json 编码，其中 protobuf 类型的 Proto 字段使用 protojson 编码。
+type SnapshotHeader struct {
+	Format     string    `json:"format"`
+	Version    uint32    `json:"version"`
+	CreateTime time.Time `json:"createTime"`
+	// Store 生成快照的存储插件名称，仅用于展示
+	Store string `json:"store"`
+	Kinds []Kind `json:"kinds"`
+}
+
+// SnapshotSection 一类资源在快照中的说明行
+type SnapshotSection struct {
+	Kind  Kind `json:"kind"`
+	Count int  `json:"count"`
+	// Checksum 与 KindReport.Checksum 的算法一致，Restore 时用于检查快照是否损坏
+	Checksum string `json:"checksum"`
+}
+
+// Backup 将 s 中的全部资源写入快照
+//
+// 快照不是某一时刻的一致视图：store.Store 只为实例以及配置发布内容提供了事务查询，这两类查询基于
+// StartReadTx 开启的事务中创建的读视图，其余资源在备份期间直接读取，期间的写入可能只有部分出现在快照中，
+// 因此建议在备份期间停止写入。WithCheckpoint 对备份不生效。
+func Backup(s store.Store, w io.Writer, opts ...Option) (*Report, error) {
+	o := newOptions(opts)
+	tx, err := s.StartReadTx()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	if err := tx.CreateReadView(); err != nil {
+		return nil, err
+	}
+
+	selected := make([]step, 0, len(steps))
+	header := &SnapshotHeader{
+		Format:     SnapshotFormat,
+		Version:    SnapshotVersion,
+		CreateTime: time.Now(),
+		Store:      s.Name(),
+	}
+	for _, item := range steps {
+		if o.selected(item.kind()) {
+			selected = append(selected, item)
+			header.Kinds = append(header.Kinds, item.kind())
+		}
+	}
+
+	buf := bufio.NewWriter(w)
+	enc := json.NewEncoder(buf)
+	if err := enc.Encode(header); err != nil {
+		return nil, err
+	}
+	report := &Report{}
+	for _, item := range selected {
+		kindReport, err := item.backup(s, tx, enc, o)
+		if err := report.add(kindReport, err, o); err != nil {
+			return report, err
+		}
+	}
+	return report, buf.Flush()
+}
+
+// Restore 将快照中的资源写入 s，s 中已经存在的资源会被跳过，因此可以重复执行；
+// 写入完成后会重新读取 s 并与快照的校验和进行比较
+func Restore(s store.Store, r io.Reader, opts ...Option) (*Report, error) {
+	o := newOptions(opts)
+	dec := json.NewDecoder(bufio.NewReader(r))
+	header := &SnapshotHeader{}
+	if err := dec.Decode(header); err != nil {
+		return nil, snapshotError("read header", err)
+	}
+	if header.Format != SnapshotFormat {
+		return nil, store.NewStatusError(store.EmptyParamsErr, "unknown snapshot format: "+header.Format)
+	}
+	if header.Version == 0 || header.Version > SnapshotVersion {
+		return nil, store.NewStatusError(store.SchemaVersionMismatch,
+			fmt.Sprintf("snapshot version %d is not supported, latest supported version is %d", header.Version, SnapshotVersion))
+	}
+
+	report := &Report{}
+	for _, kind := range header.Kinds {
+		section := &SnapshotSection{}
+		if err := dec.Decode(section); err != nil {
+			return report, snapshotError("read section "+string(kind), err)
+		}
+		if section.Kind != kind {
+			return report, store.NewStatusError(store.EmptyParamsErr,
+				fmt.Sprintf("snapshot section %s is out of order, expect %s", section.Kind, kind))
+		}
+		item := lookup(kind)
+		if item == nil {
+			return report, store.NewStatusError(store.EmptyParamsErr, "unknown snapshot resource kind: "+string(kind))
+		}
+		if !o.selected(kind) {
+			if err := skipSection(section, dec); err != nil {
+				return report, err
+			}
+			continue
+		}
+		kindReport, err := item.restore(s, section, dec, o)
+		if err := report.add(kindReport, err, o); err != nil {
+			return report, err
+		}
+	}
+	if dec.More() {
+		return report, store.NewStatusError(store.EmptyParamsErr, "unexpected data after the last snapshot section")
+	}
+	return report, nil
+}
+
+// protoField model 中 protobuf 类型字段的名称
+const protoField = "Proto"
+
+var messageType = reflect.TypeOf((*proto.Message)(nil)).Elem()
+
+// messageOf 返回 item 中 protobuf 类型的 Proto 字段，item 需要是结构体指针
+func messageOf(item any) (reflect.Value, bool) {
+	v := reflect.ValueOf(item).Elem()
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+	field := v.FieldByName(protoField)
+	if !field.IsValid() || !field.Type().Implements(messageType) || field.Kind() != reflect.Ptr {
+		return reflect.Value{}, false
+	}
+	return field, true
+}
+
+// encodeItem 编码快照中的一行资源，Proto 字段使用 protojson 编码
+func encodeItem(item any) (json.RawMessage, error) {
+	field, ok := messageOf(item)
+	if !ok || field.IsNil() {
+		return json.Marshal(item)
+	}
+	data, err := protojson.Marshal(proto.MessageV2(field.Interface()))
+	if err != nil {
+		return nil, err
+	}
+	copied := reflect.New(reflect.TypeOf(item).Elem())
+	copied.Elem().Set(reflect.ValueOf(item).Elem())
+	copied.Elem().FieldByName(protoField).Set(reflect.Zero(field.Type()))
+	raw, err := json.Marshal(copied.Interface())
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	fields[protoField] = data
+	return json.Marshal(fields)
+}
+
+// decodeItem 解码快照中的一行资源，Proto 字段使用 protojson 解码
+func decodeItem(line json.RawMessage, item any) error {
+	field, ok := messageOf(item)
+	if !ok {
+		return json.Unmarshal(line, item)
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(line, &fields); err != nil {
+		return err
+	}
+	data, exist := fields[protoField]
+	delete(fields, protoField)
+	rest, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(rest, item); err != nil {
+		return err
+	}
+	if !exist || string(data) == "null" {
+		return nil
+	}
+	msg := reflect.New(field.Type().Elem())
+	if err := protojson.Unmarshal(data, proto.MessageV2(msg.Interface())); err != nil {
+		return err
+	}
+	field.Set(msg)
+	return nil
+}
+
+// lookup 返回资源类型对应的迁移步骤
+func lookup(kind Kind) step {
+	for _, item := range steps {
+		if item.kind() == kind {
+			return item
+		}
+	}
+	return nil
+}
+
+// skipSection 跳过未选择的资源
+func skipSection(section *SnapshotSection, dec *json.Decoder) error {
+	for i := 0; i < section.Count; i++ {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return snapshotError(fmt.Sprintf("skip %s %d/%d", section.Kind, i+1, section.Count), err)
+		}
+	}
+	return nil
+}
+
+func snapshotError(action string, err error) error {
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return store.NewStatusError(store.EmptyParamsErr, "invalid snapshot, "+action+": "+err.Error())
+}
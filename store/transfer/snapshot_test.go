@@ -0,0 +1,105 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package transfer_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/memory"
+	"github.com/polarismesh/polaris-plugin-api/store/transfer"
+)
+
+func backup(t *testing.T) (store.Store, string) {
+	t.Helper()
+	src := memory.New()
+	populate(t, src)
+	buf := &bytes.Buffer{}
+	_, err := transfer.Backup(src, buf)
+	must(t, err)
+	return src, buf.String()
+}
+
+func TestBackupRestore(t *testing.T) {
+	src, snapshot := backup(t)
+	dst := memory.New()
+	_, err := transfer.Restore(dst, strings.NewReader(snapshot))
+	must(t, err)
+	_, err = transfer.Verify(src, dst)
+	must(t, err)
+
+	report, err := transfer.Restore(dst, strings.NewReader(snapshot))
+	must(t, err)
+	for _, kind := range report.Kinds {
+		if kind.Copied != 0 {
+			t.Errorf("kind %s is restored again", kind.Kind)
+		}
+	}
+
+	dst = memory.New()
+	report, err = transfer.Restore(dst, strings.NewReader(snapshot), transfer.WithKinds(transfer.KindUser))
+	must(t, err)
+	if len(report.Kinds) != 1 || report.Kinds[0].Copied != 2 {
+		t.Fatalf("expect only users to be restored: %+v", report.Kinds)
+	}
+}
+
+func TestBackupProtojson(t *testing.T) {
+	src, snapshot := backup(t)
+	// Proto 字段使用 protojson 编码，包装类型编码为普通的值
+	if !strings.Contains(snapshot, `"host":"127.0.0.1"`) {
+		t.Fatalf("instance proto is not encoded with protojson: %s", snapshot)
+	}
+	dst := memory.New()
+	_, err := transfer.Restore(dst, strings.NewReader(snapshot), transfer.WithKinds(transfer.KindNamespace,
+		transfer.KindService, transfer.KindInstance))
+	must(t, err)
+	expect, _ := src.GetInstance("ins-1")
+	ins, err := dst.GetInstance("ins-1")
+	must(t, err)
+	if ins == nil || ins.Proto.GetHost().GetValue() != expect.Proto.GetHost().GetValue() ||
+		ins.Proto.GetMetadata()["id"] != "ins-1" {
+		t.Fatalf("instance is not restored: %+v", ins)
+	}
+}
+
+func TestRestoreInvalidSnapshot(t *testing.T) {
+	_, snapshot := backup(t)
+	cases := []struct {
+		name     string
+		snapshot string
+		code     store.StatusCode
+	}{
+		{name: "truncated", snapshot: snapshot[:len(snapshot)/2], code: store.EmptyParamsErr},
+		{name: "corrupted", snapshot: strings.Replace(snapshot, `"token"`, `"tampered"`, 1), code: store.DataConflictErr},
+		{name: "version", snapshot: strings.Replace(snapshot, `"version":1`, `"version":99`, 1),
+			code: store.SchemaVersionMismatch},
+		{name: "format", snapshot: strings.Replace(snapshot, transfer.SnapshotFormat, "unknown", 1),
+			code: store.EmptyParamsErr},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := transfer.Restore(memory.New(), strings.NewReader(c.snapshot))
+			if store.Code(err) != c.code {
+				t.Fatalf("expect code %v, got %v", c.code, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package transfer 提供在两个 store.Store 插件之间迁移全部资源的能力
+//
+// 资源按照依赖顺序逐类复制：命名空间、服务、服务别名、实例、路由/限流/熔断/探测规则、服务契约、
+// 配置分组/文件/发布/发布历史/模板、用户、用户组以及鉴权策略。每一类资源复制完成后都会重新读取目标存储，
+// 逐条比较摘要并计算整体校验和，任何差异都会以 store.DataConflictErr 返回。
+//
+// 目标存储中已经存在的资源会被跳过，因此中断后重复执行 Copy 即可继续迁移；配合 Checkpoint 使用时，
+// 源数据未发生变化的已完成资源会直接跳过。目标存储会为配置分组、文件、发布、发布历史以及模板重新分配 ID，
+// 配置发布的版本号也会在目标存储中重新编号，这些字段不参与校验。
+//
+// Backup 以及 Restore 使用与存储实现无关的 JSON Lines 快照格式（见 SnapshotHeader）备份和恢复全部资源，
+// 恢复时同样会跳过已经存在的资源并进行校验。
+package transfer
+
+import (
+	"fmt"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+)
+
+// Kind 资源类型
+type Kind string
+
+const (
+	KindNamespace                Kind = "namespace"
+	KindService                  Kind = "service"
+	KindServiceAlias             Kind = "service_alias"
+	KindInstance                 Kind = "instance"
+	KindRoutingConfig            Kind = "routing_config"
+	KindRouterConfig             Kind = "router_config"
+	KindRateLimit                Kind = "ratelimit"
+	KindCircuitBreakerRule       Kind = "circuitbreaker_rule"
+	KindFaultDetectRule          Kind = "fault_detect_rule"
+	KindServiceContract          Kind = "service_contract"
+	KindConfigFileGroup          Kind = "config_file_group"
+	KindConfigFile               Kind = "config_file"
+	KindConfigFileRelease        Kind = "config_file_release"
+	KindConfigFileReleaseHistory Kind = "config_file_release_history"
+	KindConfigFileTemplate       Kind = "config_file_template"
+	KindUser                     Kind = "user"
+	KindUserGroup                Kind = "user_group"
+	KindStrategy                 Kind = "auth_strategy"
+)
+
+// Kinds 返回全部资源类型，顺序即复制顺序
+func Kinds() []Kind {
+	ret := make([]Kind, 0, len(steps))
+	for _, item := range steps {
+		ret = append(ret, item.kind())
+	}
+	return ret
+}
+
+const (
+	// defaultBatchSize 默认每批写入以及分页读取的数量
+	defaultBatchSize = 100
+)
+
+// Option Copy 以及 Verify 的可选配置
+type Option func(o *options)
+
+type options struct {
+	batchSize  uint32
+	kinds      map[Kind]struct{}
+	checkpoint Checkpoint
+	progress   func(report KindReport)
+}
+
+// WithBatchSize 设置每批写入以及分页读取的数量，默认 100
+func WithBatchSize(size uint32) Option {
+	return func(o *options) {
+		if size > 0 {
+			o.batchSize = size
+		}
+	}
+}
+
+// WithKinds 只处理指定类型的资源，默认处理全部资源
+func WithKinds(kinds ...Kind) Option {
+	return func(o *options) {
+		o.kinds = make(map[Kind]struct{}, len(kinds))
+		for _, kind := range kinds {
+			o.kinds[kind] = struct{}{}
+		}
+	}
+}
+
+// WithCheckpoint 设置断点记录，Copy 会跳过源数据校验和与断点一致的资源类型
+func WithCheckpoint(checkpoint Checkpoint) Option {
+	return func(o *options) {
+		o.checkpoint = checkpoint
+	}
+}
+
+// WithProgress 设置每一类资源处理完成后的回调
+func WithProgress(progress func(report KindReport)) Option {
+	return func(o *options) {
+		o.progress = progress
+	}
+}
+
+// KindReport 一类资源的迁移结果
+type KindReport struct {
+	Kind Kind
+	// Source 源存储中的资源数量
+	Source int
+	// Copied 本次写入目标存储的资源数量
+	Copied int
+	// Existing 目标存储中已经存在而跳过写入的资源数量
+	Existing int
+	// Extra 只存在于目标存储中的资源数量，不影响校验结果
+	Extra int
+	// Resumed 源数据与断点记录一致，整类资源被跳过
+	Resumed bool
+	// Checksum 源存储中该类资源的校验和
+	Checksum string
+	// Mismatched 在目标存储中缺失或者内容不一致的资源
+	Mismatched []string
+}
+
+// Report 迁移结果
+type Report struct {
+	Kinds []KindReport
+}
+
+// Copy 将 src 中的全部资源复制到 dst，并校验两边的数据是否一致
+func Copy(src, dst store.Store, opts ...Option) (*Report, error) {
+	return run(src, dst, true, opts)
+}
+
+// Verify 只校验 src 中的资源是否都已经完整地存在于 dst 中，不做任何写入
+func Verify(src, dst store.Store, opts ...Option) (*Report, error) {
+	return run(src, dst, false, opts)
+}
+
+func run(src, dst store.Store, write bool, opts []Option) (*Report, error) {
+	o := newOptions(opts)
+	report := &Report{}
+	for _, item := range steps {
+		if !o.selected(item.kind()) {
+			continue
+		}
+		kindReport, err := item.transfer(src, dst, write, o)
+		if err := report.add(kindReport, err, o); err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{batchSize: defaultBatchSize}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+func (o *options) selected(kind Kind) bool {
+	if o.kinds == nil {
+		return true
+	}
+	_, ok := o.kinds[kind]
+	return ok
+}
+
+// add 记录一类资源的处理结果并通知进度回调，返回处理时的错误
+func (r *Report) add(report *KindReport, err error, o *options) error {
+	if report != nil {
+		r.Kinds = append(r.Kinds, *report)
+		if o.progress != nil {
+			o.progress(*report)
+		}
+	}
+	return err
+}
+
+// mismatchError 构造校验失败的错误，只列出前几个不一致的资源
+func mismatchError(kind Kind, keys []string) error {
+	const maxKeys = 5
+	shown := keys
+	if len(shown) > maxKeys {
+		shown = shown[:maxKeys]
+	}
+	return store.NewStatusError(store.DataConflictErr,
+		fmt.Sprintf("%s: %d resources differ between source and target store: %v", kind, len(keys), shown))
+}
@@ -0,0 +1,135 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package transfer_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	apifault "github.com/polarismesh/specification/source/go/api/v1/fault_tolerance"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/memory"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+	"github.com/polarismesh/polaris-plugin-api/store/transfer"
+)
+
+func must(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// populate 为每一类资源写入测试数据
+func populate(t *testing.T, s store.Store) {
+	t.Helper()
+	must(t, s.AddNamespace(&model.Namespace{Name: "ns", Owner: "polaris", Token: "token"}))
+	must(t, s.AddService(&model.Service{ID: "svc", Name: "svc", Namespace: "ns", Meta: map[string]string{"k": "v"}}))
+	must(t, s.AddService(&model.Service{ID: "alias", Name: "alias", Namespace: "ns", Reference: "svc"}))
+	for _, id := range []string{"ins-1", "ins-2"} {
+		must(t, s.AddInstance(&model.Instance{ServiceID: "svc", Proto: &apiservice.Instance{
+			Id:        wrapperspb.String(id),
+			Service:   wrapperspb.String("svc"),
+			Namespace: wrapperspb.String("ns"),
+			Host:      wrapperspb.String("127.0.0.1"),
+			Port:      wrapperspb.UInt32(8080),
+			Metadata:  map[string]string{"id": id},
+		}}))
+	}
+	must(t, s.CreateRoutingConfig(&model.RoutingConfig{ID: "svc", InBounds: "[]", OutBounds: "[]"}))
+	must(t, s.CreateRoutingConfigV2(&model.RouterConfig{ID: "router", Name: "router", Namespace: "ns", Config: "{}"}))
+	must(t, s.CreateRateLimit(&model.RateLimit{ID: "ratelimit", ServiceID: "svc", Name: "ratelimit", Rule: "{}",
+		Proto: &apitraffic.Rule{}}))
+	must(t, s.CreateCircuitBreakerRule(&model.CircuitBreakerRule{ID: "circuitbreaker", Name: "circuitbreaker",
+		Namespace: "ns", Rule: "{}", Proto: &apifault.CircuitBreakerRule{}}))
+	must(t, s.CreateFaultDetectRule(&model.FaultDetectRule{ID: "faultdetect", Name: "faultdetect", Namespace: "ns",
+		Rule: "{}"}))
+	must(t, s.CreateServiceContract(&model.ServiceContract{ID: "contract", Namespace: "ns", Service: "svc",
+		Name: "contract", Protocol: "http"}))
+	_, err := s.CreateConfigFileGroup(&model.ConfigFileGroup{Name: "group", Namespace: "ns"})
+	must(t, err)
+	tx, err := s.StartTx()
+	must(t, err)
+	must(t, s.CreateConfigFileTx(tx, &model.ConfigFile{Name: "file", Namespace: "ns", Group: "group", Content: "v1"}))
+	release := model.NewConfigFileRelease()
+	release.ConfigFileReleaseKey = &model.ConfigFileReleaseKey{Name: "release", Namespace: "ns", Group: "group",
+		FileName: "file"}
+	release.Content = "v1"
+	must(t, s.CreateConfigFileReleaseTx(tx, release))
+	must(t, tx.Commit())
+	must(t, s.CreateConfigFileReleaseHistory(&model.ConfigFileReleaseHistory{Name: "release", Namespace: "ns",
+		Group: "group", FileName: "file", Content: "v1", Version: 1}))
+	_, err = s.CreateConfigFileTemplate(&model.ConfigFileTemplate{Name: "template", Content: "{}"})
+	must(t, err)
+	must(t, s.AddUser(&model.User{ID: "owner", Name: "owner"}))
+	must(t, s.AddUser(&model.User{ID: "user", Name: "user", Owner: "owner"}))
+	must(t, s.AddGroup(&model.UserGroup{ID: "group", Name: "group", UserIds: []string{"user"}}))
+	must(t, s.AddStrategy(&model.StrategyDetail{ID: "strategy", Name: "strategy",
+		Principals: []model.Principal{{StrategyID: "strategy", PrincipalID: "user", PrincipalRole: "1"}},
+		Resources:  []model.StrategyResource{{StrategyID: "strategy", ResID: "ns"}}}))
+}
+
+func TestCopy(t *testing.T) {
+	src, dst := memory.New(), memory.New()
+	populate(t, src)
+
+	if _, err := transfer.Verify(src, dst); store.Code(err) != store.DataConflictErr {
+		t.Fatalf("verify against an empty store should fail, got %v", err)
+	}
+	report, err := transfer.Copy(src, dst, transfer.WithBatchSize(1))
+	must(t, err)
+	for _, kind := range report.Kinds {
+		if kind.Source == 0 || kind.Copied != kind.Source {
+			t.Errorf("kind %s: copied %d of %d", kind.Kind, kind.Copied, kind.Source)
+		}
+	}
+	_, err = transfer.Verify(src, dst)
+	must(t, err)
+
+	// 目标存储中已经存在的资源会被跳过
+	report, err = transfer.Copy(src, dst)
+	must(t, err)
+	for _, kind := range report.Kinds {
+		if kind.Copied != 0 || kind.Existing != kind.Source {
+			t.Errorf("kind %s is copied again: %+v", kind.Kind, kind)
+		}
+	}
+}
+
+func TestCopyCheckpoint(t *testing.T) {
+	src, dst := memory.New(), memory.New()
+	populate(t, src)
+	checkpoint, err := transfer.NewFileCheckpoint(filepath.Join(t.TempDir(), "checkpoint.json"))
+	must(t, err)
+
+	_, err = transfer.Copy(src, dst, transfer.WithKinds(transfer.KindNamespace), transfer.WithCheckpoint(checkpoint))
+	must(t, err)
+	report, err := transfer.Copy(src, dst, transfer.WithCheckpoint(checkpoint))
+	must(t, err)
+	for _, kind := range report.Kinds {
+		if resumed := kind.Kind == transfer.KindNamespace; kind.Resumed != resumed {
+			t.Errorf("kind %s: resumed %v", kind.Kind, kind.Resumed)
+		}
+	}
+	_, err = transfer.Verify(src, dst)
+	must(t, err)
+}
@@ -0,0 +1,313 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package store
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// IsolationLevel 事务的隔离级别
+type IsolationLevel int
+
+const (
+	// IsolationDefault 使用存储默认的隔离级别
+	IsolationDefault IsolationLevel = iota
+	// IsolationReadCommitted 读已提交
+	IsolationReadCommitted
+	// IsolationRepeatableRead 可重复读
+	IsolationRepeatableRead
+	// IsolationSerializable 串行化
+	IsolationSerializable
+)
+
+// String 返回隔离级别的名称
+func (l IsolationLevel) String() string {
+	switch l {
+	case IsolationDefault:
+		return "default"
+	case IsolationReadCommitted:
+		return "read-committed"
+	case IsolationRepeatableRead:
+		return "repeatable-read"
+	case IsolationSerializable:
+		return "serializable"
+	}
+	return fmt.Sprintf("isolation(%d)", int(l))
+}
+
+// TxOptions 开启事务的参数
+type TxOptions struct {
+	// Isolation 事务的隔离级别，存储无法提供不低于该级别的隔离时 BeginTx 返回错误
+	Isolation IsolationLevel
+	// ReadOnly 是否为只读事务
+	ReadOnly bool
+}
+
+// UnifiedTx 统一的事务接口，同时满足 Tx 以及 Transaction，可以直接作为 XxxTx 方法的参数，
+// 行锁在事务结束时释放；保存点可以嵌套，同名的保存点以最近创建的为准
+type UnifiedTx interface {
+	Tx
+	Transaction
+	// Savepoint 在事务中创建保存点
+	Savepoint(name string) error
+	// RollbackToSavepoint 撤销保存点之后的修改，保存点本身仍然保留
+	RollbackToSavepoint(name string) error
+	// ReleaseSavepoint 删除保存点以及在它之后创建的保存点，已经做出的修改不受影响
+	ReleaseSavepoint(name string) error
+}
+
+// TxStore 支持统一事务接口的存储，新的存储插件只需要实现 UnifiedTx，
+// StartTx、StartReadTx 以及 CreateTransaction 都可以由 BeginTx 实现
+type TxStore interface {
+	// BeginTx 按照 opts 开启事务
+	BeginTx(opts TxOptions) (UnifiedTx, error)
+}
+
+// NewTxStore 返回 s 的 TxStore 实现，s 没有实现 TxStore 时使用 StartTx 以及 StartReadTx 适配，
+// 适配的事务只支持 IsolationDefault 并且不支持保存点；StartTx 返回的 Tx 实现了 TxLocker 时，
+// 行锁以及 DeleteNamespace、LockBootstrap 都在 Tx 所在的会话中执行，随 Tx 提交或者回滚，
+// 否则这些方法返回 NotSupportedErr
+func NewTxStore(s Store) TxStore {
+	if ts, ok := s.(TxStore); ok {
+		return ts
+	}
+	return &txStoreAdapter{store: s}
+}
+
+// TxLocker Tx 的可选接口，能够在 Tx 所在的会话中加锁以及修改命名空间的 Tx 实现该接口，
+// 行锁在 Tx 结束时释放，修改随 Tx 提交或者回滚
+type TxLocker interface {
+	Tx
+	// LockBootstrap 获取 server 启动锁
+	LockBootstrap(key string, server string) error
+	// LockNamespace 获取命名空间的排他锁
+	LockNamespace(name string) (*model.Namespace, error)
+	// DeleteNamespace 删除命名空间
+	DeleteNamespace(name string) error
+	// LockService 获取服务的排他锁
+	LockService(name string, namespace string) (*model.Service, error)
+	// RLockService 获取服务的共享锁
+	RLockService(name string, namespace string) (*model.Service, error)
+}
+
+// UnwrapTx 返回 NewTxStore 适配的事务所包装的 Tx，其他的 Tx 原样返回，
+// 根据 Tx 的具体类型路由请求的 Store 装饰器需要先调用 UnwrapTx
+func UnwrapTx(tx Tx) Tx {
+	if adapter, ok := tx.(*txAdapter); ok {
+		return adapter.Tx
+	}
+	return tx
+}
+
+var savepointName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ValidateSavepoint 检查保存点名称，只允许字母、数字以及下划线，并且不能以数字开头
+func ValidateSavepoint(name string) error {
+	if !savepointName.MatchString(name) {
+		return NewStatusError(EmptyParamsErr, "invalid savepoint name: "+name)
+	}
+	return nil
+}
+
+// txStoreAdapter 基于 Store 原有事务接口的 TxStore 适配器
+type txStoreAdapter struct {
+	store Store
+}
+
+// BeginTx 开启事务
+func (a *txStoreAdapter) BeginTx(opts TxOptions) (UnifiedTx, error) {
+	if opts.Isolation != IsolationDefault {
+		return nil, NewStatusError(NotSupportedErr, "unsupported isolation level: "+opts.Isolation.String())
+	}
+	var (
+		tx  Tx
+		err error
+	)
+	if opts.ReadOnly {
+		tx, err = a.store.StartReadTx()
+	} else {
+		tx, err = a.store.StartTx()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &txAdapter{Tx: tx, store: a.store}, nil
+}
+
+// txAdapter 基于 Tx 的 UnifiedTx，加锁等 Transaction 的方法交给实现了 TxLocker 的 Tx
+type txAdapter struct {
+	Tx
+	store Store
+}
+
+// LockBootstrap 获取 server 启动锁
+func (t *txAdapter) LockBootstrap(key string, server string) error {
+	locker, err := t.locker("LockBootstrap")
+	if err != nil {
+		return err
+	}
+	return locker.LockBootstrap(key, server)
+}
+
+// LockNamespace 获取命名空间的排他锁
+func (t *txAdapter) LockNamespace(name string) (*model.Namespace, error) {
+	locker, err := t.locker("LockNamespace")
+	if err != nil {
+		return nil, err
+	}
+	return locker.LockNamespace(name)
+}
+
+// DeleteNamespace 删除命名空间
+func (t *txAdapter) DeleteNamespace(name string) error {
+	locker, err := t.locker("DeleteNamespace")
+	if err != nil {
+		return err
+	}
+	return locker.DeleteNamespace(name)
+}
+
+// LockService 获取服务的排他锁
+func (t *txAdapter) LockService(name string, namespace string) (*model.Service, error) {
+	locker, err := t.locker("LockService")
+	if err != nil {
+		return nil, err
+	}
+	return locker.LockService(name, namespace)
+}
+
+// RLockService 获取服务的共享锁
+func (t *txAdapter) RLockService(name string, namespace string) (*model.Service, error) {
+	locker, err := t.locker("RLockService")
+	if err != nil {
+		return nil, err
+	}
+	return locker.RLockService(name, namespace)
+}
+
+// Savepoint 适配的事务不支持保存点
+func (t *txAdapter) Savepoint(name string) error {
+	return errSavepointUnsupported
+}
+
+// RollbackToSavepoint 适配的事务不支持保存点
+func (t *txAdapter) RollbackToSavepoint(name string) error {
+	return errSavepointUnsupported
+}
+
+// ReleaseSavepoint 适配的事务不支持保存点
+func (t *txAdapter) ReleaseSavepoint(name string) error {
+	return errSavepointUnsupported
+}
+
+var errSavepointUnsupported = NewStatusError(NotSupportedErr, "savepoint is not supported by the store")
+
+// locker 返回能够在 Tx 所在的会话中加锁的 TxLocker，Tx 没有实现 TxLocker 时返回 NotSupportedErr
+func (t *txAdapter) locker(method string) (TxLocker, error) {
+	if locker, ok := t.Tx.(TxLocker); ok {
+		return locker, nil
+	}
+	return nil, NewStatusError(NotSupportedErr,
+		fmt.Sprintf("%s is not supported by the transaction of store %s", method, t.store.Name()))
+}
+
+const (
+	defaultTxRetries = 3
+	defaultTxBackoff = 10 * time.Millisecond
+)
+
+// TxOption WithTx 的可选配置
+type TxOption func(c *txConfig)
+
+type txConfig struct {
+	opts       TxOptions
+	maxRetries int
+	backoff    time.Duration
+}
+
+// WithIsolation 设置事务的隔离级别
+func WithIsolation(level IsolationLevel) TxOption {
+	return func(c *txConfig) {
+		c.opts.Isolation = level
+	}
+}
+
+// WithReadOnlyTx 使用只读事务
+func WithReadOnlyTx() TxOption {
+	return func(c *txConfig) {
+		c.opts.ReadOnly = true
+	}
+}
+
+// WithDeadlockRetries 设置遇到 DeadlockErr 时的最大重试次数，默认重试 3 次，为 0 时不重试
+func WithDeadlockRetries(retries int) TxOption {
+	return func(c *txConfig) {
+		if retries >= 0 {
+			c.maxRetries = retries
+		}
+	}
+}
+
+// WithRetryBackoff 设置重试前的等待时间，第 n 次重试等待 n 倍的 backoff，默认为 10ms
+func WithRetryBackoff(backoff time.Duration) TxOption {
+	return func(c *txConfig) {
+		if backoff >= 0 {
+			c.backoff = backoff
+		}
+	}
+}
+
+// WithTx 在 s 开启的事务中执行 f，f 返回 nil 时提交事务，否则回滚事务并返回 f 的错误，f 不需要自行提交或者回滚；
+// 开启事务、执行 f 或者提交事务返回 DeadlockErr 时，回滚后重新开启事务再次执行 f，因此 f 需要可以重复执行
+func WithTx(s Store, f func(tx UnifiedTx) error, options ...TxOption) error {
+	c := &txConfig{maxRetries: defaultTxRetries, backoff: defaultTxBackoff}
+	for i := range options {
+		options[i](c)
+	}
+	ts := NewTxStore(s)
+	for attempt := 1; ; attempt++ {
+		err := runTx(ts, c.opts, f)
+		if Code(err) != DeadlockErr || attempt > c.maxRetries {
+			return err
+		}
+		time.Sleep(c.backoff * time.Duration(attempt))
+	}
+}
+
+// runTx 在一个事务中执行 f，f panic 时回滚事务后继续 panic
+func runTx(ts TxStore, opts TxOptions, f func(tx UnifiedTx) error) error {
+	tx, err := ts.BeginTx(opts)
+	if err != nil {
+		return err
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			_ = tx.Rollback()
+		}
+	}()
+	if err := f(tx); err != nil {
+		return err
+	}
+	committed = true
+	return tx.Commit()
+}
@@ -0,0 +1,177 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package store_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/memory"
+)
+
+// legacyStore 隐藏 TxStore 的存储，NewTxStore 对它使用适配器
+type legacyStore struct {
+	store.Store
+}
+
+// plainStore StartTx 返回的 Tx 无法加锁的存储
+type plainStore struct {
+	store.Store
+}
+
+type plainTx struct {
+	store.Tx
+}
+
+func (s plainStore) StartTx() (store.Tx, error) {
+	tx, err := s.Store.StartTx()
+	return plainTx{Tx: tx}, err
+}
+
+func TestWithTxDeadlockRetry(t *testing.T) {
+	s := memory.New()
+	deadlock := store.NewStatusError(store.DeadlockErr, "deadlock")
+	calls := 0
+	err := store.WithTx(s, func(tx store.UnifiedTx) error {
+		calls++
+		if calls < 3 {
+			return deadlock
+		}
+		return nil
+	}, store.WithRetryBackoff(0))
+	if err != nil || calls != 3 {
+		t.Fatalf("calls %d, err %v", calls, err)
+	}
+
+	calls = 0
+	err = store.WithTx(s, func(tx store.UnifiedTx) error {
+		calls++
+		return deadlock
+	}, store.WithDeadlockRetries(1), store.WithRetryBackoff(0))
+	if store.Code(err) != store.DeadlockErr || calls != 2 {
+		t.Fatalf("calls %d, err %v", calls, err)
+	}
+
+	calls = 0
+	failed := errors.New("failed")
+	err = store.WithTx(s, func(tx store.UnifiedTx) error {
+		calls++
+		return failed
+	})
+	if !errors.Is(err, failed) || calls != 1 {
+		t.Fatalf("calls %d, err %v", calls, err)
+	}
+}
+
+func TestWithTxRollback(t *testing.T) {
+	s := memory.New()
+	addNamespace(t, s, "ns")
+	err := store.WithTx(s, func(tx store.UnifiedTx) error {
+		if err := tx.DeleteNamespace("ns"); err != nil {
+			return err
+		}
+		return errors.New("abort")
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if ns, _ := s.GetNamespace("ns"); ns == nil {
+		t.Fatal("delete not rolled back")
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("panic swallowed")
+			}
+		}()
+		_ = store.WithTx(s, func(tx store.UnifiedTx) error {
+			if err := tx.DeleteNamespace("ns"); err != nil {
+				t.Fatal(err)
+			}
+			panic("boom")
+		})
+	}()
+	if ns, _ := s.GetNamespace("ns"); ns == nil {
+		t.Fatal("delete not rolled back after panic")
+	}
+}
+
+func TestTxAdapterLocksInTx(t *testing.T) {
+	m := memory.New(memory.WithLockTimeout(50 * time.Millisecond))
+	addNamespace(t, m, "ns")
+	addService(t, m, "svc-1", "svc", "ns")
+	ts := store.NewTxStore(legacyStore{Store: m})
+
+	tx, err := ts.BeginTx(store.TxOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.LockService("svc", "ns"); err != nil {
+		t.Fatal(err)
+	}
+	// 同一个事务中再次加锁不会等待自己持有的锁
+	if _, err := tx.RLockService("svc", "ns"); err != nil {
+		t.Fatal(err)
+	}
+	other, err := ts.BeginTx(store.TxOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.LockService("svc", "ns"); store.Code(err) != store.DeadlockErr {
+		t.Fatalf("lock held by another tx: %v", err)
+	}
+	if err := tx.DeleteNamespace("ns"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	if ns, _ := m.GetNamespace("ns"); ns == nil {
+		t.Fatal("delete not rolled back with the tx")
+	}
+	if _, err := other.LockService("svc", "ns"); err != nil {
+		t.Fatalf("lock not released: %v", err)
+	}
+	if err := other.Commit(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTxAdapterNotSupported(t *testing.T) {
+	ts := store.NewTxStore(plainStore{Store: memory.New()})
+	if _, err := ts.BeginTx(store.TxOptions{Isolation: store.IsolationSerializable}); store.Code(err) != store.NotSupportedErr {
+		t.Fatalf("isolation: %v", err)
+	}
+	tx, err := ts.BeginTx(store.TxOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	if _, err := tx.LockService("svc", "ns"); store.Code(err) != store.NotSupportedErr {
+		t.Fatalf("lock: %v", err)
+	}
+	if err := tx.DeleteNamespace("ns"); store.Code(err) != store.NotSupportedErr {
+		t.Fatalf("delete namespace: %v", err)
+	}
+	if err := tx.Savepoint("sp"); store.Code(err) != store.NotSupportedErr {
+		t.Fatalf("savepoint: %v", err)
+	}
+}
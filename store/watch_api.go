@@ -0,0 +1,115 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package store
+
+import (
+	"context"
+)
+
+// ResourceKind 变更事件对应的资源类型
+type ResourceKind string
+
+const (
+	// ResourceNamespace 命名空间
+	ResourceNamespace ResourceKind = "namespace"
+	// ResourceService 服务
+	ResourceService ResourceKind = "service"
+	// ResourceInstance 服务实例
+	ResourceInstance ResourceKind = "instance"
+	// ResourceRoutingConfig v1 版本路由规则
+	ResourceRoutingConfig ResourceKind = "routing_config"
+	// ResourceRouterConfig v2 版本路由规则
+	ResourceRouterConfig ResourceKind = "router_config"
+	// ResourceRateLimit 限流规则
+	ResourceRateLimit ResourceKind = "ratelimit"
+	// ResourceCircuitBreakerRule 熔断规则
+	ResourceCircuitBreakerRule ResourceKind = "circuitbreaker_rule"
+	// ResourceFaultDetectRule 主动探测规则
+	ResourceFaultDetectRule ResourceKind = "faultdetect_rule"
+	// ResourceServiceContract 服务契约
+	ResourceServiceContract ResourceKind = "service_contract"
+	// ResourceClient 客户端
+	ResourceClient ResourceKind = "client"
+	// ResourceGrayResource 灰度资源
+	ResourceGrayResource ResourceKind = "gray_resource"
+	// ResourceConfigGroup 配置分组
+	ResourceConfigGroup ResourceKind = "config_group"
+	// ResourceConfigRelease 配置发布
+	ResourceConfigRelease ResourceKind = "config_release"
+	// ResourceUser 用户
+	ResourceUser ResourceKind = "user"
+	// ResourceUserGroup 用户组
+	ResourceUserGroup ResourceKind = "user_group"
+	// ResourceStrategy 鉴权策略
+	ResourceStrategy ResourceKind = "strategy"
+)
+
+// ChangeOp 变更操作类型
+type ChangeOp string
+
+const (
+	// ChangePut 资源新增或者更新
+	ChangePut ChangeOp = "put"
+	// ChangeDelete 资源被删除
+	ChangeDelete ChangeOp = "delete"
+)
+
+// Cursor 可恢复的监听位置，由存储层生成，调用方只需要原样保存并在重新监听时传入
+// 空的 Cursor 表示从头开始监听，会先推送全量的有效数据
+type Cursor string
+
+// ChangeEvent 资源变更事件
+type ChangeEvent struct {
+	// Kind 资源类型
+	Kind ResourceKind
+	// Op 变更操作
+	Op ChangeOp
+	// Key 资源在同一类型内的唯一标识
+	Key string
+	// Revision 变更的版本号，同一个 Key 的 Revision 单调递增，不同 Key 之间不保证顺序
+	Revision int64
+	// Object 变更后的资源对象，类型与对应 GetMore* 方法返回的元素类型一致
+	Object interface{}
+	// Cursor 处理完该事件之后用于恢复监听的位置
+	Cursor Cursor
+}
+
+// Watcher 资源变更的监听者
+type Watcher interface {
+	// Events 变更事件，Watcher 停止后会被关闭
+	Events() <-chan ChangeEvent
+	// Err 返回 Watcher 停止的原因，调用 Stop 主动停止时返回 nil，存储持续查询失败时返回最后一次的错误
+	Err() error
+	// Stop 停止监听
+	Stop()
+}
+
+// WatchableStore 可选接口，支持原生推送资源变更的存储插件实现该接口
+// 事件至少投递一次，从 Cursor 恢复监听时可能会收到重复的事件，调用方需要根据 Revision 去重
+type WatchableStore interface {
+	// Watch 从 cursor 开始监听 kind 类型资源的变更，ctx 结束时 Watcher 停止
+	Watch(ctx context.Context, kind ResourceKind, cursor Cursor) (Watcher, error)
+}
+
+// Watch 监听 kind 类型资源的变更，s 未实现 WatchableStore 时退化为基于 GetMore* 的轮询
+func Watch(ctx context.Context, s Store, kind ResourceKind, cursor Cursor) (Watcher, error) {
+	if ws, ok := s.(WatchableStore); ok {
+		return ws.Watch(ctx, kind, cursor)
+	}
+	return NewPollingWatchStore(s).Watch(ctx, kind, cursor)
+}
@@ -0,0 +1,335 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+const (
+	defaultPollInterval    = time.Second
+	defaultPollLookback    = time.Second
+	defaultPollMaxFailures = 3
+)
+
+// PollingOption NewPollingWatchStore 的可选配置
+type PollingOption func(p *pollingWatchStore)
+
+// WithPollInterval 设置轮询 GetMore* 的间隔
+func WithPollInterval(interval time.Duration) PollingOption {
+	return func(p *pollingWatchStore) {
+		if interval > 0 {
+			p.interval = interval
+		}
+	}
+}
+
+// WithPollLookback 设置每次轮询向前回溯的时间，用于容忍数据库时钟误差以及延迟提交的事务
+func WithPollLookback(lookback time.Duration) PollingOption {
+	return func(p *pollingWatchStore) {
+		if lookback >= 0 {
+			p.lookback = lookback
+		}
+	}
+}
+
+// WithPollMaxFailures 设置 GetMore* 连续失败多少次后停止 Watcher，停止后 Err 返回最后一次失败的错误
+func WithPollMaxFailures(n int) PollingOption {
+	return func(p *pollingWatchStore) {
+		if n > 0 {
+			p.maxFailures = n
+		}
+	}
+}
+
+// NewPollingWatchStore 基于 GetMore* 轮询实现的 WatchableStore，用于不支持原生推送变更的存储插件
+// Revision 取自资源的 ModifyTime，依赖数据库时间戳；同一个 Key 的数据只有 ModifyTime 前进时才会再次推送，
+// 回溯窗口内延迟提交的数据会以更小的 Revision 推送，因此只保证同一个 Key 的 Revision 单调递增
+func NewPollingWatchStore(s Store, options ...PollingOption) WatchableStore {
+	p := &pollingWatchStore{
+		store:       s,
+		interval:    defaultPollInterval,
+		lookback:    defaultPollLookback,
+		maxFailures: defaultPollMaxFailures,
+	}
+	for i := range options {
+		options[i](p)
+	}
+	return p
+}
+
+type pollingWatchStore struct {
+	store       Store
+	interval    time.Duration
+	lookback    time.Duration
+	maxFailures int
+}
+
+// Watch 实现 WatchableStore
+func (p *pollingWatchStore) Watch(ctx context.Context, kind ResourceKind, cursor Cursor) (Watcher, error) {
+	poll, ok := pollFuncs[kind]
+	if !ok {
+		return nil, NewStatusError(EmptyParamsErr, fmt.Sprintf("unsupported resource kind: %s", kind))
+	}
+	since, err := parsePollCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &pollingWatcher{
+		poller:   newPoller(ctx, p.interval, p.maxFailures),
+		store:    p.store,
+		kind:     kind,
+		poll:     poll,
+		lookback: p.lookback,
+		since:    since,
+		first:    cursor == "",
+		seen:     make(map[string]int64),
+		events:   make(chan ChangeEvent),
+	}
+	go w.run(w.pollOnce, func() { close(w.events) })
+	return w, nil
+}
+
+// pollingWatcher 轮询实现的 Watcher
+type pollingWatcher struct {
+	*poller
+	store    Store
+	kind     ResourceKind
+	poll     pollFunc
+	lookback time.Duration
+	// since 已经推送的最大 ModifyTime
+	since time.Time
+	first bool
+	// seen 回溯窗口内已经推送过的数据的 Revision，用于去重
+	seen   map[string]int64
+	events chan ChangeEvent
+}
+
+// Events 实现 Watcher
+func (w *pollingWatcher) Events() <-chan ChangeEvent {
+	return w.events
+}
+
+// pollOnce 拉取一次增量数据并推送，返回拉取失败的错误
+func (w *pollingWatcher) pollOnce() error {
+	mtime := w.since
+	if !w.first {
+		mtime = mtime.Add(-w.lookback)
+	}
+	items, err := w.poll(w.store, mtime, w.first)
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].mtime.Before(items[j].mtime)
+	})
+
+	for i := range items {
+		item := items[i]
+		if w.first && !item.valid {
+			continue
+		}
+		revision := item.mtime.UnixNano()
+		if last, ok := w.seen[item.key]; ok && last >= revision {
+			continue
+		}
+		w.seen[item.key] = revision
+		if item.mtime.After(w.since) {
+			w.since = item.mtime
+		}
+
+		event := ChangeEvent{
+			Kind:     w.kind,
+			Op:       ChangePut,
+			Key:      item.key,
+			Revision: revision,
+			Object:   item.object,
+			Cursor:   formatPollCursor(w.since),
+		}
+		if !item.valid {
+			event.Op = ChangeDelete
+		}
+		select {
+		case w.events <- event:
+		case <-w.ctx.Done():
+			return nil
+		}
+	}
+	w.first = false
+
+	// 早于回溯窗口的数据不会再被拉取到，无需继续记录
+	deadline := w.since.Add(-w.lookback).UnixNano()
+	for key, revision := range w.seen {
+		if revision < deadline {
+			delete(w.seen, key)
+		}
+	}
+	return nil
+}
+
+func formatPollCursor(since time.Time) Cursor {
+	return Cursor(strconv.FormatInt(since.UnixNano(), 10))
+}
+
+func parsePollCursor(cursor Cursor) (time.Time, error) {
+	if cursor == "" {
+		return time.Time{}, nil
+	}
+	nanos, err := strconv.ParseInt(string(cursor), 10, 64)
+	if err != nil {
+		return time.Time{}, NewStatusError(EmptyParamsErr, fmt.Sprintf("invalid cursor: %s", cursor))
+	}
+	return time.Unix(0, nanos), nil
+}
+
+// pollItem 轮询拉取到的一条数据
+type pollItem struct {
+	key    string
+	valid  bool
+	mtime  time.Time
+	object interface{}
+}
+
+// pollFunc 通过 GetMore* 拉取某一类资源的增量数据
+type pollFunc func(s Store, mtime time.Time, firstUpdate bool) ([]pollItem, error)
+
+var pollFuncs = map[ResourceKind]pollFunc{
+	ResourceNamespace: func(s Store, mtime time.Time, _ bool) ([]pollItem, error) {
+		values, err := s.GetMoreNamespaces(mtime)
+		return toPollItems(values, err, func(v *model.Namespace) pollItem {
+			return pollItem{key: v.Name, valid: v.Valid, mtime: v.ModifyTime, object: v}
+		})
+	},
+	ResourceService: func(s Store, mtime time.Time, firstUpdate bool) ([]pollItem, error) {
+		values, err := s.GetMoreServices(mtime, firstUpdate, false, true)
+		return toPollItems(mapValues(values), err, func(v *model.Service) pollItem {
+			return pollItem{key: v.ID, valid: v.Valid, mtime: v.ModifyTime, object: v}
+		})
+	},
+	ResourceInstance: func(s Store, mtime time.Time, firstUpdate bool) ([]pollItem, error) {
+		values, err := s.GetMoreInstances(nil, mtime, firstUpdate, true, nil)
+		return toPollItems(mapValues(values), err, func(v *model.Instance) pollItem {
+			return pollItem{key: v.Proto.GetId().GetValue(), valid: v.Valid, mtime: v.ModifyTime, object: v}
+		})
+	},
+	ResourceRoutingConfig: func(s Store, mtime time.Time, firstUpdate bool) ([]pollItem, error) {
+		values, err := s.GetRoutingConfigsForCache(mtime, firstUpdate)
+		return toPollItems(values, err, func(v *model.RoutingConfig) pollItem {
+			return pollItem{key: v.ID, valid: v.Valid, mtime: v.ModifyTime, object: v}
+		})
+	},
+	ResourceRouterConfig: func(s Store, mtime time.Time, firstUpdate bool) ([]pollItem, error) {
+		values, err := s.GetRoutingConfigsV2ForCache(mtime, firstUpdate)
+		return toPollItems(values, err, func(v *model.RouterConfig) pollItem {
+			return pollItem{key: v.ID, valid: v.Valid, mtime: v.ModifyTime, object: v}
+		})
+	},
+	ResourceRateLimit: func(s Store, mtime time.Time, firstUpdate bool) ([]pollItem, error) {
+		values, err := s.GetRateLimitsForCache(mtime, firstUpdate)
+		return toPollItems(values, err, func(v *model.RateLimit) pollItem {
+			return pollItem{key: v.ID, valid: v.Valid, mtime: v.ModifyTime, object: v}
+		})
+	},
+	ResourceCircuitBreakerRule: func(s Store, mtime time.Time, firstUpdate bool) ([]pollItem, error) {
+		values, err := s.GetCircuitBreakerRulesForCache(mtime, firstUpdate)
+		return toPollItems(values, err, func(v *model.CircuitBreakerRule) pollItem {
+			return pollItem{key: v.ID, valid: v.Valid, mtime: v.ModifyTime, object: v}
+		})
+	},
+	ResourceFaultDetectRule: func(s Store, mtime time.Time, firstUpdate bool) ([]pollItem, error) {
+		values, err := s.GetFaultDetectRulesForCache(mtime, firstUpdate)
+		return toPollItems(values, err, func(v *model.FaultDetectRule) pollItem {
+			return pollItem{key: v.ID, valid: v.Valid, mtime: v.ModifyTime, object: v}
+		})
+	},
+	ResourceServiceContract: func(s Store, mtime time.Time, firstUpdate bool) ([]pollItem, error) {
+		values, err := s.GetMoreServiceContracts(firstUpdate, mtime)
+		return toPollItems(values, err, func(v *model.ServiceContract) pollItem {
+			return pollItem{key: v.ID, valid: v.Valid, mtime: v.ModifyTime, object: v}
+		})
+	},
+	ResourceClient: func(s Store, mtime time.Time, firstUpdate bool) ([]pollItem, error) {
+		values, err := s.GetMoreClients(mtime, firstUpdate)
+		return toPollItems(mapValues(values), err, func(v *model.Client) pollItem {
+			return pollItem{key: v.Proto.GetId().GetValue(), valid: v.Valid, mtime: v.ModifyTime, object: v}
+		})
+	},
+	ResourceGrayResource: func(s Store, mtime time.Time, firstUpdate bool) ([]pollItem, error) {
+		values, err := s.GetMoreGrayResouces(firstUpdate, mtime)
+		return toPollItems(values, err, func(v *model.GrayResource) pollItem {
+			return pollItem{key: v.Name, valid: v.Valid, mtime: v.ModifyTime, object: v}
+		})
+	},
+	ResourceConfigGroup: func(s Store, mtime time.Time, firstUpdate bool) ([]pollItem, error) {
+		values, err := s.GetMoreConfigGroup(firstUpdate, mtime)
+		return toPollItems(values, err, func(v *model.ConfigFileGroup) pollItem {
+			return pollItem{key: v.Namespace + "@" + v.Name, valid: v.Valid, mtime: v.ModifyTime, object: v}
+		})
+	},
+	ResourceConfigRelease: func(s Store, mtime time.Time, firstUpdate bool) ([]pollItem, error) {
+		values, err := s.GetMoreReleaseFile(firstUpdate, mtime)
+		return toPollItems(values, err, func(v *model.ConfigFileRelease) pollItem {
+			key := v.Namespace + "@" + v.Group + "@" + v.FileName + "@" + v.Name
+			return pollItem{key: key, valid: v.Valid, mtime: v.ModifyTime, object: v}
+		})
+	},
+	ResourceUser: func(s Store, mtime time.Time, firstUpdate bool) ([]pollItem, error) {
+		values, err := s.GetUsersForCache(mtime, firstUpdate)
+		return toPollItems(values, err, func(v *model.User) pollItem {
+			return pollItem{key: v.ID, valid: v.Valid, mtime: v.ModifyTime, object: v}
+		})
+	},
+	ResourceUserGroup: func(s Store, mtime time.Time, firstUpdate bool) ([]pollItem, error) {
+		values, err := s.GetGroupsForCache(mtime, firstUpdate)
+		return toPollItems(values, err, func(v *model.UserGroup) pollItem {
+			return pollItem{key: v.ID, valid: v.Valid, mtime: v.ModifyTime, object: v}
+		})
+	},
+	ResourceStrategy: func(s Store, mtime time.Time, firstUpdate bool) ([]pollItem, error) {
+		values, err := s.GetStrategyDetailsForCache(mtime, firstUpdate)
+		return toPollItems(values, err, func(v *model.StrategyDetail) pollItem {
+			return pollItem{key: v.ID, valid: v.Valid, mtime: v.ModifyTime, object: v}
+		})
+	},
+}
+
+func toPollItems[T any](values []T, err error, convert func(T) pollItem) ([]pollItem, error) {
+	if err != nil {
+		return nil, err
+	}
+	items := make([]pollItem, 0, len(values))
+	for i := range values {
+		items = append(items, convert(values[i]))
+	}
+	return items, nil
+}
+
+func mapValues[K comparable, V any](m map[K]V) []V {
+	values := make([]V, 0, len(m))
+	for _, v := range m {
+		values = append(values, v)
+	}
+	return values
+}
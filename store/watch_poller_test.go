@@ -0,0 +1,157 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package store_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-plugin-api/store"
+	"github.com/polarismesh/polaris-plugin-api/store/memory"
+	"github.com/polarismesh/polaris-plugin-api/store/model"
+)
+
+// failingStore GetMoreNamespaces 始终失败的存储
+type failingStore struct {
+	store.Store
+	err error
+}
+
+func (f failingStore) GetMoreNamespaces(mtime time.Time) ([]*model.Namespace, error) {
+	return nil, f.err
+}
+
+func nextEvent(t *testing.T, w store.Watcher) store.ChangeEvent {
+	t.Helper()
+	select {
+	case event, ok := <-w.Events():
+		if !ok {
+			t.Fatalf("watcher stopped: %v", w.Err())
+		}
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for event")
+	}
+	return store.ChangeEvent{}
+}
+
+func TestPollingWatcher(t *testing.T) {
+	m := memory.New()
+	addNamespace(t, m, "ns-1")
+	ws := store.NewPollingWatchStore(m, store.WithPollInterval(10*time.Millisecond), store.WithPollLookback(time.Minute))
+	w, err := ws.Watch(context.Background(), store.ResourceNamespace, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	event := nextEvent(t, w)
+	if event.Key != "ns-1" || event.Op != store.ChangePut || event.Cursor == "" {
+		t.Fatalf("unexpected event %+v", event)
+	}
+
+	// 回溯窗口内已经推送过的数据不会重复推送
+	addNamespace(t, m, "ns-2")
+	if event = nextEvent(t, w); event.Key != "ns-2" {
+		t.Fatalf("expect ns-2, got %+v", event)
+	}
+	// 修改后 Revision 前进，再次推送
+	time.Sleep(time.Millisecond)
+	if err := m.UpdateNamespace(&model.Namespace{Name: "ns-1", Comment: "v2"}); err != nil {
+		t.Fatal(err)
+	}
+	if next := nextEvent(t, w); next.Key != "ns-1" || next.Revision <= event.Revision {
+		t.Fatalf("expect update of ns-1 after %d, got %+v", event.Revision, next)
+	}
+
+	w.Stop()
+	if _, ok := <-w.Events(); ok {
+		t.Fatal("events should be closed after Stop")
+	}
+	if w.Err() != nil {
+		t.Fatalf("expect nil error after Stop, got %v", w.Err())
+	}
+}
+
+func TestPollingWatcherCursor(t *testing.T) {
+	m := memory.New()
+	addNamespace(t, m, "ns-1")
+	ws := store.NewPollingWatchStore(m, store.WithPollInterval(10*time.Millisecond), store.WithPollLookback(0))
+	w, err := ws.Watch(context.Background(), store.ResourceNamespace, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := nextEvent(t, w)
+	w.Stop()
+
+	// 从 Cursor 恢复监听时至少投递一次，Cursor 处的数据可能重复推送
+	time.Sleep(time.Millisecond)
+	addNamespace(t, m, "ns-2")
+	w, err = ws.Watch(context.Background(), store.ResourceNamespace, first.Cursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+	event := nextEvent(t, w)
+	if event.Key == first.Key && event.Revision == first.Revision {
+		event = nextEvent(t, w)
+	}
+	if event.Key != "ns-2" {
+		t.Fatalf("expect ns-2, got %+v", event)
+	}
+	if _, err := ws.Watch(context.Background(), store.ResourceNamespace, "bad"); store.Code(err) != store.EmptyParamsErr {
+		t.Fatalf("expect EmptyParamsErr for invalid cursor, got %v", err)
+	}
+}
+
+func TestPollingWatcherFailures(t *testing.T) {
+	cause := errors.New("db down")
+	ws := store.NewPollingWatchStore(failingStore{Store: memory.New(), err: cause},
+		store.WithPollInterval(time.Millisecond), store.WithPollMaxFailures(2))
+	w, err := ws.Watch(context.Background(), store.ResourceNamespace, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case _, ok := <-w.Events():
+		if ok {
+			t.Fatal("unexpected event")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("watcher should stop after consecutive failures")
+	}
+	if !errors.Is(w.Err(), cause) {
+		t.Fatalf("expect %v, got %v", cause, w.Err())
+	}
+	w.Stop()
+}
+
+func TestPollingWatcherContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ws := store.NewPollingWatchStore(memory.New(), store.WithPollInterval(time.Millisecond))
+	w, err := ws.Watch(ctx, store.ResourceNamespace, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	for range w.Events() {
+	}
+	if !errors.Is(w.Err(), context.Canceled) {
+		t.Fatalf("expect context.Canceled, got %v", w.Err())
+	}
+}